@@ -0,0 +1,412 @@
+// Copyright 2024 Canonical.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/juju/cmd/v3"
+	"github.com/juju/gnuflag"
+	jujuapi "github.com/juju/juju/api"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/cmd/output"
+	"github.com/juju/juju/jujuclient"
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/pkg/api"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+var (
+	modelCommandDoc = `
+The model command groups JAAS specific model commands.
+`
+
+	modelLabelCommandDoc = `
+The label command manages the labels of a model.
+
+Labels are key/value pairs attached to a model, such as a cost centre,
+environment or service. Labels are stored in JAAS and can be used to
+select models when listing or querying them.
+`
+
+	setModelLabelsCommandDoc = `
+set sets labels on a model. Existing labels with the same keys are
+replaced. Only model administrators may set labels.
+`
+	setModelLabelsCommandExamples = `
+    juju jaas model label set 00000000-0000-0000-0000-000000000001 env=prod team=ops
+`
+
+	removeModelLabelsCommandDoc = `
+remove removes labels from a model. Only model administrators may remove
+labels.
+`
+	removeModelLabelsCommandExamples = `
+    juju jaas model label remove 00000000-0000-0000-0000-000000000001 env team
+`
+
+	showModelLabelsCommandDoc = `
+show shows the labels of a model.
+`
+	showModelLabelsCommandExamples = `
+    juju jaas model label show 00000000-0000-0000-0000-000000000001
+`
+
+	listModelsCommandDoc = `
+list lists the models you have access to that match a label selector.
+
+A selector is a comma separated list of requirements, each of which takes
+one of the forms "key=value", "key!=value", "key" (the label is set) or
+"!key" (the label is not set). A model must satisfy every requirement to
+be listed.
+`
+	listModelsCommandExamples = `
+    juju jaas model list --selector env=prod
+    juju jaas model list --selector 'team=ops,!deprecated' --format yaml
+`
+)
+
+// NewModelCommand returns a command grouping JAAS model commands.
+func NewModelCommand() *cmd.SuperCommand {
+	modelCmd := jujucmd.NewSuperCommand(cmd.SuperCommandParams{
+		Name:    "model",
+		Doc:     modelCommandDoc,
+		Purpose: "JAAS model management.",
+	})
+	labelCmd := jujucmd.NewSuperCommand(cmd.SuperCommandParams{
+		Name:    "label",
+		Doc:     modelLabelCommandDoc,
+		Purpose: "Model label management.",
+	})
+	labelCmd.Register(newSetModelLabelsCommand())
+	labelCmd.Register(newRemoveModelLabelsCommand())
+	labelCmd.Register(newShowModelLabelsCommand())
+	modelCmd.Register(labelCmd)
+	modelCmd.Register(newListModelsCommand())
+
+	return modelCmd
+}
+
+// parseModelUUID returns the tag of the model with the given UUID.
+func parseModelUUID(uuid string) (string, error) {
+	if !names.IsValidModel(uuid) {
+		return "", errors.E("invalid model uuid")
+	}
+	return names.NewModelTag(uuid).String(), nil
+}
+
+// newSetModelLabelsCommand returns a command to set model labels.
+func newSetModelLabelsCommand() cmd.Command {
+	cmd := &setModelLabelsCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// setModelLabelsCommand sets labels on a model.
+type setModelLabelsCommand struct {
+	modelcmd.ControllerCommandBase
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	req apiparams.SetModelLabelsRequest
+}
+
+func (c *setModelLabelsCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "set",
+		Args:     "<model uuid> <key>=<value> ...",
+		Purpose:  "Set model labels",
+		Doc:      setModelLabelsCommandDoc,
+		Examples: setModelLabelsCommandExamples,
+	})
+}
+
+// Init implements the cmd.Command interface.
+func (c *setModelLabelsCommand) Init(args []string) error {
+	if len(args) < 2 {
+		return errors.E("model uuid and at least one label must be specified")
+	}
+	var err error
+	c.req.ModelTag, err = parseModelUUID(args[0])
+	if err != nil {
+		return err
+	}
+	c.req.Labels = make(map[string]string, len(args)-1)
+	for _, arg := range args[1:] {
+		k, v, ok := strings.Cut(arg, "=")
+		if !ok || k == "" {
+			return errors.E(fmt.Sprintf("invalid label %q, expected key=value", arg))
+		}
+		c.req.Labels[k] = v
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *setModelLabelsCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	if err := client.SetModelLabels(&c.req); err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
+
+// newRemoveModelLabelsCommand returns a command to remove model labels.
+func newRemoveModelLabelsCommand() cmd.Command {
+	cmd := &removeModelLabelsCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// removeModelLabelsCommand removes labels from a model.
+type removeModelLabelsCommand struct {
+	modelcmd.ControllerCommandBase
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	req apiparams.RemoveModelLabelsRequest
+}
+
+func (c *removeModelLabelsCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "remove",
+		Args:     "<model uuid> <key> ...",
+		Purpose:  "Remove model labels",
+		Doc:      removeModelLabelsCommandDoc,
+		Examples: removeModelLabelsCommandExamples,
+	})
+}
+
+// Init implements the cmd.Command interface.
+func (c *removeModelLabelsCommand) Init(args []string) error {
+	if len(args) < 2 {
+		return errors.E("model uuid and at least one label key must be specified")
+	}
+	var err error
+	c.req.ModelTag, err = parseModelUUID(args[0])
+	if err != nil {
+		return err
+	}
+	c.req.Keys = args[1:]
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *removeModelLabelsCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	if err := client.RemoveModelLabels(&c.req); err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
+
+// newShowModelLabelsCommand returns a command to show model labels.
+func newShowModelLabelsCommand() cmd.Command {
+	cmd := &showModelLabelsCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// showModelLabelsCommand shows the labels of a model.
+type showModelLabelsCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	req apiparams.GetModelLabelsRequest
+}
+
+func (c *showModelLabelsCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "show",
+		Args:     "<model uuid>",
+		Purpose:  "Show model labels",
+		Doc:      showModelLabelsCommandDoc,
+		Examples: showModelLabelsCommandExamples,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *showModelLabelsCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml": cmd.FormatYaml,
+		"json": cmd.FormatJson,
+	})
+}
+
+// Init implements the cmd.Command interface.
+func (c *showModelLabelsCommand) Init(args []string) error {
+	if len(args) < 1 {
+		return errors.E("model uuid must be specified")
+	}
+	if len(args) > 1 {
+		return errors.E("too many args")
+	}
+	var err error
+	c.req.ModelTag, err = parseModelUUID(args[0])
+	return err
+}
+
+// Run implements Command.Run.
+func (c *showModelLabelsCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	resp, err := client.GetModelLabels(&c.req)
+	if err != nil {
+		return errors.E(err)
+	}
+
+	err = c.out.Write(ctxt, resp.Labels)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
+
+// newListModelsCommand returns a command to list models by label.
+func newListModelsCommand() cmd.Command {
+	cmd := &listModelsCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// listModelsCommand lists the models matching a label selector.
+type listModelsCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	req apiparams.ListModelsByLabelRequest
+}
+
+func (c *listModelsCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "list",
+		Purpose:  "List models by label",
+		Doc:      listModelsCommandDoc,
+		Examples: listModelsCommandExamples,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *listModelsCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatModelsTabular,
+	})
+	f.StringVar(&c.req.LabelSelector, "selector", "", "The label selector models must match")
+}
+
+// Init implements the cmd.Command interface.
+func (c *listModelsCommand) Init(args []string) error {
+	if len(args) > 0 {
+		return errors.E("too many args")
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *listModelsCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	resp, err := client.ListModelsByLabel(&c.req)
+	if err != nil {
+		return errors.E(err)
+	}
+
+	err = c.out.Write(ctxt, resp.UserModels)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
+
+// formatModelsTabular writes a tabular list of models.
+func formatModelsTabular(writer io.Writer, value interface{}) error {
+	models, ok := value.([]jujuparams.UserModel)
+	if !ok {
+		return errors.E(fmt.Sprintf("expected value of type %T, got %T", models, value))
+	}
+	if len(models) == 0 {
+		fmt.Fprintln(writer, "No matching models.")
+		return nil
+	}
+	sort.Slice(models, func(i, j int) bool {
+		return models[i].UUID < models[j].UUID
+	})
+
+	tw := output.TabWriter(writer)
+	w := output.Wrapper{TabWriter: tw}
+	w.Println("Model", "Owner", "UUID")
+	for _, m := range models {
+		owner := m.OwnerTag
+		if t, err := names.ParseUserTag(m.OwnerTag); err == nil {
+			owner = t.Id()
+		}
+		w.Println(m.Name, owner, m.UUID)
+	}
+	tw.Flush()
+	return nil
+}
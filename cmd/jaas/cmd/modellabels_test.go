@@ -0,0 +1,88 @@
+// Copyright 2024 Canonical.
+
+package cmd_test
+
+import (
+	"github.com/juju/cmd/v3/cmdtesting"
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+	gc "gopkg.in/check.v1"
+
+	"github.com/canonical/jimm/v3/cmd/jaas/cmd"
+	"github.com/canonical/jimm/v3/internal/testutils/cmdtest"
+	"github.com/canonical/jimm/v3/internal/testutils/jimmtest"
+)
+
+type modelLabelsSuite struct {
+	cmdtest.JimmCmdSuite
+}
+
+var _ = gc.Suite(&modelLabelsSuite{})
+
+func (s *modelLabelsSuite) addModel(c *gc.C, owner, name string) names.ModelTag {
+	cct := names.NewCloudCredentialTag(jimmtest.TestCloudName + "/" + owner + "/cred")
+	s.UpdateCloudCredential(c, cct, jujuparams.CloudCredential{AuthType: "empty"})
+	return s.AddModel(c, names.NewUserTag(owner), name, names.NewCloudTag(jimmtest.TestCloudName), jimmtest.TestCloudRegionName, cct)
+}
+
+func (s *modelLabelsSuite) TestSetShowRemoveModelLabels(c *gc.C) {
+	mt := s.addModel(c, "bob@canonical.com", "model-1")
+	bClient := s.SetupCLIAccess(c, "bob")
+
+	_, err := cmdtesting.RunCommand(c, cmd.NewSetModelLabelsCommandForTesting(s.ClientStore(), bClient), mt.Id(), "env=prod", "team=ops")
+	c.Assert(err, gc.IsNil)
+
+	ctx, err := cmdtesting.RunCommand(c, cmd.NewShowModelLabelsCommandForTesting(s.ClientStore(), bClient), mt.Id())
+	c.Assert(err, gc.IsNil)
+	c.Check(cmdtesting.Stdout(ctx), gc.Equals, "env: prod\nteam: ops\n")
+
+	_, err = cmdtesting.RunCommand(c, cmd.NewRemoveModelLabelsCommandForTesting(s.ClientStore(), bClient), mt.Id(), "team")
+	c.Assert(err, gc.IsNil)
+
+	ctx, err = cmdtesting.RunCommand(c, cmd.NewShowModelLabelsCommandForTesting(s.ClientStore(), bClient), mt.Id(), "--format", "json")
+	c.Assert(err, gc.IsNil)
+	c.Check(cmdtesting.Stdout(ctx), gc.Equals, `{"env":"prod"}`+"\n")
+}
+
+func (s *modelLabelsSuite) TestSetModelLabelsUnauthorized(c *gc.C) {
+	mt := s.addModel(c, "charlie@canonical.com", "model-1")
+	bClient := s.SetupCLIAccess(c, "bob")
+
+	_, err := cmdtesting.RunCommand(c, cmd.NewSetModelLabelsCommandForTesting(s.ClientStore(), bClient), mt.Id(), "env=prod")
+	c.Assert(err, gc.ErrorMatches, `unauthorized.*`)
+}
+
+func (s *modelLabelsSuite) TestSetModelLabelsInvalidArgs(c *gc.C) {
+	bClient := s.SetupCLIAccess(c, "bob")
+
+	_, err := cmdtesting.RunCommand(c, cmd.NewSetModelLabelsCommandForTesting(s.ClientStore(), bClient), "00000000-0000-0000-0000-000000000001")
+	c.Assert(err, gc.ErrorMatches, `model uuid and at least one label must be specified`)
+	_, err = cmdtesting.RunCommand(c, cmd.NewSetModelLabelsCommandForTesting(s.ClientStore(), bClient), "00000000-0000-0000-0000-000000000001", "env")
+	c.Assert(err, gc.ErrorMatches, `invalid label "env", expected key=value`)
+	_, err = cmdtesting.RunCommand(c, cmd.NewSetModelLabelsCommandForTesting(s.ClientStore(), bClient), "model-1", "env=prod")
+	c.Assert(err, gc.ErrorMatches, `invalid model uuid`)
+}
+
+func (s *modelLabelsSuite) TestListModels(c *gc.C) {
+	mt1 := s.addModel(c, "bob@canonical.com", "model-1")
+	mt2 := s.addModel(c, "bob@canonical.com", "model-2")
+	bClient := s.SetupCLIAccess(c, "bob")
+
+	_, err := cmdtesting.RunCommand(c, cmd.NewSetModelLabelsCommandForTesting(s.ClientStore(), bClient), mt1.Id(), "env=prod")
+	c.Assert(err, gc.IsNil)
+	_, err = cmdtesting.RunCommand(c, cmd.NewSetModelLabelsCommandForTesting(s.ClientStore(), bClient), mt2.Id(), "env=staging")
+	c.Assert(err, gc.IsNil)
+
+	ctx, err := cmdtesting.RunCommand(c, cmd.NewListModelsCommandForTesting(s.ClientStore(), bClient), "--selector", "env=prod")
+	c.Assert(err, gc.IsNil)
+	c.Check(cmdtesting.Stdout(ctx), gc.Equals, `
+Model    Owner              UUID
+model-1  bob@canonical.com  `[1:]+mt1.Id()+"\n")
+
+	ctx, err = cmdtesting.RunCommand(c, cmd.NewListModelsCommandForTesting(s.ClientStore(), bClient), "--selector", "env=dev")
+	c.Assert(err, gc.IsNil)
+	c.Check(cmdtesting.Stdout(ctx), gc.Equals, "No matching models.\n")
+
+	_, err = cmdtesting.RunCommand(c, cmd.NewListModelsCommandForTesting(s.ClientStore(), bClient), "--selector", "=prod")
+	c.Assert(err, gc.ErrorMatches, `.*invalid label selector.*`)
+}
@@ -0,0 +1,133 @@
+// Copyright 2024 Canonical.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/juju/cmd/v3"
+	"github.com/juju/gnuflag"
+	jujuapi "github.com/juju/juju/api"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/cmd/output"
+	"github.com/juju/juju/jujuclient"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/pkg/api"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+var (
+	quotaCommandDoc = `
+quota shows the model quotas that apply to you and how many models count
+against each.
+
+Identity and group quotas count the models you own, cloud quotas count
+all the models hosted on the cloud or cloud region. A new model can only
+be created if it would not exceed any of the quotas shown.
+
+JAAS administrators may show the quotas of another identity with the
+--identity flag.
+`
+	quotaCommandExamples = `
+    juju quota
+    juju quota --identity alice@canonical.com --format yaml
+`
+)
+
+// NewQuotaCommand returns a command to show model quota usage.
+func NewQuotaCommand() cmd.Command {
+	cmd := &quotaCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// quotaCommand shows an identity's usage against its model quotas.
+type quotaCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+	identity string
+}
+
+func (c *quotaCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "quota",
+		Purpose:  "Show model quota usage",
+		Doc:      quotaCommandDoc,
+		Examples: quotaCommandExamples,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *quotaCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatQuotaTabular,
+	})
+	f.StringVar(&c.identity, "identity", "", "The identity to show quotas for, defaults to the current user")
+}
+
+// Init implements the cmd.Command interface.
+func (c *quotaCommand) Init(args []string) error {
+	if len(args) > 0 {
+		return errors.E("too many args")
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *quotaCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	resp, err := client.GetModelQuotaUsage(&apiparams.ModelQuotaUsageRequest{
+		Identity: c.identity,
+	})
+	if err != nil {
+		return errors.E(err)
+	}
+
+	err = c.out.Write(ctxt, resp)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
+
+// formatQuotaTabular writes a tabular summary of model quota usage.
+func formatQuotaTabular(writer io.Writer, value interface{}) error {
+	resp, ok := value.(*apiparams.ModelQuotaUsageResponse)
+	if !ok {
+		return errors.E(fmt.Sprintf("expected value of type %T, got %T", resp, value))
+	}
+	if len(resp.Quotas) == 0 {
+		fmt.Fprintf(writer, "No model quotas apply to %s.\n", resp.Identity)
+		return nil
+	}
+
+	tw := output.TabWriter(writer)
+	w := output.Wrapper{TabWriter: tw}
+	w.Println("Kind", "Subject", "Region", "Models", "Limit")
+	for _, q := range resp.Quotas {
+		w.Println(q.Kind, q.Subject, q.Region, q.Usage, q.MaxModels)
+	}
+	tw.Flush()
+	return nil
+}
@@ -0,0 +1,50 @@
+// Copyright 2024 Canonical.
+
+package cmd_test
+
+import (
+	"context"
+
+	"github.com/juju/cmd/v3/cmdtesting"
+	gc "gopkg.in/check.v1"
+
+	"github.com/canonical/jimm/v3/cmd/jaas/cmd"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/testutils/cmdtest"
+)
+
+type quotaSuite struct {
+	cmdtest.JimmCmdSuite
+}
+
+var _ = gc.Suite(&quotaSuite{})
+
+func (s *quotaSuite) TestQuota(c *gc.C) {
+	err := s.JIMM.Database.SetModelQuota(context.Background(), &dbmodel.ModelQuota{
+		Kind:      dbmodel.ModelQuotaIdentity,
+		Subject:   "bob@canonical.com",
+		MaxModels: 2,
+	})
+	c.Assert(err, gc.IsNil)
+
+	bClient := s.SetupCLIAccess(c, "bob")
+	ctx, err := cmdtesting.RunCommand(c, cmd.NewQuotaCommandForTesting(s.ClientStore(), bClient))
+	c.Assert(err, gc.IsNil)
+	c.Check(cmdtesting.Stdout(ctx), gc.Equals, `
+Kind      Subject            Region  Models  Limit
+identity  bob@canonical.com          0       2
+`[1:])
+}
+
+func (s *quotaSuite) TestQuotaNoQuotas(c *gc.C) {
+	bClient := s.SetupCLIAccess(c, "bob")
+	ctx, err := cmdtesting.RunCommand(c, cmd.NewQuotaCommandForTesting(s.ClientStore(), bClient))
+	c.Assert(err, gc.IsNil)
+	c.Check(cmdtesting.Stdout(ctx), gc.Equals, "No model quotas apply to bob@canonical.com.\n")
+}
+
+func (s *quotaSuite) TestQuotaOtherIdentityUnauthorized(c *gc.C) {
+	bClient := s.SetupCLIAccess(c, "bob")
+	_, err := cmdtesting.RunCommand(c, cmd.NewQuotaCommandForTesting(s.ClientStore(), bClient), "--identity", "alice@canonical.com")
+	c.Assert(err, gc.ErrorMatches, `unauthorized.*`)
+}
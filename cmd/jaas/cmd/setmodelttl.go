@@ -0,0 +1,122 @@
+// Copyright 2024 Canonical.
+
+package cmd
+
+import (
+	"time"
+
+	"github.com/juju/cmd/v3"
+	"github.com/juju/gnuflag"
+	jujuapi "github.com/juju/juju/api"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/pkg/api"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+var (
+	setModelTTLCommandDoc = `
+set-model-ttl sets the time-to-live of an ephemeral model, measured from
+now. Once the time-to-live passes the model is destroyed.
+
+Ephemeral models are created by specifying the jimm-ttl model config
+value when adding a model, this command is used to extend their life.
+The owner of the model is warned shortly before the model expires.
+`
+	setModelTTLCommandExamples = `
+    juju set-model-ttl 00000000-0000-0000-0000-000000000001 2h
+    juju set-model-ttl 00000000-0000-0000-0000-000000000001 72h --format yaml
+`
+)
+
+// NewSetModelTTLCommand returns a command to set the time-to-live of an
+// ephemeral model.
+func NewSetModelTTLCommand() cmd.Command {
+	cmd := &setModelTTLCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// setModelTTLCommand sets the time-to-live of an ephemeral model.
+type setModelTTLCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	req apiparams.SetModelTTLRequest
+}
+
+func (c *setModelTTLCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "set-model-ttl",
+		Args:     "<model uuid> <ttl>",
+		Purpose:  "Set the time-to-live of an ephemeral model",
+		Doc:      setModelTTLCommandDoc,
+		Examples: setModelTTLCommandExamples,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *setModelTTLCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "smart", map[string]cmd.Formatter{
+		"smart": cmd.FormatSmart,
+		"yaml":  cmd.FormatYaml,
+		"json":  cmd.FormatJson,
+	})
+}
+
+// Init implements the cmd.Command interface.
+func (c *setModelTTLCommand) Init(args []string) error {
+	if len(args) < 2 {
+		return errors.E("model uuid and ttl must be specified")
+	}
+	if len(args) > 2 {
+		return errors.E("too many args")
+	}
+	if !names.IsValidModel(args[0]) {
+		return errors.E("invalid model uuid")
+	}
+	ttl, err := time.ParseDuration(args[1])
+	if err != nil || ttl <= 0 {
+		return errors.E("ttl must be a positive duration")
+	}
+	c.req = apiparams.SetModelTTLRequest{
+		ModelTag: names.NewModelTag(args[0]).String(),
+		TTL:      ttl.String(),
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *setModelTTLCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	resp, err := client.SetModelTTL(&c.req)
+	if err != nil {
+		return errors.E(err)
+	}
+
+	err = c.out.Write(ctxt, resp.ExpiresAt.Format(time.RFC3339))
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
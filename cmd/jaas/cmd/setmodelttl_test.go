@@ -0,0 +1,64 @@
+// Copyright 2024 Canonical.
+
+package cmd_test
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/juju/cmd/v3/cmdtesting"
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+	gc "gopkg.in/check.v1"
+
+	"github.com/canonical/jimm/v3/cmd/jaas/cmd"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/testutils/cmdtest"
+	"github.com/canonical/jimm/v3/internal/testutils/jimmtest"
+)
+
+type setModelTTLSuite struct {
+	cmdtest.JimmCmdSuite
+}
+
+var _ = gc.Suite(&setModelTTLSuite{})
+
+func (s *setModelTTLSuite) TestSetModelTTL(c *gc.C) {
+	cct := names.NewCloudCredentialTag(jimmtest.TestCloudName + "/bob@canonical.com/cred")
+	s.UpdateCloudCredential(c, cct, jujuparams.CloudCredential{AuthType: "empty"})
+	mt := s.AddModel(c, names.NewUserTag("bob@canonical.com"), "model-1", names.NewCloudTag(jimmtest.TestCloudName), jimmtest.TestCloudRegionName, cct)
+
+	bClient := s.SetupCLIAccess(c, "bob")
+	before := time.Now().UTC()
+	ctx, err := cmdtesting.RunCommand(c, cmd.NewSetModelTTLCommandForTesting(s.ClientStore(), bClient), mt.Id(), "2h")
+	c.Assert(err, gc.IsNil)
+	expiresAt, err := time.Parse(time.RFC3339, strings.TrimSpace(cmdtesting.Stdout(ctx)))
+	c.Assert(err, gc.IsNil)
+	c.Check(expiresAt.After(before.Add(2*time.Hour-time.Second)), gc.Equals, true)
+
+	var m dbmodel.Model
+	m.SetTag(mt)
+	err = s.JIMM.Database.GetModel(context.Background(), &m)
+	c.Assert(err, gc.IsNil)
+	c.Check(m.ExpiresAt.Valid, gc.Equals, true)
+	c.Check(m.ExpiresAt.Time.Equal(expiresAt), gc.Equals, true)
+}
+
+func (s *setModelTTLSuite) TestSetModelTTLUnauthorized(c *gc.C) {
+	cct := names.NewCloudCredentialTag(jimmtest.TestCloudName + "/charlie@canonical.com/cred")
+	s.UpdateCloudCredential(c, cct, jujuparams.CloudCredential{AuthType: "empty"})
+	mt := s.AddModel(c, names.NewUserTag("charlie@canonical.com"), "model-1", names.NewCloudTag(jimmtest.TestCloudName), jimmtest.TestCloudRegionName, cct)
+
+	bClient := s.SetupCLIAccess(c, "bob")
+	_, err := cmdtesting.RunCommand(c, cmd.NewSetModelTTLCommandForTesting(s.ClientStore(), bClient), mt.Id(), "2h")
+	c.Assert(err, gc.ErrorMatches, `unauthorized.*`)
+}
+
+func (s *setModelTTLSuite) TestSetModelTTLInvalidArgs(c *gc.C) {
+	bClient := s.SetupCLIAccess(c, "bob")
+	_, err := cmdtesting.RunCommand(c, cmd.NewSetModelTTLCommandForTesting(s.ClientStore(), bClient), "not-a-uuid", "2h")
+	c.Assert(err, gc.ErrorMatches, `invalid model uuid`)
+	_, err = cmdtesting.RunCommand(c, cmd.NewSetModelTTLCommandForTesting(s.ClientStore(), bClient), "00000000-0000-0000-0000-000000000001", "-1h")
+	c.Assert(err, gc.ErrorMatches, `ttl must be a positive duration`)
+}
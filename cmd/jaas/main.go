@@ -30,6 +30,9 @@ func NewSuperCommand() *jujucmd.SuperCommand {
 	serviceAccountCmd.Register(cmd.NewListServiceAccountCredentialsCommand())
 	serviceAccountCmd.Register(cmd.NewUpdateCredentialCommand())
 	serviceAccountCmd.Register(cmd.NewGrantCommand())
+	serviceAccountCmd.Register(cmd.NewQuotaCommand())
+	serviceAccountCmd.Register(cmd.NewSetModelTTLCommand())
+	serviceAccountCmd.Register(cmd.NewModelCommand())
 	return serviceAccountCmd
 }
 
@@ -0,0 +1,968 @@
+// Copyright 2024 Canonical.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gosuri/uitable"
+	"github.com/juju/cmd/v3"
+	jujucmdv3 "github.com/juju/cmd/v3"
+	"github.com/juju/gnuflag"
+	jujuapi "github.com/juju/juju/api"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/pkg/api"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const (
+	alertsDoc = `
+The alerts command enables management of the alert rules evaluated by jimm,
+the receivers their alerts are sent to and the silencing of alerts.
+
+The kinds of alert rule are:
+
+    model-error              a model's status is error
+    controller-unavailable   jimm cannot connect to a controller
+    credential-invalid       a cloud credential is known to be invalid
+    audit-write-failure      a jimm replica cannot write to the audit log
+
+An alert is raised for each model, controller, credential or replica the
+rule's condition holds for. It fires, and its receivers are notified, once
+the condition has held for the rule's duration. Receivers are notified
+again every repeat interval while the alert is firing, and once more when
+it is resolved.
+`
+
+	addAlertReceiverDoc = `
+The add-receiver command adds a destination for alert notifications.
+
+Webhook receivers have notifications posted to a URL as JSON, signed in the
+same way as webhook events. If no secret is given one is generated; it is
+only shown when the receiver is added.
+
+Email receivers have notifications sent to one or more addresses using the
+SMTP server jimm is configured with.
+
+Alertmanager receivers have alerts posted to the alerts API of a Prometheus
+Alertmanager, or a compatible service, at the given base URL.
+`
+	addAlertReceiverExample = `
+    jimmctl alerts add-receiver ops-hook --type webhook --url https://ops.example.com/jimm
+    jimmctl alerts add-receiver ops-mail --type email --email ops@example.com --email oncall@example.com
+    jimmctl alerts add-receiver am --type alertmanager --url http://alertmanager:9093
+`
+	listAlertReceiversDoc = `
+The receivers command lists the alert receivers.
+`
+	listAlertReceiversExample = `
+    jimmctl alerts receivers
+    jimmctl alerts receivers --format tabular
+`
+	removeAlertReceiverDoc = `
+The remove-receiver command removes an alert receiver. A receiver used by
+an alert rule cannot be removed.
+`
+	removeAlertReceiverExample = `
+    jimmctl alerts remove-receiver ops-hook
+`
+	addAlertRuleDoc = `
+The add-rule command adds an alert rule of the given kind, notifying the
+given receivers about its alerts.
+`
+	addAlertRuleExample = `
+    jimmctl alerts add-rule models-in-error --kind model-error --for 15m --receiver ops-mail
+    jimmctl alerts add-rule controllers --kind controller-unavailable --for 5m --severity critical --receiver am --receiver ops-hook
+`
+	listAlertRulesDoc = `
+The rules command lists the alert rules.
+`
+	listAlertRulesExample = `
+    jimmctl alerts rules
+    jimmctl alerts rules --format tabular
+`
+	removeAlertRuleDoc = `
+The remove-rule command removes an alert rule along with the alerts it has
+raised. Receivers are not notified that the alerts are resolved.
+`
+	removeAlertRuleExample = `
+    jimmctl alerts remove-rule models-in-error
+`
+	listAlertsDoc = `
+The list command lists the alerts that are pending or firing.
+`
+	listAlertsExample = `
+    jimmctl alerts list
+    jimmctl alerts list --format tabular
+`
+	addAlertSilenceDoc = `
+The silence command stops notifications being sent, for the given duration,
+about the alerts raised by the given rule, about the given subject, or both.
+The silence, including its ID, is printed.
+`
+	addAlertSilenceExample = `
+    jimmctl alerts silence --rule models-in-error --duration 2h --comment "planned maintenance"
+    jimmctl alerts silence --subject 00000002-0000-0000-0000-000000000001 --duration 24h
+`
+	listAlertSilencesDoc = `
+The silences command lists the alert silences that have not ended.
+`
+	listAlertSilencesExample = `
+    jimmctl alerts silences
+    jimmctl alerts silences --format tabular
+`
+	removeAlertSilenceDoc = `
+The unsilence command removes an alert silence, so that notifications about
+the alerts it matched resume.
+`
+	removeAlertSilenceExample = `
+    jimmctl alerts unsilence 3
+`
+)
+
+// NewAlertsCommand returns a command for alert management.
+func NewAlertsCommand() *jujucmdv3.SuperCommand {
+	cmd := jujucmd.NewSuperCommand(jujucmdv3.SuperCommandParams{
+		Name:    "alerts",
+		Doc:     alertsDoc,
+		Purpose: "Alert management.",
+	})
+	cmd.Register(newAddAlertReceiverCommand())
+	cmd.Register(newListAlertReceiversCommand())
+	cmd.Register(newRemoveAlertReceiverCommand())
+	cmd.Register(newAddAlertRuleCommand())
+	cmd.Register(newListAlertRulesCommand())
+	cmd.Register(newRemoveAlertRuleCommand())
+	cmd.Register(newListAlertsCommand())
+	cmd.Register(newAddAlertSilenceCommand())
+	cmd.Register(newListAlertSilencesCommand())
+	cmd.Register(newRemoveAlertSilenceCommand())
+
+	return cmd
+}
+
+// newAddAlertReceiverCommand returns a command to add an alert receiver.
+func newAddAlertReceiverCommand() cmd.Command {
+	cmd := &addAlertReceiverCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// addAlertReceiverCommand adds an alert receiver.
+type addAlertReceiverCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	req    apiparams.AddAlertReceiverRequest
+	emails stringsFlag
+}
+
+// Info implements the cmd.Command interface.
+func (c *addAlertReceiverCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "add-receiver",
+		Args:     "<name>",
+		Purpose:  "Add an alert receiver.",
+		Doc:      addAlertReceiverDoc,
+		Examples: addAlertReceiverExample,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *addAlertReceiverCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml": cmd.FormatYaml,
+		"json": cmd.FormatJson,
+	})
+	f.StringVar(&c.req.Type, "type", "", "type of receiver, one of webhook, email or alertmanager")
+	f.StringVar(&c.req.URL, "url", "", "URL of a webhook or alertmanager receiver")
+	f.StringVar(&c.req.Secret, "secret", "", "secret used to sign webhook notifications (default generated)")
+	f.Var(&c.emails, "email", "address to send notifications to, may be repeated")
+}
+
+// Init implements the cmd.Command interface.
+func (c *addAlertReceiverCommand) Init(args []string) error {
+	if len(args) < 1 {
+		return errors.E("receiver name must be specified")
+	}
+	if len(args) > 1 {
+		return errors.E("too many args")
+	}
+	if c.req.Type == "" {
+		return errors.E("receiver type must be specified")
+	}
+	c.req.Name = args[0]
+	c.req.Emails = c.emails
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *addAlertReceiverCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	resp, err := client.AddAlertReceiver(&c.req)
+	if err != nil {
+		return errors.E(err)
+	}
+
+	err = c.out.Write(ctxt, resp)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
+
+// newListAlertReceiversCommand returns a command to list the alert
+// receivers.
+func newListAlertReceiversCommand() cmd.Command {
+	cmd := &listAlertReceiversCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// listAlertReceiversCommand lists the alert receivers.
+type listAlertReceiversCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+}
+
+// Info implements the cmd.Command interface.
+func (c *listAlertReceiversCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "receivers",
+		Purpose:  "List the alert receivers.",
+		Doc:      listAlertReceiversDoc,
+		Examples: listAlertReceiversExample,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *listAlertReceiversCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatAlertReceiversTabular,
+	})
+}
+
+// Init implements the cmd.Command interface.
+func (c *listAlertReceiversCommand) Init(args []string) error {
+	if len(args) > 0 {
+		return errors.E("too many args")
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *listAlertReceiversCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	resp, err := client.ListAlertReceivers()
+	if err != nil {
+		return errors.E(err)
+	}
+
+	err = c.out.Write(ctxt, resp)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
+
+// formatAlertReceiversTabular formats a list of alert receivers as a
+// table.
+func formatAlertReceiversTabular(writer io.Writer, value interface{}) error {
+	resp, ok := value.(*apiparams.ListAlertReceiversResponse)
+	if !ok {
+		return errors.E(fmt.Sprintf("expected value of type %T, got %T", resp, value))
+	}
+
+	table := uitable.New()
+	table.MaxColWidth = 80
+	table.Wrap = true
+	table.AddRow("Name", "Type", "Destination")
+	for _, r := range resp.Receivers {
+		destination := r.URL
+		if r.Type == "email" {
+			destination = strings.Join(r.Emails, ", ")
+		}
+		table.AddRow(r.Name, r.Type, orDash(destination))
+	}
+	fmt.Fprintln(writer, table)
+	return nil
+}
+
+// newRemoveAlertReceiverCommand returns a command to remove an alert
+// receiver.
+func newRemoveAlertReceiverCommand() cmd.Command {
+	cmd := &removeAlertReceiverCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// removeAlertReceiverCommand removes an alert receiver.
+type removeAlertReceiverCommand struct {
+	modelcmd.ControllerCommandBase
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	req apiparams.RemoveAlertReceiverRequest
+}
+
+// Info implements the cmd.Command interface.
+func (c *removeAlertReceiverCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "remove-receiver",
+		Args:     "<name>",
+		Purpose:  "Remove an alert receiver.",
+		Doc:      removeAlertReceiverDoc,
+		Examples: removeAlertReceiverExample,
+	})
+}
+
+// Init implements the cmd.Command interface.
+func (c *removeAlertReceiverCommand) Init(args []string) error {
+	if len(args) < 1 {
+		return errors.E("receiver name must be specified")
+	}
+	if len(args) > 1 {
+		return errors.E("too many args")
+	}
+	c.req.Name = args[0]
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *removeAlertReceiverCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	if err := client.RemoveAlertReceiver(&c.req); err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
+
+// newAddAlertRuleCommand returns a command to add an alert rule.
+func newAddAlertRuleCommand() cmd.Command {
+	cmd := &addAlertRuleCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// addAlertRuleCommand adds an alert rule.
+type addAlertRuleCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	req       apiparams.AddAlertRuleRequest
+	receivers stringsFlag
+}
+
+// Info implements the cmd.Command interface.
+func (c *addAlertRuleCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "add-rule",
+		Args:     "<name>",
+		Purpose:  "Add an alert rule.",
+		Doc:      addAlertRuleDoc,
+		Examples: addAlertRuleExample,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *addAlertRuleCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml": cmd.FormatYaml,
+		"json": cmd.FormatJson,
+	})
+	f.StringVar(&c.req.Kind, "kind", "", "kind of condition the rule is evaluated against")
+	f.StringVar(&c.req.For, "for", "", "how long the condition must hold before the alert fires")
+	f.StringVar(&c.req.RepeatInterval, "repeat-interval", "", "how often to notify receivers about an alert that is still firing (default 4h)")
+	f.StringVar(&c.req.Severity, "severity", "", "severity of the rule's alerts (default warning)")
+	f.Var(&c.receivers, "receiver", "name of a receiver to notify, may be repeated")
+}
+
+// Init implements the cmd.Command interface.
+func (c *addAlertRuleCommand) Init(args []string) error {
+	if len(args) < 1 {
+		return errors.E("rule name must be specified")
+	}
+	if len(args) > 1 {
+		return errors.E("too many args")
+	}
+	if c.req.Kind == "" {
+		return errors.E("rule kind must be specified")
+	}
+	if len(c.receivers) == 0 {
+		return errors.E("at least one receiver must be specified")
+	}
+	c.req.Name = args[0]
+	c.req.Receivers = c.receivers
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *addAlertRuleCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	resp, err := client.AddAlertRule(&c.req)
+	if err != nil {
+		return errors.E(err)
+	}
+
+	err = c.out.Write(ctxt, resp)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
+
+// newListAlertRulesCommand returns a command to list the alert rules.
+func newListAlertRulesCommand() cmd.Command {
+	cmd := &listAlertRulesCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// listAlertRulesCommand lists the alert rules.
+type listAlertRulesCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+}
+
+// Info implements the cmd.Command interface.
+func (c *listAlertRulesCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "rules",
+		Purpose:  "List the alert rules.",
+		Doc:      listAlertRulesDoc,
+		Examples: listAlertRulesExample,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *listAlertRulesCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatAlertRulesTabular,
+	})
+}
+
+// Init implements the cmd.Command interface.
+func (c *listAlertRulesCommand) Init(args []string) error {
+	if len(args) > 0 {
+		return errors.E("too many args")
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *listAlertRulesCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	resp, err := client.ListAlertRules()
+	if err != nil {
+		return errors.E(err)
+	}
+
+	err = c.out.Write(ctxt, resp)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
+
+// formatAlertRulesTabular formats a list of alert rules as a table.
+func formatAlertRulesTabular(writer io.Writer, value interface{}) error {
+	resp, ok := value.(*apiparams.ListAlertRulesResponse)
+	if !ok {
+		return errors.E(fmt.Sprintf("expected value of type %T, got %T", resp, value))
+	}
+
+	table := uitable.New()
+	table.MaxColWidth = 80
+	table.Wrap = true
+	table.AddRow("Name", "Kind", "For", "Repeat", "Severity", "Receivers")
+	for _, r := range resp.Rules {
+		table.AddRow(r.Name, r.Kind, r.For, r.RepeatInterval, r.Severity, strings.Join(r.Receivers, ", "))
+	}
+	fmt.Fprintln(writer, table)
+	return nil
+}
+
+// newRemoveAlertRuleCommand returns a command to remove an alert rule.
+func newRemoveAlertRuleCommand() cmd.Command {
+	cmd := &removeAlertRuleCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// removeAlertRuleCommand removes an alert rule.
+type removeAlertRuleCommand struct {
+	modelcmd.ControllerCommandBase
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	req apiparams.RemoveAlertRuleRequest
+}
+
+// Info implements the cmd.Command interface.
+func (c *removeAlertRuleCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "remove-rule",
+		Args:     "<name>",
+		Purpose:  "Remove an alert rule.",
+		Doc:      removeAlertRuleDoc,
+		Examples: removeAlertRuleExample,
+	})
+}
+
+// Init implements the cmd.Command interface.
+func (c *removeAlertRuleCommand) Init(args []string) error {
+	if len(args) < 1 {
+		return errors.E("rule name must be specified")
+	}
+	if len(args) > 1 {
+		return errors.E("too many args")
+	}
+	c.req.Name = args[0]
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *removeAlertRuleCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	if err := client.RemoveAlertRule(&c.req); err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
+
+// newListAlertsCommand returns a command to list the current alerts.
+func newListAlertsCommand() cmd.Command {
+	cmd := &listAlertsCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// listAlertsCommand lists the alerts that are pending or firing.
+type listAlertsCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+}
+
+// Info implements the cmd.Command interface.
+func (c *listAlertsCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "list",
+		Purpose:  "List the current alerts.",
+		Doc:      listAlertsDoc,
+		Examples: listAlertsExample,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *listAlertsCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatAlertsTabular,
+	})
+}
+
+// Init implements the cmd.Command interface.
+func (c *listAlertsCommand) Init(args []string) error {
+	if len(args) > 0 {
+		return errors.E("too many args")
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *listAlertsCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	resp, err := client.ListAlerts()
+	if err != nil {
+		return errors.E(err)
+	}
+
+	err = c.out.Write(ctxt, resp)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
+
+// formatAlertsTabular formats a list of alerts as a table.
+func formatAlertsTabular(writer io.Writer, value interface{}) error {
+	resp, ok := value.(*apiparams.ListAlertsResponse)
+	if !ok {
+		return errors.E(fmt.Sprintf("expected value of type %T, got %T", resp, value))
+	}
+
+	table := uitable.New()
+	table.MaxColWidth = 80
+	table.Wrap = true
+	table.AddRow("Rule", "Severity", "State", "Since", "Subject", "Summary")
+	alerts := resp.Alerts
+	sort.SliceStable(alerts, func(i, j int) bool {
+		return alerts[i].Rule < alerts[j].Rule
+	})
+	for _, a := range alerts {
+		state := a.State
+		if a.Silenced {
+			state += " (silenced)"
+		}
+		table.AddRow(a.Rule, a.Severity, state, formatTime(&a.ActiveAt), a.Subject, a.Summary)
+	}
+	fmt.Fprintln(writer, table)
+	return nil
+}
+
+// newAddAlertSilenceCommand returns a command to silence alerts.
+func newAddAlertSilenceCommand() cmd.Command {
+	cmd := &addAlertSilenceCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// addAlertSilenceCommand silences notifications for matching alerts.
+type addAlertSilenceCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	req apiparams.AddAlertSilenceRequest
+}
+
+// Info implements the cmd.Command interface.
+func (c *addAlertSilenceCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "silence",
+		Purpose:  "Silence alert notifications.",
+		Doc:      addAlertSilenceDoc,
+		Examples: addAlertSilenceExample,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *addAlertSilenceCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml": cmd.FormatYaml,
+		"json": cmd.FormatJson,
+	})
+	f.StringVar(&c.req.Rule, "rule", "", "only silence alerts raised by the named rule")
+	f.StringVar(&c.req.Subject, "subject", "", "only silence alerts about the given subject")
+	f.StringVar(&c.req.Duration, "duration", "", "how long the silence applies for")
+	f.StringVar(&c.req.Comment, "comment", "", "the reason for the silence")
+}
+
+// Init implements the cmd.Command interface.
+func (c *addAlertSilenceCommand) Init(args []string) error {
+	if len(args) > 0 {
+		return errors.E("too many args")
+	}
+	if c.req.Rule == "" && c.req.Subject == "" {
+		return errors.E("rule or subject must be specified")
+	}
+	if c.req.Duration == "" {
+		return errors.E("duration must be specified")
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *addAlertSilenceCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	resp, err := client.AddAlertSilence(&c.req)
+	if err != nil {
+		return errors.E(err)
+	}
+
+	err = c.out.Write(ctxt, resp)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
+
+// newListAlertSilencesCommand returns a command to list the alert
+// silences.
+func newListAlertSilencesCommand() cmd.Command {
+	cmd := &listAlertSilencesCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// listAlertSilencesCommand lists the alert silences that have not ended.
+type listAlertSilencesCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+}
+
+// Info implements the cmd.Command interface.
+func (c *listAlertSilencesCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "silences",
+		Purpose:  "List the alert silences.",
+		Doc:      listAlertSilencesDoc,
+		Examples: listAlertSilencesExample,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *listAlertSilencesCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatAlertSilencesTabular,
+	})
+}
+
+// Init implements the cmd.Command interface.
+func (c *listAlertSilencesCommand) Init(args []string) error {
+	if len(args) > 0 {
+		return errors.E("too many args")
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *listAlertSilencesCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	resp, err := client.ListAlertSilences()
+	if err != nil {
+		return errors.E(err)
+	}
+
+	err = c.out.Write(ctxt, resp)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
+
+// formatAlertSilencesTabular formats a list of alert silences as a table.
+func formatAlertSilencesTabular(writer io.Writer, value interface{}) error {
+	resp, ok := value.(*apiparams.ListAlertSilencesResponse)
+	if !ok {
+		return errors.E(fmt.Sprintf("expected value of type %T, got %T", resp, value))
+	}
+
+	table := uitable.New()
+	table.MaxColWidth = 80
+	table.Wrap = true
+	table.AddRow("ID", "Rule", "Subject", "Ends", "Created by", "Comment")
+	for _, s := range resp.Silences {
+		table.AddRow(s.ID, orDash(s.Rule), orDash(s.Subject), formatTime(&s.EndsAt), s.CreatedBy, orDash(s.Comment))
+	}
+	fmt.Fprintln(writer, table)
+	return nil
+}
+
+// newRemoveAlertSilenceCommand returns a command to remove an alert
+// silence.
+func newRemoveAlertSilenceCommand() cmd.Command {
+	cmd := &removeAlertSilenceCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// removeAlertSilenceCommand removes an alert silence.
+type removeAlertSilenceCommand struct {
+	modelcmd.ControllerCommandBase
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	req apiparams.RemoveAlertSilenceRequest
+}
+
+// Info implements the cmd.Command interface.
+func (c *removeAlertSilenceCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "unsilence",
+		Args:     "<id>",
+		Purpose:  "Remove an alert silence.",
+		Doc:      removeAlertSilenceDoc,
+		Examples: removeAlertSilenceExample,
+	})
+}
+
+// Init implements the cmd.Command interface.
+func (c *removeAlertSilenceCommand) Init(args []string) error {
+	if len(args) < 1 {
+		return errors.E("silence ID must be specified")
+	}
+	if len(args) > 1 {
+		return errors.E("too many args")
+	}
+	id, err := strconv.ParseUint(args[0], 10, 0)
+	if err != nil {
+		return errors.E(fmt.Sprintf("invalid silence ID %q", args[0]))
+	}
+	c.req.ID = uint(id)
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *removeAlertSilenceCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	if err := client.RemoveAlertSilence(&c.req); err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+// Copyright 2024 Canonical.
+
+package cmd_test
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/juju/cmd/v3/cmdtesting"
+	gc "gopkg.in/check.v1"
+
+	"github.com/canonical/jimm/v3/cmd/jimmctl/cmd"
+	"github.com/canonical/jimm/v3/internal/testutils/cmdtest"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+type alertsSuite struct {
+	cmdtest.JimmCmdSuite
+}
+
+var _ = gc.Suite(&alertsSuite{})
+
+func (s *alertsSuite) TestAlerts(c *gc.C) {
+	// bob is not superuser
+	bClient := s.SetupCLIAccess(c, "bob")
+	_, err := cmdtesting.RunCommand(c, cmd.NewListAlertRulesCommandForTesting(s.ClientStore(), bClient))
+	c.Check(err, gc.ErrorMatches, `unauthorized`)
+
+	// alice is superuser
+	bClient = s.SetupCLIAccess(c, "alice")
+	_, err = cmdtesting.RunCommand(c, cmd.NewAddAlertReceiverCommandForTesting(s.ClientStore(), bClient), "ops-mail")
+	c.Check(err, gc.ErrorMatches, `receiver type must be specified`)
+
+	_, err = cmdtesting.RunCommand(c, cmd.NewAddAlertReceiverCommandForTesting(s.ClientStore(), bClient), "ops-mail", "--type", "email")
+	c.Check(err, gc.ErrorMatches, `email receivers must have at least one address`)
+
+	cmdCtx, err := cmdtesting.RunCommand(c, cmd.NewAddAlertReceiverCommandForTesting(s.ClientStore(), bClient), "ops-hook", "--type", "webhook", "--url", "https://ops.example.com/jimm", "--secret", "secret")
+	c.Assert(err, gc.IsNil)
+	c.Check(cmdtesting.Stdout(cmdCtx), gc.Matches, `name: ops-hook\ntype: webhook\nurl: https://ops.example.com/jimm\nsecret: secret\ncreated-at: .*\n`)
+
+	_, err = cmdtesting.RunCommand(c, cmd.NewAddAlertReceiverCommandForTesting(s.ClientStore(), bClient), "ops-mail", "--type", "email", "--email", "ops@example.com", "--email", "oncall@example.com")
+	c.Assert(err, gc.IsNil)
+
+	cmdCtx, err = cmdtesting.RunCommand(c, cmd.NewListAlertReceiversCommandForTesting(s.ClientStore(), bClient), "--format", "tabular")
+	c.Assert(err, gc.IsNil)
+	c.Check(cmdtesting.Stdout(cmdCtx), gc.Equals, "Name    \tType   \tDestination                        \n"+
+		"ops-hook\twebhook\thttps://ops.example.com/jimm       \n"+
+		"ops-mail\temail  \tops@example.com, oncall@example.com\n")
+
+	_, err = cmdtesting.RunCommand(c, cmd.NewAddAlertRuleCommandForTesting(s.ClientStore(), bClient), "models", "--kind", "model-error")
+	c.Check(err, gc.ErrorMatches, `at least one receiver must be specified`)
+
+	cmdCtx, err = cmdtesting.RunCommand(c, cmd.NewAddAlertRuleCommandForTesting(s.ClientStore(), bClient), "models", "--kind", "model-error", "--for", "15m", "--receiver", "ops-mail", "--format", "json")
+	c.Assert(err, gc.IsNil)
+	c.Check(cmdtesting.Stdout(cmdCtx), gc.Matches, `\{"name":"models","kind":"model-error","for":"15m0s","repeat-interval":"4h0m0s","severity":"warning","receivers":\["ops-mail"\],"created-at":".*"\}\n`)
+
+	cmdCtx, err = cmdtesting.RunCommand(c, cmd.NewListAlertsCommandForTesting(s.ClientStore(), bClient))
+	c.Assert(err, gc.IsNil)
+	c.Check(cmdtesting.Stdout(cmdCtx), gc.Equals, "alerts: []\n")
+
+	_, err = cmdtesting.RunCommand(c, cmd.NewRemoveAlertReceiverCommandForTesting(s.ClientStore(), bClient), "ops-mail")
+	c.Check(err, gc.ErrorMatches, `alert receiver is used by rule "models"`)
+
+	_, err = cmdtesting.RunCommand(c, cmd.NewAddAlertSilenceCommandForTesting(s.ClientStore(), bClient), "--duration", "2h")
+	c.Check(err, gc.ErrorMatches, `rule or subject must be specified`)
+
+	cmdCtx, err = cmdtesting.RunCommand(c, cmd.NewAddAlertSilenceCommandForTesting(s.ClientStore(), bClient), "--rule", "models", "--duration", "2h", "--comment", "planned maintenance")
+	c.Assert(err, gc.IsNil)
+	c.Check(cmdtesting.Stdout(cmdCtx), gc.Matches, `(?s)id: 1\nrule: models\n.*created-by: alice@canonical.com\ncomment: planned maintenance\n`)
+
+	cmdCtx, err = cmdtesting.RunCommand(c, cmd.NewListAlertSilencesCommandForTesting(s.ClientStore(), bClient), "--format", "json")
+	c.Assert(err, gc.IsNil)
+	c.Check(cmdtesting.Stdout(cmdCtx), gc.Matches, `\{"silences":\[\{"id":1,"rule":"models",.*\}\]\}\n`)
+
+	_, err = cmdtesting.RunCommand(c, cmd.NewRemoveAlertSilenceCommandForTesting(s.ClientStore(), bClient), "one")
+	c.Check(err, gc.ErrorMatches, `invalid silence ID "one"`)
+	_, err = cmdtesting.RunCommand(c, cmd.NewRemoveAlertSilenceCommandForTesting(s.ClientStore(), bClient), "1")
+	c.Assert(err, gc.IsNil)
+
+	_, err = cmdtesting.RunCommand(c, cmd.NewRemoveAlertRuleCommandForTesting(s.ClientStore(), bClient), "models")
+	c.Assert(err, gc.IsNil)
+	_, err = cmdtesting.RunCommand(c, cmd.NewRemoveAlertReceiverCommandForTesting(s.ClientStore(), bClient), "ops-mail")
+	c.Assert(err, gc.IsNil)
+	_, err = cmdtesting.RunCommand(c, cmd.NewRemoveAlertReceiverCommandForTesting(s.ClientStore(), bClient), "ops-mail")
+	c.Check(err, gc.ErrorMatches, `alert receiver not found`)
+}
+
+func (s *alertsSuite) TestFormatAlertsTabular(c *gc.C) {
+	activeAt := time.Date(2024, 6, 1, 9, 0, 0, 0, time.UTC)
+	var buf bytes.Buffer
+	err := cmd.FormatAlertsTabular(&buf, &apiparams.ListAlertsResponse{
+		Alerts: []apiparams.Alert{{
+			Rule:     "models",
+			Severity: "warning",
+			State:    apiparams.AlertStateFiring,
+			ActiveAt: activeAt,
+			Subject:  "00000002-0000-0000-0000-000000000001",
+			Summary:  "model model-1 is in error",
+			Silenced: true,
+		}, {
+			Rule:     "controllers",
+			Severity: "critical",
+			State:    apiparams.AlertStatePending,
+			ActiveAt: activeAt,
+			Subject:  "controller-1",
+			Summary:  "controller controller-1 is unavailable",
+		}},
+	})
+	c.Assert(err, gc.IsNil)
+	c.Check(buf.String(), gc.Equals, "Rule       \tSeverity\tState            \tSince               \tSubject                             \tSummary                               \n"+
+		"controllers\tcritical\tpending          \t2024-06-01T09:00:00Z\tcontroller-1                        \tcontroller controller-1 is unavailable\n"+
+		"models     \twarning \tfiring (silenced)\t2024-06-01T09:00:00Z\t00000002-0000-0000-0000-000000000001\tmodel model-1 is in error             \n")
+
+	err = cmd.FormatAlertsTabular(&buf, "not a list")
+	c.Check(err, gc.ErrorMatches, `expected value of type .*`)
+}
+
+func (s *alertsSuite) TestFormatAlertRulesTabular(c *gc.C) {
+	var buf bytes.Buffer
+	err := cmd.FormatAlertRulesTabular(&buf, &apiparams.ListAlertRulesResponse{
+		Rules: []apiparams.AlertRule{{
+			Name:           "controllers",
+			Kind:           "controller-unavailable",
+			For:            "5m0s",
+			RepeatInterval: "4h0m0s",
+			Severity:       "critical",
+			Receivers:      []string{"am", "ops-hook"},
+		}},
+	})
+	c.Assert(err, gc.IsNil)
+	c.Check(buf.String(), gc.Equals, "Name       \tKind                  \tFor \tRepeat\tSeverity\tReceivers   \n"+
+		"controllers\tcontroller-unavailable\t5m0s\t4h0m0s\tcritical\tam, ops-hook\n")
+}
+
+func (s *alertsSuite) TestFormatAlertSilencesTabular(c *gc.C) {
+	startsAt := time.Date(2024, 6, 1, 9, 0, 0, 0, time.UTC)
+	var buf bytes.Buffer
+	err := cmd.FormatAlertSilencesTabular(&buf, &apiparams.ListAlertSilencesResponse{
+		Silences: []apiparams.AlertSilence{{
+			ID:        3,
+			Rule:      "models",
+			StartsAt:  startsAt,
+			EndsAt:    startsAt.Add(2 * time.Hour),
+			CreatedBy: "alice@canonical.com",
+			Comment:   "planned maintenance",
+		}},
+	})
+	c.Assert(err, gc.IsNil)
+	c.Check(buf.String(), gc.Equals, "ID\tRule  \tSubject\tEnds                \tCreated by         \tComment            \n"+
+		"3 \tmodels\t-      \t2024-06-01T11:00:00Z\talice@canonical.com\tplanned maintenance\n")
+}
@@ -20,6 +20,8 @@ func NewAuthCommand() *jujucmd.SuperCommand {
 	cmd.Register(NewGroupCommand())
 	cmd.Register(NewRelationCommand())
 	cmd.Register(NewRoleCommand())
+	cmd.Register(NewExportAuthCommand())
+	cmd.Register(NewImportAuthCommand())
 
 	return cmd
 }
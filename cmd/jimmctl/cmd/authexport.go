@@ -0,0 +1,122 @@
+// Copyright 2024 Canonical.
+
+package cmd
+
+import (
+	"github.com/juju/cmd/v3"
+	"github.com/juju/gnuflag"
+	jujuapi "github.com/juju/juju/api"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/pkg/api"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const (
+	exportAuthDoc = `
+The export command writes every relation tuple stored in JIMM's
+authorisation store to a YAML or JSON document.
+
+Objects and targets are written in JAAS tag form, for example
+"model-alice@canonical.com/mymodel" rather than "model-<model-uuid>",
+so that the document can be imported into another JIMM that holds
+entities with the same names.
+
+The resulting document can be restored with "jimmctl auth import".
+`
+
+	exportAuthExample = `
+    jimmctl auth export
+    jimmctl auth export --format json -o tuples.json
+`
+)
+
+// authExport is the document written by the export command and read by
+// the import command.
+type authExport struct {
+	// Tuples holds the exported relation tuples in JAAS tag form.
+	Tuples []apiparams.RelationshipTuple `yaml:"tuples" json:"tuples"`
+}
+
+// NewExportAuthCommand returns a command to export the authorisation tuples
+// known to JIMM.
+func NewExportAuthCommand() cmd.Command {
+	cmd := &exportAuthCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// exportAuthCommand exports all relation tuples.
+type exportAuthCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+}
+
+// Info implements the cmd.Command interface.
+func (c *exportAuthCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "export",
+		Purpose:  "Export authorisation tuples.",
+		Doc:      exportAuthDoc,
+		Examples: exportAuthExample,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *exportAuthCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml": cmd.FormatYaml,
+		"json": cmd.FormatJson,
+	})
+}
+
+// Init implements the cmd.Command interface.
+func (c *exportAuthCommand) Init(args []string) error {
+	if len(args) > 0 {
+		return errors.E("too many args")
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *exportAuthCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	resp, err := fetchRelations(client, apiparams.ListRelationshipTuplesRequest{
+		PageSize:     defaultPageSize,
+		ResolveUUIDs: true,
+	})
+	if err != nil {
+		return errors.E(err)
+	}
+	// Tuples that could not be resolved are still exported using their
+	// OpenFGA identifiers, warn the user so they can decide whether the
+	// export is usable.
+	for _, msg := range resp.Errors {
+		ctxt.Warningf("%s", msg)
+	}
+
+	err = c.out.Write(ctxt, authExport{Tuples: resp.Tuples})
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+// Copyright 2024 Canonical.
+
+package cmd_test
+
+import (
+	"context"
+
+	"github.com/juju/cmd/v3/cmdtesting"
+	gc "gopkg.in/check.v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/canonical/jimm/v3/cmd/jimmctl/cmd"
+	"github.com/canonical/jimm/v3/internal/testutils/cmdtest"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+type exportAuthSuite struct {
+	cmdtest.JimmCmdSuite
+}
+
+var _ = gc.Suite(&exportAuthSuite{})
+
+func (s *exportAuthSuite) TestExportAuth(c *gc.C) {
+	// alice is superuser
+	bClient := s.SetupCLIAccess(c, "alice")
+
+	_, err := s.JimmCmdSuite.JIMM.Database.AddGroup(context.Background(), "group1")
+	c.Assert(err, gc.IsNil)
+	_, err = cmdtesting.RunCommand(c, cmd.NewAddRelationCommandForTesting(s.ClientStore(), bClient), "user-bob@canonical.com", "member", "group-group1")
+	c.Assert(err, gc.IsNil)
+
+	for _, format := range []string{"yaml", "json"} {
+		context, err := cmdtesting.RunCommand(c, cmd.NewExportAuthCommandForTesting(s.ClientStore(), bClient), "--format", format)
+		c.Assert(err, gc.IsNil)
+
+		var doc struct {
+			Tuples []apiparams.RelationshipTuple `json:"tuples"`
+		}
+		err = yaml.Unmarshal([]byte(cmdtesting.Stdout(context)), &doc)
+		c.Assert(err, gc.IsNil)
+		c.Check(doc.Tuples, gc.DeepEquals, []apiparams.RelationshipTuple{{
+			Object:       "user-admin",
+			Relation:     "administrator",
+			TargetObject: "controller-jimm",
+		}, {
+			Object:       "user-alice@canonical.com",
+			Relation:     "administrator",
+			TargetObject: "controller-jimm",
+		}, {
+			Object:       "user-bob@canonical.com",
+			Relation:     "member",
+			TargetObject: "group-group1",
+		}})
+	}
+}
+
+func (s *exportAuthSuite) TestExportAuthUnauthorized(c *gc.C) {
+	bClient := s.SetupCLIAccess(c, "bob")
+
+	_, err := cmdtesting.RunCommand(c, cmd.NewExportAuthCommandForTesting(s.ClientStore(), bClient))
+	c.Assert(err, gc.ErrorMatches, `.*unauthorized.*`)
+}
@@ -0,0 +1,259 @@
+// Copyright 2024 Canonical.
+
+package cmd
+
+import (
+	"io"
+	"strings"
+
+	"github.com/juju/cmd/v3"
+	"github.com/juju/gnuflag"
+	jujuapi "github.com/juju/juju/api"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+	"sigs.k8s.io/yaml"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/pkg/api"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const (
+	importAuthDoc = `
+The import command writes the relation tuples held in a document
+produced by "jimmctl auth export" to JIMM's authorisation store.
+
+The document may be either YAML or JSON. Every object and target is
+resolved against the entities known to JIMM, so the controllers,
+models, offers, clouds, groups and roles referenced by the document
+must already exist.
+
+The tuples in the document are compared with the tuples currently
+stored in JIMM and only the missing tuples are written. With --prune
+the tuples that are stored in JIMM but are not present in the document
+are removed as well. Tuples whose object or target JIMM can no longer
+resolve, for example because the referenced entity has been deleted,
+are never removed or added; they are reported as skipped instead.
+Use --dry-run to see the difference without making
+any changes.
+`
+
+	importAuthExample = `
+    jimmctl auth import tuples.yaml
+    jimmctl auth import --dry-run tuples.json
+    jimmctl auth import --prune --batch-size 20 tuples.yaml
+`
+
+	// defaultImportBatchSize is the number of tuples written in a single
+	// request. OpenFGA rejects writes of more than 100 tuples.
+	defaultImportBatchSize = 50
+	maxImportBatchSize     = 100
+)
+
+// authImportResult describes the changes made, or to be made, by the
+// import command.
+type authImportResult struct {
+	DryRun    bool                          `yaml:"dry-run" json:"dry-run"`
+	Added     []apiparams.RelationshipTuple `yaml:"added,omitempty" json:"added,omitempty"`
+	Removed   []apiparams.RelationshipTuple `yaml:"removed,omitempty" json:"removed,omitempty"`
+	Skipped   []apiparams.RelationshipTuple `yaml:"skipped,omitempty" json:"skipped,omitempty"`
+	Unchanged int                           `yaml:"unchanged" json:"unchanged"`
+}
+
+// NewImportAuthCommand returns a command to import authorisation tuples
+// into JIMM.
+func NewImportAuthCommand() cmd.Command {
+	cmd := &importAuthCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+	cmd.file.StdinMarkers = stdinMarkers
+	return modelcmd.WrapBase(cmd)
+}
+
+// importAuthCommand imports relation tuples.
+type importAuthCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	file      cmd.FileVar
+	dryRun    bool
+	prune     bool
+	batchSize int
+}
+
+// Info implements the cmd.Command interface.
+func (c *importAuthCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "import",
+		Args:     "<filepath>",
+		Purpose:  "Import authorisation tuples.",
+		Doc:      importAuthDoc,
+		Examples: importAuthExample,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *importAuthCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml": cmd.FormatYaml,
+		"json": cmd.FormatJson,
+	})
+	f.BoolVar(&c.dryRun, "dry-run", false, "show the changes that would be made without making them")
+	f.BoolVar(&c.prune, "prune", false, "remove tuples that are not present in the imported document")
+	f.IntVar(&c.batchSize, "batch-size", defaultImportBatchSize, "number of tuples written in a single request")
+}
+
+// Init implements the cmd.Command interface.
+func (c *importAuthCommand) Init(args []string) error {
+	if len(args) < 1 {
+		return errors.E("filename not specified")
+	}
+	if len(args) > 1 {
+		return errors.E("too many args")
+	}
+	c.file.Path = args[0]
+	if c.batchSize < 1 || c.batchSize > maxImportBatchSize {
+		return errors.E("batch size must be between 1 and 100")
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *importAuthCommand) Run(ctxt *cmd.Context) error {
+	rc, err := c.file.Open(ctxt)
+	if err != nil {
+		return errors.E(err)
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		return errors.E(err)
+	}
+	var doc authExport
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return errors.E(err, "failed to parse tuple document")
+	}
+
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	current, err := fetchRelations(client, apiparams.ListRelationshipTuplesRequest{
+		PageSize:     defaultPageSize,
+		ResolveUUIDs: true,
+	})
+	if err != nil {
+		return errors.E(err)
+	}
+	for _, msg := range current.Errors {
+		ctxt.Warningf("%s", msg)
+	}
+
+	result := diffTuples(current.Tuples, doc.Tuples, c.prune)
+	result.DryRun = c.dryRun
+	if !c.dryRun {
+		for _, batch := range batchTuples(result.Added, c.batchSize) {
+			if err := client.AddRelation(&apiparams.AddRelationRequest{Tuples: batch}); err != nil {
+				return errors.E(err, "failed to add tuples")
+			}
+		}
+		for _, batch := range batchTuples(result.Removed, c.batchSize) {
+			if err := client.RemoveRelation(&apiparams.RemoveRelationRequest{Tuples: batch}); err != nil {
+				return errors.E(err, "failed to remove tuples")
+			}
+		}
+	}
+
+	err = c.out.Write(ctxt, result)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
+
+// diffTuples compares the current tuples with the wanted tuples and
+// returns the tuples that need to be added and, if prune is set, the tuples
+// that need to be removed. Duplicate wanted tuples are only added once.
+// Unresolved tuples are neither added nor removed, they are returned as
+// skipped.
+func diffTuples(current, wanted []apiparams.RelationshipTuple, prune bool) authImportResult {
+	var result authImportResult
+
+	currentSet := make(map[apiparams.RelationshipTuple]bool, len(current))
+	for _, t := range current {
+		currentSet[t] = true
+	}
+	skippedSet := make(map[apiparams.RelationshipTuple]bool)
+	skip := func(t apiparams.RelationshipTuple) {
+		if !skippedSet[t] {
+			skippedSet[t] = true
+			result.Skipped = append(result.Skipped, t)
+		}
+	}
+	wantedSet := make(map[apiparams.RelationshipTuple]bool, len(wanted))
+	for _, t := range wanted {
+		if wantedSet[t] {
+			continue
+		}
+		wantedSet[t] = true
+		if currentSet[t] {
+			result.Unchanged++
+			continue
+		}
+		if unresolvedTuple(t) {
+			skip(t)
+			continue
+		}
+		result.Added = append(result.Added, t)
+	}
+	if prune {
+		for _, t := range current {
+			if wantedSet[t] {
+				continue
+			}
+			if unresolvedTuple(t) {
+				skip(t)
+				continue
+			}
+			result.Removed = append(result.Removed, t)
+		}
+	}
+	return result
+}
+
+// unresolvedTuple reports whether the object or target of the given tuple
+// could not be resolved to a JAAS tag. JIMM returns such entities in their
+// OpenFGA form ("kind:id"), whereas JAAS tags are of the form "kind-id".
+func unresolvedTuple(t apiparams.RelationshipTuple) bool {
+	return isOpenFGAEntity(t.Object) || isOpenFGAEntity(t.TargetObject)
+}
+
+func isOpenFGAEntity(s string) bool {
+	kind, _, ok := strings.Cut(s, ":")
+	return ok && kind != "" && !strings.Contains(kind, "-")
+}
+
+// batchTuples splits the given tuples into batches of at most size tuples.
+func batchTuples(tuples []apiparams.RelationshipTuple, size int) [][]apiparams.RelationshipTuple {
+	var batches [][]apiparams.RelationshipTuple
+	for len(tuples) > size {
+		batches = append(batches, tuples[:size])
+		tuples = tuples[size:]
+	}
+	if len(tuples) > 0 {
+		batches = append(batches, tuples)
+	}
+	return batches
+}
@@ -0,0 +1,161 @@
+// Copyright 2024 Canonical.
+
+package cmd_test
+
+import (
+	"context"
+	"os"
+
+	"github.com/juju/cmd/v3/cmdtesting"
+	gc "gopkg.in/check.v1"
+
+	"github.com/canonical/jimm/v3/cmd/jimmctl/cmd"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	"github.com/canonical/jimm/v3/internal/testutils/cmdtest"
+)
+
+type importAuthSuite struct {
+	cmdtest.JimmCmdSuite
+}
+
+var _ = gc.Suite(&importAuthSuite{})
+
+const importAuthDocument = `
+tuples:
+- object: user-admin
+  relation: administrator
+  target_object: controller-jimm
+- object: user-alice@canonical.com
+  relation: administrator
+  target_object: controller-jimm
+- object: user-bob@canonical.com
+  relation: member
+  target_object: group-group1
+- object: group-group1#member
+  relation: member
+  target_object: group-group2
+`
+
+func (s *importAuthSuite) writeDocument(c *gc.C, doc string) string {
+	file, err := os.CreateTemp(c.MkDir(), "tuples.yaml")
+	c.Assert(err, gc.IsNil)
+	defer file.Close()
+	_, err = file.WriteString(doc)
+	c.Assert(err, gc.IsNil)
+	return file.Name()
+}
+
+func (s *importAuthSuite) TestImportAuth(c *gc.C) {
+	ctx := context.Background()
+	// alice is superuser
+	bClient := s.SetupCLIAccess(c, "alice")
+
+	_, err := s.JimmCmdSuite.JIMM.Database.AddGroup(ctx, "group1")
+	c.Assert(err, gc.IsNil)
+	_, err = s.JimmCmdSuite.JIMM.Database.AddGroup(ctx, "group2")
+	c.Assert(err, gc.IsNil)
+	filename := s.writeDocument(c, importAuthDocument)
+
+	context, err := cmdtesting.RunCommand(c, cmd.NewImportAuthCommandForTesting(s.ClientStore(), bClient), "--dry-run", filename)
+	c.Assert(err, gc.IsNil)
+	c.Check(cmdtesting.Stdout(context), gc.Equals, `dry-run: true
+added:
+- object: user-bob@canonical.com
+  relation: member
+  target_object: group-group1
+- object: group-group1#member
+  relation: member
+  target_object: group-group2
+unchanged: 2
+`)
+	tuples, _, err := s.JimmCmdSuite.JIMM.OpenFGAClient.ReadRelatedObjects(ctx, openfga.Tuple{}, 50, "")
+	c.Assert(err, gc.IsNil)
+	c.Check(tuples, gc.HasLen, 2)
+
+	_, err = cmdtesting.RunCommand(c, cmd.NewImportAuthCommandForTesting(s.ClientStore(), bClient), "--batch-size", "1", filename)
+	c.Assert(err, gc.IsNil)
+	tuples, _, err = s.JimmCmdSuite.JIMM.OpenFGAClient.ReadRelatedObjects(ctx, openfga.Tuple{}, 50, "")
+	c.Assert(err, gc.IsNil)
+	c.Check(tuples, gc.HasLen, 4)
+
+	// Importing the same document again is a no-op.
+	context, err = cmdtesting.RunCommand(c, cmd.NewImportAuthCommandForTesting(s.ClientStore(), bClient), filename)
+	c.Assert(err, gc.IsNil)
+	c.Check(cmdtesting.Stdout(context), gc.Equals, "dry-run: false\nunchanged: 4\n")
+}
+
+func (s *importAuthSuite) TestImportAuthPrune(c *gc.C) {
+	ctx := context.Background()
+	// alice is superuser
+	bClient := s.SetupCLIAccess(c, "alice")
+
+	_, err := s.JimmCmdSuite.JIMM.Database.AddGroup(ctx, "group1")
+	c.Assert(err, gc.IsNil)
+	_, err = cmdtesting.RunCommand(c, cmd.NewAddRelationCommandForTesting(s.ClientStore(), bClient), "user-bob@canonical.com", "member", "group-group1")
+	c.Assert(err, gc.IsNil)
+
+	filename := s.writeDocument(c, `{"tuples":[
+		{"object":"user-admin","relation":"administrator","target_object":"controller-jimm"},
+		{"object":"user-alice@canonical.com","relation":"administrator","target_object":"controller-jimm"}
+	]}`)
+
+	context, err := cmdtesting.RunCommand(c, cmd.NewImportAuthCommandForTesting(s.ClientStore(), bClient), "--prune", filename)
+	c.Assert(err, gc.IsNil)
+	c.Check(cmdtesting.Stdout(context), gc.Equals, `dry-run: false
+removed:
+- object: user-bob@canonical.com
+  relation: member
+  target_object: group-group1
+unchanged: 2
+`)
+	tuples, _, err := s.JimmCmdSuite.JIMM.OpenFGAClient.ReadRelatedObjects(ctx, openfga.Tuple{}, 50, "")
+	c.Assert(err, gc.IsNil)
+	c.Check(tuples, gc.HasLen, 2)
+}
+
+func (s *importAuthSuite) TestImportAuthPruneSkipsUnresolvedTuples(c *gc.C) {
+	ctx := context.Background()
+	// alice is superuser
+	bClient := s.SetupCLIAccess(c, "alice")
+
+	_, err := s.JimmCmdSuite.JIMM.Database.AddGroup(ctx, "group1")
+	c.Assert(err, gc.IsNil)
+	_, err = cmdtesting.RunCommand(c, cmd.NewAddRelationCommandForTesting(s.ClientStore(), bClient), "user-bob@canonical.com", "member", "group-group1")
+	c.Assert(err, gc.IsNil)
+	group := &dbmodel.GroupEntry{Name: "group1"}
+	err = s.JimmCmdSuite.JIMM.Database.GetGroup(ctx, group)
+	c.Assert(err, gc.IsNil)
+	err = s.JimmCmdSuite.JIMM.Database.RemoveGroup(ctx, group)
+	c.Assert(err, gc.IsNil)
+
+	filename := s.writeDocument(c, `{"tuples":[
+		{"object":"user-admin","relation":"administrator","target_object":"controller-jimm"},
+		{"object":"user-alice@canonical.com","relation":"administrator","target_object":"controller-jimm"}
+	]}`)
+
+	context, err := cmdtesting.RunCommand(c, cmd.NewImportAuthCommandForTesting(s.ClientStore(), bClient), "--prune", filename)
+	c.Assert(err, gc.IsNil)
+	c.Check(cmdtesting.Stdout(context), gc.Equals, `dry-run: false
+skipped:
+- object: user-bob@canonical.com
+  relation: member
+  target_object: group:`+group.UUID+`
+unchanged: 2
+`)
+	c.Check(cmdtesting.Stderr(context), gc.Matches, `(?s).*failed to parse target: failed to fetch group information: `+group.UUID+`.*`)
+	tuples, _, err := s.JimmCmdSuite.JIMM.OpenFGAClient.ReadRelatedObjects(ctx, openfga.Tuple{}, 50, "")
+	c.Assert(err, gc.IsNil)
+	c.Check(tuples, gc.HasLen, 3)
+}
+
+func (s *importAuthSuite) TestImportAuthInvalidArgs(c *gc.C) {
+	bClient := s.SetupCLIAccess(c, "alice")
+
+	_, err := cmdtesting.RunCommand(c, cmd.NewImportAuthCommandForTesting(s.ClientStore(), bClient))
+	c.Assert(err, gc.ErrorMatches, "filename not specified")
+	_, err = cmdtesting.RunCommand(c, cmd.NewImportAuthCommandForTesting(s.ClientStore(), bClient), "a", "b")
+	c.Assert(err, gc.ErrorMatches, "too many args")
+	_, err = cmdtesting.RunCommand(c, cmd.NewImportAuthCommandForTesting(s.ClientStore(), bClient), "--batch-size", "101", "a")
+	c.Assert(err, gc.ErrorMatches, "batch size must be between 1 and 100")
+}
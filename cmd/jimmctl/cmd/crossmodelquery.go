@@ -26,9 +26,12 @@ The query runs against the output of "juju status --format json",
 as such you can format your query against an output like this.
 
 The queries expect a JQ query string.
+
+The --labels flag restricts the query to models with matching labels.
 `
 	crossModelQueryExample = `
     jimmctl query-models '.applications | with_entries(select(.key=="nginx-ingress-integrator"))'
+    jimmctl query-models '.applications' --labels env=prod,team=ops
 `
 )
 
@@ -41,6 +44,9 @@ type crossModelQueryCommand struct {
 	query string
 	// queryType holds the type of query the user wishes to use.
 	queryType string
+	// labelSelector holds the label selector restricting the models
+	// queried.
+	labelSelector string
 
 	store    jujuclient.ClientStore
 	dialOpts *jujuapi.DialOpts
@@ -75,6 +81,7 @@ func (c *crossModelQueryCommand) SetFlags(f *gnuflag.FlagSet) {
 		"json": cmd.FormatJson,
 	})
 	c.file.StdinMarkers = stdinMarkers
+	f.StringVar(&c.labelSelector, "labels", "", "only query models with labels matching this selector")
 }
 
 // Info implements modelcmd.Command.
@@ -101,8 +108,9 @@ func (c *crossModelQueryCommand) Run(ctxt *cmd.Context) error {
 	}
 
 	req := apiparams.CrossModelQueryRequest{
-		Type:  c.queryType,
-		Query: c.query,
+		Type:          c.queryType,
+		Query:         c.query,
+		LabelSelector: c.labelSelector,
 	}
 
 	client := api.NewClient(apiCaller)
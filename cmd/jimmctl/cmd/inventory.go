@@ -0,0 +1,223 @@
+// Copyright 2024 Canonical.
+
+package cmd
+
+import (
+	"github.com/juju/cmd/v3"
+	jujucmdv3 "github.com/juju/cmd/v3"
+	"github.com/juju/gnuflag"
+	jujuapi "github.com/juju/juju/api"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/pkg/api"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const (
+	inventoryDoc = `
+The inventory command gives access to the estate inventory collected by
+jimm.
+
+JIMM periodically stores a snapshot of the applications, units, machines
+and relations of every model. The inventory can be read without
+contacting the controllers hosting the models, so it remains available
+while a controller is offline.
+`
+
+	showInventoryDoc = `
+The show command displays the most recently collected inventory of a
+model.
+`
+	showInventoryExample = `
+    jimmctl inventory show 2fd9ef4d-5d47-4b39-9aa5-71a1a3c3b2d1
+    jimmctl inventory show 2fd9ef4d-5d47-4b39-9aa5-71a1a3c3b2d1 --format json
+`
+
+	queryInventoryDoc = `
+The query command finds applications across the inventories of all
+models. Only jimm administrators may query the inventory.
+`
+	queryInventoryExample = `
+    jimmctl inventory query --charm postgresql --below-revision 300
+    jimmctl inventory query --base ubuntu@20.04 --controller controller-1
+`
+)
+
+// NewInventoryCommand returns a command for reading the estate
+// inventory.
+func NewInventoryCommand() *jujucmdv3.SuperCommand {
+	cmd := jujucmd.NewSuperCommand(jujucmdv3.SuperCommandParams{
+		Name:    "inventory",
+		Doc:     inventoryDoc,
+		Purpose: "Estate inventory.",
+	})
+	cmd.Register(newShowInventoryCommand())
+	cmd.Register(newQueryInventoryCommand())
+
+	return cmd
+}
+
+// newShowInventoryCommand returns a command to show the inventory of a
+// model.
+func newShowInventoryCommand() cmd.Command {
+	cmd := &showInventoryCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// showInventoryCommand displays the inventory of a model.
+type showInventoryCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	req apiparams.GetModelInventoryRequest
+}
+
+// Info implements the cmd.Command interface.
+func (c *showInventoryCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "show",
+		Args:     "<model uuid>",
+		Purpose:  "Show the inventory of a model.",
+		Doc:      showInventoryDoc,
+		Examples: showInventoryExample,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *showInventoryCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml": cmd.FormatYaml,
+		"json": cmd.FormatJson,
+	})
+}
+
+// Init implements the cmd.Command interface.
+func (c *showInventoryCommand) Init(args []string) error {
+	if len(args) < 1 {
+		return errors.E("model uuid must be specified")
+	}
+	if len(args) > 1 {
+		return errors.E("too many args")
+	}
+	if !names.IsValidModel(args[0]) {
+		return errors.E("invalid model uuid")
+	}
+	c.req.ModelTag = names.NewModelTag(args[0]).String()
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *showInventoryCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	resp, err := client.GetModelInventory(&c.req)
+	if err != nil {
+		return errors.E(err)
+	}
+
+	err = c.out.Write(ctxt, resp)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
+
+// newQueryInventoryCommand returns a command to query the inventory.
+func newQueryInventoryCommand() cmd.Command {
+	cmd := &queryInventoryCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// queryInventoryCommand finds applications across all model inventories.
+type queryInventoryCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	req apiparams.QueryInventoryRequest
+}
+
+// Info implements the cmd.Command interface.
+func (c *queryInventoryCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "query",
+		Purpose:  "Find applications across all model inventories.",
+		Doc:      queryInventoryDoc,
+		Examples: queryInventoryExample,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *queryInventoryCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml": cmd.FormatYaml,
+		"json": cmd.FormatJson,
+	})
+	f.StringVar(&c.req.Controller, "controller", "", "only find applications on the named controller")
+	f.StringVar(&c.req.Charm, "charm", "", "only find applications deployed from the named charm")
+	f.StringVar(&c.req.CharmChannel, "channel", "", "only find applications deployed from the given charm channel")
+	f.IntVar(&c.req.Revision, "revision", 0, "only find applications with the given charm revision")
+	f.IntVar(&c.req.BelowRevision, "below-revision", 0, "only find applications with a charm revision lower than the given revision")
+	f.StringVar(&c.req.Base, "base", "", "only find applications with the given base")
+	f.IntVar(&c.req.Limit, "limit", 0, "The maximum number of applications to return")
+	f.IntVar(&c.req.Offset, "offset", 0, "The offset to use when requesting applications")
+}
+
+// Init implements the cmd.Command interface.
+func (c *queryInventoryCommand) Init(args []string) error {
+	if len(args) > 0 {
+		return errors.E("too many args")
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *queryInventoryCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	resp, err := client.QueryInventory(&c.req)
+	if err != nil {
+		return errors.E(err)
+	}
+
+	err = c.out.Write(ctxt, resp)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+// Copyright 2024 Canonical.
+
+package cmd_test
+
+import (
+	"context"
+
+	"github.com/juju/cmd/v3/cmdtesting"
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+	gc "gopkg.in/check.v1"
+
+	"github.com/canonical/jimm/v3/cmd/jimmctl/cmd"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/testutils/cmdtest"
+	"github.com/canonical/jimm/v3/internal/testutils/jimmtest"
+)
+
+type inventorySuite struct {
+	cmdtest.JimmCmdSuite
+}
+
+var _ = gc.Suite(&inventorySuite{})
+
+func (s *inventorySuite) TestInventory(c *gc.C) {
+	ctx := context.Background()
+	s.AddController(c, "controller-1", s.APIInfo(c))
+
+	cct := names.NewCloudCredentialTag(jimmtest.TestCloudName + "/charlie@canonical.com/cred")
+	s.UpdateCloudCredential(c, cct, jujuparams.CloudCredential{AuthType: "empty", Attributes: map[string]string{"key": "value"}})
+	mt := s.AddModel(c, names.NewUserTag("charlie@canonical.com"), "model-2", names.NewCloudTag(jimmtest.TestCloudName), jimmtest.TestCloudRegionName, cct)
+
+	err := s.JIMM.CollectControllerInventory(ctx, &dbmodel.Controller{Name: "controller-1"})
+	c.Assert(err, gc.IsNil)
+
+	// alice is superuser
+	bClient := s.SetupCLIAccess(c, "alice")
+	cmdCtx, err := cmdtesting.RunCommand(c, cmd.NewShowInventoryCommandForTesting(s.ClientStore(), bClient), mt.Id())
+	c.Assert(err, gc.IsNil)
+	c.Check(cmdtesting.Stdout(cmdCtx), gc.Matches, `model-uuid: `+mt.Id()+`
+model-name: model-2
+owner: charlie@canonical.com
+controller: controller-1
+collected-at: .*
+`)
+
+	cmdCtx, err = cmdtesting.RunCommand(c, cmd.NewQueryInventoryCommandForTesting(s.ClientStore(), bClient), "--charm", "postgresql")
+	c.Assert(err, gc.IsNil)
+	c.Check(cmdtesting.Stdout(cmdCtx), gc.Equals, "applications: []\n")
+
+	_, err = cmdtesting.RunCommand(c, cmd.NewShowInventoryCommandForTesting(s.ClientStore(), bClient), "not-a-uuid")
+	c.Check(err, gc.ErrorMatches, `invalid model uuid`)
+}
+
+func (s *inventorySuite) TestQueryInventory(c *gc.C) {
+	// bob is not superuser
+	bClient := s.SetupCLIAccess(c, "bob")
+	_, err := cmdtesting.RunCommand(c, cmd.NewQueryInventoryCommandForTesting(s.ClientStore(), bClient))
+	c.Assert(err, gc.ErrorMatches, `unauthorized.*`)
+}
@@ -0,0 +1,341 @@
+// Copyright 2024 Canonical.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/gosuri/uitable"
+	"github.com/juju/cmd/v3"
+	jujucmdv3 "github.com/juju/cmd/v3"
+	"github.com/juju/gnuflag"
+	jujuapi "github.com/juju/juju/api"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/pkg/api"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const (
+	jobsDoc = `
+The jobs command enables management of the periodic background jobs run by
+jimm, such as the JWKS rotation and the cleanup of dying models.
+
+Jobs are run by the leader replica on their schedules. Every run is recorded
+with its status, duration and any error.
+`
+
+	listJobsDoc = `
+The list command lists the scheduled jobs along with the next time each job
+is due to run and the outcome of its most recent run.
+`
+	listJobsExample = `
+    jimmctl jobs list
+    jimmctl jobs list --format tabular
+`
+	jobHistoryDoc = `
+The history command lists the most recent runs of a scheduled job, most
+recent first.
+`
+	jobHistoryExample = `
+    jimmctl jobs history openfga-cleanup
+    jimmctl jobs history dying-model-cleanup --limit 5 --format tabular
+`
+	runJobDoc = `
+The run command requests that a scheduled job is run immediately. The job is
+started by the leader replica within a few seconds, unless it is already
+running in which case it is run again once the current run has finished.
+`
+	runJobExample = `
+    jimmctl jobs run openfga-cleanup
+`
+)
+
+// NewJobsCommand returns a command for scheduled job management.
+func NewJobsCommand() *jujucmdv3.SuperCommand {
+	cmd := jujucmd.NewSuperCommand(jujucmdv3.SuperCommandParams{
+		Name:    "jobs",
+		Doc:     jobsDoc,
+		Purpose: "Scheduled job management.",
+	})
+	cmd.Register(newListJobsCommand())
+	cmd.Register(newJobHistoryCommand())
+	cmd.Register(newRunJobCommand())
+
+	return cmd
+}
+
+// newListJobsCommand returns a command to list scheduled jobs.
+func newListJobsCommand() cmd.Command {
+	cmd := &listJobsCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// listJobsCommand lists the scheduled jobs.
+type listJobsCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+}
+
+// Info implements the cmd.Command interface.
+func (c *listJobsCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "list",
+		Purpose:  "List the scheduled jobs.",
+		Doc:      listJobsDoc,
+		Examples: listJobsExample,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *listJobsCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatJobsTabular,
+	})
+}
+
+// Init implements the cmd.Command interface.
+func (c *listJobsCommand) Init(args []string) error {
+	if len(args) > 0 {
+		return errors.E("too many args")
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *listJobsCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	resp, err := client.ListScheduledJobs()
+	if err != nil {
+		return errors.E(err)
+	}
+
+	err = c.out.Write(ctxt, resp)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
+
+// formatJobsTabular formats a list of scheduled jobs as a table.
+func formatJobsTabular(writer io.Writer, value interface{}) error {
+	resp, ok := value.(*apiparams.ListScheduledJobsResponse)
+	if !ok {
+		return errors.E(fmt.Sprintf("expected value of type %T, got %T", resp, value))
+	}
+
+	table := uitable.New()
+	table.MaxColWidth = 80
+	table.Wrap = true
+	table.AddRow("Name", "Schedule", "Next run", "Last run", "Status", "Duration")
+	for _, j := range resp.Jobs {
+		lastRun, status, duration := "-", "-", "-"
+		if j.LastRun != nil {
+			lastRun = formatTime(&j.LastRun.StartedAt)
+			status = j.LastRun.Status
+			duration = orDash(j.LastRun.Duration)
+		}
+		table.AddRow(j.Name, j.Schedule, formatTime(j.NextRunAt), lastRun, status, duration)
+	}
+	fmt.Fprintln(writer, table)
+	return nil
+}
+
+// formatTime formats t for display in a table, or returns "-" if t is
+// nil.
+func formatTime(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return "-"
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// newJobHistoryCommand returns a command to list the runs of a scheduled
+// job.
+func newJobHistoryCommand() cmd.Command {
+	cmd := &jobHistoryCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// jobHistoryCommand lists the most recent runs of a scheduled job.
+type jobHistoryCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	req apiparams.ListJobRunsRequest
+}
+
+// Info implements the cmd.Command interface.
+func (c *jobHistoryCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "history",
+		Args:     "<job>",
+		Purpose:  "List the most recent runs of a scheduled job.",
+		Doc:      jobHistoryDoc,
+		Examples: jobHistoryExample,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *jobHistoryCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatJobRunsTabular,
+	})
+	f.IntVar(&c.req.Limit, "limit", 0, "the maximum number of runs to list (default 20)")
+}
+
+// Init implements the cmd.Command interface.
+func (c *jobHistoryCommand) Init(args []string) error {
+	if len(args) < 1 {
+		return errors.E("job name must be specified")
+	}
+	if len(args) > 1 {
+		return errors.E("too many args")
+	}
+	if c.req.Limit < 0 {
+		return errors.E("limit must not be negative")
+	}
+	c.req.Job = args[0]
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *jobHistoryCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	resp, err := client.ListJobRuns(&c.req)
+	if err != nil {
+		return errors.E(err)
+	}
+
+	err = c.out.Write(ctxt, resp)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
+
+// formatJobRunsTabular formats a list of job runs as a table.
+func formatJobRunsTabular(writer io.Writer, value interface{}) error {
+	resp, ok := value.(*apiparams.ListJobRunsResponse)
+	if !ok {
+		return errors.E(fmt.Sprintf("expected value of type %T, got %T", resp, value))
+	}
+
+	table := uitable.New()
+	table.MaxColWidth = 80
+	table.Wrap = true
+	table.AddRow("ID", "Started", "Duration", "Status", "Trigger", "Replica", "Error")
+	for _, r := range resp.Runs {
+		trigger := r.Trigger
+		if r.TriggeredBy != "" {
+			trigger += " (" + r.TriggeredBy + ")"
+		}
+		table.AddRow(r.ID, formatTime(&r.StartedAt), orDash(r.Duration), r.Status, trigger, orDash(r.Replica), orDash(r.Error))
+	}
+	fmt.Fprintln(writer, table)
+	return nil
+}
+
+// newRunJobCommand returns a command to trigger a scheduled job.
+func newRunJobCommand() cmd.Command {
+	cmd := &runJobCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// runJobCommand requests that a scheduled job is run immediately.
+type runJobCommand struct {
+	modelcmd.ControllerCommandBase
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	req apiparams.TriggerScheduledJobRequest
+}
+
+// Info implements the cmd.Command interface.
+func (c *runJobCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "run",
+		Args:     "<job>",
+		Purpose:  "Run a scheduled job immediately.",
+		Doc:      runJobDoc,
+		Examples: runJobExample,
+	})
+}
+
+// Init implements the cmd.Command interface.
+func (c *runJobCommand) Init(args []string) error {
+	if len(args) < 1 {
+		return errors.E("job name must be specified")
+	}
+	if len(args) > 1 {
+		return errors.E("too many args")
+	}
+	c.req.Job = args[0]
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *runJobCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	if err := client.TriggerScheduledJob(&c.req); err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+// Copyright 2024 Canonical.
+
+package cmd_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/juju/cmd/v3/cmdtesting"
+	gc "gopkg.in/check.v1"
+
+	"github.com/canonical/jimm/v3/cmd/jimmctl/cmd"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/testutils/cmdtest"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+type jobsSuite struct {
+	cmdtest.JimmCmdSuite
+}
+
+var _ = gc.Suite(&jobsSuite{})
+
+func (s *jobsSuite) TestJobs(c *gc.C) {
+	ctx := context.Background()
+	nextRun := time.Date(2024, 6, 1, 9, 0, 0, 0, time.UTC)
+	err := s.JIMM.Database.RegisterScheduledJob(ctx, &dbmodel.ScheduledJob{
+		Name:      "openfga-cleanup",
+		Schedule:  "@every 6h",
+		NextRunAt: sql.NullTime{Time: nextRun, Valid: true},
+	})
+	c.Assert(err, gc.IsNil)
+
+	// bob is not superuser
+	bClient := s.SetupCLIAccess(c, "bob")
+	_, err = cmdtesting.RunCommand(c, cmd.NewListJobsCommandForTesting(s.ClientStore(), bClient))
+	c.Check(err, gc.ErrorMatches, `unauthorized`)
+
+	// alice is superuser
+	bClient = s.SetupCLIAccess(c, "alice")
+	cmdCtx, err := cmdtesting.RunCommand(c, cmd.NewListJobsCommandForTesting(s.ClientStore(), bClient))
+	c.Assert(err, gc.IsNil)
+	c.Check(cmdtesting.Stdout(cmdCtx), gc.Equals, "jobs:\n- name: openfga-cleanup\n  schedule: '@every 6h'\n  next-run-at: 2024-06-01T09:00:00Z\n")
+
+	_, err = cmdtesting.RunCommand(c, cmd.NewJobHistoryCommandForTesting(s.ClientStore(), bClient))
+	c.Check(err, gc.ErrorMatches, `job name must be specified`)
+
+	_, err = cmdtesting.RunCommand(c, cmd.NewJobHistoryCommandForTesting(s.ClientStore(), bClient), "no-such-job")
+	c.Check(err, gc.ErrorMatches, `scheduled job not found`)
+
+	cmdCtx, err = cmdtesting.RunCommand(c, cmd.NewJobHistoryCommandForTesting(s.ClientStore(), bClient), "openfga-cleanup", "--limit", "5")
+	c.Assert(err, gc.IsNil)
+	c.Check(cmdtesting.Stdout(cmdCtx), gc.Equals, "runs: []\n")
+
+	_, err = cmdtesting.RunCommand(c, cmd.NewRunJobCommandForTesting(s.ClientStore(), bClient), "no-such-job")
+	c.Check(err, gc.ErrorMatches, `scheduled job not found`)
+
+	_, err = cmdtesting.RunCommand(c, cmd.NewRunJobCommandForTesting(s.ClientStore(), bClient), "openfga-cleanup")
+	c.Assert(err, gc.IsNil)
+
+	cmdCtx, err = cmdtesting.RunCommand(c, cmd.NewListJobsCommandForTesting(s.ClientStore(), bClient), "--format", "json")
+	c.Assert(err, gc.IsNil)
+	c.Check(cmdtesting.Stdout(cmdCtx), gc.Matches, `\{"jobs":\[\{"name":"openfga-cleanup","schedule":"@every 6h","next-run-at":"2024-06-01T09:00:00Z","triggered-at":".*"\}\]\}\n`)
+}
+
+func (s *jobsSuite) TestFormatJobsTabular(c *gc.C) {
+	nextRun := time.Date(2024, 6, 1, 9, 0, 0, 0, time.UTC)
+	started := time.Date(2024, 6, 1, 3, 0, 0, 0, time.UTC)
+	var buf bytes.Buffer
+	err := cmd.FormatJobsTabular(&buf, &apiparams.ListScheduledJobsResponse{
+		Jobs: []apiparams.ScheduledJob{{
+			Name:      "dying-model-cleanup",
+			Schedule:  "@every 1m",
+			NextRunAt: &nextRun,
+		}, {
+			Name:      "openfga-cleanup",
+			Schedule:  "@every 6h",
+			NextRunAt: &nextRun,
+			LastRun: &apiparams.JobRun{
+				ID:        1,
+				Job:       "openfga-cleanup",
+				Status:    "succeeded",
+				StartedAt: started,
+				Duration:  "1.5s",
+			},
+		}},
+	})
+	c.Assert(err, gc.IsNil)
+	c.Check(buf.String(), gc.Equals, "Name               \tSchedule \tNext run            \tLast run            \tStatus   \tDuration\n"+
+		"dying-model-cleanup\t@every 1m\t2024-06-01T09:00:00Z\t-                   \t-        \t-       \n"+
+		"openfga-cleanup    \t@every 6h\t2024-06-01T09:00:00Z\t2024-06-01T03:00:00Z\tsucceeded\t1.5s    \n")
+
+	err = cmd.FormatJobsTabular(&buf, "not a list")
+	c.Check(err, gc.ErrorMatches, `expected value of type .*`)
+}
+
+func (s *jobsSuite) TestFormatJobRunsTabular(c *gc.C) {
+	started := time.Date(2024, 6, 1, 3, 0, 0, 0, time.UTC)
+	var buf bytes.Buffer
+	err := cmd.FormatJobRunsTabular(&buf, &apiparams.ListJobRunsResponse{
+		Runs: []apiparams.JobRun{{
+			ID:          2,
+			Job:         "openfga-cleanup",
+			Trigger:     "manual",
+			TriggeredBy: "alice@canonical.com",
+			Replica:     "jimm-0",
+			Status:      "failed",
+			StartedAt:   started,
+			Duration:    "2s",
+			Error:       "openfga unavailable",
+		}, {
+			ID:        1,
+			Job:       "openfga-cleanup",
+			Trigger:   "schedule",
+			Replica:   "jimm-0",
+			Status:    "running",
+			StartedAt: started,
+		}},
+	})
+	c.Assert(err, gc.IsNil)
+	c.Check(buf.String(), gc.Equals, "ID\tStarted             \tDuration\tStatus \tTrigger                     \tReplica\tError              \n"+
+		"2 \t2024-06-01T03:00:00Z\t2s      \tfailed \tmanual (alice@canonical.com)\tjimm-0 \topenfga unavailable\n"+
+		"1 \t2024-06-01T03:00:00Z\t-       \trunning\tschedule                    \tjimm-0 \t-                  \n")
+}
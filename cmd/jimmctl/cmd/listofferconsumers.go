@@ -0,0 +1,117 @@
+// Copyright 2024 Canonical.
+
+package cmd
+
+import (
+	"github.com/juju/cmd/v3"
+	"github.com/juju/gnuflag"
+	jujuapi "github.com/juju/juju/api"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/pkg/api"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const (
+	listOfferConsumersDoc = `
+The list-offer-consumers command lists the identities and models that
+consume an application offer, along with their relations to the offer.
+
+Consumers are those that JIMM has recorded consuming the offer and those
+with relations to the offer reported by the offering controller.
+
+With --revoked-user only the consumers with relations that would break
+if the given user's access to the offer was revoked are listed. Use
+"everyone@external" to see the effect of revoking public access.
+`
+	listOfferConsumersExample = `
+    jimmctl list-offer-consumers alice@canonical.com/mymodel.db
+    jimmctl list-offer-consumers --revoked-user bob@canonical.com alice@canonical.com/mymodel.db
+`
+)
+
+// NewListOfferConsumersCommand returns a command to list the consumers of
+// an application offer.
+func NewListOfferConsumersCommand() cmd.Command {
+	cmd := &listOfferConsumersCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// listOfferConsumersCommand lists the consumers of an application offer.
+type listOfferConsumersCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	offerURL    string
+	revokedUser string
+}
+
+// Info implements Command.Info.
+func (c *listOfferConsumersCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "list-offer-consumers",
+		Args:     "<offer url>",
+		Purpose:  "Lists the consumers of an application offer.",
+		Doc:      listOfferConsumersDoc,
+		Examples: listOfferConsumersExample,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *listOfferConsumersCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml": cmd.FormatYaml,
+		"json": cmd.FormatJson,
+	})
+	f.StringVar(&c.revokedUser, "revoked-user", "", "only list consumers whose relations would break if this user's access was revoked")
+}
+
+// Init implements the cmd.Command interface.
+func (c *listOfferConsumersCommand) Init(args []string) error {
+	if len(args) < 1 {
+		return errors.E("offer url not specified")
+	}
+	c.offerURL, args = args[0], args[1:]
+	if len(args) > 0 {
+		return errors.E("too many args")
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *listOfferConsumersCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	resp, err := client.ListOfferConsumers(&apiparams.ListOfferConsumersRequest{
+		OfferURL:    c.offerURL,
+		RevokedUser: c.revokedUser,
+	})
+	if err != nil {
+		return errors.E(err)
+	}
+
+	err = c.out.Write(ctxt, resp)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
@@ -0,0 +1,321 @@
+// Copyright 2024 Canonical.
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/juju/cmd/v3"
+	jujucmdv3 "github.com/juju/cmd/v3"
+	"github.com/juju/gnuflag"
+	jujuapi "github.com/juju/juju/api"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/pkg/api"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const (
+	modelPolicyDoc = `
+The model-policy command enables management of model creation policies
+in jimm.
+
+Policies are checked whenever a model is added. A policy applies to a
+model if the model's owner is a member of any of the policy's groups and
+the model is hosted on any of the policy's clouds; a policy without
+groups, or clouds, applies to every model. A model may only be added if
+it satisfies the rules of every policy that applies to it.
+`
+
+	setModelPolicyDoc = `
+The set command creates a model policy, or replaces an existing policy
+with the same name.
+
+Clouds take the form <cloud> or <cloud>/<region>. Required config takes
+the form <key>=<value>, or <key> to require the key be set to any value.
+Forbidden config takes the form <key>=<value>, or <key> to forbid the key
+being set at all. The name pattern is a regular expression the whole
+model name must match. Allowed credentials are shell patterns matched
+against the <cloud>/<owner>/<name> path of the model's credential.
+`
+	setModelPolicyExample = `
+    jimmctl model-policy set prod-config --clouds aws/us-east-1 --required-config logging-config="<root>=WARNING",http-proxy
+    jimmctl model-policy set students --groups students --allowed-clouds aws/eu-west-1 --name-pattern 'student-.*'
+    jimmctl model-policy set credentials --allowed-credentials 'aws/*/team-*'
+`
+	removeModelPolicyDoc = `
+The remove command removes a model policy.
+`
+	removeModelPolicyExample = `
+    jimmctl model-policy remove students
+`
+	listModelPoliciesDoc = `
+The list command lists all model policies in jimm.
+`
+	listModelPoliciesExample = `
+    jimmctl model-policy list
+`
+)
+
+// NewModelPolicyCommand returns a command for model policy management.
+func NewModelPolicyCommand() *jujucmdv3.SuperCommand {
+	cmd := jujucmd.NewSuperCommand(jujucmdv3.SuperCommandParams{
+		Name:    "model-policy",
+		Doc:     modelPolicyDoc,
+		Purpose: "Model policy management.",
+	})
+	cmd.Register(newSetModelPolicyCommand())
+	cmd.Register(newRemoveModelPolicyCommand())
+	cmd.Register(newListModelPoliciesCommand())
+
+	return cmd
+}
+
+// newSetModelPolicyCommand returns a command to set a model policy.
+func newSetModelPolicyCommand() cmd.Command {
+	cmd := &setModelPolicyCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// setModelPolicyCommand creates, or replaces, a model policy.
+type setModelPolicyCommand struct {
+	modelcmd.ControllerCommandBase
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	policy             apiparams.ModelPolicy
+	groups             string
+	clouds             string
+	allowedClouds      string
+	requiredConfig     string
+	forbiddenConfig    string
+	allowedCredentials string
+}
+
+// Info implements the cmd.Command interface.
+func (c *setModelPolicyCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "set",
+		Args:     "<name>",
+		Purpose:  "Set a model policy.",
+		Doc:      setModelPolicyDoc,
+		Examples: setModelPolicyExample,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *setModelPolicyCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	f.StringVar(&c.groups, "groups", "", "comma separated list of groups whose members' models the policy applies to")
+	f.StringVar(&c.clouds, "clouds", "", "comma separated list of clouds or cloud regions the policy applies to")
+	f.StringVar(&c.allowedClouds, "allowed-clouds", "", "comma separated list of clouds or cloud regions models may be hosted on")
+	f.StringVar(&c.requiredConfig, "required-config", "", "comma separated list of required config")
+	f.StringVar(&c.forbiddenConfig, "forbidden-config", "", "comma separated list of forbidden config")
+	f.StringVar(&c.policy.NamePattern, "name-pattern", "", "regular expression model names must match")
+	f.StringVar(&c.allowedCredentials, "allowed-credentials", "", "comma separated list of allowed credential patterns")
+}
+
+// Init implements the cmd.Command interface.
+func (c *setModelPolicyCommand) Init(args []string) error {
+	if len(args) < 1 {
+		return errors.E("policy name must be specified")
+	}
+	if len(args) > 1 {
+		return errors.E("too many args")
+	}
+	c.policy.Name = args[0]
+	c.policy.Groups = splitList(c.groups)
+	c.policy.Clouds = splitList(c.clouds)
+	c.policy.AllowedClouds = splitList(c.allowedClouds)
+	c.policy.AllowedCredentials = splitList(c.allowedCredentials)
+	var err error
+	c.policy.RequiredConfig, err = parseConfigRules(c.requiredConfig)
+	if err != nil {
+		return err
+	}
+	c.policy.ForbiddenConfig, err = parseConfigRules(c.forbiddenConfig)
+	return err
+}
+
+// splitList splits a comma separated list.
+func splitList(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
+
+// parseConfigRules parses a comma separated list of config rules of the
+// form key=value or key.
+func parseConfigRules(s string) (map[string]string, error) {
+	if s == "" {
+		return nil, nil
+	}
+	m := make(map[string]string)
+	for _, part := range strings.Split(s, ",") {
+		k, v, _ := strings.Cut(part, "=")
+		if k == "" {
+			return nil, errors.E(fmt.Sprintf("invalid config rule %q", part))
+		}
+		m[k] = v
+	}
+	return m, nil
+}
+
+// Run implements Command.Run.
+func (c *setModelPolicyCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	if err := client.SetModelPolicy(&apiparams.SetModelPolicyRequest{ModelPolicy: c.policy}); err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
+
+// newRemoveModelPolicyCommand returns a command to remove a model policy.
+func newRemoveModelPolicyCommand() cmd.Command {
+	cmd := &removeModelPolicyCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// removeModelPolicyCommand removes a model policy.
+type removeModelPolicyCommand struct {
+	modelcmd.ControllerCommandBase
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	req apiparams.RemoveModelPolicyRequest
+}
+
+// Info implements the cmd.Command interface.
+func (c *removeModelPolicyCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "remove",
+		Args:     "<name>",
+		Purpose:  "Remove a model policy.",
+		Doc:      removeModelPolicyDoc,
+		Examples: removeModelPolicyExample,
+	})
+}
+
+// Init implements the cmd.Command interface.
+func (c *removeModelPolicyCommand) Init(args []string) error {
+	if len(args) < 1 {
+		return errors.E("policy name must be specified")
+	}
+	if len(args) > 1 {
+		return errors.E("too many args")
+	}
+	c.req.Name = args[0]
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *removeModelPolicyCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	if err := client.RemoveModelPolicy(&c.req); err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
+
+// newListModelPoliciesCommand returns a command to list model policies.
+func newListModelPoliciesCommand() cmd.Command {
+	cmd := &listModelPoliciesCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// listModelPoliciesCommand lists all model policies.
+type listModelPoliciesCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+}
+
+// Info implements the cmd.Command interface.
+func (c *listModelPoliciesCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "list",
+		Purpose:  "List all model policies.",
+		Doc:      listModelPoliciesDoc,
+		Examples: listModelPoliciesExample,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *listModelPoliciesCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml": cmd.FormatYaml,
+		"json": cmd.FormatJson,
+	})
+}
+
+// Init implements the cmd.Command interface.
+func (c *listModelPoliciesCommand) Init(args []string) error {
+	if len(args) > 0 {
+		return errors.E("too many args")
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *listModelPoliciesCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	resp, err := client.ListModelPolicies()
+	if err != nil {
+		return errors.E(err)
+	}
+
+	err = c.out.Write(ctxt, resp)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+// Copyright 2024 Canonical.
+
+package cmd_test
+
+import (
+	"context"
+
+	"github.com/juju/cmd/v3/cmdtesting"
+	gc "gopkg.in/check.v1"
+
+	"github.com/canonical/jimm/v3/cmd/jimmctl/cmd"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/testutils/cmdtest"
+	"github.com/canonical/jimm/v3/internal/testutils/jimmtest"
+)
+
+type modelPolicySuite struct {
+	cmdtest.JimmCmdSuite
+}
+
+var _ = gc.Suite(&modelPolicySuite{})
+
+func (s *modelPolicySuite) TestModelPolicySuperuser(c *gc.C) {
+	ctx := context.Background()
+	_, err := s.JimmCmdSuite.JIMM.Database.AddGroup(ctx, "students")
+	c.Assert(err, gc.IsNil)
+
+	// alice is superuser
+	bClient := s.SetupCLIAccess(c, "alice")
+	_, err = cmdtesting.RunCommand(c, cmd.NewSetModelPolicyCommandForTesting(s.ClientStore(), bClient),
+		"students",
+		"--groups", "students",
+		"--allowed-clouds", jimmtest.TestCloudName+"/"+jimmtest.TestCloudRegionName,
+		"--required-config", "logging-config=<root>=WARNING,http-proxy",
+		"--forbidden-config", "development",
+		"--name-pattern", "student-.*",
+	)
+	c.Assert(err, gc.IsNil)
+
+	p := dbmodel.ModelPolicy{Name: "students"}
+	err = s.JimmCmdSuite.JIMM.Database.GetModelPolicy(ctx, &p)
+	c.Assert(err, gc.IsNil)
+	c.Check(p.RequiredConfig, gc.DeepEquals, dbmodel.StringMap{"logging-config": "<root>=WARNING", "http-proxy": ""})
+	c.Check(p.ForbiddenConfig, gc.DeepEquals, dbmodel.StringMap{"development": ""})
+
+	cmdCtx, err := cmdtesting.RunCommand(c, cmd.NewListModelPoliciesCommandForTesting(s.ClientStore(), bClient))
+	c.Assert(err, gc.IsNil)
+	c.Check(cmdtesting.Stdout(cmdCtx), gc.Equals, `policies:
+- name: students
+  groups:
+  - students
+  allowed-clouds:
+  - `+jimmtest.TestCloudName+`/`+jimmtest.TestCloudRegionName+`
+  required-config:
+    http-proxy: ""
+    logging-config: <root>=WARNING
+  forbidden-config:
+    development: ""
+  name-pattern: student-.*
+`)
+
+	_, err = cmdtesting.RunCommand(c, cmd.NewRemoveModelPolicyCommandForTesting(s.ClientStore(), bClient), "students")
+	c.Assert(err, gc.IsNil)
+	_, err = cmdtesting.RunCommand(c, cmd.NewRemoveModelPolicyCommandForTesting(s.ClientStore(), bClient), "students")
+	c.Assert(err, gc.ErrorMatches, `.*model policy not found.*`)
+}
+
+func (s *modelPolicySuite) TestSetModelPolicy(c *gc.C) {
+	// bob is not superuser
+	bClient := s.SetupCLIAccess(c, "bob")
+	_, err := cmdtesting.RunCommand(c, cmd.NewSetModelPolicyCommandForTesting(s.ClientStore(), bClient), "students")
+	c.Assert(err, gc.ErrorMatches, `unauthorized.*`)
+}
+
+func (s *modelPolicySuite) TestSetModelPolicyInvalidArgs(c *gc.C) {
+	bClient := s.SetupCLIAccess(c, "alice")
+	_, err := cmdtesting.RunCommand(c, cmd.NewSetModelPolicyCommandForTesting(s.ClientStore(), bClient))
+	c.Assert(err, gc.ErrorMatches, `policy name must be specified`)
+	_, err = cmdtesting.RunCommand(c, cmd.NewSetModelPolicyCommandForTesting(s.ClientStore(), bClient), "p", "--required-config", "=x")
+	c.Assert(err, gc.ErrorMatches, `invalid config rule "=x"`)
+	_, err = cmdtesting.RunCommand(c, cmd.NewSetModelPolicyCommandForTesting(s.ClientStore(), bClient), "p", "--name-pattern", "(")
+	c.Assert(err, gc.ErrorMatches, `invalid name pattern "\("`)
+}
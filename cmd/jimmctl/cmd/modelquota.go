@@ -0,0 +1,279 @@
+// Copyright 2024 Canonical.
+
+package cmd
+
+import (
+	"strconv"
+
+	"github.com/juju/cmd/v3"
+	jujucmdv3 "github.com/juju/cmd/v3"
+	"github.com/juju/gnuflag"
+	jujuapi "github.com/juju/juju/api"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/pkg/api"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const (
+	modelQuotaDoc = `
+The model-quota command enables management of model quotas in jimm.
+
+Quotas limit the number of models that may be created. An identity
+quota limits the number of models an identity may own, a group quota
+limits the number of models each member of the group may own and a
+cloud quota limits the number of models hosted on a cloud, or on a
+region of a cloud. Every quota that applies must be satisfied for a
+model to be added or imported.
+`
+
+	setModelQuotaDoc = `
+The set command creates, or updates, a model quota. The kind of quota
+must be one of identity, group or cloud.
+`
+	setModelQuotaExample = `
+    jimmctl model-quota set identity alice@canonical.com 5
+    jimmctl model-quota set group students 2
+    jimmctl model-quota set cloud aws 100
+    jimmctl model-quota set cloud aws 20 --region eu-west-1
+`
+	removeModelQuotaDoc = `
+The remove command removes a model quota.
+`
+	removeModelQuotaExample = `
+    jimmctl model-quota remove group students
+    jimmctl model-quota remove cloud aws --region eu-west-1
+`
+	listModelQuotasDoc = `
+The list command lists all model quotas in jimm.
+`
+	listModelQuotasExample = `
+    jimmctl model-quota list
+`
+)
+
+// NewModelQuotaCommand returns a command for model quota management.
+func NewModelQuotaCommand() *jujucmdv3.SuperCommand {
+	cmd := jujucmd.NewSuperCommand(jujucmdv3.SuperCommandParams{
+		Name:    "model-quota",
+		Doc:     modelQuotaDoc,
+		Purpose: "Model quota management.",
+	})
+	cmd.Register(newSetModelQuotaCommand())
+	cmd.Register(newRemoveModelQuotaCommand())
+	cmd.Register(newListModelQuotasCommand())
+
+	return cmd
+}
+
+// newSetModelQuotaCommand returns a command to set a model quota.
+func newSetModelQuotaCommand() cmd.Command {
+	cmd := &setModelQuotaCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// setModelQuotaCommand creates, or updates, a model quota.
+type setModelQuotaCommand struct {
+	modelcmd.ControllerCommandBase
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	quota apiparams.ModelQuota
+}
+
+// Info implements the cmd.Command interface.
+func (c *setModelQuotaCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "set",
+		Args:     "<kind> <subject> <max models>",
+		Purpose:  "Set a model quota.",
+		Doc:      setModelQuotaDoc,
+		Examples: setModelQuotaExample,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *setModelQuotaCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	f.StringVar(&c.quota.Region, "region", "", "the cloud region a cloud quota applies to")
+}
+
+// Init implements the cmd.Command interface.
+func (c *setModelQuotaCommand) Init(args []string) error {
+	if len(args) < 3 {
+		return errors.E("kind, subject and max models must be specified")
+	}
+	if len(args) > 3 {
+		return errors.E("too many args")
+	}
+	c.quota.Kind, c.quota.Subject = args[0], args[1]
+	maxModels, err := strconv.Atoi(args[2])
+	if err != nil || maxModels < 0 {
+		return errors.E("max models must be a non-negative integer")
+	}
+	c.quota.MaxModels = maxModels
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *setModelQuotaCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	if err := client.SetModelQuota(&apiparams.SetModelQuotaRequest{ModelQuota: c.quota}); err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
+
+// newRemoveModelQuotaCommand returns a command to remove a model quota.
+func newRemoveModelQuotaCommand() cmd.Command {
+	cmd := &removeModelQuotaCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// removeModelQuotaCommand removes a model quota.
+type removeModelQuotaCommand struct {
+	modelcmd.ControllerCommandBase
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	req apiparams.RemoveModelQuotaRequest
+}
+
+// Info implements the cmd.Command interface.
+func (c *removeModelQuotaCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "remove",
+		Args:     "<kind> <subject>",
+		Purpose:  "Remove a model quota.",
+		Doc:      removeModelQuotaDoc,
+		Examples: removeModelQuotaExample,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *removeModelQuotaCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	f.StringVar(&c.req.Region, "region", "", "the cloud region a cloud quota applies to")
+}
+
+// Init implements the cmd.Command interface.
+func (c *removeModelQuotaCommand) Init(args []string) error {
+	if len(args) < 2 {
+		return errors.E("kind and subject must be specified")
+	}
+	if len(args) > 2 {
+		return errors.E("too many args")
+	}
+	c.req.Kind, c.req.Subject = args[0], args[1]
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *removeModelQuotaCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	if err := client.RemoveModelQuota(&c.req); err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
+
+// newListModelQuotasCommand returns a command to list model quotas.
+func newListModelQuotasCommand() cmd.Command {
+	cmd := &listModelQuotasCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// listModelQuotasCommand lists all model quotas.
+type listModelQuotasCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+}
+
+// Info implements the cmd.Command interface.
+func (c *listModelQuotasCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "list",
+		Purpose:  "List all model quotas.",
+		Doc:      listModelQuotasDoc,
+		Examples: listModelQuotasExample,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *listModelQuotasCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml": cmd.FormatYaml,
+		"json": cmd.FormatJson,
+	})
+}
+
+// Init implements the cmd.Command interface.
+func (c *listModelQuotasCommand) Init(args []string) error {
+	if len(args) > 0 {
+		return errors.E("too many args")
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *listModelQuotasCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	resp, err := client.ListModelQuotas()
+	if err != nil {
+		return errors.E(err)
+	}
+
+	err = c.out.Write(ctxt, resp)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+// Copyright 2024 Canonical.
+
+package cmd_test
+
+import (
+	"context"
+
+	"github.com/juju/cmd/v3/cmdtesting"
+	gc "gopkg.in/check.v1"
+
+	"github.com/canonical/jimm/v3/cmd/jimmctl/cmd"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/testutils/cmdtest"
+)
+
+type modelQuotaSuite struct {
+	cmdtest.JimmCmdSuite
+}
+
+var _ = gc.Suite(&modelQuotaSuite{})
+
+func (s *modelQuotaSuite) TestModelQuotaSuperuser(c *gc.C) {
+	ctx := context.Background()
+	_, err := s.JimmCmdSuite.JIMM.Database.AddGroup(ctx, "test-group")
+	c.Assert(err, gc.IsNil)
+
+	// alice is superuser
+	bClient := s.SetupCLIAccess(c, "alice")
+	_, err = cmdtesting.RunCommand(c, cmd.NewSetModelQuotaCommandForTesting(s.ClientStore(), bClient), "group", "test-group", "3")
+	c.Assert(err, gc.IsNil)
+	_, err = cmdtesting.RunCommand(c, cmd.NewSetModelQuotaCommandForTesting(s.ClientStore(), bClient), "identity", "bob@canonical.com", "5")
+	c.Assert(err, gc.IsNil)
+
+	quotas, err := s.JimmCmdSuite.JIMM.Database.FindModelQuotas(ctx, dbmodel.ModelQuotaIdentity, "bob@canonical.com")
+	c.Assert(err, gc.IsNil)
+	c.Assert(quotas, gc.HasLen, 1)
+	c.Check(quotas[0].MaxModels, gc.Equals, 5)
+
+	cmdCtx, err := cmdtesting.RunCommand(c, cmd.NewListModelQuotasCommandForTesting(s.ClientStore(), bClient))
+	c.Assert(err, gc.IsNil)
+	c.Check(cmdtesting.Stdout(cmdCtx), gc.Equals, `quotas:
+- kind: group
+  subject: test-group
+  max-models: 3
+- kind: identity
+  subject: bob@canonical.com
+  max-models: 5
+`)
+
+	_, err = cmdtesting.RunCommand(c, cmd.NewRemoveModelQuotaCommandForTesting(s.ClientStore(), bClient), "identity", "bob@canonical.com")
+	c.Assert(err, gc.IsNil)
+	quotas, err = s.JimmCmdSuite.JIMM.Database.FindModelQuotas(ctx, dbmodel.ModelQuotaIdentity, "bob@canonical.com")
+	c.Assert(err, gc.IsNil)
+	c.Check(quotas, gc.HasLen, 0)
+}
+
+func (s *modelQuotaSuite) TestSetModelQuota(c *gc.C) {
+	// bob is not superuser
+	bClient := s.SetupCLIAccess(c, "bob")
+	_, err := cmdtesting.RunCommand(c, cmd.NewSetModelQuotaCommandForTesting(s.ClientStore(), bClient), "identity", "bob@canonical.com", "5")
+	c.Assert(err, gc.ErrorMatches, `unauthorized.*`)
+}
+
+func (s *modelQuotaSuite) TestSetModelQuotaInvalidArgs(c *gc.C) {
+	bClient := s.SetupCLIAccess(c, "alice")
+	_, err := cmdtesting.RunCommand(c, cmd.NewSetModelQuotaCommandForTesting(s.ClientStore(), bClient), "identity", "bob@canonical.com")
+	c.Assert(err, gc.ErrorMatches, `kind, subject and max models must be specified`)
+	_, err = cmdtesting.RunCommand(c, cmd.NewSetModelQuotaCommandForTesting(s.ClientStore(), bClient), "identity", "bob@canonical.com", "many")
+	c.Assert(err, gc.ErrorMatches, `max models must be a non-negative integer`)
+}
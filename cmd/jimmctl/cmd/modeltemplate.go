@@ -0,0 +1,395 @@
+// Copyright 2024 Canonical.
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/juju/cmd/v3"
+	jujucmdv3 "github.com/juju/cmd/v3"
+	"github.com/juju/gnuflag"
+	jujuapi "github.com/juju/juju/api"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/juju/common"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/pkg/api"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const (
+	modelTemplateDoc = `
+The model-template command enables management of model templates in jimm.
+
+A model template holds model config, an optional cloud and region the
+model must be hosted on, the name of the cloud credential to use when
+none is given, labels and a set of group grants. A model is created from
+a template by setting the jimm-template config key when adding it:
+
+    juju add-model mymodel --config jimm-template=prod
+`
+
+	setModelTemplateDoc = `
+The set command creates a model template, or replaces an existing
+template with the same name.
+
+Grants take the form <group>=<access>, where access is one of read,
+write or admin. Labels take the form <key>=<value>.
+`
+	setModelTemplateExample = `
+    jimmctl model-template set prod --cloud aws --region eu-west-1 --config logging-config="<root>=WARNING" --grants ops=admin,devs=read --labels env=prod
+    jimmctl model-template set dev --credential dev-cred --config ./dev-config.yaml
+`
+	removeModelTemplateDoc = `
+The remove command removes a model template. Models previously created
+from the template are not affected.
+`
+	removeModelTemplateExample = `
+    jimmctl model-template remove prod
+`
+	showModelTemplateDoc = `
+The show command shows a model template.
+`
+	showModelTemplateExample = `
+    jimmctl model-template show prod
+`
+	listModelTemplatesDoc = `
+The list command lists all model templates in jimm.
+`
+	listModelTemplatesExample = `
+    jimmctl model-template list
+`
+)
+
+// NewModelTemplateCommand returns a command for model template
+// management.
+func NewModelTemplateCommand() *jujucmdv3.SuperCommand {
+	cmd := jujucmd.NewSuperCommand(jujucmdv3.SuperCommandParams{
+		Name:    "model-template",
+		Doc:     modelTemplateDoc,
+		Purpose: "Model template management.",
+	})
+	cmd.Register(newSetModelTemplateCommand())
+	cmd.Register(newRemoveModelTemplateCommand())
+	cmd.Register(newShowModelTemplateCommand())
+	cmd.Register(newListModelTemplatesCommand())
+
+	return cmd
+}
+
+// newSetModelTemplateCommand returns a command to set a model template.
+func newSetModelTemplateCommand() cmd.Command {
+	cmd := &setModelTemplateCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// setModelTemplateCommand creates, or replaces, a model template.
+type setModelTemplateCommand struct {
+	modelcmd.ControllerCommandBase
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	template apiparams.ModelTemplate
+	config   common.ConfigFlag
+	grants   string
+	labels   string
+}
+
+// Info implements the cmd.Command interface.
+func (c *setModelTemplateCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "set",
+		Args:     "<name>",
+		Purpose:  "Set a model template.",
+		Doc:      setModelTemplateDoc,
+		Examples: setModelTemplateExample,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *setModelTemplateCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	f.StringVar(&c.template.Cloud, "cloud", "", "the cloud models must be hosted on")
+	f.StringVar(&c.template.Region, "region", "", "the cloud region models must be hosted in")
+	f.StringVar(&c.template.CloudCredential, "credential", "", "the name of the owner's credential used when none is specified")
+	f.Var(&c.config, "config", "path to a YAML model config file and/or a list of key=value pairs")
+	f.StringVar(&c.grants, "grants", "", "comma separated list of <group>=<access> grants")
+	f.StringVar(&c.labels, "labels", "", "comma separated list of <key>=<value> labels")
+}
+
+// Init implements the cmd.Command interface.
+func (c *setModelTemplateCommand) Init(args []string) error {
+	if len(args) < 1 {
+		return errors.E("template name must be specified")
+	}
+	if len(args) > 1 {
+		return errors.E("too many args")
+	}
+	c.template.Name = args[0]
+	var err error
+	c.template.Grants, err = parseKeyValueList("grant", c.grants)
+	if err != nil {
+		return err
+	}
+	c.template.Labels, err = parseKeyValueList("label", c.labels)
+	return err
+}
+
+// parseKeyValueList parses a comma separated list of key=value pairs.
+func parseKeyValueList(kind, s string) (map[string]string, error) {
+	if s == "" {
+		return nil, nil
+	}
+	m := make(map[string]string)
+	for _, part := range strings.Split(s, ",") {
+		k, v, ok := strings.Cut(part, "=")
+		if !ok || k == "" {
+			return nil, errors.E(fmt.Sprintf("invalid %s %q, expected key=value", kind, part))
+		}
+		m[k] = v
+	}
+	return m, nil
+}
+
+// Run implements Command.Run.
+func (c *setModelTemplateCommand) Run(ctxt *cmd.Context) error {
+	config, err := c.config.ReadAttrs(ctxt)
+	if err != nil {
+		return errors.E(err)
+	}
+	if len(config) > 0 {
+		c.template.Config = config
+	}
+
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	if err := client.SetModelTemplate(&apiparams.SetModelTemplateRequest{ModelTemplate: c.template}); err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
+
+// newRemoveModelTemplateCommand returns a command to remove a model
+// template.
+func newRemoveModelTemplateCommand() cmd.Command {
+	cmd := &removeModelTemplateCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// removeModelTemplateCommand removes a model template.
+type removeModelTemplateCommand struct {
+	modelcmd.ControllerCommandBase
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	req apiparams.RemoveModelTemplateRequest
+}
+
+// Info implements the cmd.Command interface.
+func (c *removeModelTemplateCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "remove",
+		Args:     "<name>",
+		Purpose:  "Remove a model template.",
+		Doc:      removeModelTemplateDoc,
+		Examples: removeModelTemplateExample,
+	})
+}
+
+// Init implements the cmd.Command interface.
+func (c *removeModelTemplateCommand) Init(args []string) error {
+	if len(args) < 1 {
+		return errors.E("template name must be specified")
+	}
+	if len(args) > 1 {
+		return errors.E("too many args")
+	}
+	c.req.Name = args[0]
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *removeModelTemplateCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	if err := client.RemoveModelTemplate(&c.req); err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
+
+// newShowModelTemplateCommand returns a command to show a model template.
+func newShowModelTemplateCommand() cmd.Command {
+	cmd := &showModelTemplateCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// showModelTemplateCommand shows a model template.
+type showModelTemplateCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	req apiparams.GetModelTemplateRequest
+}
+
+// Info implements the cmd.Command interface.
+func (c *showModelTemplateCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "show",
+		Args:     "<name>",
+		Purpose:  "Show a model template.",
+		Doc:      showModelTemplateDoc,
+		Examples: showModelTemplateExample,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *showModelTemplateCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml": cmd.FormatYaml,
+		"json": cmd.FormatJson,
+	})
+}
+
+// Init implements the cmd.Command interface.
+func (c *showModelTemplateCommand) Init(args []string) error {
+	if len(args) < 1 {
+		return errors.E("template name must be specified")
+	}
+	if len(args) > 1 {
+		return errors.E("too many args")
+	}
+	c.req.Name = args[0]
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *showModelTemplateCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	resp, err := client.GetModelTemplate(&c.req)
+	if err != nil {
+		return errors.E(err)
+	}
+
+	err = c.out.Write(ctxt, resp)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
+
+// newListModelTemplatesCommand returns a command to list model templates.
+func newListModelTemplatesCommand() cmd.Command {
+	cmd := &listModelTemplatesCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// listModelTemplatesCommand lists all model templates.
+type listModelTemplatesCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+}
+
+// Info implements the cmd.Command interface.
+func (c *listModelTemplatesCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "list",
+		Purpose:  "List all model templates.",
+		Doc:      listModelTemplatesDoc,
+		Examples: listModelTemplatesExample,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *listModelTemplatesCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml": cmd.FormatYaml,
+		"json": cmd.FormatJson,
+	})
+}
+
+// Init implements the cmd.Command interface.
+func (c *listModelTemplatesCommand) Init(args []string) error {
+	if len(args) > 0 {
+		return errors.E("too many args")
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *listModelTemplatesCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	resp, err := client.ListModelTemplates()
+	if err != nil {
+		return errors.E(err)
+	}
+
+	err = c.out.Write(ctxt, resp)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+// Copyright 2024 Canonical.
+
+package cmd_test
+
+import (
+	"context"
+
+	"github.com/juju/cmd/v3/cmdtesting"
+	gc "gopkg.in/check.v1"
+
+	"github.com/canonical/jimm/v3/cmd/jimmctl/cmd"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/testutils/cmdtest"
+	"github.com/canonical/jimm/v3/internal/testutils/jimmtest"
+)
+
+type modelTemplateSuite struct {
+	cmdtest.JimmCmdSuite
+}
+
+var _ = gc.Suite(&modelTemplateSuite{})
+
+func (s *modelTemplateSuite) TestModelTemplateSuperuser(c *gc.C) {
+	ctx := context.Background()
+	group, err := s.JimmCmdSuite.JIMM.Database.AddGroup(ctx, "ops")
+	c.Assert(err, gc.IsNil)
+
+	// alice is superuser
+	bClient := s.SetupCLIAccess(c, "alice")
+	_, err = cmdtesting.RunCommand(c, cmd.NewSetModelTemplateCommandForTesting(s.ClientStore(), bClient),
+		"prod",
+		"--cloud", jimmtest.TestCloudName,
+		"--region", jimmtest.TestCloudRegionName,
+		"--config", "logging-config=<root>=WARNING",
+		"--grants", "ops=admin",
+		"--labels", "env=prod",
+	)
+	c.Assert(err, gc.IsNil)
+
+	t := dbmodel.ModelTemplate{Name: "prod"}
+	err = s.JimmCmdSuite.JIMM.Database.GetModelTemplate(ctx, &t)
+	c.Assert(err, gc.IsNil)
+	c.Check(t.Config, gc.DeepEquals, dbmodel.Map{"logging-config": "<root>=WARNING"})
+	// Grants are stored by group UUID.
+	c.Check(t.Grants, gc.DeepEquals, dbmodel.StringMap{group.UUID: "admin"})
+
+	cmdCtx, err := cmdtesting.RunCommand(c, cmd.NewShowModelTemplateCommandForTesting(s.ClientStore(), bClient), "prod")
+	c.Assert(err, gc.IsNil)
+	c.Check(cmdtesting.Stdout(cmdCtx), gc.Equals, `name: prod
+config:
+  logging-config: <root>=WARNING
+cloud: `+jimmtest.TestCloudName+`
+region: `+jimmtest.TestCloudRegionName+`
+labels:
+  env: prod
+grants:
+  ops: admin
+`)
+
+	cmdCtx, err = cmdtesting.RunCommand(c, cmd.NewListModelTemplatesCommandForTesting(s.ClientStore(), bClient))
+	c.Assert(err, gc.IsNil)
+	c.Check(cmdtesting.Stdout(cmdCtx), gc.Matches, `templates:\n- name: prod\n(.|\n)*`)
+
+	_, err = cmdtesting.RunCommand(c, cmd.NewRemoveModelTemplateCommandForTesting(s.ClientStore(), bClient), "prod")
+	c.Assert(err, gc.IsNil)
+	_, err = cmdtesting.RunCommand(c, cmd.NewShowModelTemplateCommandForTesting(s.ClientStore(), bClient), "prod")
+	c.Assert(err, gc.ErrorMatches, `.*model template not found.*`)
+}
+
+func (s *modelTemplateSuite) TestSetModelTemplate(c *gc.C) {
+	// bob is not superuser
+	bClient := s.SetupCLIAccess(c, "bob")
+	_, err := cmdtesting.RunCommand(c, cmd.NewSetModelTemplateCommandForTesting(s.ClientStore(), bClient), "prod")
+	c.Assert(err, gc.ErrorMatches, `unauthorized.*`)
+}
+
+func (s *modelTemplateSuite) TestSetModelTemplateInvalidArgs(c *gc.C) {
+	bClient := s.SetupCLIAccess(c, "alice")
+	_, err := cmdtesting.RunCommand(c, cmd.NewSetModelTemplateCommandForTesting(s.ClientStore(), bClient))
+	c.Assert(err, gc.ErrorMatches, `template name must be specified`)
+	_, err = cmdtesting.RunCommand(c, cmd.NewSetModelTemplateCommandForTesting(s.ClientStore(), bClient), "prod", "--grants", "ops")
+	c.Assert(err, gc.ErrorMatches, `invalid grant "ops", expected key=value`)
+	_, err = cmdtesting.RunCommand(c, cmd.NewSetModelTemplateCommandForTesting(s.ClientStore(), bClient), "prod", "--grants", "ops=owner")
+	c.Assert(err, gc.ErrorMatches, `invalid access level "owner" for group "ops"`)
+}
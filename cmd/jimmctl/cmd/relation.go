@@ -61,9 +61,13 @@ Object may be one of:
 	model tag               = "model-<name>"
 	application offer tag   = "offer-<name>"
 
-If target_object is a group, the relation can only be:
+If target_object is a group, the relation can be one of:
 
 	member
+	manager
+
+Users that are not JIMM administrators may add or remove member and
+manager relations for the groups they manage.
 
 If target_object is a controller, the relation can be one of:
 
@@ -571,15 +575,17 @@ func (c *listRelationsCommand) Run(ctxt *cmd.Context) error {
 
 func fetchRelations(client *api.Client, params apiparams.ListRelationshipTuplesRequest) (*apiparams.ListRelationshipTuplesResponse, error) {
 	tuples := make([]apiparams.RelationshipTuple, 0)
+	var errs []string
 	for {
 		response, err := client.ListRelationshipTuples(&params)
 		if err != nil {
 			return nil, errors.E(fmt.Sprintf("failed to fetch list of relationship tuples: %s", err.Error()))
 		}
 		tuples = append(tuples, response.Tuples...)
+		errs = append(errs, response.Errors...)
 
 		if response.ContinuationToken == "" {
-			return &apiparams.ListRelationshipTuplesResponse{Tuples: tuples, Errors: response.Errors}, nil
+			return &apiparams.ListRelationshipTuplesResponse{Tuples: tuples, Errors: errs}, nil
 		}
 		params.ContinuationToken = response.ContinuationToken
 	}
@@ -0,0 +1,175 @@
+// Copyright 2024 Canonical.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/gosuri/uitable"
+	"github.com/juju/cmd/v3"
+	jujucmdv3 "github.com/juju/cmd/v3"
+	"github.com/juju/gnuflag"
+	jujuapi "github.com/juju/juju/api"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/pkg/api"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const (
+	reportDoc = `
+The report command generates reports about the estate managed by jimm.
+`
+
+	charmDriftReportDoc = `
+The charm-drift command reports, for every charm deployed in the models
+you can read, the revisions, channels, bases and workload versions in
+use, and lists the applications that are behind the newest revision of
+their charm in use from the same channel and base.
+
+The report is generated from the estate inventory periodically collected
+by jimm.
+`
+	charmDriftReportExample = `
+    jimmctl report charm-drift
+    jimmctl report charm-drift --charm postgresql --format tabular
+    jimmctl report charm-drift --controller controller-1 --format json
+`
+)
+
+// NewReportCommand returns a command for estate reports.
+func NewReportCommand() *jujucmdv3.SuperCommand {
+	cmd := jujucmd.NewSuperCommand(jujucmdv3.SuperCommandParams{
+		Name:    "report",
+		Doc:     reportDoc,
+		Purpose: "Estate reports.",
+	})
+	cmd.Register(newCharmDriftReportCommand())
+
+	return cmd
+}
+
+// newCharmDriftReportCommand returns a command to report charm drift.
+func newCharmDriftReportCommand() cmd.Command {
+	cmd := &charmDriftReportCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// charmDriftReportCommand reports the charm revisions deployed across the
+// estate.
+type charmDriftReportCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	req apiparams.CharmDriftReportRequest
+}
+
+// Info implements the cmd.Command interface.
+func (c *charmDriftReportCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "charm-drift",
+		Purpose:  "Report charm revision drift across the estate.",
+		Doc:      charmDriftReportDoc,
+		Examples: charmDriftReportExample,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *charmDriftReportCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatCharmDriftTabular,
+	})
+	f.StringVar(&c.req.Charm, "charm", "", "only report on the named charm")
+	f.StringVar(&c.req.Controller, "controller", "", "only report on models hosted on the named controller")
+}
+
+// Init implements the cmd.Command interface.
+func (c *charmDriftReportCommand) Init(args []string) error {
+	if len(args) > 0 {
+		return errors.E("too many args")
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *charmDriftReportCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	resp, err := client.CharmDriftReport(&c.req)
+	if err != nil {
+		return errors.E(err)
+	}
+
+	err = c.out.Write(ctxt, resp)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
+
+func formatCharmDriftTabular(writer io.Writer, value interface{}) error {
+	report, ok := value.(*apiparams.CharmDriftReport)
+	if !ok {
+		return errors.E(fmt.Sprintf("expected value of type %T, got %T", report, value))
+	}
+
+	table := uitable.New()
+	table.MaxColWidth = 80
+	table.Wrap = true
+
+	table.AddRow("Charm", "Applications", "Models", "Latest", "Revisions", "Channels", "Bases", "Behind")
+	for _, c := range report.Charms {
+		table.AddRow(c.Charm, c.Applications, c.Models, c.LatestRevision, formatDistribution(c.Revisions), formatDistribution(c.Channels), formatDistribution(c.Bases), len(c.Behind))
+	}
+	fmt.Fprintln(writer, table)
+
+	behind := uitable.New()
+	behind.MaxColWidth = 80
+	behind.Wrap = true
+	behind.AddRow("Charm", "Model", "Owner", "Controller", "Application", "Revision", "Latest", "Channel", "Base")
+	n := 0
+	for _, c := range report.Charms {
+		for _, a := range c.Behind {
+			behind.AddRow(c.Charm, a.ModelName, a.Owner, a.Controller, a.Application, a.Revision, a.LatestRevision, a.Channel, a.Base)
+			n++
+		}
+	}
+	if n > 0 {
+		fmt.Fprintln(writer)
+		fmt.Fprintln(writer, behind)
+	}
+	return nil
+}
+
+// formatDistribution formats distribution entries as a comma separated
+// list of value:count pairs.
+func formatDistribution(entries []apiparams.DistributionEntry) string {
+	parts := make([]string, len(entries))
+	for i, e := range entries {
+		parts[i] = fmt.Sprintf("%s:%d", e.Value, e.Count)
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,79 @@
+// Copyright 2024 Canonical.
+
+package cmd_test
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/juju/cmd/v3/cmdtesting"
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+	gc "gopkg.in/check.v1"
+
+	"github.com/canonical/jimm/v3/cmd/jimmctl/cmd"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/testutils/cmdtest"
+	"github.com/canonical/jimm/v3/internal/testutils/jimmtest"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+type reportSuite struct {
+	cmdtest.JimmCmdSuite
+}
+
+var _ = gc.Suite(&reportSuite{})
+
+func (s *reportSuite) TestCharmDriftReport(c *gc.C) {
+	ctx := context.Background()
+	s.AddController(c, "controller-1", s.APIInfo(c))
+
+	cct := names.NewCloudCredentialTag(jimmtest.TestCloudName + "/charlie@canonical.com/cred")
+	s.UpdateCloudCredential(c, cct, jujuparams.CloudCredential{AuthType: "empty", Attributes: map[string]string{"key": "value"}})
+	s.AddModel(c, names.NewUserTag("charlie@canonical.com"), "model-2", names.NewCloudTag(jimmtest.TestCloudName), jimmtest.TestCloudRegionName, cct)
+
+	err := s.JIMM.CollectControllerInventory(ctx, &dbmodel.Controller{Name: "controller-1"})
+	c.Assert(err, gc.IsNil)
+
+	bClient := s.SetupCLIAccess(c, "alice")
+	cmdCtx, err := cmdtesting.RunCommand(c, cmd.NewCharmDriftReportCommandForTesting(s.ClientStore(), bClient))
+	c.Assert(err, gc.IsNil)
+	c.Check(cmdtesting.Stdout(cmdCtx), gc.Equals, "charms: []\n")
+
+	_, err = cmdtesting.RunCommand(c, cmd.NewCharmDriftReportCommandForTesting(s.ClientStore(), bClient), "extra")
+	c.Check(err, gc.ErrorMatches, `too many args`)
+}
+
+func (s *reportSuite) TestFormatCharmDriftTabular(c *gc.C) {
+	var buf bytes.Buffer
+	err := cmd.FormatCharmDriftTabular(&buf, &apiparams.CharmDriftReport{
+		Charms: []apiparams.CharmDrift{{
+			Charm:          "postgresql",
+			Applications:   2,
+			Models:         2,
+			LatestRevision: 300,
+			Revisions:      []apiparams.DistributionEntry{{Value: "300", Count: 1}, {Value: "280", Count: 1}},
+			Channels:       []apiparams.DistributionEntry{{Value: "14/stable", Count: 2}},
+			Bases:          []apiparams.DistributionEntry{{Value: "ubuntu@22.04", Count: 2}},
+			Behind: []apiparams.DriftedApplication{{
+				ModelName:      "model-1",
+				Owner:          "alice@canonical.com",
+				Controller:     "controller-1",
+				Application:    "db",
+				Revision:       280,
+				LatestRevision: 300,
+				Channel:        "14/stable",
+				Base:           "ubuntu@22.04",
+			}},
+		}},
+	})
+	c.Assert(err, gc.IsNil)
+	c.Check(buf.String(), gc.Equals, "Charm     \tApplications\tModels\tLatest\tRevisions  \tChannels   \tBases         \tBehind\n"+
+		"postgresql\t2           \t2     \t300   \t300:1,280:1\t14/stable:2\tubuntu@22.04:2\t1     \n"+
+		"\n"+
+		"Charm     \tModel  \tOwner              \tController  \tApplication\tRevision\tLatest\tChannel  \tBase        \n"+
+		"postgresql\tmodel-1\talice@canonical.com\tcontroller-1\tdb         \t280     \t300   \t14/stable\tubuntu@22.04\n")
+
+	err = cmd.FormatCharmDriftTabular(&buf, "not a report")
+	c.Check(err, gc.ErrorMatches, `expected value of type .*`)
+}
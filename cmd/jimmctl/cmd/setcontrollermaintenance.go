@@ -0,0 +1,145 @@
+// Copyright 2024 Canonical.
+
+package cmd
+
+import (
+	"time"
+
+	"github.com/juju/cmd/v3"
+	"github.com/juju/gnuflag"
+	jujuapi "github.com/juju/juju/api"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/pkg/api"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const (
+	setControllerMaintenanceDoc = `
+The set-controller-maintenance command puts a controller into maintenance,
+or takes it out of maintenance when --off is given.
+
+While a controller is in maintenance JIMM creates no new models on it and
+refuses connections and requests to its models with an error containing
+the maintenance message. If --allow-read-only is given requests that
+only read the state of a model are still allowed. If --until is given the
+controller leaves maintenance automatically at that time, which must be
+in RFC3339 format.
+`
+	setControllerMaintenanceExample = `
+    jimmctl set-controller-maintenance mycontroller --message "upgrading to 3.6"
+    jimmctl set-controller-maintenance mycontroller --until 2024-06-01T12:00:00Z --allow-read-only
+    jimmctl set-controller-maintenance mycontroller --off
+`
+)
+
+// NewSetControllerMaintenanceCommand returns a command used to set the
+// maintenance state of a controller.
+func NewSetControllerMaintenanceCommand() cmd.Command {
+	cmd := &setControllerMaintenanceCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// setControllerMaintenanceCommand sets the maintenance state of a
+// controller.
+type setControllerMaintenanceCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	controllerName string
+	message        string
+	until          string
+	allowReadOnly  bool
+	off            bool
+
+	endTime *time.Time
+}
+
+func (c *setControllerMaintenanceCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "set-controller-maintenance",
+		Args:     "<controller name>",
+		Purpose:  "Sets controller maintenance state.",
+		Doc:      setControllerMaintenanceDoc,
+		Examples: setControllerMaintenanceExample,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *setControllerMaintenanceCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml": cmd.FormatYaml,
+		"json": cmd.FormatJson,
+	})
+	f.StringVar(&c.message, "message", "", "message shown to users whose requests are refused")
+	f.StringVar(&c.until, "until", "", "time the maintenance ends, in RFC3339 format")
+	f.BoolVar(&c.allowReadOnly, "allow-read-only", false, "allow read-only requests during the maintenance")
+	f.BoolVar(&c.off, "off", false, "take the controller out of maintenance")
+}
+
+// Init implements the cmd.Command interface.
+func (c *setControllerMaintenanceCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.E("missing controller name")
+	}
+	c.controllerName, args = args[0], args[1:]
+	if len(args) > 0 {
+		return errors.E("unknown arguments")
+	}
+	if c.off {
+		if c.message != "" || c.until != "" || c.allowReadOnly {
+			return errors.E("--off cannot be used with other maintenance options")
+		}
+		return nil
+	}
+	if c.until != "" {
+		t, err := time.Parse(time.RFC3339, c.until)
+		if err != nil {
+			return errors.E(err, "invalid --until time")
+		}
+		c.endTime = &t
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *setControllerMaintenanceCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+
+	info, err := client.SetControllerMaintenance(&apiparams.SetControllerMaintenanceRequest{
+		Name:          c.controllerName,
+		Maintenance:   !c.off,
+		Message:       c.message,
+		EndTime:       c.endTime,
+		AllowReadOnly: c.allowReadOnly,
+	})
+	if err != nil {
+		return errors.E(err)
+	}
+
+	err = c.out.Write(ctxt, info)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+// Copyright 2024 Canonical.
+
+package cmd_test
+
+import (
+	"github.com/juju/cmd/v3/cmdtesting"
+	gc "gopkg.in/check.v1"
+
+	"github.com/canonical/jimm/v3/cmd/jimmctl/cmd"
+	"github.com/canonical/jimm/v3/internal/testutils/cmdtest"
+)
+
+type setControllerMaintenanceSuite struct {
+	cmdtest.JimmCmdSuite
+}
+
+var _ = gc.Suite(&setControllerMaintenanceSuite{})
+
+func (s *setControllerMaintenanceSuite) TestSetControllerMaintenanceSuperuser(c *gc.C) {
+	s.AddController(c, "controller-1", s.APIInfo(c))
+
+	// alice is superuser
+	bClient := s.SetupCLIAccess(c, "alice")
+	context, err := cmdtesting.RunCommand(c, cmd.NewSetControllerMaintenanceCommandForTesting(s.ClientStore(), bClient), "controller-1", "--message", "upgrading", "--until", "2099-01-02T03:04:05Z", "--allow-read-only")
+	c.Assert(err, gc.IsNil)
+	c.Assert(cmdtesting.Stdout(context), gc.Matches, `(?s)name: controller-1
+.*status:
+  status: maintenance
+  info: upgrading
+  data:
+    allow-read-only: true
+    ends-at: "2099-01-02T03:04:05Z"
+  since: null
+`)
+
+	context, err = cmdtesting.RunCommand(c, cmd.NewSetControllerMaintenanceCommandForTesting(s.ClientStore(), bClient), "controller-1", "--off")
+	c.Assert(err, gc.IsNil)
+	c.Assert(cmdtesting.Stdout(context), gc.Matches, `(?s)name: controller-1
+.*status:
+  status: available
+.*`)
+}
+
+func (s *setControllerMaintenanceSuite) TestSetControllerMaintenance(c *gc.C) {
+	s.AddController(c, "controller-1", s.APIInfo(c))
+
+	// bob is not superuser
+	bClient := s.SetupCLIAccess(c, "bob")
+	_, err := cmdtesting.RunCommand(c, cmd.NewSetControllerMaintenanceCommandForTesting(s.ClientStore(), bClient), "controller-1")
+	c.Assert(err, gc.ErrorMatches, `unauthorized \(unauthorized access\)`)
+}
+
+func (s *setControllerMaintenanceSuite) TestSetControllerMaintenanceInvalidArguments(c *gc.C) {
+	bClient := s.SetupCLIAccess(c, "alice")
+	_, err := cmdtesting.RunCommand(c, cmd.NewSetControllerMaintenanceCommandForTesting(s.ClientStore(), bClient), "controller-1", "--until", "tomorrow")
+	c.Assert(err, gc.ErrorMatches, `invalid --until time`)
+
+	_, err = cmdtesting.RunCommand(c, cmd.NewSetControllerMaintenanceCommandForTesting(s.ClientStore(), bClient), "controller-1", "--off", "--message", "upgrading")
+	c.Assert(err, gc.ErrorMatches, `--off cannot be used with other maintenance options`)
+}
@@ -0,0 +1,117 @@
+// Copyright 2024 Canonical.
+
+package cmd
+
+import (
+	"github.com/juju/cmd/v3"
+	"github.com/juju/gnuflag"
+	jujuapi "github.com/juju/juju/api"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/pkg/api"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const (
+	transferModelOwnershipCommandDoc = `
+The transfer-model-ownership command makes another user the owner of a model.
+
+The new owner is given administrator access to the model and the previous
+owner's administrator access is removed. Only administrators of the model, or
+of the controller running it, may transfer its ownership.
+
+The --cloud-credential option switches the model to use the specified cloud
+credential, which must belong to the new owner, as part of the transfer. If
+the credential cannot be changed the model is left with its previous owner.
+`
+	transferModelOwnershipCommandExample = `
+    jimmctl transfer-model-ownership ac30d6ae-0bed-4398-bba7-75d49e39f189 bob@canonical.com
+    jimmctl transfer-model-ownership ac30d6ae-0bed-4398-bba7-75d49e39f189 bob@canonical.com --cloud-credential aws/bob@canonical.com/cred
+`
+)
+
+// NewTransferModelOwnershipCommand returns a command to transfer the
+// ownership of a model.
+func NewTransferModelOwnershipCommand() cmd.Command {
+	cmd := &transferModelOwnershipCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// transferModelOwnershipCommand transfers the ownership of a model.
+type transferModelOwnershipCommand struct {
+	modelcmd.ControllerCommandBase
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	cloudCredential string
+	req             apiparams.TransferModelOwnershipRequest
+}
+
+// Info implements the cmd.Command interface.
+func (c *transferModelOwnershipCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "transfer-model-ownership",
+		Args:     "<model uuid> <new owner>",
+		Purpose:  "Transfer the ownership of a model to another user",
+		Doc:      transferModelOwnershipCommandDoc,
+		Examples: transferModelOwnershipCommandExample,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *transferModelOwnershipCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	f.StringVar(&c.cloudCredential, "cloud-credential", "", "switch the model to the specified cloud credential (<cloud>/<owner>/<name>)")
+}
+
+// Init implements the cmd.Command interface.
+func (c *transferModelOwnershipCommand) Init(args []string) error {
+	switch len(args) {
+	default:
+		return errors.E("too many args")
+	case 0:
+		return errors.E("model uuid not specified")
+	case 1:
+		return errors.E("new owner not specified")
+	case 2:
+	}
+
+	if !names.IsValidModel(args[0]) {
+		return errors.E("invalid model uuid")
+	}
+	c.req.ModelTag = names.NewModelTag(args[0]).String()
+	c.req.NewOwner = args[1]
+	if c.cloudCredential != "" {
+		if !names.IsValidCloudCredential(c.cloudCredential) {
+			return errors.E("invalid cloud credential")
+		}
+		c.req.CloudCredentialTag = names.NewCloudCredentialTag(c.cloudCredential).String()
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *transferModelOwnershipCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	if err := client.TransferModelOwnership(&c.req); err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
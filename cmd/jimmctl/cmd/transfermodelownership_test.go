@@ -0,0 +1,56 @@
+// Copyright 2024 Canonical.
+
+package cmd_test
+
+import (
+	"context"
+
+	"github.com/juju/cmd/v3/cmdtesting"
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+	gc "gopkg.in/check.v1"
+
+	"github.com/canonical/jimm/v3/cmd/jimmctl/cmd"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/testutils/cmdtest"
+	"github.com/canonical/jimm/v3/internal/testutils/jimmtest"
+)
+
+type transferModelOwnershipSuite struct {
+	cmdtest.JimmCmdSuite
+}
+
+var _ = gc.Suite(&transferModelOwnershipSuite{})
+
+func (s *transferModelOwnershipSuite) TestTransferModelOwnership(c *gc.C) {
+	s.AddController(c, "controller-1", s.APIInfo(c))
+
+	cct := names.NewCloudCredentialTag(jimmtest.TestCloudName + "/charlie@canonical.com/cred")
+	s.UpdateCloudCredential(c, cct, jujuparams.CloudCredential{AuthType: "empty", Attributes: map[string]string{"key": "value"}})
+	mt := s.AddModel(c, names.NewUserTag("charlie@canonical.com"), "model-2", names.NewCloudTag(jimmtest.TestCloudName), jimmtest.TestCloudRegionName, cct)
+
+	// bob is not an administrator of the model
+	bClient := s.SetupCLIAccess(c, "bob")
+	_, err := cmdtesting.RunCommand(c, cmd.NewTransferModelOwnershipCommandForTesting(s.ClientStore(), bClient), mt.Id(), "bob@canonical.com")
+	c.Check(err, gc.ErrorMatches, `unauthorized`)
+
+	// alice is superuser
+	bClient = s.SetupCLIAccess(c, "alice")
+	_, err = cmdtesting.RunCommand(c, cmd.NewTransferModelOwnershipCommandForTesting(s.ClientStore(), bClient), mt.Id())
+	c.Check(err, gc.ErrorMatches, `new owner not specified`)
+
+	_, err = cmdtesting.RunCommand(c, cmd.NewTransferModelOwnershipCommandForTesting(s.ClientStore(), bClient), mt.Id(), "bob@canonical.com", "--cloud-credential", "not-a-credential")
+	c.Check(err, gc.ErrorMatches, `invalid cloud credential`)
+
+	_, err = cmdtesting.RunCommand(c, cmd.NewTransferModelOwnershipCommandForTesting(s.ClientStore(), bClient), mt.Id(), "bob@canonical.com")
+	c.Assert(err, gc.IsNil)
+
+	var model dbmodel.Model
+	model.SetTag(mt)
+	err = s.JIMM.Database.GetModel(context.Background(), &model)
+	c.Assert(err, gc.IsNil)
+	c.Check(model.OwnerIdentityName, gc.Equals, "bob@canonical.com")
+
+	_, err = cmdtesting.RunCommand(c, cmd.NewTransferModelOwnershipCommandForTesting(s.ClientStore(), bClient), mt.Id(), "bob@canonical.com")
+	c.Check(err, gc.ErrorMatches, `model is already owned by bob@canonical.com`)
+}
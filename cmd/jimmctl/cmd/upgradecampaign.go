@@ -0,0 +1,603 @@
+// Copyright 2024 Canonical.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/gosuri/uitable"
+	"github.com/juju/cmd/v3"
+	jujucmdv3 "github.com/juju/cmd/v3"
+	"github.com/juju/gnuflag"
+	jujuapi "github.com/juju/juju/api"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/pkg/api"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const (
+	upgradeCampaignDoc = `
+The upgrade-campaign command enables management of model upgrade
+campaigns in jimm.
+
+A campaign upgrades the agents of every model matching its selectors.
+Once created, a campaign validates each model, checking the model's agent
+version and performing a dry run of the upgrade. A validated campaign is
+then started, upgrading models with bounded concurrency. A campaign is
+paused when the number of failed upgrades reaches its failure threshold.
+`
+
+	createUpgradeCampaignDoc = `
+The create command creates a model upgrade campaign. Models are selected
+by the controller hosting them, by a label selector and by their current
+agent version; a model must match every given selector. If no target
+version is given each model's controller chooses the version to upgrade
+to.
+`
+	createUpgradeCampaignExample = `
+    jimmctl upgrade-campaign create to-3.5.1 --target-version 3.5.1 --agent-version 3.4.0
+    jimmctl upgrade-campaign create prod --controller controller-1 --selector env=prod --concurrency 5 --failure-threshold 3
+`
+	startUpgradeCampaignDoc = `
+The start command starts upgrading the models in a validated campaign, or
+resumes a paused campaign.
+`
+	startUpgradeCampaignExample = `
+    jimmctl upgrade-campaign start to-3.5.1
+`
+	pauseUpgradeCampaignDoc = `
+The pause command pauses a running campaign. Upgrades that have already
+been started are allowed to finish.
+`
+	pauseUpgradeCampaignExample = `
+    jimmctl upgrade-campaign pause to-3.5.1
+`
+	showUpgradeCampaignDoc = `
+The show command displays a campaign along with the state of each of its
+models.
+`
+	showUpgradeCampaignExample = `
+    jimmctl upgrade-campaign show to-3.5.1
+    jimmctl upgrade-campaign show to-3.5.1 --format tabular
+`
+	listUpgradeCampaignsDoc = `
+The list command lists all the model upgrade campaigns in jimm along with
+their progress.
+`
+	listUpgradeCampaignsExample = `
+    jimmctl upgrade-campaign list
+    jimmctl upgrade-campaign list --format tabular
+`
+	removeUpgradeCampaignDoc = `
+The remove command removes a campaign. A running campaign must be paused
+before it can be removed.
+`
+	removeUpgradeCampaignExample = `
+    jimmctl upgrade-campaign remove to-3.5.1
+`
+)
+
+// NewUpgradeCampaignCommand returns a command for model upgrade campaign
+// management.
+func NewUpgradeCampaignCommand() *jujucmdv3.SuperCommand {
+	cmd := jujucmd.NewSuperCommand(jujucmdv3.SuperCommandParams{
+		Name:    "upgrade-campaign",
+		Doc:     upgradeCampaignDoc,
+		Purpose: "Model upgrade campaign management.",
+	})
+	cmd.Register(newCreateUpgradeCampaignCommand())
+	cmd.Register(newStartUpgradeCampaignCommand())
+	cmd.Register(newPauseUpgradeCampaignCommand())
+	cmd.Register(newShowUpgradeCampaignCommand())
+	cmd.Register(newListUpgradeCampaignsCommand())
+	cmd.Register(newRemoveUpgradeCampaignCommand())
+
+	return cmd
+}
+
+// newCreateUpgradeCampaignCommand returns a command to create an upgrade
+// campaign.
+func newCreateUpgradeCampaignCommand() cmd.Command {
+	cmd := &createUpgradeCampaignCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// createUpgradeCampaignCommand creates an upgrade campaign.
+type createUpgradeCampaignCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	req apiparams.CreateUpgradeCampaignRequest
+}
+
+// Info implements the cmd.Command interface.
+func (c *createUpgradeCampaignCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "create",
+		Args:     "<name>",
+		Purpose:  "Create a model upgrade campaign.",
+		Doc:      createUpgradeCampaignDoc,
+		Examples: createUpgradeCampaignExample,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *createUpgradeCampaignCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml": cmd.FormatYaml,
+		"json": cmd.FormatJson,
+	})
+	f.StringVar(&c.req.TargetVersion, "target-version", "", "agent version to upgrade models to")
+	f.StringVar(&c.req.AgentStream, "agent-stream", "", "agent stream to upgrade from")
+	f.StringVar(&c.req.Controller, "controller", "", "only upgrade models hosted on the named controller")
+	f.StringVar(&c.req.LabelSelector, "selector", "", "only upgrade models whose labels match the selector")
+	f.StringVar(&c.req.AgentVersion, "agent-version", "", "only upgrade models running the given agent version")
+	f.IntVar(&c.req.Concurrency, "concurrency", 1, "maximum number of models upgraded at the same time")
+	f.IntVar(&c.req.FailureThreshold, "failure-threshold", 1, "number of failed upgrades after which the campaign is paused, 0 to never pause")
+}
+
+// Init implements the cmd.Command interface.
+func (c *createUpgradeCampaignCommand) Init(args []string) error {
+	if len(args) < 1 {
+		return errors.E("campaign name must be specified")
+	}
+	if len(args) > 1 {
+		return errors.E("too many args")
+	}
+	c.req.Name = args[0]
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *createUpgradeCampaignCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	resp, err := client.CreateUpgradeCampaign(&c.req)
+	if err != nil {
+		return errors.E(err)
+	}
+
+	err = c.out.Write(ctxt, resp)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
+
+// newStartUpgradeCampaignCommand returns a command to start an upgrade
+// campaign.
+func newStartUpgradeCampaignCommand() cmd.Command {
+	cmd := &startUpgradeCampaignCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// startUpgradeCampaignCommand starts, or resumes, an upgrade campaign.
+type startUpgradeCampaignCommand struct {
+	modelcmd.ControllerCommandBase
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	req apiparams.UpgradeCampaignRequest
+}
+
+// Info implements the cmd.Command interface.
+func (c *startUpgradeCampaignCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "start",
+		Args:     "<name>",
+		Purpose:  "Start, or resume, a model upgrade campaign.",
+		Doc:      startUpgradeCampaignDoc,
+		Examples: startUpgradeCampaignExample,
+	})
+}
+
+// Init implements the cmd.Command interface.
+func (c *startUpgradeCampaignCommand) Init(args []string) error {
+	if len(args) < 1 {
+		return errors.E("campaign name must be specified")
+	}
+	if len(args) > 1 {
+		return errors.E("too many args")
+	}
+	c.req.Name = args[0]
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *startUpgradeCampaignCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	if err := client.StartUpgradeCampaign(&c.req); err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
+
+// newPauseUpgradeCampaignCommand returns a command to pause an upgrade
+// campaign.
+func newPauseUpgradeCampaignCommand() cmd.Command {
+	cmd := &pauseUpgradeCampaignCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// pauseUpgradeCampaignCommand pauses a running upgrade campaign.
+type pauseUpgradeCampaignCommand struct {
+	modelcmd.ControllerCommandBase
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	req apiparams.UpgradeCampaignRequest
+}
+
+// Info implements the cmd.Command interface.
+func (c *pauseUpgradeCampaignCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "pause",
+		Args:     "<name>",
+		Purpose:  "Pause a model upgrade campaign.",
+		Doc:      pauseUpgradeCampaignDoc,
+		Examples: pauseUpgradeCampaignExample,
+	})
+}
+
+// Init implements the cmd.Command interface.
+func (c *pauseUpgradeCampaignCommand) Init(args []string) error {
+	if len(args) < 1 {
+		return errors.E("campaign name must be specified")
+	}
+	if len(args) > 1 {
+		return errors.E("too many args")
+	}
+	c.req.Name = args[0]
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *pauseUpgradeCampaignCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	if err := client.PauseUpgradeCampaign(&c.req); err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
+
+// newShowUpgradeCampaignCommand returns a command to show an upgrade
+// campaign.
+func newShowUpgradeCampaignCommand() cmd.Command {
+	cmd := &showUpgradeCampaignCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// showUpgradeCampaignCommand displays an upgrade campaign.
+type showUpgradeCampaignCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	req apiparams.UpgradeCampaignRequest
+}
+
+// Info implements the cmd.Command interface.
+func (c *showUpgradeCampaignCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "show",
+		Args:     "<name>",
+		Purpose:  "Show a model upgrade campaign.",
+		Doc:      showUpgradeCampaignDoc,
+		Examples: showUpgradeCampaignExample,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *showUpgradeCampaignCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatUpgradeCampaignTabular,
+	})
+}
+
+// Init implements the cmd.Command interface.
+func (c *showUpgradeCampaignCommand) Init(args []string) error {
+	if len(args) < 1 {
+		return errors.E("campaign name must be specified")
+	}
+	if len(args) > 1 {
+		return errors.E("too many args")
+	}
+	c.req.Name = args[0]
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *showUpgradeCampaignCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	resp, err := client.GetUpgradeCampaign(&c.req)
+	if err != nil {
+		return errors.E(err)
+	}
+
+	err = c.out.Write(ctxt, resp)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
+
+// formatUpgradeCampaignTabular formats an upgrade campaign, and the state
+// of its models, as tables.
+func formatUpgradeCampaignTabular(writer io.Writer, value interface{}) error {
+	campaign, ok := value.(*apiparams.UpgradeCampaign)
+	if !ok {
+		return errors.E(fmt.Sprintf("expected value of type %T, got %T", campaign, value))
+	}
+
+	table := uitable.New()
+	table.MaxColWidth = 80
+	table.Wrap = true
+	addUpgradeCampaignHeader(table)
+	addUpgradeCampaignRow(table, campaign)
+	fmt.Fprintln(writer, table)
+
+	if len(campaign.Models) == 0 {
+		return nil
+	}
+	models := uitable.New()
+	models.MaxColWidth = 80
+	models.Wrap = true
+	models.AddRow("Model", "Owner", "Controller", "Status", "From", "To", "Error")
+	for _, m := range campaign.Models {
+		models.AddRow(m.ModelName, m.Owner, m.Controller, m.Status, m.FromVersion, m.ChosenVersion, m.Error)
+	}
+	fmt.Fprintln(writer)
+	fmt.Fprintln(writer, models)
+	return nil
+}
+
+// newListUpgradeCampaignsCommand returns a command to list upgrade
+// campaigns.
+func newListUpgradeCampaignsCommand() cmd.Command {
+	cmd := &listUpgradeCampaignsCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// listUpgradeCampaignsCommand lists all upgrade campaigns.
+type listUpgradeCampaignsCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+}
+
+// Info implements the cmd.Command interface.
+func (c *listUpgradeCampaignsCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "list",
+		Purpose:  "List all model upgrade campaigns.",
+		Doc:      listUpgradeCampaignsDoc,
+		Examples: listUpgradeCampaignsExample,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *listUpgradeCampaignsCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatUpgradeCampaignsTabular,
+	})
+}
+
+// Init implements the cmd.Command interface.
+func (c *listUpgradeCampaignsCommand) Init(args []string) error {
+	if len(args) > 0 {
+		return errors.E("too many args")
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *listUpgradeCampaignsCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	resp, err := client.ListUpgradeCampaigns()
+	if err != nil {
+		return errors.E(err)
+	}
+
+	err = c.out.Write(ctxt, resp)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
+
+// formatUpgradeCampaignsTabular formats a list of upgrade campaigns as a
+// table.
+func formatUpgradeCampaignsTabular(writer io.Writer, value interface{}) error {
+	resp, ok := value.(*apiparams.ListUpgradeCampaignsResponse)
+	if !ok {
+		return errors.E(fmt.Sprintf("expected value of type %T, got %T", resp, value))
+	}
+
+	table := uitable.New()
+	table.MaxColWidth = 80
+	table.Wrap = true
+	addUpgradeCampaignHeader(table)
+	for i := range resp.Campaigns {
+		addUpgradeCampaignRow(table, &resp.Campaigns[i])
+	}
+	fmt.Fprintln(writer, table)
+	return nil
+}
+
+// addUpgradeCampaignHeader adds the header row of a table of upgrade
+// campaigns.
+func addUpgradeCampaignHeader(table *uitable.Table) {
+	table.AddRow("Name", "Target", "Status", "Failures", "Progress", "Message")
+}
+
+// addUpgradeCampaignRow adds a row describing the given campaign to a
+// table of upgrade campaigns.
+func addUpgradeCampaignRow(table *uitable.Table, c *apiparams.UpgradeCampaign) {
+	target := c.TargetVersion
+	if target == "" {
+		target = "-"
+	}
+	statuses := make([]string, 0, len(c.Progress))
+	for status := range c.Progress {
+		statuses = append(statuses, status)
+	}
+	sort.Strings(statuses)
+	progress := make([]string, len(statuses))
+	for i, status := range statuses {
+		progress[i] = fmt.Sprintf("%s:%d", status, c.Progress[status])
+	}
+	failures := fmt.Sprintf("%d", c.Failures)
+	if c.FailureThreshold > 0 {
+		failures = fmt.Sprintf("%d/%d", c.Failures, c.FailureThreshold)
+	}
+	table.AddRow(c.Name, target, c.Status, failures, strings.Join(progress, ","), c.Message)
+}
+
+// newRemoveUpgradeCampaignCommand returns a command to remove an upgrade
+// campaign.
+func newRemoveUpgradeCampaignCommand() cmd.Command {
+	cmd := &removeUpgradeCampaignCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// removeUpgradeCampaignCommand removes an upgrade campaign.
+type removeUpgradeCampaignCommand struct {
+	modelcmd.ControllerCommandBase
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	req apiparams.UpgradeCampaignRequest
+}
+
+// Info implements the cmd.Command interface.
+func (c *removeUpgradeCampaignCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "remove",
+		Args:     "<name>",
+		Purpose:  "Remove a model upgrade campaign.",
+		Doc:      removeUpgradeCampaignDoc,
+		Examples: removeUpgradeCampaignExample,
+	})
+}
+
+// Init implements the cmd.Command interface.
+func (c *removeUpgradeCampaignCommand) Init(args []string) error {
+	if len(args) < 1 {
+		return errors.E("campaign name must be specified")
+	}
+	if len(args) > 1 {
+		return errors.E("too many args")
+	}
+	c.req.Name = args[0]
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *removeUpgradeCampaignCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	if err := client.RemoveUpgradeCampaign(&c.req); err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+// Copyright 2024 Canonical.
+
+package cmd_test
+
+import (
+	"bytes"
+
+	"github.com/juju/cmd/v3/cmdtesting"
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+	gc "gopkg.in/check.v1"
+
+	"github.com/canonical/jimm/v3/cmd/jimmctl/cmd"
+	"github.com/canonical/jimm/v3/internal/testutils/cmdtest"
+	"github.com/canonical/jimm/v3/internal/testutils/jimmtest"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+type upgradeCampaignSuite struct {
+	cmdtest.JimmCmdSuite
+}
+
+var _ = gc.Suite(&upgradeCampaignSuite{})
+
+func (s *upgradeCampaignSuite) TestUpgradeCampaign(c *gc.C) {
+	s.AddController(c, "controller-1", s.APIInfo(c))
+
+	cct := names.NewCloudCredentialTag(jimmtest.TestCloudName + "/charlie@canonical.com/cred")
+	s.UpdateCloudCredential(c, cct, jujuparams.CloudCredential{AuthType: "empty", Attributes: map[string]string{"key": "value"}})
+	s.AddModel(c, names.NewUserTag("charlie@canonical.com"), "model-2", names.NewCloudTag(jimmtest.TestCloudName), jimmtest.TestCloudRegionName, cct)
+
+	// bob is not superuser
+	bClient := s.SetupCLIAccess(c, "bob")
+	_, err := cmdtesting.RunCommand(c, cmd.NewCreateUpgradeCampaignCommandForTesting(s.ClientStore(), bClient), "to-3.5.1")
+	c.Check(err, gc.ErrorMatches, `unauthorized`)
+
+	// alice is superuser
+	bClient = s.SetupCLIAccess(c, "alice")
+	_, err = cmdtesting.RunCommand(c, cmd.NewCreateUpgradeCampaignCommandForTesting(s.ClientStore(), bClient))
+	c.Check(err, gc.ErrorMatches, `campaign name must be specified`)
+
+	cmdCtx, err := cmdtesting.RunCommand(c, cmd.NewCreateUpgradeCampaignCommandForTesting(s.ClientStore(), bClient), "to-3.5.1", "--target-version", "3.5.1", "--controller", "controller-1", "--concurrency", "2")
+	c.Assert(err, gc.IsNil)
+	c.Check(cmdtesting.Stdout(cmdCtx), gc.Matches, `(?s)name: to-3.5.1\ntarget-version: 3.5.1\ncontroller: controller-1\nconcurrency: 2\nfailure-threshold: 1\nstatus: validating\n.*progress:\n  pending: 1\n`)
+
+	_, err = cmdtesting.RunCommand(c, cmd.NewCreateUpgradeCampaignCommandForTesting(s.ClientStore(), bClient), "to-3.5.1")
+	c.Check(err, gc.ErrorMatches, `upgrade campaign already exists`)
+
+	_, err = cmdtesting.RunCommand(c, cmd.NewStartUpgradeCampaignCommandForTesting(s.ClientStore(), bClient), "to-3.5.1")
+	c.Check(err, gc.ErrorMatches, `cannot start validating upgrade campaign`)
+
+	_, err = cmdtesting.RunCommand(c, cmd.NewPauseUpgradeCampaignCommandForTesting(s.ClientStore(), bClient), "to-3.5.1")
+	c.Check(err, gc.ErrorMatches, `cannot pause validating upgrade campaign`)
+
+	cmdCtx, err = cmdtesting.RunCommand(c, cmd.NewShowUpgradeCampaignCommandForTesting(s.ClientStore(), bClient), "to-3.5.1")
+	c.Assert(err, gc.IsNil)
+	c.Check(cmdtesting.Stdout(cmdCtx), gc.Matches, `(?s).*models:\n- model-uuid: .*\n  model-name: model-2\n  owner: charlie@canonical.com\n  controller: controller-1\n  status: pending\n.*`)
+
+	cmdCtx, err = cmdtesting.RunCommand(c, cmd.NewListUpgradeCampaignsCommandForTesting(s.ClientStore(), bClient))
+	c.Assert(err, gc.IsNil)
+	c.Check(cmdtesting.Stdout(cmdCtx), gc.Matches, `(?s)campaigns:\n- name: to-3.5.1\n.*`)
+
+	_, err = cmdtesting.RunCommand(c, cmd.NewRemoveUpgradeCampaignCommandForTesting(s.ClientStore(), bClient), "to-3.5.1")
+	c.Assert(err, gc.IsNil)
+
+	_, err = cmdtesting.RunCommand(c, cmd.NewShowUpgradeCampaignCommandForTesting(s.ClientStore(), bClient), "to-3.5.1")
+	c.Check(err, gc.ErrorMatches, `upgrade campaign not found`)
+}
+
+func (s *upgradeCampaignSuite) TestFormatUpgradeCampaignTabular(c *gc.C) {
+	campaign := apiparams.UpgradeCampaign{
+		Name:             "to-3.5.1",
+		TargetVersion:    "3.5.1",
+		Status:           "paused",
+		Failures:         1,
+		FailureThreshold: 1,
+		Message:          "paused after 1 failed upgrades",
+		Progress:         map[string]int{"upgraded": 1, "failed": 1},
+		Models: []apiparams.UpgradeCampaignModel{{
+			ModelName:     "model-1",
+			Owner:         "alice@canonical.com",
+			Controller:    "controller-1",
+			Status:        "upgraded",
+			FromVersion:   "3.4.0",
+			ChosenVersion: "3.5.1",
+		}, {
+			ModelName:     "model-2",
+			Owner:         "alice@canonical.com",
+			Controller:    "controller-1",
+			Status:        "failed",
+			FromVersion:   "3.4.0",
+			ChosenVersion: "3.5.1",
+			Error:         "upgrade in progress",
+		}},
+	}
+
+	var buf bytes.Buffer
+	err := cmd.FormatUpgradeCampaignTabular(&buf, &campaign)
+	c.Assert(err, gc.IsNil)
+	c.Check(buf.String(), gc.Equals, "Name    \tTarget\tStatus\tFailures\tProgress           \tMessage                       \n"+
+		"to-3.5.1\t3.5.1 \tpaused\t1/1     \tfailed:1,upgraded:1\tpaused after 1 failed upgrades\n"+
+		"\n"+
+		"Model  \tOwner              \tController  \tStatus  \tFrom \tTo   \tError              \n"+
+		"model-1\talice@canonical.com\tcontroller-1\tupgraded\t3.4.0\t3.5.1\t                   \n"+
+		"model-2\talice@canonical.com\tcontroller-1\tfailed  \t3.4.0\t3.5.1\tupgrade in progress\n")
+
+	buf.Reset()
+	err = cmd.FormatUpgradeCampaignsTabular(&buf, &apiparams.ListUpgradeCampaignsResponse{
+		Campaigns: []apiparams.UpgradeCampaign{campaign, {
+			Name:     "any",
+			Status:   "validating",
+			Progress: map[string]int{"pending": 3},
+		}},
+	})
+	c.Assert(err, gc.IsNil)
+	c.Check(buf.String(), gc.Equals, "Name    \tTarget\tStatus    \tFailures\tProgress           \tMessage                       \n"+
+		"to-3.5.1\t3.5.1 \tpaused    \t1/1     \tfailed:1,upgraded:1\tpaused after 1 failed upgrades\n"+
+		"any     \t-     \tvalidating\t0       \tpending:3          \t                              \n")
+
+	err = cmd.FormatUpgradeCampaignTabular(&buf, "not a campaign")
+	c.Check(err, gc.ErrorMatches, `expected value of type .*`)
+}
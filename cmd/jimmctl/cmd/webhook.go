@@ -0,0 +1,502 @@
+// Copyright 2024 Canonical.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/gosuri/uitable"
+	"github.com/juju/cmd/v3"
+	jujucmdv3 "github.com/juju/cmd/v3"
+	"github.com/juju/gnuflag"
+	jujuapi "github.com/juju/juju/api"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/pkg/api"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const (
+	webhookDoc = `
+The webhook command enables management of the webhooks that jimm delivers
+model lifecycle events to.
+
+The events are model-created, model-destroyed, model-dead, model-migrated,
+model-error and model-expiring. Each event is posted as JSON and is signed
+with the subscription's secret: the X-JIMM-Signature header holds "sha256="
+followed by the hex encoded HMAC-SHA256 of the request body. Events are
+delivered at least once, receivers should use the event ID to discard
+duplicates. Events that cannot be delivered after repeated attempts are dead
+lettered.
+`
+
+	addWebhookDoc = `
+The add command subscribes a webhook to model lifecycle events. Events may
+be restricted by type and to models matching a controller, owner and label
+selector. If no secret is given one is generated and displayed; it cannot
+be retrieved later.
+`
+	addWebhookExample = `
+    jimmctl webhook add chat-ops https://chat.example.com/hooks/jimm --event model-created --event model-error
+    jimmctl webhook add cmdb https://cmdb.example.com/jimm --selector env=prod --secret mysecret
+`
+	listWebhooksDoc = `
+The list command lists all the webhook subscriptions in jimm.
+`
+	listWebhooksExample = `
+    jimmctl webhook list
+    jimmctl webhook list --format tabular
+`
+	removeWebhookDoc = `
+The remove command removes a webhook subscription along with any events
+waiting to be delivered to it.
+`
+	removeWebhookExample = `
+    jimmctl webhook remove chat-ops
+`
+	listWebhookDeadLettersDoc = `
+The dead-letters command lists the events that could not be delivered to
+webhooks.
+`
+	listWebhookDeadLettersExample = `
+    jimmctl webhook dead-letters
+    jimmctl webhook dead-letters --subscription chat-ops
+`
+	retryWebhookDeadLetterDoc = `
+The retry command queues a dead lettered event to be delivered again.
+`
+	retryWebhookDeadLetterExample = `
+    jimmctl webhook retry 42
+`
+)
+
+// NewWebhookCommand returns a command for webhook subscription management.
+func NewWebhookCommand() *jujucmdv3.SuperCommand {
+	cmd := jujucmd.NewSuperCommand(jujucmdv3.SuperCommandParams{
+		Name:    "webhook",
+		Doc:     webhookDoc,
+		Purpose: "Model lifecycle webhook management.",
+	})
+	cmd.Register(newAddWebhookCommand())
+	cmd.Register(newListWebhooksCommand())
+	cmd.Register(newRemoveWebhookCommand())
+	cmd.Register(newListWebhookDeadLettersCommand())
+	cmd.Register(newRetryWebhookDeadLetterCommand())
+
+	return cmd
+}
+
+// stringsFlag is a flag that may be given multiple times, collecting its
+// values.
+type stringsFlag []string
+
+// String implements gnuflag.Value.
+func (f *stringsFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+// Set implements gnuflag.Value.
+func (f *stringsFlag) Set(s string) error {
+	*f = append(*f, s)
+	return nil
+}
+
+// newAddWebhookCommand returns a command to add a webhook subscription.
+func newAddWebhookCommand() cmd.Command {
+	cmd := &addWebhookCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// addWebhookCommand adds a webhook subscription.
+type addWebhookCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	events stringsFlag
+	req    apiparams.AddWebhookSubscriptionRequest
+}
+
+// Info implements the cmd.Command interface.
+func (c *addWebhookCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "add",
+		Args:     "<name> <url>",
+		Purpose:  "Subscribe a webhook to model lifecycle events.",
+		Doc:      addWebhookDoc,
+		Examples: addWebhookExample,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *addWebhookCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml": cmd.FormatYaml,
+		"json": cmd.FormatJson,
+	})
+	f.Var(&c.events, "event", "type of event to deliver, may be repeated (default all events)")
+	f.StringVar(&c.req.Secret, "secret", "", "secret used to sign events (default generated)")
+	f.StringVar(&c.req.Controller, "controller", "", "only deliver events for models hosted on the named controller")
+	f.StringVar(&c.req.Owner, "owner", "", "only deliver events for models owned by the given user")
+	f.StringVar(&c.req.LabelSelector, "selector", "", "only deliver events for models whose labels match the selector")
+}
+
+// Init implements the cmd.Command interface.
+func (c *addWebhookCommand) Init(args []string) error {
+	switch len(args) {
+	default:
+		return errors.E("too many args")
+	case 0:
+		return errors.E("subscription name must be specified")
+	case 1:
+		return errors.E("webhook url must be specified")
+	case 2:
+	}
+	c.req.Name = args[0]
+	c.req.URL = args[1]
+	c.req.EventTypes = c.events
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *addWebhookCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	resp, err := client.AddWebhookSubscription(&c.req)
+	if err != nil {
+		return errors.E(err)
+	}
+
+	err = c.out.Write(ctxt, resp)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
+
+// newListWebhooksCommand returns a command to list webhook subscriptions.
+func newListWebhooksCommand() cmd.Command {
+	cmd := &listWebhooksCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// listWebhooksCommand lists all webhook subscriptions.
+type listWebhooksCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+}
+
+// Info implements the cmd.Command interface.
+func (c *listWebhooksCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "list",
+		Purpose:  "List all webhook subscriptions.",
+		Doc:      listWebhooksDoc,
+		Examples: listWebhooksExample,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *listWebhooksCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatWebhooksTabular,
+	})
+}
+
+// Init implements the cmd.Command interface.
+func (c *listWebhooksCommand) Init(args []string) error {
+	if len(args) > 0 {
+		return errors.E("too many args")
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *listWebhooksCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	resp, err := client.ListWebhookSubscriptions()
+	if err != nil {
+		return errors.E(err)
+	}
+
+	err = c.out.Write(ctxt, resp)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
+
+// formatWebhooksTabular formats a list of webhook subscriptions as a
+// table.
+func formatWebhooksTabular(writer io.Writer, value interface{}) error {
+	resp, ok := value.(*apiparams.ListWebhookSubscriptionsResponse)
+	if !ok {
+		return errors.E(fmt.Sprintf("expected value of type %T, got %T", resp, value))
+	}
+
+	table := uitable.New()
+	table.MaxColWidth = 80
+	table.Wrap = true
+	table.AddRow("Name", "URL", "Events", "Controller", "Owner", "Selector")
+	for _, s := range resp.Subscriptions {
+		events := strings.Join(s.EventTypes, ",")
+		if events == "" {
+			events = "all"
+		}
+		table.AddRow(s.Name, s.URL, events, orDash(s.Controller), orDash(s.Owner), orDash(s.LabelSelector))
+	}
+	fmt.Fprintln(writer, table)
+	return nil
+}
+
+// orDash returns s, or "-" if s is empty.
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// newRemoveWebhookCommand returns a command to remove a webhook
+// subscription.
+func newRemoveWebhookCommand() cmd.Command {
+	cmd := &removeWebhookCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// removeWebhookCommand removes a webhook subscription.
+type removeWebhookCommand struct {
+	modelcmd.ControllerCommandBase
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	req apiparams.RemoveWebhookSubscriptionRequest
+}
+
+// Info implements the cmd.Command interface.
+func (c *removeWebhookCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "remove",
+		Args:     "<name>",
+		Purpose:  "Remove a webhook subscription.",
+		Doc:      removeWebhookDoc,
+		Examples: removeWebhookExample,
+	})
+}
+
+// Init implements the cmd.Command interface.
+func (c *removeWebhookCommand) Init(args []string) error {
+	if len(args) < 1 {
+		return errors.E("subscription name must be specified")
+	}
+	if len(args) > 1 {
+		return errors.E("too many args")
+	}
+	c.req.Name = args[0]
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *removeWebhookCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	if err := client.RemoveWebhookSubscription(&c.req); err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
+
+// newListWebhookDeadLettersCommand returns a command to list webhook
+// dead letters.
+func newListWebhookDeadLettersCommand() cmd.Command {
+	cmd := &listWebhookDeadLettersCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// listWebhookDeadLettersCommand lists the events that could not be
+// delivered to webhooks.
+type listWebhookDeadLettersCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	req apiparams.ListWebhookDeadLettersRequest
+}
+
+// Info implements the cmd.Command interface.
+func (c *listWebhookDeadLettersCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "dead-letters",
+		Purpose:  "List events that could not be delivered to webhooks.",
+		Doc:      listWebhookDeadLettersDoc,
+		Examples: listWebhookDeadLettersExample,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *listWebhookDeadLettersCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml": cmd.FormatYaml,
+		"json": cmd.FormatJson,
+	})
+	f.StringVar(&c.req.Subscription, "subscription", "", "only list the dead letters of the named subscription")
+}
+
+// Init implements the cmd.Command interface.
+func (c *listWebhookDeadLettersCommand) Init(args []string) error {
+	if len(args) > 0 {
+		return errors.E("too many args")
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *listWebhookDeadLettersCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	resp, err := client.ListWebhookDeadLetters(&c.req)
+	if err != nil {
+		return errors.E(err)
+	}
+
+	err = c.out.Write(ctxt, resp)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
+
+// newRetryWebhookDeadLetterCommand returns a command to retry the
+// delivery of a webhook dead letter.
+func newRetryWebhookDeadLetterCommand() cmd.Command {
+	cmd := &retryWebhookDeadLetterCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// retryWebhookDeadLetterCommand queues a dead lettered event to be
+// delivered again.
+type retryWebhookDeadLetterCommand struct {
+	modelcmd.ControllerCommandBase
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	req apiparams.RetryWebhookDeadLetterRequest
+}
+
+// Info implements the cmd.Command interface.
+func (c *retryWebhookDeadLetterCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "retry",
+		Args:     "<dead letter id>",
+		Purpose:  "Retry delivering an event that could not be delivered.",
+		Doc:      retryWebhookDeadLetterDoc,
+		Examples: retryWebhookDeadLetterExample,
+	})
+}
+
+// Init implements the cmd.Command interface.
+func (c *retryWebhookDeadLetterCommand) Init(args []string) error {
+	if len(args) < 1 {
+		return errors.E("dead letter id must be specified")
+	}
+	if len(args) > 1 {
+		return errors.E("too many args")
+	}
+	id, err := strconv.ParseUint(args[0], 10, 0)
+	if err != nil {
+		return errors.E("invalid dead letter id")
+	}
+	c.req.ID = uint(id)
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *retryWebhookDeadLetterCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	if err := client.RetryWebhookDeadLetter(&c.req); err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+// Copyright 2024 Canonical.
+
+package cmd_test
+
+import (
+	"bytes"
+
+	"github.com/juju/cmd/v3/cmdtesting"
+	gc "gopkg.in/check.v1"
+
+	"github.com/canonical/jimm/v3/cmd/jimmctl/cmd"
+	"github.com/canonical/jimm/v3/internal/testutils/cmdtest"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+type webhookSuite struct {
+	cmdtest.JimmCmdSuite
+}
+
+var _ = gc.Suite(&webhookSuite{})
+
+func (s *webhookSuite) TestWebhook(c *gc.C) {
+	// bob is not superuser
+	bClient := s.SetupCLIAccess(c, "bob")
+	_, err := cmdtesting.RunCommand(c, cmd.NewAddWebhookCommandForTesting(s.ClientStore(), bClient), "chat-ops", "https://chat.example.com/hook")
+	c.Check(err, gc.ErrorMatches, `unauthorized`)
+
+	// alice is superuser
+	bClient = s.SetupCLIAccess(c, "alice")
+	_, err = cmdtesting.RunCommand(c, cmd.NewAddWebhookCommandForTesting(s.ClientStore(), bClient), "chat-ops")
+	c.Check(err, gc.ErrorMatches, `webhook url must be specified`)
+
+	cmdCtx, err := cmdtesting.RunCommand(c, cmd.NewAddWebhookCommandForTesting(s.ClientStore(), bClient), "chat-ops", "https://chat.example.com/hook", "--event", "model-created", "--event", "model-error", "--secret", "secret")
+	c.Assert(err, gc.IsNil)
+	c.Check(cmdtesting.Stdout(cmdCtx), gc.Matches, `(?s)name: chat-ops\nurl: https://chat.example.com/hook\nsecret: secret\nevent-types:\n- model-created\n- model-error\n.*`)
+
+	_, err = cmdtesting.RunCommand(c, cmd.NewAddWebhookCommandForTesting(s.ClientStore(), bClient), "chat-ops", "https://chat.example.com/hook")
+	c.Check(err, gc.ErrorMatches, `webhook subscription already exists`)
+
+	cmdCtx, err = cmdtesting.RunCommand(c, cmd.NewListWebhooksCommandForTesting(s.ClientStore(), bClient))
+	c.Assert(err, gc.IsNil)
+	c.Check(cmdtesting.Stdout(cmdCtx), gc.Matches, `(?s)subscriptions:\n- name: chat-ops\n  url: https://chat.example.com/hook\n  event-types:\n.*`)
+
+	cmdCtx, err = cmdtesting.RunCommand(c, cmd.NewListWebhookDeadLettersCommandForTesting(s.ClientStore(), bClient), "--subscription", "chat-ops")
+	c.Assert(err, gc.IsNil)
+	c.Check(cmdtesting.Stdout(cmdCtx), gc.Equals, "dead-letters: []\n")
+
+	_, err = cmdtesting.RunCommand(c, cmd.NewRetryWebhookDeadLetterCommandForTesting(s.ClientStore(), bClient), "first")
+	c.Check(err, gc.ErrorMatches, `invalid dead letter id`)
+
+	_, err = cmdtesting.RunCommand(c, cmd.NewRetryWebhookDeadLetterCommandForTesting(s.ClientStore(), bClient), "42")
+	c.Check(err, gc.ErrorMatches, `webhook dead letter not found`)
+
+	_, err = cmdtesting.RunCommand(c, cmd.NewRemoveWebhookCommandForTesting(s.ClientStore(), bClient), "chat-ops")
+	c.Assert(err, gc.IsNil)
+
+	_, err = cmdtesting.RunCommand(c, cmd.NewRemoveWebhookCommandForTesting(s.ClientStore(), bClient), "chat-ops")
+	c.Check(err, gc.ErrorMatches, `webhook subscription not found`)
+}
+
+func (s *webhookSuite) TestFormatWebhooksTabular(c *gc.C) {
+	var buf bytes.Buffer
+	err := cmd.FormatWebhooksTabular(&buf, &apiparams.ListWebhookSubscriptionsResponse{
+		Subscriptions: []apiparams.WebhookSubscription{{
+			Name:       "chat-ops",
+			URL:        "https://chat.example.com/hook",
+			EventTypes: []string{"model-created", "model-error"},
+		}, {
+			Name:          "cmdb",
+			URL:           "https://cmdb.example.com/hook",
+			Controller:    "controller-1",
+			Owner:         "alice@canonical.com",
+			LabelSelector: "env=prod",
+		}},
+	})
+	c.Assert(err, gc.IsNil)
+	c.Check(buf.String(), gc.Equals, "Name    \tURL                          \tEvents                   \tController  \tOwner              \tSelector\n"+
+		"chat-ops\thttps://chat.example.com/hook\tmodel-created,model-error\t-           \t-                  \t-       \n"+
+		"cmdb    \thttps://cmdb.example.com/hook\tall                      \tcontroller-1\talice@canonical.com\tenv=prod\n")
+
+	err = cmd.FormatWebhooksTabular(&buf, "not a list")
+	c.Check(err, gc.ErrorMatches, `expected value of type .*`)
+}
@@ -31,6 +31,7 @@ func NewSuperCommand() *jujucmd.SuperCommand {
 	jimmcmd.Register(cmd.NewRemoveControllerCommand())
 	jimmcmd.Register(cmd.NewRevokeAuditLogAccessCommand())
 	jimmcmd.Register(cmd.NewSetControllerDeprecatedCommand())
+	jimmcmd.Register(cmd.NewSetControllerMaintenanceCommand())
 	jimmcmd.Register(cmd.NewUpdateMigratedModelCommand())
 	jimmcmd.Register(cmd.NewAddCloudToControllerCommand())
 	jimmcmd.Register(cmd.NewRemoveCloudFromControllerCommand())
@@ -38,6 +39,17 @@ func NewSuperCommand() *jujucmd.SuperCommand {
 	jimmcmd.Register(cmd.NewCrossModelQueryCommand())
 	jimmcmd.Register(cmd.NewPurgeLogsCommand())
 	jimmcmd.Register(cmd.NewMigrateModelCommand())
+	jimmcmd.Register(cmd.NewListOfferConsumersCommand())
+	jimmcmd.Register(cmd.NewModelQuotaCommand())
+	jimmcmd.Register(cmd.NewModelTemplateCommand())
+	jimmcmd.Register(cmd.NewModelPolicyCommand())
+	jimmcmd.Register(cmd.NewInventoryCommand())
+	jimmcmd.Register(cmd.NewReportCommand())
+	jimmcmd.Register(cmd.NewUpgradeCampaignCommand())
+	jimmcmd.Register(cmd.NewTransferModelOwnershipCommand())
+	jimmcmd.Register(cmd.NewWebhookCommand())
+	jimmcmd.Register(cmd.NewJobsCommand())
+	jimmcmd.Register(cmd.NewAlertsCommand())
 	return jimmcmd
 }
 
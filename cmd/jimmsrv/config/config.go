@@ -0,0 +1,626 @@
+// Copyright 2024 Canonical.
+
+// Package config contains the configuration file format used by jimmsrv.
+//
+// A configuration is read from a YAML file, any setting in the file can
+// be overridden by the environment variables that have always been used
+// to configure jimmsrv. Unknown settings in the file, and values that
+// cannot be parsed, are errors.
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/yaml.v3"
+
+	jimmsvc "github.com/canonical/jimm/v3/cmd/jimmsrv/service"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+// A Config is the configuration of a jimmsrv process.
+type Config struct {
+	// ListenAddress is the address the HTTP server listens on.
+	ListenAddress string `yaml:"listen-address"`
+
+	// UUID is the UUID of the JIMM controller.
+	UUID string `yaml:"uuid"`
+
+	// DNSName is the public DNS name of the JIMM service.
+	DNSName string `yaml:"dns-name"`
+
+	// DSN is the data source name of the JIMM database.
+	DSN string `yaml:"dsn"`
+
+	// Admins are the users that are JIMM administrators.
+	Admins []string `yaml:"admins"`
+
+	// ReplicaID identifies this replica in leader elections.
+	ReplicaID string `yaml:"replica-id"`
+
+	// LeaderLeaseDuration is the length of the leader's lease.
+	LeaderLeaseDuration time.Duration `yaml:"leader-lease-duration"`
+
+	// Log configures logging.
+	Log LogConfig `yaml:"log"`
+
+	// Vault configures the vault credential store.
+	Vault VaultConfig `yaml:"vault"`
+
+	// InsecureSecretStorage stores secrets in the JIMM database. This
+	// should not be used in production.
+	InsecureSecretStorage bool `yaml:"insecure-secret-storage"`
+
+	// OpenFGA configures the OpenFGA authorisation store.
+	OpenFGA OpenFGAConfig `yaml:"openfga"`
+
+	// Bakery holds the macaroon bakery key pair.
+	Bakery BakeryConfig `yaml:"bakery"`
+
+	// MacaroonExpiry is the expiry duration of authentication macaroons.
+	MacaroonExpiry time.Duration `yaml:"macaroon-expiry"`
+
+	// JWTExpiry is the expiry duration of the JWTs issued to
+	// controllers.
+	JWTExpiry time.Duration `yaml:"jwt-expiry"`
+
+	// OAuth configures the OAuth2.0 identity provider.
+	OAuth OAuthConfig `yaml:"oauth"`
+
+	// DashboardFinalRedirectURL is the dashboard URL to redirect to once
+	// a browser login completes.
+	DashboardFinalRedirectURL string `yaml:"dashboard-final-redirect-url"`
+
+	// CorsAllowedOrigins are the origins allowed to make cross-origin
+	// requests. This setting can be reloaded.
+	CorsAllowedOrigins []string `yaml:"cors-allowed-origins"`
+
+	// AuditLogRetentionPeriodInDays is the number of days audit log
+	// entries are kept, 0 keeps them forever. This setting can be
+	// reloaded.
+	AuditLogRetentionPeriodInDays int `yaml:"audit-log-retention-period-in-days"`
+
+	// ModelExpiry configures the expiry of ephemeral models.
+	ModelExpiry ModelExpiryConfig `yaml:"model-expiry"`
+
+	// Inventory configures the inventory collection.
+	Inventory InventoryConfig `yaml:"inventory"`
+
+	// PerModelMetrics enables the model health metrics labelled with
+	// each model.
+	PerModelMetrics bool `yaml:"per-model-metrics"`
+
+	// ControllerPool configures the pool of connections to controllers.
+	ControllerPool ControllerPoolConfig `yaml:"controller-pool"`
+
+	// Alerting configures the evaluation and delivery of alerts.
+	Alerting AlertingConfig `yaml:"alerting"`
+
+	// Tracing configures the export of traces.
+	Tracing TracingConfig `yaml:"tracing"`
+}
+
+// LogConfig configures logging.
+type LogConfig struct {
+	// Level is the log level. This setting can be reloaded.
+	Level string `yaml:"level"`
+
+	// DevMode enables the human readable development logger.
+	DevMode bool `yaml:"dev-mode"`
+
+	// SQL enables logging of database queries.
+	SQL bool `yaml:"sql"`
+}
+
+// VaultConfig configures the vault credential store.
+type VaultConfig struct {
+	Address      string `yaml:"address"`
+	Path         string `yaml:"path"`
+	RoleID       string `yaml:"role-id"`
+	RoleSecretID string `yaml:"role-secret-id"`
+}
+
+// OpenFGAConfig configures the OpenFGA authorisation store.
+type OpenFGAConfig struct {
+	Scheme    string        `yaml:"scheme"`
+	Host      string        `yaml:"host"`
+	Port      string        `yaml:"port"`
+	Store     string        `yaml:"store"`
+	AuthModel string        `yaml:"auth-model"`
+	Token     string        `yaml:"token"`
+	CacheSize int           `yaml:"cache-size"`
+	CacheTTL  time.Duration `yaml:"cache-ttl"`
+}
+
+// BakeryConfig holds the macaroon bakery key pair.
+type BakeryConfig struct {
+	PrivateKey string `yaml:"private-key"`
+	PublicKey  string `yaml:"public-key"`
+}
+
+// OAuthConfig configures the OAuth2.0 identity provider and the sessions
+// created by logging in.
+type OAuthConfig struct {
+	IssuerURL            string        `yaml:"issuer-url"`
+	ClientID             string        `yaml:"client-id"`
+	ClientSecret         string        `yaml:"client-secret"`
+	Scopes               []string      `yaml:"scopes"`
+	AccessTokenExpiry    time.Duration `yaml:"access-token-expiry"`
+	SessionCookieMaxAge  int           `yaml:"session-cookie-max-age"`
+	SecureSessionCookies bool          `yaml:"secure-session-cookies"`
+	SessionSecretKey     string        `yaml:"session-secret-key"`
+}
+
+// ModelExpiryConfig configures the expiry of ephemeral models.
+type ModelExpiryConfig struct {
+	WarningPeriod  time.Duration `yaml:"warning-period"`
+	DestroyStorage bool          `yaml:"destroy-storage"`
+	Force          bool          `yaml:"force"`
+}
+
+// InventoryConfig configures the inventory collection, an interval of 0
+// disables the collection.
+type InventoryConfig struct {
+	Interval time.Duration `yaml:"interval"`
+	Jitter   time.Duration `yaml:"jitter"`
+}
+
+// ControllerPoolConfig configures the pool of connections to controllers.
+type ControllerPoolConfig struct {
+	MinConnections      int           `yaml:"min-connections"`
+	MaxConnections      int           `yaml:"max-connections"`
+	IdleTimeout         time.Duration `yaml:"idle-timeout"`
+	HealthCheckInterval time.Duration `yaml:"health-check-interval"`
+	FailureThreshold    int           `yaml:"failure-threshold"`
+	OpenTimeout         time.Duration `yaml:"open-timeout"`
+}
+
+// AlertingConfig configures the evaluation and delivery of alerts.
+type AlertingConfig struct {
+	Interval time.Duration `yaml:"interval"`
+	SMTP     SMTPConfig    `yaml:"smtp"`
+}
+
+// SMTPConfig configures the SMTP server used to send alert emails.
+type SMTPConfig struct {
+	Address  string `yaml:"address"`
+	From     string `yaml:"from"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+}
+
+// TracingConfig configures the export of traces.
+type TracingConfig struct {
+	OTLPEndpoint string            `yaml:"otlp-endpoint"`
+	OTLPHeaders  map[string]string `yaml:"otlp-headers"`
+	SampleRatio  float64           `yaml:"sample-ratio"`
+}
+
+// Default returns the configuration used for any setting that is not
+// specified.
+func Default() *Config {
+	return &Config{
+		ListenAddress:       ":http-alt",
+		LeaderLeaseDuration: jimm.DefaultLeaderLeaseDuration,
+		Log: LogConfig{
+			Level: "info",
+		},
+		OpenFGA: OpenFGAConfig{
+			CacheSize: 10000,
+			CacheTTL:  10 * time.Second,
+		},
+		MacaroonExpiry: 24 * time.Hour,
+		JWTExpiry:      24 * time.Hour,
+		ModelExpiry: ModelExpiryConfig{
+			WarningPeriod: time.Hour,
+		},
+		Inventory: InventoryConfig{
+			Interval: time.Hour,
+			Jitter:   5 * time.Minute,
+		},
+		ControllerPool: ControllerPoolConfig{
+			MaxConnections:      jimm.DefaultPoolMaxConnections,
+			IdleTimeout:         jimm.DefaultPoolIdleTimeout,
+			HealthCheckInterval: jimm.DefaultPoolHealthCheckInterval,
+			FailureThreshold:    jimm.DefaultPoolFailureThreshold,
+			OpenTimeout:         jimm.DefaultPoolOpenTimeout,
+		},
+	}
+}
+
+// Read reads the configuration file at the given path, applies the
+// overrides from the environment variables returned by lookup and
+// validates the result. If path is empty the configuration is read
+// entirely from the environment.
+func Read(path string, lookup func(string) (string, bool)) (*Config, error) {
+	const op = errors.Op("config.Read")
+
+	c, err := Load(path)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	if err := c.ApplyEnv(lookup); err != nil {
+		return nil, errors.E(op, err)
+	}
+	if err := c.Validate(); err != nil {
+		return nil, errors.E(op, err)
+	}
+	return c, nil
+}
+
+// Load reads the configuration file at the given path. Settings not in
+// the file have their default value. If path is empty the default
+// configuration is returned.
+func Load(path string) (*Config, error) {
+	const op = errors.Op("config.Load")
+
+	c := Default()
+	if path == "" {
+		return c, nil
+	}
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(buf))
+	dec.KnownFields(true)
+	if err := dec.Decode(c); err != nil && err != io.EOF {
+		return nil, errors.E(op, err, fmt.Sprintf("cannot parse %s: %s", path, err))
+	}
+	return c, nil
+}
+
+// ApplyEnv overrides the configuration with the environment variables
+// returned by lookup, normally os.LookupEnv. Variables that are not set,
+// or are empty, leave the setting unchanged.
+func (c *Config) ApplyEnv(lookup func(string) (string, bool)) error {
+	const op = errors.Op("config.ApplyEnv")
+
+	e := envParser{lookup: lookup}
+	e.string(&c.ListenAddress, "JIMM_LISTEN_ADDR")
+	e.string(&c.UUID, "JIMM_UUID")
+	e.string(&c.DNSName, "JIMM_DNS_NAME")
+	e.string(&c.DSN, "JIMM_DSN")
+	e.fields(&c.Admins, "JIMM_ADMINS")
+	e.string(&c.ReplicaID, "JIMM_REPLICA_ID")
+	e.duration(&c.LeaderLeaseDuration, "JIMM_LEADER_LEASE_DURATION")
+
+	e.string(&c.Log.Level, "JIMM_LOG_LEVEL")
+	e.bool(&c.Log.DevMode, "JIMM_LOG_DEV_MODE")
+	e.bool(&c.Log.SQL, "JIMM_LOG_SQL")
+
+	e.string(&c.Vault.Address, "VAULT_ADDR")
+	e.string(&c.Vault.Path, "VAULT_PATH")
+	e.string(&c.Vault.RoleID, "VAULT_ROLE_ID")
+	e.string(&c.Vault.RoleSecretID, "VAULT_ROLE_SECRET_ID")
+	e.present(&c.InsecureSecretStorage, "INSECURE_SECRET_STORAGE")
+
+	e.string(&c.OpenFGA.Scheme, "OPENFGA_SCHEME")
+	e.string(&c.OpenFGA.Host, "OPENFGA_HOST")
+	e.string(&c.OpenFGA.Port, "OPENFGA_PORT")
+	e.string(&c.OpenFGA.Store, "OPENFGA_STORE")
+	e.string(&c.OpenFGA.AuthModel, "OPENFGA_AUTH_MODEL")
+	e.string(&c.OpenFGA.Token, "OPENFGA_TOKEN")
+	e.int(&c.OpenFGA.CacheSize, "OPENFGA_CACHE_SIZE")
+	e.duration(&c.OpenFGA.CacheTTL, "OPENFGA_CACHE_TTL")
+
+	e.string(&c.Bakery.PrivateKey, "BAKERY_PRIVATE_KEY")
+	e.string(&c.Bakery.PublicKey, "BAKERY_PUBLIC_KEY")
+	e.duration(&c.MacaroonExpiry, "JIMM_MACAROON_EXPIRY_DURATION")
+	e.duration(&c.JWTExpiry, "JIMM_JWT_EXPIRY")
+
+	e.string(&c.OAuth.IssuerURL, "JIMM_OAUTH_ISSUER_URL")
+	e.string(&c.OAuth.ClientID, "JIMM_OAUTH_CLIENT_ID")
+	e.string(&c.OAuth.ClientSecret, "JIMM_OAUTH_CLIENT_SECRET")
+	e.fields(&c.OAuth.Scopes, "JIMM_OAUTH_SCOPES")
+	e.duration(&c.OAuth.AccessTokenExpiry, "JIMM_ACCESS_TOKEN_EXPIRY_DURATION")
+	e.int(&c.OAuth.SessionCookieMaxAge, "JIMM_SESSION_COOKIE_MAX_AGE")
+	e.present(&c.OAuth.SecureSessionCookies, "JIMM_SECURE_SESSION_COOKIES")
+	e.string(&c.OAuth.SessionSecretKey, "JIMM_SESSION_SECRET_KEY")
+
+	e.string(&c.DashboardFinalRedirectURL, "JIMM_DASHBOARD_FINAL_REDIRECT_URL")
+	e.fields(&c.CorsAllowedOrigins, "CORS_ALLOWED_ORIGINS")
+	e.int(&c.AuditLogRetentionPeriodInDays, "JIMM_AUDIT_LOG_RETENTION_PERIOD_IN_DAYS")
+
+	e.duration(&c.ModelExpiry.WarningPeriod, "JIMM_MODEL_EXPIRY_WARNING_PERIOD")
+	e.bool(&c.ModelExpiry.DestroyStorage, "JIMM_MODEL_EXPIRY_DESTROY_STORAGE")
+	e.bool(&c.ModelExpiry.Force, "JIMM_MODEL_EXPIRY_FORCE")
+	e.duration(&c.Inventory.Interval, "JIMM_INVENTORY_INTERVAL")
+	e.duration(&c.Inventory.Jitter, "JIMM_INVENTORY_JITTER")
+	e.present(&c.PerModelMetrics, "JIMM_PER_MODEL_METRICS")
+
+	e.int(&c.ControllerPool.MinConnections, "JIMM_CONTROLLER_POOL_MIN_CONNECTIONS")
+	e.int(&c.ControllerPool.MaxConnections, "JIMM_CONTROLLER_POOL_MAX_CONNECTIONS")
+	e.duration(&c.ControllerPool.IdleTimeout, "JIMM_CONTROLLER_POOL_IDLE_TIMEOUT")
+	e.duration(&c.ControllerPool.HealthCheckInterval, "JIMM_CONTROLLER_POOL_HEALTH_CHECK_INTERVAL")
+	e.int(&c.ControllerPool.FailureThreshold, "JIMM_CONTROLLER_POOL_FAILURE_THRESHOLD")
+	e.duration(&c.ControllerPool.OpenTimeout, "JIMM_CONTROLLER_POOL_OPEN_TIMEOUT")
+
+	e.duration(&c.Alerting.Interval, "JIMM_ALERT_INTERVAL")
+	e.string(&c.Alerting.SMTP.Address, "JIMM_SMTP_ADDRESS")
+	e.string(&c.Alerting.SMTP.From, "JIMM_SMTP_FROM")
+	e.string(&c.Alerting.SMTP.Username, "JIMM_SMTP_USERNAME")
+	e.string(&c.Alerting.SMTP.Password, "JIMM_SMTP_PASSWORD")
+
+	e.string(&c.Tracing.OTLPEndpoint, "JIMM_OTLP_ENDPOINT")
+	e.headers(&c.Tracing.OTLPHeaders, "JIMM_OTLP_HEADERS")
+	e.float(&c.Tracing.SampleRatio, "JIMM_TRACE_SAMPLE_RATIO")
+
+	if len(e.errs) > 0 {
+		return errors.E(op, "invalid environment: "+strings.Join(e.errs, "; "))
+	}
+	return nil
+}
+
+// Validate checks that the configuration is complete and that every
+// setting has a valid value. All problems found are reported in the
+// returned error.
+func (c *Config) Validate() error {
+	const op = errors.Op("config.Validate")
+
+	var problems []string
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			problems = append(problems, fmt.Sprintf(format, args...))
+		}
+	}
+	nonNegative := func(d time.Duration, name string) {
+		check(d >= 0, "%s cannot be negative", name)
+	}
+
+	check(c.ListenAddress != "", "listen-address must be specified")
+	check(c.DSN != "", "dsn must be specified")
+	_, err := zapcore.ParseLevel(c.Log.Level)
+	check(err == nil, "log.level %q is not a valid log level", c.Log.Level)
+	check(c.InsecureSecretStorage || (c.Vault.RoleID != "" && c.Vault.RoleSecretID != ""), "vault.role-id and vault.role-secret-id must be specified unless insecure-secret-storage is set")
+	check(c.OpenFGA.CacheSize >= 0, "openfga.cache-size cannot be negative")
+	nonNegative(c.OpenFGA.CacheTTL, "openfga.cache-ttl")
+	nonNegative(c.LeaderLeaseDuration, "leader-lease-duration")
+	nonNegative(c.MacaroonExpiry, "macaroon-expiry")
+	nonNegative(c.JWTExpiry, "jwt-expiry")
+
+	u, err := url.Parse(c.OAuth.IssuerURL)
+	check(err == nil && u.Scheme != "", "oauth.issuer-url must be an absolute URL")
+	check(c.OAuth.ClientID != "", "oauth.client-id must be specified")
+	check(c.OAuth.ClientSecret != "", "oauth.client-secret must be specified")
+	check(len(c.OAuth.Scopes) > 0, "oauth.scopes must contain at least one scope")
+	nonNegative(c.OAuth.AccessTokenExpiry, "oauth.access-token-expiry")
+	check(c.OAuth.SessionCookieMaxAge >= 0, "oauth.session-cookie-max-age cannot be negative")
+	check(len(c.OAuth.SessionSecretKey) >= 64, "oauth.session-secret-key must be at least 64 characters")
+
+	if c.DashboardFinalRedirectURL != "" {
+		_, err := url.Parse(c.DashboardFinalRedirectURL)
+		check(err == nil, "dashboard-final-redirect-url is not a valid URL")
+	}
+	check(c.AuditLogRetentionPeriodInDays >= 0, "audit-log-retention-period-in-days cannot be negative")
+	nonNegative(c.ModelExpiry.WarningPeriod, "model-expiry.warning-period")
+	nonNegative(c.Inventory.Interval, "inventory.interval")
+	nonNegative(c.Inventory.Jitter, "inventory.jitter")
+	check(c.ControllerPool.MinConnections >= 0, "controller-pool.min-connections cannot be negative")
+	check(c.ControllerPool.MaxConnections >= c.ControllerPool.MinConnections, "controller-pool.max-connections cannot be less than controller-pool.min-connections")
+	nonNegative(c.ControllerPool.IdleTimeout, "controller-pool.idle-timeout")
+	nonNegative(c.ControllerPool.HealthCheckInterval, "controller-pool.health-check-interval")
+	check(c.ControllerPool.FailureThreshold >= 0, "controller-pool.failure-threshold cannot be negative")
+	nonNegative(c.ControllerPool.OpenTimeout, "controller-pool.open-timeout")
+	nonNegative(c.Alerting.Interval, "alerting.interval")
+	check(c.Tracing.SampleRatio >= 0 && c.Tracing.SampleRatio <= 1, "tracing.sample-ratio must be between 0 and 1")
+
+	if len(problems) > 0 {
+		return errors.E(op, "invalid configuration: "+strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// Params returns the service parameters described by the configuration.
+func (c *Config) Params() jimmsvc.Params {
+	return jimmsvc.Params{
+		ControllerUUID:    c.UUID,
+		DSN:               c.DSN,
+		ControllerAdmins:  c.Admins,
+		VaultRoleID:       c.Vault.RoleID,
+		VaultRoleSecretID: c.Vault.RoleSecretID,
+		VaultAddress:      c.Vault.Address,
+		VaultPath:         c.Vault.Path,
+		PublicDNSName:     c.DNSName,
+		OpenFGAParams: jimmsvc.OpenFGAParams{
+			Scheme:    c.OpenFGA.Scheme,
+			Host:      c.OpenFGA.Host,
+			Store:     c.OpenFGA.Store,
+			AuthModel: c.OpenFGA.AuthModel,
+			Token:     c.OpenFGA.Token,
+			Port:      c.OpenFGA.Port,
+			CacheSize: c.OpenFGA.CacheSize,
+			CacheTTL:  c.OpenFGA.CacheTTL,
+		},
+		PrivateKey:                    c.Bakery.PrivateKey,
+		PublicKey:                     c.Bakery.PublicKey,
+		AuditLogRetentionPeriodInDays: strconv.Itoa(c.AuditLogRetentionPeriodInDays),
+		MacaroonExpiryDuration:        c.MacaroonExpiry,
+		JWTExpiryDuration:             c.JWTExpiry,
+		InsecureSecretStorage:         c.InsecureSecretStorage,
+		OAuthAuthenticatorParams: jimmsvc.OAuthAuthenticatorParams{
+			IssuerURL:            c.OAuth.IssuerURL,
+			ClientID:             c.OAuth.ClientID,
+			ClientSecret:         c.OAuth.ClientSecret,
+			Scopes:               c.OAuth.Scopes,
+			SessionTokenExpiry:   c.OAuth.AccessTokenExpiry,
+			SessionCookieMaxAge:  c.OAuth.SessionCookieMaxAge,
+			JWTSessionKey:        c.OAuth.SessionSecretKey,
+			SecureSessionCookies: c.OAuth.SecureSessionCookies,
+		},
+		DashboardFinalRedirectURL: c.DashboardFinalRedirectURL,
+		CookieSessionKey:          []byte(c.OAuth.SessionSecretKey),
+		CorsAllowedOrigins:        c.CorsAllowedOrigins,
+		LogSQL:                    c.Log.SQL,
+		LogLevel:                  c.Log.Level,
+		ReplicaID:                 c.ReplicaID,
+		LeaderLeaseDuration:       c.LeaderLeaseDuration,
+		ModelExpiry: jimm.ModelExpiryParams{
+			WarningPeriod:  c.ModelExpiry.WarningPeriod,
+			DestroyStorage: c.ModelExpiry.DestroyStorage,
+			Force:          c.ModelExpiry.Force,
+		},
+		Inventory: jimm.InventoryParams{
+			Interval: c.Inventory.Interval,
+			Jitter:   c.Inventory.Jitter,
+		},
+		PerModelMetrics: c.PerModelMetrics,
+		ControllerPool: jimm.PoolParams{
+			MinConnections:      c.ControllerPool.MinConnections,
+			MaxConnections:      c.ControllerPool.MaxConnections,
+			IdleTimeout:         c.ControllerPool.IdleTimeout,
+			HealthCheckInterval: c.ControllerPool.HealthCheckInterval,
+			FailureThreshold:    c.ControllerPool.FailureThreshold,
+			OpenTimeout:         c.ControllerPool.OpenTimeout,
+		},
+		Alerting: jimm.AlertingParams{
+			Interval: c.Alerting.Interval,
+			SMTP: jimm.SMTPParams{
+				Address:  c.Alerting.SMTP.Address,
+				From:     c.Alerting.SMTP.From,
+				Username: c.Alerting.SMTP.Username,
+				Password: c.Alerting.SMTP.Password,
+			},
+		},
+		Tracing: servermon.TracingParams{
+			Endpoint:    c.Tracing.OTLPEndpoint,
+			Headers:     c.Tracing.OTLPHeaders,
+			SampleRatio: c.Tracing.SampleRatio,
+		},
+	}
+}
+
+// RestartRequired returns the names of the top-level settings that
+// differ between c and other and cannot be changed by reloading the
+// service.
+func (c *Config) RestartRequired(other *Config) []string {
+	a, b := *c, *other
+	a.clearReloadable()
+	b.clearReloadable()
+
+	var names []string
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	t := va.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if !reflect.DeepEqual(va.Field(i).Interface(), vb.Field(i).Interface()) {
+			names = append(names, t.Field(i).Tag.Get("yaml"))
+		}
+	}
+	return names
+}
+
+// clearReloadable clears the settings that are applied by
+// jimmsvc.Service.Reload.
+func (c *Config) clearReloadable() {
+	c.Log.Level = ""
+	c.CorsAllowedOrigins = nil
+	c.AuditLogRetentionPeriodInDays = 0
+}
+
+// An envParser applies environment variables to configuration settings,
+// collecting any parse errors.
+type envParser struct {
+	lookup func(string) (string, bool)
+	errs   []string
+}
+
+func (e *envParser) get(key string) (string, bool) {
+	v, ok := e.lookup(key)
+	return v, ok && v != ""
+}
+
+func (e *envParser) fail(key, value, kind string) {
+	e.errs = append(e.errs, fmt.Sprintf("%s: %q is not a valid %s", key, value, kind))
+}
+
+func (e *envParser) string(dst *string, key string) {
+	if v, ok := e.get(key); ok {
+		*dst = v
+	}
+}
+
+// fields parses a space separated list.
+func (e *envParser) fields(dst *[]string, key string) {
+	if v, ok := e.get(key); ok {
+		*dst = strings.Fields(v)
+	}
+}
+
+func (e *envParser) duration(dst *time.Duration, key string) {
+	if v, ok := e.get(key); ok {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			e.fail(key, v, "duration")
+			return
+		}
+		*dst = d
+	}
+}
+
+func (e *envParser) int(dst *int, key string) {
+	if v, ok := e.get(key); ok {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			e.fail(key, v, "integer")
+			return
+		}
+		*dst = n
+	}
+}
+
+func (e *envParser) float(dst *float64, key string) {
+	if v, ok := e.get(key); ok {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			e.fail(key, v, "number")
+			return
+		}
+		*dst = f
+	}
+}
+
+func (e *envParser) bool(dst *bool, key string) {
+	if v, ok := e.get(key); ok {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			e.fail(key, v, "boolean")
+			return
+		}
+		*dst = b
+	}
+}
+
+// present sets dst if the variable is set, whatever its value.
+func (e *envParser) present(dst *bool, key string) {
+	if _, ok := e.lookup(key); ok {
+		*dst = true
+	}
+}
+
+// headers parses a comma separated list of key=value pairs.
+func (e *envParser) headers(dst *map[string]string, key string) {
+	v, ok := e.get(key)
+	if !ok {
+		return
+	}
+	headers := make(map[string]string)
+	for _, h := range strings.Split(v, ",") {
+		k, hv, ok := strings.Cut(h, "=")
+		if !ok {
+			e.fail(key, v, "list of key=value headers")
+			return
+		}
+		headers[strings.TrimSpace(k)] = strings.TrimSpace(hv)
+	}
+	*dst = headers
+}
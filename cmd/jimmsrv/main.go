@@ -4,11 +4,11 @@ package main
 
 import (
 	"context"
+	"flag"
+	"fmt"
 	"net/http"
-	"net/url"
 	"os"
-	"strconv"
-	"strings"
+	"os/signal"
 	"syscall"
 	"time"
 
@@ -16,13 +16,17 @@ import (
 	"github.com/juju/zaputil/zapctx"
 	"go.uber.org/zap"
 
+	"github.com/canonical/jimm/v3/cmd/jimmsrv/config"
 	jimmsvc "github.com/canonical/jimm/v3/cmd/jimmsrv/service"
-	"github.com/canonical/jimm/v3/internal/errors"
 	"github.com/canonical/jimm/v3/internal/logger"
 	"github.com/canonical/jimm/v3/version"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "validate-config" {
+		os.Exit(validateConfig(os.Args[2:]))
+	}
+
 	ctx, s := service.NewService(context.Background(), os.Interrupt, syscall.SIGTERM)
 	s.Go(func() error {
 		return start(ctx, s)
@@ -35,156 +39,58 @@ func main() {
 	}
 }
 
+// validateConfig runs the validate-config command which checks the
+// configuration jimmsrv would start with and reports any problems. It
+// returns the process exit code.
+func validateConfig(args []string) int {
+	fs := flag.NewFlagSet("validate-config", flag.ContinueOnError)
+	noEnv := fs.Bool("no-env", false, "do not apply environment variable overrides")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: jimmsrv validate-config [--no-env] [path]\n\n")
+		fmt.Fprintf(fs.Output(), "Validate a jimmsrv configuration file, the path defaults to $JIMM_CONFIG_FILE.\n\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	path := os.Getenv("JIMM_CONFIG_FILE")
+	switch fs.NArg() {
+	case 0:
+	case 1:
+		path = fs.Arg(0)
+	default:
+		fs.Usage()
+		return 2
+	}
+	lookup := os.LookupEnv
+	if *noEnv {
+		lookup = func(string) (string, bool) { return "", false }
+	}
+	if _, err := config.Read(path, lookup); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	fmt.Println("configuration is valid")
+	return 0
+}
+
 // start initialises the jimmsrv service.
-//
-//nolint:gocognit // Start function to be ignored.
 func start(ctx context.Context, s *service.Service) error {
-	logLevel := os.Getenv("JIMM_LOG_LEVEL")
-	logDevMode, _ := strconv.ParseBool(os.Getenv("JIMM_LOG_DEV_MODE"))
-	logger.SetupLogger(ctx, logLevel, logDevMode)
+	configPath := os.Getenv("JIMM_CONFIG_FILE")
+	cfg, err := config.Read(configPath, os.LookupEnv)
+	if err != nil {
+		logger.SetupLogger(ctx, os.Getenv("JIMM_LOG_LEVEL"), false)
+		zapctx.Error(ctx, "invalid configuration", zap.Error(err))
+		return err
+	}
+	logger.SetupLogger(ctx, cfg.Log.Level, cfg.Log.DevMode)
 	zapctx.Info(ctx, "jimm info",
 		zap.String("version", version.VersionInfo.Version),
 		zap.String("commit", version.VersionInfo.GitCommit),
 	)
-	// TODO(mhilton) access logs?
-	addr := os.Getenv("JIMM_LISTEN_ADDR")
-	if addr == "" {
-		addr = ":http-alt"
-	}
-	macaroonExpiryDuration := 24 * time.Hour
-	durationString := os.Getenv("JIMM_MACAROON_EXPIRY_DURATION")
-	if durationString != "" {
-		expiry, err := time.ParseDuration(durationString)
-		if err != nil {
-			zapctx.Error(ctx, "failed to parse macaroon expiry duration", zap.Error(err))
-		}
-		macaroonExpiryDuration = expiry
-	}
-	jwtExpiryDuration := 24 * time.Hour
-	durationString = os.Getenv("JIMM_JWT_EXPIRY")
-	if durationString != "" {
-		expiry, err := time.ParseDuration(durationString)
-		if err != nil {
-			zapctx.Error(ctx, "failed to parse jwt expiry duration", zap.Error(err))
-		} else {
-			jwtExpiryDuration = expiry
-		}
-	}
-
-	sessionTokenExpiryDuration := time.Duration(0)
-	durationString = os.Getenv("JIMM_ACCESS_TOKEN_EXPIRY_DURATION")
-	if durationString != "" {
-		expiry, err := time.ParseDuration(durationString)
-		if err != nil {
-			zapctx.Error(ctx, "failed to parse access token expiry duration", zap.Error(err))
-			return err
-		}
-		sessionTokenExpiryDuration = expiry
-	}
-
-	issuerURL := os.Getenv("JIMM_OAUTH_ISSUER_URL")
-	parsedIssuerURL, err := url.Parse(issuerURL)
-	if err != nil {
-		zapctx.Error(ctx, "failed to parse oauth issuer url", zap.Error(err))
-		return err
-	}
-
-	if parsedIssuerURL.Scheme == "" {
-		zapctx.Error(ctx, "oauth issuer url has no scheme")
-		return errors.E("oauth issuer url has no scheme")
-	}
-
-	clientID := os.Getenv("JIMM_OAUTH_CLIENT_ID")
-	if clientID == "" {
-		zapctx.Error(ctx, "no oauth client id")
-		return errors.E("no oauth client id")
-	}
-
-	clientSecret := os.Getenv("JIMM_OAUTH_CLIENT_SECRET")
-	if clientSecret == "" {
-		zapctx.Error(ctx, "no oauth client secret")
-		return errors.E("no oauth client secret")
-	}
-
-	scopes := os.Getenv("JIMM_OAUTH_SCOPES")
-	scopesParsed := strings.Split(scopes, " ")
-	for i, scope := range scopesParsed {
-		scopesParsed[i] = strings.TrimSpace(scope)
-	}
-	zapctx.Info(ctx, "oauth scopes", zap.Any("scopes", scopesParsed))
-	if len(scopesParsed) == 0 {
-		zapctx.Error(ctx, "no oauth client scopes present")
-		return errors.E("no oauth client scopes present")
-	}
-
-	insecureSecretStorage := false
-	if _, ok := os.LookupEnv("INSECURE_SECRET_STORAGE"); ok {
-		insecureSecretStorage = true
-	}
-
-	secureSessionCookies := false
-	if _, ok := os.LookupEnv("JIMM_SECURE_SESSION_COOKIES"); ok {
-		secureSessionCookies = true
-	}
+	zapctx.Info(ctx, "oauth scopes", zap.Strings("scopes", cfg.OAuth.Scopes))
 
-	sessionCookieMaxAge := os.Getenv("JIMM_SESSION_COOKIE_MAX_AGE")
-	sessionCookieMaxAgeInt, err := strconv.Atoi(sessionCookieMaxAge)
-	if err != nil {
-		return errors.E("unable to parse jimm session cookie max age")
-	}
-	if sessionCookieMaxAgeInt < 0 {
-		return errors.E("jimm session cookie max age cannot be less than 0")
-	}
-
-	sessionSecretKey := os.Getenv("JIMM_SESSION_SECRET_KEY")
-	if len(sessionSecretKey) < 64 {
-		return errors.E("jimm session store secret must be at least 64 characters")
-	}
-
-	corsAllowedOrigins := strings.Split(os.Getenv("CORS_ALLOWED_ORIGINS"), " ")
-
-	logSQL, _ := strconv.ParseBool(os.Getenv("JIMM_LOG_SQL"))
-
-	jimmsvc, err := jimmsvc.NewService(ctx, jimmsvc.Params{
-		ControllerUUID:    os.Getenv("JIMM_UUID"),
-		DSN:               os.Getenv("JIMM_DSN"),
-		ControllerAdmins:  strings.Fields(os.Getenv("JIMM_ADMINS")),
-		VaultRoleID:       os.Getenv("VAULT_ROLE_ID"),
-		VaultRoleSecretID: os.Getenv("VAULT_ROLE_SECRET_ID"),
-		VaultAddress:      os.Getenv("VAULT_ADDR"),
-		VaultPath:         os.Getenv("VAULT_PATH"),
-		PublicDNSName:     os.Getenv("JIMM_DNS_NAME"),
-		OpenFGAParams: jimmsvc.OpenFGAParams{
-			Scheme:    os.Getenv("OPENFGA_SCHEME"),
-			Host:      os.Getenv("OPENFGA_HOST"),
-			Store:     os.Getenv("OPENFGA_STORE"),
-			AuthModel: os.Getenv("OPENFGA_AUTH_MODEL"),
-			Token:     os.Getenv("OPENFGA_TOKEN"),
-			Port:      os.Getenv("OPENFGA_PORT"),
-		},
-		PrivateKey:                    os.Getenv("BAKERY_PRIVATE_KEY"),
-		PublicKey:                     os.Getenv("BAKERY_PUBLIC_KEY"),
-		AuditLogRetentionPeriodInDays: os.Getenv("JIMM_AUDIT_LOG_RETENTION_PERIOD_IN_DAYS"),
-		MacaroonExpiryDuration:        macaroonExpiryDuration,
-		JWTExpiryDuration:             jwtExpiryDuration,
-		InsecureSecretStorage:         insecureSecretStorage,
-		OAuthAuthenticatorParams: jimmsvc.OAuthAuthenticatorParams{
-			IssuerURL:            issuerURL,
-			ClientID:             clientID,
-			ClientSecret:         clientSecret,
-			Scopes:               scopesParsed,
-			SessionTokenExpiry:   sessionTokenExpiryDuration,
-			SessionCookieMaxAge:  sessionCookieMaxAgeInt,
-			JWTSessionKey:        sessionSecretKey,
-			SecureSessionCookies: secureSessionCookies,
-		},
-		DashboardFinalRedirectURL: os.Getenv("JIMM_DASHBOARD_FINAL_REDIRECT_URL"),
-		CookieSessionKey:          []byte(sessionSecretKey),
-		CorsAllowedOrigins:        corsAllowedOrigins,
-		LogSQL:                    logSQL,
-		LogLevel:                  logLevel,
-		IsLeader:                  os.Getenv("JIMM_IS_LEADER") != "",
-	})
+	jimmsvc, err := jimmsvc.NewService(ctx, cfg.Params())
 	if err != nil {
 		return err
 	}
@@ -192,7 +98,7 @@ func start(ctx context.Context, s *service.Service) error {
 	jimmsvc.StartServices(ctx, s)
 
 	httpsrv := &http.Server{
-		Addr:              addr,
+		Addr:              cfg.ListenAddress,
 		Handler:           jimmsvc,
 		ReadHeaderTimeout: time.Second * 5,
 	}
@@ -208,6 +114,43 @@ func start(ctx context.Context, s *service.Service) error {
 		jimmsvc.Cleanup()
 	})
 	s.Go(httpsrv.ListenAndServe)
+	s.Go(func() error {
+		reloadOnHangup(ctx, jimmsvc, configPath, cfg)
+		return nil
+	})
 	zapctx.Info(ctx, "Successfully started JIMM server")
 	return nil
 }
+
+// reloadOnHangup re-reads the configuration whenever the process receives
+// a SIGHUP and applies the settings that can be changed without a
+// restart, see jimmsvc.Service.Reload. An invalid configuration is logged
+// and leaves the running configuration unchanged. reloadOnHangup returns
+// when the given context is canceled.
+func reloadOnHangup(ctx context.Context, svc *jimmsvc.Service, path string, cfg *config.Config) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+		}
+		zapctx.Info(ctx, "reloading configuration", zap.String("path", path))
+		newCfg, err := config.Read(path, os.LookupEnv)
+		if err != nil {
+			zapctx.Error(ctx, "cannot reload configuration", zap.Error(err))
+			continue
+		}
+		if err := svc.Reload(ctx, newCfg.Params()); err != nil {
+			zapctx.Error(ctx, "cannot reload configuration", zap.Error(err))
+			continue
+		}
+		// cfg is the configuration the server was started with, so
+		// any pending change is reported on every reload.
+		if changed := cfg.RestartRequired(newCfg); len(changed) > 0 {
+			zapctx.Warn(ctx, "configuration changes require a restart", zap.Strings("settings", changed))
+		}
+	}
+}
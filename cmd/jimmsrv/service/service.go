@@ -6,10 +6,14 @@ package service
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"net/url"
+	"os"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/antonlindstrom/pgstore"
@@ -34,6 +38,7 @@ import (
 	"github.com/canonical/jimm/v3/internal/errors"
 	"github.com/canonical/jimm/v3/internal/jimm"
 	jimmcreds "github.com/canonical/jimm/v3/internal/jimm/credentials"
+	"github.com/canonical/jimm/v3/internal/jimm/scheduler"
 	"github.com/canonical/jimm/v3/internal/jimmhttp"
 	"github.com/canonical/jimm/v3/internal/jimmhttp/rebac_admin"
 	"github.com/canonical/jimm/v3/internal/jimmjwx"
@@ -44,7 +49,9 @@ import (
 	"github.com/canonical/jimm/v3/internal/openfga"
 	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
 	"github.com/canonical/jimm/v3/internal/pubsub"
+	"github.com/canonical/jimm/v3/internal/servermon"
 	"github.com/canonical/jimm/v3/internal/vault"
+	"github.com/canonical/jimm/v3/version"
 )
 
 const (
@@ -59,6 +66,14 @@ type OpenFGAParams struct {
 	AuthModel string
 	Token     string
 	Port      string
+
+	// CacheSize is the maximum number of authorisation check results
+	// held in the client-side cache.
+	CacheSize int
+
+	// CacheTTL is the length of time an authorisation check result is
+	// cached for. If CacheTTL or CacheSize is zero results are not cached.
+	CacheTTL time.Duration
 }
 
 // OAuthAuthenticatorParams holds parameters needed to configure an OAuthAuthenticator
@@ -101,8 +116,17 @@ type Params struct {
 	// is not set a random UUID will be generated.
 	ControllerUUID string
 
-	// IsLeader indicates that this is the JIMM leader unit.
-	IsLeader bool
+	// ReplicaID identifies this JIMM replica when electing the leader
+	// that runs the background jobs that must only run once. It must be
+	// unique amongst the replicas. If this is empty an ID is generated
+	// from the host name.
+	ReplicaID string
+
+	// LeaderLeaseDuration is the length of time the elected leader holds
+	// its lease without renewing it. If the leader fails another replica
+	// takes over within this time. If this is zero
+	// jimm.DefaultLeaderLeaseDuration is used.
+	LeaderLeaseDuration time.Duration
 
 	// DSN is the data source name that the JIMM service will use to
 	// connect to its database. If this is empty an in-memory database
@@ -114,12 +138,16 @@ type Params struct {
 	// authenticate to the controller.
 	ControllerAdmins []string
 
-	// DisableConnectionCache disables caching connections to
-	// controllers. By default controller connections are cached, if
-	// this is set then a new connection will be created for each API
-	// call. This is mostly useful for testing.
+	// DisableConnectionCache disables pooling connections to
+	// controllers. By default controller and model connections are
+	// pooled, if this is set then a new connection will be created for
+	// each API call. This is mostly useful for testing.
 	DisableConnectionCache bool
 
+	// ControllerPool configures the pool of connections to
+	// controllers.
+	ControllerPool jimm.PoolParams
+
 	// VaultRoleID is the AppRole role ID.
 	VaultRoleID string
 
@@ -194,6 +222,27 @@ type Params struct {
 	// LogLevel is the default logger is set.
 	// Setting this to "debug" enables the requests logger as well.
 	LogLevel string
+
+	// ModelExpiry holds the parameters used when warning the owners of
+	// ephemeral models and destroying the models once they expire.
+	ModelExpiry jimm.ModelExpiryParams
+
+	// Inventory holds the parameters used when collecting the estate
+	// inventory. Collection is disabled if the interval is zero.
+	Inventory jimm.InventoryParams
+
+	// PerModelMetrics enables the model health metrics labelled with
+	// each model, in addition to the per-controller metrics. On large
+	// deployments these produce a very large number of series.
+	PerModelMetrics bool
+
+	// Alerting holds the parameters used when evaluating alert rules.
+	// If the interval is zero jimm.DefaultAlertInterval is used.
+	Alerting jimm.AlertingParams
+
+	// Tracing holds the parameters used to export traces. Traces are
+	// not exported if the endpoint is empty.
+	Tracing servermon.TracingParams
 }
 
 // A Service is the implementation of a JIMM server.
@@ -201,8 +250,17 @@ type Service struct {
 	jimm       *jimm.JIMM
 	jwkService *jimmjwx.JWKSService
 
-	isLeader              bool
-	auditLogCleanupPeriod int
+	elector               *jimm.LeaderElector
+	auditLogCleanupPeriod atomic.Int64
+	modelExpiry           jimm.ModelExpiryParams
+	inventory             jimm.InventoryParams
+	perModelMetrics       bool
+	alerting              jimm.AlertingParams
+	modelSummaryStatus    *jimm.ModelSummaryWatchStatus
+	controllerPool        *jimm.ControllerPool
+	health                *jimmhttp.HealthHandler
+	httpCors              atomic.Pointer[cors.Cors]
+	websocketCors         *middleware.WebsocketCors
 
 	mux      *chi.Mux
 	cleanups []func() error
@@ -218,21 +276,35 @@ func (s *Service) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 }
 
 // WatchModelSummaries connects to all controllers and starts a
-// ModelSummaryWatcher for all models. WatchModelSummaries finishes when
-// the given context is canceled, or there is a fatal error watching model
+// ModelSummaryWatcher for all models. The summaries are published through
+// the database so that they reach the pub-sub hub of every replica, see
+// DistributeModelSummaries. WatchModelSummaries finishes when the given
+// context is canceled, or there is a fatal error watching model
 // summaries.
 func (s *Service) WatchModelSummaries(ctx context.Context) error {
 	w := jimm.Watcher{
-		Database: s.jimm.Database,
-		Dialer:   s.jimm.Dialer,
-		Pubsub:   s.jimm.Pubsub,
+		Database:        s.jimm.Database,
+		Dialer:          s.jimm.Dialer,
+		Pubsub:          s.jimm.Pubsub,
+		Broker:          jimm.DatabaseModelSummaryBroker{Database: s.jimm.Database},
+		ModelEvents:     s.jimm,
+		PerModelMetrics: s.perModelMetrics,
+		Status:          s.modelSummaryStatus,
 	}
 	return w.WatchAllModelSummaries(ctx, 10*time.Minute)
 }
 
-// StartJWKSRotator see internal/jimmjwx/jwks.go for details.
-func (s *Service) StartJWKSRotator(ctx context.Context, checkRotateRequired <-chan time.Time, initialRotateRequiredTime time.Time) error {
-	return s.jwkService.StartJWKSRotator(ctx, checkRotateRequired, initialRotateRequiredTime)
+// DistributeModelSummaries publishes the model summaries published by
+// the leader's watcher to this replica's pub-sub hub.
+func (s *Service) DistributeModelSummaries(ctx context.Context) error {
+	broker := jimm.DatabaseModelSummaryBroker{Database: s.jimm.Database}
+	return jimm.DistributeModelSummaries(ctx, broker, s.jimm.Pubsub, s.modelSummaryStatus)
+}
+
+// rotateJWKS generates a new JWKS, valid for three months, if there is
+// none or the current one has expired.
+func (s *Service) rotateJWKS(ctx context.Context) error {
+	return s.jwkService.RotateJWKS(ctx, time.Now().UTC().AddDate(0, 3, 0))
 }
 
 // MonitorResources periodically updates metrics.
@@ -249,36 +321,54 @@ func (s *Service) MonitorResources(ctx context.Context) {
 	}
 }
 
-// OpenFGACleanup starts a goroutine that cleans up any orphaned tuples from OpenFGA.
-func (s *Service) OpenFGACleanup(ctx context.Context, trigger <-chan time.Time) error {
-	for {
-		select {
-		case <-trigger:
-			err := s.jimm.OpenFGACleanup(ctx)
-			if err != nil {
-				zapctx.Error(ctx, "openfga cleanup", zap.Error(err))
-				continue
-			}
-		case <-ctx.Done():
-			return nil
-		}
+// Scheduler returns the scheduler that runs the periodic background
+// jobs. The jobs are only run on the leader, but their history is visible
+// from, and they can be triggered through, every replica.
+func (s *Service) Scheduler() (*scheduler.Scheduler, error) {
+	sched := &scheduler.Scheduler{
+		Database: s.jimm.Database,
+		Replica:  s.elector.Holder,
 	}
-}
-
-// CleanupDyingModels triggers every `trigger` time and calls the jimm methods to cleanup dying models.
-func (s *Service) CleanupDyingModels(ctx context.Context, trigger <-chan time.Time) error {
-	for {
-		select {
-		case <-trigger:
-			err := s.jimm.CleanupDyingModels(ctx)
-			if err != nil {
-				zapctx.Error(ctx, "dying models cleanup", zap.Error(err))
-				continue
+	jobs := []scheduler.Job{{
+		Name:     "jwks-rotation",
+		Schedule: "@hourly",
+		Run:      s.rotateJWKS,
+	}, {
+		// cleans up all orphaned tuples
+		Name:     "openfga-cleanup",
+		Schedule: "@every 6h",
+		Jitter:   10 * time.Minute,
+		Run:      s.jimm.OpenFGACleanup,
+	}, {
+		Name:     "dying-model-cleanup",
+		Schedule: "@every 1m",
+		Run:      s.jimm.CleanupDyingModels,
+	}, {
+		// warns about and destroys expiring ephemeral models
+		Name:     "model-expiry",
+		Schedule: "@every 1m",
+		Run: func(ctx context.Context) error {
+			return s.jimm.ExpireModels(ctx, s.modelExpiry)
+		},
+	}, {
+		// the retention period can be changed by Reload, a period of
+		// 0 keeps the audit log forever.
+		Name:     "audit-log-cleanup",
+		Schedule: "CRON_TZ=UTC 0 9 * * *",
+		Run: func(ctx context.Context) error {
+			period := s.auditLogCleanupPeriod.Load()
+			if period == 0 {
+				return nil
 			}
-		case <-ctx.Done():
-			return nil
+			return jimm.NewAuditLogCleanupService(s.jimm.Database, int(period)).Cleanup(ctx)
+		},
+	}}
+	for _, job := range jobs {
+		if err := sched.Register(job); err != nil {
+			return nil, err
 		}
 	}
+	return sched, nil
 }
 
 // Cleanup cleans up resources that need to be released on shutdown.
@@ -305,6 +395,15 @@ func NewService(ctx context.Context, p Params) (*Service, error) {
 
 	s := new(Service)
 
+	if p.Tracing.ServiceVersion == "" {
+		p.Tracing.ServiceVersion = version.VersionInfo.Version
+	}
+	shutdownTracing, err := servermon.SetupTracing(ctx, p.Tracing)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	s.AddCleanup(func() error { return shutdownTracing(context.Background()) })
+
 	jimmParameters := jimm.Parameters{
 		UUID:   p.ControllerUUID,
 		Pubsub: &pubsub.Hub{MaxConcurrency: 50},
@@ -326,8 +425,11 @@ func NewService(ctx context.Context, p Params) (*Service, error) {
 		DB: database,
 	}
 	jimmParameters.Database = db
+	if err := db.Migrate(ctx, false); err != nil {
+		return nil, errors.E(op, err)
+	}
 
-	openFGAclient, err := newOpenFGAClient(ctx, p.OpenFGAParams)
+	openFGAclient, err := newOpenFGAClient(ctx, p.OpenFGAParams, db)
 	if err != nil {
 		return nil, errors.E(op, err)
 	}
@@ -384,6 +486,14 @@ func NewService(ctx context.Context, p Params) (*Service, error) {
 	}
 
 	s.jwkService = jimmjwx.NewJWKSService(credentialStore)
+	// The jwks-rotation job is first run an hour after the scheduler
+	// starts, so make sure there is a JWKS before the server is started.
+	// Without one JWT logins to controllers fail and the server is not
+	// ready.
+	if err := s.rotateJWKS(ctx); err != nil {
+		zapctx.Error(ctx, "failed to rotate JWKS", zap.Error(err))
+		return nil, errors.E(op, err, "failed to rotate JWKS")
+	}
 	jimmParameters.JWTService = jimmjwx.NewJWTService(jimmjwx.JWTServiceParams{
 		Host:   p.PublicDNSName,
 		Store:  credentialStore,
@@ -395,7 +505,9 @@ func NewService(ctx context.Context, p Params) (*Service, error) {
 	}
 
 	if !p.DisableConnectionCache {
-		jimmParameters.Dialer = jimm.CacheDialer(jimmParameters.Dialer)
+		s.controllerPool = jimm.NewControllerPool(jimmParameters.Dialer, p.ControllerPool)
+		s.AddCleanup(s.controllerPool.Close)
+		jimmParameters.Dialer = s.controllerPool
 	}
 
 	if _, err := url.Parse(p.DashboardFinalRedirectURL); err != nil {
@@ -408,18 +520,30 @@ func NewService(ctx context.Context, p Params) (*Service, error) {
 		return nil, errors.E(op, err)
 	}
 
+	replicaID := p.ReplicaID
+	if replicaID == "" {
+		replicaID = newReplicaID()
+	}
+	s.elector = &jimm.LeaderElector{
+		Database:      db,
+		Holder:        replicaID,
+		LeaseDuration: p.LeaderLeaseDuration,
+	}
+
 	s.mux = chi.NewRouter()
 
 	s.mux.Use(chimiddleware.RequestLogger(&logger.HTTPLogFormatter{}))
 	s.mux.Use(middleware.MeasureHTTPResponseTime)
-
-	// Setup CORS middleware
-	corsOpts := cors.New(cors.Options{
-		AllowedOrigins:   p.CorsAllowedOrigins,
-		AllowedMethods:   []string{"GET"},
-		AllowCredentials: true,
+	s.mux.Use(middleware.TraceContext)
+
+	// Setup CORS middleware, the allowed origins can be changed by
+	// Reload.
+	s.setCorsAllowedOrigins(p.CorsAllowedOrigins)
+	s.mux.Use(func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			s.httpCors.Load().Handler(h).ServeHTTP(w, req)
+		})
 	})
-	s.mux.Use(corsOpts.Handler)
 
 	// Setup all HTTP handlers.
 	mountHandler := func(path string, h jimmhttp.JIMMHttpHandler) {
@@ -435,13 +559,21 @@ func NewService(ctx context.Context, p Params) (*Service, error) {
 
 	s.mux.Mount("/rebac", middleware.AuthenticateRebac("/rebac", rebacBackend.Handler(""), s.jimm))
 
+	s.modelSummaryStatus = new(jimm.ModelSummaryWatchStatus)
+	healthChecks := s.healthChecks()
+	s.health = jimmhttp.NewHealthHandler(healthChecks)
+	s.mux.Get("/healthz", s.health.Healthz)
+	s.mux.Get("/readyz", s.health.Readyz)
+
+	statusChecks := map[string]jimmhttp.StatusCheck{
+		"start_time": jimmhttp.ServerStartTime,
+	}
+	for k, check := range healthChecks {
+		statusChecks[k] = check
+	}
 	mountHandler(
 		"/debug",
-		jimmhttp.NewDebugHandler(
-			map[string]jimmhttp.StatusCheck{
-				"start_time": jimmhttp.ServerStartTime,
-			},
-		),
+		jimmhttp.NewDebugHandler(statusChecks),
 	)
 	mountHandler(
 		"/.well-known",
@@ -478,68 +610,302 @@ func NewService(ctx context.Context, p Params) (*Service, error) {
 
 	// Websockets require extra care when cookies are used for authentication
 	// to avoid CSRF attacks. https://portswigger.net/web-security/websockets/cross-site-websocket-hijacking
-	websocketCors := middleware.NewWebsocketCors(p.CorsAllowedOrigins)
-	s.mux.Handle("/api", websocketCors.Handler(jujuapi.APIHandler(ctx, s.jimm, params)))
-	s.mux.Handle("/model/*", websocketCors.Handler(http.StripPrefix("/model", jujuapi.ModelHandler(ctx, s.jimm, params))))
+	s.websocketCors = middleware.NewWebsocketCors(p.CorsAllowedOrigins)
+	s.mux.Handle("/api", s.websocketCors.Handler(jujuapi.APIHandler(ctx, s.jimm, params)))
+	s.mux.Handle("/model/*", s.websocketCors.Handler(http.StripPrefix("/model", jujuapi.ModelHandler(ctx, s.jimm, params))))
 	mountHandler(
 		"/model/{uuid}/{type:charms|applications}",
 		jimmhttp.NewHTTPProxyHandler(s.jimm),
 	)
 
-	if p.AuditLogRetentionPeriodInDays != "" {
-		var err error
-		s.auditLogCleanupPeriod, err = strconv.Atoi(p.AuditLogRetentionPeriodInDays)
-		if err != nil {
-			return nil, errors.E(op, "failed to parse audit log retention period")
-		}
-		if s.auditLogCleanupPeriod < 0 {
-			return nil, errors.E(op, "retention period cannot be less than 0")
-		}
+	auditLogCleanupPeriod, err := parseAuditLogRetentionPeriod(p.AuditLogRetentionPeriodInDays)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	s.auditLogCleanupPeriod.Store(int64(auditLogCleanupPeriod))
+	s.modelExpiry = p.ModelExpiry
+	s.inventory = p.Inventory
+	s.perModelMetrics = p.PerModelMetrics
+	s.alerting = p.Alerting
+	if s.alerting.Interval <= 0 {
+		s.alerting.Interval = jimm.DefaultAlertInterval
 	}
-	s.isLeader = p.IsLeader
 
 	return s, nil
 }
 
-func (s *Service) StartServices(ctx context.Context, svc *service.Service) {
-	// on the leader unit we start additional routines
-	if s.isLeader {
-		// audit log cleanup routine
-		if s.auditLogCleanupPeriod != 0 {
-			svc.Go(func() error {
-				jimm.NewAuditLogCleanupService(s.jimm.Database, s.auditLogCleanupPeriod).Start(ctx)
-				return nil
-			})
-		}
+// Reload applies the settings in the given parameters that can be changed
+// whilst the service is running. These are LogLevel, CorsAllowedOrigins
+// and AuditLogRetentionPeriodInDays, all other parameters are ignored.
+// The parameters are validated before any setting is changed.
+func (s *Service) Reload(ctx context.Context, p Params) error {
+	const op = errors.Op("service.Reload")
 
-		// the JWKS rotator
-		svc.Go(func() error {
-			if err := s.StartJWKSRotator(ctx, time.NewTicker(time.Hour).C, time.Now().UTC().AddDate(0, 3, 0)); err != nil {
-				zapctx.Error(ctx, "failed to start JWKS rotator", zap.Error(err))
+	auditLogCleanupPeriod, err := parseAuditLogRetentionPeriod(p.AuditLogRetentionPeriodInDays)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	if err := logger.SetLogLevel(p.LogLevel); err != nil {
+		return errors.E(op, err)
+	}
+	s.setCorsAllowedOrigins(p.CorsAllowedOrigins)
+	s.websocketCors.SetAllowedOrigins(p.CorsAllowedOrigins)
+	s.auditLogCleanupPeriod.Store(int64(auditLogCleanupPeriod))
+	zapctx.Info(ctx, "service reloaded",
+		zap.String("log-level", p.LogLevel),
+		zap.Strings("cors-allowed-origins", p.CorsAllowedOrigins),
+		zap.Int("audit-log-retention-period-in-days", auditLogCleanupPeriod),
+	)
+	return nil
+}
+
+// setCorsAllowedOrigins replaces the CORS middleware applied to HTTP
+// requests with one allowing the given origins.
+func (s *Service) setCorsAllowedOrigins(origins []string) {
+	s.httpCors.Store(cors.New(cors.Options{
+		AllowedOrigins:   origins,
+		AllowedMethods:   []string{"GET"},
+		AllowCredentials: true,
+	}))
+}
+
+// parseAuditLogRetentionPeriod parses the audit log retention period
+// parameter. An empty period is the same as 0, which disables the clean
+// up of the audit log.
+func parseAuditLogRetentionPeriod(period string) (int, error) {
+	if period == "" {
+		return 0, nil
+	}
+	days, err := strconv.Atoi(period)
+	if err != nil {
+		return 0, errors.E("failed to parse audit log retention period")
+	}
+	if days < 0 {
+		return 0, errors.E("retention period cannot be less than 0")
+	}
+	return days, nil
+}
+
+// healthChecks returns the checks that determine whether the server is
+// ready to serve requests. These are also reported by /debug/status.
+func (s *Service) healthChecks() map[string]jimmhttp.HealthCheck {
+	return map[string]jimmhttp.HealthCheck{
+		"database": {
+			StatusCheck: jimmhttp.MakeStatusCheck("database", func(ctx context.Context) (interface{}, error) {
+				v, err := s.jimm.Database.Ping(ctx)
+				if v == nil {
+					return nil, err
+				}
+				return fmt.Sprintf("%d.%d", v.Major, v.Minor), err
+			}),
+			Critical: true,
+		},
+		"openfga": {
+			StatusCheck: jimmhttp.MakeStatusCheck("openfga", func(ctx context.Context) (interface{}, error) {
+				return nil, s.jimm.OpenFGAClient.Ping(ctx)
+			}),
+			Critical: true,
+		},
+		"credential_store": {
+			StatusCheck: jimmhttp.MakeStatusCheck("credential store", func(ctx context.Context) (interface{}, error) {
+				// The JWKS expiry is read as it is present in
+				// every credential store implementation. A store
+				// that has not yet got a JWKS is still reachable.
+				expiry, err := s.jimm.CredentialStore.GetJWKSExpiry(ctx)
+				if errors.ErrorCode(err) == errors.CodeNotFound {
+					return nil, nil
+				}
+				if err != nil {
+					return nil, err
+				}
+				return expiry, nil
+			}),
+			Critical: true,
+		},
+		"jwks": {
+			StatusCheck: jimmhttp.MakeStatusCheck("JWKS", func(ctx context.Context) (interface{}, error) {
+				ks, err := s.jimm.CredentialStore.GetJWKS(ctx)
+				if err != nil {
+					return nil, err
+				}
+				if ks.Len() == 0 {
+					return nil, errors.E("JWKS has no keys")
+				}
+				return ks.Len(), nil
+			}),
+			Critical: true,
+		},
+		"model_summaries": {
+			StatusCheck: jimmhttp.MakeStatusCheck("model summary watchers", s.modelSummaryStatus.Check),
+		},
+		"leader": {
+			StatusCheck: jimmhttp.MakeStatusCheck("leader", s.elector.Status),
+		},
+	}
+}
+
+// A backgroundJob is a long running routine started by StartServices.
+type backgroundJob struct {
+	// name is used when logging that the job has stopped.
+	name string
+
+	// leaderOnly jobs are only run on the elected leader replica. They
+	// are started when the replica is elected and stopped if leadership
+	// is lost. Other jobs run on every replica.
+	leaderOnly bool
+
+	// run runs the job until the given context is canceled.
+	run func(ctx context.Context) error
+}
+
+// backgroundJobs returns the jobs started by StartServices.
+func (s *Service) backgroundJobs() []backgroundJob {
+	jobs := []backgroundJob{{
+		// runs the periodic jobs, see Scheduler
+		name:       "scheduler",
+		leaderOnly: true,
+		run: func(ctx context.Context) error {
+			sched, err := s.Scheduler()
+			if err != nil {
 				return err
 			}
+			return sched.Run(ctx)
+		},
+	}, {
+		// validates and runs model upgrade campaigns
+		name:       "upgrade campaigns",
+		leaderOnly: true,
+		run: func(ctx context.Context) error {
+			return s.jimm.RunUpgradeCampaigns(ctx, 30*time.Second)
+		},
+	}, {
+		// delivers model lifecycle events to webhooks
+		name:       "webhook deliveries",
+		leaderOnly: true,
+		run: func(ctx context.Context) error {
+			return s.jimm.RunWebhookDeliveries(ctx, 10*time.Second)
+		},
+	}, {
+		// all units periodically update their controller/model metrics
+		name: "resource monitor",
+		run: func(ctx context.Context) error {
+			s.MonitorResources(ctx)
 			return nil
+		},
+	}, {
+		// only the leader watches the controllers for model summaries,
+		// so that each controller is watched once and each model event
+		// is recorded once.
+		name:       "model summary watcher",
+		leaderOnly: true,
+		run:        s.WatchModelSummaries,
+	}, {
+		// all units receive the model summaries
+		name: "model summary distribution",
+		run:  s.DistributeModelSummaries,
+	}, {
+		// all units evaluate the alert rules, each unit alerts on its
+		// own audit log failures and the leader alerts on the rest.
+		name: "alerting",
+		run: func(ctx context.Context) error {
+			alerter := jimm.Alerter{
+				JIMM:     s.jimm,
+				Replica:  s.elector.Holder,
+				IsLeader: s.elector.IsLeader,
+				SMTP:     s.alerting.SMTP,
+			}
+			return alerter.Run(ctx, s.alerting.Interval)
+		},
+	}}
+	if s.controllerPool != nil {
+		// all units maintain their own pool of controller connections
+		jobs = append(jobs, backgroundJob{
+			name: "controller pool maintenance",
+			run:  s.controllerPool.Run,
 		})
-
-		// OpenFGA cleanup - cleans up all orphaned tuples
-		svc.Go(func() error {
-			return s.OpenFGACleanup(ctx, time.NewTicker(6*time.Hour).C)
+	}
+	if s.inventory.Interval > 0 {
+		// periodically stores the inventory of every model
+		jobs = append(jobs, backgroundJob{
+			name:       "inventory collection",
+			leaderOnly: true,
+			run: func(ctx context.Context) error {
+				return s.jimm.CollectInventory(ctx, s.inventory)
+			},
 		})
+	}
+	return jobs
+}
 
-		// CleanupDyingModels cleanup - cleans up all dying models
+// StartServices starts the background jobs. Jobs that only run on the
+// leader are started whenever this replica is elected leader. Once the
+// jobs have been started /readyz stops reporting that the server is
+// starting.
+func (s *Service) StartServices(ctx context.Context, svc *service.Service) {
+	var leaderJobs []backgroundJob
+	for _, job := range s.backgroundJobs() {
+		if job.leaderOnly {
+			leaderJobs = append(leaderJobs, job)
+			continue
+		}
 		svc.Go(func() error {
-			return s.CleanupDyingModels(ctx, time.NewTicker(time.Minute).C)
+			return runBackgroundJob(ctx, job)
 		})
 	}
 
-	// all units periodically update their controller/model metrics
 	svc.Go(func() error {
-		s.MonitorResources(ctx)
-		return nil
+		return s.elector.Run(ctx, func(ctx context.Context) error {
+			return runBackgroundJobs(ctx, leaderJobs)
+		})
 	})
+	s.health.SetStarted()
+}
+
+// runBackgroundJobs runs all the given jobs until they have all stopped.
+// If any job fails the remaining jobs are stopped and the first error is
+// returned.
+func runBackgroundJobs(ctx context.Context, jobs []backgroundJob) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	var once sync.Once
+	var firstErr error
+	for _, job := range jobs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := runBackgroundJob(ctx, job); err != nil {
+				once.Do(func() {
+					firstErr = err
+					cancel()
+				})
+			}
+		}()
+	}
+	wg.Wait()
+	return firstErr
+}
+
+// runBackgroundJob runs the given job, logging any error that stops it.
+func runBackgroundJob(ctx context.Context, job backgroundJob) error {
+	err := job.run(ctx)
+	if err != nil && ctx.Err() == nil {
+		zapctx.Error(ctx, "background job stopped", zap.String("job", job.name), zap.Error(err))
+		return err
+	}
+	return nil
+}
 
-	// all units watch for model summaries
-	svc.Go(func() error { return s.WatchModelSummaries(ctx) })
+// newReplicaID generates an ID for this replica from the host name. A
+// random suffix is added so that a restarted replica starts a new term
+// of leadership.
+func newReplicaID() string {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "jimm"
+	}
+	return hostname + "-" + uuid.NewString()[:8]
 }
 
 // setupDischarger set JIMM up as a discharger of 3rd party caveats addressed to it. This is intended
@@ -652,7 +1018,11 @@ func newVaultStore(ctx context.Context, p Params) (jimmcreds.CredentialStore, er
 	}, nil
 }
 
-func newOpenFGAClient(ctx context.Context, p OpenFGAParams) (*openfga.OFGAClient, error) {
+// newOpenFGAClient returns a client for the OpenFGA store described by the
+// given parameters. The tuples in the store are migrated to the version of
+// the authorisation model supported by this JIMM, an error is returned if
+// the store has already been migrated to a newer version.
+func newOpenFGAClient(ctx context.Context, p OpenFGAParams, db *db.Database) (*openfga.OFGAClient, error) {
 	const op = errors.Op("newOpenFGAClient")
 	cofgaClient, err := cofga.NewClient(ctx, cofga.OpenFGAParams{
 		Scheme:      p.Scheme,
@@ -665,7 +1035,15 @@ func newOpenFGAClient(ctx context.Context, p OpenFGAParams) (*openfga.OFGAClient
 	if err != nil {
 		return nil, errors.E(op, err)
 	}
-	return openfga.NewOpenFGAClient(cofgaClient), nil
+	client := openfga.NewOpenFGAClient(cofgaClient)
+	client.EnableCache(p.CacheSize, p.CacheTTL)
+	err = db.UpdateAuthModelVersion(ctx, func(version int) (int, error) {
+		return openfga.MigrateAuthModel(ctx, client, version)
+	})
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	return client, nil
 }
 
 // ensureControllerAdministrators ensures that listed users have admin access to the JIMM controller.
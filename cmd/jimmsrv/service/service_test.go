@@ -4,6 +4,7 @@ package service_test
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -81,6 +82,56 @@ func TestDefaultService(t *testing.T) {
 	c.Check(resp.StatusCode, qt.Equals, http.StatusOK)
 }
 
+func TestHealthEndpoints(t *testing.T) {
+	c := qt.New(t)
+
+	ctx := context.Background()
+
+	_, _, cofgaParams, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+	p := newTestServiceParameters(c)
+	p.OpenFGAParams = cofgaParamsToJIMMOpenFGAParams(*cofgaParams)
+	p.InsecureSecretStorage = true
+	svc, err := jimmsvc.NewService(ctx, p)
+	c.Assert(err, qt.IsNil)
+	defer svc.Cleanup()
+
+	rr := httptest.NewRecorder()
+	req, err := http.NewRequest("GET", "/healthz", nil)
+	c.Assert(err, qt.IsNil)
+	svc.ServeHTTP(rr, req)
+	c.Check(rr.Code, qt.Equals, http.StatusOK)
+
+	// The background services have not been started, so the server
+	// reports that it is starting.
+	rr = httptest.NewRecorder()
+	req, err = http.NewRequest("GET", "/readyz", nil)
+	c.Assert(err, qt.IsNil)
+	svc.ServeHTTP(rr, req)
+	c.Check(rr.Code, qt.Equals, http.StatusServiceUnavailable)
+	var v struct {
+		Status string
+		Checks map[string]struct {
+			Critical bool
+			Passed   bool
+			Error    string
+		}
+	}
+	err = json.Unmarshal(rr.Body.Bytes(), &v)
+	c.Assert(err, qt.IsNil)
+	c.Check(v.Status, qt.Equals, "starting")
+	c.Check(v.Checks["database"].Passed, qt.IsTrue, qt.Commentf("%s", v.Checks["database"].Error))
+	c.Check(v.Checks["database"].Critical, qt.IsTrue)
+	c.Check(v.Checks["openfga"].Passed, qt.IsTrue, qt.Commentf("%s", v.Checks["openfga"].Error))
+	c.Check(v.Checks["credential_store"].Passed, qt.IsTrue, qt.Commentf("%s", v.Checks["credential_store"].Error))
+	// The JWKS is created when the service is created, so a new
+	// deployment can become ready without waiting for the scheduled
+	// rotation.
+	c.Check(v.Checks["jwks"].Passed, qt.IsTrue, qt.Commentf("%s", v.Checks["jwks"].Error))
+	c.Check(v.Checks["jwks"].Critical, qt.IsTrue)
+	c.Check(v.Checks["model_summaries"].Critical, qt.IsFalse)
+}
+
 func TestServiceDoesNotStartWithoutCredentialStore(t *testing.T) {
 	c := qt.New(t)
 
@@ -266,7 +317,7 @@ func TestOpenFGA(t *testing.T) {
 		}
 	})
 
-	client, err := jimmsvc.NewOpenFGAClient(context.Background(), p.OpenFGAParams)
+	client, err := jimmsvc.NewOpenFGAClient(context.Background(), p.OpenFGAParams, svc.JIMM().Database)
 	c.Assert(err, qt.IsNil)
 
 	// assert controller admins have been created in openfga
@@ -283,6 +334,35 @@ func TestOpenFGA(t *testing.T) {
 	}
 }
 
+func TestOpenFGAAuthModelVersion(t *testing.T) {
+	c := qt.New(t)
+
+	ctx := context.Background()
+
+	_, _, cofgaParams, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	p := newTestServiceParameters(c)
+	p.InsecureSecretStorage = true
+	p.OpenFGAParams = cofgaParamsToJIMMOpenFGAParams(*cofgaParams)
+	svc, err := jimmsvc.NewService(ctx, p)
+	c.Assert(err, qt.IsNil)
+	defer svc.Cleanup()
+
+	version, err := svc.JIMM().Database.GetAuthModelVersion(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Check(version, qt.Equals, openfga.AuthModelVersion)
+
+	// Simulate the store having been migrated by a newer JIMM.
+	err = svc.JIMM().Database.UpdateAuthModelVersion(ctx, func(int) (int, error) {
+		return openfga.AuthModelVersion + 1, nil
+	})
+	c.Assert(err, qt.IsNil)
+
+	_, err = jimmsvc.NewOpenFGAClient(ctx, p.OpenFGAParams, svc.JIMM().Database)
+	c.Check(err, qt.ErrorMatches, `authorisation model has incompatible version .*`)
+}
+
 func TestPublicKey(t *testing.T) {
 	c := qt.New(t)
 
@@ -598,3 +678,44 @@ func TestCORS(t *testing.T) {
 	c.Assert(response.Header.Get("Access-Control-Allow-Credentials"), qt.Equals, "true")
 	c.Assert(response.Header.Get("Access-Control-Allow-Origin"), qt.Equals, allowedOrigin)
 }
+
+func TestReload(t *testing.T) {
+	c := qt.New(t)
+
+	ctx := context.Background()
+
+	_, _, cofgaParams, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+	p := newTestServiceParameters(c)
+	p.OpenFGAParams = cofgaParamsToJIMMOpenFGAParams(*cofgaParams)
+	p.CorsAllowedOrigins = []string{"http://my-referrer.com"}
+	p.InsecureSecretStorage = true
+
+	svc, err := jimmsvc.NewService(ctx, p)
+	c.Assert(err, qt.IsNil)
+	defer svc.Cleanup()
+
+	newOrigin := "http://other-referrer.com"
+	rr := httptest.NewRecorder()
+	req, err := http.NewRequest("GET", "/debug/info", nil)
+	c.Assert(err, qt.IsNil)
+	req.Header.Set("Origin", newOrigin)
+	svc.ServeHTTP(rr, req)
+	c.Check(rr.Header().Get("Access-Control-Allow-Origin"), qt.Equals, "")
+
+	// An invalid reload changes nothing.
+	p.CorsAllowedOrigins = []string{newOrigin}
+	p.AuditLogRetentionPeriodInDays = "-1"
+	err = svc.Reload(ctx, p)
+	c.Check(err, qt.ErrorMatches, `retention period cannot be less than 0`)
+	rr = httptest.NewRecorder()
+	svc.ServeHTTP(rr, req)
+	c.Check(rr.Header().Get("Access-Control-Allow-Origin"), qt.Equals, "")
+
+	p.AuditLogRetentionPeriodInDays = "7"
+	err = svc.Reload(ctx, p)
+	c.Assert(err, qt.IsNil)
+	rr = httptest.NewRecorder()
+	svc.ServeHTTP(rr, req)
+	c.Check(rr.Header().Get("Access-Control-Allow-Origin"), qt.Equals, newOrigin)
+}
@@ -0,0 +1,387 @@
+// Copyright 2024 Canonical.
+
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+// AddAlertReceiver stores the given alert receiver. If a alert receiver with the same name
+// already exists an error with a code of CodeAlreadyExists is returned.
+func (d *Database) AddAlertReceiver(ctx context.Context, r *dbmodel.AlertReceiver) (err error) {
+	const op = errors.Op("db.AddAlertReceiver")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	db := d.DB.WithContext(ctx)
+	if err := db.Create(r).Error; err != nil {
+		err = dbError(err)
+		if errors.ErrorCode(err) == errors.CodeAlreadyExists {
+			return errors.E(op, err, "alert receiver already exists")
+		}
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// GetAlertReceiver fills in the given alert receiver using its name. If there is
+// no such alert receiver an error with a code of CodeNotFound is returned.
+func (d *Database) GetAlertReceiver(ctx context.Context, r *dbmodel.AlertReceiver) (err error) {
+	const op = errors.Op("db.GetAlertReceiver")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	db := d.DB.WithContext(ctx)
+	if err := db.Where("name = ?", r.Name).First(r).Error; err != nil {
+		err = dbError(err)
+		if errors.ErrorCode(err) == errors.CodeNotFound {
+			return errors.E(op, err, "alert receiver not found")
+		}
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// ListAlertReceivers returns all the alert receivers, ordered by name.
+func (d *Database) ListAlertReceivers(ctx context.Context) (_ []dbmodel.AlertReceiver, err error) {
+	const op = errors.Op("db.ListAlertReceivers")
+	if err := d.ready(); err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	db := d.DB.WithContext(ctx)
+	var result []dbmodel.AlertReceiver
+	if err := db.Order("name").Find(&result).Error; err != nil {
+		return nil, errors.E(op, dbError(err))
+	}
+	return result, nil
+}
+
+// DeleteAlertReceiver removes the named alert receiver. If there is no such
+// alert receiver an error with a code of CodeNotFound is returned.
+func (d *Database) DeleteAlertReceiver(ctx context.Context, r *dbmodel.AlertReceiver) (err error) {
+	const op = errors.Op("db.DeleteAlertReceiver")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	db := d.DB.WithContext(ctx)
+	result := db.Where("name = ?", r.Name).Delete(&dbmodel.AlertReceiver{})
+	if result.Error != nil {
+		return errors.E(op, dbError(result.Error))
+	}
+	if result.RowsAffected == 0 {
+		return errors.E(op, errors.CodeNotFound, "alert receiver not found")
+	}
+	return nil
+}
+
+// AddAlertRule stores the given alert rule. If a alert rule with the same name
+// already exists an error with a code of CodeAlreadyExists is returned.
+func (d *Database) AddAlertRule(ctx context.Context, r *dbmodel.AlertRule) (err error) {
+	const op = errors.Op("db.AddAlertRule")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	db := d.DB.WithContext(ctx)
+	if err := db.Create(r).Error; err != nil {
+		err = dbError(err)
+		if errors.ErrorCode(err) == errors.CodeAlreadyExists {
+			return errors.E(op, err, "alert rule already exists")
+		}
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// GetAlertRule fills in the given alert rule using its name. If there is
+// no such alert rule an error with a code of CodeNotFound is returned.
+func (d *Database) GetAlertRule(ctx context.Context, r *dbmodel.AlertRule) (err error) {
+	const op = errors.Op("db.GetAlertRule")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	db := d.DB.WithContext(ctx)
+	if err := db.Where("name = ?", r.Name).First(r).Error; err != nil {
+		err = dbError(err)
+		if errors.ErrorCode(err) == errors.CodeNotFound {
+			return errors.E(op, err, "alert rule not found")
+		}
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// ListAlertRules returns all the alert rules, ordered by name.
+func (d *Database) ListAlertRules(ctx context.Context) (_ []dbmodel.AlertRule, err error) {
+	const op = errors.Op("db.ListAlertRules")
+	if err := d.ready(); err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	db := d.DB.WithContext(ctx)
+	var result []dbmodel.AlertRule
+	if err := db.Order("name").Find(&result).Error; err != nil {
+		return nil, errors.E(op, dbError(err))
+	}
+	return result, nil
+}
+
+// DeleteAlertRule removes the named alert rule. If there is no such
+// alert rule an error with a code of CodeNotFound is returned.
+func (d *Database) DeleteAlertRule(ctx context.Context, r *dbmodel.AlertRule) (err error) {
+	const op = errors.Op("db.DeleteAlertRule")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	db := d.DB.WithContext(ctx)
+	result := db.Where("name = ?", r.Name).Delete(&dbmodel.AlertRule{})
+	if result.Error != nil {
+		return errors.E(op, dbError(result.Error))
+	}
+	if result.RowsAffected == 0 {
+		return errors.E(op, errors.CodeNotFound, "alert rule not found")
+	}
+	return nil
+}
+
+// ListAlerts returns all the alerts, along with the rules that raised
+// them, ordered by the time they became active.
+func (d *Database) ListAlerts(ctx context.Context) (_ []dbmodel.Alert, err error) {
+	const op = errors.Op("db.ListAlerts")
+	if err := d.ready(); err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	db := d.DB.WithContext(ctx)
+	var alerts []dbmodel.Alert
+	if err := db.Preload("AlertRule").Order("active_at, id").Find(&alerts).Error; err != nil {
+		return nil, errors.E(op, dbError(err))
+	}
+	return alerts, nil
+}
+
+// AddAlert stores the given alert. If the rule has already raised an
+// alert about the same subject an error with a code of CodeAlreadyExists
+// is returned.
+func (d *Database) AddAlert(ctx context.Context, a *dbmodel.Alert) (err error) {
+	const op = errors.Op("db.AddAlert")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	db := d.DB.WithContext(ctx)
+	if err := db.Omit("AlertRule").Create(a).Error; err != nil {
+		err = dbError(err)
+		if errors.ErrorCode(err) == errors.CodeAlreadyExists {
+			return errors.E(op, err, "alert already exists")
+		}
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// UpdateAlert updates the summary, labels, firing time and notification
+// time of the given alert, marking it as updated now. If there is no such
+// alert an error with a code of CodeNotFound is returned.
+func (d *Database) UpdateAlert(ctx context.Context, a *dbmodel.Alert) (err error) {
+	const op = errors.Op("db.UpdateAlert")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	db := d.DB.WithContext(ctx)
+	result := db.Model(a).Omit("AlertRule").Select("updated_at", "summary", "labels", "firing_at", "notified_at").Updates(a)
+	if result.Error != nil {
+		return errors.E(op, dbError(result.Error))
+	}
+	if result.RowsAffected == 0 {
+		return errors.E(op, errors.CodeNotFound, "alert not found")
+	}
+	return nil
+}
+
+// DeleteAlert removes the given alert.
+func (d *Database) DeleteAlert(ctx context.Context, a *dbmodel.Alert) (err error) {
+	const op = errors.Op("db.DeleteAlert")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	db := d.DB.WithContext(ctx)
+	if err := db.Delete(&dbmodel.Alert{}, a.ID).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// AddAlertSilence stores the given alert silence.
+func (d *Database) AddAlertSilence(ctx context.Context, s *dbmodel.AlertSilence) (err error) {
+	const op = errors.Op("db.AddAlertSilence")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	db := d.DB.WithContext(ctx)
+	if err := db.Create(s).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// ListAlertSilences returns the alert silences that end after the given
+// time, ordered by the time they start.
+func (d *Database) ListAlertSilences(ctx context.Context, after time.Time) (_ []dbmodel.AlertSilence, err error) {
+	const op = errors.Op("db.ListAlertSilences")
+	if err := d.ready(); err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	db := d.DB.WithContext(ctx)
+	var silences []dbmodel.AlertSilence
+	if err := db.Where("ends_at > ?", after).Order("starts_at, id").Find(&silences).Error; err != nil {
+		return nil, errors.E(op, dbError(err))
+	}
+	return silences, nil
+}
+
+// DeleteAlertSilence removes the alert silence with the ID of the given
+// silence. If there is no such silence an error with a code of
+// CodeNotFound is returned.
+func (d *Database) DeleteAlertSilence(ctx context.Context, s *dbmodel.AlertSilence) (err error) {
+	const op = errors.Op("db.DeleteAlertSilence")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	db := d.DB.WithContext(ctx)
+	result := db.Delete(&dbmodel.AlertSilence{}, s.ID)
+	if result.Error != nil {
+		return errors.E(op, dbError(result.Error))
+	}
+	if result.RowsAffected == 0 {
+		return errors.E(op, errors.CodeNotFound, "alert silence not found")
+	}
+	return nil
+}
+
+// DeleteExpiredAlertSilences removes the alert silences that ended
+// before the given time.
+func (d *Database) DeleteExpiredAlertSilences(ctx context.Context, before time.Time) (err error) {
+	const op = errors.Op("db.DeleteExpiredAlertSilences")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	db := d.DB.WithContext(ctx)
+	if err := db.Where("ends_at < ?", before).Delete(&dbmodel.AlertSilence{}).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
@@ -0,0 +1,187 @@
+// Copyright 2024 Canonical.
+
+package db_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+func TestAddAlertRuleUnconfiguredDatabase(t *testing.T) {
+	c := qt.New(t)
+
+	var d db.Database
+	err := d.AddAlertRule(context.Background(), &dbmodel.AlertRule{})
+	c.Check(err, qt.ErrorMatches, `database not configured`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeServerConfiguration)
+}
+
+func (s *dbSuite) TestAlertReceiversAndRules(c *qt.C) {
+	ctx := context.Background()
+	err := s.Database.Migrate(ctx, true)
+	c.Assert(err, qt.IsNil)
+
+	r1 := dbmodel.AlertReceiver{
+		Name:   "ops-mail",
+		Type:   dbmodel.AlertReceiverEmail,
+		Emails: dbmodel.Strings{"ops@example.com"},
+	}
+	err = s.Database.AddAlertReceiver(ctx, &r1)
+	c.Assert(err, qt.IsNil)
+	r2 := dbmodel.AlertReceiver{
+		Name:   "ops-hook",
+		Type:   dbmodel.AlertReceiverWebhook,
+		URL:    "https://ops.example.com/hook",
+		Secret: "secret",
+	}
+	err = s.Database.AddAlertReceiver(ctx, &r2)
+	c.Assert(err, qt.IsNil)
+
+	err = s.Database.AddAlertReceiver(ctx, &dbmodel.AlertReceiver{Name: "ops-mail", Type: dbmodel.AlertReceiverEmail})
+	c.Check(err, qt.ErrorMatches, `alert receiver already exists`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeAlreadyExists)
+
+	receivers, err := s.Database.ListAlertReceivers(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Assert(receivers, qt.HasLen, 2)
+	c.Check(receivers[0].Name, qt.Equals, "ops-hook")
+	c.Check(receivers[0].Secret, qt.Equals, "secret")
+	c.Check(receivers[1].Emails, qt.DeepEquals, dbmodel.Strings{"ops@example.com"})
+
+	rule := dbmodel.AlertRule{
+		Name:           "controllers",
+		Kind:           dbmodel.AlertKindControllerUnavailable,
+		For:            5 * time.Minute,
+		RepeatInterval: time.Hour,
+		Severity:       "critical",
+		Receivers:      dbmodel.Strings{"ops-mail", "ops-hook"},
+	}
+	err = s.Database.AddAlertRule(ctx, &rule)
+	c.Assert(err, qt.IsNil)
+
+	err = s.Database.AddAlertRule(ctx, &dbmodel.AlertRule{Name: "controllers", Kind: dbmodel.AlertKindModelError})
+	c.Check(err, qt.ErrorMatches, `alert rule already exists`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeAlreadyExists)
+
+	r := dbmodel.AlertRule{Name: "controllers"}
+	err = s.Database.GetAlertRule(ctx, &r)
+	c.Assert(err, qt.IsNil)
+	c.Check(r.For, qt.Equals, 5*time.Minute)
+	c.Check(r.RepeatInterval, qt.Equals, time.Hour)
+	c.Check(r.Receivers, qt.DeepEquals, dbmodel.Strings{"ops-mail", "ops-hook"})
+
+	err = s.Database.GetAlertRule(ctx, &dbmodel.AlertRule{Name: "no-such-rule"})
+	c.Check(err, qt.ErrorMatches, `alert rule not found`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	err = s.Database.DeleteAlertReceiver(ctx, &dbmodel.AlertReceiver{Name: "ops-hook"})
+	c.Assert(err, qt.IsNil)
+	err = s.Database.DeleteAlertReceiver(ctx, &dbmodel.AlertReceiver{Name: "ops-hook"})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	err = s.Database.DeleteAlertRule(ctx, &dbmodel.AlertRule{Name: "controllers"})
+	c.Assert(err, qt.IsNil)
+	rules, err := s.Database.ListAlertRules(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Check(rules, qt.HasLen, 0)
+}
+
+func (s *dbSuite) TestAlerts(c *qt.C) {
+	ctx := context.Background()
+	err := s.Database.Migrate(ctx, true)
+	c.Assert(err, qt.IsNil)
+
+	rule := dbmodel.AlertRule{
+		Name: "models-in-error",
+		Kind: dbmodel.AlertKindModelError,
+	}
+	err = s.Database.AddAlertRule(ctx, &rule)
+	c.Assert(err, qt.IsNil)
+
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	alert := dbmodel.Alert{
+		AlertRuleID: rule.ID,
+		AlertRule:   rule,
+		Subject:     "00000002-0000-0000-0000-000000000001",
+		Summary:     "model model-1 is in error",
+		Labels:      dbmodel.StringMap{"model_name": "model-1"},
+		ActiveAt:    now,
+	}
+	err = s.Database.AddAlert(ctx, &alert)
+	c.Assert(err, qt.IsNil)
+
+	err = s.Database.AddAlert(ctx, &dbmodel.Alert{AlertRuleID: rule.ID, Subject: alert.Subject, ActiveAt: now})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeAlreadyExists)
+
+	alert.FiringAt = sql.NullTime{Time: now, Valid: true}
+	alert.Summary = "model model-1 is in error: broken"
+	err = s.Database.UpdateAlert(ctx, &alert)
+	c.Assert(err, qt.IsNil)
+
+	alerts, err := s.Database.ListAlerts(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Assert(alerts, qt.HasLen, 1)
+	c.Check(alerts[0].AlertRule.Name, qt.Equals, "models-in-error")
+	c.Check(alerts[0].Summary, qt.Equals, "model model-1 is in error: broken")
+	c.Check(alerts[0].Labels, qt.DeepEquals, dbmodel.StringMap{"model_name": "model-1"})
+	c.Check(alerts[0].FiringAt.Time.Equal(now), qt.IsTrue)
+	c.Check(alerts[0].NotifiedAt.Valid, qt.IsFalse)
+
+	// Removing the rule removes its alerts.
+	err = s.Database.DeleteAlertRule(ctx, &rule)
+	c.Assert(err, qt.IsNil)
+	alerts, err = s.Database.ListAlerts(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Check(alerts, qt.HasLen, 0)
+}
+
+func (s *dbSuite) TestAlertSilences(c *qt.C) {
+	ctx := context.Background()
+	err := s.Database.Migrate(ctx, true)
+	c.Assert(err, qt.IsNil)
+
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	s1 := dbmodel.AlertSilence{
+		Rule:      "models-in-error",
+		StartsAt:  now.Add(-2 * time.Hour),
+		EndsAt:    now.Add(-time.Hour),
+		CreatedBy: "alice@canonical.com",
+	}
+	err = s.Database.AddAlertSilence(ctx, &s1)
+	c.Assert(err, qt.IsNil)
+	s2 := dbmodel.AlertSilence{
+		Subject:   "controller-1",
+		StartsAt:  now,
+		EndsAt:    now.Add(time.Hour),
+		CreatedBy: "alice@canonical.com",
+		Comment:   "maintenance",
+	}
+	err = s.Database.AddAlertSilence(ctx, &s2)
+	c.Assert(err, qt.IsNil)
+
+	silences, err := s.Database.ListAlertSilences(ctx, now)
+	c.Assert(err, qt.IsNil)
+	c.Assert(silences, qt.HasLen, 1)
+	c.Check(silences[0].ID, qt.Equals, s2.ID)
+	c.Check(silences[0].Comment, qt.Equals, "maintenance")
+
+	err = s.Database.DeleteExpiredAlertSilences(ctx, now)
+	c.Assert(err, qt.IsNil)
+	silences, err = s.Database.ListAlertSilences(ctx, now.Add(-24*time.Hour))
+	c.Assert(err, qt.IsNil)
+	c.Check(silences, qt.HasLen, 1)
+
+	err = s.Database.DeleteAlertSilence(ctx, &dbmodel.AlertSilence{ID: s2.ID})
+	c.Assert(err, qt.IsNil)
+	err = s.Database.DeleteAlertSilence(ctx, &dbmodel.AlertSilence{ID: s2.ID})
+	c.Check(err, qt.ErrorMatches, `alert silence not found`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+}
@@ -21,6 +21,8 @@ func (d *Database) AddApplicationOffer(ctx context.Context, offer *dbmodel.Appli
 	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
 	defer durationObserver()
 	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
 
 	db := d.DB.WithContext(ctx)
 
@@ -43,6 +45,8 @@ func (d *Database) GetApplicationOffer(ctx context.Context, offer *dbmodel.Appli
 	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
 	defer durationObserver()
 	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
 	db := d.DB.WithContext(ctx)
 
 	switch {
@@ -76,6 +80,8 @@ func (d *Database) DeleteApplicationOffer(ctx context.Context, offer *dbmodel.Ap
 	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
 	defer durationObserver()
 	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
 
 	db := d.DB.WithContext(ctx)
 
@@ -100,6 +106,8 @@ func (d *Database) FindApplicationOffersByModel(ctx context.Context, modelName,
 	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
 	defer durationObserver()
 	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
 
 	db := d.DB.WithContext(ctx)
 	db = db.Table("application_offers AS offers")
@@ -125,3 +133,50 @@ func (d *Database) FindApplicationOffersByModel(ctx context.Context, modelName,
 
 	return offers, nil
 }
+
+// AddApplicationOfferConsumeEvent records that an application offer has
+// been consumed.
+func (d *Database) AddApplicationOfferConsumeEvent(ctx context.Context, event *dbmodel.ApplicationOfferConsumeEvent) (err error) {
+	const op = errors.Op("db.AddApplicationOfferConsumeEvent")
+
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	db := d.DB.WithContext(ctx)
+
+	if err := db.Omit("ApplicationOffer").Create(event).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// ListApplicationOfferConsumeEvents returns the recorded consume events of
+// the given application offer, most recent first.
+func (d *Database) ListApplicationOfferConsumeEvents(ctx context.Context, offer *dbmodel.ApplicationOffer) (_ []dbmodel.ApplicationOfferConsumeEvent, err error) {
+	const op = errors.Op("db.ListApplicationOfferConsumeEvents")
+
+	if err := d.ready(); err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	db := d.DB.WithContext(ctx)
+
+	var events []dbmodel.ApplicationOfferConsumeEvent
+	if err := db.Where("application_offer_id = ?", offer.ID).Order("time DESC").Order("id DESC").Find(&events).Error; err != nil {
+		return nil, errors.E(op, dbError(err))
+	}
+	return events, nil
+}
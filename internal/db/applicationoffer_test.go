@@ -7,6 +7,7 @@ import (
 	"database/sql"
 	"sort"
 	"testing"
+	"time"
 
 	qt "github.com/frankban/quicktest"
 	"github.com/google/go-cmp/cmp/cmpopts"
@@ -220,3 +221,63 @@ func (s *dbSuite) TestFindApplicationOffersByModel(c *qt.C) {
 	c.Assert(err, qt.IsNil)
 	c.Assert(offers, qt.HasLen, 0)
 }
+
+func TestAddApplicationOfferConsumeEventUnconfiguredDatabase(t *testing.T) {
+	c := qt.New(t)
+	var d db.Database
+
+	err := d.AddApplicationOfferConsumeEvent(context.Background(), &dbmodel.ApplicationOfferConsumeEvent{})
+	c.Check(err, qt.ErrorMatches, `database not configured`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeServerConfiguration)
+}
+
+func (s *dbSuite) TestApplicationOfferConsumeEvents(c *qt.C) {
+	ctx := context.Background()
+	env := initTestEnvironment(c, s.Database)
+
+	offer := dbmodel.ApplicationOffer{
+		Name:    "offer1",
+		UUID:    "00000000-0000-0000-0000-000000000001",
+		URL:     "bob@canonical.com/test-model.offer1",
+		ModelID: env.model.ID,
+	}
+	err := s.Database.AddApplicationOffer(ctx, &offer)
+	c.Assert(err, qt.IsNil)
+
+	events, err := s.Database.ListApplicationOfferConsumeEvents(ctx, &offer)
+	c.Assert(err, qt.IsNil)
+	c.Check(events, qt.HasLen, 0)
+
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	event1 := dbmodel.ApplicationOfferConsumeEvent{
+		Time:               now.Add(-time.Hour),
+		ApplicationOfferID: offer.ID,
+		IdentityName:       "alice@canonical.com",
+		ModelUUID:          env.model1.UUID.String,
+	}
+	err = s.Database.AddApplicationOfferConsumeEvent(ctx, &event1)
+	c.Assert(err, qt.IsNil)
+	event2 := dbmodel.ApplicationOfferConsumeEvent{
+		Time:               now,
+		ApplicationOfferID: offer.ID,
+		IdentityName:       "bob@canonical.com",
+		ModelUUID:          env.model1.UUID.String,
+	}
+	err = s.Database.AddApplicationOfferConsumeEvent(ctx, &event2)
+	c.Assert(err, qt.IsNil)
+
+	events, err = s.Database.ListApplicationOfferConsumeEvents(ctx, &offer)
+	c.Assert(err, qt.IsNil)
+	c.Assert(events, qt.HasLen, 2)
+	c.Check(events[0].ID, qt.Equals, event2.ID)
+	c.Check(events[0].IdentityName, qt.Equals, "bob@canonical.com")
+	c.Check(events[0].Time.Equal(now), qt.IsTrue)
+	c.Check(events[1].ID, qt.Equals, event1.ID)
+
+	// Deleting the offer removes its consume events.
+	err = s.Database.DeleteApplicationOffer(ctx, &offer)
+	c.Assert(err, qt.IsNil)
+	events, err = s.Database.ListApplicationOfferConsumeEvents(ctx, &offer)
+	c.Assert(err, qt.IsNil)
+	c.Check(events, qt.HasLen, 0)
+}
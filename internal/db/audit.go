@@ -22,6 +22,8 @@ func (d *Database) AddAuditLogEntry(ctx context.Context, ale *dbmodel.AuditLogEn
 	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
 	defer durationObserver()
 	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
 
 	if err := d.DB.WithContext(ctx).Create(ale).Error; err != nil {
 		return errors.E(op, dbError(err))
@@ -78,6 +80,8 @@ func (d *Database) ForEachAuditLogEntry(ctx context.Context, filter AuditLogFilt
 	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
 	defer durationObserver()
 	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
 
 	db := d.DB.WithContext(ctx).Model(&dbmodel.AuditLogEntry{})
 	if !filter.Start.IsZero() {
@@ -137,6 +141,8 @@ func (d *Database) DeleteAuditLogsBefore(ctx context.Context, before time.Time)
 	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
 	defer durationObserver()
 	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
 
 	tx := d.DB.
 		WithContext(ctx).
@@ -0,0 +1,101 @@
+// Copyright 2024 Canonical.
+
+package db
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+// GetAuthModelVersion returns the version of the OpenFGA authorisation
+// model recorded in the database. If no version has been recorded 0 is
+// returned.
+func (d *Database) GetAuthModelVersion(ctx context.Context) (_ int, err error) {
+	const op = errors.Op("db.GetAuthModelVersion")
+	if err := d.ready(); err != nil {
+		return 0, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	var versions []dbmodel.Version
+	db := d.DB.WithContext(ctx)
+	if err := db.Where("component = ?", dbmodel.AuthModelComponent).Limit(1).Find(&versions).Error; err != nil {
+		return 0, errors.E(op, dbError(err))
+	}
+	if len(versions) == 0 {
+		return 0, nil
+	}
+	return versions[0].Major, nil
+}
+
+// authModelLockID is the key of the PostgreSQL advisory lock held while
+// the OpenFGA authorisation model is updated.
+const authModelLockID = 0x6a696d6d61757468
+
+// UpdateAuthModelVersion calls the given function with the version of the
+// OpenFGA authorisation model recorded in the database, or 0 if no
+// version has been recorded, and then records the version returned by the
+// function. A session advisory lock is held for the duration of the call
+// so that only one JIMM instance can update the authorisation model at a
+// time, without holding a transaction open while the function runs. If
+// the function returns an error the recorded version is not changed.
+func (d *Database) UpdateAuthModelVersion(ctx context.Context, f func(version int) (int, error)) (err error) {
+	const op = errors.Op("db.UpdateAuthModelVersion")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	// Advisory locks are held by the database session, so every query
+	// must use the same connection.
+	err = d.DB.WithContext(ctx).Connection(func(db *gorm.DB) (err error) {
+		if err := db.Exec("SELECT pg_advisory_lock(?)", authModelLockID).Error; err != nil {
+			return dbError(err)
+		}
+		defer func() {
+			// The lock is released even if the context has been
+			// canceled, otherwise it would be kept by the pooled
+			// connection.
+			uerr := db.WithContext(context.WithoutCancel(ctx)).Exec("SELECT pg_advisory_unlock(?)", authModelLockID).Error
+			if uerr != nil && err == nil {
+				err = dbError(uerr)
+			}
+		}()
+
+		v := dbmodel.Version{Component: dbmodel.AuthModelComponent}
+		if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&v).Error; err != nil {
+			return dbError(err)
+		}
+		if err := db.First(&v).Error; err != nil {
+			return dbError(err)
+		}
+		version, err := f(v.Major)
+		if err != nil {
+			return err
+		}
+		if err := db.Model(&v).Update("major", version).Error; err != nil {
+			return dbError(err)
+		}
+		return nil
+	})
+	if err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+// Copyright 2024 Canonical.
+
+package db_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+func TestGetAuthModelVersionUnconfiguredDatabase(t *testing.T) {
+	c := qt.New(t)
+
+	var d db.Database
+	_, err := d.GetAuthModelVersion(context.Background())
+	c.Check(err, qt.ErrorMatches, `database not configured`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeServerConfiguration)
+}
+
+func (s *dbSuite) TestUpdateAuthModelVersion(c *qt.C) {
+	ctx := context.Background()
+
+	err := s.Database.UpdateAuthModelVersion(ctx, func(int) (int, error) {
+		return 0, errors.E("unexpected function call")
+	})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUpgradeInProgress)
+
+	err = s.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	v, err := s.Database.GetAuthModelVersion(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Check(v, qt.Equals, 0)
+
+	err = s.Database.UpdateAuthModelVersion(ctx, func(v int) (int, error) {
+		c.Check(v, qt.Equals, 0)
+		return 2, nil
+	})
+	c.Assert(err, qt.IsNil)
+
+	v, err = s.Database.GetAuthModelVersion(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Check(v, qt.Equals, 2)
+
+	// A failed update leaves the version unchanged.
+	err = s.Database.UpdateAuthModelVersion(ctx, func(v int) (int, error) {
+		c.Check(v, qt.Equals, 2)
+		return 3, errors.E("test error")
+	})
+	c.Check(err, qt.ErrorMatches, `test error`)
+
+	v, err = s.Database.GetAuthModelVersion(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Check(v, qt.Equals, 2)
+
+	// Concurrent updates are serialised.
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := s.Database.UpdateAuthModelVersion(ctx, func(v int) (int, error) {
+				time.Sleep(10 * time.Millisecond)
+				return v + 1, nil
+			})
+			c.Check(err, qt.IsNil)
+		}()
+	}
+	wg.Wait()
+
+	v, err = s.Database.GetAuthModelVersion(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Check(v, qt.Equals, 7)
+}
@@ -25,6 +25,8 @@ func (d *Database) AddCloud(ctx context.Context, c *dbmodel.Cloud) (err error) {
 	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
 	defer durationObserver()
 	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
 
 	db := d.DB.WithContext(ctx)
 	if err := db.Create(c).Error; err != nil {
@@ -49,6 +51,8 @@ func (d *Database) GetCloud(ctx context.Context, c *dbmodel.Cloud) (err error) {
 	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
 	defer durationObserver()
 	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
 
 	db := d.DB.WithContext(ctx)
 	db = db.Where("name = ?", c.Name)
@@ -73,6 +77,8 @@ func (d *Database) GetClouds(ctx context.Context) (_ []dbmodel.Cloud, err error)
 	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
 	defer durationObserver()
 	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
 
 	var clouds []dbmodel.Cloud
 	db := d.DB.WithContext(ctx)
@@ -95,6 +101,8 @@ func (d *Database) UpdateCloud(ctx context.Context, c *dbmodel.Cloud) (err error
 	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
 	defer durationObserver()
 	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
 
 	err = d.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		if err := tx.Save(c).Error; err != nil {
@@ -140,6 +148,8 @@ func (d *Database) AddCloudRegion(ctx context.Context, cr *dbmodel.CloudRegion)
 	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
 	defer durationObserver()
 	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
 
 	db := d.DB.WithContext(ctx)
 	if err := db.Create(cr).Error; err != nil {
@@ -163,6 +173,8 @@ func (d *Database) FindRegionByCloudType(ctx context.Context, providerType, regi
 	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
 	defer durationObserver()
 	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
 
 	db := d.DB.WithContext(ctx)
 	db = db.Preload("Cloud").Preload("Controllers").Preload("Controllers.Controller")
@@ -186,6 +198,8 @@ func (d *Database) FindRegionByCloudName(ctx context.Context, cloudName, regionN
 	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
 	defer durationObserver()
 	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
 
 	db := d.DB.WithContext(ctx)
 	db = db.Preload("Cloud").Preload("Controllers").Preload("Controllers.Controller")
@@ -209,6 +223,8 @@ func (d *Database) DeleteCloud(ctx context.Context, c *dbmodel.Cloud) (err error
 	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
 	defer durationObserver()
 	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
 
 	db := d.DB.WithContext(ctx)
 	if err := db.Delete(c).Error; err != nil {
@@ -228,6 +244,8 @@ func (d *Database) DeleteCloudRegionControllerPriority(ctx context.Context, c *d
 	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
 	defer durationObserver()
 	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
 
 	db := d.DB.WithContext(ctx)
 	if err := db.Delete(c).Error; err != nil {
@@ -23,6 +23,8 @@ func (d *Database) SetCloudCredential(ctx context.Context, cred *dbmodel.CloudCr
 	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
 	defer durationObserver()
 	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
 
 	if cred.CloudName == "" || cred.OwnerIdentityName == "" || cred.Name == "" {
 		return errors.E(op, errors.CodeBadRequest, fmt.Sprintf("invalid cloudcredential tag %q", cred.CloudName+"/"+cred.OwnerIdentityName+"/"+cred.Name))
@@ -53,6 +55,8 @@ func (d *Database) GetCloudCredential(ctx context.Context, cred *dbmodel.CloudCr
 	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
 	defer durationObserver()
 	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
 
 	if cred.CloudName == "" || cred.OwnerIdentityName == "" || cred.Name == "" {
 		return errors.E(op, errors.CodeNotFound, fmt.Sprintf("cloudcredential %q not found", cred.CloudName+"/"+cred.OwnerIdentityName+"/"+cred.Name))
@@ -84,6 +88,8 @@ func (d *Database) ForEachCloudCredential(ctx context.Context, identityName, clo
 	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
 	defer durationObserver()
 	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
 
 	db := d.DB.WithContext(ctx)
 	mdb := db.Model(dbmodel.CloudCredential{})
@@ -127,6 +133,8 @@ func (d *Database) DeleteCloudCredential(ctx context.Context, cred *dbmodel.Clou
 	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
 	defer durationObserver()
 	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
 
 	db := d.DB.WithContext(ctx)
 	if err := db.Delete(cred).Error; err != nil {
@@ -135,3 +143,26 @@ func (d *Database) DeleteCloudCredential(ctx context.Context, cred *dbmodel.Clou
 	}
 	return nil
 }
+
+// ListInvalidCloudCredentials returns the cloud credentials that are known
+// to be invalid. The credentials' attributes are not loaded.
+func (d *Database) ListInvalidCloudCredentials(ctx context.Context) (_ []dbmodel.CloudCredential, err error) {
+	const op = errors.Op("db.ListInvalidCloudCredentials")
+
+	if err := d.ready(); err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	db := d.DB.WithContext(ctx)
+	var creds []dbmodel.CloudCredential
+	if err := db.Where("valid = ?", false).Order("cloud_name, owner_identity_name, name").Find(&creds).Error; err != nil {
+		return nil, errors.E(op, dbError(err))
+	}
+	return creds, nil
+}
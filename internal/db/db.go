@@ -15,6 +15,7 @@ import (
 
 	"github.com/canonical/jimm/v3/internal/dbmodel"
 	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/servermon"
 )
 
 // A Database provides access to the database model. A Database instance
@@ -38,10 +39,20 @@ type Database struct {
 //
 // Attempting to start a transaction on an unmigrated database will result
 // in an error with a code of errors.CodeUpgradeInProgress.
-func (d *Database) Transaction(f func(*Database) error) error {
+func (d *Database) Transaction(f func(*Database) error) (err error) {
 	if err := d.ready(); err != nil {
 		return err
 	}
+
+	// The span is started from the context of the database, if it has
+	// one, as the transaction is not given a context.
+	ctx := d.DB.Statement.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	_, span := servermon.StartSpan(ctx, "db.Transaction")
+	defer servermon.EndSpan(span, &err)
+
 	return d.DB.Transaction(func(tx *gorm.DB) error {
 		d := *d
 		d.DB = tx
@@ -58,11 +69,15 @@ func (d *Database) Transaction(f func(*Database) error) error {
 // then the migration will be performed no matter what the current version
 // is. The force parameter should only be set when the migration is
 // initiated by a user request.
-func (d *Database) Migrate(ctx context.Context, force bool) error {
+func (d *Database) Migrate(ctx context.Context, force bool) (err error) {
 	const op = errors.Op("db.Migrate")
 	if d == nil || d.DB == nil {
 		return errors.E(op, errors.CodeServerConfiguration, "database not configured")
 	}
+
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
 	db := d.DB.WithContext(ctx)
 	schema, _ := dbmodel.SQL.ReadFile(path.Join("sql", db.Name(), "versions.sql"))
 	if err := db.Exec(string(schema)).Error; err != nil {
@@ -114,6 +129,34 @@ func (d *Database) ready() error {
 	return nil
 }
 
+// Ping checks that the database has been migrated and that the database
+// server can be reached. On success the schema version stored in the
+// database is returned. If the stored schema is older than the version
+// required by this server an error with a code of
+// errors.CodeUpgradeInProgress is returned.
+func (d *Database) Ping(ctx context.Context) (_ *dbmodel.Version, err error) {
+	const op = errors.Op("db.Ping")
+	if err := d.ready(); err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	db := d.DB.WithContext(ctx)
+	v := dbmodel.Version{Component: dbmodel.Component}
+	if err := db.First(&v).Error; err != nil {
+		return nil, errors.E(op, dbError(err))
+	}
+	if v.Major != dbmodel.Major || v.Minor < dbmodel.Minor {
+		return &v, errors.E(op, errors.CodeUpgradeInProgress, fmt.Sprintf("database has version %d.%d, require %d.%d", v.Major, v.Minor, dbmodel.Major, dbmodel.Minor))
+	}
+	return &v, nil
+}
+
 // Close closes open connections to the underlying database backend.
 func (d *Database) Close() error {
 	sqlDB, err := d.DB.DB()
@@ -50,6 +50,29 @@ func TestTransactionUnconfiguredDatabase(t *testing.T) {
 	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeServerConfiguration)
 }
 
+func TestPingUnconfiguredDatabase(t *testing.T) {
+	c := qt.New(t)
+
+	var database db.Database
+	_, err := database.Ping(context.Background())
+	c.Check(err, qt.ErrorMatches, `database not configured`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeServerConfiguration)
+}
+
+func (s *dbSuite) TestPing(c *qt.C) {
+	ctx := context.Background()
+	_, err := s.Database.Ping(ctx)
+	c.Check(err, qt.ErrorMatches, `upgrade in progress`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUpgradeInProgress)
+
+	err = s.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+	v, err := s.Database.Ping(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Check(v.Major, qt.Equals, dbmodel.Major)
+	c.Check(v.Minor, qt.Equals, dbmodel.Minor)
+}
+
 func (s *dbSuite) TestTransaction(c *qt.C) {
 	err := s.Database.Transaction(func(d *db.Database) error {
 		return errors.E("unexpected function call")
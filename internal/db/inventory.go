@@ -0,0 +1,177 @@
+// Copyright 2024 Canonical.
+
+package db
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+// SetModelInventory stores the given model inventory, replacing any
+// existing inventory of the same model.
+func (d *Database) SetModelInventory(ctx context.Context, inv *dbmodel.ModelInventory) (err error) {
+	const op = errors.Op("db.SetModelInventory")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	err = d.Transaction(func(d *Database) error {
+		db := d.DB.WithContext(ctx)
+		// The contents of the previous inventory are removed by the
+		// cascading foreign keys.
+		if err := db.Where("model_id = ?", inv.ModelID).Delete(&dbmodel.ModelInventory{}).Error; err != nil {
+			return err
+		}
+		return db.Omit("Model").Create(inv).Error
+	})
+	if err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// GetModelInventory fills in the given model inventory using its model
+// ID. The inventory's model, along with the model's controller, is also
+// loaded. If the model has no inventory an error with a code of
+// CodeNotFound is returned.
+func (d *Database) GetModelInventory(ctx context.Context, inv *dbmodel.ModelInventory) (err error) {
+	const op = errors.Op("db.GetModelInventory")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	db := d.DB.WithContext(ctx)
+	db = db.Preload("Model").Preload("Model.Controller")
+	db = db.Preload("Applications", func(db *gorm.DB) *gorm.DB { return db.Order("name") })
+	db = db.Preload("Units", func(db *gorm.DB) *gorm.DB { return db.Order("name") })
+	db = db.Preload("Machines", func(db *gorm.DB) *gorm.DB { return db.Order("machine_id") })
+	db = db.Preload("Relations", func(db *gorm.DB) *gorm.DB { return db.Order("relation_id") })
+	if err := db.Where("model_id = ?", inv.ModelID).First(inv).Error; err != nil {
+		err = dbError(err)
+		if errors.ErrorCode(err) == errors.CodeNotFound {
+			return errors.E(op, err, "model inventory not found")
+		}
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// InventoryQuery holds the filters used to find applications in model
+// inventories. Empty fields match any value.
+type InventoryQuery struct {
+	// Controller matches applications in models hosted on the named
+	// controller.
+	Controller string
+
+	// Charm matches applications deployed from the named charm.
+	Charm string
+
+	// CharmChannel matches applications whose charm was deployed from
+	// the given channel.
+	CharmChannel string
+
+	// Revision matches applications whose charm has the given revision.
+	Revision int
+
+	// BelowRevision matches applications whose charm has a revision
+	// lower than the given revision.
+	BelowRevision int
+
+	// Base matches applications with the given base.
+	Base string
+
+	// ModelUUIDs, if not nil, restricts the results to applications in
+	// the models with the given UUIDs.
+	ModelUUIDs []string
+
+	// Offset and Limit page through the results.
+	Offset int
+	Limit  int
+}
+
+// An InventoryApplicationResult is an application found by
+// FindInventoryApplications, along with the model it is deployed in.
+type InventoryApplicationResult struct {
+	dbmodel.InventoryApplication
+
+	ModelUUID         string
+	ModelName         string
+	OwnerIdentityName string
+	ControllerName    string
+	CollectedAt       time.Time
+}
+
+// FindInventoryApplications returns the applications in all model
+// inventories that match the given query. The results are ordered by
+// model and application.
+func (d *Database) FindInventoryApplications(ctx context.Context, q InventoryQuery) (_ []InventoryApplicationResult, err error) {
+	const op = errors.Op("db.FindInventoryApplications")
+	if err := d.ready(); err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	db := d.DB.WithContext(ctx).Table("inventory_applications").
+		Select("inventory_applications.*, models.uuid AS model_uuid, models.name AS model_name, models.owner_identity_name, controllers.name AS controller_name, model_inventories.collected_at").
+		Joins("JOIN model_inventories ON model_inventories.id = inventory_applications.model_inventory_id").
+		Joins("JOIN models ON models.id = model_inventories.model_id").
+		Joins("JOIN controllers ON controllers.id = models.controller_id")
+	if q.Controller != "" {
+		db = db.Where("controllers.name = ?", q.Controller)
+	}
+	if q.Charm != "" {
+		db = db.Where("inventory_applications.charm = ?", q.Charm)
+	}
+	if q.CharmChannel != "" {
+		db = db.Where("inventory_applications.charm_channel = ?", q.CharmChannel)
+	}
+	if q.Revision != 0 {
+		db = db.Where("inventory_applications.charm_revision = ?", q.Revision)
+	}
+	if q.BelowRevision != 0 {
+		db = db.Where("inventory_applications.charm_revision >= 0 AND inventory_applications.charm_revision < ?", q.BelowRevision)
+	}
+	if q.Base != "" {
+		db = db.Where("inventory_applications.base = ?", q.Base)
+	}
+	if q.ModelUUIDs != nil {
+		if len(q.ModelUUIDs) == 0 {
+			return nil, nil
+		}
+		db = db.Where("models.uuid IN ?", q.ModelUUIDs)
+	}
+	if q.Offset > 0 {
+		db = db.Offset(q.Offset)
+	}
+	if q.Limit > 0 {
+		db = db.Limit(q.Limit)
+	}
+	var results []InventoryApplicationResult
+	if err := db.Order("models.uuid, inventory_applications.name").Scan(&results).Error; err != nil {
+		return nil, errors.E(op, dbError(err))
+	}
+	return results, nil
+}
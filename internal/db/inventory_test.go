@@ -0,0 +1,137 @@
+// Copyright 2024 Canonical.
+
+package db_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/testutils/jimmtest"
+)
+
+func TestSetModelInventoryUnconfiguredDatabase(t *testing.T) {
+	c := qt.New(t)
+
+	var d db.Database
+	err := d.SetModelInventory(context.Background(), &dbmodel.ModelInventory{})
+	c.Check(err, qt.ErrorMatches, `database not configured`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeServerConfiguration)
+}
+
+func (s *dbSuite) TestModelInventory(c *qt.C) {
+	ctx := context.Background()
+	err := s.Database.Migrate(ctx, true)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, testForEachModelEnv)
+	env.PopulateDB(c, s.Database)
+	m1 := env.Model("alice@canonical.com", "test-1").DBObject(c, s.Database)
+	m2 := env.Model("bob@canonical.com", "test-2").DBObject(c, s.Database)
+
+	inv := dbmodel.ModelInventory{ModelID: m1.ID}
+	err = s.Database.GetModelInventory(ctx, &inv)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	collectedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	err = s.Database.SetModelInventory(ctx, &dbmodel.ModelInventory{
+		ModelID:     m1.ID,
+		CollectedAt: collectedAt,
+		Applications: []dbmodel.InventoryApplication{{
+			Name:          "db",
+			Charm:         "postgresql",
+			CharmURL:      "ch:amd64/jammy/postgresql-280",
+			CharmRevision: 280,
+			Base:          "ubuntu@22.04",
+		}},
+		Units: []dbmodel.InventoryUnit{{
+			Name:        "db/0",
+			Application: "db",
+			Machine:     "0",
+		}},
+		Machines: []dbmodel.InventoryMachine{{
+			MachineID: "0",
+			Base:      "ubuntu@22.04",
+		}},
+	})
+	c.Assert(err, qt.IsNil)
+
+	// Setting an inventory replaces the previous inventory.
+	err = s.Database.SetModelInventory(ctx, &dbmodel.ModelInventory{
+		ModelID:     m1.ID,
+		CollectedAt: collectedAt.Add(time.Hour),
+		Applications: []dbmodel.InventoryApplication{{
+			Name:          "db",
+			Charm:         "postgresql",
+			CharmURL:      "ch:amd64/jammy/postgresql-300",
+			CharmRevision: 300,
+			Base:          "ubuntu@22.04",
+		}},
+		Relations: []dbmodel.InventoryRelation{{
+			RelationID: 1,
+			Key:        "db:replication",
+			Interface:  "pgpeer",
+			Scope:      "global",
+		}},
+	})
+	c.Assert(err, qt.IsNil)
+	err = s.Database.SetModelInventory(ctx, &dbmodel.ModelInventory{
+		ModelID:     m2.ID,
+		CollectedAt: collectedAt,
+		Applications: []dbmodel.InventoryApplication{{
+			Name:          "postgresql",
+			Charm:         "postgresql",
+			CharmURL:      "ch:amd64/focal/postgresql-250",
+			CharmRevision: 250,
+			Base:          "ubuntu@20.04",
+		}},
+	})
+	c.Assert(err, qt.IsNil)
+
+	inv = dbmodel.ModelInventory{ModelID: m1.ID}
+	err = s.Database.GetModelInventory(ctx, &inv)
+	c.Assert(err, qt.IsNil)
+	c.Check(inv.CollectedAt.Equal(collectedAt.Add(time.Hour)), qt.IsTrue)
+	c.Check(inv.Model.UUID.String, qt.Equals, m1.UUID.String)
+	c.Check(inv.Model.Controller.Name, qt.Equals, "test")
+	c.Assert(inv.Applications, qt.HasLen, 1)
+	c.Check(inv.Applications[0].CharmRevision, qt.Equals, 300)
+	c.Check(inv.Units, qt.HasLen, 0)
+	c.Check(inv.Machines, qt.HasLen, 0)
+	c.Check(inv.Relations, qt.HasLen, 1)
+
+	apps, err := s.Database.FindInventoryApplications(ctx, db.InventoryQuery{Charm: "postgresql"})
+	c.Assert(err, qt.IsNil)
+	c.Assert(apps, qt.HasLen, 2)
+	c.Check(apps[0].ModelUUID, qt.Equals, m1.UUID.String)
+	c.Check(apps[0].ModelName, qt.Equals, "test-1")
+	c.Check(apps[0].OwnerIdentityName, qt.Equals, "alice@canonical.com")
+	c.Check(apps[0].ControllerName, qt.Equals, "test")
+	c.Check(apps[1].Name, qt.Equals, "postgresql")
+
+	apps, err = s.Database.FindInventoryApplications(ctx, db.InventoryQuery{Charm: "postgresql", BelowRevision: 300})
+	c.Assert(err, qt.IsNil)
+	c.Assert(apps, qt.HasLen, 1)
+	c.Check(apps[0].ModelUUID, qt.Equals, m2.UUID.String)
+
+	apps, err = s.Database.FindInventoryApplications(ctx, db.InventoryQuery{Base: "ubuntu@22.04", Controller: "test"})
+	c.Assert(err, qt.IsNil)
+	c.Assert(apps, qt.HasLen, 1)
+	c.Check(apps[0].ModelUUID, qt.Equals, m1.UUID.String)
+
+	apps, err = s.Database.FindInventoryApplications(ctx, db.InventoryQuery{Controller: "no-such-controller"})
+	c.Assert(err, qt.IsNil)
+	c.Check(apps, qt.HasLen, 0)
+
+	// Removing a model removes its inventory.
+	err = s.Database.DeleteModel(ctx, &m1)
+	c.Assert(err, qt.IsNil)
+	inv = dbmodel.ModelInventory{ModelID: m1.ID}
+	err = s.Database.GetModelInventory(ctx, &inv)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+}
@@ -0,0 +1,146 @@
+// Copyright 2024 Canonical.
+
+package db
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+// acquireLeaderLeaseQuery creates or takes over a leader lease. The lease
+// is only updated if it is already held by the same holder or it has
+// expired, in which case no row is returned. The fencing token is
+// incremented whenever a new term starts. All times come from the
+// database server so that replica clocks do not need to agree.
+const acquireLeaderLeaseQuery = `
+INSERT INTO leader_leases AS l (name, holder, token, acquired_at, renewed_at, expires_at)
+VALUES (@name, @holder, 1, now(), now(), now() + @ttl * interval '1 microsecond')
+ON CONFLICT (name) DO UPDATE SET
+	holder = EXCLUDED.holder,
+	token = CASE WHEN l.holder = EXCLUDED.holder AND l.expires_at > now() THEN l.token ELSE l.token + 1 END,
+	acquired_at = CASE WHEN l.holder = EXCLUDED.holder AND l.expires_at > now() THEN l.acquired_at ELSE now() END,
+	renewed_at = now(),
+	expires_at = EXCLUDED.expires_at
+WHERE l.holder = EXCLUDED.holder OR l.expires_at <= now()
+RETURNING name, holder, token, acquired_at, renewed_at, expires_at`
+
+// AcquireLeaderLease attempts to acquire, or renew, the leader lease with
+// the name and holder of the given lease for the given duration. If the
+// lease is acquired the given lease is filled in and true is returned. If
+// the lease is held by another holder false is returned.
+func (d *Database) AcquireLeaderLease(ctx context.Context, lease *dbmodel.LeaderLease, ttl time.Duration) (_ bool, err error) {
+	const op = errors.Op("db.AcquireLeaderLease")
+	if err := d.ready(); err != nil {
+		return false, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	db := d.DB.WithContext(ctx)
+	var acquired dbmodel.LeaderLease
+	result := db.Raw(acquireLeaderLeaseQuery, map[string]interface{}{
+		"name":   lease.Name,
+		"holder": lease.Holder,
+		"ttl":    ttl.Microseconds(),
+	}).Scan(&acquired)
+	if result.Error != nil {
+		return false, errors.E(op, dbError(result.Error))
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+	*lease = acquired
+	return true, nil
+}
+
+// GetLeaderLease fills in the given leader lease using its name. If there
+// is no such lease an error with a code of CodeNotFound is returned.
+func (d *Database) GetLeaderLease(ctx context.Context, lease *dbmodel.LeaderLease) (err error) {
+	const op = errors.Op("db.GetLeaderLease")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	db := d.DB.WithContext(ctx)
+	if err := db.Where("name = ?", lease.Name).First(lease).Error; err != nil {
+		err = dbError(err)
+		if errors.ErrorCode(err) == errors.CodeNotFound {
+			return errors.E(op, err, "leader lease not found")
+		}
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// CheckLeaderLease checks that the given leader lease is still held, with
+// the same fencing token, and has not expired. If the lease is no longer
+// held an error with a code of CodeUnauthorized is returned.
+func (d *Database) CheckLeaderLease(ctx context.Context, lease *dbmodel.LeaderLease) (err error) {
+	const op = errors.Op("db.CheckLeaderLease")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	db := d.DB.WithContext(ctx)
+	var count int64
+	err = db.Model(&dbmodel.LeaderLease{}).
+		Where("name = ? AND holder = ? AND token = ? AND expires_at > now()", lease.Name, lease.Holder, lease.Token).
+		Count(&count).Error
+	if err != nil {
+		return errors.E(op, dbError(err))
+	}
+	if count == 0 {
+		return errors.E(op, errors.CodeUnauthorized, "leader lease not held")
+	}
+	return nil
+}
+
+// ReleaseLeaderLease gives up the given leader lease, so that another
+// holder can acquire it immediately. The lease is only released if it is
+// still held with the same fencing token, releasing a lease that has
+// been taken over is not an error.
+func (d *Database) ReleaseLeaderLease(ctx context.Context, lease *dbmodel.LeaderLease) (err error) {
+	const op = errors.Op("db.ReleaseLeaderLease")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	// The lease is expired rather than deleted so that the fencing token
+	// keeps increasing.
+	db := d.DB.WithContext(ctx)
+	err = db.Model(&dbmodel.LeaderLease{}).
+		Where("name = ? AND holder = ? AND token = ?", lease.Name, lease.Holder, lease.Token).
+		Update("expires_at", gorm.Expr("now()")).Error
+	if err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
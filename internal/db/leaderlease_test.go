@@ -0,0 +1,95 @@
+// Copyright 2024 Canonical.
+
+package db_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+func TestAcquireLeaderLeaseUnconfiguredDatabase(t *testing.T) {
+	c := qt.New(t)
+
+	var d db.Database
+	_, err := d.AcquireLeaderLease(context.Background(), &dbmodel.LeaderLease{}, time.Minute)
+	c.Check(err, qt.ErrorMatches, `database not configured`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeServerConfiguration)
+}
+
+func (s *dbSuite) TestLeaderLease(c *qt.C) {
+	ctx := context.Background()
+	err := s.Database.Migrate(ctx, true)
+	c.Assert(err, qt.IsNil)
+
+	lease := dbmodel.LeaderLease{Name: "test"}
+	err = s.Database.GetLeaderLease(ctx, &lease)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	lease1 := dbmodel.LeaderLease{Name: "test", Holder: "replica-1"}
+	ok, err := s.Database.AcquireLeaderLease(ctx, &lease1, time.Minute)
+	c.Assert(err, qt.IsNil)
+	c.Assert(ok, qt.IsTrue)
+	c.Check(lease1.Token, qt.Equals, int64(1))
+	c.Check(lease1.ExpiresAt.After(lease1.RenewedAt), qt.IsTrue)
+
+	// Another holder cannot take an unexpired lease.
+	lease2 := dbmodel.LeaderLease{Name: "test", Holder: "replica-2"}
+	ok, err = s.Database.AcquireLeaderLease(ctx, &lease2, time.Minute)
+	c.Assert(err, qt.IsNil)
+	c.Check(ok, qt.IsFalse)
+
+	// Renewing keeps the same term.
+	acquiredAt := lease1.AcquiredAt
+	ok, err = s.Database.AcquireLeaderLease(ctx, &lease1, 0)
+	c.Assert(err, qt.IsNil)
+	c.Assert(ok, qt.IsTrue)
+	c.Check(lease1.Token, qt.Equals, int64(1))
+	c.Check(lease1.AcquiredAt.Equal(acquiredAt), qt.IsTrue)
+
+	// Once the lease has expired it can be taken over and the fencing
+	// token increases.
+	ok, err = s.Database.AcquireLeaderLease(ctx, &lease2, time.Minute)
+	c.Assert(err, qt.IsNil)
+	c.Assert(ok, qt.IsTrue)
+	c.Check(lease2.Token, qt.Equals, int64(2))
+
+	err = s.Database.CheckLeaderLease(ctx, &lease1)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+	err = s.Database.CheckLeaderLease(ctx, &lease2)
+	c.Check(err, qt.IsNil)
+
+	// The stale holder cannot renew the lease.
+	stale := lease1
+	ok, err = s.Database.AcquireLeaderLease(ctx, &stale, time.Minute)
+	c.Assert(err, qt.IsNil)
+	c.Check(ok, qt.IsFalse)
+
+	// Releasing a lease that has been taken over has no effect.
+	err = s.Database.ReleaseLeaderLease(ctx, &lease1)
+	c.Assert(err, qt.IsNil)
+	err = s.Database.CheckLeaderLease(ctx, &lease2)
+	c.Check(err, qt.IsNil)
+
+	err = s.Database.ReleaseLeaderLease(ctx, &lease2)
+	c.Assert(err, qt.IsNil)
+	err = s.Database.CheckLeaderLease(ctx, &lease2)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+
+	lease = dbmodel.LeaderLease{Name: "test"}
+	err = s.Database.GetLeaderLease(ctx, &lease)
+	c.Assert(err, qt.IsNil)
+	c.Check(lease.Holder, qt.Equals, "replica-2")
+	c.Check(lease.Token, qt.Equals, int64(2))
+
+	ok, err = s.Database.AcquireLeaderLease(ctx, &lease1, time.Minute)
+	c.Assert(err, qt.IsNil)
+	c.Assert(ok, qt.IsTrue)
+	c.Check(lease1.Token, qt.Equals, int64(3))
+}
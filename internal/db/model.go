@@ -4,7 +4,10 @@ package db
 
 import (
 	"context"
+	"encoding/json"
+	"time"
 
+	"github.com/juju/juju/state"
 	"gorm.io/gorm"
 
 	"github.com/canonical/jimm/v3/internal/dbmodel"
@@ -24,6 +27,8 @@ func (d *Database) AddModel(ctx context.Context, model *dbmodel.Model) (err erro
 	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
 	defer durationObserver()
 	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
 
 	db := d.DB.WithContext(ctx)
 
@@ -44,6 +49,8 @@ func (d *Database) GetModel(ctx context.Context, model *dbmodel.Model) (err erro
 	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
 	defer durationObserver()
 	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
 
 	db := d.DB.WithContext(ctx)
 	switch {
@@ -85,6 +92,8 @@ func (d *Database) GetModelsUsingCredential(ctx context.Context, credentialID ui
 	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
 	defer durationObserver()
 	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
 
 	db := d.DB.WithContext(ctx)
 	var models []dbmodel.Model
@@ -105,6 +114,8 @@ func (d *Database) UpdateModel(ctx context.Context, model *dbmodel.Model) (err e
 	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
 	defer durationObserver()
 	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
 
 	db := d.DB.WithContext(ctx)
 	if err := db.Save(model).Error; err != nil {
@@ -123,6 +134,8 @@ func (d *Database) DeleteModel(ctx context.Context, model *dbmodel.Model) (err e
 	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
 	defer durationObserver()
 	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
 
 	db := d.DB.WithContext(ctx)
 	if err := db.Delete(model, model.ID).Error; err != nil {
@@ -144,6 +157,8 @@ func (d *Database) ForEachModel(ctx context.Context, f func(m *dbmodel.Model) er
 	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
 	defer durationObserver()
 	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
 
 	db := d.DB.WithContext(ctx)
 	db = preloadModel("", db)
@@ -189,6 +204,8 @@ func (d *Database) GetModelsByUUID(ctx context.Context, modelUUIDs []string) (_
 	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
 	defer durationObserver()
 	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
 
 	var models []dbmodel.Model
 	db := d.DB.WithContext(ctx)
@@ -222,12 +239,19 @@ func preloadModel(prefix string, db *gorm.DB) *gorm.DB {
 
 // GetModelsByController retrieves a list of models hosted on the specified controller.
 // Note that because we do not preload here, foreign key references will be empty.
-func (d *Database) GetModelsByController(ctx context.Context, ctl dbmodel.Controller) ([]dbmodel.Model, error) {
+func (d *Database) GetModelsByController(ctx context.Context, ctl dbmodel.Controller) (_ []dbmodel.Model, err error) {
 	const op = errors.Op("db.GetModelsByController")
 
 	if err := d.ready(); err != nil {
 		return nil, errors.E(op, err)
 	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
 	var models []dbmodel.Model
 	db := d.DB.WithContext(ctx)
 	if err := db.Model(ctl).Association("Models").Find(&models); err != nil {
@@ -237,12 +261,19 @@ func (d *Database) GetModelsByController(ctx context.Context, ctl dbmodel.Contro
 }
 
 // CountModelsByController counts the number of models hosted on a controller.
-func (d *Database) CountModelsByController(ctx context.Context, ctl dbmodel.Controller) (int, error) {
+func (d *Database) CountModelsByController(ctx context.Context, ctl dbmodel.Controller) (_ int, err error) {
 	const op = errors.Op("db.CountModelsByController")
 
 	if err := d.ready(); err != nil {
 		return 0, errors.E(op, err)
 	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
 	db := d.DB.WithContext(ctx)
 	asc := db.Model(ctl).Association("Models")
 	count := asc.Count()
@@ -251,3 +282,317 @@ func (d *Database) CountModelsByController(ctx context.Context, ctl dbmodel.Cont
 	}
 	return int(count), nil
 }
+
+// CountModelsByOwners counts the number of models owned by any of the
+// identities with the given names.
+func (d *Database) CountModelsByOwners(ctx context.Context, ownerNames []string) (_ int, err error) {
+	const op = errors.Op("db.CountModelsByOwners")
+	if err := d.ready(); err != nil {
+		return 0, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	if len(ownerNames) == 0 {
+		return 0, nil
+	}
+	var count int64
+	db := d.DB.WithContext(ctx)
+	if err := db.Model(&dbmodel.Model{}).Where("owner_identity_name IN ?", ownerNames).Count(&count).Error; err != nil {
+		return 0, errors.E(op, dbError(err))
+	}
+	return int(count), nil
+}
+
+// CountModels counts the number of models.
+func (d *Database) CountModels(ctx context.Context) (_ int, err error) {
+	const op = errors.Op("db.CountModels")
+	if err := d.ready(); err != nil {
+		return 0, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	var count int64
+	db := d.DB.WithContext(ctx)
+	if err := db.Model(&dbmodel.Model{}).Count(&count).Error; err != nil {
+		return 0, errors.E(op, dbError(err))
+	}
+	return int(count), nil
+}
+
+// CountModelsByCloudRegion counts the number of models hosted on the given
+// cloud. If region is not empty only the models hosted in that region of
+// the cloud are counted.
+func (d *Database) CountModelsByCloudRegion(ctx context.Context, cloud, region string) (_ int, err error) {
+	const op = errors.Op("db.CountModelsByCloudRegion")
+	if err := d.ready(); err != nil {
+		return 0, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	var count int64
+	db := d.DB.WithContext(ctx)
+	db = db.Model(&dbmodel.Model{}).Joins("JOIN cloud_regions ON cloud_regions.id = models.cloud_region_id").Where("cloud_regions.cloud_name = ?", cloud)
+	if region != "" {
+		db = db.Where("cloud_regions.name = ?", region)
+	}
+	if err := db.Count(&count).Error; err != nil {
+		return 0, errors.E(op, dbError(err))
+	}
+	return int(count), nil
+}
+
+// ListExpiringModels returns the models that are not dying or dead and
+// expire at, or before, the given time. The models are ordered by expiry
+// time.
+func (d *Database) ListExpiringModels(ctx context.Context, before time.Time) (_ []dbmodel.Model, err error) {
+	const op = errors.Op("db.ListExpiringModels")
+	if err := d.ready(); err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	var models []dbmodel.Model
+	db := d.DB.WithContext(ctx)
+	db = preloadModel("", db)
+	err = db.Where("expires_at IS NOT NULL AND expires_at <= ? AND life NOT IN ?", before, []string{state.Dying.String(), state.Dead.String()}).Order("expires_at").Find(&models).Error
+	if err != nil {
+		return nil, errors.E(op, dbError(err))
+	}
+	return models, nil
+}
+
+// ListModelsBySummaryStatus returns the models whose last reported
+// summary status is the given status.
+func (d *Database) ListModelsBySummaryStatus(ctx context.Context, status string) (_ []dbmodel.Model, err error) {
+	const op = errors.Op("db.ListModelsBySummaryStatus")
+	if err := d.ready(); err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	var models []dbmodel.Model
+	db := d.DB.WithContext(ctx)
+	db = preloadModel("", db)
+	if err := db.Where("summary_status = ?", status).Order("uuid").Find(&models).Error; err != nil {
+		return nil, errors.E(op, dbError(err))
+	}
+	return models, nil
+}
+
+// UpdateModelExpiry updates the expiry time, and whether the expiry
+// warning has been sent, of the given model. No other fields are updated.
+func (d *Database) UpdateModelExpiry(ctx context.Context, model *dbmodel.Model) (err error) {
+	const op = errors.Op("db.UpdateModelExpiry")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	db := d.DB.WithContext(ctx)
+	result := db.Model(model).Select("ExpiresAt", "ExpiryWarningSent").Updates(model)
+	if result.Error != nil {
+		return errors.E(op, dbError(result.Error))
+	}
+	if result.RowsAffected == 0 {
+		return errors.E(op, errors.CodeNotFound, "model not found")
+	}
+	return nil
+}
+
+// MarkExpiredModelDying sets the life of the given model to dying if it
+// has expired and is not already dying or dead. The expiry is checked in
+// the same statement that updates the model, so a model whose expiry has
+// been extended since it was read is left alone. No other fields are
+// updated. The returned value reports whether the model was updated.
+func (d *Database) MarkExpiredModelDying(ctx context.Context, model *dbmodel.Model) (_ bool, err error) {
+	const op = errors.Op("db.MarkExpiredModelDying")
+	if err := d.ready(); err != nil {
+		return false, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	db := d.DB.WithContext(ctx)
+	result := db.Model(&dbmodel.Model{}).
+		Where("id = ? AND expires_at IS NOT NULL AND expires_at <= now() AND life NOT IN ?", model.ID, []string{state.Dying.String(), state.Dead.String()}).
+		Update("life", state.Dying.String())
+	if result.Error != nil {
+		return false, errors.E(op, dbError(result.Error))
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+	model.Life = state.Dying.String()
+	return true, nil
+}
+
+// UpdateModelLife updates the life of the given model. No other fields
+// are updated.
+func (d *Database) UpdateModelLife(ctx context.Context, model *dbmodel.Model) (err error) {
+	const op = errors.Op("db.UpdateModelLife")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	db := d.DB.WithContext(ctx)
+	result := db.Model(model).Select("Life").Updates(model)
+	if result.Error != nil {
+		return errors.E(op, dbError(result.Error))
+	}
+	if result.RowsAffected == 0 {
+		return errors.E(op, errors.CodeNotFound, "model not found")
+	}
+	return nil
+}
+
+// UpdateModelSummaryStatus updates the summary status, the time it last
+// changed, and its message, of the given model. No other fields are
+// updated.
+func (d *Database) UpdateModelSummaryStatus(ctx context.Context, model *dbmodel.Model) (err error) {
+	const op = errors.Op("db.UpdateModelSummaryStatus")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	db := d.DB.WithContext(ctx)
+	result := db.Model(model).Select("SummaryStatus", "SummaryStatusSince", "SummaryStatusMessage").Updates(model)
+	if result.Error != nil {
+		return errors.E(op, dbError(result.Error))
+	}
+	if result.RowsAffected == 0 {
+		return errors.E(op, errors.CodeNotFound, "model not found")
+	}
+	return nil
+}
+
+// UpdateModelLabels sets and removes labels of the model with the UUID of
+// the given model. The labels in set replace any existing labels with the
+// same keys, then the labels with the keys in remove are removed. The
+// labels are modified in a single statement, so concurrent updates of the
+// labels are not lost. No other fields are updated.
+func (d *Database) UpdateModelLabels(ctx context.Context, model *dbmodel.Model, set map[string]string, remove []string) (err error) {
+	const op = errors.Op("db.UpdateModelLabels")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	if set == nil {
+		set = map[string]string{}
+	}
+	buf, err := json.Marshal(set)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	expr := "(COALESCE(labels, '{}'::jsonb) || ?::jsonb)"
+	args := []interface{}{string(buf)}
+	for _, k := range remove {
+		expr += " - ?::text"
+		args = append(args, k)
+	}
+
+	db := d.DB.WithContext(ctx)
+	result := db.Model(&dbmodel.Model{}).Where("uuid = ?", model.UUID).Update("labels", gorm.Expr(expr, args...))
+	if result.Error != nil {
+		return errors.E(op, dbError(result.Error))
+	}
+	if result.RowsAffected == 0 {
+		return errors.E(op, errors.CodeNotFound, "model not found")
+	}
+	return nil
+}
+
+// UpdateModelOwner updates the owner of the given model. No other fields
+// are updated. If the new owner already owns a model with the same name
+// an error with a code of CodeAlreadyExists is returned.
+func (d *Database) UpdateModelOwner(ctx context.Context, model *dbmodel.Model) (err error) {
+	const op = errors.Op("db.UpdateModelOwner")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	db := d.DB.WithContext(ctx)
+	result := db.Model(model).Select("OwnerIdentityName").Updates(model)
+	if result.Error != nil {
+		return errors.E(op, dbError(result.Error))
+	}
+	if result.RowsAffected == 0 {
+		return errors.E(op, errors.CodeNotFound, "model not found")
+	}
+	return nil
+}
+
+// whereModelLabels adds conditions to the given query that select models
+// with labels matching the given selector.
+func whereModelLabels(db *gorm.DB, sel dbmodel.LabelSelector) *gorm.DB {
+	for _, r := range sel {
+		switch r.Operator {
+		case dbmodel.LabelEquals:
+			db = db.Where("models.labels->>? = ?", r.Key, r.Value)
+		case dbmodel.LabelNotEquals:
+			db = db.Where("models.labels->>? IS DISTINCT FROM ?", r.Key, r.Value)
+		case dbmodel.LabelExists:
+			db = db.Where("models.labels->>? IS NOT NULL", r.Key)
+		case dbmodel.LabelNotExists:
+			db = db.Where("models.labels->>? IS NULL", r.Key)
+		}
+	}
+	return db
+}
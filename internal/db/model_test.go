@@ -6,7 +6,10 @@ import (
 	"context"
 	"database/sql"
 	"sort"
+	"strconv"
+	"sync"
 	"testing"
+	"time"
 
 	qt "github.com/frankban/quicktest"
 	"github.com/juju/juju/state"
@@ -702,3 +705,249 @@ func (s *dbSuite) TestCountModelsByController(c *qt.C) {
 	c.Assert(err, qt.IsNil)
 	c.Assert(count, qt.Equals, 3)
 }
+
+func (s *dbSuite) TestCountModelsByOwnersAndCloudRegion(c *qt.C) {
+	ctx := context.Background()
+	err := s.Database.Migrate(ctx, true)
+	c.Assert(err, qt.Equals, nil)
+
+	env := jimmtest.ParseEnvironment(c, testCountModelsByControllerEnv)
+	env.PopulateDB(c, s.Database)
+
+	count, err := s.Database.CountModelsByOwners(ctx, []string{"bob@canonical.com"})
+	c.Assert(err, qt.IsNil)
+	c.Check(count, qt.Equals, 2)
+	count, err = s.Database.CountModelsByOwners(ctx, []string{"bob@canonical.com", "alice@canonical.com"})
+	c.Assert(err, qt.IsNil)
+	c.Check(count, qt.Equals, 3)
+	count, err = s.Database.CountModelsByOwners(ctx, []string{"charlie@canonical.com"})
+	c.Assert(err, qt.IsNil)
+	c.Check(count, qt.Equals, 0)
+	count, err = s.Database.CountModelsByOwners(ctx, nil)
+	c.Assert(err, qt.IsNil)
+	c.Check(count, qt.Equals, 0)
+	count, err = s.Database.CountModels(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Check(count, qt.Equals, 3)
+
+	count, err = s.Database.CountModelsByCloudRegion(ctx, "test", "")
+	c.Assert(err, qt.IsNil)
+	c.Check(count, qt.Equals, 3)
+	count, err = s.Database.CountModelsByCloudRegion(ctx, "test", "test-region")
+	c.Assert(err, qt.IsNil)
+	c.Check(count, qt.Equals, 3)
+	count, err = s.Database.CountModelsByCloudRegion(ctx, "test", "other-region")
+	c.Assert(err, qt.IsNil)
+	c.Check(count, qt.Equals, 0)
+}
+
+func (s *dbSuite) TestListExpiringModels(c *qt.C) {
+	ctx := context.Background()
+	err := s.Database.Migrate(ctx, true)
+	c.Assert(err, qt.Equals, nil)
+
+	env := jimmtest.ParseEnvironment(c, testCountModelsByControllerEnv)
+	env.PopulateDB(c, s.Database)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	expiries := []time.Time{now.Add(2 * time.Hour), now.Add(-time.Minute), now.Add(time.Minute)}
+	for i, m := range env.Models {
+		model := m.DBObject(c, s.Database)
+		model.ExpiresAt = sql.NullTime{Time: expiries[i], Valid: true}
+		err := s.Database.UpdateModelExpiry(ctx, &model)
+		c.Assert(err, qt.IsNil)
+	}
+	// Dying models are not listed.
+	dying := env.Models[0].DBObject(c, s.Database)
+	dying.ExpiresAt = sql.NullTime{Time: now.Add(-time.Hour), Valid: true}
+	err = s.Database.UpdateModelExpiry(ctx, &dying)
+	c.Assert(err, qt.IsNil)
+	dying.Life = state.Dying.String()
+	err = s.Database.UpdateModel(ctx, &dying)
+	c.Assert(err, qt.IsNil)
+
+	models, err := s.Database.ListExpiringModels(ctx, now.Add(time.Hour))
+	c.Assert(err, qt.IsNil)
+	c.Assert(models, qt.HasLen, 2)
+	c.Check(models[0].UUID.String, qt.Equals, "00000002-0000-0000-0000-000000000002")
+	c.Check(models[0].Controller.Name, qt.Equals, "test")
+	c.Check(models[1].UUID.String, qt.Equals, "00000002-0000-0000-0000-000000000003")
+
+	models[1].ExpiryWarningSent = true
+	err = s.Database.UpdateModelExpiry(ctx, &models[1])
+	c.Assert(err, qt.IsNil)
+	m := dbmodel.Model{UUID: models[1].UUID}
+	err = s.Database.GetModel(ctx, &m)
+	c.Assert(err, qt.IsNil)
+	c.Check(m.ExpiryWarningSent, qt.IsTrue)
+	c.Check(m.ExpiresAt.Time.Equal(now.Add(time.Minute)), qt.IsTrue)
+
+	err = s.Database.UpdateModelExpiry(ctx, &dbmodel.Model{ID: 1000})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+}
+
+func (s *dbSuite) TestMarkExpiredModelDying(c *qt.C) {
+	ctx := context.Background()
+	err := s.Database.Migrate(ctx, true)
+	c.Assert(err, qt.Equals, nil)
+
+	env := jimmtest.ParseEnvironment(c, testCountModelsByControllerEnv)
+	env.PopulateDB(c, s.Database)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	expired := env.Models[0].DBObject(c, s.Database)
+	expired.ExpiresAt = sql.NullTime{Time: now.Add(-time.Minute), Valid: true}
+	err = s.Database.UpdateModelExpiry(ctx, &expired)
+	c.Assert(err, qt.IsNil)
+	extended := env.Models[1].DBObject(c, s.Database)
+	extended.ExpiresAt = sql.NullTime{Time: now.Add(time.Hour), Valid: true}
+	err = s.Database.UpdateModelExpiry(ctx, &extended)
+	c.Assert(err, qt.IsNil)
+
+	// A stale copy of the model does not overwrite concurrent changes.
+	stale := expired
+	err = s.Database.UpdateModelLabels(ctx, &expired, map[string]string{"team": "a"}, nil)
+	c.Assert(err, qt.IsNil)
+	updated, err := s.Database.MarkExpiredModelDying(ctx, &stale)
+	c.Assert(err, qt.IsNil)
+	c.Check(updated, qt.IsTrue)
+	c.Check(stale.Life, qt.Equals, state.Dying.String())
+	m := dbmodel.Model{UUID: expired.UUID}
+	err = s.Database.GetModel(ctx, &m)
+	c.Assert(err, qt.IsNil)
+	c.Check(m.Life, qt.Equals, state.Dying.String())
+	c.Check(m.Labels, qt.DeepEquals, dbmodel.StringMap{"team": "a"})
+
+	// Dying models are not updated again.
+	updated, err = s.Database.MarkExpiredModelDying(ctx, &stale)
+	c.Assert(err, qt.IsNil)
+	c.Check(updated, qt.IsFalse)
+
+	// Models that have not expired are not updated, even if they had
+	// expired when they were read.
+	stale = extended
+	stale.ExpiresAt = expired.ExpiresAt
+	updated, err = s.Database.MarkExpiredModelDying(ctx, &stale)
+	c.Assert(err, qt.IsNil)
+	c.Check(updated, qt.IsFalse)
+	m = dbmodel.Model{UUID: extended.UUID}
+	err = s.Database.GetModel(ctx, &m)
+	c.Assert(err, qt.IsNil)
+	c.Check(m.Life, qt.Not(qt.Equals), state.Dying.String())
+
+	stale = expired
+	stale.Life = state.Alive.String()
+	err = s.Database.UpdateModelLife(ctx, &stale)
+	c.Assert(err, qt.IsNil)
+	m = dbmodel.Model{UUID: expired.UUID}
+	err = s.Database.GetModel(ctx, &m)
+	c.Assert(err, qt.IsNil)
+	c.Check(m.Life, qt.Equals, state.Alive.String())
+	c.Check(m.Labels, qt.DeepEquals, dbmodel.StringMap{"team": "a"})
+
+	err = s.Database.UpdateModelLife(ctx, &dbmodel.Model{ID: 1000})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+}
+
+func (s *dbSuite) TestListModelsBySummaryStatus(c *qt.C) {
+	ctx := context.Background()
+	err := s.Database.Migrate(ctx, true)
+	c.Assert(err, qt.Equals, nil)
+
+	env := jimmtest.ParseEnvironment(c, testCountModelsByControllerEnv)
+	env.PopulateDB(c, s.Database)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	statuses := []string{"error", "available", "error"}
+	for i, m := range env.Models {
+		model := m.DBObject(c, s.Database)
+		model.SummaryStatus = statuses[i]
+		model.SummaryStatusSince = sql.NullTime{Time: now, Valid: true}
+		model.SummaryStatusMessage = "unit/" + strconv.Itoa(i) + ": hook failed"
+		err := s.Database.UpdateModelSummaryStatus(ctx, &model)
+		c.Assert(err, qt.IsNil)
+	}
+
+	models, err := s.Database.ListModelsBySummaryStatus(ctx, "error")
+	c.Assert(err, qt.IsNil)
+	c.Assert(models, qt.HasLen, 2)
+	c.Check(models[0].UUID.String, qt.Equals, "00000002-0000-0000-0000-000000000001")
+	c.Check(models[0].SummaryStatusMessage, qt.Equals, "unit/0: hook failed")
+	c.Check(models[0].SummaryStatusSince.Time.Equal(now), qt.IsTrue)
+	c.Check(models[0].CloudRegion.Cloud.Name, qt.Not(qt.Equals), "")
+	c.Check(models[1].UUID.String, qt.Equals, "00000002-0000-0000-0000-000000000003")
+}
+
+func TestListExpiringModelsUnconfiguredDatabase(t *testing.T) {
+	c := qt.New(t)
+
+	var d db.Database
+	_, err := d.ListExpiringModels(context.Background(), time.Now())
+	c.Check(err, qt.ErrorMatches, `database not configured`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeServerConfiguration)
+}
+
+func (s *dbSuite) TestUpdateModelLabels(c *qt.C) {
+	ctx := context.Background()
+	err := s.Database.Migrate(ctx, true)
+	c.Assert(err, qt.Equals, nil)
+
+	env := jimmtest.ParseEnvironment(c, testCountModelsByControllerEnv)
+	env.PopulateDB(c, s.Database)
+
+	model := env.Models[0].DBObject(c, s.Database)
+	c.Check(model.Labels, qt.IsNil)
+	err = s.Database.UpdateModelLabels(ctx, &model, map[string]string{"env": "prod", "team": "ops"}, nil)
+	c.Assert(err, qt.IsNil)
+
+	m := dbmodel.Model{UUID: model.UUID}
+	err = s.Database.GetModel(ctx, &m)
+	c.Assert(err, qt.IsNil)
+	c.Check(m.Labels, qt.DeepEquals, dbmodel.StringMap{"env": "prod", "team": "ops"})
+
+	// Labels not being set or removed are left unchanged, so concurrent
+	// updates of different labels are all kept.
+	var wg sync.WaitGroup
+	for _, k := range []string{"a", "b", "c", "d"} {
+		wg.Add(1)
+		go func(k string) {
+			defer wg.Done()
+			err := s.Database.UpdateModelLabels(ctx, &dbmodel.Model{UUID: model.UUID}, map[string]string{k: "1"}, nil)
+			c.Check(err, qt.IsNil)
+		}(k)
+	}
+	wg.Wait()
+	err = s.Database.UpdateModelLabels(ctx, &model, map[string]string{"env": "staging"}, []string{"team", "a", "missing"})
+	c.Assert(err, qt.IsNil)
+
+	m = dbmodel.Model{UUID: model.UUID}
+	err = s.Database.GetModel(ctx, &m)
+	c.Assert(err, qt.IsNil)
+	c.Check(m.Labels, qt.DeepEquals, dbmodel.StringMap{"env": "staging", "b": "1", "c": "1", "d": "1"})
+
+	err = s.Database.UpdateModelLabels(ctx, &dbmodel.Model{UUID: sql.NullString{String: "00000000-0000-0000-0000-000000000000", Valid: true}}, map[string]string{"env": "prod"}, nil)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+}
+
+func (s *dbSuite) TestUpdateModelOwner(c *qt.C) {
+	ctx := context.Background()
+	err := s.Database.Migrate(ctx, true)
+	c.Assert(err, qt.Equals, nil)
+
+	env := jimmtest.ParseEnvironment(c, testCountModelsByControllerEnv)
+	env.PopulateDB(c, s.Database)
+
+	model := env.Model("alice@canonical.com", "test-1").DBObject(c, s.Database)
+	model.OwnerIdentityName = "bob@canonical.com"
+	err = s.Database.UpdateModelOwner(ctx, &model)
+	c.Assert(err, qt.IsNil)
+
+	m := dbmodel.Model{UUID: model.UUID}
+	err = s.Database.GetModel(ctx, &m)
+	c.Assert(err, qt.IsNil)
+	c.Check(m.OwnerIdentityName, qt.Equals, "bob@canonical.com")
+	c.Check(m.Owner.Name, qt.Equals, "bob@canonical.com")
+
+	err = s.Database.UpdateModelOwner(ctx, &dbmodel.Model{ID: 1000, OwnerIdentityName: "bob@canonical.com"})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+}
@@ -0,0 +1,111 @@
+// Copyright 2024 Canonical.
+
+package db
+
+import (
+	"context"
+
+	"gorm.io/gorm/clause"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+// SetModelPolicy creates the given model policy, or replaces the
+// existing policy with the same name.
+func (d *Database) SetModelPolicy(ctx context.Context, p *dbmodel.ModelPolicy) (err error) {
+	const op = errors.Op("db.SetModelPolicy")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	db := d.DB.WithContext(ctx)
+	err = db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"groups", "clouds", "allowed_clouds", "required_config", "forbidden_config", "name_pattern", "allowed_credentials", "updated_at"}),
+	}).Create(p).Error
+	if err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// GetModelPolicy fills in the given model policy using its name. If
+// there is no such policy an error with a code of CodeNotFound is
+// returned.
+func (d *Database) GetModelPolicy(ctx context.Context, p *dbmodel.ModelPolicy) (err error) {
+	const op = errors.Op("db.GetModelPolicy")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	db := d.DB.WithContext(ctx)
+	if err := db.Where("name = ?", p.Name).First(p).Error; err != nil {
+		err = dbError(err)
+		if errors.ErrorCode(err) == errors.CodeNotFound {
+			return errors.E(op, err, "model policy not found")
+		}
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// DeleteModelPolicy removes the model policy with the name of the
+// given policy. If there is no such policy an error with a code of
+// CodeNotFound is returned.
+func (d *Database) DeleteModelPolicy(ctx context.Context, p *dbmodel.ModelPolicy) (err error) {
+	const op = errors.Op("db.DeleteModelPolicy")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	db := d.DB.WithContext(ctx)
+	result := db.Where("name = ?", p.Name).Delete(&dbmodel.ModelPolicy{})
+	if result.Error != nil {
+		return errors.E(op, dbError(result.Error))
+	}
+	if result.RowsAffected == 0 {
+		return errors.E(op, errors.CodeNotFound, "model policy not found")
+	}
+	return nil
+}
+
+// ListModelPolicies returns all the model policies, ordered by name.
+func (d *Database) ListModelPolicies(ctx context.Context) (_ []dbmodel.ModelPolicy, err error) {
+	const op = errors.Op("db.ListModelPolicies")
+	if err := d.ready(); err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	var policies []dbmodel.ModelPolicy
+	db := d.DB.WithContext(ctx)
+	if err := db.Order("name").Find(&policies).Error; err != nil {
+		return nil, errors.E(op, dbError(err))
+	}
+	return policies, nil
+}
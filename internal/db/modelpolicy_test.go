@@ -0,0 +1,83 @@
+// Copyright 2024 Canonical.
+
+package db_test
+
+import (
+	"context"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+func TestSetModelPolicyUnconfiguredDatabase(t *testing.T) {
+	c := qt.New(t)
+
+	var d db.Database
+	err := d.SetModelPolicy(context.Background(), &dbmodel.ModelPolicy{})
+	c.Check(err, qt.ErrorMatches, `database not configured`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeServerConfiguration)
+}
+
+func (s *dbSuite) TestModelPolicies(c *qt.C) {
+	ctx := context.Background()
+	err := s.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	p1 := dbmodel.ModelPolicy{
+		Name:               "prod",
+		Groups:             dbmodel.Strings{"00000000-0000-0000-0000-000000000001"},
+		Clouds:             dbmodel.Strings{"test-cloud/prod-region"},
+		RequiredConfig:     dbmodel.StringMap{"logging-config": "<root>=WARNING"},
+		ForbiddenConfig:    dbmodel.StringMap{"development": "true"},
+		NamePattern:        "prod-.*",
+		AllowedCredentials: dbmodel.Strings{"test-cloud/*/prod"},
+	}
+	err = s.Database.SetModelPolicy(ctx, &p1)
+	c.Assert(err, qt.IsNil)
+	err = s.Database.SetModelPolicy(ctx, &dbmodel.ModelPolicy{
+		Name:          "students",
+		AllowedClouds: dbmodel.Strings{"test-cloud"},
+	})
+	c.Assert(err, qt.IsNil)
+
+	p := dbmodel.ModelPolicy{Name: "prod"}
+	err = s.Database.GetModelPolicy(ctx, &p)
+	c.Assert(err, qt.IsNil)
+	c.Check(p.Groups, qt.DeepEquals, p1.Groups)
+	c.Check(p.Clouds, qt.DeepEquals, p1.Clouds)
+	c.Check(p.RequiredConfig, qt.DeepEquals, p1.RequiredConfig)
+	c.Check(p.ForbiddenConfig, qt.DeepEquals, p1.ForbiddenConfig)
+	c.Check(p.NamePattern, qt.Equals, "prod-.*")
+	c.Check(p.AllowedCredentials, qt.DeepEquals, p1.AllowedCredentials)
+
+	// Setting an existing policy replaces it.
+	err = s.Database.SetModelPolicy(ctx, &dbmodel.ModelPolicy{
+		Name:        "prod",
+		NamePattern: "p-.*",
+	})
+	c.Assert(err, qt.IsNil)
+	p = dbmodel.ModelPolicy{Name: "prod"}
+	err = s.Database.GetModelPolicy(ctx, &p)
+	c.Assert(err, qt.IsNil)
+	c.Check(p.NamePattern, qt.Equals, "p-.*")
+	c.Check(p.Groups, qt.IsNil)
+	c.Check(p.RequiredConfig, qt.IsNil)
+
+	all, err := s.Database.ListModelPolicies(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Assert(all, qt.HasLen, 2)
+	c.Check(all[0].Name, qt.Equals, "prod")
+	c.Check(all[1].Name, qt.Equals, "students")
+	c.Check(all[1].AllowedClouds, qt.DeepEquals, dbmodel.Strings{"test-cloud"})
+
+	err = s.Database.DeleteModelPolicy(ctx, &dbmodel.ModelPolicy{Name: "students"})
+	c.Assert(err, qt.IsNil)
+	err = s.Database.DeleteModelPolicy(ctx, &dbmodel.ModelPolicy{Name: "students"})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+	err = s.Database.GetModelPolicy(ctx, &dbmodel.ModelPolicy{Name: "students"})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+}
@@ -0,0 +1,139 @@
+// Copyright 2024 Canonical.
+
+package db
+
+import (
+	"context"
+
+	"gorm.io/gorm/clause"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+// modelQuotaLockID is the key of the PostgreSQL advisory lock held while
+// models are counted and added against the model quotas.
+const modelQuotaLockID = 0x6a696d6d71756f74
+
+// LockModelQuotas takes a lock that serialises adding models that are
+// subject to model quotas, so that concurrent additions cannot both see
+// the usage below a quota. The database must be in a transaction, the lock
+// is held until the transaction ends.
+func (d *Database) LockModelQuotas(ctx context.Context) (err error) {
+	const op = errors.Op("db.LockModelQuotas")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	db := d.DB.WithContext(ctx)
+	if err := db.Exec("SELECT pg_advisory_xact_lock(?)", modelQuotaLockID).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// SetModelQuota creates the given model quota, or updates the maximum
+// number of models of the existing quota with the same kind, subject and
+// region.
+func (d *Database) SetModelQuota(ctx context.Context, q *dbmodel.ModelQuota) (err error) {
+	const op = errors.Op("db.SetModelQuota")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	db := d.DB.WithContext(ctx)
+	err = db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "kind"}, {Name: "subject"}, {Name: "region"}},
+		DoUpdates: clause.AssignmentColumns([]string{"max_models", "updated_at"}),
+	}).Create(q).Error
+	if err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// DeleteModelQuota removes the model quota with the kind, subject and
+// region of the given quota. If there is no such quota an error with a
+// code of CodeNotFound is returned.
+func (d *Database) DeleteModelQuota(ctx context.Context, q *dbmodel.ModelQuota) (err error) {
+	const op = errors.Op("db.DeleteModelQuota")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	db := d.DB.WithContext(ctx)
+	result := db.Where("kind = ? AND subject = ? AND region = ?", q.Kind, q.Subject, q.Region).Delete(&dbmodel.ModelQuota{})
+	if result.Error != nil {
+		return errors.E(op, dbError(result.Error))
+	}
+	if result.RowsAffected == 0 {
+		return errors.E(op, errors.CodeNotFound, "model quota not found")
+	}
+	return nil
+}
+
+// ListModelQuotas returns all the model quotas, ordered by kind, subject
+// and region.
+func (d *Database) ListModelQuotas(ctx context.Context) (_ []dbmodel.ModelQuota, err error) {
+	const op = errors.Op("db.ListModelQuotas")
+	if err := d.ready(); err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	var quotas []dbmodel.ModelQuota
+	db := d.DB.WithContext(ctx)
+	if err := db.Order("kind, subject, region").Find(&quotas).Error; err != nil {
+		return nil, errors.E(op, dbError(err))
+	}
+	return quotas, nil
+}
+
+// FindModelQuotas returns the model quotas of the given kind that apply to
+// any of the given subjects, ordered by subject and region.
+func (d *Database) FindModelQuotas(ctx context.Context, kind dbmodel.ModelQuotaKind, subjects ...string) (_ []dbmodel.ModelQuota, err error) {
+	const op = errors.Op("db.FindModelQuotas")
+	if len(subjects) == 0 {
+		return nil, nil
+	}
+	if err := d.ready(); err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	var quotas []dbmodel.ModelQuota
+	db := d.DB.WithContext(ctx)
+	if err := db.Where("kind = ? AND subject IN ?", kind, subjects).Order("subject, region").Find(&quotas).Error; err != nil {
+		return nil, errors.E(op, dbError(err))
+	}
+	return quotas, nil
+}
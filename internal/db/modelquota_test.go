@@ -0,0 +1,91 @@
+// Copyright 2024 Canonical.
+
+package db_test
+
+import (
+	"context"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+func TestSetModelQuotaUnconfiguredDatabase(t *testing.T) {
+	c := qt.New(t)
+
+	var d db.Database
+	err := d.SetModelQuota(context.Background(), &dbmodel.ModelQuota{})
+	c.Check(err, qt.ErrorMatches, `database not configured`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeServerConfiguration)
+}
+
+func (s *dbSuite) TestModelQuotas(c *qt.C) {
+	ctx := context.Background()
+	err := s.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	quotas := []dbmodel.ModelQuota{{
+		Kind:      dbmodel.ModelQuotaIdentity,
+		Subject:   "alice@canonical.com",
+		MaxModels: 2,
+	}, {
+		Kind:      dbmodel.ModelQuotaGroup,
+		Subject:   "00000000-0000-0000-0000-000000000001",
+		MaxModels: 3,
+	}, {
+		Kind:      dbmodel.ModelQuotaCloud,
+		Subject:   "test-cloud",
+		MaxModels: 10,
+	}, {
+		Kind:      dbmodel.ModelQuotaCloud,
+		Subject:   "test-cloud",
+		Region:    "test-region",
+		MaxModels: 5,
+	}}
+	for i := range quotas {
+		err := s.Database.SetModelQuota(ctx, &quotas[i])
+		c.Assert(err, qt.IsNil)
+	}
+
+	// Setting an existing quota updates the limit.
+	err = s.Database.SetModelQuota(ctx, &dbmodel.ModelQuota{
+		Kind:      dbmodel.ModelQuotaIdentity,
+		Subject:   "alice@canonical.com",
+		MaxModels: 4,
+	})
+	c.Assert(err, qt.IsNil)
+
+	found, err := s.Database.FindModelQuotas(ctx, dbmodel.ModelQuotaIdentity, "alice@canonical.com", "bob@canonical.com")
+	c.Assert(err, qt.IsNil)
+	c.Assert(found, qt.HasLen, 1)
+	c.Check(found[0].MaxModels, qt.Equals, 4)
+
+	found, err = s.Database.FindModelQuotas(ctx, dbmodel.ModelQuotaCloud, "test-cloud")
+	c.Assert(err, qt.IsNil)
+	c.Assert(found, qt.HasLen, 2)
+	c.Check(found[0].Region, qt.Equals, "")
+	c.Check(found[1].Region, qt.Equals, "test-region")
+
+	found, err = s.Database.FindModelQuotas(ctx, dbmodel.ModelQuotaGroup)
+	c.Assert(err, qt.IsNil)
+	c.Check(found, qt.HasLen, 0)
+
+	all, err := s.Database.ListModelQuotas(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Assert(all, qt.HasLen, 4)
+	c.Check(all[0].Kind, qt.Equals, dbmodel.ModelQuotaCloud)
+	c.Check(all[2].Kind, qt.Equals, dbmodel.ModelQuotaGroup)
+	c.Check(all[3].Kind, qt.Equals, dbmodel.ModelQuotaIdentity)
+
+	err = s.Database.DeleteModelQuota(ctx, &dbmodel.ModelQuota{Kind: dbmodel.ModelQuotaCloud, Subject: "test-cloud", Region: "test-region"})
+	c.Assert(err, qt.IsNil)
+	err = s.Database.DeleteModelQuota(ctx, &dbmodel.ModelQuota{Kind: dbmodel.ModelQuotaCloud, Subject: "test-cloud", Region: "test-region"})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	all, err = s.Database.ListModelQuotas(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Check(all, qt.HasLen, 3)
+}
@@ -0,0 +1,172 @@
+// Copyright 2024 Canonical.
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/stdlib"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+// modelSummaryChannel is the notification channel on which the UUIDs of
+// models with updated summaries are sent.
+const modelSummaryChannel = "jimm_model_summaries"
+
+// PublishModelSummary stores the given model summary, replacing any
+// previous summary of the model, and notifies every WatchModelSummaries
+// caller that it has changed.
+func (d *Database) PublishModelSummary(ctx context.Context, s *dbmodel.ModelSummary) (err error) {
+	const op = errors.Op("db.PublishModelSummary")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	err = d.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "model_uuid"}},
+			DoUpdates: clause.AssignmentColumns([]string{"summary", "updated_at"}),
+		}).Create(s).Error
+		if err != nil {
+			return err
+		}
+		// The notification is only sent when the transaction commits.
+		return tx.Exec("SELECT pg_notify(?, ?)", modelSummaryChannel, s.ModelUUID).Error
+	})
+	if err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// GetModelSummary fills in the given model summary using its model UUID.
+// If there is no such summary an error with a code of CodeNotFound is
+// returned.
+func (d *Database) GetModelSummary(ctx context.Context, s *dbmodel.ModelSummary) (err error) {
+	const op = errors.Op("db.GetModelSummary")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	db := d.DB.WithContext(ctx)
+	if err := db.Where("model_uuid = ?", s.ModelUUID).First(s).Error; err != nil {
+		err = dbError(err)
+		if errors.ErrorCode(err) == errors.CodeNotFound {
+			return errors.E(op, err, "model summary not found")
+		}
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// ListModelSummaries returns the stored summaries of all models.
+func (d *Database) ListModelSummaries(ctx context.Context) (_ []dbmodel.ModelSummary, err error) {
+	const op = errors.Op("db.ListModelSummaries")
+	if err := d.ready(); err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	db := d.DB.WithContext(ctx)
+	var summaries []dbmodel.ModelSummary
+	if err := db.Order("model_uuid").Find(&summaries).Error; err != nil {
+		return nil, errors.E(op, dbError(err))
+	}
+	return summaries, nil
+}
+
+// WatchModelSummaries calls the given function with every stored model
+// summary, and then with each model summary as it is published, by any
+// JIMM replica, until the given context is canceled or there is an error
+// receiving notifications. A dedicated database connection is held for
+// the duration of the watch. WatchModelSummaries always returns a non-nil
+// error.
+func (d *Database) WatchModelSummaries(ctx context.Context, f func(*dbmodel.ModelSummary)) error {
+	const op = errors.Op("db.WatchModelSummaries")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	sqlDB, err := d.DB.DB()
+	if err != nil {
+		return errors.E(op, err)
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return errors.E(op, dbError(err))
+	}
+	defer conn.Close()
+
+	// Start listening before reading the stored summaries so that no
+	// update can be missed.
+	if _, err := conn.ExecContext(ctx, "LISTEN "+modelSummaryChannel); err != nil {
+		return errors.E(op, dbError(err))
+	}
+	defer func() {
+		// Stop listening before the connection is returned to the
+		// pool. If this fails the connection has been closed.
+		_, _ = conn.ExecContext(context.WithoutCancel(ctx), "UNLISTEN "+modelSummaryChannel)
+	}()
+
+	summaries, err := d.ListModelSummaries(ctx)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	for i := range summaries {
+		f(&summaries[i])
+	}
+
+	for {
+		var modelUUID string
+		err := conn.Raw(func(driverConn any) error {
+			c, ok := driverConn.(*stdlib.Conn)
+			if !ok {
+				return errors.E(errors.CodeNotSupported, "database driver does not support notifications")
+			}
+			n, err := c.Conn().WaitForNotification(ctx)
+			if err != nil {
+				return err
+			}
+			modelUUID = n.Payload
+			return nil
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				return errors.E(op, ctx.Err())
+			}
+			return errors.E(op, err)
+		}
+		s := dbmodel.ModelSummary{ModelUUID: modelUUID}
+		if err := d.GetModelSummary(ctx, &s); err != nil {
+			if errors.ErrorCode(err) == errors.CodeNotFound {
+				// The model has been removed since the summary was
+				// published.
+				continue
+			}
+			return errors.E(op, err)
+		}
+		f(&s)
+	}
+}
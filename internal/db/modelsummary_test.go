@@ -0,0 +1,83 @@
+// Copyright 2024 Canonical.
+
+package db_test
+
+import (
+	"context"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/testutils/jimmtest"
+)
+
+func TestPublishModelSummaryUnconfiguredDatabase(t *testing.T) {
+	c := qt.New(t)
+
+	var d db.Database
+	err := d.PublishModelSummary(context.Background(), &dbmodel.ModelSummary{})
+	c.Check(err, qt.ErrorMatches, `database not configured`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeServerConfiguration)
+}
+
+func (s *dbSuite) TestModelSummaries(c *qt.C) {
+	ctx := context.Background()
+	err := s.Database.Migrate(ctx, true)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, testCountModelsByControllerEnv)
+	env.PopulateDB(c, s.Database)
+
+	summary := dbmodel.ModelSummary{ModelUUID: "00000002-0000-0000-0000-000000000001"}
+	err = s.Database.GetModelSummary(ctx, &summary)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	summary.Summary = dbmodel.JSON(`{"status": "available"}`)
+	err = s.Database.PublishModelSummary(ctx, &summary)
+	c.Assert(err, qt.IsNil)
+	summary = dbmodel.ModelSummary{
+		ModelUUID: "00000002-0000-0000-0000-000000000002",
+		Summary:   dbmodel.JSON(`{"status": "busy"}`),
+	}
+	err = s.Database.PublishModelSummary(ctx, &summary)
+	c.Assert(err, qt.IsNil)
+
+	// A new summary replaces the previous one.
+	summary = dbmodel.ModelSummary{
+		ModelUUID: "00000002-0000-0000-0000-000000000001",
+		Summary:   dbmodel.JSON(`{"status": "error"}`),
+	}
+	err = s.Database.PublishModelSummary(ctx, &summary)
+	c.Assert(err, qt.IsNil)
+
+	summary = dbmodel.ModelSummary{ModelUUID: "00000002-0000-0000-0000-000000000001"}
+	err = s.Database.GetModelSummary(ctx, &summary)
+	c.Assert(err, qt.IsNil)
+	c.Check(string(summary.Summary), qt.JSONEquals, map[string]string{"status": "error"})
+
+	summaries, err := s.Database.ListModelSummaries(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Assert(summaries, qt.HasLen, 2)
+	c.Check(summaries[0].ModelUUID, qt.Equals, "00000002-0000-0000-0000-000000000001")
+	c.Check(summaries[1].ModelUUID, qt.Equals, "00000002-0000-0000-0000-000000000002")
+	c.Check(string(summaries[1].Summary), qt.JSONEquals, map[string]string{"status": "busy"})
+
+	// Summaries are removed with their model.
+	m := env.Model("bob@canonical.com", "test-2").DBObject(c, s.Database)
+	err = s.Database.DeleteModel(ctx, &m)
+	c.Assert(err, qt.IsNil)
+	summaries, err = s.Database.ListModelSummaries(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Check(summaries, qt.HasLen, 1)
+
+	// Summaries can only be stored for known models.
+	summary = dbmodel.ModelSummary{
+		ModelUUID: "00000002-0000-0000-0000-000000000009",
+		Summary:   dbmodel.JSON(`{}`),
+	}
+	err = s.Database.PublishModelSummary(ctx, &summary)
+	c.Check(err, qt.Not(qt.IsNil))
+}
@@ -0,0 +1,111 @@
+// Copyright 2024 Canonical.
+
+package db
+
+import (
+	"context"
+
+	"gorm.io/gorm/clause"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+// SetModelTemplate creates the given model template, or replaces the
+// existing template with the same name.
+func (d *Database) SetModelTemplate(ctx context.Context, t *dbmodel.ModelTemplate) (err error) {
+	const op = errors.Op("db.SetModelTemplate")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	db := d.DB.WithContext(ctx)
+	err = db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"config", "cloud", "region", "cloud_credential", "labels", "grants", "updated_at"}),
+	}).Create(t).Error
+	if err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// GetModelTemplate fills in the given model template using its name. If
+// there is no such template an error with a code of CodeNotFound is
+// returned.
+func (d *Database) GetModelTemplate(ctx context.Context, t *dbmodel.ModelTemplate) (err error) {
+	const op = errors.Op("db.GetModelTemplate")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	db := d.DB.WithContext(ctx)
+	if err := db.Where("name = ?", t.Name).First(t).Error; err != nil {
+		err = dbError(err)
+		if errors.ErrorCode(err) == errors.CodeNotFound {
+			return errors.E(op, err, "model template not found")
+		}
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// DeleteModelTemplate removes the model template with the name of the
+// given template. If there is no such template an error with a code of
+// CodeNotFound is returned.
+func (d *Database) DeleteModelTemplate(ctx context.Context, t *dbmodel.ModelTemplate) (err error) {
+	const op = errors.Op("db.DeleteModelTemplate")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	db := d.DB.WithContext(ctx)
+	result := db.Where("name = ?", t.Name).Delete(&dbmodel.ModelTemplate{})
+	if result.Error != nil {
+		return errors.E(op, dbError(result.Error))
+	}
+	if result.RowsAffected == 0 {
+		return errors.E(op, errors.CodeNotFound, "model template not found")
+	}
+	return nil
+}
+
+// ListModelTemplates returns all the model templates, ordered by name.
+func (d *Database) ListModelTemplates(ctx context.Context) (_ []dbmodel.ModelTemplate, err error) {
+	const op = errors.Op("db.ListModelTemplates")
+	if err := d.ready(); err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	var templates []dbmodel.ModelTemplate
+	db := d.DB.WithContext(ctx)
+	if err := db.Order("name").Find(&templates).Error; err != nil {
+		return nil, errors.E(op, dbError(err))
+	}
+	return templates, nil
+}
@@ -0,0 +1,84 @@
+// Copyright 2024 Canonical.
+
+package db_test
+
+import (
+	"context"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+func TestSetModelTemplateUnconfiguredDatabase(t *testing.T) {
+	c := qt.New(t)
+
+	var d db.Database
+	err := d.SetModelTemplate(context.Background(), &dbmodel.ModelTemplate{})
+	c.Check(err, qt.ErrorMatches, `database not configured`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeServerConfiguration)
+}
+
+func (s *dbSuite) TestModelTemplates(c *qt.C) {
+	ctx := context.Background()
+	err := s.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	t1 := dbmodel.ModelTemplate{
+		Name:   "prod",
+		Config: dbmodel.Map{"logging-config": "<root>=WARNING"},
+		Cloud:  "test-cloud",
+		Region: "test-region",
+		Labels: dbmodel.StringMap{"env": "prod"},
+		Grants: dbmodel.StringMap{"ops": "admin"},
+	}
+	err = s.Database.SetModelTemplate(ctx, &t1)
+	c.Assert(err, qt.IsNil)
+	t2 := dbmodel.ModelTemplate{
+		Name:            "dev",
+		CloudCredential: "dev-cred",
+	}
+	err = s.Database.SetModelTemplate(ctx, &t2)
+	c.Assert(err, qt.IsNil)
+
+	t := dbmodel.ModelTemplate{Name: "prod"}
+	err = s.Database.GetModelTemplate(ctx, &t)
+	c.Assert(err, qt.IsNil)
+	c.Check(t.Config, qt.DeepEquals, t1.Config)
+	c.Check(t.Cloud, qt.Equals, "test-cloud")
+	c.Check(t.Region, qt.Equals, "test-region")
+	c.Check(t.Labels, qt.DeepEquals, t1.Labels)
+	c.Check(t.Grants, qt.DeepEquals, t1.Grants)
+
+	// Setting an existing template replaces it.
+	err = s.Database.SetModelTemplate(ctx, &dbmodel.ModelTemplate{
+		Name:  "prod",
+		Cloud: "other-cloud",
+	})
+	c.Assert(err, qt.IsNil)
+	t = dbmodel.ModelTemplate{Name: "prod"}
+	err = s.Database.GetModelTemplate(ctx, &t)
+	c.Assert(err, qt.IsNil)
+	c.Check(t.Cloud, qt.Equals, "other-cloud")
+	c.Check(t.Region, qt.Equals, "")
+	c.Check(t.Config, qt.IsNil)
+	c.Check(t.Grants, qt.IsNil)
+
+	all, err := s.Database.ListModelTemplates(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Assert(all, qt.HasLen, 2)
+	c.Check(all[0].Name, qt.Equals, "dev")
+	c.Check(all[0].CloudCredential, qt.Equals, "dev-cred")
+	c.Check(all[1].Name, qt.Equals, "prod")
+
+	err = s.Database.DeleteModelTemplate(ctx, &dbmodel.ModelTemplate{Name: "dev"})
+	c.Assert(err, qt.IsNil)
+	err = s.Database.DeleteModelTemplate(ctx, &dbmodel.ModelTemplate{Name: "dev"})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	err = s.Database.GetModelTemplate(ctx, &dbmodel.ModelTemplate{Name: "dev"})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+}
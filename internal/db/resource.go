@@ -80,7 +80,8 @@ type Resource struct {
 
 // ListResources returns a list of models, clouds, controllers, service accounts, and application offers, with its respective parents.
 // It has been implemented with a raw query because this is a specific implementation for the ReBAC Admin UI.
-func (d *Database) ListResources(ctx context.Context, limit, offset int, namePrefixFilter, typeFilter string) (_ []Resource, err error) {
+// If labelSelector is not empty only the models with matching labels are returned.
+func (d *Database) ListResources(ctx context.Context, limit, offset int, namePrefixFilter, typeFilter string, labelSelector dbmodel.LabelSelector) (_ []Resource, err error) {
 	const op = errors.Op("db.ListResources")
 	if err := d.ready(); err != nil {
 		return nil, errors.E(op, err)
@@ -89,11 +90,13 @@ func (d *Database) ListResources(ctx context.Context, limit, offset int, namePre
 	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
 	defer durationObserver()
 	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
 
 	db := d.DB.WithContext(ctx)
-	query, err := buildQuery(db, offset, limit, namePrefixFilter, typeFilter)
+	query, err := buildQuery(db, offset, limit, namePrefixFilter, typeFilter, labelSelector)
 	if err != nil {
-		return nil, err
+		return nil, errors.E(op, err)
 	}
 	rows, err := query.Rows()
 	if err != nil {
@@ -115,7 +118,8 @@ func (d *Database) ListResources(ctx context.Context, limit, offset int, namePre
 // buildQuery is a utility function to build the database query according to two optional parameters.
 // namePrefixFilter: used to match resources name prefix.
 // typeFilter: used to match resources type. If this is not empty the resources are fetched from a single table.
-func buildQuery(db *gorm.DB, offset, limit int, namePrefixFilter, typeFilter string) (*gorm.DB, error) {
+// labelSelector: used to match model labels. If this is not empty only models are fetched.
+func buildQuery(db *gorm.DB, offset, limit int, namePrefixFilter, typeFilter string, labelSelector dbmodel.LabelSelector) (*gorm.DB, error) {
 	applicationOffersQuery := db.Select(selectApplicationOffers).
 		Model(&dbmodel.ApplicationOffer{}).
 		Where("(CASE WHEN ? = '' THEN TRUE ELSE application_offers.name LIKE ? END)", namePrefixFilter, namePrefixFilter+"%").
@@ -133,11 +137,20 @@ func buildQuery(db *gorm.DB, offset, limit int, namePrefixFilter, typeFilter str
 		Model(&dbmodel.Model{}).
 		Where("(CASE WHEN ? = '' THEN TRUE ELSE models.name LIKE ? END)", namePrefixFilter, namePrefixFilter+"%").
 		Joins("JOIN controllers ON models.controller_id = controllers.id")
+	modelsQuery = whereModelLabels(modelsQuery, labelSelector)
 
 	serviceAccountsQuery := db.Select(selectIdentities).
 		Model(&dbmodel.Identity{}).
 		Where("name LIKE '%@serviceaccount' AND (CASE WHEN ? = '' THEN TRUE ELSE identities.name LIKE ? END)", namePrefixFilter, namePrefixFilter+"%")
 
+	// labels are only set on models.
+	if len(labelSelector) > 0 {
+		if typeFilter != "" && typeFilter != ModelsQueryKey {
+			return nil, errors.E(errors.CodeBadRequest, "label selectors can only be used with models")
+		}
+		typeFilter = ModelsQueryKey
+	}
+
 	// if the typeFilter is set we only return the query for that specif entityType, otherwise the union.
 	if typeFilter == "" {
 		return db.
@@ -70,12 +70,12 @@ func (s *dbSuite) TestGetResources(c *qt.C) {
 	ctx := context.Background()
 	err := s.Database.Migrate(context.Background(), true)
 	c.Assert(err, qt.Equals, nil)
-	res, err := s.Database.ListResources(ctx, 10, 0, "", "")
+	res, err := s.Database.ListResources(ctx, 10, 0, "", "", nil)
 	c.Assert(err, qt.Equals, nil)
 	c.Assert(res, qt.HasLen, 0)
 	// create one model, one controller, one cloud
 	model, controller, cloud, sva := SetupDB(c, s.Database)
-	res, err = s.Database.ListResources(ctx, 10, 0, "", "")
+	res, err = s.Database.ListResources(ctx, 10, 0, "", "", nil)
 	c.Assert(err, qt.Equals, nil)
 	c.Assert(res, qt.HasLen, 4)
 	for _, r := range res {
@@ -166,7 +166,7 @@ func (s *dbSuite) TestGetResourcesWithNameTypeFilter(c *qt.C) {
 	}
 	for _, t := range tests {
 		c.Run(t.description, func(c *qt.C) {
-			res, err := s.Database.ListResources(ctx, t.limit, t.offset, t.nameFilter, t.typeFilter)
+			res, err := s.Database.ListResources(ctx, t.limit, t.offset, t.nameFilter, t.typeFilter, nil)
 			c.Assert(err, qt.Equals, nil)
 			c.Assert(res, qt.HasLen, t.expectedSize)
 			for i, r := range res {
@@ -175,3 +175,44 @@ func (s *dbSuite) TestGetResourcesWithNameTypeFilter(c *qt.C) {
 		})
 	}
 }
+
+func (s *dbSuite) TestGetResourcesWithLabelSelector(c *qt.C) {
+	ctx := context.Background()
+	err := s.Database.Migrate(context.Background(), true)
+	c.Assert(err, qt.Equals, nil)
+	model, _, _, _ := SetupDB(c, s.Database)
+
+	err = s.Database.UpdateModelLabels(ctx, &model, map[string]string{"env": "prod", "team": "ops"}, nil)
+	c.Assert(err, qt.IsNil)
+
+	tests := []struct {
+		selector    string
+		expectCount int
+	}{
+		{"env=prod", 1},
+		{"env=prod,team=ops", 1},
+		{"env=staging", 0},
+		{"env!=staging", 1},
+		{"team", 1},
+		{"!team", 0},
+		{"!service", 1},
+	}
+	for _, t := range tests {
+		c.Run(t.selector, func(c *qt.C) {
+			sel, err := dbmodel.ParseLabelSelector(t.selector)
+			c.Assert(err, qt.IsNil)
+			res, err := s.Database.ListResources(ctx, 10, 0, "", "", sel)
+			c.Assert(err, qt.IsNil)
+			c.Assert(res, qt.HasLen, t.expectCount)
+			for _, r := range res {
+				c.Check(r.Type, qt.Equals, "model")
+				c.Check(r.ID.String, qt.Equals, model.UUID.String)
+			}
+		})
+	}
+
+	sel, err := dbmodel.ParseLabelSelector("env=prod")
+	c.Assert(err, qt.IsNil)
+	_, err = s.Database.ListResources(ctx, 10, 0, "", db.CloudsQueryKey, sel)
+	c.Assert(err, qt.ErrorMatches, `label selectors can only be used with models`)
+}
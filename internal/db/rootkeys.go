@@ -3,6 +3,7 @@
 package db
 
 import (
+	"context"
 	"time"
 
 	"github.com/go-macaroon-bakery/macaroon-bakery/v3/bakery"
@@ -16,7 +17,7 @@ import (
 
 // GetKey implements Backing.GetKey.
 func (d *Database) GetKey(id []byte) (_ dbrootkeystore.RootKey, err error) {
-	const op = errors.Op("db.FindLatestKey")
+	const op = errors.Op("db.GetKey")
 
 	if err := d.ready(); err != nil {
 		return dbrootkeystore.RootKey{}, bakery.ErrNotFound
@@ -25,11 +26,14 @@ func (d *Database) GetKey(id []byte) (_ dbrootkeystore.RootKey, err error) {
 	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
 	defer durationObserver()
 	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	// The bakery does not pass a context to the backing store.
+	ctx, span := servermon.StartSpan(context.Background(), string(op))
+	defer servermon.EndSpan(span, &err)
 
 	rk := dbmodel.RootKey{
 		ID: id,
 	}
-	if err := d.DB.First(&rk).Error; err != nil {
+	if err := d.DB.WithContext(ctx).First(&rk).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return dbrootkeystore.RootKey{}, bakery.ErrNotFound
 		}
@@ -54,8 +58,11 @@ func (d *Database) FindLatestKey(createdAfter, expiresAfter, expiresBefore time.
 	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
 	defer durationObserver()
 	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	// The bakery does not pass a context to the backing store.
+	ctx, span := servermon.StartSpan(context.Background(), string(op))
+	defer servermon.EndSpan(span, &err)
 
-	db := d.DB.Where("created_at > ?", createdAfter)
+	db := d.DB.WithContext(ctx).Where("created_at > ?", createdAfter)
 	db = db.Where("expires BETWEEN ? AND ?", expiresAfter, expiresBefore)
 	db = db.Order("created_at DESC")
 	var rk dbmodel.RootKey
@@ -84,6 +91,9 @@ func (d *Database) InsertKey(key dbrootkeystore.RootKey) (err error) {
 	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
 	defer durationObserver()
 	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	// The bakery does not pass a context to the backing store.
+	ctx, span := servermon.StartSpan(context.Background(), string(op))
+	defer servermon.EndSpan(span, &err)
 
 	rk := dbmodel.RootKey{
 		ID:        key.Id,
@@ -91,7 +101,7 @@ func (d *Database) InsertKey(key dbrootkeystore.RootKey) (err error) {
 		Expires:   key.Expires,
 		RootKey:   key.RootKey,
 	}
-	if err := d.DB.Create(&rk).Error; err != nil {
+	if err := d.DB.WithContext(ctx).Create(&rk).Error; err != nil {
 		return errors.E(op, dbError(err))
 	}
 	return nil
@@ -0,0 +1,295 @@
+// Copyright 2024 Canonical.
+
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+// RegisterScheduledJob ensures that the given scheduled job is stored. If
+// the job is not already stored it is added. If the stored job has a
+// different schedule or jitter these, and the next run time, are updated
+// from the given job. Otherwise the stored next run time is kept. On
+// success the given job is filled in with the stored job.
+func (d *Database) RegisterScheduledJob(ctx context.Context, j *dbmodel.ScheduledJob) (err error) {
+	const op = errors.Op("db.RegisterScheduledJob")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	err = d.Transaction(func(tx *Database) error {
+		db := tx.DB.WithContext(ctx)
+		var stored dbmodel.ScheduledJob
+		err := db.Where("name = ?", j.Name).Limit(1).Find(&stored).Error
+		if err != nil {
+			return err
+		}
+		if stored.Name == "" {
+			return db.Create(j).Error
+		}
+		if stored.Schedule != j.Schedule || stored.Jitter != j.Jitter {
+			stored.Schedule = j.Schedule
+			stored.Jitter = j.Jitter
+			stored.NextRunAt = j.NextRunAt
+			if err := db.Model(&stored).Select("schedule", "jitter", "next_run_at").Updates(&stored).Error; err != nil {
+				return err
+			}
+		}
+		*j = stored
+		return nil
+	})
+	if err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// GetScheduledJob fills in the given scheduled job using its name. If
+// there is no such job an error with a code of CodeNotFound is returned.
+func (d *Database) GetScheduledJob(ctx context.Context, j *dbmodel.ScheduledJob) (err error) {
+	const op = errors.Op("db.GetScheduledJob")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	db := d.DB.WithContext(ctx)
+	if err := db.Where("name = ?", j.Name).First(j).Error; err != nil {
+		err = dbError(err)
+		if errors.ErrorCode(err) == errors.CodeNotFound {
+			return errors.E(op, err, "scheduled job not found")
+		}
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// ListScheduledJobs returns all the scheduled jobs, ordered by name.
+func (d *Database) ListScheduledJobs(ctx context.Context) (_ []dbmodel.ScheduledJob, err error) {
+	const op = errors.Op("db.ListScheduledJobs")
+	if err := d.ready(); err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	db := d.DB.WithContext(ctx)
+	var jobs []dbmodel.ScheduledJob
+	if err := db.Order("name").Find(&jobs).Error; err != nil {
+		return nil, errors.E(op, dbError(err))
+	}
+	return jobs, nil
+}
+
+// UpdateScheduledJobNextRun updates the next run time of the given
+// scheduled job. If there is no such job an error with a code of
+// CodeNotFound is returned.
+func (d *Database) UpdateScheduledJobNextRun(ctx context.Context, j *dbmodel.ScheduledJob) (err error) {
+	const op = errors.Op("db.UpdateScheduledJobNextRun")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	db := d.DB.WithContext(ctx)
+	result := db.Model(j).Select("next_run_at").Updates(j)
+	if result.Error != nil {
+		return errors.E(op, dbError(result.Error))
+	}
+	if result.RowsAffected == 0 {
+		return errors.E(op, errors.CodeNotFound, "scheduled job not found")
+	}
+	return nil
+}
+
+// TriggerScheduledJob stores the trigger time and user of the given
+// scheduled job, requesting that it is run immediately. If there is no
+// such job an error with a code of CodeNotFound is returned.
+func (d *Database) TriggerScheduledJob(ctx context.Context, j *dbmodel.ScheduledJob) (err error) {
+	const op = errors.Op("db.TriggerScheduledJob")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	db := d.DB.WithContext(ctx)
+	result := db.Model(j).Select("triggered_at", "triggered_by").Updates(j)
+	if result.Error != nil {
+		return errors.E(op, dbError(result.Error))
+	}
+	if result.RowsAffected == 0 {
+		return errors.E(op, errors.CodeNotFound, "scheduled job not found")
+	}
+	return nil
+}
+
+// ClearScheduledJobTrigger clears the trigger of the given scheduled job,
+// as long as the job has not been triggered again since it was read.
+func (d *Database) ClearScheduledJobTrigger(ctx context.Context, j *dbmodel.ScheduledJob) (err error) {
+	const op = errors.Op("db.ClearScheduledJobTrigger")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	db := d.DB.WithContext(ctx)
+	err = db.Model(&dbmodel.ScheduledJob{}).
+		Where("name = ? AND triggered_at = ?", j.Name, j.TriggeredAt).
+		Updates(map[string]interface{}{"triggered_at": nil, "triggered_by": ""}).Error
+	if err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// AddJobRun stores the given job run.
+func (d *Database) AddJobRun(ctx context.Context, r *dbmodel.JobRun) (err error) {
+	const op = errors.Op("db.AddJobRun")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	db := d.DB.WithContext(ctx)
+	if err := db.Create(r).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// UpdateJobRun updates the status, finish time and error of the given
+// job run. If there is no such run an error with a code of CodeNotFound
+// is returned.
+func (d *Database) UpdateJobRun(ctx context.Context, r *dbmodel.JobRun) (err error) {
+	const op = errors.Op("db.UpdateJobRun")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	db := d.DB.WithContext(ctx)
+	result := db.Model(r).Select("status", "finished_at", "error").Updates(r)
+	if result.Error != nil {
+		return errors.E(op, dbError(result.Error))
+	}
+	if result.RowsAffected == 0 {
+		return errors.E(op, errors.CodeNotFound, "job run not found")
+	}
+	return nil
+}
+
+// ListJobRuns returns at most limit runs of the named job, most recent
+// first.
+func (d *Database) ListJobRuns(ctx context.Context, job string, limit int) (_ []dbmodel.JobRun, err error) {
+	const op = errors.Op("db.ListJobRuns")
+	if err := d.ready(); err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	db := d.DB.WithContext(ctx)
+	var runs []dbmodel.JobRun
+	if err := db.Where("job_name = ?", job).Order("id DESC").Limit(limit).Find(&runs).Error; err != nil {
+		return nil, errors.E(op, dbError(err))
+	}
+	return runs, nil
+}
+
+// InterruptJobRuns marks every job run that is still running as
+// interrupted, finishing at the given time. It is used when a new leader
+// takes over, any runs still running were abandoned by the previous
+// leader. The number of interrupted runs is returned.
+func (d *Database) InterruptJobRuns(ctx context.Context, finishedAt time.Time) (_ int64, err error) {
+	const op = errors.Op("db.InterruptJobRuns")
+	if err := d.ready(); err != nil {
+		return 0, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	db := d.DB.WithContext(ctx)
+	result := db.Model(&dbmodel.JobRun{}).
+		Where("status = ?", dbmodel.JobRunRunning).
+		Updates(map[string]interface{}{"status": dbmodel.JobRunInterrupted, "finished_at": finishedAt})
+	if result.Error != nil {
+		return 0, errors.E(op, dbError(result.Error))
+	}
+	return result.RowsAffected, nil
+}
+
+// PruneJobRuns removes all but the most recent keep runs of the named
+// job.
+func (d *Database) PruneJobRuns(ctx context.Context, job string, keep int) (err error) {
+	const op = errors.Op("db.PruneJobRuns")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	db := d.DB.WithContext(ctx)
+	recent := db.Model(&dbmodel.JobRun{}).Select("id").Where("job_name = ?", job).Order("id DESC").Limit(keep)
+	err = db.Where("job_name = ? AND id NOT IN (?)", job, recent).Delete(&dbmodel.JobRun{}).Error
+	if err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
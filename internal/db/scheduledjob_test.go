@@ -0,0 +1,169 @@
+// Copyright 2024 Canonical.
+
+package db_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+func TestRegisterScheduledJobUnconfiguredDatabase(t *testing.T) {
+	c := qt.New(t)
+
+	var d db.Database
+	err := d.RegisterScheduledJob(context.Background(), &dbmodel.ScheduledJob{})
+	c.Check(err, qt.ErrorMatches, `database not configured`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeServerConfiguration)
+}
+
+func (s *dbSuite) TestScheduledJobs(c *qt.C) {
+	ctx := context.Background()
+	err := s.Database.Migrate(ctx, true)
+	c.Assert(err, qt.IsNil)
+
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	next := sql.NullTime{Time: now.Add(time.Hour), Valid: true}
+
+	job := dbmodel.ScheduledJob{
+		Name:      "job-1",
+		Schedule:  "@hourly",
+		NextRunAt: next,
+	}
+	err = s.Database.RegisterScheduledJob(ctx, &job)
+	c.Assert(err, qt.IsNil)
+
+	// Registering the same job again keeps the stored next run.
+	job = dbmodel.ScheduledJob{
+		Name:      "job-1",
+		Schedule:  "@hourly",
+		NextRunAt: sql.NullTime{Time: now.Add(2 * time.Hour), Valid: true},
+	}
+	err = s.Database.RegisterScheduledJob(ctx, &job)
+	c.Assert(err, qt.IsNil)
+	c.Check(job.NextRunAt.Time.Equal(next.Time), qt.IsTrue)
+
+	// Changing the schedule replaces the next run.
+	job = dbmodel.ScheduledJob{
+		Name:      "job-1",
+		Schedule:  "@daily",
+		Jitter:    time.Minute,
+		NextRunAt: sql.NullTime{Time: now.Add(24 * time.Hour), Valid: true},
+	}
+	err = s.Database.RegisterScheduledJob(ctx, &job)
+	c.Assert(err, qt.IsNil)
+
+	job = dbmodel.ScheduledJob{Name: "job-1"}
+	err = s.Database.GetScheduledJob(ctx, &job)
+	c.Assert(err, qt.IsNil)
+	c.Check(job.Schedule, qt.Equals, "@daily")
+	c.Check(job.Jitter, qt.Equals, time.Minute)
+	c.Check(job.NextRunAt.Time.Equal(now.Add(24*time.Hour)), qt.IsTrue)
+
+	err = s.Database.RegisterScheduledJob(ctx, &dbmodel.ScheduledJob{Name: "job-0", Schedule: "@every 1m"})
+	c.Assert(err, qt.IsNil)
+	jobs, err := s.Database.ListScheduledJobs(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Assert(jobs, qt.HasLen, 2)
+	c.Check(jobs[0].Name, qt.Equals, "job-0")
+	c.Check(jobs[1].Name, qt.Equals, "job-1")
+
+	job.NextRunAt = sql.NullTime{Time: now, Valid: true}
+	err = s.Database.UpdateScheduledJobNextRun(ctx, &job)
+	c.Assert(err, qt.IsNil)
+
+	job.TriggeredAt = sql.NullTime{Time: now, Valid: true}
+	job.TriggeredBy = "alice@canonical.com"
+	err = s.Database.TriggerScheduledJob(ctx, &job)
+	c.Assert(err, qt.IsNil)
+
+	err = s.Database.TriggerScheduledJob(ctx, &dbmodel.ScheduledJob{Name: "no-such-job"})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	// A trigger is not cleared if the job has been triggered again.
+	stale := job
+	stale.TriggeredAt = sql.NullTime{Time: now.Add(-time.Minute), Valid: true}
+	err = s.Database.ClearScheduledJobTrigger(ctx, &stale)
+	c.Assert(err, qt.IsNil)
+	job2 := dbmodel.ScheduledJob{Name: "job-1"}
+	err = s.Database.GetScheduledJob(ctx, &job2)
+	c.Assert(err, qt.IsNil)
+	c.Check(job2.TriggeredAt.Valid, qt.IsTrue)
+	c.Check(job2.TriggeredBy, qt.Equals, "alice@canonical.com")
+	c.Check(job2.NextRunAt.Time.Equal(now), qt.IsTrue)
+
+	err = s.Database.ClearScheduledJobTrigger(ctx, &job2)
+	c.Assert(err, qt.IsNil)
+	job2 = dbmodel.ScheduledJob{Name: "job-1"}
+	err = s.Database.GetScheduledJob(ctx, &job2)
+	c.Assert(err, qt.IsNil)
+	c.Check(job2.TriggeredAt.Valid, qt.IsFalse)
+	c.Check(job2.TriggeredBy, qt.Equals, "")
+}
+
+func (s *dbSuite) TestJobRuns(c *qt.C) {
+	ctx := context.Background()
+	err := s.Database.Migrate(ctx, true)
+	c.Assert(err, qt.IsNil)
+
+	err = s.Database.RegisterScheduledJob(ctx, &dbmodel.ScheduledJob{Name: "job-1", Schedule: "@hourly"})
+	c.Assert(err, qt.IsNil)
+
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	var runs []dbmodel.JobRun
+	for i := 0; i < 4; i++ {
+		r := dbmodel.JobRun{
+			JobName:   "job-1",
+			Trigger:   dbmodel.JobTriggerSchedule,
+			Replica:   "replica-1",
+			Status:    dbmodel.JobRunRunning,
+			StartedAt: now.Add(time.Duration(i) * time.Minute),
+		}
+		err := s.Database.AddJobRun(ctx, &r)
+		c.Assert(err, qt.IsNil)
+		runs = append(runs, r)
+	}
+
+	runs[0].Status = dbmodel.JobRunSucceeded
+	runs[0].FinishedAt = sql.NullTime{Time: now.Add(time.Second), Valid: true}
+	err = s.Database.UpdateJobRun(ctx, &runs[0])
+	c.Assert(err, qt.IsNil)
+	runs[1].Status = dbmodel.JobRunFailed
+	runs[1].FinishedAt = sql.NullTime{Time: now.Add(time.Minute + time.Second), Valid: true}
+	runs[1].Error = "test error"
+	err = s.Database.UpdateJobRun(ctx, &runs[1])
+	c.Assert(err, qt.IsNil)
+
+	err = s.Database.UpdateJobRun(ctx, &dbmodel.JobRun{ID: 1000, Status: dbmodel.JobRunFailed})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	// The runs still running are interrupted.
+	n, err := s.Database.InterruptJobRuns(ctx, now.Add(time.Hour))
+	c.Assert(err, qt.IsNil)
+	c.Check(n, qt.Equals, int64(2))
+
+	got, err := s.Database.ListJobRuns(ctx, "job-1", 3)
+	c.Assert(err, qt.IsNil)
+	c.Assert(got, qt.HasLen, 3)
+	c.Check(got[0].ID, qt.Equals, runs[3].ID)
+	c.Check(got[0].Status, qt.Equals, dbmodel.JobRunInterrupted)
+	c.Check(got[1].Status, qt.Equals, dbmodel.JobRunInterrupted)
+	c.Check(got[2].ID, qt.Equals, runs[1].ID)
+	c.Check(got[2].Status, qt.Equals, dbmodel.JobRunFailed)
+	c.Check(got[2].Error, qt.Equals, "test error")
+
+	err = s.Database.PruneJobRuns(ctx, "job-1", 2)
+	c.Assert(err, qt.IsNil)
+	got, err = s.Database.ListJobRuns(ctx, "job-1", 10)
+	c.Assert(err, qt.IsNil)
+	c.Assert(got, qt.HasLen, 2)
+	c.Check(got[0].ID, qt.Equals, runs[3].ID)
+	c.Check(got[1].ID, qt.Equals, runs[2].ID)
+}
@@ -0,0 +1,171 @@
+// Copyright 2024 Canonical.
+
+package db
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+// AddUpgradeCampaign stores the given upgrade campaign along with its
+// models. If a campaign with the same name already exists an error with
+// a code of CodeAlreadyExists is returned.
+func (d *Database) AddUpgradeCampaign(ctx context.Context, c *dbmodel.UpgradeCampaign) (err error) {
+	const op = errors.Op("db.AddUpgradeCampaign")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	db := d.DB.WithContext(ctx)
+	if err := db.Omit("Models.Model").Create(c).Error; err != nil {
+		err = dbError(err)
+		if errors.ErrorCode(err) == errors.CodeAlreadyExists {
+			return errors.E(op, err, "upgrade campaign already exists")
+		}
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// GetUpgradeCampaign fills in the given upgrade campaign using its name.
+// The campaign's models, along with their controllers, are also loaded.
+// If there is no such campaign an error with a code of CodeNotFound is
+// returned.
+func (d *Database) GetUpgradeCampaign(ctx context.Context, c *dbmodel.UpgradeCampaign) (err error) {
+	const op = errors.Op("db.GetUpgradeCampaign")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	db := d.DB.WithContext(ctx)
+	db = db.Preload("Models", func(db *gorm.DB) *gorm.DB { return db.Order("id") })
+	db = db.Preload("Models.Model").Preload("Models.Model.Controller")
+	if err := db.Where("name = ?", c.Name).First(c).Error; err != nil {
+		err = dbError(err)
+		if errors.ErrorCode(err) == errors.CodeNotFound {
+			return errors.E(op, err, "upgrade campaign not found")
+		}
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// ListUpgradeCampaigns returns the upgrade campaigns, ordered by name.
+// If any statuses are given only campaigns in those states are returned.
+// The models of each campaign are loaded, but not the models' details.
+func (d *Database) ListUpgradeCampaigns(ctx context.Context, statuses ...string) (_ []dbmodel.UpgradeCampaign, err error) {
+	const op = errors.Op("db.ListUpgradeCampaigns")
+	if err := d.ready(); err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	db := d.DB.WithContext(ctx)
+	db = db.Preload("Models")
+	if len(statuses) > 0 {
+		db = db.Where("status IN ?", statuses)
+	}
+	var campaigns []dbmodel.UpgradeCampaign
+	if err := db.Order("name").Find(&campaigns).Error; err != nil {
+		return nil, errors.E(op, dbError(err))
+	}
+	return campaigns, nil
+}
+
+// UpdateUpgradeCampaign updates the status, failure count and message of
+// the given upgrade campaign.
+func (d *Database) UpdateUpgradeCampaign(ctx context.Context, c *dbmodel.UpgradeCampaign) (err error) {
+	const op = errors.Op("db.UpdateUpgradeCampaign")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	db := d.DB.WithContext(ctx)
+	result := db.Model(c).Select("status", "failures", "message").Updates(c)
+	if result.Error != nil {
+		return errors.E(op, dbError(result.Error))
+	}
+	if result.RowsAffected == 0 {
+		return errors.E(op, errors.CodeNotFound, "upgrade campaign not found")
+	}
+	return nil
+}
+
+// UpdateUpgradeCampaignModel updates the state of the given model in its
+// upgrade campaign.
+func (d *Database) UpdateUpgradeCampaignModel(ctx context.Context, m *dbmodel.UpgradeCampaignModel) (err error) {
+	const op = errors.Op("db.UpdateUpgradeCampaignModel")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	db := d.DB.WithContext(ctx)
+	result := db.Model(m).Select("status", "from_version", "chosen_version", "error").Updates(m)
+	if result.Error != nil {
+		return errors.E(op, dbError(result.Error))
+	}
+	if result.RowsAffected == 0 {
+		return errors.E(op, errors.CodeNotFound, "upgrade campaign model not found")
+	}
+	return nil
+}
+
+// DeleteUpgradeCampaign removes the upgrade campaign with the name of
+// the given campaign, along with its models. If there is no such
+// campaign an error with a code of CodeNotFound is returned.
+func (d *Database) DeleteUpgradeCampaign(ctx context.Context, c *dbmodel.UpgradeCampaign) (err error) {
+	const op = errors.Op("db.DeleteUpgradeCampaign")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	db := d.DB.WithContext(ctx)
+	result := db.Where("name = ?", c.Name).Delete(&dbmodel.UpgradeCampaign{})
+	if result.Error != nil {
+		return errors.E(op, dbError(result.Error))
+	}
+	if result.RowsAffected == 0 {
+		return errors.E(op, errors.CodeNotFound, "upgrade campaign not found")
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+// Copyright 2024 Canonical.
+
+package db_test
+
+import (
+	"context"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/testutils/jimmtest"
+)
+
+func TestAddUpgradeCampaignUnconfiguredDatabase(t *testing.T) {
+	c := qt.New(t)
+
+	var d db.Database
+	err := d.AddUpgradeCampaign(context.Background(), &dbmodel.UpgradeCampaign{})
+	c.Check(err, qt.ErrorMatches, `database not configured`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeServerConfiguration)
+}
+
+func (s *dbSuite) TestUpgradeCampaigns(c *qt.C) {
+	ctx := context.Background()
+	err := s.Database.Migrate(ctx, true)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, testForEachModelEnv)
+	env.PopulateDB(c, s.Database)
+	m1 := env.Model("alice@canonical.com", "test-1").DBObject(c, s.Database)
+	m2 := env.Model("bob@canonical.com", "test-2").DBObject(c, s.Database)
+
+	campaign := dbmodel.UpgradeCampaign{
+		Name:          "to-3.5",
+		TargetVersion: "3.5.1",
+		Concurrency:   2,
+		Status:        dbmodel.UpgradeCampaignValidating,
+		Models: []dbmodel.UpgradeCampaignModel{{
+			ModelID: m1.ID,
+			Status:  dbmodel.UpgradeModelPending,
+		}, {
+			ModelID: m2.ID,
+			Status:  dbmodel.UpgradeModelPending,
+		}},
+	}
+	err = s.Database.AddUpgradeCampaign(ctx, &campaign)
+	c.Assert(err, qt.IsNil)
+
+	err = s.Database.AddUpgradeCampaign(ctx, &dbmodel.UpgradeCampaign{Name: "to-3.5", Status: dbmodel.UpgradeCampaignValidating})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeAlreadyExists)
+
+	campaign.Models[1].Status = dbmodel.UpgradeModelInvalid
+	campaign.Models[1].Error = "model is not healthy"
+	err = s.Database.UpdateUpgradeCampaignModel(ctx, &campaign.Models[1])
+	c.Assert(err, qt.IsNil)
+
+	campaign.Status = dbmodel.UpgradeCampaignReady
+	err = s.Database.UpdateUpgradeCampaign(ctx, &campaign)
+	c.Assert(err, qt.IsNil)
+
+	uc := dbmodel.UpgradeCampaign{Name: "to-3.5"}
+	err = s.Database.GetUpgradeCampaign(ctx, &uc)
+	c.Assert(err, qt.IsNil)
+	c.Check(uc.Status, qt.Equals, dbmodel.UpgradeCampaignReady)
+	c.Check(uc.Concurrency, qt.Equals, 2)
+	c.Assert(uc.Models, qt.HasLen, 2)
+	c.Check(uc.Models[0].Model.UUID, qt.Equals, m1.UUID)
+	c.Check(uc.Models[0].Model.Controller.Name, qt.Equals, m1.Controller.Name)
+	c.Check(uc.Models[0].Status, qt.Equals, dbmodel.UpgradeModelPending)
+	c.Check(uc.Models[1].Status, qt.Equals, dbmodel.UpgradeModelInvalid)
+	c.Check(uc.Models[1].Error, qt.Equals, "model is not healthy")
+
+	err = s.Database.AddUpgradeCampaign(ctx, &dbmodel.UpgradeCampaign{Name: "other", Status: dbmodel.UpgradeCampaignRunning})
+	c.Assert(err, qt.IsNil)
+
+	campaigns, err := s.Database.ListUpgradeCampaigns(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Assert(campaigns, qt.HasLen, 2)
+	c.Check(campaigns[0].Name, qt.Equals, "other")
+	c.Check(campaigns[1].Name, qt.Equals, "to-3.5")
+	c.Check(campaigns[1].Models, qt.HasLen, 2)
+
+	campaigns, err = s.Database.ListUpgradeCampaigns(ctx, dbmodel.UpgradeCampaignValidating, dbmodel.UpgradeCampaignRunning)
+	c.Assert(err, qt.IsNil)
+	c.Assert(campaigns, qt.HasLen, 1)
+	c.Check(campaigns[0].Name, qt.Equals, "other")
+
+	err = s.Database.DeleteUpgradeCampaign(ctx, &dbmodel.UpgradeCampaign{Name: "to-3.5"})
+	c.Assert(err, qt.IsNil)
+	err = s.Database.DeleteUpgradeCampaign(ctx, &dbmodel.UpgradeCampaign{Name: "to-3.5"})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+	err = s.Database.GetUpgradeCampaign(ctx, &dbmodel.UpgradeCampaign{Name: "to-3.5"})
+	c.Check(err, qt.ErrorMatches, "upgrade campaign not found")
+}
@@ -0,0 +1,312 @@
+// Copyright 2024 Canonical.
+
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+// AddWebhookSubscription stores the given webhook subscription. If a
+// subscription with the same name already exists an error with a code of
+// CodeAlreadyExists is returned.
+func (d *Database) AddWebhookSubscription(ctx context.Context, s *dbmodel.WebhookSubscription) (err error) {
+	const op = errors.Op("db.AddWebhookSubscription")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	db := d.DB.WithContext(ctx)
+	if err := db.Create(s).Error; err != nil {
+		err = dbError(err)
+		if errors.ErrorCode(err) == errors.CodeAlreadyExists {
+			return errors.E(op, err, "webhook subscription already exists")
+		}
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// GetWebhookSubscription fills in the given webhook subscription using
+// its name. If there is no such subscription an error with a code of
+// CodeNotFound is returned.
+func (d *Database) GetWebhookSubscription(ctx context.Context, s *dbmodel.WebhookSubscription) (err error) {
+	const op = errors.Op("db.GetWebhookSubscription")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	db := d.DB.WithContext(ctx)
+	if err := db.Where("name = ?", s.Name).First(s).Error; err != nil {
+		err = dbError(err)
+		if errors.ErrorCode(err) == errors.CodeNotFound {
+			return errors.E(op, err, "webhook subscription not found")
+		}
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// ListWebhookSubscriptions returns all the webhook subscriptions, ordered
+// by name.
+func (d *Database) ListWebhookSubscriptions(ctx context.Context) (_ []dbmodel.WebhookSubscription, err error) {
+	const op = errors.Op("db.ListWebhookSubscriptions")
+	if err := d.ready(); err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	db := d.DB.WithContext(ctx)
+	var subscriptions []dbmodel.WebhookSubscription
+	if err := db.Order("name").Find(&subscriptions).Error; err != nil {
+		return nil, errors.E(op, dbError(err))
+	}
+	return subscriptions, nil
+}
+
+// DeleteWebhookSubscription removes the webhook subscription with the
+// name of the given subscription, along with any pending deliveries and
+// dead letters. If there is no such subscription an error with a code of
+// CodeNotFound is returned.
+func (d *Database) DeleteWebhookSubscription(ctx context.Context, s *dbmodel.WebhookSubscription) (err error) {
+	const op = errors.Op("db.DeleteWebhookSubscription")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	db := d.DB.WithContext(ctx)
+	result := db.Where("name = ?", s.Name).Delete(&dbmodel.WebhookSubscription{})
+	if result.Error != nil {
+		return errors.E(op, dbError(result.Error))
+	}
+	if result.RowsAffected == 0 {
+		return errors.E(op, errors.CodeNotFound, "webhook subscription not found")
+	}
+	return nil
+}
+
+// AddWebhookDeliveries stores the given webhook deliveries.
+func (d *Database) AddWebhookDeliveries(ctx context.Context, deliveries []dbmodel.WebhookDelivery) (err error) {
+	const op = errors.Op("db.AddWebhookDeliveries")
+	if len(deliveries) == 0 {
+		return nil
+	}
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	db := d.DB.WithContext(ctx)
+	if err := db.Omit("WebhookSubscription").Create(&deliveries).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// ListDueWebhookDeliveries returns at most limit webhook deliveries that
+// are due to be attempted at the given time, oldest first. The
+// subscription of each delivery is also loaded.
+func (d *Database) ListDueWebhookDeliveries(ctx context.Context, now time.Time, limit int) (_ []dbmodel.WebhookDelivery, err error) {
+	const op = errors.Op("db.ListDueWebhookDeliveries")
+	if err := d.ready(); err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	db := d.DB.WithContext(ctx)
+	db = db.Preload("WebhookSubscription")
+	var deliveries []dbmodel.WebhookDelivery
+	if err := db.Where("next_attempt_at <= ?", now).Order("id").Limit(limit).Find(&deliveries).Error; err != nil {
+		return nil, errors.E(op, dbError(err))
+	}
+	return deliveries, nil
+}
+
+// UpdateWebhookDelivery updates the attempt count, next attempt time and
+// last error of the given webhook delivery.
+func (d *Database) UpdateWebhookDelivery(ctx context.Context, wd *dbmodel.WebhookDelivery) (err error) {
+	const op = errors.Op("db.UpdateWebhookDelivery")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	db := d.DB.WithContext(ctx)
+	result := db.Model(wd).Select("attempts", "next_attempt_at", "last_error").Updates(wd)
+	if result.Error != nil {
+		return errors.E(op, dbError(result.Error))
+	}
+	if result.RowsAffected == 0 {
+		return errors.E(op, errors.CodeNotFound, "webhook delivery not found")
+	}
+	return nil
+}
+
+// DeleteWebhookDelivery removes the given webhook delivery.
+func (d *Database) DeleteWebhookDelivery(ctx context.Context, wd *dbmodel.WebhookDelivery) (err error) {
+	const op = errors.Op("db.DeleteWebhookDelivery")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	db := d.DB.WithContext(ctx)
+	if err := db.Delete(&dbmodel.WebhookDelivery{}, wd.ID).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// DeadLetterWebhookDelivery replaces the given webhook delivery with a
+// dead letter recording that it could not be delivered.
+func (d *Database) DeadLetterWebhookDelivery(ctx context.Context, wd *dbmodel.WebhookDelivery) (err error) {
+	const op = errors.Op("db.DeadLetterWebhookDelivery")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	err = d.Transaction(func(tx *Database) error {
+		db := tx.DB.WithContext(ctx)
+		dl := dbmodel.WebhookDeadLetter{
+			WebhookSubscriptionID: wd.WebhookSubscriptionID,
+			EventID:               wd.EventID,
+			EventType:             wd.EventType,
+			Payload:               wd.Payload,
+			Attempts:              wd.Attempts,
+			LastError:             wd.LastError,
+		}
+		if err := db.Omit("WebhookSubscription").Create(&dl).Error; err != nil {
+			return err
+		}
+		return db.Delete(&dbmodel.WebhookDelivery{}, wd.ID).Error
+	})
+	if err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// ListWebhookDeadLetters returns the webhook dead letters, oldest first.
+// If a subscription name is given only the dead letters of that
+// subscription are returned. The subscription of each dead letter is
+// also loaded.
+func (d *Database) ListWebhookDeadLetters(ctx context.Context, subscription string) (_ []dbmodel.WebhookDeadLetter, err error) {
+	const op = errors.Op("db.ListWebhookDeadLetters")
+	if err := d.ready(); err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	db := d.DB.WithContext(ctx)
+	db = db.Preload("WebhookSubscription")
+	if subscription != "" {
+		db = db.Joins("JOIN webhook_subscriptions ON webhook_subscriptions.id = webhook_dead_letters.webhook_subscription_id").
+			Where("webhook_subscriptions.name = ?", subscription)
+	}
+	var deadLetters []dbmodel.WebhookDeadLetter
+	if err := db.Order("webhook_dead_letters.id").Find(&deadLetters).Error; err != nil {
+		return nil, errors.E(op, dbError(err))
+	}
+	return deadLetters, nil
+}
+
+// RequeueWebhookDeadLetter replaces the webhook dead letter with the ID
+// of the given dead letter with a delivery that is attempted at the
+// given time. If there is no such dead letter an error with a code of
+// CodeNotFound is returned.
+func (d *Database) RequeueWebhookDeadLetter(ctx context.Context, dl *dbmodel.WebhookDeadLetter, next time.Time) (err error) {
+	const op = errors.Op("db.RequeueWebhookDeadLetter")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	err = d.Transaction(func(tx *Database) error {
+		db := tx.DB.WithContext(ctx)
+		if err := db.First(dl, dl.ID).Error; err != nil {
+			return err
+		}
+		wd := dbmodel.WebhookDelivery{
+			WebhookSubscriptionID: dl.WebhookSubscriptionID,
+			EventID:               dl.EventID,
+			EventType:             dl.EventType,
+			Payload:               dl.Payload,
+			NextAttemptAt:         next,
+		}
+		if err := db.Omit("WebhookSubscription").Create(&wd).Error; err != nil {
+			return err
+		}
+		return db.Delete(&dbmodel.WebhookDeadLetter{}, dl.ID).Error
+	})
+	if err != nil {
+		err = dbError(err)
+		if errors.ErrorCode(err) == errors.CodeNotFound {
+			return errors.E(op, err, "webhook dead letter not found")
+		}
+		return errors.E(op, err)
+	}
+	return nil
+}
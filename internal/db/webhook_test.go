@@ -0,0 +1,161 @@
+// Copyright 2024 Canonical.
+
+package db_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+func TestAddWebhookSubscriptionUnconfiguredDatabase(t *testing.T) {
+	c := qt.New(t)
+
+	var d db.Database
+	err := d.AddWebhookSubscription(context.Background(), &dbmodel.WebhookSubscription{})
+	c.Check(err, qt.ErrorMatches, `database not configured`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeServerConfiguration)
+}
+
+func (s *dbSuite) TestWebhookSubscriptions(c *qt.C) {
+	ctx := context.Background()
+	err := s.Database.Migrate(ctx, true)
+	c.Assert(err, qt.IsNil)
+
+	s1 := dbmodel.WebhookSubscription{
+		Name:       "chat-ops",
+		URL:        "https://chat.example.com/hook",
+		Secret:     "secret",
+		EventTypes: dbmodel.Strings{dbmodel.WebhookEventModelError},
+	}
+	err = s.Database.AddWebhookSubscription(ctx, &s1)
+	c.Assert(err, qt.IsNil)
+	s2 := dbmodel.WebhookSubscription{
+		Name:          "cmdb",
+		URL:           "https://cmdb.example.com/hook",
+		Secret:        "secret",
+		LabelSelector: "env=prod",
+	}
+	err = s.Database.AddWebhookSubscription(ctx, &s2)
+	c.Assert(err, qt.IsNil)
+
+	err = s.Database.AddWebhookSubscription(ctx, &dbmodel.WebhookSubscription{Name: "cmdb"})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeAlreadyExists)
+
+	s3 := dbmodel.WebhookSubscription{Name: "chat-ops"}
+	err = s.Database.GetWebhookSubscription(ctx, &s3)
+	c.Assert(err, qt.IsNil)
+	c.Check(s3.URL, qt.Equals, s1.URL)
+	c.Check(s3.EventTypes, qt.DeepEquals, s1.EventTypes)
+
+	subscriptions, err := s.Database.ListWebhookSubscriptions(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Assert(subscriptions, qt.HasLen, 2)
+	c.Check(subscriptions[0].Name, qt.Equals, "chat-ops")
+	c.Check(subscriptions[1].Name, qt.Equals, "cmdb")
+
+	err = s.Database.DeleteWebhookSubscription(ctx, &dbmodel.WebhookSubscription{Name: "cmdb"})
+	c.Assert(err, qt.IsNil)
+	err = s.Database.DeleteWebhookSubscription(ctx, &dbmodel.WebhookSubscription{Name: "cmdb"})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+	err = s.Database.GetWebhookSubscription(ctx, &dbmodel.WebhookSubscription{Name: "cmdb"})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+}
+
+func (s *dbSuite) TestWebhookDeliveries(c *qt.C) {
+	ctx := context.Background()
+	err := s.Database.Migrate(ctx, true)
+	c.Assert(err, qt.IsNil)
+
+	sub := dbmodel.WebhookSubscription{
+		Name:   "chat-ops",
+		URL:    "https://chat.example.com/hook",
+		Secret: "secret",
+	}
+	err = s.Database.AddWebhookSubscription(ctx, &sub)
+	c.Assert(err, qt.IsNil)
+
+	now := time.Now()
+	err = s.Database.AddWebhookDeliveries(ctx, []dbmodel.WebhookDelivery{{
+		WebhookSubscriptionID: sub.ID,
+		EventID:               "event-1",
+		EventType:             dbmodel.WebhookEventModelCreated,
+		Payload:               dbmodel.JSON(`{"id":"event-1"}`),
+		NextAttemptAt:         now.Add(-time.Second),
+	}, {
+		WebhookSubscriptionID: sub.ID,
+		EventID:               "event-2",
+		EventType:             dbmodel.WebhookEventModelDead,
+		Payload:               dbmodel.JSON(`{"id":"event-2"}`),
+		NextAttemptAt:         now.Add(time.Hour),
+	}})
+	c.Assert(err, qt.IsNil)
+
+	deliveries, err := s.Database.ListDueWebhookDeliveries(ctx, now, 10)
+	c.Assert(err, qt.IsNil)
+	c.Assert(deliveries, qt.HasLen, 1)
+	c.Check(deliveries[0].EventID, qt.Equals, "event-1")
+	c.Check(deliveries[0].WebhookSubscription.Name, qt.Equals, "chat-ops")
+
+	deliveries[0].Attempts = 1
+	deliveries[0].LastError = "connection refused"
+	deliveries[0].NextAttemptAt = now.Add(time.Minute)
+	err = s.Database.UpdateWebhookDelivery(ctx, &deliveries[0])
+	c.Assert(err, qt.IsNil)
+
+	deliveries, err = s.Database.ListDueWebhookDeliveries(ctx, now, 10)
+	c.Assert(err, qt.IsNil)
+	c.Check(deliveries, qt.HasLen, 0)
+
+	deliveries, err = s.Database.ListDueWebhookDeliveries(ctx, now.Add(2*time.Hour), 10)
+	c.Assert(err, qt.IsNil)
+	c.Assert(deliveries, qt.HasLen, 2)
+	c.Check(deliveries[0].Attempts, qt.Equals, 1)
+	c.Check(deliveries[0].LastError, qt.Equals, "connection refused")
+
+	err = s.Database.DeleteWebhookDelivery(ctx, &deliveries[1])
+	c.Assert(err, qt.IsNil)
+
+	err = s.Database.DeadLetterWebhookDelivery(ctx, &deliveries[0])
+	c.Assert(err, qt.IsNil)
+
+	deliveries, err = s.Database.ListDueWebhookDeliveries(ctx, now.Add(2*time.Hour), 10)
+	c.Assert(err, qt.IsNil)
+	c.Check(deliveries, qt.HasLen, 0)
+
+	deadLetters, err := s.Database.ListWebhookDeadLetters(ctx, "")
+	c.Assert(err, qt.IsNil)
+	c.Assert(deadLetters, qt.HasLen, 1)
+	c.Check(deadLetters[0].EventID, qt.Equals, "event-1")
+	c.Check(deadLetters[0].Attempts, qt.Equals, 1)
+	c.Check(deadLetters[0].WebhookSubscription.Name, qt.Equals, "chat-ops")
+	deadLetterID := deadLetters[0].ID
+
+	deadLetters, err = s.Database.ListWebhookDeadLetters(ctx, "cmdb")
+	c.Assert(err, qt.IsNil)
+	c.Check(deadLetters, qt.HasLen, 0)
+
+	err = s.Database.RequeueWebhookDeadLetter(ctx, &dbmodel.WebhookDeadLetter{ID: deadLetterID}, now)
+	c.Assert(err, qt.IsNil)
+	deliveries, err = s.Database.ListDueWebhookDeliveries(ctx, now, 10)
+	c.Assert(err, qt.IsNil)
+	c.Assert(deliveries, qt.HasLen, 1)
+	c.Check(deliveries[0].EventID, qt.Equals, "event-1")
+	c.Check(deliveries[0].Attempts, qt.Equals, 0)
+
+	err = s.Database.RequeueWebhookDeadLetter(ctx, &dbmodel.WebhookDeadLetter{ID: 1000}, now)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	// Removing the subscription removes its deliveries.
+	err = s.Database.DeleteWebhookSubscription(ctx, &sub)
+	c.Assert(err, qt.IsNil)
+	deliveries, err = s.Database.ListDueWebhookDeliveries(ctx, now, 10)
+	c.Assert(err, qt.IsNil)
+	c.Check(deliveries, qt.HasLen, 0)
+}
@@ -0,0 +1,253 @@
+// Copyright 2024 Canonical.
+
+package dbmodel
+
+import (
+	"database/sql"
+	"time"
+
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// The kinds of condition that alert rules are evaluated against.
+const (
+	// AlertKindModelError fires when the status of a model is error.
+	AlertKindModelError = "model-error"
+
+	// AlertKindControllerUnavailable fires when JIMM cannot connect to
+	// a controller.
+	AlertKindControllerUnavailable = "controller-unavailable"
+
+	// AlertKindCredentialInvalid fires when a cloud credential is
+	// known to be invalid.
+	AlertKindCredentialInvalid = "credential-invalid"
+
+	// AlertKindAuditWriteFailure fires when a JIMM replica has failed
+	// to write to the audit log.
+	AlertKindAuditWriteFailure = "audit-write-failure"
+)
+
+// AlertKinds holds all the kinds of alert rule.
+var AlertKinds = []string{
+	AlertKindModelError,
+	AlertKindControllerUnavailable,
+	AlertKindCredentialInvalid,
+	AlertKindAuditWriteFailure,
+}
+
+// The types of alert receiver.
+const (
+	// AlertReceiverWebhook receivers have alert notifications posted
+	// to a URL as JSON, signed in the same way as webhook events.
+	AlertReceiverWebhook = "webhook"
+
+	// AlertReceiverEmail receivers have alert notifications sent by
+	// email.
+	AlertReceiverEmail = "email"
+
+	// AlertReceiverAlertmanager receivers have alerts posted to the
+	// alerts API of a Prometheus Alertmanager, or a compatible service.
+	AlertReceiverAlertmanager = "alertmanager"
+)
+
+// AlertReceiverTypes holds all the types of alert receiver.
+var AlertReceiverTypes = []string{
+	AlertReceiverWebhook,
+	AlertReceiverEmail,
+	AlertReceiverAlertmanager,
+}
+
+// An AlertReceiver is a destination that alert notifications are sent
+// to.
+type AlertReceiver struct {
+	ID        uint `gorm:"primaryKey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	// Name is the name of the receiver.
+	Name string `gorm:"not null;uniqueIndex"`
+
+	// Type is the type of the receiver.
+	Type string `gorm:"not null"`
+
+	// URL is the URL notifications are posted to by webhook and
+	// alertmanager receivers.
+	URL string `gorm:"not null"`
+
+	// Secret is the key used to sign the notifications posted to
+	// webhook receivers.
+	Secret string `gorm:"not null"`
+
+	// Emails holds the addresses notifications are sent to by email
+	// receivers.
+	Emails Strings
+}
+
+// ToAPIAlertReceiver converts an alert receiver to its API
+// representation. The receiver's secret is not included.
+func (r AlertReceiver) ToAPIAlertReceiver() apiparams.AlertReceiver {
+	return apiparams.AlertReceiver{
+		Name:      r.Name,
+		Type:      r.Type,
+		URL:       r.URL,
+		Emails:    r.Emails,
+		CreatedAt: r.CreatedAt,
+	}
+}
+
+// An AlertRule describes a condition that raises an alert.
+type AlertRule struct {
+	ID        uint `gorm:"primaryKey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	// Name is the name of the rule.
+	Name string `gorm:"not null;uniqueIndex"`
+
+	// Kind is the kind of condition the rule is evaluated against.
+	Kind string `gorm:"not null"`
+
+	// For is the length of time the condition must hold before the
+	// alert fires.
+	For time.Duration `gorm:"column:for_duration;not null"`
+
+	// RepeatInterval is the time to wait before notifying receivers
+	// again about an alert that is still firing.
+	RepeatInterval time.Duration `gorm:"not null"`
+
+	// Severity is included with every alert raised by the rule.
+	Severity string `gorm:"not null"`
+
+	// Receivers holds the names of the receivers notified when the
+	// rule's alerts fire or are resolved.
+	Receivers Strings
+}
+
+// ToAPIAlertRule converts an alert rule to its API representation.
+func (r AlertRule) ToAPIAlertRule() apiparams.AlertRule {
+	return apiparams.AlertRule{
+		Name:           r.Name,
+		Kind:           r.Kind,
+		For:            r.For.String(),
+		RepeatInterval: r.RepeatInterval.String(),
+		Severity:       r.Severity,
+		Receivers:      r.Receivers,
+		CreatedAt:      r.CreatedAt,
+	}
+}
+
+// An Alert is raised by an alert rule whose condition holds for a
+// subject, such as a model or controller. There is at most one alert for
+// each rule and subject, which is removed once the condition no longer
+// holds.
+type Alert struct {
+	ID        uint `gorm:"primaryKey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	// AlertRule is the rule that raised the alert.
+	AlertRuleID uint `gorm:"not null"`
+	AlertRule   AlertRule
+
+	// Subject identifies the entity the alert is about.
+	Subject string `gorm:"not null"`
+
+	// Summary describes the alert.
+	Summary string `gorm:"not null"`
+
+	// Labels holds further details of the subject of the alert.
+	Labels StringMap
+
+	// ActiveAt is the time the rule's condition started to hold.
+	ActiveAt time.Time `gorm:"not null"`
+
+	// FiringAt is the time the condition had held for the duration of
+	// the rule, at which point receivers are notified.
+	FiringAt sql.NullTime
+
+	// NotifiedAt is the time the receivers were last notified about the
+	// alert.
+	NotifiedAt sql.NullTime
+}
+
+// State returns the state of the alert, either pending or firing.
+func (a Alert) State() string {
+	if a.FiringAt.Valid {
+		return apiparams.AlertStateFiring
+	}
+	return apiparams.AlertStatePending
+}
+
+// ToAPIAlert converts an alert to its API representation. The alert's
+// rule must have been loaded.
+func (a Alert) ToAPIAlert() apiparams.Alert {
+	alert := apiparams.Alert{
+		Rule:     a.AlertRule.Name,
+		Kind:     a.AlertRule.Kind,
+		Severity: a.AlertRule.Severity,
+		Subject:  a.Subject,
+		Summary:  a.Summary,
+		Labels:   a.Labels,
+		State:    a.State(),
+		ActiveAt: a.ActiveAt,
+	}
+	if a.FiringAt.Valid {
+		alert.FiringAt = &a.FiringAt.Time
+	}
+	if a.NotifiedAt.Valid {
+		alert.NotifiedAt = &a.NotifiedAt.Time
+	}
+	return alert
+}
+
+// An AlertSilence stops notifications being sent for matching alerts
+// for a period of time.
+type AlertSilence struct {
+	ID        uint `gorm:"primaryKey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	// Rule and Subject restrict the alerts silenced to those raised by
+	// the named rule and about the given subject. Empty values match
+	// all alerts.
+	Rule    string `gorm:"not null"`
+	Subject string `gorm:"not null"`
+
+	// StartsAt and EndsAt hold the period the silence applies.
+	StartsAt time.Time `gorm:"not null"`
+	EndsAt   time.Time `gorm:"not null"`
+
+	// CreatedBy is the name of the identity that created the silence.
+	CreatedBy string `gorm:"not null"`
+
+	// Comment describes the reason for the silence.
+	Comment string `gorm:"not null"`
+}
+
+// Matches determines whether the silence applies to the given alert at
+// the given time. The alert's rule must have been loaded.
+func (s AlertSilence) Matches(a *Alert, t time.Time) bool {
+	if t.Before(s.StartsAt) || !t.Before(s.EndsAt) {
+		return false
+	}
+	if s.Rule != "" && s.Rule != a.AlertRule.Name {
+		return false
+	}
+	if s.Subject != "" && s.Subject != a.Subject {
+		return false
+	}
+	return true
+}
+
+// ToAPIAlertSilence converts an alert silence to its API representation.
+func (s AlertSilence) ToAPIAlertSilence() apiparams.AlertSilence {
+	return apiparams.AlertSilence{
+		ID:        s.ID,
+		Rule:      s.Rule,
+		Subject:   s.Subject,
+		StartsAt:  s.StartsAt,
+		EndsAt:    s.EndsAt,
+		CreatedBy: s.CreatedBy,
+		Comment:   s.Comment,
+	}
+}
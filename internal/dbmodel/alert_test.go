@@ -0,0 +1,99 @@
+// Copyright 2024 Canonical.
+
+package dbmodel_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+var alertSilenceMatchesTests = []struct {
+	name    string
+	silence dbmodel.AlertSilence
+	offset  time.Duration
+	expect  bool
+}{{
+	name:    "matching rule",
+	silence: dbmodel.AlertSilence{Rule: "models-in-error"},
+	expect:  true,
+}, {
+	name:    "matching subject",
+	silence: dbmodel.AlertSilence{Subject: "00000002-0000-0000-0000-000000000001"},
+	expect:  true,
+}, {
+	name:    "matching rule and subject",
+	silence: dbmodel.AlertSilence{Rule: "models-in-error", Subject: "00000002-0000-0000-0000-000000000001"},
+	expect:  true,
+}, {
+	name:    "other rule",
+	silence: dbmodel.AlertSilence{Rule: "controllers", Subject: "00000002-0000-0000-0000-000000000001"},
+}, {
+	name:    "other subject",
+	silence: dbmodel.AlertSilence{Rule: "models-in-error", Subject: "00000002-0000-0000-0000-000000000002"},
+}, {
+	name:    "not started",
+	silence: dbmodel.AlertSilence{Rule: "models-in-error"},
+	offset:  -2 * time.Hour,
+}, {
+	name:    "ended",
+	silence: dbmodel.AlertSilence{Rule: "models-in-error"},
+	offset:  time.Hour,
+}}
+
+func TestAlertSilenceMatches(t *testing.T) {
+	c := qt.New(t)
+
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	alert := dbmodel.Alert{
+		AlertRule: dbmodel.AlertRule{Name: "models-in-error"},
+		Subject:   "00000002-0000-0000-0000-000000000001",
+	}
+	for _, test := range alertSilenceMatchesTests {
+		c.Run(test.name, func(c *qt.C) {
+			s := test.silence
+			s.StartsAt = now.Add(-time.Hour)
+			s.EndsAt = now.Add(time.Hour)
+			c.Check(s.Matches(&alert, now.Add(test.offset)), qt.Equals, test.expect)
+		})
+	}
+}
+
+func TestAlertToAPIAlert(t *testing.T) {
+	c := qt.New(t)
+
+	activeAt := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	alert := dbmodel.Alert{
+		AlertRule: dbmodel.AlertRule{
+			Name:     "controllers",
+			Kind:     dbmodel.AlertKindControllerUnavailable,
+			Severity: "critical",
+		},
+		Subject:  "controller-1",
+		Summary:  "controller controller-1 is unavailable",
+		Labels:   dbmodel.StringMap{"controller": "controller-1"},
+		ActiveAt: activeAt,
+	}
+	c.Check(alert.ToAPIAlert(), qt.DeepEquals, apiparams.Alert{
+		Rule:     "controllers",
+		Kind:     dbmodel.AlertKindControllerUnavailable,
+		Severity: "critical",
+		Subject:  "controller-1",
+		Summary:  "controller controller-1 is unavailable",
+		Labels:   map[string]string{"controller": "controller-1"},
+		State:    apiparams.AlertStatePending,
+		ActiveAt: activeAt,
+	})
+
+	firingAt := activeAt.Add(5 * time.Minute)
+	alert.FiringAt = sql.NullTime{Time: firingAt, Valid: true}
+	apiAlert := alert.ToAPIAlert()
+	c.Check(apiAlert.State, qt.Equals, apiparams.AlertStateFiring)
+	c.Check(apiAlert.FiringAt, qt.DeepEquals, &firingAt)
+	c.Check(apiAlert.NotifiedAt, qt.IsNil)
+}
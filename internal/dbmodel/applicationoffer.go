@@ -45,3 +45,22 @@ func (o ApplicationOffer) ResourceTag() names.ApplicationOfferTag {
 func (o *ApplicationOffer) SetTag(t names.ApplicationOfferTag) {
 	o.UUID = t.Id()
 }
+
+// An ApplicationOfferConsumeEvent records an identity consuming an
+// application offer into a model.
+type ApplicationOfferConsumeEvent struct {
+	ID uint `gorm:"primaryKey"`
+
+	// Time is the time the offer was consumed.
+	Time time.Time
+
+	// ApplicationOffer is the offer that was consumed.
+	ApplicationOfferID uint
+	ApplicationOffer   ApplicationOffer
+
+	// IdentityName is the name of the identity that consumed the offer.
+	IdentityName string
+
+	// ModelUUID is the UUID of the model the offer was consumed into.
+	ModelUUID string
+}
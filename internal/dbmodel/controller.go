@@ -66,6 +66,9 @@ type Controller struct {
 	// therefore no new models or clouds will be added to the controller.
 	Deprecated bool `gorm:"not null;default:FALSE"`
 
+	// Maintenance holds the maintenance state of the controller.
+	Maintenance ControllerMaintenance `gorm:"embedded;embeddedPrefix:maintenance_"`
+
 	// AgentVersion holds the string representation of the controller's
 	// agent version.
 	AgentVersion string
@@ -125,6 +128,17 @@ func (c Controller) ToAPIControllerInfo() apiparams.ControllerInfo {
 	ci.Username = c.AdminIdentityName
 	ci.AgentVersion = c.AgentVersion
 	switch {
+	case c.Maintenance.Active(time.Now()):
+		ci.Status = jujuparams.EntityStatus{
+			Status: "maintenance",
+			Info:   c.Maintenance.Message,
+			Data: map[string]interface{}{
+				"allow-read-only": c.Maintenance.AllowReadOnly,
+			},
+		}
+		if c.Maintenance.EndsAt.Valid {
+			ci.Status.Data["ends-at"] = c.Maintenance.EndsAt.Time.UTC().Format(time.RFC3339)
+		}
 	case c.UnavailableSince.Valid:
 		ci.Status = jujuparams.EntityStatus{
 			Status: "unavailable",
@@ -142,6 +156,32 @@ func (c Controller) ToAPIControllerInfo() apiparams.ControllerInfo {
 	return ci
 }
 
+// ControllerMaintenance holds the maintenance state of a controller. While
+// a controller is in maintenance JIMM refuses operations on its models
+// that could be affected by the maintenance.
+type ControllerMaintenance struct {
+	// Enabled records whether the controller has been put into
+	// maintenance.
+	Enabled bool `gorm:"not null;default:FALSE"`
+
+	// Message is the message given to users whose requests are refused
+	// because of the maintenance.
+	Message string `gorm:"not null;default:''"`
+
+	// EndsAt is the time the maintenance is expected to end, if known.
+	// The maintenance is no longer active after this time.
+	EndsAt sql.NullTime
+
+	// AllowReadOnly records whether read-only requests to models on
+	// the controller are still allowed during the maintenance.
+	AllowReadOnly bool `gorm:"not null;default:FALSE"`
+}
+
+// Active returns whether the maintenance is in effect at the given time.
+func (m ControllerMaintenance) Active(now time.Time) bool {
+	return m.Enabled && (!m.EndsAt.Valid || now.Before(m.EndsAt.Time))
+}
+
 // ToJujuRedirectInfoResult converts a controller entry to a juju
 // RedirectInfoResult value.
 func (c Controller) ToJujuRedirectInfoResult() jujuparams.RedirectInfoResult {
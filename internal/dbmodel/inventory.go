@@ -0,0 +1,191 @@
+// Copyright 2024 Canonical.
+
+package dbmodel
+
+import (
+	"time"
+
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// A ModelInventory is a snapshot of the contents of a model, collected
+// periodically from the model's controller. Each model has at most one
+// inventory, which is replaced on every collection.
+type ModelInventory struct {
+	ID uint `gorm:"primaryKey"`
+
+	// Model is the model the inventory describes.
+	ModelID uint `gorm:"not null;uniqueIndex"`
+	Model   Model
+
+	// CollectedAt is the time the inventory was collected.
+	CollectedAt time.Time `gorm:"not null"`
+
+	// Applications, Units, Machines and Relations hold the contents of
+	// the model.
+	Applications []InventoryApplication `gorm:"constraint:OnDelete:CASCADE"`
+	Units        []InventoryUnit        `gorm:"constraint:OnDelete:CASCADE"`
+	Machines     []InventoryMachine     `gorm:"constraint:OnDelete:CASCADE"`
+	Relations    []InventoryRelation    `gorm:"constraint:OnDelete:CASCADE"`
+}
+
+// ToAPIModelInventory converts a model inventory to its API
+// representation. The inventory's model must have been loaded, along
+// with the model's owner and controller.
+func (i ModelInventory) ToAPIModelInventory() apiparams.ModelInventory {
+	inv := apiparams.ModelInventory{
+		ModelUUID:   i.Model.UUID.String,
+		ModelName:   i.Model.Name,
+		Owner:       i.Model.OwnerIdentityName,
+		Controller:  i.Model.Controller.Name,
+		CollectedAt: i.CollectedAt,
+	}
+	for _, a := range i.Applications {
+		inv.Applications = append(inv.Applications, a.ToAPIInventoryApplication())
+	}
+	for _, u := range i.Units {
+		inv.Units = append(inv.Units, apiparams.InventoryUnit{
+			Name:           u.Name,
+			Application:    u.Application,
+			Machine:        u.Machine,
+			Principal:      u.Principal,
+			WorkloadStatus: u.WorkloadStatus,
+			AgentStatus:    u.AgentStatus,
+		})
+	}
+	for _, m := range i.Machines {
+		inv.Machines = append(inv.Machines, apiparams.InventoryMachine{
+			ID:         m.MachineID,
+			Base:       m.Base,
+			InstanceID: m.InstanceID,
+			Status:     m.Status,
+			Hardware:   m.Hardware,
+		})
+	}
+	for _, r := range i.Relations {
+		inv.Relations = append(inv.Relations, apiparams.InventoryRelation{
+			ID:        r.RelationID,
+			Key:       r.Key,
+			Interface: r.Interface,
+			Scope:     r.Scope,
+			Status:    r.Status,
+		})
+	}
+	return inv
+}
+
+// An InventoryApplication is an application in a model inventory.
+type InventoryApplication struct {
+	ID               uint `gorm:"primaryKey"`
+	ModelInventoryID uint `gorm:"not null"`
+
+	// Name is the name of the application.
+	Name string `gorm:"not null"`
+
+	// Charm is the name of the application's charm.
+	Charm string `gorm:"not null;index:idx_inventory_applications_charm"`
+
+	// CharmURL is the full URL of the application's charm.
+	CharmURL string `gorm:"not null"`
+
+	// CharmChannel is the channel the charm was deployed from.
+	CharmChannel string `gorm:"not null"`
+
+	// CharmRevision is the revision of the application's charm, or -1
+	// if the charm has no revision.
+	CharmRevision int `gorm:"not null;index:idx_inventory_applications_charm"`
+
+	// Base is the base of the application, in the form
+	// "<name>@<channel>".
+	Base string `gorm:"not null;index:idx_inventory_applications_base"`
+
+	// Status is the status of the application.
+	Status string `gorm:"not null"`
+
+	// Exposed records whether the application is exposed.
+	Exposed bool `gorm:"not null"`
+
+	// WorkloadVersion is the version of the application's workload.
+	WorkloadVersion string `gorm:"not null"`
+}
+
+// ToAPIInventoryApplication converts an inventory application to its API
+// representation.
+func (a InventoryApplication) ToAPIInventoryApplication() apiparams.InventoryApplication {
+	return apiparams.InventoryApplication{
+		Name:            a.Name,
+		Charm:           a.Charm,
+		CharmURL:        a.CharmURL,
+		CharmChannel:    a.CharmChannel,
+		CharmRevision:   a.CharmRevision,
+		Base:            a.Base,
+		Status:          a.Status,
+		Exposed:         a.Exposed,
+		WorkloadVersion: a.WorkloadVersion,
+	}
+}
+
+// An InventoryUnit is a unit in a model inventory.
+type InventoryUnit struct {
+	ID               uint `gorm:"primaryKey"`
+	ModelInventoryID uint `gorm:"not null"`
+
+	// Name is the name of the unit.
+	Name string `gorm:"not null"`
+
+	// Application is the name of the unit's application.
+	Application string `gorm:"not null"`
+
+	// Machine is the ID of the machine hosting the unit.
+	Machine string `gorm:"not null"`
+
+	// Principal is the name of the principal unit of a subordinate
+	// unit.
+	Principal string `gorm:"not null"`
+
+	// WorkloadStatus and AgentStatus are the status of the unit.
+	WorkloadStatus string `gorm:"not null"`
+	AgentStatus    string `gorm:"not null"`
+}
+
+// An InventoryMachine is a machine in a model inventory.
+type InventoryMachine struct {
+	ID               uint `gorm:"primaryKey"`
+	ModelInventoryID uint `gorm:"not null"`
+
+	// MachineID is the ID of the machine in its model.
+	MachineID string `gorm:"not null"`
+
+	// Base is the base of the machine, in the form "<name>@<channel>".
+	Base string `gorm:"not null;index:idx_inventory_machines_base"`
+
+	// InstanceID is the provider's ID for the machine.
+	InstanceID string `gorm:"not null"`
+
+	// Status is the status of the machine's agent.
+	Status string `gorm:"not null"`
+
+	// Hardware describes the machine's hardware.
+	Hardware string `gorm:"not null"`
+}
+
+// An InventoryRelation is a relation in a model inventory.
+type InventoryRelation struct {
+	ID               uint `gorm:"primaryKey"`
+	ModelInventoryID uint `gorm:"not null"`
+
+	// RelationID is the ID of the relation in its model.
+	RelationID int `gorm:"not null"`
+
+	// Key identifies the endpoints of the relation.
+	Key string `gorm:"not null"`
+
+	// Interface is the interface of the relation.
+	Interface string `gorm:"not null"`
+
+	// Scope is the scope of the relation.
+	Scope string `gorm:"not null"`
+
+	// Status is the status of the relation.
+	Status string `gorm:"not null"`
+}
@@ -0,0 +1,151 @@
+// Copyright 2024 Canonical.
+
+package dbmodel
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+const (
+	// maxLabelKeyLength is the maximum length of a label key.
+	maxLabelKeyLength = 63
+
+	// maxLabelValueLength is the maximum length of a label value.
+	maxLabelValueLength = 255
+)
+
+// labelKeyRE matches valid label keys. Keys start with an alphanumeric
+// character and may contain alphanumerics, '-', '_', '.' and '/'.
+var labelKeyRE = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._/-]*$`)
+
+// ValidateLabelKey checks that the given string is a valid label key.
+func ValidateLabelKey(key string) error {
+	if len(key) > maxLabelKeyLength || !labelKeyRE.MatchString(key) {
+		return errors.E(errors.CodeBadRequest, fmt.Sprintf("invalid label key %q", key))
+	}
+	return nil
+}
+
+// ValidateLabelValue checks that the given string is a valid label value.
+// Values may be any string that does not contain a ',' or '=' character.
+func ValidateLabelValue(value string) error {
+	if len(value) > maxLabelValueLength || strings.ContainsAny(value, ",=") {
+		return errors.E(errors.CodeBadRequest, fmt.Sprintf("invalid label value %q", value))
+	}
+	return nil
+}
+
+// A LabelOperator is the operator in a label selector requirement.
+type LabelOperator string
+
+const (
+	// LabelEquals requires a label to have a specific value.
+	LabelEquals LabelOperator = "="
+
+	// LabelNotEquals requires a label to not have a specific value. A
+	// model without the label satisfies the requirement.
+	LabelNotEquals LabelOperator = "!="
+
+	// LabelExists requires a label to be set, with any value.
+	LabelExists LabelOperator = "exists"
+
+	// LabelNotExists requires a label to not be set.
+	LabelNotExists LabelOperator = "!exists"
+)
+
+// A LabelRequirement is a single requirement in a label selector.
+type LabelRequirement struct {
+	Key      string
+	Operator LabelOperator
+	Value    string
+}
+
+// Matches determines whether the given labels satisfy the requirement.
+func (r LabelRequirement) Matches(labels map[string]string) bool {
+	v, ok := labels[r.Key]
+	switch r.Operator {
+	case LabelEquals:
+		return ok && v == r.Value
+	case LabelNotEquals:
+		return !ok || v != r.Value
+	case LabelExists:
+		return ok
+	case LabelNotExists:
+		return !ok
+	}
+	return false
+}
+
+// A LabelSelector selects models by their labels. A model is selected
+// if it satisfies all the requirements in the selector. An empty
+// selector selects all models.
+type LabelSelector []LabelRequirement
+
+// ParseLabelSelector parses a label selector. A selector is a comma
+// separated list of requirements, each of which takes one of the forms:
+//
+//	key=value   the label must have the given value
+//	key!=value  the label must not have the given value
+//	key         the label must be set
+//	!key        the label must not be set
+func ParseLabelSelector(s string) (LabelSelector, error) {
+	var sel LabelSelector
+	if strings.TrimSpace(s) == "" {
+		return sel, nil
+	}
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		var r LabelRequirement
+		switch {
+		case strings.Contains(part, "!="):
+			k, v, _ := strings.Cut(part, "!=")
+			r = LabelRequirement{Key: strings.TrimSpace(k), Operator: LabelNotEquals, Value: strings.TrimSpace(v)}
+		case strings.Contains(part, "="):
+			k, v, _ := strings.Cut(part, "=")
+			r = LabelRequirement{Key: strings.TrimSpace(k), Operator: LabelEquals, Value: strings.TrimPrefix(strings.TrimSpace(v), "=")}
+		case strings.HasPrefix(part, "!"):
+			r = LabelRequirement{Key: strings.TrimSpace(part[1:]), Operator: LabelNotExists}
+		default:
+			r = LabelRequirement{Key: part, Operator: LabelExists}
+		}
+		if err := ValidateLabelKey(r.Key); err != nil {
+			return nil, errors.E(errors.CodeBadRequest, fmt.Sprintf("invalid label selector %q: invalid key %q", s, r.Key))
+		}
+		if err := ValidateLabelValue(r.Value); err != nil {
+			return nil, errors.E(errors.CodeBadRequest, fmt.Sprintf("invalid label selector %q: invalid value %q", s, r.Value))
+		}
+		sel = append(sel, r)
+	}
+	return sel, nil
+}
+
+// Matches determines whether the given labels satisfy every requirement
+// of the selector.
+func (s LabelSelector) Matches(labels map[string]string) bool {
+	for _, r := range s {
+		if !r.Matches(labels) {
+			return false
+		}
+	}
+	return true
+}
+
+// String returns the selector in the form parsed by ParseLabelSelector.
+func (s LabelSelector) String() string {
+	parts := make([]string, len(s))
+	for i, r := range s {
+		switch r.Operator {
+		case LabelEquals, LabelNotEquals:
+			parts[i] = r.Key + string(r.Operator) + r.Value
+		case LabelExists:
+			parts[i] = r.Key
+		case LabelNotExists:
+			parts[i] = "!" + r.Key
+		}
+	}
+	return strings.Join(parts, ",")
+}
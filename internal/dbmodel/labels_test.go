@@ -0,0 +1,109 @@
+// Copyright 2024 Canonical.
+
+package dbmodel_test
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+var parseLabelSelectorTests = []struct {
+	selector    string
+	expect      dbmodel.LabelSelector
+	expectError string
+}{{
+	selector: "",
+}, {
+	selector: "env=prod",
+	expect:   dbmodel.LabelSelector{{Key: "env", Operator: dbmodel.LabelEquals, Value: "prod"}},
+}, {
+	selector: "env==prod",
+	expect:   dbmodel.LabelSelector{{Key: "env", Operator: dbmodel.LabelEquals, Value: "prod"}},
+}, {
+	selector: " env != prod , team, !deprecated, cost/centre=",
+	expect: dbmodel.LabelSelector{
+		{Key: "env", Operator: dbmodel.LabelNotEquals, Value: "prod"},
+		{Key: "team", Operator: dbmodel.LabelExists},
+		{Key: "deprecated", Operator: dbmodel.LabelNotExists},
+		{Key: "cost/centre", Operator: dbmodel.LabelEquals, Value: ""},
+	},
+}, {
+	selector:    "=prod",
+	expectError: `invalid label selector "=prod": invalid key ""`,
+}, {
+	selector:    "env=prod,,team",
+	expectError: `invalid label selector "env=prod,,team": invalid key ""`,
+}, {
+	selector:    "env=a=b",
+	expectError: `invalid label selector "env=a=b": invalid value "a=b"`,
+}, {
+	selector:    "-env",
+	expectError: `invalid label selector "-env": invalid key "-env"`,
+}}
+
+func TestParseLabelSelector(t *testing.T) {
+	c := qt.New(t)
+
+	for _, test := range parseLabelSelectorTests {
+		c.Run(test.selector, func(c *qt.C) {
+			sel, err := dbmodel.ParseLabelSelector(test.selector)
+			if test.expectError != "" {
+				c.Check(err, qt.ErrorMatches, test.expectError)
+				c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+				return
+			}
+			c.Assert(err, qt.IsNil)
+			c.Check(sel, qt.DeepEquals, test.expect)
+
+			// The selector round trips.
+			sel2, err := dbmodel.ParseLabelSelector(sel.String())
+			c.Assert(err, qt.IsNil)
+			c.Check(sel2, qt.DeepEquals, sel)
+		})
+	}
+}
+
+func TestLabelSelectorMatches(t *testing.T) {
+	c := qt.New(t)
+
+	labels := map[string]string{"env": "prod", "team": "ops"}
+	tests := []struct {
+		selector string
+		expect   bool
+	}{
+		{"", true},
+		{"env=prod", true},
+		{"env=staging", false},
+		{"env!=staging", true},
+		{"env!=prod", false},
+		{"service!=web", true},
+		{"team", true},
+		{"service", false},
+		{"!service", true},
+		{"!team", false},
+		{"env=prod,team=ops", true},
+		{"env=prod,team=dev", false},
+	}
+	for _, test := range tests {
+		sel, err := dbmodel.ParseLabelSelector(test.selector)
+		c.Assert(err, qt.IsNil)
+		c.Check(sel.Matches(labels), qt.Equals, test.expect, qt.Commentf("selector %q", test.selector))
+	}
+	sel, err := dbmodel.ParseLabelSelector("!env")
+	c.Assert(err, qt.IsNil)
+	c.Check(sel.Matches(nil), qt.IsTrue)
+}
+
+func TestValidateLabel(t *testing.T) {
+	c := qt.New(t)
+
+	c.Check(dbmodel.ValidateLabelKey("app.kubernetes.io/name"), qt.IsNil)
+	c.Check(dbmodel.ValidateLabelKey(""), qt.ErrorMatches, `invalid label key ""`)
+	c.Check(dbmodel.ValidateLabelKey("a b"), qt.ErrorMatches, `invalid label key "a b"`)
+	c.Check(dbmodel.ValidateLabelValue("any value: at all!"), qt.IsNil)
+	c.Check(dbmodel.ValidateLabelValue("a,b"), qt.ErrorMatches, `invalid label value "a,b"`)
+}
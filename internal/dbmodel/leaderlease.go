@@ -0,0 +1,30 @@
+// Copyright 2024 Canonical.
+
+package dbmodel
+
+import "time"
+
+// A LeaderLease records which JIMM replica currently holds a named
+// leadership role.
+type LeaderLease struct {
+	// Name is the name of the leadership role.
+	Name string `gorm:"primaryKey"`
+
+	// Holder identifies the replica holding the lease.
+	Holder string `gorm:"not null"`
+
+	// Token is the fencing token of the lease. It is incremented every
+	// time the lease changes hands, so work started under an older token
+	// can be detected as stale.
+	Token int64 `gorm:"not null"`
+
+	// AcquiredAt is the time the current holder acquired the lease.
+	AcquiredAt time.Time `gorm:"not null"`
+
+	// RenewedAt is the time the current holder last renewed the lease.
+	RenewedAt time.Time `gorm:"not null"`
+
+	// ExpiresAt is the time after which the lease can be taken over by
+	// another replica.
+	ExpiresAt time.Time `gorm:"not null"`
+}
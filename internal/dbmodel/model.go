@@ -48,6 +48,30 @@ type Model struct {
 
 	// Offers are the ApplicationOffers attached to the model.
 	Offers []ApplicationOffer
+
+	// ExpiresAt holds the time after which an ephemeral model is
+	// destroyed automatically. Models without an expiry time are not
+	// ephemeral.
+	ExpiresAt sql.NullTime
+
+	// ExpiryWarningSent records whether the owner of an ephemeral model
+	// has been warned that the model is about to expire.
+	ExpiryWarningSent bool
+
+	// Labels holds user defined key/value metadata for the model.
+	Labels StringMap
+
+	// SummaryStatus holds the status of the model last reported in the
+	// model summaries from its controller.
+	SummaryStatus string
+
+	// SummaryStatusSince holds the time at which the model's summary
+	// status last changed.
+	SummaryStatusSince sql.NullTime
+
+	// SummaryStatusMessage holds the first status message reported with
+	// the model's summary status.
+	SummaryStatusMessage string
 }
 
 // Tag returns a names.Tag for the model.
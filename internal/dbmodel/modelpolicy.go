@@ -0,0 +1,207 @@
+// Copyright 2024 Canonical.
+
+package dbmodel
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// Model policy rules, as reported in policy violations.
+const (
+	ModelPolicyRuleAllowedClouds      = "allowed-clouds"
+	ModelPolicyRuleRequiredConfig     = "required-config"
+	ModelPolicyRuleForbiddenConfig    = "forbidden-config"
+	ModelPolicyRuleNamePattern        = "name-pattern"
+	ModelPolicyRuleAllowedCredentials = "allowed-credentials"
+)
+
+// A ModelPolicy holds a set of admission rules checked when a model is
+// added.
+type ModelPolicy struct {
+	// Note this doesn't use the standard gorm.Model to avoid soft-deletes.
+	ID        uint `gorm:"primarykey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	// Name is the name of the policy.
+	Name string `gorm:"not null;uniqueIndex"`
+
+	// Groups holds the UUIDs of the groups whose members' models the
+	// policy applies to. An empty list applies to every owner.
+	Groups Strings
+
+	// Clouds holds the clouds, in the form "cloud" or "cloud/region",
+	// hosting the models the policy applies to. An empty list applies
+	// to every cloud.
+	Clouds Strings
+
+	// AllowedClouds holds the clouds, in the form "cloud" or
+	// "cloud/region", models may be hosted on. An empty list allows any
+	// cloud.
+	AllowedClouds Strings
+
+	// RequiredConfig maps config keys that must be set to the value
+	// they must have. An empty value allows any value.
+	RequiredConfig StringMap
+
+	// ForbiddenConfig maps config keys to a value they must not have.
+	// An empty value forbids the key from being set.
+	ForbiddenConfig StringMap
+
+	// NamePattern is a regular expression model names must match in
+	// full. An empty pattern allows any name.
+	NamePattern string `gorm:"not null"`
+
+	// AllowedCredentials holds shell patterns matched against the path
+	// of the credential used by the model. An empty list allows any
+	// credential.
+	AllowedCredentials Strings
+}
+
+// A ModelPolicySubject describes a model being checked against model
+// policies.
+type ModelPolicySubject struct {
+	// Name is the name of the model.
+	Name string
+
+	// Groups holds the UUIDs of the groups the model's owner is a
+	// member of.
+	Groups []string
+
+	// Cloud and Region identify where the model is hosted.
+	Cloud  string
+	Region string
+
+	// Credential is the path, "cloud/owner/name", of the model's cloud
+	// credential.
+	Credential string
+
+	// Config holds the model's config.
+	Config map[string]interface{}
+}
+
+// AppliesTo reports whether the policy applies to the given model.
+func (p ModelPolicy) AppliesTo(s ModelPolicySubject) bool {
+	if len(p.Groups) > 0 && !containsAny(p.Groups, s.Groups) {
+		return false
+	}
+	if len(p.Clouds) > 0 && !matchCloudRegion(p.Clouds, s.Cloud, s.Region) {
+		return false
+	}
+	return true
+}
+
+// Check returns the rules of the policy the given model violates. The
+// policy is assumed to apply to the model.
+func (p ModelPolicy) Check(s ModelPolicySubject) []apiparams.ModelPolicyViolation {
+	var violations []apiparams.ModelPolicyViolation
+	violate := func(rule, format string, args ...interface{}) {
+		violations = append(violations, apiparams.ModelPolicyViolation{
+			Policy:  p.Name,
+			Rule:    rule,
+			Message: fmt.Sprintf(format, args...),
+		})
+	}
+
+	if len(p.AllowedClouds) > 0 && !matchCloudRegion(p.AllowedClouds, s.Cloud, s.Region) {
+		violate(ModelPolicyRuleAllowedClouds, "cloud region %s/%s is not allowed", s.Cloud, s.Region)
+	}
+	for _, k := range sortedKeys(p.RequiredConfig) {
+		v, ok := s.Config[k]
+		switch {
+		case !ok:
+			violate(ModelPolicyRuleRequiredConfig, "config key %q must be set", k)
+		case p.RequiredConfig[k] != "" && fmt.Sprint(v) != p.RequiredConfig[k]:
+			violate(ModelPolicyRuleRequiredConfig, "config key %q must be %q", k, p.RequiredConfig[k])
+		}
+	}
+	for _, k := range sortedKeys(p.ForbiddenConfig) {
+		v, ok := s.Config[k]
+		switch {
+		case !ok:
+		case p.ForbiddenConfig[k] == "":
+			violate(ModelPolicyRuleForbiddenConfig, "config key %q must not be set", k)
+		case fmt.Sprint(v) == p.ForbiddenConfig[k]:
+			violate(ModelPolicyRuleForbiddenConfig, "config key %q must not be %q", k, p.ForbiddenConfig[k])
+		}
+	}
+	if p.NamePattern != "" {
+		re, err := CompileModelNamePattern(p.NamePattern)
+		if err != nil || !re.MatchString(s.Name) {
+			violate(ModelPolicyRuleNamePattern, "model name %q does not match %q", s.Name, p.NamePattern)
+		}
+	}
+	if len(p.AllowedCredentials) > 0 {
+		allowed := false
+		for _, pattern := range p.AllowedCredentials {
+			if ok, _ := path.Match(pattern, s.Credential); ok {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			violate(ModelPolicyRuleAllowedCredentials, "cloud credential %s is not allowed", s.Credential)
+		}
+	}
+	return violations
+}
+
+// CompileModelNamePattern compiles a model policy name pattern so that it
+// must match the whole model name.
+func CompileModelNamePattern(pattern string) (*regexp.Regexp, error) {
+	return regexp.Compile("^(?:" + pattern + ")$")
+}
+
+// ToAPIModelPolicy converts a model policy to its API representation. The
+// given names are used for the policy's groups.
+func (p ModelPolicy) ToAPIModelPolicy(groupNames []string) apiparams.ModelPolicy {
+	return apiparams.ModelPolicy{
+		Name:               p.Name,
+		Groups:             groupNames,
+		Clouds:             p.Clouds,
+		AllowedClouds:      p.AllowedClouds,
+		RequiredConfig:     p.RequiredConfig,
+		ForbiddenConfig:    p.ForbiddenConfig,
+		NamePattern:        p.NamePattern,
+		AllowedCredentials: p.AllowedCredentials,
+	}
+}
+
+// matchCloudRegion reports whether any of the given "cloud" or
+// "cloud/region" entries matches the given cloud and region.
+func matchCloudRegion(entries []string, cloud, region string) bool {
+	for _, e := range entries {
+		c, r, hasRegion := strings.Cut(e, "/")
+		if c == cloud && (!hasRegion || r == region) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsAny(a, b []string) bool {
+	for _, x := range a {
+		for _, y := range b {
+			if x == y {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
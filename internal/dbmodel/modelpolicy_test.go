@@ -0,0 +1,152 @@
+// Copyright 2024 Canonical.
+
+package dbmodel_test
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+var modelPolicySubject = dbmodel.ModelPolicySubject{
+	Name:       "prod-web",
+	Groups:     []string{"group-1"},
+	Cloud:      "aws",
+	Region:     "us-east-1",
+	Credential: "aws/alice@canonical.com/prod",
+	Config: map[string]interface{}{
+		"logging-config":            "<root>=WARNING",
+		"automatically-retry-hooks": true,
+	},
+}
+
+func TestModelPolicyAppliesTo(t *testing.T) {
+	c := qt.New(t)
+
+	tests := []struct {
+		about  string
+		policy dbmodel.ModelPolicy
+		expect bool
+	}{{
+		about:  "empty scope",
+		expect: true,
+	}, {
+		about:  "matching group",
+		policy: dbmodel.ModelPolicy{Groups: dbmodel.Strings{"group-2", "group-1"}},
+		expect: true,
+	}, {
+		about:  "other group",
+		policy: dbmodel.ModelPolicy{Groups: dbmodel.Strings{"group-2"}},
+	}, {
+		about:  "matching cloud",
+		policy: dbmodel.ModelPolicy{Clouds: dbmodel.Strings{"aws"}},
+		expect: true,
+	}, {
+		about:  "matching cloud region",
+		policy: dbmodel.ModelPolicy{Clouds: dbmodel.Strings{"aws/us-east-1"}},
+		expect: true,
+	}, {
+		about:  "other cloud region",
+		policy: dbmodel.ModelPolicy{Clouds: dbmodel.Strings{"aws/eu-west-1"}},
+	}, {
+		about:  "matching group, other cloud",
+		policy: dbmodel.ModelPolicy{Groups: dbmodel.Strings{"group-1"}, Clouds: dbmodel.Strings{"gce"}},
+	}}
+	for _, test := range tests {
+		c.Run(test.about, func(c *qt.C) {
+			c.Check(test.policy.AppliesTo(modelPolicySubject), qt.Equals, test.expect)
+		})
+	}
+}
+
+func TestModelPolicyCheck(t *testing.T) {
+	c := qt.New(t)
+
+	tests := []struct {
+		about  string
+		policy dbmodel.ModelPolicy
+		expect []apiparams.ModelPolicyViolation
+	}{{
+		about: "no rules",
+	}, {
+		about: "all rules satisfied",
+		policy: dbmodel.ModelPolicy{
+			AllowedClouds:      dbmodel.Strings{"gce", "aws/us-east-1"},
+			RequiredConfig:     dbmodel.StringMap{"logging-config": "<root>=WARNING", "automatically-retry-hooks": "true"},
+			ForbiddenConfig:    dbmodel.StringMap{"default-series": "", "logging-config": "<root>=DEBUG"},
+			NamePattern:        "prod-[a-z]+",
+			AllowedCredentials: dbmodel.Strings{"aws/*/prod"},
+		},
+	}, {
+		about: "cloud not allowed",
+		policy: dbmodel.ModelPolicy{
+			Name:          "p",
+			AllowedClouds: dbmodel.Strings{"aws/eu-west-1"},
+		},
+		expect: []apiparams.ModelPolicyViolation{{
+			Policy:  "p",
+			Rule:    "allowed-clouds",
+			Message: "cloud region aws/us-east-1 is not allowed",
+		}},
+	}, {
+		about: "required config",
+		policy: dbmodel.ModelPolicy{
+			Name:           "p",
+			RequiredConfig: dbmodel.StringMap{"logging-config": "<root>=INFO", "http-proxy": ""},
+		},
+		expect: []apiparams.ModelPolicyViolation{{
+			Policy:  "p",
+			Rule:    "required-config",
+			Message: `config key "http-proxy" must be set`,
+		}, {
+			Policy:  "p",
+			Rule:    "required-config",
+			Message: `config key "logging-config" must be "<root>=INFO"`,
+		}},
+	}, {
+		about: "forbidden config",
+		policy: dbmodel.ModelPolicy{
+			Name:            "p",
+			ForbiddenConfig: dbmodel.StringMap{"automatically-retry-hooks": "true", "logging-config": ""},
+		},
+		expect: []apiparams.ModelPolicyViolation{{
+			Policy:  "p",
+			Rule:    "forbidden-config",
+			Message: `config key "automatically-retry-hooks" must not be "true"`,
+		}, {
+			Policy:  "p",
+			Rule:    "forbidden-config",
+			Message: `config key "logging-config" must not be set`,
+		}},
+	}, {
+		about: "name pattern must match the whole name",
+		policy: dbmodel.ModelPolicy{
+			Name:        "p",
+			NamePattern: "prod",
+		},
+		expect: []apiparams.ModelPolicyViolation{{
+			Policy:  "p",
+			Rule:    "name-pattern",
+			Message: `model name "prod-web" does not match "prod"`,
+		}},
+	}, {
+		about: "credential not allowed",
+		policy: dbmodel.ModelPolicy{
+			Name:               "p",
+			AllowedCredentials: dbmodel.Strings{"aws/*/dev"},
+		},
+		expect: []apiparams.ModelPolicyViolation{{
+			Policy:  "p",
+			Rule:    "allowed-credentials",
+			Message: `cloud credential aws/alice@canonical.com/prod is not allowed`,
+		}},
+	}}
+	for _, test := range tests {
+		c.Run(test.about, func(c *qt.C) {
+			c.Check(test.policy.Check(modelPolicySubject), qt.DeepEquals, test.expect)
+		})
+	}
+}
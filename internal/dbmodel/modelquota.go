@@ -0,0 +1,62 @@
+// Copyright 2024 Canonical.
+
+package dbmodel
+
+import (
+	"time"
+
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// A ModelQuotaKind is the kind of entity to which a ModelQuota applies.
+type ModelQuotaKind string
+
+const (
+	// ModelQuotaIdentity is the kind of quota that limits the number of
+	// models owned by a single identity.
+	ModelQuotaIdentity ModelQuotaKind = "identity"
+
+	// ModelQuotaGroup is the kind of quota that limits the total number
+	// of models owned by all the members of a group, including the
+	// members of any nested groups.
+	ModelQuotaGroup ModelQuotaKind = "group"
+
+	// ModelQuotaCloud is the kind of quota that limits the number of
+	// models hosted on a cloud, or cloud region.
+	ModelQuotaCloud ModelQuotaKind = "cloud"
+)
+
+// A ModelQuota limits the number of models that may be created by an
+// identity, by the members of a group, or on a cloud.
+type ModelQuota struct {
+	// Note this doesn't use the standard gorm.Model to avoid soft-deletes.
+	ID        uint `gorm:"primarykey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	// Kind is the kind of entity the quota applies to.
+	Kind ModelQuotaKind `gorm:"not null;uniqueIndex:idx_model_quotas_kind_subject_region"`
+
+	// Subject identifies the entity the quota applies to. This is the
+	// name of an identity, the UUID of a group or the name of a cloud.
+	Subject string `gorm:"not null;uniqueIndex:idx_model_quotas_kind_subject_region"`
+
+	// Region is the cloud region the quota applies to. This is only
+	// used with cloud quotas, an empty region applies to the whole
+	// cloud.
+	Region string `gorm:"not null;uniqueIndex:idx_model_quotas_kind_subject_region"`
+
+	// MaxModels is the maximum number of models allowed.
+	MaxModels int `gorm:"not null"`
+}
+
+// ToAPIModelQuota converts a model quota to its API representation.
+// The usage is not set.
+func (q ModelQuota) ToAPIModelQuota() apiparams.ModelQuota {
+	return apiparams.ModelQuota{
+		Kind:      string(q.Kind),
+		Subject:   q.Subject,
+		Region:    q.Region,
+		MaxModels: q.MaxModels,
+	}
+}
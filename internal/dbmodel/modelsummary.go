@@ -0,0 +1,19 @@
+// Copyright 2024 Canonical.
+
+package dbmodel
+
+import "time"
+
+// A ModelSummary holds the latest summary of a model received from its
+// controller. Model summaries are stored so that they can be distributed
+// to every JIMM replica.
+type ModelSummary struct {
+	// ModelUUID is the UUID of the summarized model.
+	ModelUUID string `gorm:"primaryKey"`
+
+	// Summary is the encoded jujuparams.ModelAbstract.
+	Summary JSON `gorm:"not null"`
+
+	// UpdatedAt is the time the summary was stored.
+	UpdatedAt time.Time `gorm:"not null"`
+}
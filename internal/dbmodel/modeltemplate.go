@@ -0,0 +1,59 @@
+// Copyright 2024 Canonical.
+
+package dbmodel
+
+import (
+	"time"
+
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// A ModelTemplate is a named set of settings applied to models created
+// from it.
+type ModelTemplate struct {
+	// Note this doesn't use the standard gorm.Model to avoid soft-deletes.
+	ID        uint `gorm:"primarykey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	// Name is the name of the template.
+	Name string `gorm:"not null;uniqueIndex"`
+
+	// Config holds the model config applied to models created from the
+	// template.
+	Config Map
+
+	// Cloud is the name of the cloud models created from the template
+	// must be hosted on. An empty cloud allows any cloud.
+	Cloud string `gorm:"not null"`
+
+	// Region is the name of the cloud region models created from the
+	// template must be hosted in. An empty region allows any region.
+	Region string `gorm:"not null"`
+
+	// CloudCredential is the name of the model owner's cloud credential
+	// used when a model is created without specifying a credential.
+	CloudCredential string `gorm:"not null"`
+
+	// Labels holds the labels set on models created from the template.
+	Labels StringMap
+
+	// Grants maps the UUIDs of groups to the access level the group is
+	// granted on models created from the template.
+	Grants StringMap
+}
+
+// ToAPIModelTemplate converts a model template to its API representation.
+// The given grants, keyed by group name, are used in place of the
+// template's grants, which are keyed by group UUID.
+func (t ModelTemplate) ToAPIModelTemplate(grants map[string]string) apiparams.ModelTemplate {
+	return apiparams.ModelTemplate{
+		Name:            t.Name,
+		Config:          t.Config,
+		Cloud:           t.Cloud,
+		Region:          t.Region,
+		CloudCredential: t.CloudCredential,
+		Labels:          t.Labels,
+		Grants:          grants,
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright 2024 Canonical.
+
+package dbmodel
+
+import (
+	"database/sql"
+	"time"
+
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// The ways a job run can be started.
+const (
+	// JobTriggerSchedule is the trigger of runs started by a job's
+	// schedule.
+	JobTriggerSchedule = "schedule"
+
+	// JobTriggerManual is the trigger of runs requested by a user.
+	JobTriggerManual = "manual"
+)
+
+// The statuses of a job run.
+const (
+	JobRunRunning     = "running"
+	JobRunSucceeded   = "succeeded"
+	JobRunFailed      = "failed"
+	JobRunInterrupted = "interrupted"
+)
+
+// A ScheduledJob is a background job that is run on a schedule by the
+// JIMM leader.
+type ScheduledJob struct {
+	// Name is the name of the job.
+	Name      string `gorm:"primaryKey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	// Schedule is the cron expression describing when the job runs.
+	Schedule string `gorm:"not null"`
+
+	// Jitter is the maximum random delay added to each scheduled run.
+	Jitter time.Duration `gorm:"not null"`
+
+	// NextRunAt is the time the job is next scheduled to run.
+	NextRunAt sql.NullTime
+
+	// TriggeredAt and TriggeredBy are set when a user has asked for the
+	// job to be run immediately, they are cleared when the run starts.
+	TriggeredAt sql.NullTime
+	TriggeredBy string `gorm:"not null"`
+}
+
+// ToAPIScheduledJob converts a scheduled job to its API representation.
+// The given last run, if any, is included.
+func (j ScheduledJob) ToAPIScheduledJob(lastRun *JobRun) apiparams.ScheduledJob {
+	job := apiparams.ScheduledJob{
+		Name:     j.Name,
+		Schedule: j.Schedule,
+	}
+	if j.Jitter > 0 {
+		job.Jitter = j.Jitter.String()
+	}
+	if j.NextRunAt.Valid {
+		job.NextRunAt = &j.NextRunAt.Time
+	}
+	if j.TriggeredAt.Valid {
+		job.TriggeredAt = &j.TriggeredAt.Time
+	}
+	if lastRun != nil {
+		run := lastRun.ToAPIJobRun()
+		job.LastRun = &run
+	}
+	return job
+}
+
+// A JobRun records a single run of a scheduled job.
+type JobRun struct {
+	ID uint `gorm:"primaryKey"`
+
+	// JobName is the name of the job that was run.
+	JobName string `gorm:"not null"`
+
+	// Trigger is how the run was started, either JobTriggerSchedule or
+	// JobTriggerManual. TriggeredBy holds the user that triggered a
+	// manual run.
+	Trigger     string `gorm:"not null"`
+	TriggeredBy string `gorm:"not null"`
+
+	// Replica identifies the JIMM replica that performed the run.
+	Replica string `gorm:"not null"`
+
+	// Status is the status of the run.
+	Status string `gorm:"not null"`
+
+	// StartedAt and FinishedAt are the times the run started and
+	// finished.
+	StartedAt  time.Time `gorm:"not null"`
+	FinishedAt sql.NullTime
+
+	// Error holds the error returned by a failed run.
+	Error string `gorm:"not null"`
+}
+
+// ToAPIJobRun converts a job run to its API representation.
+func (r JobRun) ToAPIJobRun() apiparams.JobRun {
+	run := apiparams.JobRun{
+		ID:          r.ID,
+		Job:         r.JobName,
+		Trigger:     r.Trigger,
+		TriggeredBy: r.TriggeredBy,
+		Replica:     r.Replica,
+		Status:      r.Status,
+		StartedAt:   r.StartedAt,
+		Error:       r.Error,
+	}
+	if r.FinishedAt.Valid {
+		run.FinishedAt = &r.FinishedAt.Time
+		run.Duration = r.FinishedAt.Time.Sub(r.StartedAt).String()
+	}
+	return run
+}
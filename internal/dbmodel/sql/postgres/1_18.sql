@@ -0,0 +1,11 @@
+-- 1_18.sql is a migration that records when application offers are consumed.
+CREATE TABLE IF NOT EXISTS application_offer_consume_events (
+	id BIGSERIAL PRIMARY KEY,
+	time TIMESTAMP WITH TIME ZONE NOT NULL,
+	application_offer_id BIGINT NOT NULL REFERENCES application_offers (id) ON DELETE CASCADE,
+	identity_name TEXT NOT NULL,
+	model_uuid TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_application_offer_consume_events_application_offer_id ON application_offer_consume_events (application_offer_id);
+
+UPDATE versions SET major=1, minor=18 WHERE component='jimmdb';
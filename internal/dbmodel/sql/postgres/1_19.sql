@@ -0,0 +1,13 @@
+-- 1_19.sql is a migration that adds model quotas.
+CREATE TABLE IF NOT EXISTS model_quotas (
+	id BIGSERIAL PRIMARY KEY,
+	created_at TIMESTAMP WITH TIME ZONE,
+	updated_at TIMESTAMP WITH TIME ZONE,
+	kind TEXT NOT NULL,
+	subject TEXT NOT NULL,
+	region TEXT NOT NULL DEFAULT '',
+	max_models INTEGER NOT NULL CHECK (max_models >= 0)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_model_quotas_kind_subject_region ON model_quotas (kind, subject, region);
+
+UPDATE versions SET major=1, minor=19 WHERE component='jimmdb';
@@ -0,0 +1,6 @@
+-- 1_20.sql is a migration that adds expiry times to ephemeral models.
+ALTER TABLE models ADD COLUMN IF NOT EXISTS expires_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE models ADD COLUMN IF NOT EXISTS expiry_warning_sent BOOLEAN NOT NULL DEFAULT FALSE;
+CREATE INDEX IF NOT EXISTS idx_models_expires_at ON models (expires_at) WHERE expires_at IS NOT NULL;
+
+UPDATE versions SET major=1, minor=20 WHERE component='jimmdb';
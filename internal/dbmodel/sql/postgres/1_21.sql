@@ -0,0 +1,5 @@
+-- 1_21.sql is a migration that adds user defined labels to models.
+ALTER TABLE models ADD COLUMN IF NOT EXISTS labels JSONB;
+CREATE INDEX IF NOT EXISTS idx_models_labels ON models USING GIN (labels);
+
+UPDATE versions SET major=1, minor=21 WHERE component='jimmdb';
@@ -0,0 +1,15 @@
+-- 1_22.sql is a migration that adds model templates.
+CREATE TABLE IF NOT EXISTS model_templates (
+	id BIGSERIAL PRIMARY KEY,
+	created_at TIMESTAMP WITH TIME ZONE,
+	updated_at TIMESTAMP WITH TIME ZONE,
+	name TEXT NOT NULL UNIQUE,
+	config BYTEA,
+	cloud TEXT NOT NULL DEFAULT '',
+	region TEXT NOT NULL DEFAULT '',
+	cloud_credential TEXT NOT NULL DEFAULT '',
+	labels BYTEA,
+	grants BYTEA
+);
+
+UPDATE versions SET major=1, minor=22 WHERE component='jimmdb';
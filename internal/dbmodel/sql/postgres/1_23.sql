@@ -0,0 +1,16 @@
+-- 1_23.sql is a migration that adds model creation policies.
+CREATE TABLE IF NOT EXISTS model_policies (
+	id BIGSERIAL PRIMARY KEY,
+	created_at TIMESTAMP WITH TIME ZONE,
+	updated_at TIMESTAMP WITH TIME ZONE,
+	name TEXT NOT NULL UNIQUE,
+	groups BYTEA,
+	clouds BYTEA,
+	allowed_clouds BYTEA,
+	required_config BYTEA,
+	forbidden_config BYTEA,
+	name_pattern TEXT NOT NULL DEFAULT '',
+	allowed_credentials BYTEA
+);
+
+UPDATE versions SET major=1, minor=23 WHERE component='jimmdb';
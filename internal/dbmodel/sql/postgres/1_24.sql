@@ -0,0 +1,56 @@
+-- 1_24.sql is a migration that adds the estate inventory, a periodically
+-- collected snapshot of the contents of every model.
+CREATE TABLE IF NOT EXISTS model_inventories (
+	id BIGSERIAL PRIMARY KEY,
+	model_id BIGINT NOT NULL UNIQUE REFERENCES models (id) ON DELETE CASCADE,
+	collected_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS inventory_applications (
+	id BIGSERIAL PRIMARY KEY,
+	model_inventory_id BIGINT NOT NULL REFERENCES model_inventories (id) ON DELETE CASCADE,
+	name TEXT NOT NULL,
+	charm TEXT NOT NULL,
+	charm_url TEXT NOT NULL,
+	charm_channel TEXT NOT NULL DEFAULT '',
+	charm_revision INTEGER NOT NULL DEFAULT -1,
+	base TEXT NOT NULL DEFAULT '',
+	status TEXT NOT NULL DEFAULT '',
+	exposed BOOLEAN NOT NULL DEFAULT FALSE
+);
+CREATE INDEX IF NOT EXISTS idx_inventory_applications_charm ON inventory_applications (charm, charm_revision);
+CREATE INDEX IF NOT EXISTS idx_inventory_applications_base ON inventory_applications (base);
+
+CREATE TABLE IF NOT EXISTS inventory_units (
+	id BIGSERIAL PRIMARY KEY,
+	model_inventory_id BIGINT NOT NULL REFERENCES model_inventories (id) ON DELETE CASCADE,
+	name TEXT NOT NULL,
+	application TEXT NOT NULL,
+	machine TEXT NOT NULL DEFAULT '',
+	principal TEXT NOT NULL DEFAULT '',
+	workload_status TEXT NOT NULL DEFAULT '',
+	agent_status TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS inventory_machines (
+	id BIGSERIAL PRIMARY KEY,
+	model_inventory_id BIGINT NOT NULL REFERENCES model_inventories (id) ON DELETE CASCADE,
+	machine_id TEXT NOT NULL,
+	base TEXT NOT NULL DEFAULT '',
+	instance_id TEXT NOT NULL DEFAULT '',
+	status TEXT NOT NULL DEFAULT '',
+	hardware TEXT NOT NULL DEFAULT ''
+);
+CREATE INDEX IF NOT EXISTS idx_inventory_machines_base ON inventory_machines (base);
+
+CREATE TABLE IF NOT EXISTS inventory_relations (
+	id BIGSERIAL PRIMARY KEY,
+	model_inventory_id BIGINT NOT NULL REFERENCES model_inventories (id) ON DELETE CASCADE,
+	relation_id INTEGER NOT NULL,
+	key TEXT NOT NULL,
+	interface TEXT NOT NULL DEFAULT '',
+	scope TEXT NOT NULL DEFAULT '',
+	status TEXT NOT NULL DEFAULT ''
+);
+
+UPDATE versions SET major=1, minor=24 WHERE component='jimmdb';
@@ -0,0 +1,5 @@
+-- 1_25.sql is a migration that records the workload version of
+-- applications in the estate inventory.
+ALTER TABLE inventory_applications ADD COLUMN IF NOT EXISTS workload_version TEXT NOT NULL DEFAULT '';
+
+UPDATE versions SET major=1, minor=25 WHERE component='jimmdb';
@@ -0,0 +1,33 @@
+-- 1_26.sql is a migration that adds model upgrade campaigns.
+CREATE TABLE IF NOT EXISTS upgrade_campaigns (
+	id BIGSERIAL PRIMARY KEY,
+	created_at TIMESTAMP WITH TIME ZONE,
+	updated_at TIMESTAMP WITH TIME ZONE,
+	name TEXT NOT NULL UNIQUE,
+	target_version TEXT NOT NULL DEFAULT '',
+	agent_stream TEXT NOT NULL DEFAULT '',
+	controller TEXT NOT NULL DEFAULT '',
+	label_selector TEXT NOT NULL DEFAULT '',
+	agent_version TEXT NOT NULL DEFAULT '',
+	concurrency INTEGER NOT NULL DEFAULT 1,
+	failure_threshold INTEGER NOT NULL DEFAULT 0,
+	status TEXT NOT NULL,
+	failures INTEGER NOT NULL DEFAULT 0,
+	message TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS upgrade_campaign_models (
+	id BIGSERIAL PRIMARY KEY,
+	created_at TIMESTAMP WITH TIME ZONE,
+	updated_at TIMESTAMP WITH TIME ZONE,
+	upgrade_campaign_id BIGINT NOT NULL REFERENCES upgrade_campaigns (id) ON DELETE CASCADE,
+	model_id BIGINT NOT NULL REFERENCES models (id) ON DELETE CASCADE,
+	status TEXT NOT NULL,
+	from_version TEXT NOT NULL DEFAULT '',
+	chosen_version TEXT NOT NULL DEFAULT '',
+	error TEXT NOT NULL DEFAULT '',
+	UNIQUE (upgrade_campaign_id, model_id)
+);
+CREATE INDEX IF NOT EXISTS upgrade_campaign_models_status ON upgrade_campaign_models (upgrade_campaign_id, status);
+
+UPDATE versions SET major=1, minor=26 WHERE component='jimmdb';
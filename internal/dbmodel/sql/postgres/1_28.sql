@@ -0,0 +1,12 @@
+-- 1_28.sql is a migration that adds leader leases used to elect the JIMM
+-- replica that runs background jobs.
+CREATE TABLE IF NOT EXISTS leader_leases (
+	name TEXT PRIMARY KEY,
+	holder TEXT NOT NULL,
+	token BIGINT NOT NULL,
+	acquired_at TIMESTAMP WITH TIME ZONE NOT NULL,
+	renewed_at TIMESTAMP WITH TIME ZONE NOT NULL,
+	expires_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+UPDATE versions SET major=1, minor=28 WHERE component='jimmdb';
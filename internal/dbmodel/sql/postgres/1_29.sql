@@ -0,0 +1,9 @@
+-- 1_29.sql is a migration that adds the latest model summaries, which
+-- are distributed to all JIMM replicas.
+CREATE TABLE IF NOT EXISTS model_summaries (
+	model_uuid TEXT PRIMARY KEY REFERENCES models (uuid) ON DELETE CASCADE,
+	summary JSONB NOT NULL,
+	updated_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+UPDATE versions SET major=1, minor=29 WHERE component='jimmdb';
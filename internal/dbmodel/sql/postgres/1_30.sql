@@ -0,0 +1,27 @@
+-- 1_30.sql is a migration that adds scheduled background jobs and the
+-- history of their runs.
+CREATE TABLE IF NOT EXISTS scheduled_jobs (
+	name TEXT PRIMARY KEY,
+	created_at TIMESTAMP WITH TIME ZONE,
+	updated_at TIMESTAMP WITH TIME ZONE,
+	schedule TEXT NOT NULL,
+	jitter BIGINT NOT NULL DEFAULT 0,
+	next_run_at TIMESTAMP WITH TIME ZONE,
+	triggered_at TIMESTAMP WITH TIME ZONE,
+	triggered_by TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS job_runs (
+	id BIGSERIAL PRIMARY KEY,
+	job_name TEXT NOT NULL REFERENCES scheduled_jobs (name) ON DELETE CASCADE,
+	trigger TEXT NOT NULL,
+	triggered_by TEXT NOT NULL DEFAULT '',
+	replica TEXT NOT NULL,
+	status TEXT NOT NULL,
+	started_at TIMESTAMP WITH TIME ZONE NOT NULL,
+	finished_at TIMESTAMP WITH TIME ZONE,
+	error TEXT NOT NULL DEFAULT ''
+);
+CREATE INDEX IF NOT EXISTS idx_job_runs_job_name_id ON job_runs (job_name, id);
+
+UPDATE versions SET major=1, minor=30 WHERE component='jimmdb';
@@ -0,0 +1,53 @@
+-- 1_31.sql is a migration that adds alert rules, their receivers and
+-- silences, and the alerts they raise.
+CREATE TABLE IF NOT EXISTS alert_receivers (
+	id BIGSERIAL PRIMARY KEY,
+	created_at TIMESTAMP WITH TIME ZONE,
+	updated_at TIMESTAMP WITH TIME ZONE,
+	name TEXT NOT NULL UNIQUE,
+	type TEXT NOT NULL,
+	url TEXT NOT NULL DEFAULT '',
+	secret TEXT NOT NULL DEFAULT '',
+	emails BYTEA
+);
+
+CREATE TABLE IF NOT EXISTS alert_rules (
+	id BIGSERIAL PRIMARY KEY,
+	created_at TIMESTAMP WITH TIME ZONE,
+	updated_at TIMESTAMP WITH TIME ZONE,
+	name TEXT NOT NULL UNIQUE,
+	kind TEXT NOT NULL,
+	for_duration BIGINT NOT NULL DEFAULT 0,
+	repeat_interval BIGINT NOT NULL DEFAULT 0,
+	severity TEXT NOT NULL DEFAULT '',
+	receivers BYTEA
+);
+
+CREATE TABLE IF NOT EXISTS alerts (
+	id BIGSERIAL PRIMARY KEY,
+	created_at TIMESTAMP WITH TIME ZONE,
+	updated_at TIMESTAMP WITH TIME ZONE,
+	alert_rule_id BIGINT NOT NULL REFERENCES alert_rules (id) ON DELETE CASCADE,
+	subject TEXT NOT NULL,
+	summary TEXT NOT NULL DEFAULT '',
+	labels JSONB,
+	active_at TIMESTAMP WITH TIME ZONE NOT NULL,
+	firing_at TIMESTAMP WITH TIME ZONE,
+	notified_at TIMESTAMP WITH TIME ZONE,
+	UNIQUE (alert_rule_id, subject)
+);
+
+CREATE TABLE IF NOT EXISTS alert_silences (
+	id BIGSERIAL PRIMARY KEY,
+	created_at TIMESTAMP WITH TIME ZONE,
+	updated_at TIMESTAMP WITH TIME ZONE,
+	rule TEXT NOT NULL DEFAULT '',
+	subject TEXT NOT NULL DEFAULT '',
+	starts_at TIMESTAMP WITH TIME ZONE NOT NULL,
+	ends_at TIMESTAMP WITH TIME ZONE NOT NULL,
+	created_by TEXT NOT NULL,
+	comment TEXT NOT NULL DEFAULT ''
+);
+CREATE INDEX IF NOT EXISTS idx_alert_silences_ends_at ON alert_silences (ends_at);
+
+UPDATE versions SET major=1, minor=31 WHERE component='jimmdb';
@@ -0,0 +1,7 @@
+-- 1_32.sql is a migration that adds the maintenance state to controllers.
+ALTER TABLE controllers ADD COLUMN IF NOT EXISTS maintenance_enabled BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE controllers ADD COLUMN IF NOT EXISTS maintenance_message TEXT NOT NULL DEFAULT '';
+ALTER TABLE controllers ADD COLUMN IF NOT EXISTS maintenance_ends_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE controllers ADD COLUMN IF NOT EXISTS maintenance_allow_read_only BOOLEAN NOT NULL DEFAULT FALSE;
+
+UPDATE versions SET major=1, minor=32 WHERE component='jimmdb';
@@ -0,0 +1,6 @@
+-- 1_33.sql is a migration that adds the last status reported in the
+-- model summaries to models.
+ALTER TABLE models ADD COLUMN IF NOT EXISTS summary_status TEXT NOT NULL DEFAULT '';
+ALTER TABLE models ADD COLUMN IF NOT EXISTS summary_status_since TIMESTAMP WITH TIME ZONE;
+
+UPDATE versions SET major=1, minor=33 WHERE component='jimmdb';
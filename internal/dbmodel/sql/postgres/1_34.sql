@@ -0,0 +1,5 @@
+-- 1_34.sql is a migration that adds the status message last reported in
+-- the model summaries to models.
+ALTER TABLE models ADD COLUMN IF NOT EXISTS summary_status_message TEXT NOT NULL DEFAULT '';
+
+UPDATE versions SET major=1, minor=34 WHERE component='jimmdb';
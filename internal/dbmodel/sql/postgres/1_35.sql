@@ -0,0 +1,11 @@
+-- 1_35.sql is a migration that keys the group grants of model templates
+-- by group UUID, rather than group name, so that renaming a group does
+-- not break the templates that refer to it.
+UPDATE model_templates SET grants = convert_to((
+	SELECT COALESCE(jsonb_object_agg(COALESCE(g.uuid, e.key), e.value), '{}'::jsonb)::text
+	FROM jsonb_each_text(convert_from(model_templates.grants, 'UTF8')::jsonb) AS e
+	LEFT JOIN groups g ON g.name = e.key
+), 'UTF8')
+WHERE grants IS NOT NULL;
+
+UPDATE versions SET major=1, minor=35 WHERE component='jimmdb';
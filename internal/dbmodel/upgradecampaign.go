@@ -0,0 +1,181 @@
+// Copyright 2024 Canonical.
+
+package dbmodel
+
+import (
+	"time"
+
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// The states of an upgrade campaign.
+const (
+	// UpgradeCampaignValidating is the state of a campaign whose models
+	// are being checked to see whether they can be upgraded.
+	UpgradeCampaignValidating = "validating"
+
+	// UpgradeCampaignReady is the state of a validated campaign waiting
+	// to be started.
+	UpgradeCampaignReady = "ready"
+
+	// UpgradeCampaignRunning is the state of a campaign whose models are
+	// being upgraded.
+	UpgradeCampaignRunning = "running"
+
+	// UpgradeCampaignPaused is the state of a campaign that has been
+	// paused, either by a user or because too many upgrades failed.
+	UpgradeCampaignPaused = "paused"
+
+	// UpgradeCampaignCompleted is the state of a campaign with no models
+	// left to upgrade.
+	UpgradeCampaignCompleted = "completed"
+)
+
+// The states of a model in an upgrade campaign.
+const (
+	// UpgradeModelPending is the state of a model that has not yet been
+	// validated.
+	UpgradeModelPending = "pending"
+
+	// UpgradeModelSkipped is the state of a model that does not need to
+	// be upgraded, or does not match the campaign's agent version.
+	UpgradeModelSkipped = "skipped"
+
+	// UpgradeModelInvalid is the state of a model that failed
+	// validation and will not be upgraded.
+	UpgradeModelInvalid = "invalid"
+
+	// UpgradeModelValidated is the state of a model waiting to be
+	// upgraded.
+	UpgradeModelValidated = "validated"
+
+	// UpgradeModelUpgrading is the state of a model being upgraded.
+	UpgradeModelUpgrading = "upgrading"
+
+	// UpgradeModelUpgraded is the state of a model whose upgrade was
+	// started by its controller.
+	UpgradeModelUpgraded = "upgraded"
+
+	// UpgradeModelFailed is the state of a model whose upgrade failed.
+	UpgradeModelFailed = "failed"
+)
+
+// An UpgradeCampaign upgrades the agent version of a set of models
+// across the estate.
+type UpgradeCampaign struct {
+	ID        uint `gorm:"primaryKey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	// Name is the name of the campaign.
+	Name string `gorm:"not null;uniqueIndex"`
+
+	// TargetVersion is the agent version models are upgraded to. If it
+	// is empty each model's controller chooses the version.
+	TargetVersion string `gorm:"not null"`
+
+	// AgentStream is the agent stream to upgrade from.
+	AgentStream string `gorm:"not null"`
+
+	// Controller, LabelSelector and AgentVersion hold the selectors used
+	// to choose the campaign's models.
+	Controller    string `gorm:"not null"`
+	LabelSelector string `gorm:"not null"`
+	AgentVersion  string `gorm:"not null"`
+
+	// Concurrency is the maximum number of models upgraded at the same
+	// time.
+	Concurrency int `gorm:"not null"`
+
+	// FailureThreshold is the number of failed upgrades after which the
+	// campaign is paused. A threshold of zero never pauses the
+	// campaign.
+	FailureThreshold int `gorm:"not null"`
+
+	// Status is the state of the campaign.
+	Status string `gorm:"not null"`
+
+	// Failures is the number of upgrades that have failed since the
+	// campaign was last started.
+	Failures int `gorm:"not null"`
+
+	// Message describes why the campaign is in its current state.
+	Message string `gorm:"not null"`
+
+	// Models holds the models in the campaign.
+	Models []UpgradeCampaignModel `gorm:"constraint:OnDelete:CASCADE"`
+}
+
+// ToAPIUpgradeCampaign converts an upgrade campaign to its API
+// representation. The campaign's models, along with their owners and
+// controllers, must have been loaded. The models themselves are only
+// included if withModels is true.
+func (c UpgradeCampaign) ToAPIUpgradeCampaign(withModels bool) apiparams.UpgradeCampaign {
+	ac := apiparams.UpgradeCampaign{
+		Name:             c.Name,
+		TargetVersion:    c.TargetVersion,
+		AgentStream:      c.AgentStream,
+		Controller:       c.Controller,
+		LabelSelector:    c.LabelSelector,
+		AgentVersion:     c.AgentVersion,
+		Concurrency:      c.Concurrency,
+		FailureThreshold: c.FailureThreshold,
+		Status:           c.Status,
+		Failures:         c.Failures,
+		Message:          c.Message,
+		CreatedAt:        c.CreatedAt,
+		UpdatedAt:        c.UpdatedAt,
+		Progress:         make(map[string]int),
+	}
+	for _, m := range c.Models {
+		ac.Progress[m.Status]++
+		if withModels {
+			ac.Models = append(ac.Models, m.ToAPIUpgradeCampaignModel())
+		}
+	}
+	return ac
+}
+
+// An UpgradeCampaignModel holds the state of a model in an upgrade
+// campaign.
+type UpgradeCampaignModel struct {
+	ID        uint `gorm:"primaryKey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	UpgradeCampaignID uint `gorm:"not null"`
+
+	// Model is the model to upgrade.
+	ModelID uint `gorm:"not null"`
+	Model   Model
+
+	// Status is the state of the model in the campaign.
+	Status string `gorm:"not null"`
+
+	// FromVersion is the agent version of the model when it was
+	// validated.
+	FromVersion string `gorm:"not null"`
+
+	// ChosenVersion is the version the model's controller chose to
+	// upgrade the model to.
+	ChosenVersion string `gorm:"not null"`
+
+	// Error holds the reason validating, or upgrading, the model failed.
+	Error string `gorm:"not null"`
+}
+
+// ToAPIUpgradeCampaignModel converts an upgrade campaign model to its
+// API representation.
+func (m UpgradeCampaignModel) ToAPIUpgradeCampaignModel() apiparams.UpgradeCampaignModel {
+	return apiparams.UpgradeCampaignModel{
+		ModelUUID:     m.Model.UUID.String,
+		ModelName:     m.Model.Name,
+		Owner:         m.Model.OwnerIdentityName,
+		Controller:    m.Model.Controller.Name,
+		Status:        m.Status,
+		FromVersion:   m.FromVersion,
+		ChosenVersion: m.ChosenVersion,
+		Error:         m.Error,
+		UpdatedAt:     m.UpdatedAt,
+	}
+}
@@ -8,6 +8,11 @@ const (
 	// Component is the component name in the version table for th
 	Component = "jimmdb"
 
+	// AuthModelComponent is the component name in the version table for
+	// the version of the OpenFGA authorisation model that the stored
+	// tuples conform to. Only the Major version is used.
+	AuthModelComponent = "authmodel"
+
 	// Major is the major version of the model described in the dbmodel
 	// package. It should be incremented if the database model is modified
 	// in a way that is not backwards-compatible. That is, a column or
@@ -20,7 +25,7 @@ const (
 	// Minor is the minor version of the model described in the dbmodel
 	// package. It should be incremented for any change made to the
 	// database model from database model in a released JIMM.
-	Minor = 17
+	Minor = 35
 )
 
 type Version struct {
@@ -0,0 +1,191 @@
+// Copyright 2024 Canonical.
+
+package dbmodel
+
+import (
+	"encoding/json"
+	"time"
+
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// The types of model lifecycle event delivered to webhooks.
+const (
+	// WebhookEventModelCreated is sent when a model is added.
+	WebhookEventModelCreated = "model-created"
+
+	// WebhookEventModelDestroyed is sent when a model starts being
+	// destroyed.
+	WebhookEventModelDestroyed = "model-destroyed"
+
+	// WebhookEventModelDead is sent when a destroyed model has been
+	// removed from its controller.
+	WebhookEventModelDead = "model-dead"
+
+	// WebhookEventModelMigrated is sent when a model has been migrated
+	// to another controller.
+	WebhookEventModelMigrated = "model-migrated"
+
+	// WebhookEventModelError is sent when the status of a model moves to
+	// error.
+	WebhookEventModelError = "model-error"
+
+	// WebhookEventModelExpiring is sent when an ephemeral model is about
+	// to expire.
+	WebhookEventModelExpiring = "model-expiring"
+)
+
+// WebhookEventTypes holds all the types of webhook event.
+var WebhookEventTypes = []string{
+	WebhookEventModelCreated,
+	WebhookEventModelDestroyed,
+	WebhookEventModelDead,
+	WebhookEventModelMigrated,
+	WebhookEventModelError,
+	WebhookEventModelExpiring,
+}
+
+// A WebhookSubscription is a webhook that model lifecycle events are
+// posted to.
+type WebhookSubscription struct {
+	ID        uint `gorm:"primaryKey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	// Name is the name of the subscription.
+	Name string `gorm:"not null;uniqueIndex"`
+
+	// URL is the URL events are posted to.
+	URL string `gorm:"not null"`
+
+	// Secret is the key used to sign event payloads.
+	Secret string `gorm:"not null"`
+
+	// EventTypes holds the types of event delivered to the webhook. If
+	// it is empty all events are delivered.
+	EventTypes Strings
+
+	// LabelSelector, Controller and Owner restrict the events delivered
+	// to those for matching models. Empty values match all models.
+	LabelSelector string `gorm:"not null"`
+	Controller    string `gorm:"not null"`
+	Owner         string `gorm:"not null"`
+}
+
+// Matches determines whether an event of the given type about the given
+// model should be delivered to the subscription. The model's controller
+// must have been loaded.
+func (s WebhookSubscription) Matches(eventType string, m *Model) bool {
+	if len(s.EventTypes) > 0 {
+		found := false
+		for _, t := range s.EventTypes {
+			if t == eventType {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if s.Controller != "" && s.Controller != m.Controller.Name {
+		return false
+	}
+	if s.Owner != "" && s.Owner != m.OwnerIdentityName {
+		return false
+	}
+	if s.LabelSelector != "" {
+		sel, err := ParseLabelSelector(s.LabelSelector)
+		if err != nil || !sel.Matches(m.Labels) {
+			return false
+		}
+	}
+	return true
+}
+
+// ToAPIWebhookSubscription converts a webhook subscription to its API
+// representation. The subscription's secret is not included.
+func (s WebhookSubscription) ToAPIWebhookSubscription() apiparams.WebhookSubscription {
+	return apiparams.WebhookSubscription{
+		Name:          s.Name,
+		URL:           s.URL,
+		EventTypes:    s.EventTypes,
+		LabelSelector: s.LabelSelector,
+		Controller:    s.Controller,
+		Owner:         s.Owner,
+		CreatedAt:     s.CreatedAt,
+	}
+}
+
+// A WebhookDelivery is an event waiting to be delivered to a webhook.
+type WebhookDelivery struct {
+	ID        uint `gorm:"primaryKey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	// WebhookSubscription is the subscription the event is delivered
+	// to.
+	WebhookSubscriptionID uint `gorm:"not null"`
+	WebhookSubscription   WebhookSubscription
+
+	// EventID and EventType identify the event being delivered.
+	EventID   string `gorm:"not null"`
+	EventType string `gorm:"not null"`
+
+	// Payload is the encoded apiparams.WebhookEvent that is posted to
+	// the webhook.
+	Payload JSON `gorm:"not null"`
+
+	// Attempts is the number of failed attempts to deliver the event.
+	Attempts int `gorm:"not null"`
+
+	// NextAttemptAt is the time the event will next be delivered.
+	NextAttemptAt time.Time `gorm:"not null"`
+
+	// LastError holds the reason the last delivery attempt failed.
+	LastError string `gorm:"not null"`
+}
+
+// A WebhookDeadLetter is an event that could not be delivered to a
+// webhook.
+type WebhookDeadLetter struct {
+	ID        uint `gorm:"primaryKey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	// WebhookSubscription is the subscription the event was delivered
+	// to.
+	WebhookSubscriptionID uint `gorm:"not null"`
+	WebhookSubscription   WebhookSubscription
+
+	// EventID and EventType identify the event that was not delivered.
+	EventID   string `gorm:"not null"`
+	EventType string `gorm:"not null"`
+
+	// Payload is the encoded apiparams.WebhookEvent that was posted to
+	// the webhook.
+	Payload JSON `gorm:"not null"`
+
+	// Attempts is the number of times delivery was attempted.
+	Attempts int `gorm:"not null"`
+
+	// LastError holds the reason the last delivery attempt failed.
+	LastError string `gorm:"not null"`
+}
+
+// ToAPIWebhookDeadLetter converts a webhook dead letter to its API
+// representation. The dead letter's subscription must have been loaded.
+func (l WebhookDeadLetter) ToAPIWebhookDeadLetter() apiparams.WebhookDeadLetter {
+	dl := apiparams.WebhookDeadLetter{
+		ID:           l.ID,
+		Subscription: l.WebhookSubscription.Name,
+		Attempts:     l.Attempts,
+		LastError:    l.LastError,
+		CreatedAt:    l.CreatedAt,
+	}
+	// The payload was encoded by JIMM so any error here can only be
+	// caused by database corruption, in which case the event is left
+	// partially filled.
+	_ = json.Unmarshal(l.Payload, &dl.Event)
+	return dl
+}
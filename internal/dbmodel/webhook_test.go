@@ -0,0 +1,109 @@
+// Copyright 2024 Canonical.
+
+package dbmodel_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+var webhookSubscriptionMatchesTests = []struct {
+	name         string
+	subscription dbmodel.WebhookSubscription
+	eventType    string
+	expect       bool
+}{{
+	name:      "all events",
+	eventType: dbmodel.WebhookEventModelCreated,
+	expect:    true,
+}, {
+	name:         "matching event type",
+	subscription: dbmodel.WebhookSubscription{EventTypes: dbmodel.Strings{dbmodel.WebhookEventModelError, dbmodel.WebhookEventModelDead}},
+	eventType:    dbmodel.WebhookEventModelDead,
+	expect:       true,
+}, {
+	name:         "other event type",
+	subscription: dbmodel.WebhookSubscription{EventTypes: dbmodel.Strings{dbmodel.WebhookEventModelError}},
+	eventType:    dbmodel.WebhookEventModelCreated,
+}, {
+	name:         "matching controller and owner",
+	subscription: dbmodel.WebhookSubscription{Controller: "controller-1", Owner: "alice@canonical.com"},
+	eventType:    dbmodel.WebhookEventModelCreated,
+	expect:       true,
+}, {
+	name:         "other controller",
+	subscription: dbmodel.WebhookSubscription{Controller: "controller-2"},
+	eventType:    dbmodel.WebhookEventModelCreated,
+}, {
+	name:         "other owner",
+	subscription: dbmodel.WebhookSubscription{Owner: "bob@canonical.com"},
+	eventType:    dbmodel.WebhookEventModelCreated,
+}, {
+	name:         "matching labels",
+	subscription: dbmodel.WebhookSubscription{LabelSelector: "env=prod,team"},
+	eventType:    dbmodel.WebhookEventModelCreated,
+	expect:       true,
+}, {
+	name:         "other labels",
+	subscription: dbmodel.WebhookSubscription{LabelSelector: "env=staging"},
+	eventType:    dbmodel.WebhookEventModelCreated,
+}}
+
+func TestWebhookSubscriptionMatches(t *testing.T) {
+	c := qt.New(t)
+
+	m := dbmodel.Model{
+		OwnerIdentityName: "alice@canonical.com",
+		Controller:        dbmodel.Controller{Name: "controller-1"},
+		Labels:            dbmodel.StringMap{"env": "prod", "team": "infra"},
+	}
+	for _, test := range webhookSubscriptionMatchesTests {
+		c.Run(test.name, func(c *qt.C) {
+			c.Check(test.subscription.Matches(test.eventType, &m), qt.Equals, test.expect)
+		})
+	}
+}
+
+func TestWebhookDeadLetterToAPIWebhookDeadLetter(t *testing.T) {
+	c := qt.New(t)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	event := apiparams.WebhookEvent{
+		ID:   "00000000-0000-0000-0000-000000000001",
+		Type: dbmodel.WebhookEventModelCreated,
+		Time: now,
+		Model: apiparams.WebhookEventModel{
+			UUID:       "00000002-0000-0000-0000-000000000001",
+			Name:       "model-1",
+			Owner:      "alice@canonical.com",
+			Controller: "controller-1",
+		},
+	}
+	payload, err := json.Marshal(event)
+	c.Assert(err, qt.IsNil)
+
+	dl := dbmodel.WebhookDeadLetter{
+		ID:                  1,
+		CreatedAt:           now,
+		WebhookSubscription: dbmodel.WebhookSubscription{Name: "chat-ops"},
+		EventID:             event.ID,
+		EventType:           event.Type,
+		Payload:             payload,
+		Attempts:            8,
+		LastError:           "connection refused",
+	}
+	c.Check(dl.ToAPIWebhookDeadLetter(), qt.DeepEquals, apiparams.WebhookDeadLetter{
+		ID:           1,
+		Subscription: "chat-ops",
+		Attempts:     8,
+		LastError:    "connection refused",
+		CreatedAt:    now,
+		Event:        event,
+	})
+}
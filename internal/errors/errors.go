@@ -110,6 +110,7 @@ const (
 	CodeBadRequest                   Code = jujuparams.CodeBadRequest
 	CodeCloudRegionRequired          Code = jujuparams.CodeCloudRegionRequired
 	CodeConnectionFailed             Code = "connection failed"
+	CodeControllerMaintenance        Code = apiparams.CodeControllerMaintenance
 	CodeDatabaseLocked               Code = "database locked"
 	CodeForbidden                    Code = jujuparams.CodeForbidden
 	CodeIncompatibleClouds           Code = jujuparams.CodeIncompatibleClouds
@@ -117,9 +118,11 @@ const (
 	CodeNotFound                     Code = jujuparams.CodeNotFound
 	CodeNotImplemented               Code = jujuparams.CodeNotImplemented
 	CodeNotSupported                 Code = jujuparams.CodeNotSupported
+	CodePolicyViolation              Code = apiparams.CodePolicyViolation
 	CodeRedirect                     Code = jujuparams.CodeRedirect
 	CodeServerConfiguration          Code = "server configuration"
 	CodeStillAlive                   Code = apiparams.CodeStillAlive
+	CodeQuotaExceeded                Code = apiparams.CodeQuotaExceeded
 	CodeUnauthorized                 Code = jujuparams.CodeUnauthorized
 	CodeSessionTokenInvalid          Code = jujuparams.CodeSessionTokenInvalid
 	CodeUpgradeInProgress            Code = jujuparams.CodeUpgradeInProgress
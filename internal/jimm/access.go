@@ -583,6 +583,9 @@ func (j *JIMM) OpenFGACleanup(ctx context.Context) (err error) {
 
 		orphanedTuples := j.orphanedTuples(ctx, tuples...)
 		if len(orphanedTuples) > 0 {
+			if err := checkLeader(ctx); err != nil {
+				return errors.E(op, err)
+			}
 			zapctx.Debug(ctx, "removing orphaned tuples", zap.Any("tuples", orphanedTuples))
 			err = j.OpenFGAClient.RemoveRelation(ctx, orphanedTuples...)
 			if err != nil {
@@ -0,0 +1,950 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/mail"
+	"net/smtp"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juju/zaputil/zapctx"
+	"go.uber.org/zap"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	"github.com/canonical/jimm/v3/internal/servermon"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const (
+	// DefaultAlertInterval is the default time between evaluations of
+	// the alert rules.
+	DefaultAlertInterval = 30 * time.Second
+
+	// defaultAlertRepeatInterval is the repeat interval of alert rules
+	// that do not specify one.
+	defaultAlertRepeatInterval = 4 * time.Hour
+
+	// defaultAlertSeverity is the severity of alert rules that do not
+	// specify one.
+	defaultAlertSeverity = "warning"
+
+	// auditWriteFailureWindow is the length of time after a failure to
+	// write to the audit log that the failure condition holds.
+	auditWriteFailureWindow = 5 * time.Minute
+
+	// AlertNotificationEvent is the value of the WebhookEventHeader in
+	// alert notifications posted to webhook receivers.
+	AlertNotificationEvent = "alert"
+
+	// alertEmailTimeout is the maximum time taken to send an alert
+	// notification email.
+	alertEmailTimeout = 30 * time.Second
+)
+
+// SMTPParams holds the parameters used to send alert notifications by
+// email.
+type SMTPParams struct {
+	// Address is the host:port address of the SMTP server. If this is
+	// empty alert notifications cannot be sent by email.
+	Address string
+
+	// From is the address alert notifications are sent from.
+	From string
+
+	// Username and Password, if set, are used to authenticate with the
+	// SMTP server.
+	Username string
+	Password string
+}
+
+// AlertingParams holds the parameters used when evaluating alert rules.
+type AlertingParams struct {
+	// Interval is the time between evaluations of the alert rules.
+	Interval time.Duration
+
+	// SMTP holds the parameters used to send notifications to email
+	// receivers.
+	SMTP SMTPParams
+}
+
+// auditWriteFailures records the recent failures of a replica to write
+// to the audit log.
+type auditWriteFailures struct {
+	mu    sync.Mutex
+	first time.Time
+	last  time.Time
+	err   string
+}
+
+// record records a failure to write to the audit log at the given time.
+func (f *auditWriteFailures) record(t time.Time, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.last.IsZero() || t.Sub(f.last) > auditWriteFailureWindow {
+		f.first = t
+	}
+	f.last = t
+	f.err = err.Error()
+}
+
+// active returns the time failures started and the most recent error if
+// there has been a failure within auditWriteFailureWindow of the given
+// time.
+func (f *auditWriteFailures) active(t time.Time) (time.Time, string, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.last.IsZero() || t.Sub(f.last) > auditWriteFailureWindow {
+		return time.Time{}, "", false
+	}
+	return f.first, f.err, true
+}
+
+// AddAlertReceiver adds a destination for alert notifications. If no
+// secret is given for a webhook receiver one is generated; the secret is
+// only ever returned by this call. Only JIMM administrators may add alert
+// receivers.
+func (j *JIMM) AddAlertReceiver(ctx context.Context, user *openfga.User, req apiparams.AddAlertReceiverRequest) (apiparams.AlertReceiver, error) {
+	const op = errors.Op("jimm.AddAlertReceiver")
+
+	if err := j.checkJimmAdmin(user); err != nil {
+		return apiparams.AlertReceiver{}, errors.E(op, err)
+	}
+	if req.Name == "" {
+		return apiparams.AlertReceiver{}, errors.E(op, errors.CodeBadRequest, "receiver name not specified")
+	}
+	r := dbmodel.AlertReceiver{
+		Name: req.Name,
+		Type: req.Type,
+	}
+	switch req.Type {
+	case dbmodel.AlertReceiverWebhook, dbmodel.AlertReceiverAlertmanager:
+		u, err := url.Parse(req.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return apiparams.AlertReceiver{}, errors.E(op, errors.CodeBadRequest, fmt.Sprintf("invalid receiver url %q", req.URL))
+		}
+		r.URL = req.URL
+	case dbmodel.AlertReceiverEmail:
+		if len(req.Emails) == 0 {
+			return apiparams.AlertReceiver{}, errors.E(op, errors.CodeBadRequest, "email receivers must have at least one address")
+		}
+		for _, addr := range req.Emails {
+			if _, err := mail.ParseAddress(addr); err != nil {
+				return apiparams.AlertReceiver{}, errors.E(op, errors.CodeBadRequest, fmt.Sprintf("invalid email address %q", addr))
+			}
+		}
+		r.Emails = req.Emails
+	default:
+		return apiparams.AlertReceiver{}, errors.E(op, errors.CodeBadRequest, fmt.Sprintf("invalid receiver type %q", req.Type))
+	}
+	if r.Type == dbmodel.AlertReceiverWebhook {
+		r.Secret = req.Secret
+		if r.Secret == "" {
+			buf := make([]byte, 32)
+			if _, err := rand.Read(buf); err != nil {
+				return apiparams.AlertReceiver{}, errors.E(op, err)
+			}
+			r.Secret = hex.EncodeToString(buf)
+		}
+	}
+
+	if err := j.Database.AddAlertReceiver(ctx, &r); err != nil {
+		return apiparams.AlertReceiver{}, errors.E(op, err)
+	}
+	ar := r.ToAPIAlertReceiver()
+	ar.Secret = r.Secret
+	return ar, nil
+}
+
+// ListAlertReceivers returns all the alert receivers. Only JIMM
+// administrators may list alert receivers.
+func (j *JIMM) ListAlertReceivers(ctx context.Context, user *openfga.User) ([]apiparams.AlertReceiver, error) {
+	const op = errors.Op("jimm.ListAlertReceivers")
+
+	if err := j.checkJimmAdmin(user); err != nil {
+		return nil, errors.E(op, err)
+	}
+	receivers, err := j.Database.ListAlertReceivers(ctx)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	result := make([]apiparams.AlertReceiver, len(receivers))
+	for i, r := range receivers {
+		result[i] = r.ToAPIAlertReceiver()
+	}
+	return result, nil
+}
+
+// RemoveAlertReceiver removes the named alert receiver. A receiver used
+// by an alert rule cannot be removed. Only JIMM administrators may remove
+// alert receivers.
+func (j *JIMM) RemoveAlertReceiver(ctx context.Context, user *openfga.User, name string) error {
+	const op = errors.Op("jimm.RemoveAlertReceiver")
+
+	if err := j.checkJimmAdmin(user); err != nil {
+		return errors.E(op, err)
+	}
+	rules, err := j.Database.ListAlertRules(ctx)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	for _, r := range rules {
+		for _, rn := range r.Receivers {
+			if rn == name {
+				return errors.E(op, errors.CodeBadRequest, fmt.Sprintf("alert receiver is used by rule %q", r.Name))
+			}
+		}
+	}
+	if err := j.Database.DeleteAlertReceiver(ctx, &dbmodel.AlertReceiver{Name: name}); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// AddAlertRule adds an alert rule. Only JIMM administrators may add alert
+// rules.
+func (j *JIMM) AddAlertRule(ctx context.Context, user *openfga.User, req apiparams.AddAlertRuleRequest) (apiparams.AlertRule, error) {
+	const op = errors.Op("jimm.AddAlertRule")
+
+	if err := j.checkJimmAdmin(user); err != nil {
+		return apiparams.AlertRule{}, errors.E(op, err)
+	}
+	if req.Name == "" {
+		return apiparams.AlertRule{}, errors.E(op, errors.CodeBadRequest, "rule name not specified")
+	}
+	if !isAlertKind(req.Kind) {
+		return apiparams.AlertRule{}, errors.E(op, errors.CodeBadRequest, fmt.Sprintf("invalid alert kind %q", req.Kind))
+	}
+	r := dbmodel.AlertRule{
+		Name:           req.Name,
+		Kind:           req.Kind,
+		RepeatInterval: defaultAlertRepeatInterval,
+		Severity:       req.Severity,
+		Receivers:      req.Receivers,
+	}
+	var err error
+	if req.For != "" {
+		r.For, err = time.ParseDuration(req.For)
+		if err != nil || r.For < 0 {
+			return apiparams.AlertRule{}, errors.E(op, errors.CodeBadRequest, fmt.Sprintf("invalid duration %q", req.For))
+		}
+	}
+	if req.RepeatInterval != "" {
+		r.RepeatInterval, err = time.ParseDuration(req.RepeatInterval)
+		if err != nil || r.RepeatInterval <= 0 {
+			return apiparams.AlertRule{}, errors.E(op, errors.CodeBadRequest, fmt.Sprintf("invalid repeat interval %q", req.RepeatInterval))
+		}
+	}
+	if r.Severity == "" {
+		r.Severity = defaultAlertSeverity
+	}
+	if len(r.Receivers) == 0 {
+		return apiparams.AlertRule{}, errors.E(op, errors.CodeBadRequest, "no receivers specified")
+	}
+	for _, name := range r.Receivers {
+		if err := j.Database.GetAlertReceiver(ctx, &dbmodel.AlertReceiver{Name: name}); err != nil {
+			if errors.ErrorCode(err) == errors.CodeNotFound {
+				return apiparams.AlertRule{}, errors.E(op, errors.CodeBadRequest, fmt.Sprintf("alert receiver %q not found", name))
+			}
+			return apiparams.AlertRule{}, errors.E(op, err)
+		}
+	}
+
+	if err := j.Database.AddAlertRule(ctx, &r); err != nil {
+		return apiparams.AlertRule{}, errors.E(op, err)
+	}
+	return r.ToAPIAlertRule(), nil
+}
+
+// ListAlertRules returns all the alert rules. Only JIMM administrators may
+// list alert rules.
+func (j *JIMM) ListAlertRules(ctx context.Context, user *openfga.User) ([]apiparams.AlertRule, error) {
+	const op = errors.Op("jimm.ListAlertRules")
+
+	if err := j.checkJimmAdmin(user); err != nil {
+		return nil, errors.E(op, err)
+	}
+	rules, err := j.Database.ListAlertRules(ctx)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	result := make([]apiparams.AlertRule, len(rules))
+	for i, r := range rules {
+		result[i] = r.ToAPIAlertRule()
+	}
+	return result, nil
+}
+
+// RemoveAlertRule removes the named alert rule along with the alerts it
+// has raised. Receivers are not notified that the alerts are resolved.
+// Only JIMM administrators may remove alert rules.
+func (j *JIMM) RemoveAlertRule(ctx context.Context, user *openfga.User, name string) error {
+	const op = errors.Op("jimm.RemoveAlertRule")
+
+	if err := j.checkJimmAdmin(user); err != nil {
+		return errors.E(op, err)
+	}
+	if err := j.Database.DeleteAlertRule(ctx, &dbmodel.AlertRule{Name: name}); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// ListAlerts returns the alerts that are pending or firing. Only JIMM
+// administrators may list alerts.
+func (j *JIMM) ListAlerts(ctx context.Context, user *openfga.User) ([]apiparams.Alert, error) {
+	const op = errors.Op("jimm.ListAlerts")
+
+	if err := j.checkJimmAdmin(user); err != nil {
+		return nil, errors.E(op, err)
+	}
+	now := time.Now()
+	alerts, err := j.Database.ListAlerts(ctx)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	silences, err := j.Database.ListAlertSilences(ctx, now)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	result := make([]apiparams.Alert, len(alerts))
+	for i := range alerts {
+		result[i] = alerts[i].ToAPIAlert()
+		result[i].Silenced = isSilenced(silences, &alerts[i], now)
+	}
+	return result, nil
+}
+
+// AddAlertSilence silences notifications for the matching alerts, from
+// now for the requested duration. Only JIMM administrators may add alert
+// silences.
+func (j *JIMM) AddAlertSilence(ctx context.Context, user *openfga.User, req apiparams.AddAlertSilenceRequest) (apiparams.AlertSilence, error) {
+	const op = errors.Op("jimm.AddAlertSilence")
+
+	if err := j.checkJimmAdmin(user); err != nil {
+		return apiparams.AlertSilence{}, errors.E(op, err)
+	}
+	if req.Rule == "" && req.Subject == "" {
+		return apiparams.AlertSilence{}, errors.E(op, errors.CodeBadRequest, "rule or subject must be specified")
+	}
+	d, err := time.ParseDuration(req.Duration)
+	if err != nil || d <= 0 {
+		return apiparams.AlertSilence{}, errors.E(op, errors.CodeBadRequest, fmt.Sprintf("invalid duration %q", req.Duration))
+	}
+	now := time.Now().UTC()
+	s := dbmodel.AlertSilence{
+		Rule:      req.Rule,
+		Subject:   req.Subject,
+		StartsAt:  now,
+		EndsAt:    now.Add(d),
+		CreatedBy: user.Name,
+		Comment:   req.Comment,
+	}
+	if err := j.Database.AddAlertSilence(ctx, &s); err != nil {
+		return apiparams.AlertSilence{}, errors.E(op, err)
+	}
+	return s.ToAPIAlertSilence(), nil
+}
+
+// ListAlertSilences returns the alert silences that have not ended. Only
+// JIMM administrators may list alert silences.
+func (j *JIMM) ListAlertSilences(ctx context.Context, user *openfga.User) ([]apiparams.AlertSilence, error) {
+	const op = errors.Op("jimm.ListAlertSilences")
+
+	if err := j.checkJimmAdmin(user); err != nil {
+		return nil, errors.E(op, err)
+	}
+	silences, err := j.Database.ListAlertSilences(ctx, time.Now())
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	result := make([]apiparams.AlertSilence, len(silences))
+	for i, s := range silences {
+		result[i] = s.ToAPIAlertSilence()
+	}
+	return result, nil
+}
+
+// RemoveAlertSilence removes the alert silence with the given ID. Only
+// JIMM administrators may remove alert silences.
+func (j *JIMM) RemoveAlertSilence(ctx context.Context, user *openfga.User, id uint) error {
+	const op = errors.Op("jimm.RemoveAlertSilence")
+
+	if err := j.checkJimmAdmin(user); err != nil {
+		return errors.E(op, err)
+	}
+	if err := j.Database.DeleteAlertSilence(ctx, &dbmodel.AlertSilence{ID: id}); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// An Alerter evaluates the alert rules and notifies their receivers
+// about the alerts raised. Every replica should run an Alerter: the
+// audit-write-failure rules are evaluated by each replica for itself,
+// all other rules are only evaluated by the leader.
+type Alerter struct {
+	// JIMM is the JIMM whose state the alert rules are evaluated
+	// against.
+	JIMM *JIMM
+
+	// Replica identifies this replica in the alerts about it.
+	Replica string
+
+	// IsLeader reports whether this replica is the leader. If this is
+	// nil the replica is assumed to be the leader.
+	IsLeader func() bool
+
+	// SMTP holds the parameters used to send notifications to email
+	// receivers.
+	SMTP SMTPParams
+}
+
+// An alertCondition is a condition of an alert rule that holds for a
+// subject.
+type alertCondition struct {
+	since   time.Time
+	summary string
+	labels  map[string]string
+}
+
+// Run evaluates the alert rules every interval until the given context
+// is canceled.
+func (a *Alerter) Run(ctx context.Context, interval time.Duration) error {
+	const op = errors.Op("jimm.Alerter.Run")
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		if err := a.Evaluate(ctx, time.Now()); err != nil {
+			// The alerter keeps running so that alerts are
+			// raised once the cause has been fixed.
+			zapctx.Error(ctx, "cannot evaluate alert rules", zap.Error(err))
+		}
+	}
+}
+
+// Evaluate evaluates the alert rules at the given time, raising and
+// resolving alerts and notifying receivers as required.
+func (a *Alerter) Evaluate(ctx context.Context, now time.Time) error {
+	const op = errors.Op("jimm.Alerter.Evaluate")
+
+	db := a.JIMM.Database
+	leader := a.IsLeader == nil || a.IsLeader()
+	rules, err := db.ListAlertRules(ctx)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	receivers, err := db.ListAlertReceivers(ctx)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	silences, err := db.ListAlertSilences(ctx, now)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	stored, err := db.ListAlerts(ctx)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	if leader {
+		if err := db.DeleteExpiredAlertSilences(ctx, now); err != nil {
+			zapctx.Warn(ctx, "cannot remove expired alert silences", zap.Error(err))
+		}
+	}
+
+	// Find the conditions that hold for each rule this replica
+	// evaluates.
+	conditions := make(map[string]map[string]alertCondition)
+	evaluated := make(map[uint]bool)
+	byKind := make(map[string]map[string]alertCondition)
+	for _, r := range rules {
+		if r.Kind != dbmodel.AlertKindAuditWriteFailure && !leader {
+			continue
+		}
+		evaluated[r.ID] = true
+		if _, ok := byKind[r.Kind]; !ok {
+			byKind[r.Kind], err = a.conditions(ctx, r.Kind, now)
+			if err != nil {
+				return errors.E(op, err)
+			}
+		}
+		conditions[r.Name] = byKind[r.Kind]
+	}
+
+	var notifications []alertNotification
+	current := make(map[string]map[string]bool)
+	for i := range stored {
+		alert := &stored[i]
+		if !a.evaluates(alert, evaluated, leader, now) {
+			continue
+		}
+		if current[alert.AlertRule.Name] == nil {
+			current[alert.AlertRule.Name] = make(map[string]bool)
+		}
+		current[alert.AlertRule.Name][alert.Subject] = true
+		cond, ok := conditions[alert.AlertRule.Name][alert.Subject]
+		if !ok {
+			// The condition no longer holds, the alert is
+			// resolved.
+			if alert.NotifiedAt.Valid && !isSilenced(silences, alert, now) {
+				notifications = append(notifications, alertNotification{alert: alert, resolved: true})
+			} else if err := db.DeleteAlert(ctx, alert); err != nil {
+				return errors.E(op, err)
+			}
+			continue
+		}
+		alert.Summary = cond.summary
+		alert.Labels = cond.labels
+		if !alert.FiringAt.Valid && now.Sub(alert.ActiveAt) >= alert.AlertRule.For {
+			alert.FiringAt.Time, alert.FiringAt.Valid = now, true
+		}
+		if err := db.UpdateAlert(ctx, alert); err != nil {
+			return errors.E(op, err)
+		}
+		if alert.FiringAt.Valid && !isSilenced(silences, alert, now) {
+			notifications = append(notifications, alertNotification{alert: alert})
+		}
+	}
+
+	for _, r := range rules {
+		subjects := make([]string, 0, len(conditions[r.Name]))
+		for subject := range conditions[r.Name] {
+			if !current[r.Name][subject] {
+				subjects = append(subjects, subject)
+			}
+		}
+		sort.Strings(subjects)
+		for _, subject := range subjects {
+			cond := conditions[r.Name][subject]
+			alert := &dbmodel.Alert{
+				AlertRuleID: r.ID,
+				AlertRule:   r,
+				Subject:     subject,
+				Summary:     cond.summary,
+				Labels:      cond.labels,
+				ActiveAt:    cond.since,
+			}
+			if now.Sub(alert.ActiveAt) >= r.For {
+				alert.FiringAt.Time, alert.FiringAt.Valid = now, true
+			}
+			if err := db.AddAlert(ctx, alert); err != nil {
+				return errors.E(op, err)
+			}
+			if alert.FiringAt.Valid && !isSilenced(silences, alert, now) {
+				notifications = append(notifications, alertNotification{alert: alert})
+			}
+		}
+	}
+
+	return a.notify(ctx, receivers, notifications, now)
+}
+
+// evaluates reports whether this replica evaluates the given alert.
+// Replicas evaluate their own audit-write-failure alerts, which they
+// update at every evaluation while the failures continue. The leader also
+// evaluates those that have not been updated for auditWriteFailureWindow,
+// so that the alerts of replicas that have gone away are resolved.
+func (a *Alerter) evaluates(alert *dbmodel.Alert, evaluated map[uint]bool, leader bool, now time.Time) bool {
+	if !evaluated[alert.AlertRuleID] {
+		return false
+	}
+	if alert.AlertRule.Kind == dbmodel.AlertKindAuditWriteFailure {
+		if alert.Subject == a.Replica {
+			return true
+		}
+		return leader && now.Sub(alert.UpdatedAt) > auditWriteFailureWindow
+	}
+	return true
+}
+
+// conditions returns the conditions of the given kind that hold at the
+// given time, keyed by subject.
+func (a *Alerter) conditions(ctx context.Context, kind string, now time.Time) (map[string]alertCondition, error) {
+	conditions := make(map[string]alertCondition)
+	switch kind {
+	case dbmodel.AlertKindModelError:
+		models, err := a.JIMM.Database.ListModelsBySummaryStatus(ctx, "error")
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range models {
+			summary := fmt.Sprintf("model %s is in error", m.Name)
+			if m.SummaryStatusMessage != "" {
+				summary += ": " + m.SummaryStatusMessage
+			}
+			conditions[m.UUID.String] = alertCondition{
+				since:   m.SummaryStatusSince.Time,
+				summary: summary,
+				labels: map[string]string{
+					"model_uuid": m.UUID.String,
+					"model_name": m.Name,
+					"cloud":      m.CloudRegion.Cloud.Name,
+					"region":     m.CloudRegion.Name,
+				},
+			}
+		}
+	case dbmodel.AlertKindControllerUnavailable:
+		err := a.JIMM.Database.ForEachController(ctx, func(ctl *dbmodel.Controller) error {
+			if !ctl.UnavailableSince.Valid {
+				return nil
+			}
+			conditions[ctl.Name] = alertCondition{
+				since:   ctl.UnavailableSince.Time,
+				summary: fmt.Sprintf("controller %s is unavailable", ctl.Name),
+				labels:  map[string]string{"controller": ctl.Name},
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	case dbmodel.AlertKindCredentialInvalid:
+		creds, err := a.JIMM.Database.ListInvalidCloudCredentials(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, cred := range creds {
+			id := cred.ResourceTag().Id()
+			conditions[id] = alertCondition{
+				since:   cred.UpdatedAt,
+				summary: fmt.Sprintf("cloud credential %s is invalid", id),
+				labels: map[string]string{
+					"cloud":      cred.CloudName,
+					"owner":      cred.OwnerIdentityName,
+					"credential": cred.Name,
+				},
+			}
+		}
+	case dbmodel.AlertKindAuditWriteFailure:
+		if since, msg, ok := a.JIMM.auditWriteFailures.active(now); ok {
+			conditions[a.Replica] = alertCondition{
+				since:   since,
+				summary: fmt.Sprintf("replica %s cannot write to the audit log: %s", a.Replica, msg),
+				labels:  map[string]string{"replica": a.Replica},
+			}
+		}
+	}
+	return conditions, nil
+}
+
+// An alertNotification is an alert that receivers are to be notified
+// about.
+type alertNotification struct {
+	alert    *dbmodel.Alert
+	resolved bool
+}
+
+// notify sends the given notifications to the receivers of the rules
+// that raised them. Notifications about firing alerts are only sent
+// once per repeat interval of the rule, except to alertmanager receivers
+// which are sent every firing alert every time so that the Alertmanager
+// knows the alert is still firing. Resolved alerts are removed once their
+// receivers have been notified.
+func (a *Alerter) notify(ctx context.Context, receivers []dbmodel.AlertReceiver, notifications []alertNotification, now time.Time) error {
+	const op = errors.Op("jimm.Alerter.notify")
+
+	receiversByName := make(map[string]*dbmodel.AlertReceiver, len(receivers))
+	for i := range receivers {
+		receiversByName[receivers[i].Name] = &receivers[i]
+	}
+
+	var names []string
+	batches := make(map[string][]alertNotification)
+	due := make(map[*dbmodel.Alert]bool)
+	for _, n := range notifications {
+		alert := n.alert
+		due[alert] = n.resolved || !alert.NotifiedAt.Valid || now.Sub(alert.NotifiedAt.Time) >= alert.AlertRule.RepeatInterval
+		for _, name := range alert.AlertRule.Receivers {
+			r, ok := receiversByName[name]
+			if !ok {
+				continue
+			}
+			if !due[alert] && r.Type != dbmodel.AlertReceiverAlertmanager {
+				continue
+			}
+			if _, ok := batches[name]; !ok {
+				names = append(names, name)
+			}
+			batches[name] = append(batches[name], n)
+		}
+	}
+
+	failed := make(map[*dbmodel.Alert]bool)
+	for _, name := range names {
+		r := receiversByName[name]
+		err := a.send(ctx, r, batches[name], now)
+		result := "success"
+		if err != nil {
+			result = "failure"
+			zapctx.Error(ctx, "cannot send alert notification", zap.String("receiver", name), zap.Error(err))
+			for _, n := range batches[name] {
+				failed[n.alert] = true
+			}
+		}
+		servermon.AlertNotificationCount.WithLabelValues(r.Type, result).Inc()
+	}
+
+	for _, n := range notifications {
+		alert := n.alert
+		if n.resolved {
+			// Resolved alerts are only notified once, even if
+			// sending the notification fails.
+			if err := a.JIMM.Database.DeleteAlert(ctx, alert); err != nil {
+				return errors.E(op, err)
+			}
+			continue
+		}
+		if !due[alert] || failed[alert] {
+			continue
+		}
+		alert.NotifiedAt.Time, alert.NotifiedAt.Valid = now, true
+		if err := a.JIMM.Database.UpdateAlert(ctx, alert); err != nil {
+			return errors.E(op, err)
+		}
+	}
+	return nil
+}
+
+// send sends a notification about the given alerts to the given
+// receiver.
+func (a *Alerter) send(ctx context.Context, r *dbmodel.AlertReceiver, notifications []alertNotification, now time.Time) error {
+	alerts := make([]apiparams.Alert, len(notifications))
+	status := apiparams.AlertStateResolved
+	for i, n := range notifications {
+		alerts[i] = n.alert.ToAPIAlert()
+		if n.resolved {
+			alerts[i].State = apiparams.AlertStateResolved
+			alerts[i].ResolvedAt = &now
+		} else {
+			status = apiparams.AlertStateFiring
+		}
+	}
+	switch r.Type {
+	case dbmodel.AlertReceiverWebhook:
+		return postAlertWebhook(ctx, r, apiparams.AlertNotification{
+			Receiver: r.Name,
+			Status:   status,
+			Alerts:   alerts,
+		})
+	case dbmodel.AlertReceiverAlertmanager:
+		return postAlertmanagerAlerts(ctx, r, alerts)
+	case dbmodel.AlertReceiverEmail:
+		return a.sendAlertEmail(ctx, r, status, alerts)
+	default:
+		return errors.E(fmt.Sprintf("unknown receiver type %q", r.Type))
+	}
+}
+
+// postAlertWebhook posts the given notification to a webhook receiver.
+// The notification is signed in the same way as webhook events.
+func postAlertWebhook(ctx context.Context, r *dbmodel.AlertReceiver, n apiparams.AlertNotification) error {
+	payload, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.URL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventHeader, AlertNotificationEvent)
+	req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(r.Secret, payload))
+	return doAlertRequest(ctx, req)
+}
+
+// alertmanagerAlert is an alert in the format accepted by the alerts API
+// of a Prometheus Alertmanager.
+type alertmanagerAlert struct {
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	StartsAt    time.Time         `json:"startsAt"`
+	EndsAt      *time.Time        `json:"endsAt,omitempty"`
+}
+
+// postAlertmanagerAlerts posts the given alerts to the alerts API of an
+// Alertmanager. The Alertmanager groups and deduplicates the alerts.
+func postAlertmanagerAlerts(ctx context.Context, r *dbmodel.AlertReceiver, alerts []apiparams.Alert) error {
+	amAlerts := make([]alertmanagerAlert, len(alerts))
+	for i, alert := range alerts {
+		labels := make(map[string]string, len(alert.Labels)+4)
+		for k, v := range alert.Labels {
+			labels[k] = v
+		}
+		labels["alertname"] = alert.Rule
+		labels["kind"] = alert.Kind
+		labels["severity"] = alert.Severity
+		labels["subject"] = alert.Subject
+		amAlerts[i] = alertmanagerAlert{
+			Labels:      labels,
+			Annotations: map[string]string{"summary": alert.Summary},
+			StartsAt:    alert.ActiveAt,
+			EndsAt:      alert.ResolvedAt,
+		}
+		if alert.FiringAt != nil {
+			amAlerts[i].StartsAt = *alert.FiringAt
+		}
+	}
+	payload, err := json.Marshal(amAlerts)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(r.URL, "/")+"/api/v2/alerts", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return doAlertRequest(ctx, req)
+}
+
+// doAlertRequest performs the given request. Any response other than a
+// 2xx status is an error.
+func doAlertRequest(ctx context.Context, req *http.Request) error {
+	servermon.InjectTraceContext(ctx, req.Header)
+	resp, err := webhookClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected response status %q", resp.Status)
+	}
+	return nil
+}
+
+// sendAlertEmail sends a notification about the given alerts to the
+// addresses of an email receiver. Sending the email is abandoned if it
+// takes longer than alertEmailTimeout or the given context is cancelled.
+func (a *Alerter) sendAlertEmail(ctx context.Context, r *dbmodel.AlertReceiver, status string, alerts []apiparams.Alert) error {
+	if a.SMTP.Address == "" {
+		return errors.E("email notifications are not configured")
+	}
+	var body strings.Builder
+	for _, alert := range alerts {
+		fmt.Fprintf(&body, "[%s] %s (%s, severity %s)\r\n", strings.ToUpper(alert.State), alert.Summary, alert.Rule, alert.Severity)
+		keys := make([]string, 0, len(alert.Labels))
+		for k := range alert.Labels {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			fmt.Fprintf(&body, "  %s: %s\r\n", k, alert.Labels[k])
+		}
+		fmt.Fprintf(&body, "  active since: %s\r\n\r\n", alert.ActiveAt.UTC().Format(time.RFC3339))
+	}
+	msg := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: [JIMM %s] %d alert(s)\r\nContent-Type: text/plain; charset=utf-8\r\n\r\n%s",
+		a.SMTP.From,
+		strings.Join(r.Emails, ", "),
+		strings.ToUpper(status),
+		len(alerts),
+		body.String(),
+	)
+	var auth smtp.Auth
+	if a.SMTP.Username != "" {
+		host, _, _ := strings.Cut(a.SMTP.Address, ":")
+		auth = smtp.PlainAuth("", a.SMTP.Username, a.SMTP.Password, host)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, alertEmailTimeout)
+	defer cancel()
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", a.SMTP.Address)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return err
+		}
+	}
+	// Closing the connection unblocks the client if the context is
+	// cancelled before the deadline.
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+	return sendMail(conn, a.SMTP.Address, auth, a.SMTP.From, r.Emails, []byte(msg))
+}
+
+// sendMail sends the given message over the given connection to an SMTP
+// server at the given address, in the same way as smtp.SendMail.
+func sendMail(conn net.Conn, addr string, auth smtp.Auth, from string, to []string, msg []byte) error {
+	host, _, _ := strings.Cut(addr, ":")
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+	if auth != nil {
+		if ok, _ := c.Extension("AUTH"); !ok {
+			return errors.E("smtp server does not support authentication")
+		}
+		if err := c.Auth(auth); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(from); err != nil {
+		return err
+	}
+	for _, addr := range to {
+		if err := c.Rcpt(addr); err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// isSilenced reports whether any of the given silences applies to the
+// given alert at the given time.
+func isSilenced(silences []dbmodel.AlertSilence, alert *dbmodel.Alert, t time.Time) bool {
+	for _, s := range silences {
+		if s.Matches(alert, t) {
+			return true
+		}
+	}
+	return false
+}
+
+func isAlertKind(kind string) bool {
+	for _, k := range dbmodel.AlertKinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,49 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+func TestSendAlertEmailUnresponsiveServer(t *testing.T) {
+	c := qt.New(t)
+
+	// The server accepts connections but never responds.
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, qt.IsNil)
+	defer l.Close()
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	a := &Alerter{
+		SMTP: SMTPParams{
+			Address: l.Addr().String(),
+			From:    "jimm@example.com",
+		},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err = a.sendAlertEmail(ctx, &dbmodel.AlertReceiver{Emails: []string{"ops@example.com"}}, apiparams.AlertStateFiring, []apiparams.Alert{{
+		Rule:    "models-in-error",
+		Summary: "model-1 is in error",
+	}})
+	c.Check(err, qt.Not(qt.IsNil))
+	c.Check(time.Since(start) < 5*time.Second, qt.IsTrue)
+}
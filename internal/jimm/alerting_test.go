@@ -0,0 +1,343 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	"github.com/canonical/jimm/v3/internal/testutils/jimmtest"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// alertReceiver records the alert notifications posted to it as a webhook
+// receiver, and the alerts posted to it as an Alertmanager.
+type alertReceiver struct {
+	mu            sync.Mutex
+	notifications []apiparams.AlertNotification
+	amAlerts      [][]map[string]interface{}
+	errors        []string
+}
+
+func (r *alertReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		r.errors = append(r.errors, err.Error())
+		return
+	}
+	if req.URL.Path == "/api/v2/alerts" {
+		var alerts []map[string]interface{}
+		if err := json.Unmarshal(body, &alerts); err != nil {
+			r.errors = append(r.errors, err.Error())
+		}
+		r.amAlerts = append(r.amAlerts, alerts)
+		return
+	}
+	if sig := req.Header.Get(jimm.WebhookSignatureHeader); sig != jimm.SignWebhookPayload("secret", body) {
+		r.errors = append(r.errors, "bad signature "+sig)
+	}
+	if req.Header.Get(jimm.WebhookEventHeader) != jimm.AlertNotificationEvent {
+		r.errors = append(r.errors, "bad headers")
+	}
+	var n apiparams.AlertNotification
+	if err := json.Unmarshal(body, &n); err != nil {
+		r.errors = append(r.errors, err.Error())
+	}
+	r.notifications = append(r.notifications, n)
+}
+
+func (r *alertReceiver) received() ([]apiparams.AlertNotification, [][]map[string]interface{}, []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]apiparams.AlertNotification(nil), r.notifications...),
+		append([][]map[string]interface{}(nil), r.amAlerts...),
+		append([]string(nil), r.errors...)
+}
+
+func TestAlerting(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	receiver := new(alertReceiver)
+	srv := httptest.NewServer(receiver)
+	defer srv.Close()
+
+	j := jimmtest.NewJIMM(c, nil)
+
+	env := jimmtest.ParseEnvironment(c, webhookTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, j.OpenFGAClient)
+	aliceIdentity := env.User("alice@canonical.com").DBObject(c, j.Database)
+	alice := openfga.NewUser(&aliceIdentity, j.OpenFGAClient)
+	admin := openfga.NewUser(&aliceIdentity, j.OpenFGAClient)
+	admin.JimmAdmin = true
+
+	hookReq := apiparams.AddAlertReceiverRequest{
+		Name:   "ops-hook",
+		Type:   dbmodel.AlertReceiverWebhook,
+		URL:    srv.URL,
+		Secret: "secret",
+	}
+	_, err := j.AddAlertReceiver(ctx, alice, hookReq)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+
+	_, err = j.AddAlertReceiver(ctx, admin, apiparams.AddAlertReceiverRequest{Name: "bad", Type: dbmodel.AlertReceiverEmail})
+	c.Check(err, qt.ErrorMatches, `email receivers must have at least one address`)
+	_, err = j.AddAlertReceiver(ctx, admin, apiparams.AddAlertReceiverRequest{Name: "bad", Type: "pager"})
+	c.Check(err, qt.ErrorMatches, `invalid receiver type "pager"`)
+
+	r, err := j.AddAlertReceiver(ctx, admin, hookReq)
+	c.Assert(err, qt.IsNil)
+	c.Check(r.Secret, qt.Equals, "secret")
+	_, err = j.AddAlertReceiver(ctx, admin, apiparams.AddAlertReceiverRequest{Name: "am", Type: dbmodel.AlertReceiverAlertmanager, URL: srv.URL})
+	c.Assert(err, qt.IsNil)
+
+	_, err = j.AddAlertRule(ctx, admin, apiparams.AddAlertRuleRequest{Name: "bad", Kind: "model-on-fire", Receivers: []string{"ops-hook"}})
+	c.Check(err, qt.ErrorMatches, `invalid alert kind "model-on-fire"`)
+	_, err = j.AddAlertRule(ctx, admin, apiparams.AddAlertRuleRequest{Name: "bad", Kind: dbmodel.AlertKindModelError, Receivers: []string{"pager"}})
+	c.Check(err, qt.ErrorMatches, `alert receiver "pager" not found`)
+
+	rule, err := j.AddAlertRule(ctx, admin, apiparams.AddAlertRuleRequest{
+		Name:      "controllers",
+		Kind:      dbmodel.AlertKindControllerUnavailable,
+		For:       "5m",
+		Severity:  "critical",
+		Receivers: []string{"ops-hook", "am"},
+	})
+	c.Assert(err, qt.IsNil)
+	c.Check(rule.RepeatInterval, qt.Equals, "4h0m0s")
+	_, err = j.AddAlertRule(ctx, admin, apiparams.AddAlertRuleRequest{
+		Name:      "models",
+		Kind:      dbmodel.AlertKindModelError,
+		Receivers: []string{"ops-hook"},
+	})
+	c.Assert(err, qt.IsNil)
+
+	err = j.RemoveAlertReceiver(ctx, admin, "am")
+	c.Check(err, qt.ErrorMatches, `alert receiver is used by rule "controllers"`)
+
+	alerter := jimm.Alerter{
+		JIMM:    j,
+		Replica: "jimm-0",
+	}
+
+	// The controller becomes unavailable, the alert is pending until
+	// the condition has held for 5 minutes.
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	ctl := dbmodel.Controller{Name: "controller-1"}
+	err = j.Database.GetController(ctx, &ctl)
+	c.Assert(err, qt.IsNil)
+	ctl.UnavailableSince = sql.NullTime{Time: now, Valid: true}
+	err = j.Database.UpdateController(ctx, &ctl)
+	c.Assert(err, qt.IsNil)
+
+	err = alerter.Evaluate(ctx, now)
+	c.Assert(err, qt.IsNil)
+	alerts, err := j.ListAlerts(ctx, admin)
+	c.Assert(err, qt.IsNil)
+	c.Assert(alerts, qt.HasLen, 1)
+	c.Check(alerts[0].Rule, qt.Equals, "controllers")
+	c.Check(alerts[0].Subject, qt.Equals, "controller-1")
+	c.Check(alerts[0].State, qt.Equals, apiparams.AlertStatePending)
+	notifications, amAlerts, errs := receiver.received()
+	c.Check(errs, qt.HasLen, 0)
+	c.Check(notifications, qt.HasLen, 0)
+	c.Check(amAlerts, qt.HasLen, 0)
+
+	// Once it fires both receivers are notified.
+	err = alerter.Evaluate(ctx, now.Add(5*time.Minute))
+	c.Assert(err, qt.IsNil)
+	notifications, amAlerts, errs = receiver.received()
+	c.Check(errs, qt.HasLen, 0)
+	c.Assert(notifications, qt.HasLen, 1)
+	c.Check(notifications[0].Receiver, qt.Equals, "ops-hook")
+	c.Check(notifications[0].Status, qt.Equals, apiparams.AlertStateFiring)
+	c.Assert(notifications[0].Alerts, qt.HasLen, 1)
+	c.Check(notifications[0].Alerts[0].Summary, qt.Equals, "controller controller-1 is unavailable")
+	c.Check(notifications[0].Alerts[0].Severity, qt.Equals, "critical")
+	c.Assert(amAlerts, qt.HasLen, 1)
+	c.Assert(amAlerts[0], qt.HasLen, 1)
+	c.Check(amAlerts[0][0]["labels"], qt.DeepEquals, map[string]interface{}{
+		"alertname":  "controllers",
+		"kind":       dbmodel.AlertKindControllerUnavailable,
+		"severity":   "critical",
+		"subject":    "controller-1",
+		"controller": "controller-1",
+	})
+
+	// The webhook is not notified again until the repeat interval has
+	// passed, the Alertmanager is sent the alert every time.
+	err = alerter.Evaluate(ctx, now.Add(6*time.Minute))
+	c.Assert(err, qt.IsNil)
+	notifications, amAlerts, _ = receiver.received()
+	c.Check(notifications, qt.HasLen, 1)
+	c.Check(amAlerts, qt.HasLen, 2)
+
+	// A model in error raises an alert that fires immediately. The
+	// time the model went into error is taken from the stored status,
+	// so it is the same whichever replica evaluates the rule.
+	model := env.Model("alice@canonical.com", "model-1").DBObject(c, j.Database)
+	model.SummaryStatus = "error"
+	model.SummaryStatusSince = sql.NullTime{Time: now.Add(time.Minute), Valid: true}
+	model.SummaryStatusMessage = "unit-app-0: hook failed"
+	err = j.Database.UpdateModelSummaryStatus(ctx, &model)
+	c.Assert(err, qt.IsNil)
+	other := jimm.Alerter{
+		JIMM:    &jimm.JIMM{Parameters: jimm.Parameters{Database: j.Database}},
+		Replica: "jimm-1",
+	}
+	err = other.Evaluate(ctx, now.Add(7*time.Minute))
+	c.Assert(err, qt.IsNil)
+	notifications, _, _ = receiver.received()
+	c.Assert(notifications, qt.HasLen, 2)
+	c.Assert(notifications[1].Alerts, qt.HasLen, 1)
+	c.Check(notifications[1].Alerts[0].Rule, qt.Equals, "models")
+	c.Check(notifications[1].Alerts[0].Subject, qt.Equals, "00000001-0000-0000-0000-0000-000000000001")
+	c.Check(notifications[1].Alerts[0].Summary, qt.Equals, "model model-1 is in error: unit-app-0: hook failed")
+	c.Check(notifications[1].Alerts[0].Labels["region"], qt.Equals, "test-region-1")
+	c.Check(notifications[1].Alerts[0].ActiveAt.Equal(now.Add(time.Minute)), qt.IsTrue)
+
+	// Silenced alerts are not notified.
+	_, err = j.AddAlertSilence(ctx, admin, apiparams.AddAlertSilenceRequest{Duration: "1h"})
+	c.Check(err, qt.ErrorMatches, `rule or subject must be specified`)
+	silence, err := j.AddAlertSilence(ctx, admin, apiparams.AddAlertSilenceRequest{Rule: "models", Duration: "1h", Comment: "known"})
+	c.Assert(err, qt.IsNil)
+	c.Check(silence.CreatedBy, qt.Equals, "alice@canonical.com")
+	alerts, err = j.ListAlerts(ctx, admin)
+	c.Assert(err, qt.IsNil)
+	c.Assert(alerts, qt.HasLen, 2)
+	c.Check(alerts[0].Silenced, qt.IsFalse)
+	c.Check(alerts[1].Silenced, qt.IsTrue)
+
+	// A model that is no longer in error resolves its alert, which is
+	// silenced so no notification is sent.
+	model.SummaryStatus = "available"
+	model.SummaryStatusSince = sql.NullTime{Time: now.Add(8 * time.Minute), Valid: true}
+	model.SummaryStatusMessage = ""
+	err = j.Database.UpdateModelSummaryStatus(ctx, &model)
+	c.Assert(err, qt.IsNil)
+
+	// Once the controller is available again the resolved alert is
+	// notified and removed.
+	ctl.UnavailableSince = sql.NullTime{}
+	err = j.Database.UpdateController(ctx, &ctl)
+	c.Assert(err, qt.IsNil)
+	err = alerter.Evaluate(ctx, time.Now())
+	c.Assert(err, qt.IsNil)
+	notifications, _, errs = receiver.received()
+	c.Check(errs, qt.HasLen, 0)
+	c.Assert(notifications, qt.HasLen, 3)
+	c.Check(notifications[2].Status, qt.Equals, apiparams.AlertStateResolved)
+	c.Assert(notifications[2].Alerts, qt.HasLen, 1)
+	c.Check(notifications[2].Alerts[0].Rule, qt.Equals, "controllers")
+	c.Check(notifications[2].Alerts[0].ResolvedAt, qt.Not(qt.IsNil))
+	alerts, err = j.ListAlerts(ctx, admin)
+	c.Assert(err, qt.IsNil)
+	c.Check(alerts, qt.HasLen, 0)
+
+	silences, err := j.ListAlertSilences(ctx, admin)
+	c.Assert(err, qt.IsNil)
+	c.Assert(silences, qt.HasLen, 1)
+	err = j.RemoveAlertSilence(ctx, admin, silences[0].ID)
+	c.Assert(err, qt.IsNil)
+
+	err = j.RemoveAlertRule(ctx, alice, "controllers")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+	err = j.RemoveAlertRule(ctx, admin, "controllers")
+	c.Assert(err, qt.IsNil)
+	err = j.RemoveAlertReceiver(ctx, admin, "am")
+	c.Assert(err, qt.IsNil)
+}
+
+func TestAlertingAuditWriteFailure(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	receiver := new(alertReceiver)
+	srv := httptest.NewServer(receiver)
+	defer srv.Close()
+
+	j := jimmtest.NewJIMM(c, nil)
+
+	err := j.Database.AddAlertReceiver(ctx, &dbmodel.AlertReceiver{Name: "ops-hook", Type: dbmodel.AlertReceiverWebhook, URL: srv.URL, Secret: "secret"})
+	c.Assert(err, qt.IsNil)
+	err = j.Database.AddAlertRule(ctx, &dbmodel.AlertRule{
+		Name:           "audit",
+		Kind:           dbmodel.AlertKindAuditWriteFailure,
+		RepeatInterval: time.Hour,
+		Severity:       "critical",
+		Receivers:      dbmodel.Strings{"ops-hook"},
+	})
+	c.Assert(err, qt.IsNil)
+
+	now := time.Now()
+	j.RecordAuditWriteFailure(now, errors.E("connection refused"))
+
+	// Replicas that are not the leader alert on their own audit write
+	// failures.
+	follower := jimm.Alerter{JIMM: j, Replica: "jimm-0", IsLeader: func() bool { return false }}
+	err = follower.Evaluate(ctx, now)
+	c.Assert(err, qt.IsNil)
+	notifications, _, errs := receiver.received()
+	c.Check(errs, qt.HasLen, 0)
+	c.Assert(notifications, qt.HasLen, 1)
+	c.Check(notifications[0].Alerts[0].Subject, qt.Equals, "jimm-0")
+	c.Check(notifications[0].Alerts[0].Summary, qt.Equals, "replica jimm-0 cannot write to the audit log: connection refused")
+
+	// Another replica leaves the alert alone, even if it is the leader,
+	// while the replica is still updating it.
+	other := jimm.Alerter{JIMM: &jimm.JIMM{Parameters: jimm.Parameters{Database: j.Database}}, Replica: "jimm-1"}
+	err = other.Evaluate(ctx, now.Add(time.Minute))
+	c.Assert(err, qt.IsNil)
+	alerts, err := j.Database.ListAlerts(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Check(alerts, qt.HasLen, 1)
+
+	// Once there have been no failures for a while the alert resolves.
+	err = follower.Evaluate(ctx, now.Add(10*time.Minute))
+	c.Assert(err, qt.IsNil)
+	notifications, _, _ = receiver.received()
+	c.Assert(notifications, qt.HasLen, 2)
+	c.Check(notifications[1].Status, qt.Equals, apiparams.AlertStateResolved)
+	alerts, err = j.Database.ListAlerts(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Check(alerts, qt.HasLen, 0)
+
+	// The alert of a replica that goes away while failing is resolved
+	// by the leader once the replica has stopped updating it.
+	now = time.Now()
+	j.RecordAuditWriteFailure(now, errors.E("connection refused"))
+	err = follower.Evaluate(ctx, now)
+	c.Assert(err, qt.IsNil)
+	notifications, _, _ = receiver.received()
+	c.Assert(notifications, qt.HasLen, 3)
+	nonLeader := jimm.Alerter{JIMM: &jimm.JIMM{Parameters: jimm.Parameters{Database: j.Database}}, Replica: "jimm-2", IsLeader: func() bool { return false }}
+	err = nonLeader.Evaluate(ctx, now.Add(10*time.Minute))
+	c.Assert(err, qt.IsNil)
+	alerts, err = j.Database.ListAlerts(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Check(alerts, qt.HasLen, 1)
+	err = other.Evaluate(ctx, now.Add(10*time.Minute))
+	c.Assert(err, qt.IsNil)
+	notifications, _, _ = receiver.received()
+	c.Assert(notifications, qt.HasLen, 4)
+	c.Check(notifications[3].Status, qt.Equals, apiparams.AlertStateResolved)
+	c.Check(notifications[3].Alerts[0].Subject, qt.Equals, "jimm-0")
+	alerts, err = j.Database.ListAlerts(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Check(alerts, qt.HasLen, 0)
+}
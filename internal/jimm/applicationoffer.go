@@ -24,6 +24,7 @@ import (
 	"github.com/canonical/jimm/v3/internal/errors"
 	"github.com/canonical/jimm/v3/internal/openfga"
 	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
 )
 
 // AddApplicationOfferParams holds parameters for the Offer method.
@@ -81,6 +82,9 @@ func (j *JIMM) Offer(ctx context.Context, user *openfga.User, offer AddApplicati
 		return errors.E(op, err)
 	}
 
+	if err := ControllerMaintenanceError(&model.Controller); err != nil {
+		return errors.E(op, err)
+	}
 	api, err := j.dial(ctx, &model.Controller, names.ModelTag{})
 	if err != nil {
 		return errors.E(op, err)
@@ -399,7 +403,7 @@ func (j *JIMM) GrantOfferAccess(ctx context.Context, user *openfga.User, offerUR
 		return errors.E(op, err)
 	}
 
-	err = j.doApplicationOfferAdmin(ctx, user, offerURL, func(offer *dbmodel.ApplicationOffer, api API) error {
+	err = j.doApplicationOfferAdmin(ctx, user, offerURL, false, func(offer *dbmodel.ApplicationOffer, api API) error {
 		tUser := openfga.NewUser(identity, j.OpenFGAClient)
 		currentRelation := tUser.GetApplicationOfferAccess(ctx, offer.ResourceTag())
 		currentAccessLevel := ToOfferAccessString(currentRelation)
@@ -452,16 +456,19 @@ func determineAccessLevelAfterGrant(currentAccessLevel, grantAccessLevel string)
 	}
 }
 
-// RevokeOfferAccess revokes rights for an application offer.
-func (j *JIMM) RevokeOfferAccess(ctx context.Context, user *openfga.User, offerURL string, ut names.UserTag, access jujuparams.OfferAccessPermission) (err error) {
+// RevokeOfferAccess revokes rights for an application offer. The
+// consumers with relations that relied on the revoked access, and so will
+// stop working, are returned.
+func (j *JIMM) RevokeOfferAccess(ctx context.Context, user *openfga.User, offerURL string, ut names.UserTag, access jujuparams.OfferAccessPermission) (_ []apiparams.OfferConsumer, err error) {
 	const op = errors.Op("jimm.RevokeOfferAccess")
 
 	identity, err := dbmodel.NewIdentity(ut.Id())
 	if err != nil {
-		return errors.E(op, err)
+		return nil, errors.E(op, err)
 	}
 
-	err = j.doApplicationOfferAdmin(ctx, user, offerURL, func(offer *dbmodel.ApplicationOffer, api API) error {
+	var broken []apiparams.OfferConsumer
+	err = j.doApplicationOfferAdmin(ctx, user, offerURL, false, func(offer *dbmodel.ApplicationOffer, api API) error {
 		tUser := openfga.NewUser(identity, j.OpenFGAClient)
 		targetRelation, err := ToOfferRelation(string(access))
 		if err != nil {
@@ -497,20 +504,27 @@ func (j *JIMM) RevokeOfferAccess(ctx context.Context, user *openfga.User, offerU
 		if stillHasAccess {
 			return errors.E(op, "unable to completely revoke given access due to other relations; try to remove them as well, or use 'jimmctl' for more control")
 		}
+		// Relations established using the revoked access will stop
+		// working once the user is no longer a consumer of the offer.
+		switch currentRelation {
+		case ofganames.AdministratorRelation, ofganames.ConsumerRelation:
+		default:
+			broken = j.brokenOfferConsumers(ctx, offer, api, identity.Name)
+		}
 		return nil
 	})
 
 	if err != nil {
-		return errors.E(op, err)
+		return nil, errors.E(op, err)
 	}
-	return nil
+	return broken, nil
 }
 
 // DestroyOffer removes the application offer.
 func (j *JIMM) DestroyOffer(ctx context.Context, user *openfga.User, offerURL string, force bool) error {
 	const op = errors.Op("jimm.DestroyOffer")
 
-	err := j.doApplicationOfferAdmin(ctx, user, offerURL, func(offer *dbmodel.ApplicationOffer, api API) error {
+	err := j.doApplicationOfferAdmin(ctx, user, offerURL, true, func(offer *dbmodel.ApplicationOffer, api API) error {
 		if err := api.DestroyApplicationOffer(ctx, offerURL, force); err != nil {
 			return err
 		}
@@ -684,7 +698,7 @@ func (j *JIMM) queryControllersForOffers(ctx context.Context, user *openfga.User
 // Note: The user does not need to have any access level on the offer itself.
 // As long as they are model admins or controller superusers they can also
 // manipulate the application offer as admins.
-func (j *JIMM) doApplicationOfferAdmin(ctx context.Context, user *openfga.User, offerURL string, f func(offer *dbmodel.ApplicationOffer, api API) error) error {
+func (j *JIMM) doApplicationOfferAdmin(ctx context.Context, user *openfga.User, offerURL string, refuseMaintenance bool, f func(offer *dbmodel.ApplicationOffer, api API) error) error {
 	const op = errors.Op("jimm.doApplicationOfferAdmin")
 
 	offer := dbmodel.ApplicationOffer{
@@ -701,6 +715,11 @@ func (j *JIMM) doApplicationOfferAdmin(ctx context.Context, user *openfga.User,
 	if !isOfferAdmin {
 		return errors.E(op, errors.CodeUnauthorized, "unauthorized")
 	}
+	if refuseMaintenance {
+		if err := ControllerMaintenanceError(&offer.Model.Controller); err != nil {
+			return errors.E(op, err)
+		}
+	}
 	// add offer admin claim
 	api, err := j.dial(
 		ctx,
@@ -27,6 +27,7 @@ import (
 	"github.com/canonical/jimm/v3/internal/openfga"
 	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
 	"github.com/canonical/jimm/v3/internal/testutils/jimmtest"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
 )
 
 type environment struct {
@@ -352,7 +353,7 @@ func TestRevokeOfferAccess(t *testing.T) {
 				c.Assert(jimm.ToOfferAccessString(appliedRelation), qt.Equals, expectedAppliedRelation)
 			}
 
-			err := j.RevokeOfferAccess(ctx, openfga.NewUser(&authenticatedUser, j.OpenFGAClient), offerURL, offerUser.ResourceTag(), revokeAccessLevel)
+			_, err := j.RevokeOfferAccess(ctx, openfga.NewUser(&authenticatedUser, j.OpenFGAClient), offerURL, offerUser.ResourceTag(), revokeAccessLevel)
 			if test.expectedError == "" {
 				c.Assert(err, qt.IsNil)
 				assertAppliedRelation(test.expectedAccessLevel)
@@ -2454,3 +2455,139 @@ func TestListApplicationOffers(t *testing.T) {
 		}},
 	}})
 }
+
+func TestListApplicationOfferConsumers(t *testing.T) {
+	c := qt.New(t)
+
+	ctx := context.Background()
+
+	consumerModelUUID := "00000000-0000-0000-0000-0000-0000000000005"
+	j := jimmtest.NewJIMM(c, &jimm.Parameters{
+		Dialer: &jimmtest.Dialer{
+			API: &jimmtest.API{
+				GetApplicationOffer_: func(_ context.Context, details *jujuparams.ApplicationOfferAdminDetailsV5) error {
+					details.Connections = []jujuparams.OfferConnection{{
+						SourceModelTag: names.NewModelTag(consumerModelUUID).String(),
+						RelationId:     7,
+						Username:       "eve@canonical.com",
+						Endpoint:       "db",
+						Status: jujuparams.EntityStatus{
+							Status: "joined",
+						},
+					}}
+					return nil
+				},
+			},
+		},
+	})
+
+	u, err := dbmodel.NewIdentity("alice@canonical.com")
+	c.Assert(err, qt.IsNil)
+	c.Assert(j.Database.DB.Create(&u).Error, qt.IsNil)
+
+	u1, err := dbmodel.NewIdentity("eve@canonical.com")
+	c.Assert(err, qt.IsNil)
+	c.Assert(j.Database.DB.Create(&u1).Error, qt.IsNil)
+
+	cloud := dbmodel.Cloud{
+		Name: "test-cloud",
+		Type: "test-provider",
+		Regions: []dbmodel.CloudRegion{{
+			Name: "test-region-1",
+		}},
+	}
+	c.Assert(j.Database.DB.Create(&cloud).Error, qt.IsNil)
+
+	controller := dbmodel.Controller{
+		Name:        "test-controller-1",
+		UUID:        "00000000-0000-0000-0000-0000-0000000000001",
+		CloudName:   "test-cloud",
+		CloudRegion: "test-region-1",
+	}
+	err = j.Database.AddController(ctx, &controller)
+	c.Assert(err, qt.IsNil)
+
+	cred := dbmodel.CloudCredential{
+		Name:              "test-credential-1",
+		CloudName:         cloud.Name,
+		OwnerIdentityName: u.Name,
+		AuthType:          "empty",
+	}
+	err = j.Database.SetCloudCredential(ctx, &cred)
+	c.Assert(err, qt.IsNil)
+
+	for _, m := range []dbmodel.Model{{
+		Name:              "test-model",
+		UUID:              sql.NullString{String: "00000000-0000-0000-0000-0000-0000000000003", Valid: true},
+		OwnerIdentityName: u.Name,
+		ControllerID:      controller.ID,
+		CloudRegionID:     cloud.Regions[0].ID,
+		CloudCredentialID: cred.ID,
+	}, {
+		Name:              "consumer-model",
+		UUID:              sql.NullString{String: consumerModelUUID, Valid: true},
+		OwnerIdentityName: u.Name,
+		ControllerID:      controller.ID,
+		CloudRegionID:     cloud.Regions[0].ID,
+		CloudCredentialID: cred.ID,
+	}} {
+		err = j.Database.AddModel(ctx, &m)
+		c.Assert(err, qt.IsNil)
+	}
+
+	offer := dbmodel.ApplicationOffer{
+		ModelID: 1,
+		Name:    "test-application-offer",
+		UUID:    "00000000-0000-0000-0000-0000-0000000000004",
+		URL:     "alice@canonical.com/test-model.test-application-offer",
+	}
+	err = j.Database.AddApplicationOffer(ctx, &offer)
+	c.Assert(err, qt.IsNil)
+
+	err = openfga.NewUser(u, j.OpenFGAClient).SetApplicationOfferAccess(ctx, offer.ResourceTag(), ofganames.AdministratorRelation)
+	c.Assert(err, qt.IsNil)
+	err = openfga.NewUser(u1, j.OpenFGAClient).SetApplicationOfferAccess(ctx, offer.ResourceTag(), ofganames.ConsumerRelation)
+	c.Assert(err, qt.IsNil)
+
+	err = j.RecordApplicationOfferConsumed(ctx, u1.Name, consumerModelUUID, offer.UUID, "")
+	c.Assert(err, qt.IsNil)
+	err = j.RecordApplicationOfferConsumed(ctx, u.Name, consumerModelUUID, "", offer.URL)
+	c.Assert(err, qt.IsNil)
+	err = j.RecordApplicationOfferConsumed(ctx, u.Name, consumerModelUUID, "no-such-offer", "")
+	c.Assert(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	_, err = j.ListApplicationOfferConsumers(ctx, openfga.NewUser(u1, j.OpenFGAClient), offer.URL, "")
+	c.Assert(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+
+	consumers, err := j.ListApplicationOfferConsumers(ctx, openfga.NewUser(u, j.OpenFGAClient), offer.URL, "")
+	c.Assert(err, qt.IsNil)
+	c.Assert(consumers, qt.HasLen, 2)
+	c.Check(consumers[0].Identity, qt.Equals, u.Name)
+	c.Check(consumers[0].ModelUUID, qt.Equals, consumerModelUUID)
+	c.Check(consumers[0].ModelName, qt.Equals, "alice@canonical.com/consumer-model")
+	c.Check(consumers[0].LastConsumed, qt.Not(qt.IsNil))
+	c.Check(consumers[0].Relations, qt.HasLen, 0)
+	c.Check(consumers[1].Identity, qt.Equals, u1.Name)
+	c.Check(consumers[1].LastConsumed, qt.Not(qt.IsNil))
+	c.Check(consumers[1].Relations, qt.DeepEquals, []apiparams.OfferConsumerRelation{{
+		RelationID: 7,
+		Endpoint:   "db",
+		Status:     "joined",
+	}})
+
+	consumers, err = j.ListApplicationOfferConsumers(ctx, openfga.NewUser(u, j.OpenFGAClient), offer.URL, u.Name)
+	c.Assert(err, qt.IsNil)
+	c.Check(consumers, qt.HasLen, 0)
+
+	consumers, err = j.ListApplicationOfferConsumers(ctx, openfga.NewUser(u, j.OpenFGAClient), offer.URL, u1.Name)
+	c.Assert(err, qt.IsNil)
+	c.Assert(consumers, qt.HasLen, 1)
+	c.Check(consumers[0].Identity, qt.Equals, u1.Name)
+
+	// Revoking access returns the relations that will break.
+	broken, err := j.RevokeOfferAccess(ctx, openfga.NewUser(u, j.OpenFGAClient), offer.URL, u1.ResourceTag(), jujuparams.OfferConsumeAccess)
+	c.Assert(err, qt.IsNil)
+	c.Assert(broken, qt.HasLen, 1)
+	c.Check(broken[0].Identity, qt.Equals, u1.Name)
+	c.Check(broken[0].Relations, qt.HasLen, 1)
+}
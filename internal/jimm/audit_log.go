@@ -15,6 +15,7 @@ import (
 
 	"github.com/canonical/jimm/v3/internal/db"
 	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
 	"github.com/canonical/jimm/v3/internal/servermon"
 	"github.com/canonical/jimm/v3/internal/utils"
 )
@@ -133,17 +134,6 @@ type auditLogCleanupService struct {
 	db                            *db.Database
 }
 
-// pollTimeOfDay holds the time hour, minutes and seconds to poll at.
-type pollTimeOfDay struct {
-	Hours   int
-	Minutes int
-	Seconds int
-}
-
-var pollDuration = pollTimeOfDay{
-	Hours: 9,
-}
-
 // NewAuditLogCleanupService returns a service capable of cleaning up audit logs
 // on a defined retention period. The retention period is in DAYS.
 func NewAuditLogCleanupService(db *db.Database, auditLogRetentionPeriodInDays int) *auditLogCleanupService {
@@ -153,49 +143,18 @@ func NewAuditLogCleanupService(db *db.Database, auditLogRetentionPeriodInDays in
 	}
 }
 
-// Start starts a routine which checks daily for any logs
-// needed to be cleaned up.
-func (a *auditLogCleanupService) Start(ctx context.Context) {
-	go a.poll(ctx)
-}
+// Cleanup deletes the audit logs older than the retention period.
+func (a *auditLogCleanupService) Cleanup(ctx context.Context) error {
+	const op = errors.Op("jimm.auditLogCleanupService.Cleanup")
 
-// poll is designed to be run in a routine where it can be cancelled safely
-// from the service's context. It calculates the poll duration at 9am each day
-// UTC.
-func (a *auditLogCleanupService) poll(ctx context.Context) {
-
-	for {
-		select {
-		case <-time.After(calculateNextPollDuration(time.Now().UTC())):
-			retentionDate := time.Now().AddDate(0, 0, -(a.auditLogRetentionPeriodInDays))
-			deleted, err := a.db.DeleteAuditLogsBefore(ctx, retentionDate)
-			if err != nil {
-				zapctx.Error(ctx, "failed to cleanup audit logs", zap.Error(err))
-				continue
-			}
-			zapctx.Debug(ctx, "audit log cleanup run successfully", zap.Int64("count", deleted))
-		case <-ctx.Done():
-			zapctx.Debug(ctx, "exiting audit log cleanup polling")
-			return
-		}
+	if err := checkLeader(ctx); err != nil {
+		return errors.E(op, err)
 	}
-}
-
-// calculateNextPollDuration returns the next duration to poll on.
-// We recalculate each time and not rely on running every 24 hours
-// for absolute consistency within ns apart.
-func calculateNextPollDuration(startingTime time.Time) time.Duration {
-	now := startingTime
-	nineAM := time.Date(now.Year(), now.Month(), now.Day(), pollDuration.Hours, 0, 0, 0, time.UTC)
-	nineAMDuration := nineAM.Sub(now)
-	var d time.Duration
-	// If 9am is behind the current time, i.e., 1pm
-	if nineAMDuration < 0 {
-		// Add 24 hours, flip it to an absolute duration, i.e., -10h == 10h
-		// and subtract it from 24 hours to calculate 9am tomorrow
-		d = time.Hour*24 - nineAMDuration.Abs()
-	} else {
-		d = nineAMDuration.Abs()
+	retentionDate := time.Now().AddDate(0, 0, -(a.auditLogRetentionPeriodInDays))
+	deleted, err := a.db.DeleteAuditLogsBefore(ctx, retentionDate)
+	if err != nil {
+		return errors.E(op, err)
 	}
-	return d
+	zapctx.Debug(ctx, "audit log cleanup run successfully", zap.Int64("count", deleted))
+	return nil
 }
@@ -55,29 +55,13 @@ func TestAuditLogCleanupServicePurgesLogs(t *testing.T) {
 	c.Assert(err, qt.IsNil)
 	c.Assert(logs, qt.HasLen, 3)
 
-	jimm.PollDuration.Hours = now.Hour()
-	jimm.PollDuration.Minutes = now.Minute()
-	jimm.PollDuration.Seconds = now.Second() + 2
-	svc := jimm.NewAuditLogCleanupService(db, 1)
-	svc.Start(ctx)
+	svc := jimm.NewAuditLogCleanupService(db, 2)
+	err = svc.Cleanup(ctx)
+	c.Assert(err, qt.IsNil)
 
 	// Check 2 were purged
 	logs = make([]dbmodel.AuditLogEntry, 0)
 	err = db.DB.Find(&logs).Error
 	c.Assert(err, qt.IsNil)
-	c.Assert(logs, qt.HasLen, 3)
-}
-
-func TestCalculateNextPollDuration(t *testing.T) {
-	c := qt.New(t)
-
-	// Test where 9am is behind 12pm
-	startingTime := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
-	d := jimm.CalculateNextPollDuration(startingTime)
-	c.Assert(d, qt.Equals, time.Hour*21)
-
-	// Test where 9am is ahead of 7pm
-	startingTime = time.Date(2023, 1, 1, 7, 0, 0, 0, time.UTC)
-	d = jimm.CalculateNextPollDuration(startingTime)
-	c.Assert(d, qt.Equals, time.Hour*2)
+	c.Assert(logs, qt.HasLen, 1)
 }
@@ -0,0 +1,150 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"sort"
+	"strconv"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// CharmDriftReport reports, for each charm deployed in the models the
+// user can read, the distribution of revisions, channels, bases and
+// workload versions in use, along with the applications that are behind
+// the newest revision in use. The report is built from the stored
+// estate inventory.
+func (j *JIMM) CharmDriftReport(ctx context.Context, user *openfga.User, req apiparams.CharmDriftReportRequest) (apiparams.CharmDriftReport, error) {
+	const op = errors.Op("jimm.CharmDriftReport")
+
+	q := db.InventoryQuery{
+		Charm:      req.Charm,
+		Controller: req.Controller,
+	}
+	if !user.JimmAdmin {
+		uuids, err := user.ListModels(ctx, ofganames.ReaderRelation)
+		if err != nil {
+			return apiparams.CharmDriftReport{}, errors.E(op, err, errors.CodeOpenFGARequestFailed)
+		}
+		q.ModelUUIDs = append([]string{}, uuids...)
+	}
+	apps, err := j.Database.FindInventoryApplications(ctx, q)
+	if err != nil {
+		return apiparams.CharmDriftReport{}, errors.E(op, err)
+	}
+	return newCharmDriftReport(apps), nil
+}
+
+// newCharmDriftReport builds a charm drift report from the given
+// inventory applications.
+func newCharmDriftReport(apps []db.InventoryApplicationResult) apiparams.CharmDriftReport {
+	byCharm := make(map[string][]db.InventoryApplicationResult)
+	for _, a := range apps {
+		byCharm[a.Charm] = append(byCharm[a.Charm], a)
+	}
+	report := apiparams.CharmDriftReport{
+		Charms: make([]apiparams.CharmDrift, 0, len(byCharm)),
+	}
+	for _, charm := range sortedMapKeys(byCharm) {
+		apps := byCharm[charm]
+		drift := apiparams.CharmDrift{
+			Charm:          charm,
+			Applications:   len(apps),
+			LatestRevision: -1,
+		}
+		models := make(map[string]bool)
+		revisions := make(map[int]int)
+		channels := make(map[string]int)
+		bases := make(map[string]int)
+		workloadVersions := make(map[string]int)
+		for _, a := range apps {
+			models[a.ModelUUID] = true
+			revisions[a.CharmRevision]++
+			if a.CharmRevision > drift.LatestRevision {
+				drift.LatestRevision = a.CharmRevision
+			}
+			if a.CharmChannel != "" {
+				channels[a.CharmChannel]++
+			}
+			if a.Base != "" {
+				bases[a.Base]++
+			}
+			if a.WorkloadVersion != "" {
+				workloadVersions[a.WorkloadVersion]++
+			}
+		}
+		drift.Models = len(models)
+
+		revs := make([]int, 0, len(revisions))
+		for r := range revisions {
+			revs = append(revs, r)
+		}
+		sort.Sort(sort.Reverse(sort.IntSlice(revs)))
+		for _, r := range revs {
+			drift.Revisions = append(drift.Revisions, apiparams.DistributionEntry{
+				Value: strconv.Itoa(r),
+				Count: revisions[r],
+			})
+		}
+		drift.Channels = distribution(channels)
+		drift.Bases = distribution(bases)
+		drift.WorkloadVersions = distribution(workloadVersions)
+
+		// Revisions are only comparable within the same channel and
+		// base, as a channel may track an older release of the charm
+		// and revisions are built separately for each base.
+		type track struct {
+			channel string
+			base    string
+		}
+		latest := make(map[track]int)
+		for _, a := range apps {
+			t := track{a.CharmChannel, a.Base}
+			if r, ok := latest[t]; !ok || a.CharmRevision > r {
+				latest[t] = a.CharmRevision
+			}
+		}
+		for _, a := range apps {
+			// Charms without a revision, such as local charms, cannot
+			// be compared.
+			latestRevision := latest[track{a.CharmChannel, a.Base}]
+			if a.CharmRevision < 0 || a.CharmRevision >= latestRevision {
+				continue
+			}
+			drift.Behind = append(drift.Behind, apiparams.DriftedApplication{
+				ModelUUID:      a.ModelUUID,
+				ModelName:      a.ModelName,
+				Owner:          a.OwnerIdentityName,
+				Controller:     a.ControllerName,
+				Application:    a.Name,
+				Revision:       a.CharmRevision,
+				LatestRevision: latestRevision,
+				Channel:        a.CharmChannel,
+				Base:           a.Base,
+			})
+		}
+		report.Charms = append(report.Charms, drift)
+	}
+	return report
+}
+
+// distribution converts the given counts to distribution entries ordered
+// by decreasing count, and then by value.
+func distribution(counts map[string]int) []apiparams.DistributionEntry {
+	var entries []apiparams.DistributionEntry
+	for v, n := range counts {
+		entries = append(entries, apiparams.DistributionEntry{Value: v, Count: n})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Count != entries[j].Count {
+			return entries[i].Count > entries[j].Count
+		}
+		return entries[i].Value < entries[j].Value
+	})
+	return entries
+}
@@ -0,0 +1,178 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	"github.com/canonical/jimm/v3/internal/testutils/jimmtest"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+func charmDriftTestApp(model, name, charm string, revision int, channel, base, workloadVersion string) db.InventoryApplicationResult {
+	return db.InventoryApplicationResult{
+		InventoryApplication: dbmodel.InventoryApplication{
+			Name:            name,
+			Charm:           charm,
+			CharmRevision:   revision,
+			CharmChannel:    channel,
+			Base:            base,
+			WorkloadVersion: workloadVersion,
+		},
+		ModelUUID:         model,
+		ModelName:         model,
+		OwnerIdentityName: "alice@canonical.com",
+		ControllerName:    "controller-1",
+	}
+}
+
+func TestNewCharmDriftReport(t *testing.T) {
+	c := qt.New(t)
+
+	report := jimm.NewCharmDriftReport([]db.InventoryApplicationResult{
+		charmDriftTestApp("m1", "db", "postgresql", 300, "14/stable", "ubuntu@22.04", "14.9"),
+		charmDriftTestApp("m1", "db-replica", "postgresql", 300, "14/stable", "ubuntu@22.04", "14.9"),
+		charmDriftTestApp("m2", "db", "postgresql", 280, "14/candidate", "ubuntu@20.04", "14.8"),
+		charmDriftTestApp("m2", "db-old", "postgresql", 270, "14/candidate", "ubuntu@20.04", "14.8"),
+		charmDriftTestApp("m3", "db", "postgresql", 250, "14/stable", "ubuntu@20.04", "14.8"),
+		charmDriftTestApp("m2", "app", "my-app", -1, "", "ubuntu@22.04", ""),
+	})
+	c.Check(report, qt.DeepEquals, apiparams.CharmDriftReport{
+		Charms: []apiparams.CharmDrift{{
+			Charm:          "my-app",
+			Applications:   1,
+			Models:         1,
+			LatestRevision: -1,
+			Revisions:      []apiparams.DistributionEntry{{Value: "-1", Count: 1}},
+			Bases:          []apiparams.DistributionEntry{{Value: "ubuntu@22.04", Count: 1}},
+		}, {
+			Charm:          "postgresql",
+			Applications:   5,
+			Models:         3,
+			LatestRevision: 300,
+			Revisions: []apiparams.DistributionEntry{
+				{Value: "300", Count: 2},
+				{Value: "280", Count: 1},
+				{Value: "270", Count: 1},
+				{Value: "250", Count: 1},
+			},
+			Channels: []apiparams.DistributionEntry{{Value: "14/stable", Count: 3}, {Value: "14/candidate", Count: 2}},
+			Bases:    []apiparams.DistributionEntry{{Value: "ubuntu@20.04", Count: 3}, {Value: "ubuntu@22.04", Count: 2}},
+			WorkloadVersions: []apiparams.DistributionEntry{
+				{Value: "14.8", Count: 3},
+				{Value: "14.9", Count: 2},
+			},
+			// Only applications behind others with the same channel
+			// and base are reported.
+			Behind: []apiparams.DriftedApplication{{
+				ModelUUID:      "m2",
+				ModelName:      "m2",
+				Owner:          "alice@canonical.com",
+				Controller:     "controller-1",
+				Application:    "db-old",
+				Revision:       270,
+				LatestRevision: 280,
+				Channel:        "14/candidate",
+				Base:           "ubuntu@20.04",
+			}},
+		}},
+	})
+
+	c.Check(jimm.NewCharmDriftReport(nil), qt.DeepEquals, apiparams.CharmDriftReport{Charms: []apiparams.CharmDrift{}})
+}
+
+const charmDriftTestEnv = `clouds:
+- name: test-cloud
+  type: test-provider
+  regions:
+  - name: test-region-1
+cloud-credentials:
+- name: test-credential-1
+  owner: alice@canonical.com
+  cloud: test-cloud
+  auth-type: empty
+controllers:
+- name: controller-1
+  uuid: 00000000-0000-0000-0000-0000-0000000000001
+  cloud: test-cloud
+  region: test-region-1
+models:
+- name: model-1
+  owner: alice@canonical.com
+  uuid: 00000001-0000-0000-0000-0000-000000000001
+  cloud: test-cloud
+  region: test-region-1
+  cloud-credential: test-credential-1
+  controller: controller-1
+  users:
+  - user: alice@canonical.com
+    access: admin
+- name: model-2
+  owner: alice@canonical.com
+  uuid: 00000001-0000-0000-0000-0000-000000000002
+  cloud: test-cloud
+  region: test-region-1
+  cloud-credential: test-credential-1
+  controller: controller-1
+  users:
+  - user: alice@canonical.com
+    access: admin
+  - user: bob@canonical.com
+    access: read
+users:
+- username: alice@canonical.com
+  controller-access: login
+- username: bob@canonical.com
+  controller-access: login
+`
+
+func TestCharmDriftReport(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	j := jimmtest.NewJIMM(c, nil)
+	env := jimmtest.ParseEnvironment(c, charmDriftTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, j.OpenFGAClient)
+
+	for i, rev := range []int{300, 280} {
+		m := env.Model("alice@canonical.com", []string{"model-1", "model-2"}[i]).DBObject(c, j.Database)
+		err := j.Database.SetModelInventory(ctx, &dbmodel.ModelInventory{
+			ModelID: m.ID,
+			Applications: []dbmodel.InventoryApplication{{
+				Name:          "db",
+				Charm:         "postgresql",
+				CharmRevision: rev,
+			}},
+		})
+		c.Assert(err, qt.IsNil)
+	}
+
+	aliceIdentity := env.User("alice@canonical.com").DBObject(c, j.Database)
+	alice := openfga.NewUser(&aliceIdentity, j.OpenFGAClient)
+	report, err := j.CharmDriftReport(ctx, alice, apiparams.CharmDriftReportRequest{Charm: "postgresql"})
+	c.Assert(err, qt.IsNil)
+	c.Assert(report.Charms, qt.HasLen, 1)
+	c.Check(report.Charms[0].LatestRevision, qt.Equals, 300)
+	c.Assert(report.Charms[0].Behind, qt.HasLen, 1)
+	c.Check(report.Charms[0].Behind[0].ModelName, qt.Equals, "model-2")
+
+	// Only the models the user can read are included.
+	bobIdentity := env.User("bob@canonical.com").DBObject(c, j.Database)
+	bob := openfga.NewUser(&bobIdentity, j.OpenFGAClient)
+	report, err = j.CharmDriftReport(ctx, bob, apiparams.CharmDriftReportRequest{})
+	c.Assert(err, qt.IsNil)
+	c.Assert(report.Charms, qt.HasLen, 1)
+	c.Check(report.Charms[0].LatestRevision, qt.Equals, 280)
+	c.Check(report.Charms[0].Behind, qt.HasLen, 0)
+
+	report, err = j.CharmDriftReport(ctx, alice, apiparams.CharmDriftReportRequest{Controller: "no-such-controller"})
+	c.Assert(err, qt.IsNil)
+	c.Check(report.Charms, qt.HasLen, 0)
+}
@@ -173,6 +173,11 @@ func (j *JIMM) UpdateCloudCredential(ctx context.Context, user *openfga.User, ar
 			continue
 		}
 		seen[model.ControllerID] = true
+		// Refuse the update rather than leave the credential out of
+		// date on a controller in maintenance.
+		if err := ControllerMaintenanceError(&model.Controller); err != nil {
+			return result, errors.E(op, err)
+		}
 		controllers = append(controllers, model.Controller)
 	}
 
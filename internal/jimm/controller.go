@@ -560,9 +560,14 @@ func (m *modelImporter) setModelCloud(ctx context.Context) error {
 	return nil
 }
 
-func (m *modelImporter) save(ctx context.Context) error {
+// save adds the imported model, and its offers, to the database if doing
+// so would not exceed any of the given model quota limits.
+func (m *modelImporter) save(ctx context.Context, quotaLimits []modelQuotaLimit) error {
 	return m.jimm.Database.Transaction(func(d *db.Database) error {
-		err := m.jimm.Database.AddModel(ctx, &m.model)
+		if err := m.jimm.checkModelQuotaLimits(ctx, d, quotaLimits); err != nil {
+			return err
+		}
+		err := d.AddModel(ctx, &m.model)
 		if err != nil {
 			if errors.ErrorCode(err) == errors.CodeAlreadyExists {
 				return fmt.Errorf("model (%s) already exists", m.model.Name)
@@ -576,7 +581,7 @@ func (m *modelImporter) save(ctx context.Context) error {
 				URL:     offer.OfferURL,
 				ModelID: m.model.ID,
 			}
-			if err := m.jimm.Database.AddApplicationOffer(ctx, &dbOffer); err != nil {
+			if err := d.AddApplicationOffer(ctx, &dbOffer); err != nil {
 				if errors.ErrorCode(err) == errors.CodeAlreadyExists {
 					return fmt.Errorf("offer with URL %s already exists", offer.OfferURL)
 				}
@@ -625,7 +630,12 @@ func (j *JIMM) ImportModel(ctx context.Context, user *openfga.User, controllerNa
 		return errors.E(op, err)
 	}
 
-	if err := importer.save(ctx); err != nil {
+	quotaLimits, err := j.modelQuotaLimits(ctx, importer.model.Owner.Name, importer.model.CloudRegion.CloudName, importer.model.CloudRegion.Name)
+	if err != nil {
+		return errors.E(op, err)
+	}
+
+	if err := importer.save(ctx, quotaLimits); err != nil {
 		return errors.E(op, err)
 	}
 
@@ -680,6 +690,7 @@ func (j *JIMM) UpdateMigratedModel(ctx context.Context, user *openfga.User, mode
 		return errors.E(op, err)
 	}
 
+	sourceController := model.Controller.Name
 	model.Controller = targetController
 	model.ControllerID = targetController.ID
 	err = j.Database.UpdateModel(ctx, &model)
@@ -687,6 +698,7 @@ func (j *JIMM) UpdateMigratedModel(ctx context.Context, user *openfga.User, mode
 		zapctx.Error(ctx, "failed to update model", zap.String("model", model.UUID.String), zaputil.Error(err))
 		return errors.E(op, err)
 	}
+	j.RecordModelEvent(ctx, dbmodel.WebhookEventModelMigrated, &model, fmt.Sprintf("migrated from %s", sourceController))
 
 	return nil
 }
@@ -736,6 +748,19 @@ func (j *JIMM) InitiateMigration(ctx context.Context, user *openfga.User, spec j
 	if err != nil {
 		return result, errors.E(op, "failed to retrieve the model from the database", err)
 	}
+	if err := ControllerMaintenanceError(&model.Controller); err != nil {
+		return result, errors.E(op, err)
+	}
+	// The target controller may not be managed by JIMM, in which case
+	// its maintenance state is unknown.
+	targetController := dbmodel.Controller{UUID: targetControllerTag.Id()}
+	if err := j.Database.GetController(ctx, &targetController); err == nil {
+		if err := ControllerMaintenanceError(&targetController); err != nil {
+			return result, errors.E(op, err)
+		}
+	} else if errors.ErrorCode(err) != errors.CodeNotFound {
+		return result, errors.E(op, err)
+	}
 
 	api, err := j.dial(ctx, &model.Controller, names.ModelTag{})
 	if err != nil {
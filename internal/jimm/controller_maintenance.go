@@ -0,0 +1,67 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+)
+
+// SetControllerMaintenance sets the maintenance state of the named
+// controller. While a controller is in maintenance no new models are
+// created on it and requests to its models that might be affected by the
+// maintenance are refused with an error containing the maintenance
+// message. Setting a maintenance state that is not enabled takes the
+// controller out of maintenance.
+func (j *JIMM) SetControllerMaintenance(ctx context.Context, user *openfga.User, controllerName string, m dbmodel.ControllerMaintenance) error {
+	const op = errors.Op("jimm.SetControllerMaintenance")
+
+	if !user.JimmAdmin {
+		return errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+
+	if !m.Enabled {
+		m = dbmodel.ControllerMaintenance{}
+	} else if m.EndsAt.Valid && !m.EndsAt.Time.After(time.Now()) {
+		return errors.E(op, errors.CodeBadRequest, "maintenance end time must be in the future")
+	}
+
+	err := j.Database.Transaction(func(db *db.Database) error {
+		c := dbmodel.Controller{
+			Name: controllerName,
+		}
+		if err := db.GetController(ctx, &c); err != nil {
+			return err
+		}
+		c.Maintenance = m
+		return db.UpdateController(ctx, &c)
+	})
+	if err != nil {
+		return errors.E(op, err)
+	}
+
+	return nil
+}
+
+// ControllerMaintenanceError returns the error returned for requests that
+// are refused because the given controller is in maintenance. If the
+// controller is not in maintenance nil is returned.
+func ControllerMaintenanceError(ctl *dbmodel.Controller) error {
+	if !ctl.Maintenance.Active(time.Now()) {
+		return nil
+	}
+	msg := fmt.Sprintf("controller %q is in maintenance", ctl.Name)
+	if ctl.Maintenance.Message != "" {
+		msg += ": " + ctl.Maintenance.Message
+	}
+	if ctl.Maintenance.EndsAt.Valid {
+		msg += fmt.Sprintf(" (expected to end at %s)", ctl.Maintenance.EndsAt.Time.UTC().Format(time.RFC3339))
+	}
+	return errors.E(errors.CodeControllerMaintenance, msg)
+}
@@ -0,0 +1,161 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	"github.com/canonical/jimm/v3/internal/testutils/jimmtest"
+)
+
+func TestSetControllerMaintenance(t *testing.T) {
+	c := qt.New(t)
+
+	ctx := context.Background()
+
+	j := jimmtest.NewJIMM(c, nil)
+
+	env := jimmtest.ParseEnvironment(c, testSetControllerDeprecatedEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, j.OpenFGAClient)
+
+	aliceIdentity := env.User("alice@canonical.com").DBObject(c, j.Database)
+	alice := openfga.NewUser(&aliceIdentity, j.OpenFGAClient)
+	alice.JimmAdmin = true
+	eveIdentity := env.User("eve@canonical.com").DBObject(c, j.Database)
+	eve := openfga.NewUser(&eveIdentity, j.OpenFGAClient)
+
+	endsAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	m := dbmodel.ControllerMaintenance{
+		Enabled:       true,
+		Message:       "upgrading",
+		EndsAt:        sql.NullTime{Time: endsAt, Valid: true},
+		AllowReadOnly: true,
+	}
+
+	err := j.SetControllerMaintenance(ctx, eve, "test1", m)
+	c.Check(err, qt.ErrorMatches, "unauthorized")
+
+	err = j.SetControllerMaintenance(ctx, alice, "test1", dbmodel.ControllerMaintenance{
+		Enabled: true,
+		EndsAt:  sql.NullTime{Time: time.Now().Add(-time.Minute), Valid: true},
+	})
+	c.Check(err, qt.ErrorMatches, "maintenance end time must be in the future")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+
+	err = j.SetControllerMaintenance(ctx, alice, "test1", m)
+	c.Assert(err, qt.IsNil)
+	ctl := dbmodel.Controller{Name: "test1"}
+	err = j.Database.GetController(ctx, &ctl)
+	c.Assert(err, qt.IsNil)
+	c.Check(ctl.Maintenance.Enabled, qt.IsTrue)
+	c.Check(ctl.Maintenance.Message, qt.Equals, "upgrading")
+	c.Check(ctl.Maintenance.EndsAt.Time.Equal(endsAt), qt.IsTrue)
+	c.Check(ctl.Maintenance.AllowReadOnly, qt.IsTrue)
+	c.Check(ctl.ToAPIControllerInfo().Status.Status, qt.Equals, "maintenance")
+
+	err = jimm.ControllerMaintenanceError(&ctl)
+	c.Check(err, qt.ErrorMatches, `controller "test1" is in maintenance: upgrading \(expected to end at .*\)`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeControllerMaintenance)
+
+	// Taking the controller out of maintenance clears the maintenance
+	// state.
+	err = j.SetControllerMaintenance(ctx, alice, "test1", dbmodel.ControllerMaintenance{Message: "ignored"})
+	c.Assert(err, qt.IsNil)
+	ctl = dbmodel.Controller{Name: "test1"}
+	err = j.Database.GetController(ctx, &ctl)
+	c.Assert(err, qt.IsNil)
+	c.Check(ctl.Maintenance, qt.DeepEquals, dbmodel.ControllerMaintenance{})
+	c.Check(jimm.ControllerMaintenanceError(&ctl), qt.IsNil)
+	c.Check(ctl.ToAPIControllerInfo().Status.Status, qt.Equals, "available")
+}
+
+const testControllerMaintenanceEnv = `clouds:
+- name: test
+  type: test
+  regions:
+  - name: test-region
+cloud-credentials:
+- name: test-cred
+  cloud: test
+  owner: alice@canonical.com
+  type: empty
+controllers:
+- name: test1
+  uuid: 00000001-0000-0000-0000-000000000001
+  cloud: test
+  region: test-region
+  agent-version: 3.2.1
+models:
+- name: test-model
+  owner: alice@canonical.com
+  uuid: 00000002-0000-0000-0000-000000000001
+  cloud: test
+  region: test-region
+  cloud-credential: test-cred
+  controller: test1
+users:
+- username: alice@canonical.com
+  controller-access: superuser
+`
+
+func TestControllerMaintenanceRefusedBeforeDial(t *testing.T) {
+	c := qt.New(t)
+
+	ctx := context.Background()
+
+	j := jimmtest.NewJIMM(c, &jimm.Parameters{
+		Dialer: &jimmtest.Dialer{
+			Err: errors.E("unexpected dial"),
+		},
+	})
+
+	env := jimmtest.ParseEnvironment(c, testControllerMaintenanceEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, j.OpenFGAClient)
+
+	aliceIdentity := env.User("alice@canonical.com").DBObject(c, j.Database)
+	alice := openfga.NewUser(&aliceIdentity, j.OpenFGAClient)
+	alice.JimmAdmin = true
+
+	err := j.SetControllerMaintenance(ctx, alice, "test1", dbmodel.ControllerMaintenance{Enabled: true})
+	c.Assert(err, qt.IsNil)
+
+	mt := names.NewModelTag("00000002-0000-0000-0000-000000000001")
+	err = j.DestroyModel(ctx, alice, mt, nil, nil, nil, nil)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeControllerMaintenance)
+
+	err = j.ChangeModelCredential(ctx, alice, mt, names.NewCloudCredentialTag("test/alice@canonical.com/test-cred"))
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeControllerMaintenance)
+
+	_, err = j.InitiateMigration(ctx, alice, jujuparams.MigrationSpec{
+		ModelTag: mt.String(),
+		TargetInfo: jujuparams.MigrationTargetInfo{
+			ControllerTag: names.NewControllerTag("00000001-0000-0000-0000-000000000002").String(),
+			AuthTag:       names.NewUserTag("admin").String(),
+		},
+	})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeControllerMaintenance)
+
+	err = j.Offer(ctx, alice, jimm.AddApplicationOfferParams{
+		ModelTag:  mt,
+		OfferName: "test-offer",
+	})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeControllerMaintenance)
+
+	// The model is unchanged.
+	m := dbmodel.Model{}
+	m.SetTag(mt)
+	err = j.Database.GetModel(ctx, &m)
+	c.Assert(err, qt.IsNil)
+	c.Check(m.Life, qt.Equals, "alive")
+}
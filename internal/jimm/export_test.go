@@ -4,6 +4,7 @@ package jimm
 
 import (
 	"context"
+	"time"
 
 	jujuparams "github.com/juju/juju/rpc/params"
 	"github.com/juju/names/v5"
@@ -16,12 +17,15 @@ import (
 
 var (
 	DetermineAccessLevelAfterGrant = determineAccessLevelAfterGrant
-	PollDuration                   = pollDuration
-	CalculateNextPollDuration      = calculateNextPollDuration
+	CheckLeader                    = checkLeader
 	NewControllerClient            = &newControllerClient
 	FillMigrationTarget            = fillMigrationTarget
 	InitiateMigration              = &initiateMigration
 	ResolveTag                     = resolveTag
+	NewCharmDriftReport            = newCharmDriftReport
+	AddModelOwnerRelation          = &addModelOwnerRelation
+	RemoveModelOwnerRelation       = &removeModelOwnerRelation
+	AddControllerModel             = &addControllerModel
 )
 
 func NewWatcherWithControllerUnavailableChan(db *db.Database, dialer Dialer, pubsub Publisher, testChannel chan error) *Watcher {
@@ -61,3 +65,35 @@ func (j *JIMM) UpdateUserLastLogin(ctx context.Context, identifier string) error
 func (j *JIMM) EveryoneUser() *openfga.User {
 	return j.everyoneUser()
 }
+
+func (j *JIMM) RecordAuditWriteFailure(t time.Time, err error) {
+	j.auditWriteFailures.record(t, err)
+}
+
+func (s *ModelSummaryWatchStatus) SetController(name string, err error) {
+	s.setController(name, err)
+}
+
+func (s *ModelSummaryWatchStatus) PauseController(name string) {
+	s.pauseController(name)
+}
+
+func (s *ModelSummaryWatchStatus) RemoveController(name string) {
+	s.removeController(name)
+}
+
+func (s *ModelSummaryWatchStatus) RetainControllers(names map[string]bool) {
+	s.retainControllers(names)
+}
+
+func (s *ModelSummaryWatchStatus) SetListening(err error) {
+	s.setListening(err)
+}
+
+func (p *ControllerPool) SetClock(now func() time.Time) {
+	p.now = now
+}
+
+func (p *ControllerPool) Maintain(ctx context.Context) {
+	p.maintain(ctx)
+}
@@ -60,16 +60,26 @@ func (j *groupManager) CountGroups(ctx context.Context, user *openfga.User) (int
 	return count, nil
 }
 
-// getGroup returns a group based on the provided UUID or name.
+// getGroup returns a group based on the provided UUID or name. The user
+// must be an admin or a manager of the group.
 func (j *groupManager) getGroup(ctx context.Context, user *openfga.User, group *dbmodel.GroupEntry) (*dbmodel.GroupEntry, error) {
 	const op = errors.Op("jimm.getGroup")
 
-	if !user.JimmAdmin {
-		return nil, errors.E(op, errors.CodeUnauthorized, "unauthorized")
-	}
 	if err := j.store.GetGroup(ctx, group); err != nil {
+		if !user.JimmAdmin {
+			return nil, errors.E(op, errors.CodeUnauthorized, "unauthorized")
+		}
 		return nil, errors.E(op, err)
 	}
+	if !user.JimmAdmin {
+		isManager, err := user.IsGroupManager(ctx, group.ResourceTag())
+		if err != nil {
+			return nil, errors.E(op, err)
+		}
+		if !isManager {
+			return nil, errors.E(op, errors.CodeUnauthorized, "unauthorized")
+		}
+	}
 	return group, nil
 }
 
@@ -108,6 +108,31 @@ func (s *groupManagerSuite) TestGetGroup(c *qt.C) {
 	c.Assert(err, qt.Not(qt.IsNil))
 }
 
+func (s *groupManagerSuite) TestGetGroupAsManager(c *qt.C) {
+	c.Parallel()
+	ctx := context.Background()
+
+	groupEntry, err := s.manager.AddGroup(ctx, s.adminUser, "test-group-1")
+	c.Assert(err, qt.IsNil)
+
+	_, err = s.manager.GetGroupByUUID(ctx, s.user, groupEntry.UUID)
+	c.Assert(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+
+	err = s.ofgaClient.AddRelation(ctx, openfga.Tuple{
+		Object:   ofganames.ConvertTag(s.user.ResourceTag()),
+		Relation: ofganames.ManagerRelation,
+		Target:   ofganames.ConvertTag(groupEntry.ResourceTag()),
+	})
+	c.Assert(err, qt.IsNil)
+
+	gotGroup, err := s.manager.GetGroupByUUID(ctx, s.user, groupEntry.UUID)
+	c.Assert(err, qt.IsNil)
+	c.Assert(gotGroup, qt.DeepEquals, groupEntry)
+
+	_, err = s.manager.GetGroupByName(ctx, s.user, "non-existent")
+	c.Assert(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+}
+
 func (s *groupManagerSuite) TestRemoveGroup(c *qt.C) {
 	c.Parallel()
 	ctx := context.Background()
@@ -149,6 +174,11 @@ func (s *groupManagerSuite) TestRemoveGroupRemovesTuples(c *qt.C) {
 			Relation: "member",
 			Target:   ofganames.ConvertTag(group2.ResourceTag()),
 		},
+		{
+			Object:   ofganames.ConvertTag(user.ResourceTag()),
+			Relation: "manager",
+			Target:   ofganames.ConvertTag(group2.ResourceTag()),
+		},
 		{
 			Object:   ofganames.ConvertTagWithRelation(group2.ResourceTag(), ofganames.MemberRelation),
 			Relation: "member",
@@ -177,7 +207,7 @@ func (s *groupManagerSuite) TestRemoveGroupRemovesTuples(c *qt.C) {
 
 	remainingTuples, _, err := s.ofgaClient.ReadRelatedObjects(ctx, ofga.Tuple{}, 0, "")
 	c.Assert(err, qt.IsNil)
-	c.Assert(remainingTuples, qt.HasLen, 3)
+	c.Assert(remainingTuples, qt.HasLen, 4)
 
 	err = s.manager.RemoveGroup(ctx, s.adminUser, group2.Name)
 	c.Assert(err, qt.IsNil)
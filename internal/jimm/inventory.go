@@ -0,0 +1,306 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/juju/charm/v12"
+	corebase "github.com/juju/juju/core/base"
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/juju/state"
+	"github.com/juju/names/v5"
+	"github.com/juju/zaputil/zapctx"
+	"go.uber.org/zap"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	"github.com/canonical/jimm/v3/internal/servermon"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// InventoryParams holds the parameters used when collecting the estate
+// inventory.
+type InventoryParams struct {
+	// Interval is the time between collections from each controller.
+	Interval time.Duration
+
+	// Jitter is the maximum random delay before each collection from a
+	// controller, which spreads the load of collecting from many
+	// controllers.
+	Jitter time.Duration
+}
+
+// CollectInventory periodically collects the inventory of every model
+// on every controller known to JIMM, storing the inventories in the
+// database. A collection from each controller is started, after a random
+// delay of up to the configured jitter, at every interval unless the
+// previous collection from that controller is still running.
+// CollectInventory blocks until the given context is cancelled, or there
+// is an error querying the database.
+func (j *JIMM) CollectInventory(ctx context.Context, p InventoryParams) error {
+	const op = errors.Op("jimm.CollectInventory")
+
+	r := newRunner()
+	// Ensure that all started goroutines are completed before we return.
+	defer r.wait()
+
+	ticker := time.NewTicker(p.Interval)
+	defer ticker.Stop()
+	for {
+		err := j.Database.ForEachController(ctx, func(ctl *dbmodel.Controller) error {
+			ctl = &dbmodel.Controller{ID: ctl.ID, Name: ctl.Name}
+			r.run(ctl.Name, func() {
+				ctx := zapctx.WithFields(ctx, zap.String("controller", ctl.Name))
+				if p.Jitter > 0 {
+					select {
+					case <-ctx.Done():
+						return
+					case <-time.After(time.Duration(rand.Int63n(int64(p.Jitter)))):
+					}
+				}
+				if err := j.CollectControllerInventory(ctx, ctl); err != nil {
+					zapctx.Error(ctx, "inventory collection failed", zap.Error(err))
+				}
+			})
+			return nil
+		})
+		if err != nil {
+			// Ignore temporary database errors.
+			if errors.ErrorCode(err) != errors.CodeDatabaseLocked {
+				return errors.E(op, err)
+			}
+			zapctx.Warn(ctx, "temporary error polling for controllers", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// CollectControllerInventory collects the inventory of every model on the
+// given controller. A failure to collect the inventory of one model does
+// not prevent the collection of the others; the previous inventory of
+// such a model is kept. Controllers in maintenance are skipped, keeping
+// their previous inventories.
+func (j *JIMM) CollectControllerInventory(ctx context.Context, ctl *dbmodel.Controller) error {
+	const op = errors.Op("jimm.CollectControllerInventory")
+	durationObserver := servermon.DurationObserver(servermon.JimmMethodsDurationHistogram, string(op))
+	defer durationObserver()
+
+	if err := j.Database.GetController(ctx, ctl); err != nil {
+		return errors.E(op, err)
+	}
+	if ctl.Maintenance.Active(time.Now()) {
+		zapctx.Debug(ctx, "skipping inventory collection from controller in maintenance")
+		return nil
+	}
+	var models []dbmodel.Model
+	err := j.Database.ForEachControllerModel(ctx, ctl, func(m *dbmodel.Model) error {
+		models = append(models, *m)
+		return nil
+	})
+	if err != nil {
+		return errors.E(op, err)
+	}
+	for i := range models {
+		m := &models[i]
+		if m.Life == state.Dead.String() || !m.UUID.Valid {
+			continue
+		}
+		m.Controller = *ctl
+		if err := j.CollectModelInventory(ctx, m); err != nil {
+			zapctx.Error(ctx, "cannot collect model inventory", zap.String("model", m.UUID.String), zap.Error(err))
+		}
+	}
+	return nil
+}
+
+// CollectModelInventory collects and stores the inventory of the given
+// model, which must have its controller loaded.
+func (j *JIMM) CollectModelInventory(ctx context.Context, m *dbmodel.Model) error {
+	const op = errors.Op("jimm.CollectModelInventory")
+
+	api, err := j.dial(ctx, &m.Controller, m.ResourceTag())
+	if err != nil {
+		return errors.E(op, err)
+	}
+	defer api.Close()
+	status, err := api.Status(ctx, nil)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	inv := newModelInventory(m.ID, status, time.Now().UTC())
+	if err := checkLeader(ctx); err != nil {
+		return errors.E(op, err)
+	}
+	if err := j.Database.SetModelInventory(ctx, inv); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// GetModelInventory returns the most recently collected inventory of the
+// model with the given tag. The user must be able to read the model. As
+// the inventory is stored by JIMM it is available even when the model's
+// controller is not.
+func (j *JIMM) GetModelInventory(ctx context.Context, user *openfga.User, mt names.ModelTag) (apiparams.ModelInventory, error) {
+	const op = errors.Op("jimm.GetModelInventory")
+
+	ok, err := user.IsModelReader(ctx, mt)
+	if err != nil {
+		return apiparams.ModelInventory{}, errors.E(op, err, errors.CodeOpenFGARequestFailed)
+	}
+	if !ok {
+		return apiparams.ModelInventory{}, errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+	var m dbmodel.Model
+	m.SetTag(mt)
+	if err := j.Database.GetModel(ctx, &m); err != nil {
+		return apiparams.ModelInventory{}, errors.E(op, err)
+	}
+	inv := dbmodel.ModelInventory{ModelID: m.ID}
+	if err := j.Database.GetModelInventory(ctx, &inv); err != nil {
+		return apiparams.ModelInventory{}, errors.E(op, err)
+	}
+	return inv.ToAPIModelInventory(), nil
+}
+
+// QueryInventory finds the applications across all model inventories
+// that match the given request. Only JIMM administrators may query the
+// inventory.
+func (j *JIMM) QueryInventory(ctx context.Context, user *openfga.User, req apiparams.QueryInventoryRequest) ([]apiparams.InventoryApplicationResult, error) {
+	const op = errors.Op("jimm.QueryInventory")
+
+	if err := j.checkJimmAdmin(user); err != nil {
+		return nil, errors.E(op, err)
+	}
+	apps, err := j.Database.FindInventoryApplications(ctx, db.InventoryQuery{
+		Controller:    req.Controller,
+		Charm:         req.Charm,
+		CharmChannel:  req.CharmChannel,
+		Revision:      req.Revision,
+		BelowRevision: req.BelowRevision,
+		Base:          req.Base,
+		Offset:        req.Offset,
+		Limit:         req.Limit,
+	})
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	results := make([]apiparams.InventoryApplicationResult, len(apps))
+	for i, a := range apps {
+		results[i] = apiparams.InventoryApplicationResult{
+			InventoryApplication: a.ToAPIInventoryApplication(),
+			ModelUUID:            a.ModelUUID,
+			ModelName:            a.ModelName,
+			Owner:                a.OwnerIdentityName,
+			Controller:           a.ControllerName,
+			CollectedAt:          a.CollectedAt,
+		}
+	}
+	return results, nil
+}
+
+// newModelInventory normalizes the given status of a model into a model
+// inventory.
+func newModelInventory(modelID uint, status *jujuparams.FullStatus, collectedAt time.Time) *dbmodel.ModelInventory {
+	inv := dbmodel.ModelInventory{
+		ModelID:     modelID,
+		CollectedAt: collectedAt,
+	}
+	for _, name := range sortedMapKeys(status.Applications) {
+		app := status.Applications[name]
+		charmName, revision := app.Charm, -1
+		if curl, err := charm.ParseURL(app.Charm); err == nil {
+			charmName, revision = curl.Name, curl.Revision
+		}
+		inv.Applications = append(inv.Applications, dbmodel.InventoryApplication{
+			Name:            name,
+			Charm:           charmName,
+			CharmURL:        app.Charm,
+			CharmChannel:    app.CharmChannel,
+			CharmRevision:   revision,
+			Base:            formatBase(app.Base),
+			Status:          app.Status.Status,
+			Exposed:         app.Exposed,
+			WorkloadVersion: app.WorkloadVersion,
+		})
+		for _, unitName := range sortedMapKeys(app.Units) {
+			unit := app.Units[unitName]
+			inv.Units = append(inv.Units, newInventoryUnit(unitName, name, "", unit))
+			for _, subName := range sortedMapKeys(unit.Subordinates) {
+				sub := unit.Subordinates[subName]
+				if sub.Machine == "" {
+					sub.Machine = unit.Machine
+				}
+				subApp, _ := names.UnitApplication(subName)
+				inv.Units = append(inv.Units, newInventoryUnit(subName, subApp, unitName, sub))
+			}
+		}
+	}
+	var addMachines func(map[string]jujuparams.MachineStatus)
+	addMachines = func(machines map[string]jujuparams.MachineStatus) {
+		for _, id := range sortedMapKeys(machines) {
+			m := machines[id]
+			inv.Machines = append(inv.Machines, dbmodel.InventoryMachine{
+				MachineID:  id,
+				Base:       formatBase(m.Base),
+				InstanceID: string(m.InstanceId),
+				Status:     m.AgentStatus.Status,
+				Hardware:   m.Hardware,
+			})
+			addMachines(m.Containers)
+		}
+	}
+	addMachines(status.Machines)
+	for _, r := range status.Relations {
+		inv.Relations = append(inv.Relations, dbmodel.InventoryRelation{
+			RelationID: r.Id,
+			Key:        r.Key,
+			Interface:  r.Interface,
+			Scope:      r.Scope,
+			Status:     r.Status.Status,
+		})
+	}
+	return &inv
+}
+
+func newInventoryUnit(name, application, principal string, unit jujuparams.UnitStatus) dbmodel.InventoryUnit {
+	return dbmodel.InventoryUnit{
+		Name:           name,
+		Application:    application,
+		Machine:        unit.Machine,
+		Principal:      principal,
+		WorkloadStatus: unit.WorkloadStatus.Status,
+		AgentStatus:    unit.AgentStatus.Status,
+	}
+}
+
+// formatBase formats the given base in the form "<name>@<channel>",
+// omitting a stable risk from the channel.
+func formatBase(b jujuparams.Base) string {
+	if b.Name == "" {
+		return ""
+	}
+	if base, err := corebase.ParseBase(b.Name, b.Channel); err == nil {
+		return base.DisplayString()
+	}
+	return b.Name + "@" + b.Channel
+}
+
+func sortedMapKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,216 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	"github.com/canonical/jimm/v3/internal/testutils/jimmtest"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const inventoryTestEnv = `clouds:
+- name: test-cloud
+  type: test-provider
+  regions:
+  - name: test-region-1
+cloud-credentials:
+- name: test-credential-1
+  owner: alice@canonical.com
+  cloud: test-cloud
+  auth-type: empty
+controllers:
+- name: controller-1
+  uuid: 00000000-0000-0000-0000-0000-0000000000001
+  cloud: test-cloud
+  region: test-region-1
+models:
+- name: model-1
+  owner: alice@canonical.com
+  uuid: 00000001-0000-0000-0000-0000-000000000001
+  cloud: test-cloud
+  region: test-region-1
+  cloud-credential: test-credential-1
+  controller: controller-1
+  life: alive
+  users:
+  - user: alice@canonical.com
+    access: admin
+users:
+- username: alice@canonical.com
+  controller-access: login
+- username: bob@canonical.com
+  controller-access: login
+`
+
+var inventoryTestStatus = jujuparams.FullStatus{
+	Applications: map[string]jujuparams.ApplicationStatus{
+		"db": {
+			Charm:        "ch:amd64/jammy/postgresql-280",
+			CharmChannel: "14/stable",
+			Base:         jujuparams.Base{Name: "ubuntu", Channel: "22.04/stable"},
+			Status:       jujuparams.DetailedStatus{Status: "active"},
+			Units: map[string]jujuparams.UnitStatus{
+				"db/0": {
+					Machine:        "0",
+					WorkloadStatus: jujuparams.DetailedStatus{Status: "active"},
+					AgentStatus:    jujuparams.DetailedStatus{Status: "idle"},
+					Subordinates: map[string]jujuparams.UnitStatus{
+						"ntp/0": {
+							WorkloadStatus: jujuparams.DetailedStatus{Status: "active"},
+						},
+					},
+				},
+			},
+		},
+		"ntp": {
+			Charm:  "local:ntp",
+			Base:   jujuparams.Base{Name: "ubuntu", Channel: "22.04"},
+			Status: jujuparams.DetailedStatus{Status: "active"},
+		},
+	},
+	Machines: map[string]jujuparams.MachineStatus{
+		"0": {
+			Base:        jujuparams.Base{Name: "ubuntu", Channel: "22.04/stable"},
+			InstanceId:  "i-0",
+			AgentStatus: jujuparams.DetailedStatus{Status: "started"},
+			Hardware:    "arch=amd64 cores=2",
+			Containers: map[string]jujuparams.MachineStatus{
+				"0/lxd/0": {
+					Base:       jujuparams.Base{Name: "ubuntu", Channel: "20.04/stable"},
+					InstanceId: "juju-0-lxd-0",
+				},
+			},
+		},
+	},
+	Relations: []jujuparams.RelationStatus{{
+		Id:        1,
+		Key:       "ntp:juju-info db:juju-info",
+		Interface: "juju-info",
+		Scope:     "container",
+		Status:    jujuparams.DetailedStatus{Status: "joined"},
+	}},
+}
+
+func TestCollectInventory(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	dialer := &jimmtest.Dialer{
+		API: &jimmtest.API{
+			Status_: func(context.Context, []string) (*jujuparams.FullStatus, error) {
+				return &inventoryTestStatus, nil
+			},
+		},
+	}
+	j := jimmtest.NewJIMM(c, &jimm.Parameters{
+		Dialer: dialer,
+	})
+
+	env := jimmtest.ParseEnvironment(c, inventoryTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, j.OpenFGAClient)
+	aliceIdentity := env.User("alice@canonical.com").DBObject(c, j.Database)
+	alice := openfga.NewUser(&aliceIdentity, j.OpenFGAClient)
+	bobIdentity := env.User("bob@canonical.com").DBObject(c, j.Database)
+	bob := openfga.NewUser(&bobIdentity, j.OpenFGAClient)
+	admin := openfga.NewUser(&bobIdentity, j.OpenFGAClient)
+	admin.JimmAdmin = true
+
+	mt := names.NewModelTag("00000001-0000-0000-0000-0000-000000000001")
+	_, err := j.GetModelInventory(ctx, alice, mt)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	ctl := dbmodel.Controller{Name: "controller-1"}
+	err = j.CollectControllerInventory(ctx, &ctl)
+	c.Assert(err, qt.IsNil)
+
+	// The inventory remains available while the controller is offline.
+	dialer.Err = errors.E("controller offline")
+	err = j.CollectControllerInventory(ctx, &ctl)
+	c.Assert(err, qt.IsNil)
+
+	inv, err := j.GetModelInventory(ctx, alice, mt)
+	c.Assert(err, qt.IsNil)
+	c.Check(inv.CollectedAt.IsZero(), qt.IsFalse)
+	inv.CollectedAt = inv.CollectedAt.UTC()
+	c.Check(inv, qt.DeepEquals, apiparams.ModelInventory{
+		ModelUUID:   mt.Id(),
+		ModelName:   "model-1",
+		Owner:       "alice@canonical.com",
+		Controller:  "controller-1",
+		CollectedAt: inv.CollectedAt,
+		Applications: []apiparams.InventoryApplication{{
+			Name:          "db",
+			Charm:         "postgresql",
+			CharmURL:      "ch:amd64/jammy/postgresql-280",
+			CharmChannel:  "14/stable",
+			CharmRevision: 280,
+			Base:          "ubuntu@22.04",
+			Status:        "active",
+		}, {
+			Name:          "ntp",
+			Charm:         "ntp",
+			CharmURL:      "local:ntp",
+			CharmRevision: -1,
+			Base:          "ubuntu@22.04",
+			Status:        "active",
+		}},
+		Units: []apiparams.InventoryUnit{{
+			Name:           "db/0",
+			Application:    "db",
+			Machine:        "0",
+			WorkloadStatus: "active",
+			AgentStatus:    "idle",
+		}, {
+			Name:           "ntp/0",
+			Application:    "ntp",
+			Machine:        "0",
+			Principal:      "db/0",
+			WorkloadStatus: "active",
+		}},
+		Machines: []apiparams.InventoryMachine{{
+			ID:         "0",
+			Base:       "ubuntu@22.04",
+			InstanceID: "i-0",
+			Status:     "started",
+			Hardware:   "arch=amd64 cores=2",
+		}, {
+			ID:         "0/lxd/0",
+			Base:       "ubuntu@20.04",
+			InstanceID: "juju-0-lxd-0",
+		}},
+		Relations: []apiparams.InventoryRelation{{
+			ID:        1,
+			Key:       "ntp:juju-info db:juju-info",
+			Interface: "juju-info",
+			Scope:     "container",
+			Status:    "joined",
+		}},
+	})
+
+	_, err = j.GetModelInventory(ctx, bob, mt)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+
+	_, err = j.QueryInventory(ctx, alice, apiparams.QueryInventoryRequest{})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+
+	apps, err := j.QueryInventory(ctx, admin, apiparams.QueryInventoryRequest{Charm: "postgresql", BelowRevision: 300})
+	c.Assert(err, qt.IsNil)
+	c.Assert(apps, qt.HasLen, 1)
+	c.Check(apps[0].Name, qt.Equals, "db")
+	c.Check(apps[0].ModelUUID, qt.Equals, mt.Id())
+	c.Check(apps[0].Controller, qt.Equals, "controller-1")
+
+	apps, err = j.QueryInventory(ctx, admin, apiparams.QueryInventoryRequest{Charm: "postgresql", Revision: 300})
+	c.Assert(err, qt.IsNil)
+	c.Check(apps, qt.HasLen, 0)
+}
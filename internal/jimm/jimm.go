@@ -19,6 +19,7 @@ import (
 	"github.com/juju/juju/core/crossmodel"
 	jujuparams "github.com/juju/juju/rpc/params"
 	"github.com/juju/names/v5"
+	"github.com/juju/version/v2"
 	"github.com/juju/zaputil/zapctx"
 	"github.com/lestrrat-go/jwx/v2/jwt"
 	"go.uber.org/zap"
@@ -222,6 +223,7 @@ func (p *Parameters) Validate() error {
 // See [Option] and [Parameters] to better understand how to perform dependency injection.
 // Primitives like the dialer or authentication service can be mocked at a low level,
 // alternatively top business layer objects like the RoleManager can be mocked instead.
+// The database must already have been migrated.
 func New(p Parameters) (*JIMM, error) {
 	if err := p.Validate(); err != nil {
 		return nil, err
@@ -231,10 +233,6 @@ func New(p Parameters) (*JIMM, error) {
 		Parameters: p,
 	}
 
-	if err := j.Database.Migrate(context.Background(), false); err != nil {
-		return nil, errors.E(err)
-	}
-
 	roleManager, err := role.NewRoleManager(j.Database, p.OpenFGAClient)
 	if err != nil {
 		return nil, err
@@ -262,6 +260,10 @@ type JIMM struct {
 
 	// groupManager provides a means to manage groups within JIMM.
 	groupManager GroupManager
+
+	// auditWriteFailures records the recent failures to write to the
+	// audit log, used to evaluate audit-write-failure alert rules.
+	auditWriteFailures auditWriteFailures
 }
 
 // ResourceTag returns JIMM's controller tag stating its UUID.
@@ -456,6 +458,10 @@ type API interface {
 	// UpdateCredential updates a credential.
 	UpdateCredential(context.Context, jujuparams.TaggedCredential) ([]jujuparams.UpdateCredentialModelResult, error)
 
+	// UpgradeModel starts upgrading the agents of a model, or checks
+	// that the upgrade is possible if dryRun is true.
+	UpgradeModel(ctx context.Context, model names.ModelTag, targetVersion version.Number, stream string, dryRun bool) (version.Number, error)
+
 	// ValidateModelUpgrade validates that a model can be upgraded.
 	ValidateModelUpgrade(context.Context, names.ModelTag, bool) error
 
@@ -507,6 +513,7 @@ func (j *JIMM) AddAuditLogEntry(ale *dbmodel.AuditLogEntry) {
 	redactSensitiveParams(ale)
 	if err := j.Database.AddAuditLogEntry(ctx, ale); err != nil {
 		zapctx.Error(ctx, "cannot store audit log entry", zap.Error(err), zap.Any("entry", *ale))
+		j.auditWriteFailures.record(time.Now(), err)
 	}
 }
 
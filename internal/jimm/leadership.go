@@ -0,0 +1,310 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/juju/zaputil/zapctx"
+	"go.uber.org/zap"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+const (
+	// DefaultLeaderLeaseName is the name of the lease used to elect the
+	// replica that runs JIMM's background jobs.
+	DefaultLeaderLeaseName = "jimm"
+
+	// DefaultLeaderLeaseDuration is the default length of time a leader
+	// lease is held without being renewed.
+	DefaultLeaderLeaseDuration = 30 * time.Second
+)
+
+// A LeaderElector elects a single leader amongst the JIMM replicas sharing
+// a database. Leadership is held as a lease in the database which the
+// leader renews every third of the lease duration. If the leader cannot
+// renew the lease it steps down before the lease expires, after which
+// another replica can take over. Every new term of leadership has a
+// larger fencing token than the previous one.
+type LeaderElector struct {
+	// Database is the database holding the lease.
+	Database *db.Database
+
+	// Name is the name of the lease. Replicas using the same name compete
+	// for the same leadership. If this is empty DefaultLeaderLeaseName
+	// is used.
+	Name string
+
+	// Holder identifies this replica. It must be unique amongst the
+	// replicas.
+	Holder string
+
+	// LeaseDuration is the length of time the lease is held without
+	// being renewed. If this is zero DefaultLeaderLeaseDuration is used.
+	LeaseDuration time.Duration
+
+	mu     sync.Mutex
+	leader bool
+	lease  dbmodel.LeaderLease
+}
+
+// A LeaderStatus is the status of a leader election, as seen by a
+// replica.
+type LeaderStatus struct {
+	// Replica is the holder name of the reporting replica.
+	Replica string `json:"replica"`
+
+	// IsLeader reports whether the reporting replica is the leader.
+	IsLeader bool `json:"is-leader"`
+
+	// Leader is the holder name of the replica that last acquired the
+	// lease.
+	Leader string `json:"leader,omitempty"`
+
+	// Token is the fencing token of the current lease.
+	Token int64 `json:"token,omitempty"`
+
+	// AcquiredAt is the time the current leader acquired the lease.
+	AcquiredAt *time.Time `json:"acquired-at,omitempty"`
+
+	// ExpiresAt is the time the lease expires unless it is renewed.
+	ExpiresAt *time.Time `json:"expires-at,omitempty"`
+}
+
+// Run campaigns for leadership until the given context is canceled. Each
+// time this replica is elected the given lead function is called with a
+// context that is canceled when leadership is lost. The lead function
+// should return once its context is canceled, leadership is not given up
+// until it has done so. If the lead function returns an error whilst
+// still leading, the lease is released and the error is returned.
+func (e *LeaderElector) Run(ctx context.Context, lead func(context.Context) error) error {
+	const op = errors.Op("jimm.LeaderElector.Run")
+
+	ttl := e.leaseDuration()
+	ctx = zapctx.WithFields(ctx, zap.String("lease", e.name()), zap.String("holder", e.Holder))
+	ticker := time.NewTicker(ttl / 3)
+	defer ticker.Stop()
+	for {
+		deadline, ok, err := e.campaign(ctx, ttl)
+		if err != nil {
+			zapctx.Warn(ctx, "cannot acquire leader lease", zap.Error(err))
+		}
+		if ok {
+			if err := e.lead(ctx, ttl, deadline, ticker.C, lead); err != nil {
+				return errors.E(op, err)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// lead runs the given lead function for a single term of leadership,
+// renewing the lease each time renew fires. The lead function is given a
+// context that carries the term of leadership, see checkLeader.
+func (e *LeaderElector) lead(ctx context.Context, ttl time.Duration, deadline time.Time, renew <-chan time.Time, lead func(context.Context) error) error {
+	e.mu.Lock()
+	e.leader = true
+	token := e.lease.Token
+	term := leaderTerm{db: e.Database, lease: e.lease}
+	e.mu.Unlock()
+	zapctx.Info(ctx, "elected leader", zap.Int64("token", token))
+
+	leaderCtx, cancel := context.WithCancel(context.WithValue(ctx, leaderTermKey{}, term))
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		done <- lead(leaderCtx)
+	}()
+
+	stepDown := time.NewTimer(time.Until(deadline))
+	defer stepDown.Stop()
+
+	var leadErr error
+	finished := false
+loop:
+	for {
+		select {
+		case leadErr = <-done:
+			finished = true
+			break loop
+		case <-ctx.Done():
+			break loop
+		case <-stepDown.C:
+			zapctx.Warn(ctx, "leader lease not renewed in time, stepping down")
+			break loop
+		case <-renew:
+			deadline, ok, err := e.campaign(ctx, ttl)
+			if err != nil {
+				// Keep leading until the deadline in case the
+				// error is temporary.
+				zapctx.Warn(ctx, "cannot renew leader lease", zap.Error(err))
+				continue
+			}
+			if !ok {
+				zapctx.Warn(ctx, "leader lease lost, stepping down")
+				break loop
+			}
+			stepDown.Reset(time.Until(deadline))
+		}
+	}
+	cancel()
+	if !finished {
+		if err := <-done; err != nil {
+			zapctx.Debug(ctx, "leader jobs stopped", zap.Error(err))
+		}
+	}
+
+	e.mu.Lock()
+	e.leader = false
+	lease := e.lease
+	e.mu.Unlock()
+
+	// Release the lease, even if the context has been canceled, so that
+	// another replica can take over without waiting for it to expire.
+	releaseCtx, releaseCancel := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
+	defer releaseCancel()
+	if err := e.Database.ReleaseLeaderLease(releaseCtx, &lease); err != nil {
+		zapctx.Warn(ctx, "cannot release leader lease", zap.Error(err))
+	}
+	zapctx.Info(ctx, "stepped down as leader", zap.Int64("token", token))
+	return leadErr
+}
+
+// campaign attempts to acquire or renew the lease. If the lease is held
+// the time by which it must next be renewed is returned. The deadline
+// leaves a third of the lease duration for the work being done by the
+// leader to stop before the lease expires.
+func (e *LeaderElector) campaign(ctx context.Context, ttl time.Duration) (time.Time, bool, error) {
+	start := time.Now()
+	lease := dbmodel.LeaderLease{
+		Name:   e.name(),
+		Holder: e.Holder,
+	}
+	ok, err := e.Database.AcquireLeaderLease(ctx, &lease, ttl)
+	if err != nil || !ok {
+		return time.Time{}, false, err
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.leader && lease.Token != e.lease.Token {
+		// The lease expired and was reacquired, so a new term has
+		// started, the current term must end.
+		return time.Time{}, false, nil
+	}
+	e.lease = lease
+	return start.Add(ttl - ttl/3), true, nil
+}
+
+// IsLeader reports whether this replica is currently the leader.
+func (e *LeaderElector) IsLeader() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.leader
+}
+
+// Token returns the fencing token of the current term of leadership. If
+// this replica is not the leader 0 is returned.
+func (e *LeaderElector) Token() int64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if !e.leader {
+		return 0
+	}
+	return e.lease.Token
+}
+
+// Fence checks, in the database, that this replica still holds the lease
+// for the current term of leadership. Work that must not be done by a
+// replica that has lost leadership should call Fence immediately before
+// it is performed. If the lease is not held an error with a code of
+// CodeUnauthorized is returned.
+func (e *LeaderElector) Fence(ctx context.Context) error {
+	const op = errors.Op("jimm.LeaderElector.Fence")
+
+	e.mu.Lock()
+	leader := e.leader
+	lease := e.lease
+	e.mu.Unlock()
+	if !leader {
+		return errors.E(op, errors.CodeUnauthorized, "not the leader")
+	}
+	if err := e.Database.CheckLeaderLease(ctx, &lease); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// leaderTermKey is the context key holding the leaderTerm in which
+// leader-only work is being done.
+type leaderTermKey struct{}
+
+// A leaderTerm is a single term of leadership.
+type leaderTerm struct {
+	db    *db.Database
+	lease dbmodel.LeaderLease
+}
+
+// checkLeader checks, in the database, that the term of leadership in
+// which the given context was created by LeaderElector.Run has not ended.
+// Leader-only work calls checkLeader immediately before each change it
+// makes, so that a replica that has lost leadership makes no further
+// changes whilst its work is stopping. Work done outside of a term of
+// leadership, such as on behalf of a user, is not checked. If the term
+// has ended an error with a code of CodeUnauthorized is returned.
+func checkLeader(ctx context.Context) error {
+	const op = errors.Op("jimm.checkLeader")
+
+	term, ok := ctx.Value(leaderTermKey{}).(leaderTerm)
+	if !ok {
+		return nil
+	}
+	if err := term.db.CheckLeaderLease(ctx, &term.lease); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// Status returns the current status of the leader election.
+func (e *LeaderElector) Status(ctx context.Context) (interface{}, error) {
+	const op = errors.Op("jimm.LeaderElector.Status")
+
+	status := LeaderStatus{
+		Replica:  e.Holder,
+		IsLeader: e.IsLeader(),
+	}
+	lease := dbmodel.LeaderLease{Name: e.name()}
+	if err := e.Database.GetLeaderLease(ctx, &lease); err != nil {
+		if errors.ErrorCode(err) == errors.CodeNotFound {
+			return status, nil
+		}
+		return nil, errors.E(op, err)
+	}
+	status.Leader = lease.Holder
+	status.Token = lease.Token
+	status.AcquiredAt = &lease.AcquiredAt
+	status.ExpiresAt = &lease.ExpiresAt
+	return status, nil
+}
+
+func (e *LeaderElector) name() string {
+	if e.Name == "" {
+		return DefaultLeaderLeaseName
+	}
+	return e.Name
+}
+
+func (e *LeaderElector) leaseDuration() time.Duration {
+	if e.LeaseDuration <= 0 {
+		return DefaultLeaderLeaseDuration
+	}
+	return e.LeaseDuration
+}
@@ -0,0 +1,170 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/testutils/jimmtest"
+)
+
+func TestLeaderElectorFailover(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	j := jimmtest.NewJIMM(c, nil)
+
+	type term struct {
+		holder string
+		token  int64
+	}
+	terms := make(chan term, 2)
+	newElector := func(holder string) (*jimm.LeaderElector, context.CancelFunc, chan error) {
+		e := &jimm.LeaderElector{
+			Database:      j.Database,
+			Holder:        holder,
+			LeaseDuration: 300 * time.Millisecond,
+		}
+		ctx, cancel := context.WithCancel(ctx)
+		errc := make(chan error, 1)
+		go func() {
+			errc <- e.Run(ctx, func(ctx context.Context) error {
+				terms <- term{holder: holder, token: e.Token()}
+				<-ctx.Done()
+				return nil
+			})
+		}()
+		return e, cancel, errc
+	}
+
+	e1, cancel1, errc1 := newElector("replica-1")
+	defer cancel1()
+	var t1 term
+	select {
+	case t1 = <-terms:
+	case <-time.After(5 * time.Second):
+		c.Fatalf("no leader elected")
+	}
+	c.Check(t1.holder, qt.Equals, "replica-1")
+	c.Check(e1.IsLeader(), qt.IsTrue)
+	c.Check(e1.Fence(ctx), qt.IsNil)
+
+	e2, cancel2, errc2 := newElector("replica-2")
+	defer cancel2()
+
+	// The second replica does not become leader whilst the first holds
+	// the lease.
+	select {
+	case t := <-terms:
+		c.Fatalf("unexpected leader elected: %v", t)
+	case <-time.After(time.Second):
+	}
+	c.Check(e2.IsLeader(), qt.IsFalse)
+	err := e2.Fence(ctx)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+
+	status, err := e2.Status(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Check(status.(jimm.LeaderStatus).Leader, qt.Equals, "replica-1")
+	c.Check(status.(jimm.LeaderStatus).Replica, qt.Equals, "replica-2")
+	c.Check(status.(jimm.LeaderStatus).IsLeader, qt.IsFalse)
+
+	// Stopping the leader fails over to the second replica with a new
+	// fencing token.
+	cancel1()
+	c.Assert(<-errc1, qt.IsNil)
+	c.Check(e1.IsLeader(), qt.IsFalse)
+	err = e1.Fence(ctx)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+
+	var t2 term
+	select {
+	case t2 = <-terms:
+	case <-time.After(5 * time.Second):
+		c.Fatalf("no failover")
+	}
+	c.Check(t2.holder, qt.Equals, "replica-2")
+	c.Check(t2.token > t1.token, qt.IsTrue)
+	c.Check(e2.Fence(ctx), qt.IsNil)
+
+	cancel2()
+	c.Assert(<-errc2, qt.IsNil)
+}
+
+func TestLeaderElectorLeadError(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	j := jimmtest.NewJIMM(c, nil)
+
+	e := &jimm.LeaderElector{
+		Database:      j.Database,
+		Holder:        "replica-1",
+		LeaseDuration: 300 * time.Millisecond,
+	}
+	err := e.Run(ctx, func(context.Context) error {
+		return errors.E("test error")
+	})
+	c.Check(err, qt.ErrorMatches, `test error`)
+	c.Check(e.IsLeader(), qt.IsFalse)
+
+	// The lease was released so another replica can take over
+	// immediately.
+	e2 := &jimm.LeaderElector{
+		Database:      j.Database,
+		Holder:        "replica-2",
+		LeaseDuration: 300 * time.Millisecond,
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var token int64
+	err = e2.Run(ctx, func(context.Context) error {
+		token = e2.Token()
+		cancel()
+		return nil
+	})
+	c.Assert(err, qt.IsNil)
+	c.Check(token, qt.Equals, int64(2))
+}
+
+func TestLeaderElectorFencesStaleLeader(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	j := jimmtest.NewJIMM(c, nil)
+
+	// Work done outside of a term of leadership is not fenced.
+	c.Check(jimm.CheckLeader(ctx), qt.IsNil)
+
+	e := &jimm.LeaderElector{
+		Database:      j.Database,
+		Holder:        "replica-1",
+		LeaseDuration: 300 * time.Millisecond,
+	}
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var leaderCtx context.Context
+	err := e.Run(runCtx, func(ctx context.Context) error {
+		c.Check(jimm.CheckLeader(ctx), qt.IsNil)
+		leaderCtx = ctx
+		cancel()
+		return nil
+	})
+	c.Assert(err, qt.IsNil)
+
+	// Another replica takes over, work continuing from the previous
+	// term is rejected.
+	lease := dbmodel.LeaderLease{Name: jimm.DefaultLeaderLeaseName, Holder: "replica-2"}
+	ok, err := j.Database.AcquireLeaderLease(ctx, &lease, time.Minute)
+	c.Assert(err, qt.IsNil)
+	c.Assert(ok, qt.IsTrue)
+	err = jimm.CheckLeader(context.WithoutCancel(leaderCtx))
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/juju/zaputil/zapctx"
 	"go.uber.org/zap"
 
+	"github.com/canonical/jimm/v3/internal/db"
 	"github.com/canonical/jimm/v3/internal/dbmodel"
 	"github.com/canonical/jimm/v3/internal/errors"
 	"github.com/canonical/jimm/v3/internal/openfga"
@@ -31,11 +32,23 @@ import (
 // are tried. It is a variable so it can be replaced in tests.
 var shuffle func(int, func(int, int)) = rand.Shuffle
 
+// addControllerModel relates a model to its controller. It is a variable
+// so that tests can inject failures.
+var addControllerModel = (*openfga.OFGAClient).AddControllerModel
+
 func shuffleRegionControllers(controllers []dbmodel.CloudRegionControllerPriority) {
 	shuffle(len(controllers), func(i, j int) {
 		controllers[i], controllers[j] = controllers[j], controllers[i]
 	})
+	// Controllers in maintenance are only chosen if there is no other
+	// controller for the region.
+	now := time.Now()
 	sort.SliceStable(controllers, func(i, j int) bool {
+		mi := controllers[i].Controller.Maintenance.Active(now)
+		mj := controllers[j].Controller.Maintenance.Active(now)
+		if mi != mj {
+			return mj
+		}
 		return controllers[i].Priority > controllers[j].Priority
 	})
 }
@@ -48,6 +61,12 @@ type ModelCreateArgs struct {
 	Cloud           names.CloudTag
 	CloudRegion     string
 	CloudCredential names.CloudCredentialTag
+	// TTL, if non-zero, is the time after which the model expires and
+	// is destroyed.
+	TTL time.Duration
+	// Template, if set, is the name of the model template the model is
+	// created from.
+	Template string
 }
 
 // FromJujuModelCreateArgs converts jujuparams.ModelCreateArgs into AddModelArgs.
@@ -57,6 +76,29 @@ func (a *ModelCreateArgs) FromJujuModelCreateArgs(args *jujuparams.ModelCreateAr
 	}
 	a.Name = args.Name
 	a.Config = args.Config
+	if v, ok := args.Config[ModelTTLConfigKey]; ok {
+		ttl, err := parseModelTTL(v)
+		if err != nil {
+			return err
+		}
+		a.TTL = ttl
+	}
+	if v, ok := args.Config[ModelTemplateConfigKey]; ok {
+		name, ok := v.(string)
+		if !ok || name == "" {
+			return errors.E(errors.CodeBadRequest, fmt.Sprintf("invalid %s value %v", ModelTemplateConfigKey, v))
+		}
+		a.Template = name
+	}
+	if a.TTL != 0 || a.Template != "" {
+		// JIMM specific keys are not passed on to the controller.
+		a.Config = make(map[string]interface{}, len(args.Config))
+		for k, v := range args.Config {
+			if k != ModelTTLConfigKey && k != ModelTemplateConfigKey {
+				a.Config[k] = v
+			}
+		}
+	}
 	a.CloudRegion = args.CloudRegion
 	if args.CloudTag != "" {
 		ct, err := names.ParseCloudTag(args.CloudTag)
@@ -110,6 +152,9 @@ type modelBuilder struct {
 	cloud         *dbmodel.Cloud
 	cloudRegion   string
 	cloudRegionID uint
+	ttl           time.Duration
+	template      *dbmodel.ModelTemplate
+	quotaLimits   []modelQuotaLimit
 	model         *dbmodel.Model
 	modelInfo     *jujuparams.ModelInfo
 }
@@ -176,6 +221,27 @@ func (b *modelBuilder) WithConfig(cfg map[string]interface{}) *modelBuilder {
 	return b
 }
 
+// WithTTL returns a builder that creates a model that expires after the
+// specified duration. A zero duration creates a model that does not
+// expire.
+func (b *modelBuilder) WithTTL(ttl time.Duration) *modelBuilder {
+	if b.err != nil {
+		return b
+	}
+	b.ttl = ttl
+	return b
+}
+
+// WithModelQuotaLimits returns a builder that only creates the model if
+// doing so would not exceed any of the given model quota limits.
+func (b *modelBuilder) WithModelQuotaLimits(limits []modelQuotaLimit) *modelBuilder {
+	if b.err != nil {
+		return b
+	}
+	b.quotaLimits = limits
+	return b
+}
+
 // WithCloud returns a builder with the specified cloud.
 func (b *modelBuilder) WithCloud(user *openfga.User, cloud names.CloudTag) *modelBuilder {
 	if b.err != nil {
@@ -349,10 +415,32 @@ func (b *modelBuilder) CreateDatabaseModel() *modelBuilder {
 		CloudCredentialID: b.credential.ID,
 		CloudRegionID:     b.cloudRegionID,
 	}
+	if b.template != nil && len(b.template.Labels) > 0 {
+		b.model.Labels = make(dbmodel.StringMap, len(b.template.Labels))
+		for k, v := range b.template.Labels {
+			b.model.Labels[k] = v
+		}
+	}
+	if b.ttl > 0 {
+		b.model.ExpiresAt = sql.NullTime{
+			Time:  time.Now().UTC().Add(b.ttl).Round(time.Second),
+			Valid: true,
+		}
+	}
 
-	err := b.jimm.Database.AddModel(b.ctx, b.model)
+	// The model quotas are checked in the same transaction that adds
+	// the model so that concurrent requests cannot exceed them.
+	err := b.jimm.Database.Transaction(func(tx *db.Database) error {
+		if err := b.jimm.checkModelQuotaLimits(b.ctx, tx, b.quotaLimits); err != nil {
+			return err
+		}
+		return tx.AddModel(b.ctx, b.model)
+	})
 	if err != nil {
-		if errors.ErrorCode(err) == errors.CodeAlreadyExists {
+		if errors.ErrorCode(err) == errors.CodeQuotaExceeded {
+			b.err = err
+			return b
+		} else if errors.ErrorCode(err) == errors.CodeAlreadyExists {
 			b.err = errors.E(err, fmt.Sprintf("model %s/%s already exists", b.owner.Name, b.name))
 			return b
 		} else {
@@ -376,11 +464,34 @@ func (b *modelBuilder) Cleanup() {
 	// the model should be deleted from the database regardless of the request
 	// context expiration
 	ctx := context.Background()
+	if b.modelInfo != nil {
+		// the model was created on the controller, so remove any
+		// relations added for it and destroy it.
+		b.destroyControllerModel(ctx)
+	}
 	if derr := b.jimm.Database.DeleteModel(ctx, b.model); derr != nil {
 		zapctx.Error(ctx, "failed to delete model", zap.String("model", b.model.Name), zap.String("owner", b.model.Owner.Name), zaputil.Error(derr))
 	}
 }
 
+// destroyControllerModel removes a partially created model from the
+// controller and from OpenFGA.
+func (b *modelBuilder) destroyControllerModel(ctx context.Context) {
+	mt := names.NewModelTag(b.modelInfo.UUID)
+	if err := b.jimm.OpenFGAClient.RemoveModel(ctx, mt); err != nil {
+		zapctx.Error(ctx, "failed to remove model relations", zap.String("model", mt.Id()), zaputil.Error(err))
+	}
+	api, err := b.jimm.dial(ctx, b.controller, names.ModelTag{})
+	if err != nil {
+		zapctx.Error(ctx, "leaked model", zap.String("model", mt.Id()), zaputil.Error(err))
+		return
+	}
+	defer api.Close()
+	if err := api.DestroyModel(ctx, mt, nil, nil, nil, nil); err != nil {
+		zapctx.Error(ctx, "leaked model", zap.String("model", mt.Id()), zaputil.Error(err))
+	}
+}
+
 func (b *modelBuilder) UpdateDatabaseModel() *modelBuilder {
 	if b.err != nil {
 		return b
@@ -468,6 +579,11 @@ func (b *modelBuilder) CreateControllerModel() *modelBuilder {
 		return b
 	}
 
+	if err := ControllerMaintenanceError(b.controller); err != nil {
+		b.err = err
+		return b
+	}
+
 	api, err := b.jimm.dial(
 		b.ctx,
 		b.controller,
@@ -548,6 +664,23 @@ func (b *modelBuilder) updateCredential(ctx context.Context, api API, cred *dbmo
 	return err
 }
 
+// AddModelPermissions grants the model owner administrator access to the
+// newly created model and relates the model to its controller.
+func (b *modelBuilder) AddModelPermissions() *modelBuilder {
+	if b.err != nil {
+		return b
+	}
+	if b.modelInfo == nil {
+		b.err = errors.E("model not created")
+		return b
+	}
+	owner := openfga.NewUser(b.owner, b.jimm.OpenFGAClient)
+	if err := b.jimm.addModelPermissions(b.ctx, owner, names.NewModelTag(b.modelInfo.UUID), b.controller.ResourceTag()); err != nil {
+		b.err = errors.E(err)
+	}
+	return b
+}
+
 // JujuModelInfo returns model information returned by the controller.
 func (b *modelBuilder) JujuModelInfo() *jujuparams.ModelInfo {
 	return b.modelInfo
@@ -576,16 +709,26 @@ func (j *JIMM) AddModel(ctx context.Context, user *openfga.User, args *ModelCrea
 	builder := newModelBuilder(ctx, j)
 	builder = builder.WithOwner(owner)
 	builder = builder.WithName(args.Name)
+	builder = builder.WithTTL(args.TTL)
+	builder = builder.WithTemplate(args.Template)
 	if err := builder.Error(); err != nil {
 		return nil, errors.E(op, err)
 	}
 
-	builder = builder.WithCloud(user, args.Cloud)
+	cloudTag, err := builder.templateCloud(args.Cloud)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	builder = builder.WithCloud(user, cloudTag)
 	if err := builder.Error(); err != nil {
 		return nil, errors.E(op, err)
 	}
 
-	builder = builder.WithCloudRegion(args.CloudRegion)
+	cloudRegion, err := builder.templateCloudRegion(args.CloudRegion)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	builder = builder.WithCloudRegion(cloudRegion)
 	if err := builder.Error(); err != nil {
 		return nil, errors.E(op, err)
 	}
@@ -622,12 +765,27 @@ func (j *JIMM) AddModel(ctx context.Context, user *openfga.User, args *ModelCrea
 		return nil, errors.E(op, errors.CodeUnauthorized, "unauthorized")
 	}
 
+	quotaLimits, err := j.modelQuotaLimits(ctx, owner.Name, builder.cloud.Name, builder.cloudRegion)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	builder = builder.WithModelQuotaLimits(quotaLimits)
+
 	// last but not least, use the provided config values
 	// overriding all defaults
 	builder = builder.WithConfig(args.Config)
+	// except for template config, which models created from the
+	// template must comply with
+	if builder.template != nil {
+		builder = builder.WithConfig(builder.template.Config)
+	}
 
-	if args.CloudCredential != (names.CloudCredentialTag{}) {
-		builder = builder.WithCloudCredential(args.CloudCredential)
+	credentialTag := args.CloudCredential
+	if credentialTag == (names.CloudCredentialTag{}) {
+		credentialTag = builder.templateCloudCredential()
+	}
+	if credentialTag != (names.CloudCredentialTag{}) {
+		builder = builder.WithCloudCredential(credentialTag)
 		if err := builder.Error(); err != nil {
 			return nil, errors.E(op, err)
 		}
@@ -638,6 +796,11 @@ func (j *JIMM) AddModel(ctx context.Context, user *openfga.User, args *ModelCrea
 	}
 	defer builder.Cleanup()
 
+	builder = builder.CheckModelPolicies()
+	if err := builder.Error(); err != nil {
+		return nil, errors.E(op, err)
+	}
+
 	builder = builder.CreateControllerModel()
 	if err := builder.Error(); err != nil {
 		return nil, errors.E(op, err)
@@ -648,15 +811,20 @@ func (j *JIMM) AddModel(ctx context.Context, user *openfga.User, args *ModelCrea
 		return nil, errors.E(op, err)
 	}
 
-	mi := builder.JujuModelInfo()
-
-	ownerUser := openfga.NewUser(owner, j.OpenFGAClient)
-	modelTag := names.NewModelTag(mi.UUID)
-	controllerTag := builder.controller.ResourceTag()
+	builder = builder.ApplyTemplateGrants()
+	if err := builder.Error(); err != nil {
+		return nil, errors.E(op, err)
+	}
 
-	if err := j.addModelPermissions(ctx, ownerUser, modelTag, controllerTag); err != nil {
+	builder = builder.AddModelPermissions()
+	if err := builder.Error(); err != nil {
 		return nil, errors.E(op, err)
 	}
+
+	mi := builder.JujuModelInfo()
+	m := *builder.model
+	m.Controller = *builder.controller
+	j.RecordModelEvent(ctx, dbmodel.WebhookEventModelCreated, &m, "")
 	return mi, nil
 }
 
@@ -678,7 +846,7 @@ func (j *JIMM) GetModel(ctx context.Context, uuid string) (dbmodel.Model, error)
 // addModelPermissions grants a user access to a model and sets the relation between the controller and model.
 // Call this when adding/importing a model to set the necessary permissions.
 func (j *JIMM) addModelPermissions(ctx context.Context, owner *openfga.User, mt names.ModelTag, ct names.ControllerTag) error {
-	if err := j.OpenFGAClient.AddControllerModel(ctx, ct, mt); err != nil {
+	if err := addControllerModel(j.OpenFGAClient, ctx, ct, mt); err != nil {
 		zapctx.Error(
 			ctx,
 			"failed to add controller->model relation",
@@ -753,7 +921,8 @@ func (m *modelSummariesMap) addModelSummary(summary jujuparams.ModelSummaryResul
 
 // ListModelSummaries returns the list of modelsummary the user has access to.
 // It queries the controllers and then merge the info from the JIMM db.
-func (j *JIMM) ListModelSummaries(ctx context.Context, user *openfga.User, maskingControllerUUID string) (jujuparams.ModelSummaryResults, error) {
+// Only models with labels matching the given selector are included.
+func (j *JIMM) ListModelSummaries(ctx context.Context, user *openfga.User, maskingControllerUUID string, labelSelector dbmodel.LabelSelector) (jujuparams.ModelSummaryResults, error) {
 	const op = errors.Op("jimm.ListModelSummaries")
 
 	modelSummariesSafeMap := modelSummariesMap{}
@@ -767,6 +936,9 @@ func (j *JIMM) ListModelSummaries(ctx context.Context, user *openfga.User, maski
 	var uniqueControllers []dbmodel.Controller
 	uniqueControllerMap := make(map[string]struct{}, 0)
 	err := j.ForEachUserModel(ctx, user, func(m *dbmodel.Model, uap jujuparams.UserAccessPermission) error {
+		if !labelSelector.Matches(m.Labels) {
+			return nil
+		}
 		models = append(models, struct {
 			model      *dbmodel.Model
 			userAccess jujuparams.UserAccessPermission
@@ -1081,7 +1253,7 @@ func (j *JIMM) RevokeModelAccess(ctx context.Context, user *openfga.User, mt nam
 		requiredAccess = ofganames.ReaderRelation
 	}
 
-	err = j.doModel(ctx, user, mt, requiredAccess, func(_ *dbmodel.Model, _ API) error {
+	err = j.doModel(ctx, user, mt, requiredAccess, false, func(_ *dbmodel.Model, _ API) error {
 		targetUser := &dbmodel.Identity{}
 		targetUser.SetTag(ut)
 		if err := j.Database.GetIdentity(ctx, targetUser); err != nil {
@@ -1153,7 +1325,9 @@ func (j *JIMM) DestroyModel(ctx context.Context, user *openfga.User, mt names.Mo
 	const op = errors.Op("jimm.DestroyModel")
 	zapctx.Info(ctx, string(op))
 
-	err := j.doModelAdmin(ctx, user, mt, func(m *dbmodel.Model, api API) error {
+	var model dbmodel.Model
+	err := j.doModelAdminOutsideMaintenance(ctx, user, mt, func(m *dbmodel.Model, api API) error {
+		model = *m
 		m.Life = state.Dying.String()
 		if err := j.Database.UpdateModel(ctx, m); err != nil {
 			zapctx.Error(ctx, "failed to store model change", zaputil.Error(err))
@@ -1174,6 +1348,7 @@ func (j *JIMM) DestroyModel(ctx context.Context, user *openfga.User, mt names.Mo
 	if err != nil {
 		return errors.E(op, err)
 	}
+	j.RecordModelEvent(ctx, dbmodel.WebhookEventModelDestroyed, &model, "")
 
 	// NOTE (alesstimec) If we remove OpenFGA relation now, the user
 	// will no longer be authorised to check for model status (which
@@ -1254,7 +1429,14 @@ func (j *JIMM) ValidateModelUpgrade(ctx context.Context, user *openfga.User, mt
 // returned from the dial operation. If the given function returns an error
 // that error will be returned with the code unmasked.
 func (j *JIMM) doModelAdmin(ctx context.Context, user *openfga.User, mt names.ModelTag, f func(*dbmodel.Model, API) error) error {
-	return j.doModel(ctx, user, mt, ofganames.AdministratorRelation, f)
+	return j.doModel(ctx, user, mt, ofganames.AdministratorRelation, false, f)
+}
+
+// doModelAdminOutsideMaintenance is like doModelAdmin except that if the
+// controller hosting the model is in maintenance an error with the code
+// CodeControllerMaintenance is returned without connecting to it.
+func (j *JIMM) doModelAdminOutsideMaintenance(ctx context.Context, user *openfga.User, mt names.ModelTag, f func(*dbmodel.Model, API) error) error {
+	return j.doModel(ctx, user, mt, ofganames.AdministratorRelation, true, f)
 }
 
 // GetUserModelAccess returns the access level a user has against a specific model.
@@ -1263,7 +1445,7 @@ func (j *JIMM) GetUserModelAccess(ctx context.Context, user *openfga.User, model
 	return ToModelAccessString(accessLevel), nil
 }
 
-func (j *JIMM) doModel(ctx context.Context, user *openfga.User, mt names.ModelTag, requireRelation openfga.Relation, f func(*dbmodel.Model, API) error) error {
+func (j *JIMM) doModel(ctx context.Context, user *openfga.User, mt names.ModelTag, requireRelation openfga.Relation, refuseMaintenance bool, f func(*dbmodel.Model, API) error) error {
 	const op = errors.Op("jimm.doModel")
 	zapctx.Info(ctx, string(op))
 
@@ -1285,6 +1467,12 @@ func (j *JIMM) doModel(ctx context.Context, user *openfga.User, mt names.ModelTa
 		return errors.E(op, errors.CodeUnauthorized, "unauthorized")
 	}
 
+	if refuseMaintenance {
+		if err := ControllerMaintenanceError(&m.Controller); err != nil {
+			return errors.E(op, err)
+		}
+	}
+
 	api, err := j.dial(ctx, &m.Controller, names.ModelTag{})
 	if err != nil {
 		return errors.E(op, err)
@@ -1314,37 +1502,44 @@ func (j *JIMM) ChangeModelCredential(ctx context.Context, user *openfga.User, mo
 		return errors.E(op, err)
 	}
 
+	if err := j.changeModelCredential(ctx, user, modelTag, &credential); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// changeModelCredential changes the credential used with a model to the
+// given credential on both the controller and the local database. The
+// user must be an administrator of the model, it is up to the caller to
+// check that the credential may be used.
+func (j *JIMM) changeModelCredential(ctx context.Context, user *openfga.User, modelTag names.ModelTag, credential *dbmodel.CloudCredential) error {
 	var m *dbmodel.Model
-	err = j.doModelAdmin(ctx, user, modelTag, func(model *dbmodel.Model, api API) error {
-		_, err = j.updateControllerCloudCredential(ctx, &credential, api.UpdateCredential)
+	err := j.doModelAdminOutsideMaintenance(ctx, user, modelTag, func(model *dbmodel.Model, api API) error {
+		_, err := j.updateControllerCloudCredential(ctx, credential, api.UpdateCredential)
 		if err != nil {
-			return errors.E(op, err)
+			return err
 		}
 
-		err = api.ChangeModelCredential(ctx, modelTag, cloudCredentialTag)
+		err = api.ChangeModelCredential(ctx, modelTag, credential.ResourceTag())
 		if err != nil {
-			return errors.E(op, err)
+			return err
 		}
 		m = model
 		return nil
 	})
 	if err != nil {
-		return errors.E(op, err)
+		return err
 	}
 
-	m.CloudCredential = credential
+	m.CloudCredential = *credential
 	m.CloudCredentialID = credential.ID
-	err = j.Database.UpdateModel(ctx, m)
-	if err != nil {
-		return errors.E(op, err)
-	}
-
-	return nil
+	return j.Database.UpdateModel(ctx, m)
 }
 
 // ListModels list the models that the user has access to. It intentionally excludes the
 // controller model as this call is used within the context of login and register commands.
-func (j *JIMM) ListModels(ctx context.Context, user *openfga.User) ([]base.UserModel, error) {
+// Only models with labels matching the given selector are included.
+func (j *JIMM) ListModels(ctx context.Context, user *openfga.User, labelSelector dbmodel.LabelSelector) ([]base.UserModel, error) {
 	const op = errors.Op("jimm.ListModels")
 	zapctx.Info(ctx, string(op))
 
@@ -1366,6 +1561,9 @@ func (j *JIMM) ListModels(ctx context.Context, user *openfga.User) ([]base.UserM
 	var controllers []dbmodel.Controller
 	seen := make(map[uint]bool)
 	for _, model := range models {
+		if !labelSelector.Matches(model.Labels) {
+			continue
+		}
 		modelsMap[model.UUID.String] = model // Set map for lookup
 		if seen[model.ControllerID] {
 			continue
@@ -37,9 +37,13 @@ func (j *JIMM) CleanupDyingModels(ctx context.Context) (err error) {
 		if err := api.ModelInfo(ctx, &jujuparams.ModelInfo{UUID: m.UUID.String}); err != nil {
 			// Some versions of juju return unauthorized for models that cannot be found.
 			if errors.ErrorCode(err) == errors.CodeNotFound || errors.ErrorCode(err) == errors.CodeUnauthorized {
+				if err := checkLeader(ctx); err != nil {
+					return err
+				}
 				if err := j.Database.DeleteModel(ctx, m); err != nil {
 					zapctx.Error(ctx, fmt.Sprintf("cannot delete model %s: %s\n", m.UUID.String, err))
 				} else {
+					j.RecordModelEvent(ctx, dbmodel.WebhookEventModelDead, m, "")
 					return nil
 				}
 			} else {
@@ -0,0 +1,215 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/names/v5"
+	"github.com/juju/zaputil"
+	"github.com/juju/zaputil/zapctx"
+	"go.uber.org/zap"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+// ModelTTLConfigKey is the model config key that may be given when
+// creating a model to make it ephemeral. The value is a duration, such as
+// "2h", after which the model is destroyed. The key is not passed on to
+// the juju controller.
+const ModelTTLConfigKey = "jimm-ttl"
+
+// ModelExpiryParams holds the parameters used when expiring ephemeral
+// models.
+type ModelExpiryParams struct {
+	// WarningPeriod is how long before a model expires that its owner
+	// is warned.
+	WarningPeriod time.Duration
+
+	// DestroyStorage determines whether the storage of an expired
+	// model is destroyed along with the model. If this is false and the
+	// model has persistent storage the model will not be destroyed.
+	DestroyStorage bool
+
+	// Force forces the destruction of expired models, ignoring any
+	// errors.
+	Force bool
+}
+
+// parseModelTTL parses the TTL given in model config.
+func parseModelTTL(v interface{}) (time.Duration, error) {
+	s, ok := v.(string)
+	if !ok {
+		return 0, errors.E(errors.CodeBadRequest, fmt.Sprintf("invalid %s: must be a duration", ModelTTLConfigKey))
+	}
+	ttl, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, errors.E(errors.CodeBadRequest, fmt.Sprintf("invalid %s: %s", ModelTTLConfigKey, err))
+	}
+	if ttl <= 0 {
+		return 0, errors.E(errors.CodeBadRequest, fmt.Sprintf("invalid %s: must be positive", ModelTTLConfigKey))
+	}
+	return ttl, nil
+}
+
+// SetModelTTL sets the model with the given tag to expire once the given
+// TTL has passed. This can be used to extend the life of an ephemeral
+// model, or to make an existing model ephemeral. The user must be an
+// administrator of the model. The new expiry time is returned.
+func (j *JIMM) SetModelTTL(ctx context.Context, user *openfga.User, mt names.ModelTag, ttl time.Duration) (time.Time, error) {
+	const op = errors.Op("jimm.SetModelTTL")
+
+	if ttl <= 0 {
+		return time.Time{}, errors.E(op, errors.CodeBadRequest, "ttl must be positive")
+	}
+	isAdministrator, err := openfga.IsAdministrator(ctx, user, mt)
+	if err != nil {
+		return time.Time{}, errors.E(op, err, errors.CodeOpenFGARequestFailed)
+	}
+	if !isAdministrator {
+		return time.Time{}, errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+
+	var m dbmodel.Model
+	m.SetTag(mt)
+	if err := j.Database.GetModel(ctx, &m); err != nil {
+		return time.Time{}, errors.E(op, err)
+	}
+	if m.Life == state.Dying.String() || m.Life == state.Dead.String() {
+		return time.Time{}, errors.E(op, errors.CodeBadRequest, "model is being destroyed")
+	}
+	m.ExpiresAt = sql.NullTime{
+		Time:  time.Now().UTC().Add(ttl).Round(time.Second),
+		Valid: true,
+	}
+	m.ExpiryWarningSent = false
+	if err := j.Database.UpdateModelExpiry(ctx, &m); err != nil {
+		return time.Time{}, errors.E(op, err)
+	}
+	return m.ExpiresAt.Time, nil
+}
+
+// ExpireModels warns the owners of ephemeral models that will expire
+// within the warning period and destroys the ephemeral models that have
+// expired. Expired models on controllers in maintenance are destroyed
+// once the maintenance is over. If any expired model cannot be destroyed
+// an error is returned after the remaining models have been processed.
+func (j *JIMM) ExpireModels(ctx context.Context, p ModelExpiryParams) (err error) {
+	const op = errors.Op("jimm.ExpireModels")
+	zapctx.Info(ctx, string(op))
+	durationObserver := servermon.DurationObserver(servermon.JimmMethodsDurationHistogram, string(op))
+	defer durationObserver()
+
+	now := time.Now().UTC()
+	models, err := j.Database.ListExpiringModels(ctx, now.Add(p.WarningPeriod))
+	if err != nil {
+		return errors.E(op, err)
+	}
+	var failed int
+	for i := range models {
+		m := &models[i]
+		if m.ExpiresAt.Time.After(now) {
+			if !m.ExpiryWarningSent {
+				j.warnModelExpiry(ctx, m)
+			}
+			continue
+		}
+		if m.Controller.Maintenance.Active(now) {
+			zapctx.Debug(ctx, "not destroying expired model on controller in maintenance", zap.String("model", m.UUID.String))
+			continue
+		}
+		if err := j.destroyExpiredModel(ctx, m, p); err != nil {
+			zapctx.Error(ctx, "cannot destroy expired model", zap.String("model", m.UUID.String), zaputil.Error(err))
+			failed++
+		}
+	}
+	if failed > 0 {
+		return errors.E(op, fmt.Sprintf("cannot destroy %d expired model(s)", failed))
+	}
+	return nil
+}
+
+// warnModelExpiry warns the owner of the given model that it is about to
+// expire. The warning is sent to webhooks subscribed to model-expiring
+// events, so that it can be passed on to the owner, and is recorded in
+// the audit log.
+func (j *JIMM) warnModelExpiry(ctx context.Context, m *dbmodel.Model) {
+	if err := checkLeader(ctx); err != nil {
+		zapctx.Error(ctx, "cannot warn of model expiry", zaputil.Error(err))
+		return
+	}
+	zapctx.Warn(ctx, "ephemeral model will expire soon",
+		zap.String("model", m.UUID.String),
+		zap.String("owner", m.OwnerIdentityName),
+		zap.Time("expires-at", m.ExpiresAt.Time),
+	)
+	j.RecordModelEvent(ctx, dbmodel.WebhookEventModelExpiring, m, fmt.Sprintf("model expires at %s", m.ExpiresAt.Time.Format(time.RFC3339)))
+	j.addModelExpiryAuditLogEntry(m, "ModelExpiryWarning")
+	m.ExpiryWarningSent = true
+	if err := j.Database.UpdateModelExpiry(ctx, m); err != nil {
+		zapctx.Error(ctx, "failed to store model change", zaputil.Error(err))
+	}
+}
+
+// destroyExpiredModel destroys the given expired model.
+func (j *JIMM) destroyExpiredModel(ctx context.Context, m *dbmodel.Model, p ModelExpiryParams) error {
+	api, err := j.dial(ctx, &m.Controller, names.ModelTag{})
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	if err := checkLeader(ctx); err != nil {
+		return err
+	}
+	updated, err := j.Database.MarkExpiredModelDying(ctx, m)
+	if err != nil {
+		return err
+	}
+	if !updated {
+		// The model's expiry has been extended, or it is already
+		// being destroyed, since it was listed.
+		zapctx.Debug(ctx, "not destroying model that is no longer expired", zap.String("model", m.UUID.String))
+		return nil
+	}
+	zapctx.Info(ctx, "destroying expired model", zap.String("model", m.UUID.String), zap.String("owner", m.OwnerIdentityName))
+	if err := api.DestroyModel(ctx, m.ResourceTag(), &p.DestroyStorage, &p.Force, nil, nil); err != nil {
+		m.Life = state.Alive.String()
+		if uerr := j.Database.UpdateModelLife(ctx, m); uerr != nil {
+			zapctx.Error(ctx, "failed to store model change", zaputil.Error(uerr))
+		}
+		return err
+	}
+	j.addModelExpiryAuditLogEntry(m, "ModelExpired")
+	j.RecordModelEvent(ctx, dbmodel.WebhookEventModelDestroyed, m, "model expired")
+	return nil
+}
+
+// addModelExpiryAuditLogEntry records an event in the lifecycle of an
+// ephemeral model in the audit log. The event is attributed to JIMM, which
+// acts on the model without any user being involved.
+func (j *JIMM) addModelExpiryAuditLogEntry(m *dbmodel.Model, method string) {
+	params, err := json.Marshal(map[string]interface{}{
+		"expires-at": m.ExpiresAt.Time,
+		"owner":      m.OwnerIdentityName,
+	})
+	if err != nil {
+		zapctx.Error(context.Background(), "failed to marshal audit log params", zap.Error(err))
+	}
+	j.AddAuditLogEntry(&dbmodel.AuditLogEntry{
+		Time:         time.Now().UTC().Round(time.Millisecond),
+		Model:        m.UUID.String,
+		FacadeName:   "JIMM",
+		FacadeMethod: method,
+		IdentityTag:  j.ResourceTag().String(),
+		Params:       params,
+	})
+}
@@ -0,0 +1,282 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/juju/state"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+func TestFromJujuModelCreateArgsTTL(t *testing.T) {
+	c := qt.New(t)
+
+	jujuArgs := jujuparams.ModelCreateArgs{
+		Name:     "model-1",
+		OwnerTag: names.NewUserTag("alice@canonical.com").String(),
+		Config: map[string]interface{}{
+			"jimm-ttl":       "2h",
+			"default-series": "jammy",
+		},
+	}
+	var args jimm.ModelCreateArgs
+	err := args.FromJujuModelCreateArgs(&jujuArgs)
+	c.Assert(err, qt.IsNil)
+	c.Check(args.TTL, qt.Equals, 2*time.Hour)
+	c.Check(args.Config, qt.DeepEquals, map[string]interface{}{"default-series": "jammy"})
+	// The original config is not modified.
+	c.Check(jujuArgs.Config["jimm-ttl"], qt.Equals, "2h")
+
+	for _, ttl := range []interface{}{"forever", "-1h", 3600} {
+		jujuArgs.Config["jimm-ttl"] = ttl
+		err = args.FromJujuModelCreateArgs(&jujuArgs)
+		c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest, qt.Commentf("ttl %v", ttl))
+	}
+}
+
+func TestAddModelWithTTL(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+	j, alice, _ := newAddModelTestJIMM(c, &addModelTestAPI{}, false)
+
+	args := addModelTestArgs
+	args.Config = map[string]interface{}{"jimm-ttl": "1h"}
+	before := time.Now().UTC()
+	_, err := addTestModel(ctx, j, alice, args)
+	c.Assert(err, qt.IsNil)
+
+	m := dbmodel.Model{
+		UUID: sql.NullString{String: "00000001-0000-0000-0000-0000-000000000002", Valid: true},
+	}
+	err = j.Database.GetModel(ctx, &m)
+	c.Assert(err, qt.IsNil)
+	c.Assert(m.ExpiresAt.Valid, qt.IsTrue)
+	c.Check(m.ExpiresAt.Time.Before(before.Add(time.Hour-time.Second)), qt.IsFalse)
+	c.Check(m.ExpiresAt.Time.After(time.Now().Add(time.Hour+time.Second)), qt.IsFalse)
+}
+
+func TestExpireModels(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	var mu sync.Mutex
+	var destroyed []string
+	var destroyStorage, force bool
+	j, alice, admin := newAddModelTestJIMM(c, &addModelTestAPI{
+		destroyModel: func(_ context.Context, mt names.ModelTag, ds, f *bool, _, _ *time.Duration) error {
+			mu.Lock()
+			defer mu.Unlock()
+			destroyed = append(destroyed, mt.Id())
+			destroyStorage, force = *ds, *f
+			return nil
+		},
+	}, true)
+	mt := names.NewModelTag("00000001-0000-0000-0000-0000-000000000001")
+	p := jimm.ModelExpiryParams{
+		WarningPeriod:  time.Hour,
+		DestroyStorage: true,
+	}
+
+	_, err := j.AddWebhookSubscription(ctx, admin, apiparams.AddWebhookSubscriptionRequest{
+		Name:       "owner-notifications",
+		URL:        "https://example.com/hook",
+		EventTypes: []string{dbmodel.WebhookEventModelExpiring, dbmodel.WebhookEventModelDestroyed},
+	})
+	c.Assert(err, qt.IsNil)
+	events := func() []apiparams.WebhookEvent {
+		deliveries, err := j.Database.ListDueWebhookDeliveries(ctx, time.Now().Add(time.Hour), 10)
+		c.Assert(err, qt.IsNil)
+		events := make([]apiparams.WebhookEvent, len(deliveries))
+		for i, wd := range deliveries {
+			err := json.Unmarshal(wd.Payload, &events[i])
+			c.Assert(err, qt.IsNil)
+		}
+		return events
+	}
+
+	// Models that do not expire are ignored.
+	err = j.ExpireModels(ctx, p)
+	c.Assert(err, qt.IsNil)
+	c.Check(destroyed, qt.HasLen, 0)
+
+	// Models expiring within the warning period are warned about once.
+	_, err = j.SetModelTTL(ctx, alice, mt, 30*time.Minute)
+	c.Assert(err, qt.IsNil)
+	err = j.ExpireModels(ctx, p)
+	c.Assert(err, qt.IsNil)
+	err = j.ExpireModels(ctx, p)
+	c.Assert(err, qt.IsNil)
+	c.Check(destroyed, qt.HasLen, 0)
+
+	m := dbmodel.Model{UUID: sql.NullString{String: mt.Id(), Valid: true}}
+	err = j.Database.GetModel(ctx, &m)
+	c.Assert(err, qt.IsNil)
+	c.Check(m.ExpiryWarningSent, qt.IsTrue)
+
+	var warnings []string
+	err = j.Database.ForEachAuditLogEntry(ctx, db.AuditLogFilter{Method: "ModelExpiryWarning"}, func(ale *dbmodel.AuditLogEntry) error {
+		warnings = append(warnings, ale.IdentityTag)
+		return nil
+	})
+	c.Assert(err, qt.IsNil)
+	// The warning is attributed to JIMM rather than the model's owner.
+	c.Check(warnings, qt.DeepEquals, []string{j.ResourceTag().String()})
+
+	queued := events()
+	c.Assert(queued, qt.HasLen, 1)
+	c.Check(queued[0].Type, qt.Equals, dbmodel.WebhookEventModelExpiring)
+	c.Check(queued[0].Model.Owner, qt.Equals, "alice@canonical.com")
+	c.Check(queued[0].Message, qt.Equals, "model expires at "+m.ExpiresAt.Time.Format(time.RFC3339))
+
+	// Expired models are destroyed.
+	m.ExpiresAt = sql.NullTime{Time: time.Now().UTC().Add(-time.Minute), Valid: true}
+	err = j.Database.UpdateModelExpiry(ctx, &m)
+	c.Assert(err, qt.IsNil)
+	err = j.ExpireModels(ctx, p)
+	c.Assert(err, qt.IsNil)
+	c.Check(destroyed, qt.DeepEquals, []string{mt.Id()})
+	c.Check(destroyStorage, qt.IsTrue)
+	c.Check(force, qt.IsFalse)
+
+	err = j.Database.GetModel(ctx, &m)
+	c.Assert(err, qt.IsNil)
+	c.Check(m.Life, qt.Equals, state.Dying.String())
+
+	queued = events()
+	c.Assert(queued, qt.HasLen, 2)
+	c.Check(queued[1].Type, qt.Equals, dbmodel.WebhookEventModelDestroyed)
+	c.Check(queued[1].Model.UUID, qt.Equals, mt.Id())
+	c.Check(queued[1].Message, qt.Equals, "model expired")
+
+	// Dying models are not destroyed again.
+	err = j.ExpireModels(ctx, p)
+	c.Assert(err, qt.IsNil)
+	c.Check(destroyed, qt.HasLen, 1)
+}
+
+func TestExpireModelsDestroyFails(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	var j *jimm.JIMM
+	mt := names.NewModelTag("00000001-0000-0000-0000-0000-000000000001")
+	j, alice, _ := newAddModelTestJIMM(c, &addModelTestAPI{
+		destroyModel: func(ctx context.Context, _ names.ModelTag, _, _ *bool, _, _ *time.Duration) error {
+			// Change the model while it is being destroyed.
+			m := dbmodel.Model{UUID: sql.NullString{String: mt.Id(), Valid: true}}
+			err := j.Database.GetModel(ctx, &m)
+			c.Assert(err, qt.IsNil)
+			err = j.Database.UpdateModelLabels(ctx, &m, map[string]string{"team": "a"}, nil)
+			c.Assert(err, qt.IsNil)
+			return errors.E("model has persistent storage")
+		},
+	}, false)
+	_, err := j.SetModelTTL(ctx, alice, mt, time.Hour)
+	c.Assert(err, qt.IsNil)
+
+	m := dbmodel.Model{UUID: sql.NullString{String: mt.Id(), Valid: true}}
+	err = j.Database.GetModel(ctx, &m)
+	c.Assert(err, qt.IsNil)
+	m.ExpiresAt = sql.NullTime{Time: time.Now().UTC().Add(-time.Minute), Valid: true}
+	err = j.Database.UpdateModelExpiry(ctx, &m)
+	c.Assert(err, qt.IsNil)
+
+	err = j.ExpireModels(ctx, jimm.ModelExpiryParams{})
+	c.Check(err, qt.ErrorMatches, `cannot destroy 1 expired model\(s\)`)
+
+	// The model is restored without losing the changes made while it
+	// was being destroyed.
+	err = j.Database.GetModel(ctx, &m)
+	c.Assert(err, qt.IsNil)
+	c.Check(m.Life, qt.Equals, state.Alive.String())
+	c.Check(m.Labels, qt.DeepEquals, dbmodel.StringMap{"team": "a"})
+}
+
+func TestSetModelTTL(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+	j, alice, _ := newAddModelTestJIMM(c, &addModelTestAPI{}, false)
+	mt := names.NewModelTag("00000001-0000-0000-0000-0000-000000000001")
+
+	_, err := j.SetModelTTL(ctx, alice, mt, 0)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+
+	charlie, err := dbmodel.NewIdentity("charlie@canonical.com")
+	c.Assert(err, qt.IsNil)
+	err = j.Database.GetIdentity(ctx, charlie)
+	c.Assert(err, qt.IsNil)
+	_, err = j.SetModelTTL(ctx, openfga.NewUser(charlie, j.OpenFGAClient), mt, time.Hour)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+
+	before := time.Now().UTC()
+	expiresAt, err := j.SetModelTTL(ctx, alice, mt, 24*time.Hour)
+	c.Assert(err, qt.IsNil)
+	c.Check(expiresAt.Before(before.Add(24*time.Hour-time.Second)), qt.IsFalse)
+
+	m := dbmodel.Model{UUID: sql.NullString{String: mt.Id(), Valid: true}}
+	err = j.Database.GetModel(ctx, &m)
+	c.Assert(err, qt.IsNil)
+	c.Check(m.ExpiresAt.Time.Equal(expiresAt), qt.IsTrue)
+	c.Check(m.ExpiryWarningSent, qt.IsFalse)
+}
+
+func TestExpireModelsStaleLeader(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	var destroyed []string
+	j, alice, _ := newAddModelTestJIMM(c, &addModelTestAPI{
+		destroyModel: func(_ context.Context, mt names.ModelTag, _, _ *bool, _, _ *time.Duration) error {
+			destroyed = append(destroyed, mt.Id())
+			return nil
+		},
+	}, false)
+	mt := names.NewModelTag("00000001-0000-0000-0000-0000-000000000001")
+	_, err := j.SetModelTTL(ctx, alice, mt, time.Hour)
+	c.Assert(err, qt.IsNil)
+	m := dbmodel.Model{UUID: sql.NullString{String: mt.Id(), Valid: true}}
+	err = j.Database.GetModel(ctx, &m)
+	c.Assert(err, qt.IsNil)
+	m.ExpiresAt = sql.NullTime{Time: time.Now().UTC().Add(-time.Minute), Valid: true}
+	err = j.Database.UpdateModelExpiry(ctx, &m)
+	c.Assert(err, qt.IsNil)
+
+	// Take a context from a term of leadership that has ended, as
+	// though the job had not yet noticed leadership being lost.
+	e := &jimm.LeaderElector{
+		Database:      j.Database,
+		Holder:        "replica-1",
+		LeaseDuration: 300 * time.Millisecond,
+	}
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var leaderCtx context.Context
+	err = e.Run(runCtx, func(ctx context.Context) error {
+		leaderCtx = context.WithoutCancel(ctx)
+		cancel()
+		return nil
+	})
+	c.Assert(err, qt.IsNil)
+
+	err = j.ExpireModels(leaderCtx, jimm.ModelExpiryParams{})
+	c.Check(err, qt.ErrorMatches, `cannot destroy 1 expired model\(s\)`)
+	c.Check(destroyed, qt.HasLen, 0)
+	err = j.Database.GetModel(ctx, &m)
+	c.Assert(err, qt.IsNil)
+	c.Check(m.Life, qt.Equals, state.Alive.String())
+}
@@ -0,0 +1,88 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+)
+
+// SetModelLabels sets the given labels on the model with the given tag.
+// Existing labels with the same keys are replaced, other labels are left
+// unchanged. The user must be an administrator of the model.
+func (j *JIMM) SetModelLabels(ctx context.Context, user *openfga.User, mt names.ModelTag, labels map[string]string) error {
+	const op = errors.Op("jimm.SetModelLabels")
+
+	for k, v := range labels {
+		if err := dbmodel.ValidateLabelKey(k); err != nil {
+			return errors.E(op, err)
+		}
+		if err := dbmodel.ValidateLabelValue(v); err != nil {
+			return errors.E(op, err)
+		}
+	}
+	err := j.updateModelLabels(ctx, user, mt, labels, nil)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// RemoveModelLabels removes the labels with the given keys from the model
+// with the given tag. Keys that are not set on the model are ignored. The
+// user must be an administrator of the model.
+func (j *JIMM) RemoveModelLabels(ctx context.Context, user *openfga.User, mt names.ModelTag, keys []string) error {
+	const op = errors.Op("jimm.RemoveModelLabels")
+
+	err := j.updateModelLabels(ctx, user, mt, nil, keys)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// GetModelLabels returns the labels of the model with the given tag. The
+// user must have read access to the model.
+func (j *JIMM) GetModelLabels(ctx context.Context, user *openfga.User, mt names.ModelTag) (map[string]string, error) {
+	const op = errors.Op("jimm.GetModelLabels")
+
+	isReader, err := user.IsModelReader(ctx, mt)
+	if err != nil {
+		return nil, errors.E(op, err, errors.CodeOpenFGARequestFailed)
+	}
+	if !isReader {
+		return nil, errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+
+	var m dbmodel.Model
+	m.SetTag(mt)
+	if err := j.Database.GetModel(ctx, &m); err != nil {
+		return nil, errors.E(op, err)
+	}
+	labels := make(map[string]string, len(m.Labels))
+	for k, v := range m.Labels {
+		labels[k] = v
+	}
+	return labels, nil
+}
+
+// updateModelLabels checks the user is an administrator of the model with
+// the given tag and then sets and removes the given labels of the model.
+func (j *JIMM) updateModelLabels(ctx context.Context, user *openfga.User, mt names.ModelTag, set map[string]string, remove []string) error {
+	isAdministrator, err := openfga.IsAdministrator(ctx, user, mt)
+	if err != nil {
+		return errors.E(err, errors.CodeOpenFGARequestFailed)
+	}
+	if !isAdministrator {
+		return errors.E(errors.CodeUnauthorized, "unauthorized")
+	}
+
+	var m dbmodel.Model
+	m.SetTag(mt)
+	return j.Database.UpdateModelLabels(ctx, &m, set, remove)
+}
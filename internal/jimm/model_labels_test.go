@@ -0,0 +1,149 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/juju/juju/api/base"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/openfga"
+)
+
+const modelLabelsTestEnv = `clouds:
+- name: test-cloud
+  type: test-provider
+  regions:
+  - name: test-cloud-region
+cloud-credentials:
+- owner: alice@canonical.com
+  name: cred-1
+  cloud: test-cloud
+controllers:
+- name: controller-1
+  uuid: 00000001-0000-0000-0000-000000000001
+  cloud: test-cloud
+  region: test-cloud-region
+models:
+- name: model-1
+  uuid: 00000002-0000-0000-0000-000000000001
+  controller: controller-1
+  cloud: test-cloud
+  region: test-cloud-region
+  cloud-credential: cred-1
+  owner: alice@canonical.com
+  users:
+  - user: alice@canonical.com
+    access: admin
+  - user: bob@canonical.com
+    access: read
+- name: model-2
+  uuid: 00000002-0000-0000-0000-000000000002
+  controller: controller-1
+  cloud: test-cloud
+  region: test-cloud-region
+  cloud-credential: cred-1
+  owner: alice@canonical.com
+  users:
+  - user: alice@canonical.com
+    access: admin
+`
+
+func newModelLabelsTestJIMM(c *qt.C) (*jimm.JIMM, *openfga.User, *openfga.User) {
+	// bob only has the access granted in the environment.
+	return newAddModelTestJIMM(c, &addModelTestAPI{
+		env: modelLabelsTestEnv,
+		models: []base.UserModel{
+			{Name: "model-1", UUID: "00000002-0000-0000-0000-000000000001"},
+			{Name: "model-2", UUID: "00000002-0000-0000-0000-000000000002"},
+		},
+	}, false)
+}
+
+func TestModelLabels(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+	j, alice, bob := newModelLabelsTestJIMM(c)
+	mt := names.NewModelTag("00000002-0000-0000-0000-000000000001")
+
+	labels, err := j.GetModelLabels(ctx, alice, mt)
+	c.Assert(err, qt.IsNil)
+	c.Check(labels, qt.DeepEquals, map[string]string{})
+
+	err = j.SetModelLabels(ctx, alice, mt, map[string]string{"env": "prod", "team": "ops"})
+	c.Assert(err, qt.IsNil)
+	err = j.SetModelLabels(ctx, alice, mt, map[string]string{"env": "staging", "service": "web"})
+	c.Assert(err, qt.IsNil)
+
+	// Readers may see the labels, but not change them.
+	labels, err = j.GetModelLabels(ctx, bob, mt)
+	c.Assert(err, qt.IsNil)
+	c.Check(labels, qt.DeepEquals, map[string]string{"env": "staging", "team": "ops", "service": "web"})
+	err = j.SetModelLabels(ctx, bob, mt, map[string]string{"env": "prod"})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+	err = j.RemoveModelLabels(ctx, bob, mt, []string{"env"})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+
+	err = j.RemoveModelLabels(ctx, alice, mt, []string{"team", "no-such-label"})
+	c.Assert(err, qt.IsNil)
+	labels, err = j.GetModelLabels(ctx, alice, mt)
+	c.Assert(err, qt.IsNil)
+	c.Check(labels, qt.DeepEquals, map[string]string{"env": "staging", "service": "web"})
+
+	// Bob has no access to the second model.
+	_, err = j.GetModelLabels(ctx, bob, names.NewModelTag("00000002-0000-0000-0000-000000000002"))
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+}
+
+func TestSetModelLabelsInvalid(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+	j, alice, _ := newModelLabelsTestJIMM(c)
+	mt := names.NewModelTag("00000002-0000-0000-0000-000000000001")
+
+	err := j.SetModelLabels(ctx, alice, mt, map[string]string{"not a key": "x"})
+	c.Check(err, qt.ErrorMatches, `invalid label key "not a key"`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+	err = j.SetModelLabels(ctx, alice, mt, map[string]string{"env": "a,b"})
+	c.Check(err, qt.ErrorMatches, `invalid label value "a,b"`)
+}
+
+func TestListModelsByLabel(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+	j, alice, _ := newModelLabelsTestJIMM(c)
+
+	err := j.SetModelLabels(ctx, alice, names.NewModelTag("00000002-0000-0000-0000-000000000001"), map[string]string{"env": "prod"})
+	c.Assert(err, qt.IsNil)
+	err = j.SetModelLabels(ctx, alice, names.NewModelTag("00000002-0000-0000-0000-000000000002"), map[string]string{"env": "staging"})
+	c.Assert(err, qt.IsNil)
+
+	sel, err := dbmodel.ParseLabelSelector("env=prod")
+	c.Assert(err, qt.IsNil)
+
+	models, err := j.ListModels(ctx, alice, sel)
+	c.Assert(err, qt.IsNil)
+	c.Assert(models, qt.HasLen, 1)
+	c.Check(models[0].UUID, qt.Equals, "00000002-0000-0000-0000-000000000001")
+
+	models, err = j.ListModels(ctx, alice, nil)
+	c.Assert(err, qt.IsNil)
+	c.Check(models, qt.HasLen, 2)
+
+	summaries, err := j.ListModelSummaries(ctx, alice, "", sel)
+	c.Assert(err, qt.IsNil)
+	c.Assert(summaries.Results, qt.HasLen, 1)
+	c.Check(summaries.Results[0].Result.UUID, qt.Equals, "00000002-0000-0000-0000-000000000001")
+
+	sel, err = dbmodel.ParseLabelSelector("!env")
+	c.Assert(err, qt.IsNil)
+	summaries, err = j.ListModelSummaries(ctx, alice, "", sel)
+	c.Assert(err, qt.IsNil)
+	c.Check(summaries.Results, qt.HasLen, 0)
+}
@@ -0,0 +1,171 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/juju/names/v5"
+	"github.com/juju/zaputil/zapctx"
+	"go.uber.org/zap"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
+)
+
+var (
+	// addModelOwnerRelation and removeModelOwnerRelation write the
+	// model ownership relations, they are variables so that tests can
+	// inject failures.
+	addModelOwnerRelation    = (*openfga.OFGAClient).AddRelation
+	removeModelOwnerRelation = (*openfga.OFGAClient).RemoveRelation
+)
+
+// TransferModelOwnership makes the identity with the given name the owner
+// of the given model. The new owner is given administrator access to the
+// model, and the previous owner's direct administrator access is removed.
+// If a cloud credential is given, it must belong to the new owner, and
+// the model is switched to use it once the owner has been changed. If the
+// credential cannot be changed the ownership change is undone. Only
+// administrators of the model, which includes administrators of the
+// model's controller, may transfer its ownership. If the new owner already
+// owns a model with the same name an error with a code of
+// CodeAlreadyExists is returned.
+func (j *JIMM) TransferModelOwnership(ctx context.Context, user *openfga.User, mt names.ModelTag, newOwner string, cloudCredentialTag names.CloudCredentialTag) error {
+	const op = errors.Op("jimm.TransferModelOwnership")
+	zapctx.Info(ctx, string(op))
+
+	m := dbmodel.Model{}
+	m.SetTag(mt)
+	if err := j.Database.GetModel(ctx, &m); err != nil {
+		return errors.E(op, err)
+	}
+	isAdmin, err := user.HasModelRelation(ctx, mt, ofganames.AdministratorRelation)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	if !isAdmin {
+		return errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+
+	owner := &dbmodel.Identity{Name: newOwner}
+	if err := j.Database.FetchIdentity(ctx, owner); err != nil {
+		return errors.E(op, err)
+	}
+	if owner.Name == m.OwnerIdentityName {
+		return errors.E(op, errors.CodeBadRequest, fmt.Sprintf("model is already owned by %s", owner.Name))
+	}
+	previousOwner := m.Owner
+
+	var credential *dbmodel.CloudCredential
+	if !cloudCredentialTag.IsZero() {
+		if cloudCredentialTag.Owner().Id() != owner.Name {
+			return errors.E(op, errors.CodeBadRequest, fmt.Sprintf("cloud credential %s is not owned by %s", cloudCredentialTag.Id(), owner.Name))
+		}
+		credential = &dbmodel.CloudCredential{}
+		credential.SetTag(cloudCredentialTag)
+		if err := j.Database.GetCloudCredential(ctx, credential); err != nil {
+			return errors.E(op, err)
+		}
+		// Changing the credential requires the model's controller.
+		if err := ControllerMaintenanceError(&m.Controller); err != nil {
+			return errors.E(op, err)
+		}
+	}
+
+	// The new owner is granted access before the owner is changed, and
+	// the grant is undone if the change fails, so that the model's owner
+	// always has administrator access.
+	newOwnerTuple := modelAdministratorTuple(owner, mt)
+	granted := true
+	if err := addModelOwnerRelation(j.OpenFGAClient, ctx, newOwnerTuple); err != nil {
+		if !strings.Contains(err.Error(), "cannot write a tuple which already exists") {
+			return errors.E(op, err, "failed to grant the new owner administrator access")
+		}
+		granted = false
+	}
+	revokeGrant := func() {
+		if !granted {
+			return
+		}
+		if err := removeModelOwnerRelation(j.OpenFGAClient, ctx, newOwnerTuple); err != nil {
+			zapctx.Error(ctx, "failed to remove the new owner's administrator access", zap.String("model", mt.Id()), zap.String("owner", owner.Name), zap.Error(err))
+		}
+	}
+
+	m.SetOwner(owner)
+	if err := j.Database.UpdateModelOwner(ctx, &m); err != nil {
+		revokeGrant()
+		if errors.ErrorCode(err) == errors.CodeAlreadyExists {
+			return errors.E(op, err, fmt.Sprintf("%s already owns a model named %s", owner.Name, m.Name))
+		}
+		return errors.E(op, err)
+	}
+
+	// The credential is changed once the model belongs to the new owner,
+	// if it cannot be changed the model is given back to the previous
+	// owner.
+	if credential != nil {
+		if err := j.changeModelCredential(ctx, user, mt, credential); err != nil {
+			m.SetOwner(&previousOwner)
+			if uerr := j.Database.UpdateModelOwner(ctx, &m); uerr != nil {
+				zapctx.Error(ctx, "failed to restore the model's previous owner", zap.String("model", mt.Id()), zap.String("owner", previousOwner.Name), zap.Error(uerr))
+				return errors.E(op, err)
+			}
+			revokeGrant()
+			return errors.E(op, err)
+		}
+	}
+
+	if err := removeModelOwnerRelation(j.OpenFGAClient, ctx, modelAdministratorTuple(&previousOwner, mt)); err != nil {
+		if !strings.Contains(err.Error(), "cannot delete a tuple which does not exist") {
+			// The transfer has happened, so it is recorded even
+			// though the previous owner keeps their access.
+			j.addModelOwnershipAuditLogEntry(user, &m, previousOwner.Name, cloudCredentialTag)
+			return errors.E(op, err, "failed to revoke the previous owner's administrator access")
+		}
+	}
+
+	j.addModelOwnershipAuditLogEntry(user, &m, previousOwner.Name, cloudCredentialTag)
+	return nil
+}
+
+// modelAdministratorTuple returns the tuple that gives the given identity
+// administrator access to the given model.
+func modelAdministratorTuple(i *dbmodel.Identity, mt names.ModelTag) openfga.Tuple {
+	return openfga.Tuple{
+		Object:   ofganames.ConvertTag(i.ResourceTag()),
+		Relation: ofganames.AdministratorRelation,
+		Target:   ofganames.ConvertTag(mt),
+	}
+}
+
+// addModelOwnershipAuditLogEntry records the transfer of a model's
+// ownership in the audit log.
+func (j *JIMM) addModelOwnershipAuditLogEntry(user *openfga.User, m *dbmodel.Model, previousOwner string, cloudCredentialTag names.CloudCredentialTag) {
+	p := map[string]interface{}{
+		"previous-owner": previousOwner,
+		"new-owner":      m.OwnerIdentityName,
+	}
+	if !cloudCredentialTag.IsZero() {
+		p["cloud-credential"] = cloudCredentialTag.Id()
+	}
+	params, err := json.Marshal(p)
+	if err != nil {
+		zapctx.Error(context.Background(), "failed to marshal audit log params", zap.Error(err))
+	}
+	j.AddAuditLogEntry(&dbmodel.AuditLogEntry{
+		Time:         time.Now().UTC().Round(time.Millisecond),
+		Model:        m.UUID.String,
+		FacadeName:   "JIMM",
+		FacadeMethod: "TransferModelOwnership",
+		IdentityTag:  user.Tag().String(),
+		Params:       params,
+	})
+}
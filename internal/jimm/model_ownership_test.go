@@ -0,0 +1,243 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
+	"github.com/canonical/jimm/v3/internal/testutils/jimmtest"
+)
+
+const transferModelOwnershipTestEnv = `clouds:
+- name: test-cloud
+  type: test-provider
+  regions:
+  - name: test-region-1
+cloud-credentials:
+- name: cred-1
+  owner: alice@canonical.com
+  cloud: test-cloud
+  auth-type: empty
+- name: cred-1
+  owner: bob@canonical.com
+  cloud: test-cloud
+  auth-type: empty
+- name: cred-1
+  owner: charlie@canonical.com
+  cloud: test-cloud
+  auth-type: empty
+controllers:
+- name: controller-1
+  uuid: 00000000-0000-0000-0000-0000-0000000000001
+  cloud: test-cloud
+  region: test-region-1
+models:
+- name: model-1
+  owner: alice@canonical.com
+  uuid: 00000002-0000-0000-0000-000000000001
+  cloud: test-cloud
+  region: test-region-1
+  cloud-credential: cred-1
+  controller: controller-1
+  life: alive
+  users:
+  - user: alice@canonical.com
+    access: admin
+- name: model-1
+  owner: charlie@canonical.com
+  uuid: 00000002-0000-0000-0000-000000000002
+  cloud: test-cloud
+  region: test-region-1
+  cloud-credential: cred-1
+  controller: controller-1
+  life: alive
+  users:
+  - user: charlie@canonical.com
+    access: admin
+users:
+- username: alice@canonical.com
+  controller-access: login
+- username: bob@canonical.com
+  controller-access: login
+- username: charlie@canonical.com
+  controller-access: login
+`
+
+func TestTransferModelOwnership(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	var changedCredential string
+	var changeCredentialErr error
+	j := jimmtest.NewJIMM(c, &jimm.Parameters{
+		Dialer: &jimmtest.Dialer{
+			API: &jimmtest.API{
+				UpdateCredential_: func(context.Context, jujuparams.TaggedCredential) ([]jujuparams.UpdateCredentialModelResult, error) {
+					return nil, nil
+				},
+				ChangeModelCredential_: func(_ context.Context, _ names.ModelTag, credentialTag names.CloudCredentialTag) error {
+					if changeCredentialErr != nil {
+						return changeCredentialErr
+					}
+					changedCredential = credentialTag.Id()
+					return nil
+				},
+			},
+		},
+	})
+
+	env := jimmtest.ParseEnvironment(c, transferModelOwnershipTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, j.OpenFGAClient)
+	aliceIdentity := env.User("alice@canonical.com").DBObject(c, j.Database)
+	alice := openfga.NewUser(&aliceIdentity, j.OpenFGAClient)
+	bobIdentity := env.User("bob@canonical.com").DBObject(c, j.Database)
+	bob := openfga.NewUser(&bobIdentity, j.OpenFGAClient)
+
+	mt := names.NewModelTag("00000002-0000-0000-0000-000000000001")
+
+	err := j.TransferModelOwnership(ctx, bob, mt, "bob@canonical.com", names.CloudCredentialTag{})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+
+	err = j.TransferModelOwnership(ctx, alice, mt, "alice@canonical.com", names.CloudCredentialTag{})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+
+	err = j.TransferModelOwnership(ctx, alice, mt, "dave@canonical.com", names.CloudCredentialTag{})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	// charlie already owns a model called model-1.
+	err = j.TransferModelOwnership(ctx, alice, mt, "charlie@canonical.com", names.CloudCredentialTag{})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeAlreadyExists)
+	c.Check(err, qt.ErrorMatches, `charlie@canonical.com already owns a model named model-1`)
+	// The access granted to charlie is removed again.
+	charlieIdentity := env.User("charlie@canonical.com").DBObject(c, j.Database)
+	charlie := openfga.NewUser(&charlieIdentity, j.OpenFGAClient)
+	c.Check(charlie.GetModelAccess(ctx, mt), qt.Equals, ofganames.NoRelation)
+
+	err = j.TransferModelOwnership(ctx, alice, mt, "bob@canonical.com", names.CloudCredentialTag{})
+	c.Assert(err, qt.IsNil)
+	c.Check(changedCredential, qt.Equals, "")
+
+	m := dbmodel.Model{UUID: sql.NullString{String: mt.Id(), Valid: true}}
+	err = j.Database.GetModel(ctx, &m)
+	c.Assert(err, qt.IsNil)
+	c.Check(m.OwnerIdentityName, qt.Equals, "bob@canonical.com")
+	c.Check(bob.GetModelAccess(ctx, mt), qt.Equals, ofganames.AdministratorRelation)
+	c.Check(alice.GetModelAccess(ctx, mt), qt.Equals, ofganames.NoRelation)
+
+	// The credential must belong to the new owner.
+	err = j.TransferModelOwnership(ctx, bob, mt, "alice@canonical.com", names.NewCloudCredentialTag("test-cloud/bob@canonical.com/cred-1"))
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+	c.Check(err, qt.ErrorMatches, `cloud credential test-cloud/bob@canonical.com/cred-1 is not owned by alice@canonical.com`)
+
+	// If the credential cannot be changed the model is given back to
+	// its previous owner.
+	changeCredentialErr = errors.E("credential invalid")
+	err = j.TransferModelOwnership(ctx, bob, mt, "alice@canonical.com", names.NewCloudCredentialTag("test-cloud/alice@canonical.com/cred-1"))
+	c.Check(err, qt.ErrorMatches, `credential invalid`)
+	changeCredentialErr = nil
+	m = dbmodel.Model{UUID: sql.NullString{String: mt.Id(), Valid: true}}
+	err = j.Database.GetModel(ctx, &m)
+	c.Assert(err, qt.IsNil)
+	c.Check(m.OwnerIdentityName, qt.Equals, "bob@canonical.com")
+	c.Check(m.CloudCredential.OwnerIdentityName, qt.Equals, "alice@canonical.com")
+	c.Check(bob.GetModelAccess(ctx, mt), qt.Equals, ofganames.AdministratorRelation)
+	c.Check(alice.GetModelAccess(ctx, mt), qt.Equals, ofganames.NoRelation)
+
+	// The new owner, who is not a JIMM administrator, can transfer the
+	// model back, switching to a credential of the model's new owner.
+	err = j.TransferModelOwnership(ctx, bob, mt, "alice@canonical.com", names.NewCloudCredentialTag("test-cloud/alice@canonical.com/cred-1"))
+	c.Assert(err, qt.IsNil)
+	c.Check(changedCredential, qt.Equals, "test-cloud/alice@canonical.com/cred-1")
+
+	m = dbmodel.Model{UUID: sql.NullString{String: mt.Id(), Valid: true}}
+	err = j.Database.GetModel(ctx, &m)
+	c.Assert(err, qt.IsNil)
+	c.Check(m.OwnerIdentityName, qt.Equals, "alice@canonical.com")
+	c.Check(m.CloudCredential.Name, qt.Equals, "cred-1")
+	c.Check(m.CloudCredential.OwnerIdentityName, qt.Equals, "alice@canonical.com")
+
+	var transfers []map[string]string
+	err = j.Database.ForEachAuditLogEntry(ctx, db.AuditLogFilter{Method: "TransferModelOwnership"}, func(ale *dbmodel.AuditLogEntry) error {
+		var p map[string]string
+		if err := json.Unmarshal(ale.Params, &p); err != nil {
+			return err
+		}
+		p["identity"] = ale.IdentityTag
+		p["model"] = ale.Model
+		transfers = append(transfers, p)
+		return nil
+	})
+	c.Assert(err, qt.IsNil)
+	c.Check(transfers, qt.DeepEquals, []map[string]string{{
+		"identity":       "user-alice@canonical.com",
+		"model":          mt.Id(),
+		"previous-owner": "alice@canonical.com",
+		"new-owner":      "bob@canonical.com",
+	}, {
+		"identity":         "user-bob@canonical.com",
+		"model":            mt.Id(),
+		"previous-owner":   "bob@canonical.com",
+		"new-owner":        "alice@canonical.com",
+		"cloud-credential": "test-cloud/alice@canonical.com/cred-1",
+	}})
+}
+
+func TestTransferModelOwnershipOpenFGAFailure(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	j := jimmtest.NewJIMM(c, nil)
+
+	env := jimmtest.ParseEnvironment(c, transferModelOwnershipTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, j.OpenFGAClient)
+	aliceIdentity := env.User("alice@canonical.com").DBObject(c, j.Database)
+	alice := openfga.NewUser(&aliceIdentity, j.OpenFGAClient)
+	bobIdentity := env.User("bob@canonical.com").DBObject(c, j.Database)
+	bob := openfga.NewUser(&bobIdentity, j.OpenFGAClient)
+
+	mt := names.NewModelTag("00000002-0000-0000-0000-000000000001")
+	owner := func() string {
+		m := dbmodel.Model{UUID: sql.NullString{String: mt.Id(), Valid: true}}
+		err := j.Database.GetModel(ctx, &m)
+		c.Assert(err, qt.IsNil)
+		return m.OwnerIdentityName
+	}
+
+	c.Run("grant fails", func(c *qt.C) {
+		// If the new owner cannot be granted access the owner is
+		// unchanged.
+		c.Patch(jimm.AddModelOwnerRelation, func(*openfga.OFGAClient, context.Context, ...openfga.Tuple) error {
+			return errors.E("openfga unavailable")
+		})
+		err := j.TransferModelOwnership(ctx, alice, mt, "bob@canonical.com", names.CloudCredentialTag{})
+		c.Check(err, qt.ErrorMatches, `failed to grant the new owner administrator access`)
+	})
+	c.Check(owner(), qt.Equals, "alice@canonical.com")
+	c.Check(bob.GetModelAccess(ctx, mt), qt.Equals, ofganames.NoRelation)
+	c.Check(alice.GetModelAccess(ctx, mt), qt.Equals, ofganames.AdministratorRelation)
+
+	c.Run("revoke fails", func(c *qt.C) {
+		// If the previous owner's access cannot be revoked the
+		// transfer has still happened.
+		c.Patch(jimm.RemoveModelOwnerRelation, func(*openfga.OFGAClient, context.Context, ...openfga.Tuple) error {
+			return errors.E("openfga unavailable")
+		})
+		err := j.TransferModelOwnership(ctx, alice, mt, "bob@canonical.com", names.CloudCredentialTag{})
+		c.Check(err, qt.ErrorMatches, `failed to revoke the previous owner's administrator access`)
+	})
+	c.Check(owner(), qt.Equals, "bob@canonical.com")
+	c.Check(bob.GetModelAccess(ctx, mt), qt.Equals, ofganames.AdministratorRelation)
+}
@@ -25,7 +25,9 @@ import (
 // If a result is erroneous, for example, bad data type parsing, the resulting struct field
 // Errors will contain a map from model UUID -> []error. Otherwise, the Results field
 // will contain model UUID -> []Jq result.
-func (j *JIMM) QueryModelsJq(ctx context.Context, modelUUIDs []string, jqQuery string) (params.CrossModelQueryResponse, error) {
+//
+// Only models with labels matching the given selector are queried.
+func (j *JIMM) QueryModelsJq(ctx context.Context, modelUUIDs []string, jqQuery string, labelSelector dbmodel.LabelSelector) (params.CrossModelQueryResponse, error) {
 	op := errors.Op("QueryModels")
 	results := params.CrossModelQueryResponse{
 		Results: make(map[string][]any),
@@ -47,6 +49,9 @@ func (j *JIMM) QueryModelsJq(ctx context.Context, modelUUIDs []string, jqQuery s
 	}
 
 	for _, model := range models {
+		if !labelSelector.Matches(model.Labels) {
+			continue
+		}
 		modelUUID := model.UUID.String
 		params, err := retriever.GetParams(ctx, model)
 		if err != nil {
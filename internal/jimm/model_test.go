@@ -1995,7 +1995,7 @@ func TestModelSummaries(t *testing.T) {
 					},
 				},
 			}
-			summaries, err := j.ListModelSummaries(ctx, alice, "")
+			summaries, err := j.ListModelSummaries(ctx, alice, "", nil)
 			c.Check(err, qt.IsNil)
 			c.Check(summaries.Results, qt.HasLen, t.expectedSummariesSize)
 			c.Check(summaries.Results, qt.DeepEquals, t.expectedSummaries)
@@ -4009,7 +4009,7 @@ func TestListModels(t *testing.T) {
 				c.Assert(err, qt.IsNil)
 				user := openfga.NewUser(dbUser, j.OpenFGAClient)
 
-				models, err := j.ListModels(context.Background(), user)
+				models, err := j.ListModels(context.Background(), user, nil)
 				if test.expectedError != "" {
 					c.Assert(err, qt.ErrorMatches, test.expectedError)
 				} else {
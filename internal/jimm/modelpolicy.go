@@ -0,0 +1,206 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// A ModelPolicyError is returned when adding a model would violate one or
+// more model policies.
+type ModelPolicyError struct {
+	Violations []apiparams.ModelPolicyViolation
+}
+
+// Error implements the error interface.
+func (e *ModelPolicyError) Error() string {
+	msgs := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		msgs[i] = fmt.Sprintf("policy %q: %s", v.Policy, v.Message)
+	}
+	return "model policy violation: " + strings.Join(msgs, "; ")
+}
+
+// ErrorCode returns the error code of the error.
+func (e *ModelPolicyError) ErrorCode() string {
+	return string(errors.CodePolicyViolation)
+}
+
+// ErrorInfo returns structured information about the violations, which is
+// returned to API clients.
+func (e *ModelPolicyError) ErrorInfo() map[string]interface{} {
+	return map[string]interface{}{
+		"violations": e.Violations,
+	}
+}
+
+// SetModelPolicy creates, or replaces, a model policy. Only JIMM
+// administrators may set model policies.
+func (j *JIMM) SetModelPolicy(ctx context.Context, user *openfga.User, policy apiparams.ModelPolicy) error {
+	const op = errors.Op("jimm.SetModelPolicy")
+
+	if err := j.checkJimmAdmin(user); err != nil {
+		return errors.E(op, err)
+	}
+	p, err := j.modelPolicyFromParams(ctx, policy)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	if err := j.Database.SetModelPolicy(ctx, &p); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// RemoveModelPolicy removes the model policy with the given name. Only
+// JIMM administrators may remove model policies.
+func (j *JIMM) RemoveModelPolicy(ctx context.Context, user *openfga.User, name string) error {
+	const op = errors.Op("jimm.RemoveModelPolicy")
+
+	if err := j.checkJimmAdmin(user); err != nil {
+		return errors.E(op, err)
+	}
+	if err := j.Database.DeleteModelPolicy(ctx, &dbmodel.ModelPolicy{Name: name}); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// ListModelPolicies returns all the model policies. Only JIMM
+// administrators may list model policies.
+func (j *JIMM) ListModelPolicies(ctx context.Context, user *openfga.User) ([]apiparams.ModelPolicy, error) {
+	const op = errors.Op("jimm.ListModelPolicies")
+
+	if err := j.checkJimmAdmin(user); err != nil {
+		return nil, errors.E(op, err)
+	}
+	policies, err := j.Database.ListModelPolicies(ctx)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	result := make([]apiparams.ModelPolicy, len(policies))
+	for i, p := range policies {
+		var groupNames []string
+		for _, uuid := range p.Groups {
+			// Report the UUID of any group that has since been
+			// removed.
+			group := dbmodel.GroupEntry{UUID: uuid}
+			if err := j.Database.GetGroup(ctx, &group); err == nil {
+				uuid = group.Name
+			}
+			groupNames = append(groupNames, uuid)
+		}
+		result[i] = p.ToAPIModelPolicy(groupNames)
+	}
+	return result, nil
+}
+
+// modelPolicyFromParams validates the given policy parameters and
+// converts them to a database model policy.
+func (j *JIMM) modelPolicyFromParams(ctx context.Context, p apiparams.ModelPolicy) (dbmodel.ModelPolicy, error) {
+	if p.Name == "" {
+		return dbmodel.ModelPolicy{}, errors.E(errors.CodeBadRequest, "policy name not specified")
+	}
+	var groupUUIDs dbmodel.Strings
+	for _, name := range p.Groups {
+		group := dbmodel.GroupEntry{Name: name}
+		if err := j.Database.GetGroup(ctx, &group); err != nil {
+			return dbmodel.ModelPolicy{}, err
+		}
+		groupUUIDs = append(groupUUIDs, group.UUID)
+	}
+	for _, entry := range append(append([]string(nil), p.Clouds...), p.AllowedClouds...) {
+		if err := j.checkPolicyCloud(ctx, entry); err != nil {
+			return dbmodel.ModelPolicy{}, err
+		}
+	}
+	for _, m := range []map[string]string{p.RequiredConfig, p.ForbiddenConfig} {
+		if _, ok := m[""]; ok {
+			return dbmodel.ModelPolicy{}, errors.E(errors.CodeBadRequest, "empty config key")
+		}
+	}
+	if p.NamePattern != "" {
+		if _, err := dbmodel.CompileModelNamePattern(p.NamePattern); err != nil {
+			return dbmodel.ModelPolicy{}, errors.E(errors.CodeBadRequest, fmt.Sprintf("invalid name pattern %q", p.NamePattern))
+		}
+	}
+	for _, pattern := range p.AllowedCredentials {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return dbmodel.ModelPolicy{}, errors.E(errors.CodeBadRequest, fmt.Sprintf("invalid credential pattern %q", pattern))
+		}
+	}
+	return dbmodel.ModelPolicy{
+		Name:               p.Name,
+		Groups:             groupUUIDs,
+		Clouds:             p.Clouds,
+		AllowedClouds:      p.AllowedClouds,
+		RequiredConfig:     p.RequiredConfig,
+		ForbiddenConfig:    p.ForbiddenConfig,
+		NamePattern:        p.NamePattern,
+		AllowedCredentials: p.AllowedCredentials,
+	}, nil
+}
+
+// checkPolicyCloud checks that the given "cloud" or "cloud/region" entry
+// identifies a known cloud, or cloud region.
+func (j *JIMM) checkPolicyCloud(ctx context.Context, entry string) error {
+	cloudName, region, hasRegion := strings.Cut(entry, "/")
+	cloud := dbmodel.Cloud{Name: cloudName}
+	if err := j.Database.GetCloud(ctx, &cloud); err != nil {
+		return err
+	}
+	if hasRegion && cloud.Region(region).Name == "" {
+		return errors.E(errors.CodeNotFound, fmt.Sprintf("cloud region %s not found", entry))
+	}
+	return nil
+}
+
+// CheckModelPolicies checks the model being built against every model
+// policy that applies to it. It must be called after the cloud region,
+// credential and config of the model have been determined. If any policy
+// is violated the builder's error is set to a *ModelPolicyError listing
+// every violation.
+func (b *modelBuilder) CheckModelPolicies() *modelBuilder {
+	if b.err != nil {
+		return b
+	}
+	policies, err := b.jimm.Database.ListModelPolicies(b.ctx)
+	if err != nil {
+		b.err = errors.E(err, "failed to fetch model policies")
+		return b
+	}
+	if len(policies) == 0 {
+		return b
+	}
+	groups, err := b.jimm.identityGroupUUIDs(b.ctx, b.owner.Name)
+	if err != nil {
+		b.err = err
+		return b
+	}
+	subject := dbmodel.ModelPolicySubject{
+		Name:       b.name,
+		Groups:     groups,
+		Cloud:      b.cloud.Name,
+		Region:     b.cloudRegion,
+		Credential: b.credential.Path(),
+		Config:     b.config,
+	}
+	var violations []apiparams.ModelPolicyViolation
+	for _, p := range policies {
+		if p.AppliesTo(subject) {
+			violations = append(violations, p.Check(subject)...)
+		}
+	}
+	if len(violations) > 0 {
+		b.err = errors.E(&ModelPolicyError{Violations: violations})
+	}
+	return b
+}
@@ -0,0 +1,184 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	stderrors "errors"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+var modelPolicyTestArgs = jujuparams.ModelCreateArgs{
+	CloudTag:           names.NewCloudTag("test-cloud").String(),
+	CloudRegion:        "test-region-1",
+	CloudCredentialTag: names.NewCloudCredentialTag("test-cloud/alice@canonical.com/test-credential-1").String(),
+	Config:             map[string]interface{}{"logging-config": "<root>=WARNING"},
+}
+
+func TestSetModelPolicyUnauthorized(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+	j, alice, _ := newAddModelTestJIMM(c, &addModelTestAPI{}, false)
+
+	err := j.SetModelPolicy(ctx, alice, apiparams.ModelPolicy{Name: "p"})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+	err = j.RemoveModelPolicy(ctx, alice, "p")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+	_, err = j.ListModelPolicies(ctx, alice)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+}
+
+func TestSetModelPolicyInvalid(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+	j, _, admin := newAddModelTestJIMM(c, &addModelTestAPI{}, true)
+
+	tests := []struct {
+		policy      apiparams.ModelPolicy
+		expectError string
+		expectCode  errors.Code
+	}{{
+		policy:      apiparams.ModelPolicy{},
+		expectError: `policy name not specified`,
+		expectCode:  errors.CodeBadRequest,
+	}, {
+		policy:     apiparams.ModelPolicy{Name: "p", Groups: []string{"no-such-group"}},
+		expectCode: errors.CodeNotFound,
+	}, {
+		policy:     apiparams.ModelPolicy{Name: "p", Clouds: []string{"no-such-cloud"}},
+		expectCode: errors.CodeNotFound,
+	}, {
+		policy:      apiparams.ModelPolicy{Name: "p", AllowedClouds: []string{"test-cloud/no-such-region"}},
+		expectError: `cloud region test-cloud/no-such-region not found`,
+		expectCode:  errors.CodeNotFound,
+	}, {
+		policy:      apiparams.ModelPolicy{Name: "p", RequiredConfig: map[string]string{"": "x"}},
+		expectError: `empty config key`,
+		expectCode:  errors.CodeBadRequest,
+	}, {
+		policy:      apiparams.ModelPolicy{Name: "p", NamePattern: "("},
+		expectError: `invalid name pattern "\("`,
+		expectCode:  errors.CodeBadRequest,
+	}, {
+		policy:      apiparams.ModelPolicy{Name: "p", AllowedCredentials: []string{"["}},
+		expectError: `invalid credential pattern "\["`,
+		expectCode:  errors.CodeBadRequest,
+	}}
+	for _, test := range tests {
+		err := j.SetModelPolicy(ctx, admin, test.policy)
+		if test.expectError != "" {
+			c.Check(err, qt.ErrorMatches, test.expectError)
+		}
+		c.Check(errors.ErrorCode(err), qt.Equals, test.expectCode)
+	}
+}
+
+func TestAddModelPolicyViolation(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+	api := &addModelTestAPI{}
+	j, alice, admin := newAddModelTestJIMM(c, api, true)
+
+	err := j.SetModelPolicy(ctx, admin, apiparams.ModelPolicy{
+		Name:            "prod",
+		AllowedClouds:   []string{"test-cloud/test-region-2"},
+		ForbiddenConfig: map[string]string{"logging-config": ""},
+		NamePattern:     "prod-.*",
+	})
+	c.Assert(err, qt.IsNil)
+
+	policies, err := j.ListModelPolicies(ctx, admin)
+	c.Assert(err, qt.IsNil)
+	c.Assert(policies, qt.HasLen, 1)
+	c.Check(policies[0].Name, qt.Equals, "prod")
+
+	_, err = addTestModel(ctx, j, alice, modelPolicyTestArgs)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodePolicyViolation)
+	var perr *jimm.ModelPolicyError
+	c.Assert(stderrors.As(err, &perr), qt.IsTrue)
+	c.Check(perr.Violations, qt.DeepEquals, []apiparams.ModelPolicyViolation{{
+		Policy:  "prod",
+		Rule:    dbmodel.ModelPolicyRuleAllowedClouds,
+		Message: "cloud region test-cloud/test-region-1 is not allowed",
+	}, {
+		Policy:  "prod",
+		Rule:    dbmodel.ModelPolicyRuleForbiddenConfig,
+		Message: `config key "logging-config" must not be set`,
+	}, {
+		Policy:  "prod",
+		Rule:    dbmodel.ModelPolicyRuleNamePattern,
+		Message: `model name "model-2" does not match "prod-.*"`,
+	}})
+	// The model is not created on the controller.
+	c.Check(api.config, qt.IsNil)
+
+	m := dbmodel.Model{
+		Name:              "model-2",
+		OwnerIdentityName: "alice@canonical.com",
+	}
+	err = j.Database.GetModel(ctx, &m)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	err = j.RemoveModelPolicy(ctx, admin, "prod")
+	c.Assert(err, qt.IsNil)
+	_, err = addTestModel(ctx, j, alice, modelPolicyTestArgs)
+	c.Assert(err, qt.IsNil)
+}
+
+func TestAddModelPolicyScope(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+	j, alice, admin := newAddModelTestJIMM(c, &addModelTestAPI{}, true)
+
+	group, err := j.Database.AddGroup(ctx, "students")
+	c.Assert(err, qt.IsNil)
+
+	// Policies scoped to other groups, or other cloud regions, do not
+	// apply to the model.
+	err = j.SetModelPolicy(ctx, admin, apiparams.ModelPolicy{
+		Name:           "students",
+		Groups:         []string{"students"},
+		RequiredConfig: map[string]string{"http-proxy": ""},
+	})
+	c.Assert(err, qt.IsNil)
+	err = j.SetModelPolicy(ctx, admin, apiparams.ModelPolicy{
+		Name:           "region-2",
+		Clouds:         []string{"test-cloud/test-region-2"},
+		RequiredConfig: map[string]string{"http-proxy": ""},
+	})
+	c.Assert(err, qt.IsNil)
+	err = j.SetModelPolicy(ctx, admin, apiparams.ModelPolicy{
+		Name:               "credentials",
+		Clouds:             []string{"test-cloud"},
+		RequiredConfig:     map[string]string{"logging-config": "<root>=WARNING"},
+		AllowedCredentials: []string{"test-cloud/*/test-credential-*"},
+	})
+	c.Assert(err, qt.IsNil)
+
+	_, err = addTestModel(ctx, j, alice, modelPolicyTestArgs)
+	c.Assert(err, qt.IsNil)
+
+	err = j.OpenFGAClient.AddRelation(ctx, openfga.Tuple{
+		Object:   ofganames.ConvertTag(alice.ResourceTag()),
+		Relation: ofganames.MemberRelation,
+		Target:   ofganames.ConvertTag(group.ResourceTag()),
+	})
+	c.Assert(err, qt.IsNil)
+
+	args := modelPolicyTestArgs
+	args.Name = "model-3"
+	_, err = addTestModel(ctx, j, alice, args)
+	c.Check(err, qt.ErrorMatches, `model policy violation: policy "students": config key "http-proxy" must be set`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodePolicyViolation)
+}
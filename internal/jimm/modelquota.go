@@ -0,0 +1,355 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+	jimmnames "github.com/canonical/jimm/v3/pkg/names"
+)
+
+// SetModelQuota creates, or updates, a model quota. Group quotas are
+// specified by group name. Only JIMM administrators may set model quotas.
+func (j *JIMM) SetModelQuota(ctx context.Context, user *openfga.User, quota apiparams.ModelQuota) error {
+	const op = errors.Op("jimm.SetModelQuota")
+
+	if err := j.checkJimmAdmin(user); err != nil {
+		return errors.E(op, err)
+	}
+	if quota.MaxModels < 0 {
+		return errors.E(op, errors.CodeBadRequest, "max models cannot be negative")
+	}
+	q, err := j.modelQuotaFromParams(ctx, quota.Kind, quota.Subject, quota.Region)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	q.MaxModels = quota.MaxModels
+	if err := j.Database.SetModelQuota(ctx, &q); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// RemoveModelQuota removes a model quota. Only JIMM administrators may
+// remove model quotas.
+func (j *JIMM) RemoveModelQuota(ctx context.Context, user *openfga.User, kind, subject, region string) error {
+	const op = errors.Op("jimm.RemoveModelQuota")
+
+	if err := j.checkJimmAdmin(user); err != nil {
+		return errors.E(op, err)
+	}
+	q, err := j.modelQuotaFromParams(ctx, kind, subject, region)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	if err := j.Database.DeleteModelQuota(ctx, &q); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// ListModelQuotas returns all the model quotas. Only JIMM administrators
+// may list model quotas.
+func (j *JIMM) ListModelQuotas(ctx context.Context, user *openfga.User) ([]apiparams.ModelQuota, error) {
+	const op = errors.Op("jimm.ListModelQuotas")
+
+	if err := j.checkJimmAdmin(user); err != nil {
+		return nil, errors.E(op, err)
+	}
+	quotas, err := j.Database.ListModelQuotas(ctx)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	result := make([]apiparams.ModelQuota, len(quotas))
+	for i, q := range quotas {
+		result[i] = j.toAPIModelQuota(ctx, q)
+	}
+	return result, nil
+}
+
+// GetModelQuotaUsage returns the model quotas that apply to the identity
+// with the given name, along with the current usage against each. If
+// identityName is empty the given user is reported on. Only JIMM
+// administrators may report on other identities.
+func (j *JIMM) GetModelQuotaUsage(ctx context.Context, user *openfga.User, identityName string) ([]apiparams.ModelQuotaUsage, error) {
+	const op = errors.Op("jimm.GetModelQuotaUsage")
+
+	if identityName == "" {
+		identityName = user.Name
+	}
+	if identityName != user.Name && !user.JimmAdmin {
+		return nil, errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+
+	limits, err := j.ownerModelQuotaLimits(ctx, identityName)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	cloudQuotas, err := j.allCloudModelQuotas(ctx)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	for _, q := range cloudQuotas {
+		limits = append(limits, modelQuotaLimit{quota: q})
+	}
+
+	var usage []apiparams.ModelQuotaUsage
+	for _, l := range limits {
+		n, err := l.count(ctx, j.Database)
+		if err != nil {
+			return nil, errors.E(op, err)
+		}
+		usage = append(usage, apiparams.ModelQuotaUsage{
+			ModelQuota: j.toAPIModelQuota(ctx, l.quota),
+			Usage:      n,
+		})
+	}
+	return usage, nil
+}
+
+// A modelQuotaLimit is a model quota along with the identities whose
+// models count towards it.
+type modelQuotaLimit struct {
+	quota dbmodel.ModelQuota
+
+	// owners holds the names of the identities whose models count
+	// towards an identity or group quota. For a group quota these are
+	// all the members of the group.
+	owners []string
+
+	// everyone is set if the models of every identity count towards the
+	// quota, which is the case for a group that everyone is a member of.
+	everyone bool
+}
+
+// count returns the number of models in the given database that count
+// towards the limit.
+func (l modelQuotaLimit) count(ctx context.Context, db *db.Database) (int, error) {
+	switch {
+	case l.quota.Kind == dbmodel.ModelQuotaCloud:
+		return db.CountModelsByCloudRegion(ctx, l.quota.Subject, l.quota.Region)
+	case l.everyone:
+		return db.CountModels(ctx)
+	default:
+		return db.CountModelsByOwners(ctx, l.owners)
+	}
+}
+
+// modelQuotaLimits returns the limits of the model quotas that apply to
+// adding a model owned by the given identity to the given region of the
+// given cloud.
+func (j *JIMM) modelQuotaLimits(ctx context.Context, ownerName, cloud, region string) ([]modelQuotaLimit, error) {
+	limits, err := j.ownerModelQuotaLimits(ctx, ownerName)
+	if err != nil {
+		return nil, err
+	}
+	quotas, err := j.Database.FindModelQuotas(ctx, dbmodel.ModelQuotaCloud, cloud)
+	if err != nil {
+		return nil, err
+	}
+	for _, q := range quotas {
+		if q.Region != "" && q.Region != region {
+			continue
+		}
+		limits = append(limits, modelQuotaLimit{quota: q})
+	}
+	return limits, nil
+}
+
+// checkModelQuotaLimits checks that adding a model to the given database
+// would not exceed any of the given limits. If a limit would be exceeded
+// an error with a code of CodeQuotaExceeded is returned. The database must
+// be in a transaction: the model quotas are locked until the transaction
+// ends so that the model must be added in the same transaction.
+func (j *JIMM) checkModelQuotaLimits(ctx context.Context, db *db.Database, limits []modelQuotaLimit) error {
+	if len(limits) == 0 {
+		return nil
+	}
+	if err := db.LockModelQuotas(ctx); err != nil {
+		return err
+	}
+	for _, l := range limits {
+		n, err := l.count(ctx, db)
+		if err != nil {
+			return err
+		}
+		if n >= l.quota.MaxModels {
+			return modelQuotaExceededError(j.toAPIModelQuota(ctx, l.quota))
+		}
+	}
+	return nil
+}
+
+// modelQuotaExceededError returns the error reported when creating a model
+// would exceed the given quota.
+func modelQuotaExceededError(q apiparams.ModelQuota) error {
+	subject := q.Subject
+	if q.Region != "" {
+		subject += "/" + q.Region
+	}
+	return errors.E(errors.CodeQuotaExceeded, fmt.Sprintf("model quota exceeded: %s %q is limited to %d models", q.Kind, subject, q.MaxModels))
+}
+
+// ownerModelQuotaLimits returns the limits of the identity quotas and the
+// group quotas that limit the number of models the identity with the given
+// name may own. The models of every member of a group count towards the
+// group's quota.
+func (j *JIMM) ownerModelQuotaLimits(ctx context.Context, identityName string) ([]modelQuotaLimit, error) {
+	quotas, err := j.Database.FindModelQuotas(ctx, dbmodel.ModelQuotaIdentity, identityName)
+	if err != nil {
+		return nil, err
+	}
+	var limits []modelQuotaLimit
+	for _, q := range quotas {
+		limits = append(limits, modelQuotaLimit{quota: q, owners: []string{identityName}})
+	}
+	groupUUIDs, err := j.identityGroupUUIDs(ctx, identityName)
+	if err != nil {
+		return nil, err
+	}
+	groupQuotas, err := j.Database.FindModelQuotas(ctx, dbmodel.ModelQuotaGroup, groupUUIDs...)
+	if err != nil {
+		return nil, err
+	}
+	for _, q := range groupQuotas {
+		members, everyone, err := j.groupMemberNames(ctx, q.Subject)
+		if err != nil {
+			return nil, err
+		}
+		limits = append(limits, modelQuotaLimit{quota: q, owners: members, everyone: everyone})
+	}
+	return limits, nil
+}
+
+// groupMemberNames returns the names of the identities that are members
+// of the group with the given UUID, including the members of groups that
+// are members of the group. If everyone is a member of the group the
+// returned bool is true.
+func (j *JIMM) groupMemberNames(ctx context.Context, groupUUID string) ([]string, bool, error) {
+	var members []string
+	var everyone bool
+	seen := map[string]bool{groupUUID: true}
+	groups := []string{groupUUID}
+	for len(groups) > 0 {
+		key := openfga.Tuple{
+			Relation: ofganames.MemberRelation,
+			Target:   ofganames.ConvertTag(jimmnames.NewGroupTag(groups[0])),
+		}
+		groups = groups[1:]
+		var continuationToken string
+		for {
+			tuples, ct, err := j.OpenFGAClient.ReadRelatedObjects(ctx, key, 50, continuationToken)
+			if err != nil {
+				return nil, false, errors.E(err, errors.CodeOpenFGARequestFailed)
+			}
+			for _, t := range tuples {
+				switch t.Object.Kind {
+				case openfga.UserType:
+					if t.Object.ID == ofganames.EveryoneUser {
+						everyone = true
+						continue
+					}
+					members = append(members, t.Object.ID)
+				case openfga.GroupType:
+					if !seen[t.Object.ID] {
+						seen[t.Object.ID] = true
+						groups = append(groups, t.Object.ID)
+					}
+				}
+			}
+			if ct == "" {
+				break
+			}
+			continuationToken = ct
+		}
+	}
+	return members, everyone, nil
+}
+
+// identityGroupUUIDs returns the UUIDs of the groups the identity with
+// the given name is a member of.
+func (j *JIMM) identityGroupUUIDs(ctx context.Context, identityName string) ([]string, error) {
+	groups, err := j.OpenFGAClient.ListObjects(ctx, ofganames.ConvertTag(names.NewUserTag(identityName)), ofganames.MemberRelation, openfga.GroupType, nil)
+	if err != nil {
+		return nil, errors.E(err, errors.CodeOpenFGARequestFailed)
+	}
+	groupUUIDs := make([]string, len(groups))
+	for i, g := range groups {
+		groupUUIDs[i] = g.ID
+	}
+	return groupUUIDs, nil
+}
+
+// allCloudModelQuotas returns all the cloud model quotas.
+func (j *JIMM) allCloudModelQuotas(ctx context.Context) ([]dbmodel.ModelQuota, error) {
+	quotas, err := j.Database.ListModelQuotas(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var cloudQuotas []dbmodel.ModelQuota
+	for _, q := range quotas {
+		if q.Kind == dbmodel.ModelQuotaCloud {
+			cloudQuotas = append(cloudQuotas, q)
+		}
+	}
+	return cloudQuotas, nil
+}
+
+// modelQuotaFromParams validates the given quota parameters and returns
+// the model quota they identify.
+func (j *JIMM) modelQuotaFromParams(ctx context.Context, kind, subject, region string) (dbmodel.ModelQuota, error) {
+	q := dbmodel.ModelQuota{
+		Kind:    dbmodel.ModelQuotaKind(kind),
+		Subject: subject,
+	}
+	switch q.Kind {
+	case dbmodel.ModelQuotaIdentity:
+		if !names.IsValidUser(subject) {
+			return q, errors.E(errors.CodeBadRequest, "invalid identity name")
+		}
+	case dbmodel.ModelQuotaGroup:
+		group := dbmodel.GroupEntry{Name: subject}
+		if err := j.Database.GetGroup(ctx, &group); err != nil {
+			return q, err
+		}
+		q.Subject = group.UUID
+	case dbmodel.ModelQuotaCloud:
+		cloud := dbmodel.Cloud{Name: subject}
+		if err := j.Database.GetCloud(ctx, &cloud); err != nil {
+			return q, err
+		}
+		if region != "" && cloud.Region(region).Name != region {
+			return q, errors.E(errors.CodeNotFound, fmt.Sprintf("cloud region %q not found", region))
+		}
+		q.Region = region
+		return q, nil
+	default:
+		return q, errors.E(errors.CodeBadRequest, fmt.Sprintf("invalid quota kind %q", kind))
+	}
+	if region != "" {
+		return q, errors.E(errors.CodeBadRequest, "region is only valid for cloud quotas")
+	}
+	return q, nil
+}
+
+// toAPIModelQuota converts the given model quota to its API
+// representation, replacing group UUIDs with the group name.
+func (j *JIMM) toAPIModelQuota(ctx context.Context, q dbmodel.ModelQuota) apiparams.ModelQuota {
+	aq := q.ToAPIModelQuota()
+	if q.Kind == dbmodel.ModelQuotaGroup {
+		group := dbmodel.GroupEntry{UUID: q.Subject}
+		if err := j.Database.GetGroup(ctx, &group); err == nil {
+			aq.Subject = group.Name
+		}
+	}
+	return aq
+}
@@ -0,0 +1,343 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/juju/juju/api/base"
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
+	"github.com/canonical/jimm/v3/internal/testutils/jimmtest"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const modelQuotaTestEnv = `clouds:
+- name: test-cloud
+  type: test-provider
+  regions:
+  - name: test-region-1
+  - name: test-region-2
+  users:
+  - user: alice@canonical.com
+    access: add-model
+cloud-credentials:
+- name: test-credential-1
+  owner: alice@canonical.com
+  cloud: test-cloud
+  auth-type: empty
+controllers:
+- name: controller-1
+  uuid: 00000000-0000-0000-0000-0000-0000000000001
+  cloud: test-cloud
+  region: test-region-1
+  cloud-regions:
+  - cloud: test-cloud
+    region: test-region-1
+    priority: 1
+models:
+- name: model-1
+  owner: alice@canonical.com
+  uuid: 00000001-0000-0000-0000-0000-000000000001
+  cloud: test-cloud
+  region: test-region-1
+  cloud-credential: test-credential-1
+  controller: controller-1
+users:
+- username: alice@canonical.com
+  controller-access: login
+- username: bob@canonical.com
+  controller-access: superuser
+`
+
+// addModelTestAPI configures the controller API of the JIMM returned by
+// newAddModelTestJIMM and records the calls made to it.
+type addModelTestAPI struct {
+	// env is the environment JIMM is populated with, if it is empty
+	// modelQuotaTestEnv is used.
+	env string
+
+	// models are the models returned by ListModels.
+	models []base.UserModel
+
+	// destroyModel, if set, is called when a model is destroyed.
+	destroyModel func(context.Context, names.ModelTag, *bool, *bool, *time.Duration, *time.Duration) error
+
+	// config holds the config of the last model created.
+	config map[string]interface{}
+
+	// destroyed counts the models destroyed.
+	destroyed atomic.Int32
+}
+
+// newAddModelTestJIMM returns a JIMM, populated with the given API's
+// environment, whose controllers create models using the given API. It
+// also returns the users alice and bob, bob is a JIMM administrator if
+// bobIsAdmin is set.
+func newAddModelTestJIMM(c *qt.C, api *addModelTestAPI, bobIsAdmin bool) (*jimm.JIMM, *openfga.User, *openfga.User) {
+	create := createModel(`
+uuid: 00000001-0000-0000-0000-0000-000000000002
+status:
+  status: started
+life: alive
+`[1:])
+	j := jimmtest.NewJIMM(c, &jimm.Parameters{
+		Dialer: &jimmtest.Dialer{
+			API: &jimmtest.API{
+				UpdateCredential_: func(context.Context, jujuparams.TaggedCredential) ([]jujuparams.UpdateCredentialModelResult, error) {
+					return nil, nil
+				},
+				GrantJIMMModelAdmin_: func(context.Context, names.ModelTag) error {
+					return nil
+				},
+				CreateModel_: func(ctx context.Context, args *jujuparams.ModelCreateArgs, mi *jujuparams.ModelInfo) error {
+					api.config = args.Config
+					return create(ctx, args, mi)
+				},
+				DestroyModel_: func(ctx context.Context, mt names.ModelTag, destroyStorage, force *bool, maxWait, timeout *time.Duration) error {
+					api.destroyed.Add(1)
+					if api.destroyModel == nil {
+						return nil
+					}
+					return api.destroyModel(ctx, mt, destroyStorage, force, maxWait, timeout)
+				},
+				ListModels_: func(context.Context) ([]base.UserModel, error) {
+					return api.models, nil
+				},
+				ListModelSummaries_: func(context.Context, jujuparams.ModelSummariesRequest) (jujuparams.ModelSummaryResults, error) {
+					return jujuparams.ModelSummaryResults{}, nil
+				},
+			},
+		},
+	})
+
+	envYAML := api.env
+	if envYAML == "" {
+		envYAML = modelQuotaTestEnv
+	}
+	env := jimmtest.ParseEnvironment(c, envYAML)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, j.OpenFGAClient)
+
+	alice := env.User("alice@canonical.com").DBObject(c, j.Database)
+	bob := env.User("bob@canonical.com").DBObject(c, j.Database)
+	u := openfga.NewUser(&bob, j.OpenFGAClient)
+	u.JimmAdmin = bobIsAdmin
+	return j, openfga.NewUser(&alice, j.OpenFGAClient), u
+}
+
+// addModelTestArgs are the arguments used to add a model to the
+// modelQuotaTestEnv environment.
+var addModelTestArgs = jujuparams.ModelCreateArgs{
+	CloudTag:           names.NewCloudTag("test-cloud").String(),
+	CloudRegion:        "test-region-1",
+	CloudCredentialTag: names.NewCloudCredentialTag("test-cloud/alice@canonical.com/test-credential-1").String(),
+}
+
+// addTestModel adds a model owned by the given user using the given
+// arguments. If no name is specified the model is called model-2.
+func addTestModel(ctx context.Context, j *jimm.JIMM, user *openfga.User, args jujuparams.ModelCreateArgs) (*jujuparams.ModelInfo, error) {
+	if args.Name == "" {
+		args.Name = "model-2"
+	}
+	args.OwnerTag = user.ResourceTag().String()
+	var margs jimm.ModelCreateArgs
+	if err := margs.FromJujuModelCreateArgs(&args); err != nil {
+		return nil, err
+	}
+	return j.AddModel(ctx, user, &margs)
+}
+
+func TestSetModelQuotaUnauthorized(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+	j, alice, _ := newAddModelTestJIMM(c, &addModelTestAPI{}, false)
+
+	err := j.SetModelQuota(ctx, alice, apiparams.ModelQuota{Kind: "identity", Subject: "alice@canonical.com", MaxModels: 10})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+	_, err = j.ListModelQuotas(ctx, alice)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+	_, err = j.GetModelQuotaUsage(ctx, alice, "bob@canonical.com")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+}
+
+func TestSetModelQuotaInvalid(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+	j, _, admin := newAddModelTestJIMM(c, &addModelTestAPI{}, true)
+
+	err := j.SetModelQuota(ctx, admin, apiparams.ModelQuota{Kind: "planet", Subject: "earth", MaxModels: 1})
+	c.Check(err, qt.ErrorMatches, `invalid quota kind "planet"`)
+	err = j.SetModelQuota(ctx, admin, apiparams.ModelQuota{Kind: "identity", Subject: "alice@canonical.com", Region: "test-region-1", MaxModels: 1})
+	c.Check(err, qt.ErrorMatches, `region is only valid for cloud quotas`)
+	err = j.SetModelQuota(ctx, admin, apiparams.ModelQuota{Kind: "cloud", Subject: "test-cloud", Region: "no-such-region", MaxModels: 1})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+	err = j.SetModelQuota(ctx, admin, apiparams.ModelQuota{Kind: "group", Subject: "no-such-group", MaxModels: 1})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+	err = j.SetModelQuota(ctx, admin, apiparams.ModelQuota{Kind: "identity", Subject: "alice@canonical.com", MaxModels: -1})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+}
+
+func TestAddModelIdentityQuota(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+	j, alice, admin := newAddModelTestJIMM(c, &addModelTestAPI{}, true)
+
+	err := j.SetModelQuota(ctx, admin, apiparams.ModelQuota{Kind: "identity", Subject: "alice@canonical.com", MaxModels: 1})
+	c.Assert(err, qt.IsNil)
+
+	_, err = addTestModel(ctx, j, alice, addModelTestArgs)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeQuotaExceeded)
+	c.Check(err, qt.ErrorMatches, `model quota exceeded: identity "alice@canonical.com" is limited to 1 models`)
+
+	usage, err := j.GetModelQuotaUsage(ctx, alice, "")
+	c.Assert(err, qt.IsNil)
+	c.Check(usage, qt.DeepEquals, []apiparams.ModelQuotaUsage{{
+		ModelQuota: apiparams.ModelQuota{Kind: "identity", Subject: "alice@canonical.com", MaxModels: 1},
+		Usage:      1,
+	}})
+
+	// Raising the quota allows the model to be added.
+	err = j.SetModelQuota(ctx, admin, apiparams.ModelQuota{Kind: "identity", Subject: "alice@canonical.com", MaxModels: 2})
+	c.Assert(err, qt.IsNil)
+	_, err = addTestModel(ctx, j, alice, addModelTestArgs)
+	c.Assert(err, qt.IsNil)
+
+	quotas, err := j.ListModelQuotas(ctx, admin)
+	c.Assert(err, qt.IsNil)
+	c.Check(quotas, qt.DeepEquals, []apiparams.ModelQuota{
+		{Kind: "identity", Subject: "alice@canonical.com", MaxModels: 2},
+	})
+
+	err = j.RemoveModelQuota(ctx, admin, "identity", "alice@canonical.com", "")
+	c.Assert(err, qt.IsNil)
+	err = j.RemoveModelQuota(ctx, admin, "identity", "alice@canonical.com", "")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+}
+
+func TestAddModelGroupQuota(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+	j, alice, admin := newAddModelTestJIMM(c, &addModelTestAPI{}, true)
+
+	group, err := j.Database.AddGroup(ctx, "students")
+	c.Assert(err, qt.IsNil)
+	err = j.OpenFGAClient.AddRelation(ctx, openfga.Tuple{
+		Object:   ofganames.ConvertTag(alice.ResourceTag()),
+		Relation: ofganames.MemberRelation,
+		Target:   ofganames.ConvertTag(group.ResourceTag()),
+	})
+	c.Assert(err, qt.IsNil)
+
+	// The models of every member of the group, including those of
+	// nested groups, count towards the group's quota.
+	tutors, err := j.Database.AddGroup(ctx, "tutors")
+	c.Assert(err, qt.IsNil)
+	err = j.OpenFGAClient.AddRelation(ctx, openfga.Tuple{
+		Object:   ofganames.ConvertTagWithRelation(tutors.ResourceTag(), ofganames.MemberRelation),
+		Relation: ofganames.MemberRelation,
+		Target:   ofganames.ConvertTag(group.ResourceTag()),
+	}, openfga.Tuple{
+		Object:   ofganames.ConvertTag(admin.ResourceTag()),
+		Relation: ofganames.MemberRelation,
+		Target:   ofganames.ConvertTag(tutors.ResourceTag()),
+	})
+	c.Assert(err, qt.IsNil)
+	m := dbmodel.Model{UUID: sql.NullString{String: "00000001-0000-0000-0000-0000-000000000001", Valid: true}}
+	err = j.Database.GetModel(ctx, &m)
+	c.Assert(err, qt.IsNil)
+	err = j.Database.AddModel(ctx, &dbmodel.Model{
+		Name:              "bob-model",
+		UUID:              sql.NullString{String: "00000001-0000-0000-0000-0000-000000000003", Valid: true},
+		OwnerIdentityName: admin.Name,
+		ControllerID:      m.ControllerID,
+		CloudRegionID:     m.CloudRegionID,
+		CloudCredentialID: m.CloudCredentialID,
+	})
+	c.Assert(err, qt.IsNil)
+
+	err = j.SetModelQuota(ctx, admin, apiparams.ModelQuota{Kind: "group", Subject: "students", MaxModels: 2})
+	c.Assert(err, qt.IsNil)
+
+	_, err = addTestModel(ctx, j, alice, addModelTestArgs)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeQuotaExceeded)
+	c.Check(err, qt.ErrorMatches, `model quota exceeded: group "students" is limited to 2 models`)
+
+	usage, err := j.GetModelQuotaUsage(ctx, admin, "alice@canonical.com")
+	c.Assert(err, qt.IsNil)
+	c.Check(usage, qt.DeepEquals, []apiparams.ModelQuotaUsage{{
+		ModelQuota: apiparams.ModelQuota{Kind: "group", Subject: "students", MaxModels: 2},
+		Usage:      2,
+	}})
+}
+
+func TestAddModelQuotaConcurrent(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+	j, alice, admin := newAddModelTestJIMM(c, &addModelTestAPI{}, true)
+
+	err := j.SetModelQuota(ctx, admin, apiparams.ModelQuota{Kind: "identity", Subject: "alice@canonical.com", MaxModels: 2})
+	c.Assert(err, qt.IsNil)
+
+	// Only one of the concurrent requests can add a model.
+	errs := make([]error, 5)
+	var wg sync.WaitGroup
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			args := addModelTestArgs
+			args.Name = fmt.Sprintf("model-%d", i+2)
+			_, errs[i] = addTestModel(ctx, j, alice, args)
+		}(i)
+	}
+	wg.Wait()
+
+	var added int
+	for _, err := range errs {
+		if err == nil {
+			added++
+			continue
+		}
+		c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeQuotaExceeded)
+	}
+	c.Check(added, qt.Equals, 1)
+}
+
+func TestAddModelCloudQuota(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+	j, alice, admin := newAddModelTestJIMM(c, &addModelTestAPI{}, true)
+
+	// A quota on another region does not apply.
+	err := j.SetModelQuota(ctx, admin, apiparams.ModelQuota{Kind: "cloud", Subject: "test-cloud", Region: "test-region-2", MaxModels: 0})
+	c.Assert(err, qt.IsNil)
+	err = j.SetModelQuota(ctx, admin, apiparams.ModelQuota{Kind: "cloud", Subject: "test-cloud", Region: "test-region-1", MaxModels: 1})
+	c.Assert(err, qt.IsNil)
+
+	_, err = addTestModel(ctx, j, alice, addModelTestArgs)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeQuotaExceeded)
+	c.Check(err, qt.ErrorMatches, `model quota exceeded: cloud "test-cloud/test-region-1" is limited to 1 models`)
+
+	usage, err := j.GetModelQuotaUsage(ctx, alice, "")
+	c.Assert(err, qt.IsNil)
+	c.Check(usage, qt.DeepEquals, []apiparams.ModelQuotaUsage{{
+		ModelQuota: apiparams.ModelQuota{Kind: "cloud", Subject: "test-cloud", Region: "test-region-1", MaxModels: 1},
+		Usage:      1,
+	}, {
+		ModelQuota: apiparams.ModelQuota{Kind: "cloud", Subject: "test-cloud", Region: "test-region-2", MaxModels: 0},
+		Usage:      0,
+	}})
+}
@@ -0,0 +1,111 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/zaputil/zapctx"
+	"go.uber.org/zap"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+// A ModelSummaryBroker distributes model summaries between JIMM replicas,
+// so that only one replica needs to watch each controller. Summaries
+// published by any replica are received by the listeners on every
+// replica.
+type ModelSummaryBroker interface {
+	// PublishModelSummary sends the given summary to every replica.
+	PublishModelSummary(ctx context.Context, summary jujuparams.ModelAbstract) error
+
+	// ListenModelSummaries calls the given function with the latest
+	// summary of every model, and then with each summary as it is
+	// published, until the given context is canceled or the broker
+	// fails.
+	ListenModelSummaries(ctx context.Context, f func(jujuparams.ModelAbstract)) error
+}
+
+// A DatabaseModelSummaryBroker is a ModelSummaryBroker that stores the
+// latest summary of each model in the JIMM database and notifies the
+// listeners using postgres notifications.
+type DatabaseModelSummaryBroker struct {
+	// Database is the database through which summaries are
+	// distributed.
+	Database *db.Database
+}
+
+// PublishModelSummary implements ModelSummaryBroker.
+func (b DatabaseModelSummaryBroker) PublishModelSummary(ctx context.Context, summary jujuparams.ModelAbstract) error {
+	const op = errors.Op("jimm.PublishModelSummary")
+
+	buf, err := json.Marshal(summary)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	s := dbmodel.ModelSummary{
+		ModelUUID: summary.UUID,
+		Summary:   buf,
+	}
+	if err := b.Database.PublishModelSummary(ctx, &s); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// ListenModelSummaries implements ModelSummaryBroker.
+func (b DatabaseModelSummaryBroker) ListenModelSummaries(ctx context.Context, f func(jujuparams.ModelAbstract)) error {
+	const op = errors.Op("jimm.ListenModelSummaries")
+
+	err := b.Database.WatchModelSummaries(ctx, func(s *dbmodel.ModelSummary) {
+		var summary jujuparams.ModelAbstract
+		if err := json.Unmarshal(s.Summary, &summary); err != nil {
+			zapctx.Error(ctx, "cannot decode model summary", zap.String("model", s.ModelUUID), zap.Error(err))
+			return
+		}
+		f(summary)
+	})
+	return errors.E(op, err)
+}
+
+// DistributeModelSummaries publishes every summary received from the
+// given broker to the given publisher, normally the replica's pub-sub
+// hub. If the broker fails it is reconnected, with an increasing delay,
+// until the given context is canceled. If status is not nil the state of
+// the connection to the broker is recorded in it.
+func DistributeModelSummaries(ctx context.Context, broker ModelSummaryBroker, p Publisher, status *ModelSummaryWatchStatus) error {
+	const minDelay, maxDelay = time.Second, time.Minute
+
+	delay := minDelay
+	for {
+		start := time.Now()
+		status.setListening(nil)
+		err := broker.ListenModelSummaries(ctx, func(summary jujuparams.ModelAbstract) {
+			p.Publish(summary.UUID, summary)
+		})
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err == nil {
+			err = errors.E("model summary listener stopped")
+		}
+		status.setListening(err)
+		zapctx.Error(ctx, "model summary distribution failed", zap.Error(err))
+		if time.Since(start) > maxDelay {
+			// The broker was working for a while, so retry
+			// quickly.
+			delay = minDelay
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(delay):
+		}
+		delay = min(2*delay, maxDelay)
+	}
+}
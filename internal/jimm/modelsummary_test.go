@@ -0,0 +1,123 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	jujuparams "github.com/juju/juju/rpc/params"
+
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/pubsub"
+	"github.com/canonical/jimm/v3/internal/testutils/jimmtest"
+)
+
+const modelSummaryBrokerTestEnv = `clouds:
+- name: test-cloud
+  type: test-provider
+  regions:
+  - name: test-region-1
+cloud-credentials:
+- name: cred-1
+  owner: alice@canonical.com
+  cloud: test-cloud
+  auth-type: empty
+controllers:
+- name: controller-1
+  uuid: 00000000-0000-0000-0000-0000-0000000000001
+  cloud: test-cloud
+  region: test-region-1
+models:
+- name: model-1
+  owner: alice@canonical.com
+  uuid: 00000002-0000-0000-0000-000000000001
+  cloud: test-cloud
+  region: test-region-1
+  cloud-credential: cred-1
+  controller: controller-1
+  life: alive
+- name: model-2
+  owner: alice@canonical.com
+  uuid: 00000002-0000-0000-0000-000000000002
+  cloud: test-cloud
+  region: test-region-1
+  cloud-credential: cred-1
+  controller: controller-1
+  life: alive
+`
+
+func TestDistributeModelSummaries(t *testing.T) {
+	c := qt.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	j := jimmtest.NewJIMM(c, nil)
+	env := jimmtest.ParseEnvironment(c, modelSummaryBrokerTestEnv)
+	env.PopulateDB(c, j.Database)
+
+	broker := jimm.DatabaseModelSummaryBroker{Database: j.Database}
+
+	// A summary published before a replica starts listening is
+	// received when it starts.
+	err := broker.PublishModelSummary(ctx, jujuparams.ModelAbstract{
+		UUID:   "00000002-0000-0000-0000-000000000001",
+		Name:   "model-1",
+		Status: "available",
+	})
+	c.Assert(err, qt.IsNil)
+
+	// Two replicas, each with its own hub.
+	var hubs [2]*pubsub.Hub
+	var summaries [2]chan jujuparams.ModelAbstract
+	for i := range hubs {
+		hubs[i] = &pubsub.Hub{}
+		summaries[i] = make(chan jujuparams.ModelAbstract, 10)
+		ch := summaries[i]
+		unsubscribe, err := hubs[i].SubscribeMatch(func(string) bool { return true }, func(_ string, v interface{}) {
+			ch <- v.(jujuparams.ModelAbstract)
+		})
+		c.Assert(err, qt.IsNil)
+		defer unsubscribe()
+		go func() {
+			err := jimm.DistributeModelSummaries(ctx, broker, hubs[i], nil)
+			c.Check(err, qt.IsNil)
+		}()
+	}
+
+	next := func(ch chan jujuparams.ModelAbstract) jujuparams.ModelAbstract {
+		select {
+		case s := <-ch:
+			return s
+		case <-time.After(5 * time.Second):
+			c.Fatalf("timed out waiting for model summary")
+		}
+		return jujuparams.ModelAbstract{}
+	}
+	for _, ch := range summaries {
+		s := next(ch)
+		c.Check(s.UUID, qt.Equals, "00000002-0000-0000-0000-000000000001")
+		c.Check(s.Status, qt.Equals, "available")
+	}
+
+	// The replicas are listening once they have received the stored
+	// summaries.
+	err = broker.PublishModelSummary(ctx, jujuparams.ModelAbstract{
+		UUID:   "00000002-0000-0000-0000-000000000002",
+		Name:   "model-2",
+		Status: "busy",
+		Admins: []string{"alice@canonical.com"},
+	})
+	c.Assert(err, qt.IsNil)
+	for _, ch := range summaries {
+		s := next(ch)
+		c.Check(s, qt.DeepEquals, jujuparams.ModelAbstract{
+			UUID:   "00000002-0000-0000-0000-000000000002",
+			Name:   "model-2",
+			Status: "busy",
+			Admins: []string{"alice@canonical.com"},
+		})
+	}
+}
@@ -0,0 +1,264 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	jujupermission "github.com/juju/juju/core/permission"
+	"github.com/juju/names/v5"
+	"github.com/juju/zaputil/zapctx"
+	"go.uber.org/zap"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// ModelTemplateConfigKey is the model config key used to specify the
+// name of the template a model is created from. The key is removed from
+// the config before the model is created on the controller.
+const ModelTemplateConfigKey = "jimm-template"
+
+// SetModelTemplate creates, or replaces, a model template. Only JIMM
+// administrators may set model templates.
+func (j *JIMM) SetModelTemplate(ctx context.Context, user *openfga.User, template apiparams.ModelTemplate) error {
+	const op = errors.Op("jimm.SetModelTemplate")
+
+	if err := j.checkJimmAdmin(user); err != nil {
+		return errors.E(op, err)
+	}
+	t, err := j.modelTemplateFromParams(ctx, template)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	if err := j.Database.SetModelTemplate(ctx, &t); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// RemoveModelTemplate removes the model template with the given name.
+// Models previously created from the template are not affected. Only
+// JIMM administrators may remove model templates.
+func (j *JIMM) RemoveModelTemplate(ctx context.Context, user *openfga.User, name string) error {
+	const op = errors.Op("jimm.RemoveModelTemplate")
+
+	if err := j.checkJimmAdmin(user); err != nil {
+		return errors.E(op, err)
+	}
+	if err := j.Database.DeleteModelTemplate(ctx, &dbmodel.ModelTemplate{Name: name}); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// GetModelTemplate returns the model template with the given name. Any
+// user may read model templates so that they can create models from
+// them.
+func (j *JIMM) GetModelTemplate(ctx context.Context, user *openfga.User, name string) (apiparams.ModelTemplate, error) {
+	const op = errors.Op("jimm.GetModelTemplate")
+
+	t := dbmodel.ModelTemplate{Name: name}
+	if err := j.Database.GetModelTemplate(ctx, &t); err != nil {
+		return apiparams.ModelTemplate{}, errors.E(op, err)
+	}
+	return j.modelTemplateToParams(ctx, t), nil
+}
+
+// ListModelTemplates returns all the model templates. Any user may list
+// model templates.
+func (j *JIMM) ListModelTemplates(ctx context.Context, user *openfga.User) ([]apiparams.ModelTemplate, error) {
+	const op = errors.Op("jimm.ListModelTemplates")
+
+	templates, err := j.Database.ListModelTemplates(ctx)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	result := make([]apiparams.ModelTemplate, len(templates))
+	for i, t := range templates {
+		result[i] = j.modelTemplateToParams(ctx, t)
+	}
+	return result, nil
+}
+
+// modelTemplateToParams converts the given database model template to its
+// API representation, with the grants keyed by group name.
+func (j *JIMM) modelTemplateToParams(ctx context.Context, t dbmodel.ModelTemplate) apiparams.ModelTemplate {
+	var grants map[string]string
+	if t.Grants != nil {
+		grants = make(map[string]string, len(t.Grants))
+	}
+	for uuid, access := range t.Grants {
+		// Report the UUID of any group that has since been removed.
+		group := dbmodel.GroupEntry{UUID: uuid}
+		if err := j.Database.GetGroup(ctx, &group); err == nil {
+			uuid = group.Name
+		}
+		grants[uuid] = access
+	}
+	return t.ToAPIModelTemplate(grants)
+}
+
+// modelTemplateFromParams validates the given template parameters and
+// converts them to a database model template.
+func (j *JIMM) modelTemplateFromParams(ctx context.Context, p apiparams.ModelTemplate) (dbmodel.ModelTemplate, error) {
+	if p.Name == "" {
+		return dbmodel.ModelTemplate{}, errors.E(errors.CodeBadRequest, "template name not specified")
+	}
+	for _, k := range []string{ModelTemplateConfigKey, ModelTTLConfigKey} {
+		if _, ok := p.Config[k]; ok {
+			return dbmodel.ModelTemplate{}, errors.E(errors.CodeBadRequest, fmt.Sprintf("config key %q cannot be used in a template", k))
+		}
+	}
+	if p.Cloud != "" {
+		cloud := dbmodel.Cloud{Name: p.Cloud}
+		if err := j.Database.GetCloud(ctx, &cloud); err != nil {
+			return dbmodel.ModelTemplate{}, err
+		}
+		if p.Region != "" && cloud.Region(p.Region).Name == "" {
+			return dbmodel.ModelTemplate{}, errors.E(errors.CodeNotFound, fmt.Sprintf("cloud region %s/%s not found", p.Cloud, p.Region))
+		}
+	} else if p.Region != "" {
+		return dbmodel.ModelTemplate{}, errors.E(errors.CodeBadRequest, "region specified without cloud")
+	}
+	if p.CloudCredential != "" && !names.IsValidCloudCredentialName(p.CloudCredential) {
+		return dbmodel.ModelTemplate{}, errors.E(errors.CodeBadRequest, fmt.Sprintf("invalid cloud credential name %q", p.CloudCredential))
+	}
+	for k, v := range p.Labels {
+		if err := dbmodel.ValidateLabelKey(k); err != nil {
+			return dbmodel.ModelTemplate{}, err
+		}
+		if err := dbmodel.ValidateLabelValue(v); err != nil {
+			return dbmodel.ModelTemplate{}, err
+		}
+	}
+	var grants dbmodel.StringMap
+	if p.Grants != nil {
+		grants = make(dbmodel.StringMap, len(p.Grants))
+	}
+	for groupName, access := range p.Grants {
+		switch jujupermission.Access(access) {
+		case jujupermission.ReadAccess, jujupermission.WriteAccess, jujupermission.AdminAccess:
+		default:
+			return dbmodel.ModelTemplate{}, errors.E(errors.CodeBadRequest, fmt.Sprintf("invalid access level %q for group %q", access, groupName))
+		}
+		group := dbmodel.GroupEntry{Name: groupName}
+		if err := j.Database.GetGroup(ctx, &group); err != nil {
+			return dbmodel.ModelTemplate{}, err
+		}
+		grants[group.UUID] = access
+	}
+	return dbmodel.ModelTemplate{
+		Name:            p.Name,
+		Config:          p.Config,
+		Cloud:           p.Cloud,
+		Region:          p.Region,
+		CloudCredential: p.CloudCredential,
+		Labels:          p.Labels,
+		Grants:          grants,
+	}, nil
+}
+
+// WithTemplate returns a builder that creates a model from the template
+// with the given name. An empty name creates a model without a template.
+// The template must be set before the cloud and cloud region so that its
+// constraints can be applied.
+func (b *modelBuilder) WithTemplate(name string) *modelBuilder {
+	if b.err != nil || name == "" {
+		return b
+	}
+	t := dbmodel.ModelTemplate{Name: name}
+	if err := b.jimm.Database.GetModelTemplate(b.ctx, &t); err != nil {
+		b.err = errors.E(err, fmt.Sprintf("failed to fetch model template %q", name))
+		return b
+	}
+	b.template = &t
+	return b
+}
+
+// templateCloud returns the cloud to use given the requested cloud and
+// the cloud constraint of the template, if any.
+func (b *modelBuilder) templateCloud(cloud names.CloudTag) (names.CloudTag, error) {
+	if b.template == nil || b.template.Cloud == "" {
+		return cloud, nil
+	}
+	if cloud.Id() != "" && cloud.Id() != b.template.Cloud {
+		return cloud, errors.E(errors.CodeBadRequest, fmt.Sprintf("model template %q requires cloud %q", b.template.Name, b.template.Cloud))
+	}
+	return names.NewCloudTag(b.template.Cloud), nil
+}
+
+// templateCloudRegion returns the cloud region to use given the
+// requested region and the region constraint of the template, if any.
+func (b *modelBuilder) templateCloudRegion(region string) (string, error) {
+	if b.template == nil || b.template.Region == "" {
+		return region, nil
+	}
+	if region != "" && region != b.template.Region {
+		return region, errors.E(errors.CodeBadRequest, fmt.Sprintf("model template %q requires region %q", b.template.Name, b.template.Region))
+	}
+	return b.template.Region, nil
+}
+
+// templateCloudCredential returns the tag of the credential selected by
+// the template, or a zero tag if the template doesn't select one.
+func (b *modelBuilder) templateCloudCredential() names.CloudCredentialTag {
+	if b.template == nil || b.template.CloudCredential == "" || b.cloud == nil || b.owner == nil {
+		return names.CloudCredentialTag{}
+	}
+	return names.NewCloudCredentialTag(fmt.Sprintf("%s/%s/%s", b.cloud.Name, b.owner.Name, b.template.CloudCredential))
+}
+
+// ApplyTemplateGrants grants the groups listed in the template access to
+// the newly created model. Groups that have been removed since the
+// template was set are skipped.
+func (b *modelBuilder) ApplyTemplateGrants() *modelBuilder {
+	if b.err != nil || b.template == nil || len(b.template.Grants) == 0 {
+		return b
+	}
+	if b.modelInfo == nil {
+		b.err = errors.E("model not created")
+		return b
+	}
+	groupUUIDs := make([]string, 0, len(b.template.Grants))
+	for uuid := range b.template.Grants {
+		groupUUIDs = append(groupUUIDs, uuid)
+	}
+	sort.Strings(groupUUIDs)
+
+	modelTag := ofganames.ConvertTag(names.NewModelTag(b.modelInfo.UUID))
+	tuples := make([]openfga.Tuple, 0, len(groupUUIDs))
+	for _, uuid := range groupUUIDs {
+		group := dbmodel.GroupEntry{UUID: uuid}
+		if err := b.jimm.Database.GetGroup(b.ctx, &group); err != nil {
+			if errors.ErrorCode(err) == errors.CodeNotFound {
+				zapctx.Warn(b.ctx, "skipping template grant to removed group", zap.String("template", b.template.Name), zap.String("group", uuid))
+				continue
+			}
+			b.err = errors.E(err, fmt.Sprintf("failed to fetch group %q", uuid))
+			return b
+		}
+		relation, err := ofganames.ConvertJujuRelation(b.template.Grants[uuid])
+		if err != nil {
+			b.err = errors.E(err, errors.CodeBadRequest)
+			return b
+		}
+		tuples = append(tuples, openfga.Tuple{
+			Object:   ofganames.ConvertTagWithRelation(group.ResourceTag(), ofganames.MemberRelation),
+			Relation: relation,
+			Target:   modelTag,
+		})
+	}
+	if len(tuples) == 0 {
+		return b
+	}
+	if err := b.jimm.OpenFGAClient.AddRelation(b.ctx, tuples...); err != nil {
+		b.err = errors.E(err, errors.CodeOpenFGARequestFailed, "failed to grant template access")
+	}
+	return b
+}
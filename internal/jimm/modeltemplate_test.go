@@ -0,0 +1,251 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+func TestSetModelTemplateUnauthorized(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+	j, alice, _ := newAddModelTestJIMM(c, &addModelTestAPI{}, false)
+
+	err := j.SetModelTemplate(ctx, alice, apiparams.ModelTemplate{Name: "prod"})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+	err = j.RemoveModelTemplate(ctx, alice, "prod")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+}
+
+func TestSetModelTemplateInvalid(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+	j, _, admin := newAddModelTestJIMM(c, &addModelTestAPI{}, true)
+
+	tests := []struct {
+		template    apiparams.ModelTemplate
+		expectError string
+		expectCode  errors.Code
+	}{{
+		template:    apiparams.ModelTemplate{},
+		expectError: `template name not specified`,
+		expectCode:  errors.CodeBadRequest,
+	}, {
+		template:    apiparams.ModelTemplate{Name: "t", Config: map[string]interface{}{"jimm-ttl": "1h"}},
+		expectError: `config key "jimm-ttl" cannot be used in a template`,
+		expectCode:  errors.CodeBadRequest,
+	}, {
+		template:   apiparams.ModelTemplate{Name: "t", Cloud: "no-such-cloud"},
+		expectCode: errors.CodeNotFound,
+	}, {
+		template:    apiparams.ModelTemplate{Name: "t", Cloud: "test-cloud", Region: "no-such-region"},
+		expectError: `cloud region test-cloud/no-such-region not found`,
+		expectCode:  errors.CodeNotFound,
+	}, {
+		template:    apiparams.ModelTemplate{Name: "t", Region: "test-region-1"},
+		expectError: `region specified without cloud`,
+		expectCode:  errors.CodeBadRequest,
+	}, {
+		template:    apiparams.ModelTemplate{Name: "t", Labels: map[string]string{"env": "a,b"}},
+		expectError: `invalid label value .*`,
+		expectCode:  errors.CodeBadRequest,
+	}, {
+		template:    apiparams.ModelTemplate{Name: "t", Grants: map[string]string{"ops": "consume"}},
+		expectError: `invalid access level "consume" for group "ops"`,
+		expectCode:  errors.CodeBadRequest,
+	}, {
+		template:   apiparams.ModelTemplate{Name: "t", Grants: map[string]string{"no-such-group": "read"}},
+		expectCode: errors.CodeNotFound,
+	}}
+	for _, test := range tests {
+		err := j.SetModelTemplate(ctx, admin, test.template)
+		if test.expectError != "" {
+			c.Check(err, qt.ErrorMatches, test.expectError)
+		}
+		c.Check(errors.ErrorCode(err), qt.Equals, test.expectCode)
+	}
+}
+
+func TestAddModelFromTemplate(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+	api := &addModelTestAPI{}
+	j, alice, admin := newAddModelTestJIMM(c, api, true)
+
+	group, err := j.Database.AddGroup(ctx, "ops")
+	c.Assert(err, qt.IsNil)
+	err = j.SetModelTemplate(ctx, admin, apiparams.ModelTemplate{
+		Name:            "prod",
+		Config:          map[string]interface{}{"logging-config": "<root>=WARNING"},
+		Cloud:           "test-cloud",
+		Region:          "test-region-1",
+		CloudCredential: "test-credential-1",
+		Labels:          map[string]string{"env": "prod"},
+		Grants:          map[string]string{"ops": "write"},
+	})
+	c.Assert(err, qt.IsNil)
+
+	templates, err := j.ListModelTemplates(ctx, alice)
+	c.Assert(err, qt.IsNil)
+	c.Assert(templates, qt.HasLen, 1)
+	c.Check(templates[0].Name, qt.Equals, "prod")
+
+	// The template's cloud constraint is enforced.
+	_, err = addTestModel(ctx, j, alice, jujuparams.ModelCreateArgs{
+		CloudTag:    names.NewCloudTag("other-cloud").String(),
+		CloudRegion: "test-region-1",
+		Config:      map[string]interface{}{jimm.ModelTemplateConfigKey: "prod"},
+	})
+	c.Check(err, qt.ErrorMatches, `model template "prod" requires cloud "test-cloud"`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+
+	// An unknown template is rejected.
+	_, err = addTestModel(ctx, j, alice, jujuparams.ModelCreateArgs{
+		Config: map[string]interface{}{jimm.ModelTemplateConfigKey: "staging"},
+	})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	mi, err := addTestModel(ctx, j, alice, jujuparams.ModelCreateArgs{
+		Config: map[string]interface{}{
+			jimm.ModelTemplateConfigKey: "prod",
+			"logging-config":            "<root>=DEBUG",
+			"default-series":            "jammy",
+		},
+	})
+	c.Assert(err, qt.IsNil)
+	c.Check(mi.CloudRegion, qt.Equals, "test-region-1")
+	c.Check(mi.CloudCredentialTag, qt.Equals, names.NewCloudCredentialTag("test-cloud/alice@canonical.com/test-credential-1").String())
+	// Template config takes precedence and JIMM specific keys are not
+	// passed to the controller.
+	c.Check(api.config, qt.DeepEquals, map[string]interface{}{
+		"logging-config": "<root>=WARNING",
+		"default-series": "jammy",
+	})
+
+	labels, err := j.GetModelLabels(ctx, alice, names.NewModelTag(mi.UUID))
+	c.Assert(err, qt.IsNil)
+	c.Check(labels, qt.DeepEquals, map[string]string{"env": "prod"})
+
+	ok, err := j.OpenFGAClient.CheckRelation(ctx, openfga.Tuple{
+		Object:   ofganames.ConvertTagWithRelation(group.ResourceTag(), ofganames.MemberRelation),
+		Relation: ofganames.WriterRelation,
+		Target:   ofganames.ConvertTag(names.NewModelTag(mi.UUID)),
+	}, false)
+	c.Assert(err, qt.IsNil)
+	c.Check(ok, qt.IsTrue)
+}
+
+func TestAddModelFromTemplateRollback(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+	api := &addModelTestAPI{}
+	j, alice, admin := newAddModelTestJIMM(c, api, true)
+
+	group, err := j.Database.AddGroup(ctx, "ops")
+	c.Assert(err, qt.IsNil)
+
+	// A grant that cannot be converted to a relation fails after the
+	// model is created on the controller. Such a grant can only be
+	// written directly to the database.
+	err = j.Database.SetModelTemplate(ctx, &dbmodel.ModelTemplate{
+		Name:   "prod",
+		Grants: dbmodel.StringMap{group.UUID: "superuser"},
+	})
+	c.Assert(err, qt.IsNil)
+
+	_, err = addTestModel(ctx, j, alice, jujuparams.ModelCreateArgs{
+		CloudTag:           names.NewCloudTag("test-cloud").String(),
+		CloudCredentialTag: names.NewCloudCredentialTag("test-cloud/alice@canonical.com/test-credential-1").String(),
+		Config:             map[string]interface{}{jimm.ModelTemplateConfigKey: "prod"},
+	})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+	c.Check(api.destroyed.Load(), qt.Equals, int32(1))
+
+	m := dbmodel.Model{
+		Name:              "model-2",
+		OwnerIdentityName: "alice@canonical.com",
+	}
+	err = j.Database.GetModel(ctx, &m)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	// A failure after the grants have been written removes them along
+	// with the model.
+	err = j.SetModelTemplate(ctx, admin, apiparams.ModelTemplate{
+		Name:   "prod",
+		Grants: map[string]string{"ops": "read"},
+	})
+	c.Assert(err, qt.IsNil)
+	c.Patch(jimm.AddControllerModel, func(*openfga.OFGAClient, context.Context, names.ControllerTag, names.ModelTag) error {
+		return errors.E("openfga unavailable")
+	})
+	_, err = addTestModel(ctx, j, alice, jujuparams.ModelCreateArgs{
+		CloudTag:           names.NewCloudTag("test-cloud").String(),
+		CloudCredentialTag: names.NewCloudCredentialTag("test-cloud/alice@canonical.com/test-credential-1").String(),
+		Config:             map[string]interface{}{jimm.ModelTemplateConfigKey: "prod"},
+	})
+	c.Check(err, qt.ErrorMatches, `openfga unavailable`)
+	c.Check(api.destroyed.Load(), qt.Equals, int32(2))
+
+	err = j.Database.GetModel(ctx, &m)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+	ok, err := j.OpenFGAClient.CheckRelation(ctx, openfga.Tuple{
+		Object:   ofganames.ConvertTagWithRelation(group.ResourceTag(), ofganames.MemberRelation),
+		Relation: ofganames.ReaderRelation,
+		Target:   ofganames.ConvertTag(names.NewModelTag("00000001-0000-0000-0000-0000-000000000002")),
+	}, false)
+	c.Assert(err, qt.IsNil)
+	c.Check(ok, qt.IsFalse)
+}
+
+func TestAddModelFromTemplateChangedGroups(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+	api := &addModelTestAPI{}
+	j, alice, admin := newAddModelTestJIMM(c, api, true)
+
+	ops, err := j.Database.AddGroup(ctx, "ops")
+	c.Assert(err, qt.IsNil)
+	dev, err := j.Database.AddGroup(ctx, "dev")
+	c.Assert(err, qt.IsNil)
+	err = j.SetModelTemplate(ctx, admin, apiparams.ModelTemplate{
+		Name:   "prod",
+		Grants: map[string]string{"ops": "write", "dev": "read"},
+	})
+	c.Assert(err, qt.IsNil)
+
+	// Templates follow a renamed group and skip a removed one.
+	err = j.Database.UpdateGroupName(ctx, ops.UUID, "operations")
+	c.Assert(err, qt.IsNil)
+	err = j.Database.RemoveGroup(ctx, dev)
+	c.Assert(err, qt.IsNil)
+
+	template, err := j.GetModelTemplate(ctx, alice, "prod")
+	c.Assert(err, qt.IsNil)
+	c.Check(template.Grants, qt.DeepEquals, map[string]string{"operations": "write", dev.UUID: "read"})
+
+	mi, err := addTestModel(ctx, j, alice, jujuparams.ModelCreateArgs{
+		Config: map[string]interface{}{jimm.ModelTemplateConfigKey: "prod"},
+	})
+	c.Assert(err, qt.IsNil)
+	c.Check(api.destroyed.Load(), qt.Equals, int32(0))
+
+	ok, err := j.OpenFGAClient.CheckRelation(ctx, openfga.Tuple{
+		Object:   ofganames.ConvertTagWithRelation(ops.ResourceTag(), ofganames.MemberRelation),
+		Relation: ofganames.WriterRelation,
+		Target:   ofganames.ConvertTag(names.NewModelTag(mi.UUID)),
+	}, false)
+	c.Assert(err, qt.IsNil)
+	c.Check(ok, qt.IsTrue)
+}
@@ -4,6 +4,7 @@ package jimm
 import (
 	"context"
 
+	jujuparams "github.com/juju/juju/rpc/params"
 	"github.com/juju/zaputil/zapctx"
 	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
@@ -36,3 +37,176 @@ func (j *JIMM) UpdateMetrics(ctx context.Context) {
 	}
 	servermon.ControllerCount.Set(float64(controllerCount))
 }
+
+// modelHealthMetrics aggregates the model summaries received from a
+// controller into the model health metrics of that controller.
+type modelHealthMetrics struct {
+	controller string
+	perModel   bool
+	models     map[string]modelHealth
+
+	// agentVersions holds the agent version of each model, keyed by
+	// model UUID. The versions are not part of the summaries sent by
+	// the model summary watcher so they are recorded separately.
+	agentVersions map[string]string
+
+	// statuses, owners and versions hold the label values exported by
+	// the previous call to export, so that series that no longer apply
+	// can be removed.
+	statuses map[string]bool
+	owners   map[string]bool
+	versions map[string]bool
+}
+
+// modelHealth holds the parts of a model summary used in the model
+// health metrics.
+type modelHealth struct {
+	name     string
+	owner    string
+	status   string
+	machines int
+	units    int
+}
+
+// newModelHealthMetrics returns a modelHealthMetrics for the named
+// controller. If perModel is true metrics labelled with each model are
+// also exported.
+func newModelHealthMetrics(controller string, perModel bool) *modelHealthMetrics {
+	return &modelHealthMetrics{
+		controller:    controller,
+		perModel:      perModel,
+		models:        make(map[string]modelHealth),
+		agentVersions: make(map[string]string),
+	}
+}
+
+// update records the given summary of a model owned by the given owner.
+func (h *modelHealthMetrics) update(summary jujuparams.ModelAbstract, owner string) {
+	prev, ok := h.models[summary.UUID]
+	mh := modelHealth{
+		name:     summary.Name,
+		owner:    owner,
+		status:   summary.Status,
+		machines: summary.Size.Machines,
+		units:    summary.Size.Units,
+	}
+	h.models[summary.UUID] = mh
+	if !h.perModel {
+		return
+	}
+	if ok && prev != mh {
+		h.deleteModelSeries(summary.UUID, prev)
+	}
+	servermon.ModelSummaryModelMachines.WithLabelValues(h.controller, summary.UUID, mh.name, mh.owner).Set(float64(mh.machines))
+	servermon.ModelSummaryModelUnits.WithLabelValues(h.controller, summary.UUID, mh.name, mh.owner).Set(float64(mh.units))
+	servermon.ModelSummaryModelStatus.WithLabelValues(h.controller, summary.UUID, mh.name, mh.status).Set(1)
+}
+
+// updateAgentVersions records the agent versions of the models in the
+// given summaries.
+func (h *modelHealthMetrics) updateAgentVersions(results jujuparams.ModelSummaryResults) {
+	for _, r := range results.Results {
+		if r.Error != nil || r.Result == nil || r.Result.AgentVersion == nil {
+			continue
+		}
+		h.agentVersions[r.Result.UUID] = r.Result.AgentVersion.String()
+	}
+}
+
+// remove removes the model with the given UUID from the metrics.
+func (h *modelHealthMetrics) remove(uuid string) {
+	delete(h.agentVersions, uuid)
+	mh, ok := h.models[uuid]
+	if !ok {
+		return
+	}
+	delete(h.models, uuid)
+	if h.perModel {
+		h.deleteModelSeries(uuid, mh)
+	}
+}
+
+func (h *modelHealthMetrics) deleteModelSeries(uuid string, mh modelHealth) {
+	servermon.ModelSummaryModelMachines.DeleteLabelValues(h.controller, uuid, mh.name, mh.owner)
+	servermon.ModelSummaryModelUnits.DeleteLabelValues(h.controller, uuid, mh.name, mh.owner)
+	servermon.ModelSummaryModelStatus.DeleteLabelValues(h.controller, uuid, mh.name, mh.status)
+}
+
+// export updates the per-controller metrics from the recorded model
+// summaries.
+func (h *modelHealthMetrics) export() {
+	statuses := make(map[string]int)
+	machines := make(map[string]int)
+	units := make(map[string]int)
+	versions := make(map[string]int)
+	for uuid, mh := range h.models {
+		statuses[mh.status]++
+		machines[mh.owner] += mh.machines
+		units[mh.owner] += mh.units
+		if v, ok := h.agentVersions[uuid]; ok {
+			versions[v]++
+		}
+	}
+
+	for status, n := range statuses {
+		servermon.ModelSummaryModelCount.WithLabelValues(h.controller, status).Set(float64(n))
+	}
+	for status := range h.statuses {
+		if _, ok := statuses[status]; !ok {
+			servermon.ModelSummaryModelCount.DeleteLabelValues(h.controller, status)
+		}
+	}
+	servermon.ModelSummaryErrorModelCount.WithLabelValues(h.controller).Set(float64(statuses["error"]))
+	for owner := range machines {
+		servermon.ModelSummaryMachineCount.WithLabelValues(h.controller, owner).Set(float64(machines[owner]))
+		servermon.ModelSummaryUnitCount.WithLabelValues(h.controller, owner).Set(float64(units[owner]))
+	}
+	for owner := range h.owners {
+		if _, ok := machines[owner]; !ok {
+			servermon.ModelSummaryMachineCount.DeleteLabelValues(h.controller, owner)
+			servermon.ModelSummaryUnitCount.DeleteLabelValues(h.controller, owner)
+		}
+	}
+
+	for v, n := range versions {
+		servermon.ModelSummaryAgentVersionCount.WithLabelValues(h.controller, v).Set(float64(n))
+	}
+	for v := range h.versions {
+		if _, ok := versions[v]; !ok {
+			servermon.ModelSummaryAgentVersionCount.DeleteLabelValues(h.controller, v)
+		}
+	}
+
+	h.statuses = make(map[string]bool, len(statuses))
+	for status := range statuses {
+		h.statuses[status] = true
+	}
+	h.owners = make(map[string]bool, len(machines))
+	for owner := range machines {
+		h.owners[owner] = true
+	}
+	h.versions = make(map[string]bool, len(versions))
+	for v := range versions {
+		h.versions[v] = true
+	}
+}
+
+// reset removes all the model health metrics of the controller. It is
+// used when the model summaries of the controller are no longer being
+// watched, so the metrics would become stale.
+func (h *modelHealthMetrics) reset() {
+	labels := prometheus.Labels{"controller": h.controller}
+	servermon.ModelSummaryModelCount.DeletePartialMatch(labels)
+	servermon.ModelSummaryErrorModelCount.DeletePartialMatch(labels)
+	servermon.ModelSummaryMachineCount.DeletePartialMatch(labels)
+	servermon.ModelSummaryUnitCount.DeletePartialMatch(labels)
+	servermon.ModelSummaryAgentVersionCount.DeletePartialMatch(labels)
+	servermon.ModelSummaryModelMachines.DeletePartialMatch(labels)
+	servermon.ModelSummaryModelUnits.DeletePartialMatch(labels)
+	servermon.ModelSummaryModelStatus.DeletePartialMatch(labels)
+	h.models = make(map[string]modelHealth)
+	h.agentVersions = make(map[string]string)
+	h.statuses = nil
+	h.owners = nil
+	h.versions = nil
+}
@@ -0,0 +1,181 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/version/v2"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+func TestModelHealthMetrics(t *testing.T) {
+	c := qt.New(t)
+
+	h := newModelHealthMetrics("test-controller", false)
+	defer h.reset()
+
+	h.update(jujuparams.ModelAbstract{
+		UUID:   "00000002-0000-0000-0000-000000000001",
+		Name:   "model-1",
+		Status: "available",
+		Size:   jujuparams.ModelSummarySize{Machines: 2, Units: 3},
+	}, "alice@canonical.com")
+	h.update(jujuparams.ModelAbstract{
+		UUID:   "00000002-0000-0000-0000-000000000002",
+		Name:   "model-2",
+		Status: "error",
+		Size:   jujuparams.ModelSummarySize{Machines: 1, Units: 1},
+	}, "alice@canonical.com")
+	h.update(jujuparams.ModelAbstract{
+		UUID:   "00000002-0000-0000-0000-000000000003",
+		Name:   "model-3",
+		Status: "available",
+		Size:   jujuparams.ModelSummarySize{Machines: 4, Units: 8},
+	}, "bob@canonical.com")
+	h.export()
+
+	err := testutil.CollectAndCompare(servermon.ModelSummaryModelCount, strings.NewReader(`
+# HELP jimm_model_summary_models The number of models on each controller, by model status.
+# TYPE jimm_model_summary_models gauge
+jimm_model_summary_models{controller="test-controller",status="available"} 2
+jimm_model_summary_models{controller="test-controller",status="error"} 1
+`))
+	c.Check(err, qt.IsNil)
+	c.Check(testutil.ToFloat64(servermon.ModelSummaryErrorModelCount.WithLabelValues("test-controller")), qt.Equals, float64(1))
+	c.Check(testutil.ToFloat64(servermon.ModelSummaryMachineCount.WithLabelValues("test-controller", "alice@canonical.com")), qt.Equals, float64(3))
+	c.Check(testutil.ToFloat64(servermon.ModelSummaryUnitCount.WithLabelValues("test-controller", "alice@canonical.com")), qt.Equals, float64(4))
+	c.Check(testutil.ToFloat64(servermon.ModelSummaryMachineCount.WithLabelValues("test-controller", "bob@canonical.com")), qt.Equals, float64(4))
+	c.Check(testutil.ToFloat64(servermon.ModelSummaryUnitCount.WithLabelValues("test-controller", "bob@canonical.com")), qt.Equals, float64(8))
+
+	// Per-model metrics are not exported unless enabled.
+	c.Check(testutil.CollectAndCount(servermon.ModelSummaryModelUnits), qt.Equals, 0)
+
+	// Series that no longer apply are removed.
+	h.update(jujuparams.ModelAbstract{
+		UUID:   "00000002-0000-0000-0000-000000000002",
+		Name:   "model-2",
+		Status: "available",
+		Size:   jujuparams.ModelSummarySize{Machines: 1, Units: 1},
+	}, "alice@canonical.com")
+	h.remove("00000002-0000-0000-0000-000000000003")
+	h.export()
+
+	err = testutil.CollectAndCompare(servermon.ModelSummaryModelCount, strings.NewReader(`
+# HELP jimm_model_summary_models The number of models on each controller, by model status.
+# TYPE jimm_model_summary_models gauge
+jimm_model_summary_models{controller="test-controller",status="available"} 2
+`))
+	c.Check(err, qt.IsNil)
+	c.Check(testutil.ToFloat64(servermon.ModelSummaryErrorModelCount.WithLabelValues("test-controller")), qt.Equals, float64(0))
+	c.Check(testutil.CollectAndCount(servermon.ModelSummaryMachineCount), qt.Equals, 1)
+
+	h.reset()
+	c.Check(testutil.CollectAndCount(servermon.ModelSummaryModelCount), qt.Equals, 0)
+	c.Check(testutil.CollectAndCount(servermon.ModelSummaryErrorModelCount), qt.Equals, 0)
+	c.Check(testutil.CollectAndCount(servermon.ModelSummaryMachineCount), qt.Equals, 0)
+	c.Check(testutil.CollectAndCount(servermon.ModelSummaryUnitCount), qt.Equals, 0)
+}
+
+func TestModelHealthMetricsPerModel(t *testing.T) {
+	c := qt.New(t)
+
+	h := newModelHealthMetrics("test-controller", true)
+	defer h.reset()
+
+	h.update(jujuparams.ModelAbstract{
+		UUID:   "00000002-0000-0000-0000-000000000001",
+		Name:   "model-1",
+		Status: "available",
+		Size:   jujuparams.ModelSummarySize{Machines: 2, Units: 3},
+	}, "alice@canonical.com")
+	h.export()
+
+	c.Check(testutil.ToFloat64(servermon.ModelSummaryModelMachines.WithLabelValues("test-controller", "00000002-0000-0000-0000-000000000001", "model-1", "alice@canonical.com")), qt.Equals, float64(2))
+	c.Check(testutil.ToFloat64(servermon.ModelSummaryModelUnits.WithLabelValues("test-controller", "00000002-0000-0000-0000-000000000001", "model-1", "alice@canonical.com")), qt.Equals, float64(3))
+	err := testutil.CollectAndCompare(servermon.ModelSummaryModelStatus, strings.NewReader(`
+# HELP jimm_model_summary_model_status The status of each model, the value is always 1. Only exported if per-model metrics are enabled.
+# TYPE jimm_model_summary_model_status gauge
+jimm_model_summary_model_status{controller="test-controller",model_name="model-1",model_uuid="00000002-0000-0000-0000-000000000001",status="available"} 1
+`))
+	c.Check(err, qt.IsNil)
+
+	// A change of status replaces the status series.
+	h.update(jujuparams.ModelAbstract{
+		UUID:   "00000002-0000-0000-0000-000000000001",
+		Name:   "model-1",
+		Status: "error",
+		Size:   jujuparams.ModelSummarySize{Machines: 2, Units: 3},
+	}, "alice@canonical.com")
+	err = testutil.CollectAndCompare(servermon.ModelSummaryModelStatus, strings.NewReader(`
+# HELP jimm_model_summary_model_status The status of each model, the value is always 1. Only exported if per-model metrics are enabled.
+# TYPE jimm_model_summary_model_status gauge
+jimm_model_summary_model_status{controller="test-controller",model_name="model-1",model_uuid="00000002-0000-0000-0000-000000000001",status="error"} 1
+`))
+	c.Check(err, qt.IsNil)
+
+	h.remove("00000002-0000-0000-0000-000000000001")
+	c.Check(testutil.CollectAndCount(servermon.ModelSummaryModelMachines), qt.Equals, 0)
+	c.Check(testutil.CollectAndCount(servermon.ModelSummaryModelUnits), qt.Equals, 0)
+	c.Check(testutil.CollectAndCount(servermon.ModelSummaryModelStatus), qt.Equals, 0)
+}
+
+func TestModelHealthMetricsAgentVersions(t *testing.T) {
+	c := qt.New(t)
+
+	h := newModelHealthMetrics("test-controller", false)
+	defer h.reset()
+
+	for i, name := range []string{"model-1", "model-2", "model-3"} {
+		h.update(jujuparams.ModelAbstract{
+			UUID:   fmt.Sprintf("00000002-0000-0000-0000-00000000000%d", i+1),
+			Name:   name,
+			Status: "available",
+		}, "alice@canonical.com")
+	}
+	v1 := version.MustParse("3.5.4")
+	v2 := version.MustParse("3.6.0")
+	h.updateAgentVersions(jujuparams.ModelSummaryResults{
+		Results: []jujuparams.ModelSummaryResult{{
+			Result: &jujuparams.ModelSummary{UUID: "00000002-0000-0000-0000-000000000001", AgentVersion: &v1},
+		}, {
+			Result: &jujuparams.ModelSummary{UUID: "00000002-0000-0000-0000-000000000002", AgentVersion: &v1},
+		}, {
+			Result: &jujuparams.ModelSummary{UUID: "00000002-0000-0000-0000-000000000003", AgentVersion: &v2},
+		}, {
+			// Models that JIMM is not watching are not counted.
+			Result: &jujuparams.ModelSummary{UUID: "00000002-0000-0000-0000-000000000004", AgentVersion: &v2},
+		}, {
+			Error: &jujuparams.Error{Message: "test error"},
+		}},
+	})
+	h.export()
+
+	err := testutil.CollectAndCompare(servermon.ModelSummaryAgentVersionCount, strings.NewReader(`
+# HELP jimm_model_summary_agent_versions The number of models on each controller, by model agent version.
+# TYPE jimm_model_summary_agent_versions gauge
+jimm_model_summary_agent_versions{controller="test-controller",version="3.5.4"} 2
+jimm_model_summary_agent_versions{controller="test-controller",version="3.6.0"} 1
+`))
+	c.Check(err, qt.IsNil)
+
+	// Series that no longer apply are removed.
+	h.remove("00000002-0000-0000-0000-000000000003")
+	h.export()
+
+	err = testutil.CollectAndCompare(servermon.ModelSummaryAgentVersionCount, strings.NewReader(`
+# HELP jimm_model_summary_agent_versions The number of models on each controller, by model agent version.
+# TYPE jimm_model_summary_agent_versions gauge
+jimm_model_summary_agent_versions{controller="test-controller",version="3.5.4"} 2
+`))
+	c.Check(err, qt.IsNil)
+
+	h.reset()
+	c.Check(testutil.CollectAndCount(servermon.ModelSummaryAgentVersionCount), qt.Equals, 0)
+}
@@ -0,0 +1,174 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"time"
+
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+	"github.com/juju/zaputil/zapctx"
+	"go.uber.org/zap"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// RecordApplicationOfferConsumed records that the identity with the given
+// name consumed the application offer into the model with the given UUID.
+// The offer is identified by its UUID or, if that is empty, its URL.
+func (j *JIMM) RecordApplicationOfferConsumed(ctx context.Context, identityName, modelUUID, offerUUID, offerURL string) error {
+	const op = errors.Op("jimm.RecordApplicationOfferConsumed")
+
+	offer := dbmodel.ApplicationOffer{
+		UUID: offerUUID,
+		URL:  offerURL,
+	}
+	if err := j.Database.GetApplicationOffer(ctx, &offer); err != nil {
+		return errors.E(op, err)
+	}
+	err := j.Database.AddApplicationOfferConsumeEvent(ctx, &dbmodel.ApplicationOfferConsumeEvent{
+		Time:               time.Now().UTC().Round(time.Millisecond),
+		ApplicationOfferID: offer.ID,
+		IdentityName:       identityName,
+		ModelUUID:          modelUUID,
+	})
+	if err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// ListApplicationOfferConsumers returns the consumers of the application
+// offer with the given URL. A consumer is an identity that has consumed the
+// offer into a model, either as recorded by JIMM or as reported by the
+// relations that the offering controller knows about. If revokedUser is
+// not empty only the consumers with relations that would break if that
+// user's access to the offer was revoked are returned. The user must be an
+// administrator of the offer.
+func (j *JIMM) ListApplicationOfferConsumers(ctx context.Context, user *openfga.User, offerURL, revokedUser string) ([]apiparams.OfferConsumer, error) {
+	const op = errors.Op("jimm.ListApplicationOfferConsumers")
+
+	var consumers []apiparams.OfferConsumer
+	err := j.doApplicationOfferAdmin(ctx, user, offerURL, false, func(offer *dbmodel.ApplicationOffer, api API) error {
+		events, err := j.Database.ListApplicationOfferConsumeEvents(ctx, offer)
+		if err != nil {
+			return err
+		}
+		var details jujuparams.ApplicationOfferAdminDetailsV5
+		details.OfferURL = offer.URL
+		if err := api.GetApplicationOffer(ctx, &details); err != nil {
+			return err
+		}
+		consumers = j.offerConsumers(ctx, events, details.Connections)
+		if revokedUser != "" {
+			consumers = relationsBrokenByRevoke(consumers, revokedUser)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	return consumers, nil
+}
+
+// offerConsumers combines the recorded consume events with the current
+// connections of an offer into a list of consumers, one for each identity
+// and model pair, sorted by identity and model.
+func (j *JIMM) offerConsumers(ctx context.Context, events []dbmodel.ApplicationOfferConsumeEvent, connections []jujuparams.OfferConnection) []apiparams.OfferConsumer {
+	type key struct {
+		identity  string
+		modelUUID string
+	}
+	consumers := make(map[key]*apiparams.OfferConsumer)
+	get := func(k key) *apiparams.OfferConsumer {
+		if c, ok := consumers[k]; ok {
+			return c
+		}
+		c := &apiparams.OfferConsumer{
+			Identity:  k.identity,
+			ModelUUID: k.modelUUID,
+		}
+		consumers[k] = c
+		return c
+	}
+	for _, e := range events {
+		c := get(key{e.IdentityName, e.ModelUUID})
+		if c.LastConsumed == nil || e.Time.After(*c.LastConsumed) {
+			t := e.Time
+			c.LastConsumed = &t
+		}
+	}
+	for _, conn := range connections {
+		mt, err := names.ParseModelTag(conn.SourceModelTag)
+		if err != nil {
+			continue
+		}
+		c := get(key{conn.Username, mt.Id()})
+		c.Relations = append(c.Relations, apiparams.OfferConsumerRelation{
+			RelationID: conn.RelationId,
+			Endpoint:   conn.Endpoint,
+			Status:     string(conn.Status.Status),
+		})
+	}
+
+	result := make([]apiparams.OfferConsumer, 0, len(consumers))
+	for k, c := range consumers {
+		m := dbmodel.Model{
+			UUID: sql.NullString{String: k.modelUUID, Valid: true},
+		}
+		if err := j.Database.GetModel(ctx, &m); err == nil {
+			c.ModelName = m.OwnerIdentityName + "/" + m.Name
+		}
+		result = append(result, *c)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Identity != result[j].Identity {
+			return result[i].Identity < result[j].Identity
+		}
+		return result[i].ModelUUID < result[j].ModelUUID
+	})
+	return result
+}
+
+// relationsBrokenByRevoke returns the relations of the given consumers
+// that rely on the given user's access to the offer. If the user is the
+// everyone user all relations are returned.
+func relationsBrokenByRevoke(consumers []apiparams.OfferConsumer, username string) []apiparams.OfferConsumer {
+	var broken []apiparams.OfferConsumer
+	for _, c := range consumers {
+		if len(c.Relations) == 0 {
+			continue
+		}
+		if username != ofganames.EveryoneUser && c.Identity != username {
+			continue
+		}
+		broken = append(broken, c)
+	}
+	return broken
+}
+
+// brokenOfferConsumers returns the consumers with relations on the
+// offer that rely on access that has been revoked from the given user. The
+// access has already been revoked, so failures to determine the consumers
+// are logged rather than returned.
+func (j *JIMM) brokenOfferConsumers(ctx context.Context, offer *dbmodel.ApplicationOffer, api API, username string) []apiparams.OfferConsumer {
+	events, err := j.Database.ListApplicationOfferConsumeEvents(ctx, offer)
+	if err != nil {
+		zapctx.Error(ctx, "cannot list offer consume events", zap.String("offer", offer.URL), zap.Error(err))
+		return nil
+	}
+	var details jujuparams.ApplicationOfferAdminDetailsV5
+	details.OfferURL = offer.URL
+	if err := api.GetApplicationOffer(ctx, &details); err != nil {
+		zapctx.Error(ctx, "cannot get offer connections", zap.String("offer", offer.URL), zap.Error(err))
+		return nil
+	}
+	return relationsBrokenByRevoke(j.offerConsumers(ctx, events, details.Connections), username)
+}
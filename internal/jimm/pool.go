@@ -0,0 +1,678 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/juju/names/v5"
+	"github.com/juju/zaputil/zapctx"
+	"go.uber.org/zap"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+// Default values for the PoolParams.
+const (
+	DefaultPoolMaxConnections      = 10
+	DefaultPoolIdleTimeout         = 5 * time.Minute
+	DefaultPoolHealthCheckInterval = 30 * time.Second
+	DefaultPoolFailureThreshold    = 5
+	DefaultPoolOpenTimeout         = 30 * time.Second
+)
+
+// PoolParams configures a ControllerPool. Any zero value is replaced with
+// the matching default.
+type PoolParams struct {
+	// MinConnections is the number of controller connections that are
+	// kept open to each active controller. A controller is active if a
+	// connection to it has been requested within the IdleTimeout.
+	MinConnections int
+
+	// MaxConnections is the maximum number of connections open to each
+	// controller, and to each model. A request for a connection when
+	// the maximum is reached waits for another connection to be
+	// returned.
+	MaxConnections int
+
+	// IdleTimeout is the time after which a connection that hasn't been
+	// used is closed.
+	IdleTimeout time.Duration
+
+	// HealthCheckInterval is the time after which an idle connection
+	// is pinged before being reused. It is also the interval between
+	// maintenance runs of the pool, see Run.
+	HealthCheckInterval time.Duration
+
+	// FailureThreshold is the number of consecutive failed dials after
+	// which the circuit breaker for a controller opens. Whilst the
+	// circuit is open requests for connections to the controller fail
+	// immediately.
+	FailureThreshold int
+
+	// OpenTimeout is the time the circuit breaker stays open before a
+	// single trial dial to the controller is allowed.
+	OpenTimeout time.Duration
+}
+
+func (p PoolParams) withDefaults() PoolParams {
+	if p.MaxConnections <= 0 {
+		p.MaxConnections = DefaultPoolMaxConnections
+	}
+	if p.MinConnections > p.MaxConnections {
+		p.MinConnections = p.MaxConnections
+	}
+	if p.IdleTimeout <= 0 {
+		p.IdleTimeout = DefaultPoolIdleTimeout
+	}
+	if p.HealthCheckInterval <= 0 {
+		p.HealthCheckInterval = DefaultPoolHealthCheckInterval
+	}
+	if p.FailureThreshold <= 0 {
+		p.FailureThreshold = DefaultPoolFailureThreshold
+	}
+	if p.OpenTimeout <= 0 {
+		p.OpenTimeout = DefaultPoolOpenTimeout
+	}
+	return p
+}
+
+// NewControllerPool returns a ControllerPool that uses the given Dialer to
+// make new connections.
+func NewControllerPool(d Dialer, p PoolParams) *ControllerPool {
+	return &ControllerPool{
+		dialer:      d,
+		params:      p.withDefaults(),
+		now:         time.Now,
+		controllers: make(map[string]*controllerConns),
+	}
+}
+
+// A ControllerPool is a Dialer that pools connections to controllers and
+// models, so that the cost of establishing connections is shared by a
+// number of operations. Each connection is used by one operation at a
+// time. Controller connections are pooled by controller name and model
+// connections by model UUID.
+//
+// Each controller has a circuit breaker that stops the pool dialing a
+// controller that is failing, so that requests fail fast rather than
+// waiting for a dial that is unlikely to succeed. Only dial errors that
+// wrap a TransportError count as failures; any other error shows that
+// the controller could be reached.
+type ControllerPool struct {
+	dialer Dialer
+	params PoolParams
+	now    func() time.Time
+
+	mu          sync.Mutex
+	closed      bool
+	controllers map[string]*controllerConns
+}
+
+// A TransportError is returned, wrapped, by a Dialer when the connection
+// to a controller could not be established, as opposed to being
+// established and then rejected.
+type TransportError struct {
+	Err error
+}
+
+// Error implements the error interface.
+func (e *TransportError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *TransportError) Unwrap() error {
+	return e.Err
+}
+
+// controllerConns holds the connections to a single controller.
+type controllerConns struct {
+	name string
+
+	// ctl is the controller as it was last dialed, it is used to dial
+	// new connections when topping up the pool.
+	ctl *dbmodel.Controller
+
+	// lastRequested is the time a connection to the controller was last
+	// requested.
+	lastRequested time.Time
+
+	breaker circuitBreaker
+
+	// pools contains the connection pools for the controller, keyed by
+	// model UUID. The pool for controller connections has the key "".
+	pools map[string]*connPool
+}
+
+// pool returns the connection pool for the given model UUID.
+func (cc *controllerConns) pool(modelUUID string) *connPool {
+	cp, ok := cc.pools[modelUUID]
+	if !ok {
+		cp = &connPool{available: make(chan struct{})}
+		cc.pools[modelUUID] = cp
+	}
+	return cp
+}
+
+// A connPool is a pool of equivalent connections.
+type connPool struct {
+	// idle contains the connections that are not in use, in the order
+	// they were returned.
+	idle []*pooledConn
+
+	// open is the number of connections that are idle, in use, or being
+	// dialed.
+	open int
+
+	// available is closed, and replaced, whenever a connection is
+	// returned to the pool or a connection is closed.
+	available chan struct{}
+}
+
+// notify wakes any requests waiting for a connection.
+func (cp *connPool) notify() {
+	close(cp.available)
+	cp.available = make(chan struct{})
+}
+
+// A pooledConn is a connection held by the pool.
+type pooledConn struct {
+	api API
+
+	// ctl holds the controller details reported when the connection
+	// was dialed.
+	ctl dbmodel.Controller
+
+	lastUsed    time.Time
+	lastChecked time.Time
+}
+
+// updateController updates the given controller with the details
+// reported by the controller when the connection was dialed.
+func (c *pooledConn) updateController(ctl *dbmodel.Controller) {
+	ctl.UUID = c.ctl.UUID
+	ctl.AgentVersion = c.ctl.AgentVersion
+	ctl.Addresses = c.ctl.Addresses
+}
+
+// Dial implements Dialer.Dial. An idle connection is used if one is
+// available, otherwise a new connection is dialed. If the maximum number
+// of connections are already open then Dial waits for one to be returned
+// to the pool. The returned connection is returned to the pool when it is
+// closed.
+func (p *ControllerPool) Dial(ctx context.Context, ctl *dbmodel.Controller, mt names.ModelTag, requiredPermissions map[string]string) (API, error) {
+	const op = errors.Op("jimm.ControllerPool.Dial")
+
+	durationObserver := servermon.DurationObserver(servermon.ControllerPoolAcquireDurationHistogram, ctl.Name)
+	defer durationObserver()
+
+	for {
+		p.mu.Lock()
+		if p.closed {
+			p.mu.Unlock()
+			return nil, errors.E(op, errors.CodeConnectionFailed, "connection pool closed")
+		}
+		cc := p.controllerLocked(ctl.Name)
+		cc.lastRequested = p.now()
+		cp := cc.pool(mt.Id())
+		if n := len(cp.idle); n > 0 {
+			conn := cp.idle[n-1]
+			cp.idle = cp.idle[:n-1]
+			p.updateMetricsLocked(cc)
+			p.mu.Unlock()
+			if reason := p.check(ctx, conn, ctl); reason != "" {
+				p.discard(cc, cp, conn, reason)
+				continue
+			}
+			conn.updateController(ctl)
+			return p.wrap(cc, cp, conn), nil
+		}
+		if cp.open < p.params.MaxConnections {
+			if !cc.breaker.allow(p.now(), p.params.OpenTimeout) {
+				p.mu.Unlock()
+				servermon.ControllerPoolDialCount.WithLabelValues(ctl.Name, "rejected").Inc()
+				return nil, errors.E(op, errors.CodeConnectionFailed, fmt.Sprintf("controller %q is unavailable after repeated connection failures", ctl.Name))
+			}
+			cp.open++
+			p.updateMetricsLocked(cc)
+			p.mu.Unlock()
+			return p.dial(ctx, cc, cp, ctl, mt, requiredPermissions)
+		}
+		available := cp.available
+		p.mu.Unlock()
+
+		select {
+		case <-available:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// controllerLocked returns the connections to the controller with the
+// given name. p.mu must be held.
+func (p *ControllerPool) controllerLocked(name string) *controllerConns {
+	cc, ok := p.controllers[name]
+	if !ok {
+		cc = &controllerConns{
+			name:  name,
+			pools: make(map[string]*connPool),
+		}
+		p.controllers[name] = cc
+	}
+	return cc
+}
+
+// check checks that an idle connection is still usable by the given
+// controller. If the connection is not usable the reason is returned.
+func (p *ControllerPool) check(ctx context.Context, conn *pooledConn, ctl *dbmodel.Controller) string {
+	if conn.api.IsBroken() {
+		return "broken"
+	}
+	if ctl.UUID != "" && conn.ctl.UUID != "" && ctl.UUID != conn.ctl.UUID {
+		// The controller has been replaced by a different
+		// controller with the same name.
+		return "stale"
+	}
+	if p.now().Sub(conn.lastChecked) < p.params.HealthCheckInterval {
+		return ""
+	}
+	if err := conn.api.Ping(ctx); err != nil {
+		zapctx.Warn(ctx, "pooled connection failed", zap.String("controller", ctl.Name), zap.Error(err))
+		return "unhealthy"
+	}
+	conn.lastChecked = p.now()
+	return ""
+}
+
+// dial dials a new connection for the given pool. The new connection
+// must already be counted in the pool's open connections. The dial
+// continues if the given context is canceled, and the connection is
+// added to the pool when it completes.
+func (p *ControllerPool) dial(ctx context.Context, cc *controllerConns, cp *connPool, ctl *dbmodel.Controller, mt names.ModelTag, requiredPermissions map[string]string) (API, error) {
+	type result struct {
+		conn *pooledConn
+		err  error
+	}
+	ch := make(chan result, 1)
+	dctl := *ctl
+	go func() {
+		// The connection is used beyond the lifetime of the
+		// request that caused it to be dialed.
+		dctx := context.WithoutCancel(ctx)
+		api, err := p.dialer.Dial(dctx, &dctl, mt, requiredPermissions)
+		p.dialed(dctx, cc, cp, &dctl, err)
+		if err != nil {
+			ch <- result{err: err}
+			return
+		}
+		now := p.now()
+		ch <- result{conn: &pooledConn{
+			api:         api,
+			ctl:         dctl,
+			lastUsed:    now,
+			lastChecked: now,
+		}}
+	}()
+
+	select {
+	case r := <-ch:
+		if r.err != nil {
+			return nil, r.err
+		}
+		r.conn.updateController(ctl)
+		return p.wrap(cc, cp, r.conn), nil
+	case <-ctx.Done():
+		go func() {
+			if r := <-ch; r.err == nil {
+				p.put(cc, cp, r.conn)
+			}
+		}()
+		return nil, ctx.Err()
+	}
+}
+
+// dialed records the result of dialing a controller. Only transport
+// errors count towards opening the controller's circuit breaker.
+func (p *ControllerPool) dialed(ctx context.Context, cc *controllerConns, cp *connPool, ctl *dbmodel.Controller, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err != nil {
+		servermon.ControllerPoolDialCount.WithLabelValues(cc.name, "failure").Inc()
+		cp.open--
+		cp.notify()
+		var terr *TransportError
+		if !stderrors.As(err, &terr) {
+			// The controller was reached, so it is not failing.
+			if cc.breaker.success() {
+				zapctx.Info(ctx, "controller circuit breaker closed", zap.String("controller", cc.name))
+			}
+		} else if cc.breaker.failure(p.now(), p.params.FailureThreshold) {
+			zapctx.Warn(ctx, "controller circuit breaker opened", zap.String("controller", cc.name), zap.Error(err))
+		}
+	} else {
+		servermon.ControllerPoolDialCount.WithLabelValues(cc.name, "success").Inc()
+		if cc.breaker.success() {
+			zapctx.Info(ctx, "controller circuit breaker closed", zap.String("controller", cc.name))
+		}
+		ctl := *ctl
+		cc.ctl = &ctl
+	}
+	p.updateMetricsLocked(cc)
+}
+
+// wrap wraps the given connection such that it is returned to the pool
+// when it is closed.
+func (p *ControllerPool) wrap(cc *controllerConns, cp *connPool, conn *pooledConn) API {
+	return &pooledAPI{
+		API:  conn.api,
+		pool: p,
+		cc:   cc,
+		cp:   cp,
+		conn: conn,
+	}
+}
+
+// put returns a connection to the pool.
+func (p *ControllerPool) put(cc *controllerConns, cp *connPool, conn *pooledConn) {
+	p.mu.Lock()
+	reason := ""
+	switch {
+	case p.closed:
+		reason = "closed"
+	case conn.api.IsBroken():
+		reason = "broken"
+	default:
+		conn.lastUsed = p.now()
+		cp.idle = append(cp.idle, conn)
+		cp.notify()
+		p.updateMetricsLocked(cc)
+		p.mu.Unlock()
+		return
+	}
+	p.mu.Unlock()
+	p.discard(cc, cp, conn, reason)
+}
+
+// discard closes a connection taken from the pool.
+func (p *ControllerPool) discard(cc *controllerConns, cp *connPool, conn *pooledConn, reason string) {
+	p.mu.Lock()
+	cp.open--
+	cp.notify()
+	servermon.ControllerPoolEvictionCount.WithLabelValues(cc.name, reason).Inc()
+	p.updateMetricsLocked(cc)
+	p.mu.Unlock()
+	conn.api.Close()
+}
+
+// updateMetricsLocked updates the exported metrics for the given
+// controller. p.mu must be held.
+func (p *ControllerPool) updateMetricsLocked(cc *controllerConns) {
+	var open, idle int
+	for _, cp := range cc.pools {
+		open += cp.open
+		idle += len(cp.idle)
+	}
+	servermon.ControllerPoolConnections.WithLabelValues(cc.name, "idle").Set(float64(idle))
+	servermon.ControllerPoolConnections.WithLabelValues(cc.name, "in_use").Set(float64(open - idle))
+	servermon.ControllerCircuitBreakerState.WithLabelValues(cc.name).Set(float64(cc.breaker.state))
+}
+
+// Run performs periodic maintenance of the pool until the given context
+// is canceled. Idle connections that are broken, fail a health check or
+// exceed the idle timeout are closed, and the controller pools of active
+// controllers are topped up to the minimum number of connections.
+func (p *ControllerPool) Run(ctx context.Context) error {
+	ticker := time.NewTicker(p.params.HealthCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			p.maintain(ctx)
+		}
+	}
+}
+
+// maintain performs a single maintenance run of the pool.
+func (p *ControllerPool) maintain(ctx context.Context) {
+	type idleConn struct {
+		cc   *controllerConns
+		cp   *connPool
+		conn *pooledConn
+	}
+	var evict, check []idleConn
+	var reasons []string
+	var topUp []*controllerConns
+
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return
+	}
+	now := p.now()
+	for name, cc := range p.controllers {
+		active := now.Sub(cc.lastRequested) < p.params.IdleTimeout
+		for uuid, cp := range cc.pools {
+			minConns := 0
+			if uuid == "" && active {
+				minConns = p.params.MinConnections
+			}
+			open := cp.open
+			var keep []*pooledConn
+			for _, conn := range cp.idle {
+				switch {
+				case conn.api.IsBroken():
+					evict = append(evict, idleConn{cc, cp, conn})
+					reasons = append(reasons, "broken")
+					open--
+				case (!active || now.Sub(conn.lastUsed) >= p.params.IdleTimeout) && open > minConns:
+					evict = append(evict, idleConn{cc, cp, conn})
+					reasons = append(reasons, "idle")
+					open--
+				case now.Sub(conn.lastChecked) >= p.params.HealthCheckInterval:
+					// Take the connection out of the pool
+					// whilst it is checked.
+					check = append(check, idleConn{cc, cp, conn})
+				default:
+					keep = append(keep, conn)
+				}
+			}
+			cp.idle = keep
+			if uuid != "" && cp.open == 0 {
+				delete(cc.pools, uuid)
+			}
+		}
+		if active && cc.ctl != nil && cc.breaker.state == circuitClosed && p.params.MinConnections > 0 {
+			topUp = append(topUp, cc)
+		}
+		p.updateMetricsLocked(cc)
+		if !active && cc.breaker.state == circuitClosed {
+			p.removeIdleControllerLocked(name, cc)
+		}
+	}
+	p.mu.Unlock()
+
+	for i, c := range evict {
+		p.discard(c.cc, c.cp, c.conn, reasons[i])
+	}
+	for _, c := range check {
+		if err := c.conn.api.Ping(ctx); err != nil {
+			zapctx.Warn(ctx, "pooled connection failed", zap.String("controller", c.cc.name), zap.Error(err))
+			p.discard(c.cc, c.cp, c.conn, "unhealthy")
+			continue
+		}
+		c.conn.lastChecked = p.now()
+		p.put(c.cc, c.cp, c.conn)
+	}
+	for _, cc := range topUp {
+		p.topUp(ctx, cc)
+	}
+}
+
+// removeIdleControllerLocked removes the entry for a controller that is
+// no longer being used, and has no open connections. p.mu must be held.
+func (p *ControllerPool) removeIdleControllerLocked(name string, cc *controllerConns) {
+	for uuid, cp := range cc.pools {
+		if cp.open > 0 {
+			return
+		}
+		delete(cc.pools, uuid)
+	}
+	delete(p.controllers, name)
+	servermon.ControllerPoolConnections.DeleteLabelValues(name, "idle")
+	servermon.ControllerPoolConnections.DeleteLabelValues(name, "in_use")
+	servermon.ControllerCircuitBreakerState.DeleteLabelValues(name)
+}
+
+// topUp dials controller connections until the controller has the
+// minimum number of connections open.
+func (p *ControllerPool) topUp(ctx context.Context, cc *controllerConns) {
+	for {
+		p.mu.Lock()
+		cp := cc.pool("")
+		if p.closed || cp.open >= p.params.MinConnections || !cc.breaker.allow(p.now(), p.params.OpenTimeout) {
+			p.mu.Unlock()
+			return
+		}
+		cp.open++
+		ctl := *cc.ctl
+		p.mu.Unlock()
+
+		api, err := p.dialer.Dial(ctx, &ctl, names.ModelTag{}, nil)
+		p.dialed(ctx, cc, cp, &ctl, err)
+		if err != nil {
+			zapctx.Warn(ctx, "cannot top up controller connections", zap.String("controller", cc.name), zap.Error(err))
+			return
+		}
+		now := p.now()
+		p.put(cc, cp, &pooledConn{
+			api:         api,
+			ctl:         ctl,
+			lastUsed:    now,
+			lastChecked: now,
+		})
+	}
+}
+
+// Close closes all idle connections and stops the pool from creating any
+// more. Connections that are in use are closed when they are returned.
+func (p *ControllerPool) Close() error {
+	p.mu.Lock()
+	p.closed = true
+	var conns []*pooledConn
+	for _, cc := range p.controllers {
+		for _, cp := range cc.pools {
+			conns = append(conns, cp.idle...)
+			cp.open -= len(cp.idle)
+			cp.idle = nil
+			cp.notify()
+		}
+		p.updateMetricsLocked(cc)
+	}
+	p.mu.Unlock()
+
+	var firstErr error
+	for _, conn := range conns {
+		if err := conn.api.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// A pooledAPI is a connection that has been taken from a ControllerPool.
+type pooledAPI struct {
+	API
+
+	pool   *ControllerPool
+	cc     *controllerConns
+	cp     *connPool
+	conn   *pooledConn
+	closed atomic.Bool
+}
+
+// Close implements API.Close by returning the connection to the pool.
+func (a *pooledAPI) Close() error {
+	// Protect from returning the same connection multiple times.
+	if a.closed.Swap(true) {
+		return nil
+	}
+	a.pool.put(a.cc, a.cp, a.conn)
+	return nil
+}
+
+// circuitState is the state of a circuitBreaker. The values are those
+// exported in the jimm_controller_pool_circuit_state metric.
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitHalfOpen
+	circuitOpen
+)
+
+// A circuitBreaker tracks failed dials to a controller. Once the number
+// of consecutive failures reaches the threshold the circuit opens, and no
+// dials are allowed until the open timeout has passed. After that a single
+// trial dial is allowed, if it succeeds the circuit closes otherwise it
+// opens again.
+type circuitBreaker struct {
+	state    circuitState
+	failures int
+	openedAt time.Time
+	trial    bool
+}
+
+// allow reports whether a dial is allowed at the given time.
+func (b *circuitBreaker) allow(now time.Time, openTimeout time.Duration) bool {
+	switch b.state {
+	case circuitOpen:
+		if now.Sub(b.openedAt) < openTimeout {
+			return false
+		}
+		b.state = circuitHalfOpen
+		b.trial = true
+		return true
+	case circuitHalfOpen:
+		if b.trial {
+			return false
+		}
+		b.trial = true
+		return true
+	default:
+		return true
+	}
+}
+
+// success records a successful dial. It returns true if the circuit was
+// previously open.
+func (b *circuitBreaker) success() bool {
+	wasOpen := b.state != circuitClosed
+	b.state = circuitClosed
+	b.failures = 0
+	b.trial = false
+	return wasOpen
+}
+
+// failure records a failed dial at the given time. It returns true if the
+// circuit opened as a result.
+func (b *circuitBreaker) failure(now time.Time, threshold int) bool {
+	b.failures++
+	b.trial = false
+	if b.state == circuitOpen || (b.state == circuitClosed && b.failures < threshold) {
+		return false
+	}
+	b.state = circuitOpen
+	b.openedAt = now
+	return true
+}
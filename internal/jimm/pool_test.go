@@ -0,0 +1,452 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/testutils/jimmtest"
+)
+
+// poolTestDialer is a Dialer that returns a new API for each dial, and
+// counts the dials and closed connections.
+type poolTestDialer struct {
+	// block, if not nil, is received from before each dial completes.
+	block chan struct{}
+
+	mu     sync.Mutex
+	err    error
+	apis   []*jimmtest.API
+	dials  int
+	closed int
+}
+
+func (d *poolTestDialer) Dial(_ context.Context, ctl *dbmodel.Controller, _ names.ModelTag, _ map[string]string) (jimm.API, error) {
+	if d.block != nil {
+		<-d.block
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.dials++
+	if d.err != nil {
+		return nil, d.err
+	}
+	ctl.UUID = jimmtest.ControllerUUID
+	api := &jimmtest.API{
+		Close_: func() error {
+			d.mu.Lock()
+			defer d.mu.Unlock()
+			d.closed++
+			return nil
+		},
+	}
+	d.apis = append(d.apis, api)
+	return api, nil
+}
+
+func (d *poolTestDialer) setErr(err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.err = err
+}
+
+func (d *poolTestDialer) api(i int) *jimmtest.API {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.apis[i]
+}
+
+func (d *poolTestDialer) counts() (dials, closed int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.dials, d.closed
+}
+
+// testClock is a clock that only changes when advanced.
+type testClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *testClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func newTestPool(d jimm.Dialer, p jimm.PoolParams) (*jimm.ControllerPool, *testClock) {
+	clock := &testClock{now: time.Now()}
+	pool := jimm.NewControllerPool(d, p)
+	pool.SetClock(clock.Now)
+	return pool, clock
+}
+
+func TestControllerPoolDialError(t *testing.T) {
+	c := qt.New(t)
+
+	testError := errors.E("test error")
+	dialer := &poolTestDialer{err: testError}
+	pool, _ := newTestPool(dialer, jimm.PoolParams{})
+	ctl := dbmodel.Controller{
+		Name: "test-controller",
+	}
+	_, err := pool.Dial(context.Background(), &ctl, names.ModelTag{}, nil)
+	c.Check(err, qt.Equals, testError)
+
+	dialer.setErr(nil)
+	api, err := pool.Dial(context.Background(), &ctl, names.ModelTag{}, nil)
+	c.Assert(err, qt.IsNil)
+	defer api.Close()
+	c.Check(ctl.UUID, qt.Equals, jimmtest.ControllerUUID)
+}
+
+func TestControllerPoolReusesConnections(t *testing.T) {
+	c := qt.New(t)
+
+	dialer := &poolTestDialer{}
+	pool, _ := newTestPool(dialer, jimm.PoolParams{})
+	ctl := dbmodel.Controller{
+		Name: "test-controller",
+	}
+	api, err := pool.Dial(context.Background(), &ctl, names.ModelTag{}, nil)
+	c.Assert(err, qt.IsNil)
+	err = api.Close()
+	c.Assert(err, qt.IsNil)
+	// Closing the connection twice doesn't return it to the pool twice.
+	err = api.Close()
+	c.Assert(err, qt.IsNil)
+
+	ctl2 := dbmodel.Controller{
+		Name: "test-controller",
+	}
+	api1, err := pool.Dial(context.Background(), &ctl2, names.ModelTag{}, nil)
+	c.Assert(err, qt.IsNil)
+	defer api1.Close()
+	// The controller is updated as if the connection had been dialed.
+	c.Check(ctl2.UUID, qt.Equals, jimmtest.ControllerUUID)
+	api2, err := pool.Dial(context.Background(), &ctl2, names.ModelTag{}, nil)
+	c.Assert(err, qt.IsNil)
+	defer api2.Close()
+
+	dials, closed := dialer.counts()
+	c.Check(dials, qt.Equals, 2)
+	c.Check(closed, qt.Equals, 0)
+}
+
+func TestControllerPoolModelConnections(t *testing.T) {
+	c := qt.New(t)
+
+	dialer := &poolTestDialer{}
+	pool, _ := newTestPool(dialer, jimm.PoolParams{})
+	ctl := dbmodel.Controller{
+		Name: "test-controller",
+	}
+	mt1 := names.NewModelTag("00000002-0000-0000-0000-000000000001")
+	mt2 := names.NewModelTag("00000002-0000-0000-0000-000000000002")
+
+	for _, mt := range []names.ModelTag{mt1, mt1, mt2, {}, mt2} {
+		api, err := pool.Dial(context.Background(), &ctl, mt, nil)
+		c.Assert(err, qt.IsNil)
+		err = api.Close()
+		c.Assert(err, qt.IsNil)
+	}
+	dials, _ := dialer.counts()
+	c.Check(dials, qt.Equals, 3)
+}
+
+func TestControllerPoolMaxConnections(t *testing.T) {
+	c := qt.New(t)
+
+	dialer := &poolTestDialer{}
+	pool, _ := newTestPool(dialer, jimm.PoolParams{MaxConnections: 1})
+	ctl := dbmodel.Controller{
+		Name: "test-controller",
+	}
+	api, err := pool.Dial(context.Background(), &ctl, names.ModelTag{}, nil)
+	c.Assert(err, qt.IsNil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = pool.Dial(ctx, &ctl, names.ModelTag{}, nil)
+	c.Check(err, qt.Equals, context.DeadlineExceeded)
+
+	errC := make(chan error)
+	go func() {
+		api, err := pool.Dial(context.Background(), &ctl, names.ModelTag{}, nil)
+		if err == nil {
+			err = api.Close()
+		}
+		errC <- err
+	}()
+	err = api.Close()
+	c.Assert(err, qt.IsNil)
+	c.Check(<-errC, qt.IsNil)
+
+	dials, _ := dialer.counts()
+	c.Check(dials, qt.Equals, 1)
+}
+
+func TestControllerPoolBrokenConnection(t *testing.T) {
+	c := qt.New(t)
+
+	dialer := &poolTestDialer{}
+	pool, _ := newTestPool(dialer, jimm.PoolParams{})
+	ctl := dbmodel.Controller{
+		Name: "test-controller",
+	}
+	api, err := pool.Dial(context.Background(), &ctl, names.ModelTag{}, nil)
+	c.Assert(err, qt.IsNil)
+	dialer.api(0).IsBroken_ = true
+	err = api.Close()
+	c.Assert(err, qt.IsNil)
+
+	api, err = pool.Dial(context.Background(), &ctl, names.ModelTag{}, nil)
+	c.Assert(err, qt.IsNil)
+	defer api.Close()
+
+	dials, closed := dialer.counts()
+	c.Check(dials, qt.Equals, 2)
+	c.Check(closed, qt.Equals, 1)
+}
+
+func TestControllerPoolHealthCheck(t *testing.T) {
+	c := qt.New(t)
+
+	dialer := &poolTestDialer{}
+	pool, clock := newTestPool(dialer, jimm.PoolParams{HealthCheckInterval: time.Minute})
+	ctl := dbmodel.Controller{
+		Name: "test-controller",
+	}
+	api, err := pool.Dial(context.Background(), &ctl, names.ModelTag{}, nil)
+	c.Assert(err, qt.IsNil)
+	err = api.Close()
+	c.Assert(err, qt.IsNil)
+	dialer.api(0).Ping_ = func(context.Context) error {
+		return errors.E("ping error")
+	}
+
+	// A recently checked connection is reused without a ping.
+	api, err = pool.Dial(context.Background(), &ctl, names.ModelTag{}, nil)
+	c.Assert(err, qt.IsNil)
+	err = api.Close()
+	c.Assert(err, qt.IsNil)
+	dials, closed := dialer.counts()
+	c.Check(dials, qt.Equals, 1)
+	c.Check(closed, qt.Equals, 0)
+
+	clock.Advance(2 * time.Minute)
+	api, err = pool.Dial(context.Background(), &ctl, names.ModelTag{}, nil)
+	c.Assert(err, qt.IsNil)
+	defer api.Close()
+	dials, closed = dialer.counts()
+	c.Check(dials, qt.Equals, 2)
+	c.Check(closed, qt.Equals, 1)
+}
+
+func TestControllerPoolCircuitBreaker(t *testing.T) {
+	c := qt.New(t)
+
+	testError := errors.E(errors.CodeConnectionFailed, &jimm.TransportError{Err: errors.E("connection refused")})
+	dialer := &poolTestDialer{err: testError}
+	pool, clock := newTestPool(dialer, jimm.PoolParams{
+		FailureThreshold: 2,
+		OpenTimeout:      time.Minute,
+	})
+	ctl := dbmodel.Controller{
+		Name: "test-controller",
+	}
+	dial := func() error {
+		api, err := pool.Dial(context.Background(), &ctl, names.ModelTag{}, nil)
+		if err == nil {
+			api.Close()
+		}
+		return err
+	}
+
+	c.Check(dial(), qt.Equals, testError)
+	c.Check(dial(), qt.Equals, testError)
+	// The circuit is open, so the controller isn't dialed.
+	err := dial()
+	c.Check(err, qt.ErrorMatches, `controller "test-controller" is unavailable after repeated connection failures`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeConnectionFailed)
+	dials, _ := dialer.counts()
+	c.Check(dials, qt.Equals, 2)
+
+	// After the open timeout a single trial dial is allowed, which
+	// opens the circuit again when it fails.
+	clock.Advance(time.Minute)
+	c.Check(dial(), qt.Equals, testError)
+	c.Check(dial(), qt.ErrorMatches, `controller "test-controller" is unavailable .*`)
+	dials, _ = dialer.counts()
+	c.Check(dials, qt.Equals, 3)
+
+	// A successful trial dial closes the circuit.
+	clock.Advance(time.Minute)
+	dialer.setErr(nil)
+	c.Check(dial(), qt.IsNil)
+	dialer.setErr(testError)
+	api, err := pool.Dial(context.Background(), &ctl, names.ModelTag{}, nil)
+	c.Assert(err, qt.IsNil)
+	defer api.Close()
+	c.Check(dial(), qt.Equals, testError)
+}
+
+func TestControllerPoolCircuitBreakerIgnoresLoginErrors(t *testing.T) {
+	c := qt.New(t)
+
+	loginError := errors.E(errors.CodeConnectionFailed, "login failed")
+	dialer := &poolTestDialer{err: loginError}
+	pool, _ := newTestPool(dialer, jimm.PoolParams{
+		FailureThreshold: 2,
+		OpenTimeout:      time.Minute,
+	})
+	ctl := dbmodel.Controller{
+		Name: "test-controller",
+	}
+
+	// Errors from a controller that was reached do not open the
+	// circuit.
+	for i := 0; i < 3; i++ {
+		_, err := pool.Dial(context.Background(), &ctl, names.ModelTag{}, nil)
+		c.Check(err, qt.Equals, loginError)
+	}
+	dials, _ := dialer.counts()
+	c.Check(dials, qt.Equals, 3)
+
+	// They also reset the count of transport failures.
+	transportError := errors.E(errors.CodeConnectionFailed, &jimm.TransportError{Err: errors.E("connection refused")})
+	dialer.setErr(transportError)
+	_, err := pool.Dial(context.Background(), &ctl, names.ModelTag{}, nil)
+	c.Check(err, qt.Equals, transportError)
+	dialer.setErr(loginError)
+	_, err = pool.Dial(context.Background(), &ctl, names.ModelTag{}, nil)
+	c.Check(err, qt.Equals, loginError)
+	dialer.setErr(transportError)
+	_, err = pool.Dial(context.Background(), &ctl, names.ModelTag{}, nil)
+	c.Check(err, qt.Equals, transportError)
+	dials, _ = dialer.counts()
+	c.Check(dials, qt.Equals, 6)
+	_, err = pool.Dial(context.Background(), &ctl, names.ModelTag{}, nil)
+	c.Check(err, qt.Equals, transportError)
+	dials, _ = dialer.counts()
+	c.Check(dials, qt.Equals, 7)
+
+	// Consecutive transport failures open the circuit.
+	_, err = pool.Dial(context.Background(), &ctl, names.ModelTag{}, nil)
+	c.Check(err, qt.ErrorMatches, `controller "test-controller" is unavailable .*`)
+}
+
+func TestControllerPoolMaintain(t *testing.T) {
+	c := qt.New(t)
+
+	dialer := &poolTestDialer{}
+	pool, clock := newTestPool(dialer, jimm.PoolParams{
+		MinConnections:      1,
+		IdleTimeout:         10 * time.Minute,
+		HealthCheckInterval: time.Minute,
+	})
+	ctl := dbmodel.Controller{
+		Name: "test-controller",
+	}
+	api1, err := pool.Dial(context.Background(), &ctl, names.ModelTag{}, nil)
+	c.Assert(err, qt.IsNil)
+	api2, err := pool.Dial(context.Background(), &ctl, names.ModelTag{}, nil)
+	c.Assert(err, qt.IsNil)
+	c.Assert(api1.Close(), qt.IsNil)
+	c.Assert(api2.Close(), qt.IsNil)
+
+	// Use one of the connections half way through the idle timeout.
+	clock.Advance(5 * time.Minute)
+	api, err := pool.Dial(context.Background(), &ctl, names.ModelTag{}, nil)
+	c.Assert(err, qt.IsNil)
+	c.Assert(api.Close(), qt.IsNil)
+
+	// The unused connection is closed.
+	clock.Advance(5 * time.Minute)
+	pool.Maintain(context.Background())
+	dials, closed := dialer.counts()
+	c.Check(dials, qt.Equals, 2)
+	c.Check(closed, qt.Equals, 1)
+
+	// The minimum number of connections is kept whilst the
+	// controller is active, even if they fail.
+	dialer.api(0).IsBroken_ = true
+	dialer.api(1).IsBroken_ = true
+	pool.Maintain(context.Background())
+	dials, closed = dialer.counts()
+	c.Check(dials, qt.Equals, 3)
+	c.Check(closed, qt.Equals, 2)
+
+	// All connections are closed once the controller is inactive.
+	clock.Advance(10 * time.Minute)
+	pool.Maintain(context.Background())
+	dials, closed = dialer.counts()
+	c.Check(dials, qt.Equals, 3)
+	c.Check(closed, qt.Equals, 3)
+}
+
+func TestControllerPoolContextCanceled(t *testing.T) {
+	c := qt.New(t)
+
+	dialer := &poolTestDialer{block: make(chan struct{})}
+	pool, _ := newTestPool(dialer, jimm.PoolParams{MaxConnections: 1})
+	ctl := dbmodel.Controller{
+		Name: "test-controller",
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	api, err := pool.Dial(ctx, &ctl, names.ModelTag{}, nil)
+	c.Check(err, qt.Equals, context.Canceled)
+	c.Check(api, qt.IsNil)
+
+	// The dial completes in the background and its connection is
+	// added to the pool.
+	close(dialer.block)
+	api, err = pool.Dial(context.Background(), &ctl, names.ModelTag{}, nil)
+	c.Assert(err, qt.IsNil)
+	defer api.Close()
+	dials, _ := dialer.counts()
+	c.Check(dials, qt.Equals, 1)
+}
+
+func TestControllerPoolClose(t *testing.T) {
+	c := qt.New(t)
+
+	dialer := &poolTestDialer{}
+	pool, _ := newTestPool(dialer, jimm.PoolParams{})
+	ctl := dbmodel.Controller{
+		Name: "test-controller",
+	}
+	api1, err := pool.Dial(context.Background(), &ctl, names.ModelTag{}, nil)
+	c.Assert(err, qt.IsNil)
+	api2, err := pool.Dial(context.Background(), &ctl, names.ModelTag{}, nil)
+	c.Assert(err, qt.IsNil)
+	c.Assert(api1.Close(), qt.IsNil)
+
+	err = pool.Close()
+	c.Assert(err, qt.IsNil)
+	_, closed := dialer.counts()
+	c.Check(closed, qt.Equals, 1)
+
+	c.Assert(api2.Close(), qt.IsNil)
+	_, closed = dialer.counts()
+	c.Check(closed, qt.Equals, 2)
+
+	_, err = pool.Dial(context.Background(), &ctl, names.ModelTag{}, nil)
+	c.Check(err, qt.ErrorMatches, `connection pool closed`)
+}
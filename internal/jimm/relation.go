@@ -14,19 +14,24 @@ import (
 	"github.com/canonical/jimm/v3/internal/openfga"
 	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
 	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+	jimmnames "github.com/canonical/jimm/v3/pkg/names"
 )
 
 // AddRelation checks user permission and add given relations tuples.
-// At the moment user is required be admin.
+// The user is required to be an admin or, if every tuple describes the
+// membership or management of a group, a manager of those groups.
 func (j *JIMM) AddRelation(ctx context.Context, user *openfga.User, tuples []apiparams.RelationshipTuple) error {
 	const op = errors.Op("jimm.AddRelation")
-	if !user.JimmAdmin {
-		return errors.E(op, errors.CodeUnauthorized, "unauthorized")
-	}
 	parsedTuples, err := j.parseTuples(ctx, tuples)
 	if err != nil {
+		if !user.JimmAdmin {
+			return errors.E(op, errors.CodeUnauthorized, "unauthorized")
+		}
 		return errors.E(err)
 	}
+	if err := checkRelationChangeAllowed(ctx, user, parsedTuples); err != nil {
+		return errors.E(op, err)
+	}
 	err = j.OpenFGAClient.AddRelation(ctx, parsedTuples...)
 	if err != nil {
 		return errors.E(op, errors.CodeOpenFGARequestFailed, err)
@@ -35,14 +40,18 @@ func (j *JIMM) AddRelation(ctx context.Context, user *openfga.User, tuples []api
 }
 
 // RemoveRelation checks user permission and remove given relations tuples.
-// At the moment user is required be admin.
+// The user is required to be an admin or, if every tuple describes the
+// membership or management of a group, a manager of those groups.
 func (j *JIMM) RemoveRelation(ctx context.Context, user *openfga.User, tuples []apiparams.RelationshipTuple) error {
 	const op = errors.Op("jimm.RemoveRelation")
-	if !user.JimmAdmin {
-		return errors.E(op, errors.CodeUnauthorized, "unauthorized")
-	}
 	parsedTuples, err := j.parseTuples(ctx, tuples)
 	if err != nil {
+		if !user.JimmAdmin {
+			return errors.E(op, errors.CodeUnauthorized, "unauthorized")
+		}
+		return errors.E(op, err)
+	}
+	if err := checkRelationChangeAllowed(ctx, user, parsedTuples); err != nil {
 		return errors.E(op, err)
 	}
 	err = j.OpenFGAClient.RemoveRelation(ctx, parsedTuples...)
@@ -52,6 +61,39 @@ func (j *JIMM) RemoveRelation(ctx context.Context, user *openfga.User, tuples []
 	return nil
 }
 
+// checkRelationChangeAllowed returns an error with a code of
+// errors.CodeUnauthorized unless the user is a JIMM admin or every tuple
+// adds a member or manager to a group that the user manages.
+func checkRelationChangeAllowed(ctx context.Context, user *openfga.User, tuples []openfga.Tuple) error {
+	if user.JimmAdmin {
+		return nil
+	}
+	if len(tuples) == 0 {
+		return errors.E(errors.CodeUnauthorized, "unauthorized")
+	}
+	managed := make(map[string]bool)
+	for _, t := range tuples {
+		if t.Target == nil || t.Target.Kind != openfga.GroupType {
+			return errors.E(errors.CodeUnauthorized, "unauthorized")
+		}
+		if t.Relation != ofganames.MemberRelation && t.Relation != ofganames.ManagerRelation {
+			return errors.E(errors.CodeUnauthorized, "unauthorized")
+		}
+		if managed[t.Target.ID] {
+			continue
+		}
+		isManager, err := user.IsGroupManager(ctx, jimmnames.NewGroupTag(t.Target.ID))
+		if err != nil {
+			return errors.E(errors.CodeOpenFGARequestFailed, err)
+		}
+		if !isManager {
+			return errors.E(errors.CodeUnauthorized, "unauthorized")
+		}
+		managed[t.Target.ID] = true
+	}
+	return nil
+}
+
 // CheckRelation checks user permission and return true if the given tuple exists.
 // At the moment user is required be admin or checking its own relations
 func (j *JIMM) CheckRelation(ctx context.Context, user *openfga.User, tuple apiparams.RelationshipTuple, trace bool) (_ bool, err error) {
@@ -271,3 +271,59 @@ func TestListObjectRelations(t *testing.T) {
 		})
 	}
 }
+
+func TestAddRelationGroupManager(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	j := jimmtest.NewJIMM(c, nil)
+
+	admin := openfga.NewUser(&dbmodel.Identity{Name: "admin@canonical.com"}, j.OpenFGAClient)
+	admin.JimmAdmin = true
+	manager := openfga.NewUser(&dbmodel.Identity{Name: "manager@canonical.com"}, j.OpenFGAClient)
+
+	user, group, _, model, _, _, _, _ := jimmtest.CreateTestControllerEnvironment(ctx, c, j.Database)
+
+	memberTuple := apiparams.RelationshipTuple{
+		Object:       user.Tag().String(),
+		Relation:     names.MemberRelation.String(),
+		TargetObject: group.ResourceTag().String(),
+	}
+
+	// A user that doesn't manage the group cannot change its membership.
+	err := j.AddRelation(ctx, manager, []apiparams.RelationshipTuple{memberTuple})
+	c.Assert(err, qt.ErrorMatches, "unauthorized")
+
+	err = j.AddRelation(ctx, admin, []apiparams.RelationshipTuple{{
+		Object:       manager.Tag().String(),
+		Relation:     names.ManagerRelation.String(),
+		TargetObject: group.ResourceTag().String(),
+	}})
+	c.Assert(err, qt.IsNil)
+
+	err = j.AddRelation(ctx, manager, []apiparams.RelationshipTuple{memberTuple, {
+		Object:       "user-bob@canonical.com",
+		Relation:     names.ManagerRelation.String(),
+		TargetObject: group.ResourceTag().String(),
+	}})
+	c.Assert(err, qt.IsNil)
+
+	isMember, err := j.CheckRelation(ctx, admin, memberTuple, false)
+	c.Assert(err, qt.IsNil)
+	c.Assert(isMember, qt.IsTrue)
+
+	// Managing a group does not grant access to anything else.
+	err = j.AddRelation(ctx, manager, []apiparams.RelationshipTuple{memberTuple, {
+		Object:       user.Tag().String(),
+		Relation:     names.WriterRelation.String(),
+		TargetObject: model.ResourceTag().String(),
+	}})
+	c.Assert(err, qt.ErrorMatches, "unauthorized")
+
+	err = j.RemoveRelation(ctx, manager, []apiparams.RelationshipTuple{memberTuple})
+	c.Assert(err, qt.IsNil)
+
+	isMember, err = j.CheckRelation(ctx, admin, memberTuple, false)
+	c.Assert(err, qt.IsNil)
+	c.Assert(isMember, qt.IsFalse)
+}
@@ -0,0 +1,100 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const (
+	// defaultJobRunsLimit is the number of job runs returned by
+	// ListJobRuns if no limit is given.
+	defaultJobRunsLimit = 20
+
+	// maxJobRunsLimit is the largest number of job runs returned by
+	// ListJobRuns.
+	maxJobRunsLimit = 1000
+)
+
+// ListScheduledJobs returns the scheduled background jobs along with
+// their most recent runs. Only JIMM administrators may list scheduled
+// jobs.
+func (j *JIMM) ListScheduledJobs(ctx context.Context, user *openfga.User) ([]apiparams.ScheduledJob, error) {
+	const op = errors.Op("jimm.ListScheduledJobs")
+
+	if err := j.checkJimmAdmin(user); err != nil {
+		return nil, errors.E(op, err)
+	}
+	jobs, err := j.Database.ListScheduledJobs(ctx)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	result := make([]apiparams.ScheduledJob, len(jobs))
+	for i, job := range jobs {
+		runs, err := j.Database.ListJobRuns(ctx, job.Name, 1)
+		if err != nil {
+			return nil, errors.E(op, err)
+		}
+		var lastRun *dbmodel.JobRun
+		if len(runs) > 0 {
+			lastRun = &runs[0]
+		}
+		result[i] = job.ToAPIScheduledJob(lastRun)
+	}
+	return result, nil
+}
+
+// ListJobRuns returns at most limit runs of the named scheduled job, most
+// recent first. Only JIMM administrators may list job runs.
+func (j *JIMM) ListJobRuns(ctx context.Context, user *openfga.User, job string, limit int) ([]apiparams.JobRun, error) {
+	const op = errors.Op("jimm.ListJobRuns")
+
+	if err := j.checkJimmAdmin(user); err != nil {
+		return nil, errors.E(op, err)
+	}
+	if err := j.Database.GetScheduledJob(ctx, &dbmodel.ScheduledJob{Name: job}); err != nil {
+		return nil, errors.E(op, err)
+	}
+	if limit <= 0 {
+		limit = defaultJobRunsLimit
+	}
+	limit = min(limit, maxJobRunsLimit)
+	runs, err := j.Database.ListJobRuns(ctx, job, limit)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	result := make([]apiparams.JobRun, len(runs))
+	for i, r := range runs {
+		result[i] = r.ToAPIJobRun()
+	}
+	return result, nil
+}
+
+// TriggerScheduledJob requests that the named scheduled job is run
+// immediately. The job is run by the leader's scheduler the next time it
+// checks for triggered jobs, or once the current run has finished if the
+// job is already running. Only JIMM administrators may trigger scheduled
+// jobs.
+func (j *JIMM) TriggerScheduledJob(ctx context.Context, user *openfga.User, job string) error {
+	const op = errors.Op("jimm.TriggerScheduledJob")
+
+	if err := j.checkJimmAdmin(user); err != nil {
+		return errors.E(op, err)
+	}
+	sj := dbmodel.ScheduledJob{
+		Name:        job,
+		TriggeredAt: sql.NullTime{Time: time.Now().UTC(), Valid: true},
+		TriggeredBy: user.Name,
+	}
+	if err := j.Database.TriggerScheduledJob(ctx, &sj); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
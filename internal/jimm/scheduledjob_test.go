@@ -0,0 +1,104 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	"github.com/canonical/jimm/v3/internal/testutils/jimmtest"
+)
+
+const scheduledJobsTestEnv = `users:
+- username: alice@canonical.com
+  controller-access: superuser
+- username: bob@canonical.com
+  controller-access: login
+`
+
+func TestScheduledJobs(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	j := jimmtest.NewJIMM(c, nil)
+
+	env := jimmtest.ParseEnvironment(c, scheduledJobsTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, j.OpenFGAClient)
+	aliceIdentity := env.User("alice@canonical.com").DBObject(c, j.Database)
+	alice := openfga.NewUser(&aliceIdentity, j.OpenFGAClient)
+	alice.JimmAdmin = true
+	bobIdentity := env.User("bob@canonical.com").DBObject(c, j.Database)
+	bob := openfga.NewUser(&bobIdentity, j.OpenFGAClient)
+
+	next := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	err := j.Database.RegisterScheduledJob(ctx, &dbmodel.ScheduledJob{
+		Name:      "cleanup",
+		Schedule:  "@daily",
+		Jitter:    time.Minute,
+		NextRunAt: sql.NullTime{Time: next, Valid: true},
+	})
+	c.Assert(err, qt.IsNil)
+	err = j.Database.RegisterScheduledJob(ctx, &dbmodel.ScheduledJob{
+		Name:     "rotate",
+		Schedule: "@hourly",
+	})
+	c.Assert(err, qt.IsNil)
+	started := time.Date(2029, 12, 31, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		err := j.Database.AddJobRun(ctx, &dbmodel.JobRun{
+			JobName:    "cleanup",
+			Trigger:    dbmodel.JobTriggerSchedule,
+			Replica:    "replica-1",
+			Status:     dbmodel.JobRunSucceeded,
+			StartedAt:  started.Add(time.Duration(i) * time.Hour),
+			FinishedAt: sql.NullTime{Time: started.Add(time.Duration(i)*time.Hour + time.Second), Valid: true},
+		})
+		c.Assert(err, qt.IsNil)
+	}
+
+	_, err = j.ListScheduledJobs(ctx, bob)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+	_, err = j.ListJobRuns(ctx, bob, "cleanup", 0)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+	err = j.TriggerScheduledJob(ctx, bob, "cleanup")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+
+	jobs, err := j.ListScheduledJobs(ctx, alice)
+	c.Assert(err, qt.IsNil)
+	c.Assert(jobs, qt.HasLen, 2)
+	c.Check(jobs[0].Name, qt.Equals, "cleanup")
+	c.Check(jobs[0].Schedule, qt.Equals, "@daily")
+	c.Check(jobs[0].Jitter, qt.Equals, "1m0s")
+	c.Check(jobs[0].NextRunAt.Equal(next), qt.IsTrue)
+	c.Assert(jobs[0].LastRun, qt.Not(qt.IsNil))
+	c.Check(jobs[0].LastRun.StartedAt.Equal(started.Add(2*time.Hour)), qt.IsTrue)
+	c.Check(jobs[0].LastRun.Duration, qt.Equals, "1s")
+	c.Check(jobs[1].Name, qt.Equals, "rotate")
+	c.Check(jobs[1].LastRun, qt.IsNil)
+
+	runs, err := j.ListJobRuns(ctx, alice, "cleanup", 2)
+	c.Assert(err, qt.IsNil)
+	c.Assert(runs, qt.HasLen, 2)
+	c.Check(runs[0].StartedAt.Equal(started.Add(2*time.Hour)), qt.IsTrue)
+	c.Check(runs[0].Status, qt.Equals, dbmodel.JobRunSucceeded)
+	c.Check(runs[1].StartedAt.Equal(started.Add(time.Hour)), qt.IsTrue)
+
+	_, err = j.ListJobRuns(ctx, alice, "no-such-job", 0)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	err = j.TriggerScheduledJob(ctx, alice, "cleanup")
+	c.Assert(err, qt.IsNil)
+	jobs, err = j.ListScheduledJobs(ctx, alice)
+	c.Assert(err, qt.IsNil)
+	c.Check(jobs[0].TriggeredAt, qt.Not(qt.IsNil))
+
+	err = j.TriggerScheduledJob(ctx, alice, "no-such-job")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+}
@@ -0,0 +1,335 @@
+// Copyright 2024 Canonical.
+
+// Package scheduler runs JIMM's periodic background jobs on cron-like
+// schedules, recording the outcome of every run in the database.
+package scheduler
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/juju/zaputil/zapctx"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+const (
+	// DefaultPollInterval is the default maximum time between checks
+	// for jobs that have been triggered manually.
+	DefaultPollInterval = 10 * time.Second
+
+	// DefaultKeepRuns is the default number of runs of each job kept in
+	// the history.
+	DefaultKeepRuns = 100
+)
+
+// A Job is a function that is run on a schedule.
+type Job struct {
+	// Name is the unique name of the job.
+	Name string
+
+	// Schedule describes when the job is run. It is either a standard
+	// five field cron expression, such as "0 9 * * *", or one of the
+	// descriptors "@hourly", "@daily", "@weekly", "@monthly" or
+	// "@every <duration>".
+	Schedule string
+
+	// Jitter is the maximum random delay added to each scheduled run,
+	// which stops jobs sharing a schedule all starting at once.
+	Jitter time.Duration
+
+	// Run performs the job. It should return promptly once the given
+	// context is canceled.
+	Run func(ctx context.Context) error
+}
+
+// A Scheduler runs registered jobs according to their schedules. The
+// schedule of each job, its next run time and the history of its runs
+// are stored in the database so that they survive a change of the
+// replica running the scheduler. A job can also be triggered to run
+// immediately, from any replica, using the database. A job is never run
+// concurrently with itself; if a job is still running when it is due the
+// scheduled run is skipped.
+type Scheduler struct {
+	// Database is the database holding the job schedules and history.
+	Database *db.Database
+
+	// Replica identifies the JIMM replica running the scheduler in the
+	// job history.
+	Replica string
+
+	// PollInterval is the maximum time between checks for jobs that
+	// have been triggered manually. If this is zero DefaultPollInterval
+	// is used.
+	PollInterval time.Duration
+
+	// KeepRuns is the number of runs of each job kept in the history.
+	// If this is zero DefaultKeepRuns is used.
+	KeepRuns int
+
+	jobs map[string]*job
+
+	mu      sync.Mutex
+	running map[string]bool
+	wg      sync.WaitGroup
+}
+
+// A job is a registered job along with its parsed schedule and next run
+// time.
+type job struct {
+	Job
+	schedule cron.Schedule
+	next     time.Time
+}
+
+// Register adds the given job to the scheduler. Jobs must be registered
+// before the scheduler is run. An error with a code of CodeBadRequest is
+// returned if the job's schedule is invalid, or a job with the same name
+// has already been registered.
+func (s *Scheduler) Register(j Job) error {
+	const op = errors.Op("scheduler.Register")
+
+	if j.Name == "" || j.Run == nil {
+		return errors.E(op, errors.CodeBadRequest, "job name and function must be specified")
+	}
+	if _, ok := s.jobs[j.Name]; ok {
+		return errors.E(op, errors.CodeBadRequest, fmt.Sprintf("job %q already registered", j.Name))
+	}
+	schedule, err := cron.ParseStandard(j.Schedule)
+	if err != nil {
+		return errors.E(op, errors.CodeBadRequest, fmt.Sprintf("invalid schedule for job %q: %s", j.Name, err))
+	}
+	if s.jobs == nil {
+		s.jobs = make(map[string]*job)
+	}
+	s.jobs[j.Name] = &job{
+		Job:      j,
+		schedule: schedule,
+	}
+	return nil
+}
+
+// Run runs the registered jobs until the given context is canceled. Only
+// one replica should run the scheduler at a time, normally the leader.
+// Any runs left running by a previous scheduler are recorded as
+// interrupted when Run starts. Run waits for all running jobs to finish
+// before returning.
+func (s *Scheduler) Run(ctx context.Context) error {
+	const op = errors.Op("scheduler.Run")
+
+	defer s.wg.Wait()
+
+	now := time.Now()
+	if n, err := s.Database.InterruptJobRuns(ctx, now); err != nil {
+		return errors.E(op, err)
+	} else if n > 0 {
+		zapctx.Warn(ctx, "interrupted job runs found", zap.Int64("count", n))
+	}
+	for _, name := range s.jobNames() {
+		j := s.jobs[name]
+		sj := dbmodel.ScheduledJob{
+			Name:      j.Name,
+			Schedule:  j.Schedule,
+			Jitter:    j.Jitter,
+			NextRunAt: sql.NullTime{Time: j.nextRun(now), Valid: true},
+		}
+		if err := s.Database.RegisterScheduledJob(ctx, &sj); err != nil {
+			return errors.E(op, err)
+		}
+		// A job that was due whilst no scheduler was running is run
+		// straight away.
+		j.next = sj.NextRunAt.Time
+	}
+
+	pollInterval := s.PollInterval
+	if pollInterval <= 0 {
+		pollInterval = DefaultPollInterval
+	}
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-timer.C:
+		}
+		s.runDue(ctx, time.Now())
+
+		wait := pollInterval
+		for _, j := range s.jobs {
+			if d := time.Until(j.next); d < wait {
+				wait = d
+			}
+		}
+		timer.Reset(max(wait, 0))
+	}
+}
+
+// runDue starts every job that has been triggered or is due at the given
+// time.
+func (s *Scheduler) runDue(ctx context.Context, now time.Time) {
+	stored, err := s.Database.ListScheduledJobs(ctx)
+	if err != nil {
+		// Scheduled runs still go ahead, triggers are picked up
+		// once the database is available.
+		zapctx.Warn(ctx, "cannot list scheduled jobs", zap.Error(err))
+	}
+	triggered := make(map[string]dbmodel.ScheduledJob)
+	for _, sj := range stored {
+		if sj.TriggeredAt.Valid {
+			triggered[sj.Name] = sj
+		}
+	}
+
+	for _, name := range s.jobNames() {
+		j := s.jobs[name]
+		ctx := zapctx.WithFields(ctx, zap.String("job", name))
+		if sj, ok := triggered[name]; ok {
+			if s.isRunning(name) {
+				// The trigger is kept until the current run
+				// finishes.
+				continue
+			}
+			if err := s.Database.ClearScheduledJobTrigger(ctx, &sj); err != nil {
+				zapctx.Error(ctx, "cannot clear job trigger", zap.Error(err))
+				continue
+			}
+			s.start(ctx, j, dbmodel.JobTriggerManual, sj.TriggeredBy)
+			continue
+		}
+		if now.Before(j.next) {
+			continue
+		}
+		j.next = j.nextRun(now)
+		sj := dbmodel.ScheduledJob{
+			Name:      name,
+			NextRunAt: sql.NullTime{Time: j.next, Valid: true},
+		}
+		if err := s.Database.UpdateScheduledJobNextRun(ctx, &sj); err != nil {
+			zapctx.Warn(ctx, "cannot update next job run", zap.Error(err))
+		}
+		if s.isRunning(name) {
+			zapctx.Warn(ctx, "job still running, skipping scheduled run")
+			continue
+		}
+		s.start(ctx, j, dbmodel.JobTriggerSchedule, "")
+	}
+}
+
+// start runs the given job in a new goroutine, recording the run in the
+// job history.
+func (s *Scheduler) start(ctx context.Context, j *job, trigger, triggeredBy string) {
+	s.mu.Lock()
+	if s.running == nil {
+		s.running = make(map[string]bool)
+	}
+	s.running[j.Name] = true
+	s.mu.Unlock()
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			delete(s.running, j.Name)
+		}()
+		s.run(ctx, j, trigger, triggeredBy)
+	}()
+}
+
+// run runs the given job, recording the run in the job history.
+func (s *Scheduler) run(ctx context.Context, j *job, trigger, triggeredBy string) {
+	run := dbmodel.JobRun{
+		JobName:     j.Name,
+		Trigger:     trigger,
+		TriggeredBy: triggeredBy,
+		Replica:     s.Replica,
+		Status:      dbmodel.JobRunRunning,
+		StartedAt:   time.Now().UTC(),
+	}
+	recorded := true
+	if err := s.Database.AddJobRun(ctx, &run); err != nil {
+		// The job is still run, it just won't appear in the
+		// history.
+		zapctx.Error(ctx, "cannot record job run", zap.Error(err))
+		recorded = false
+	}
+
+	zapctx.Info(ctx, "job started", zap.String("trigger", trigger))
+	running := servermon.ScheduledJobRunning.WithLabelValues(j.Name)
+	running.Set(1)
+	err := j.Run(ctx)
+	running.Set(0)
+	finished := time.Now().UTC()
+
+	switch {
+	case err == nil:
+		run.Status = dbmodel.JobRunSucceeded
+		servermon.ScheduledJobLastSuccess.WithLabelValues(j.Name).Set(float64(finished.Unix()))
+		zapctx.Info(ctx, "job succeeded")
+	case ctx.Err() != nil:
+		run.Status = dbmodel.JobRunInterrupted
+		run.Error = err.Error()
+		zapctx.Warn(ctx, "job interrupted", zap.Error(err))
+	default:
+		run.Status = dbmodel.JobRunFailed
+		run.Error = err.Error()
+		zapctx.Error(ctx, "job failed", zap.Error(err))
+	}
+	run.FinishedAt = sql.NullTime{Time: finished, Valid: true}
+	servermon.ScheduledJobRunCount.WithLabelValues(j.Name, run.Status).Inc()
+	servermon.ScheduledJobDurationHistogram.WithLabelValues(j.Name).Observe(finished.Sub(run.StartedAt).Seconds())
+
+	if !recorded {
+		return
+	}
+	// Record the outcome even if the scheduler is stopping.
+	ctx = context.WithoutCancel(ctx)
+	if err := s.Database.UpdateJobRun(ctx, &run); err != nil {
+		zapctx.Error(ctx, "cannot record job run", zap.Error(err))
+	}
+	keep := s.KeepRuns
+	if keep <= 0 {
+		keep = DefaultKeepRuns
+	}
+	if err := s.Database.PruneJobRuns(ctx, j.Name, keep); err != nil {
+		zapctx.Warn(ctx, "cannot prune job history", zap.Error(err))
+	}
+}
+
+func (s *Scheduler) isRunning(name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.running[name]
+}
+
+// jobNames returns the names of the registered jobs in order.
+func (s *Scheduler) jobNames() []string {
+	names := make([]string, 0, len(s.jobs))
+	for name := range s.jobs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// nextRun returns the time of the next scheduled run of the job after the
+// given time, including a random delay of up to the job's jitter.
+func (j *job) nextRun(now time.Time) time.Time {
+	next := j.schedule.Next(now)
+	if j.Jitter > 0 {
+		next = next.Add(time.Duration(rand.Int63n(int64(j.Jitter))))
+	}
+	return next
+}
@@ -0,0 +1,164 @@
+// Copyright 2024 Canonical.
+
+package scheduler_test
+
+import (
+	"context"
+	"database/sql"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm/scheduler"
+	"github.com/canonical/jimm/v3/internal/testutils/jimmtest"
+)
+
+func TestRegister(t *testing.T) {
+	c := qt.New(t)
+
+	var s scheduler.Scheduler
+	run := func(context.Context) error { return nil }
+
+	err := s.Register(scheduler.Job{Name: "job-1", Schedule: "@hourly", Run: run})
+	c.Assert(err, qt.IsNil)
+
+	err = s.Register(scheduler.Job{Name: "job-4", Schedule: "CRON_TZ=UTC 0 9 * * *", Run: run})
+	c.Assert(err, qt.IsNil)
+
+	err = s.Register(scheduler.Job{Name: "job-1", Schedule: "@daily", Run: run})
+	c.Check(err, qt.ErrorMatches, `job "job-1" already registered`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+
+	err = s.Register(scheduler.Job{Name: "job-2", Schedule: "every hour", Run: run})
+	c.Check(err, qt.ErrorMatches, `invalid schedule for job "job-2": .*`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+
+	err = s.Register(scheduler.Job{Name: "job-3", Schedule: "@hourly"})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+}
+
+func TestScheduler(t *testing.T) {
+	c := qt.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	database := &db.Database{
+		DB: jimmtest.PostgresDB(c, nil),
+	}
+	err := database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	// A run left running by a previous scheduler.
+	err = database.RegisterScheduledJob(ctx, &dbmodel.ScheduledJob{Name: "manual", Schedule: "@yearly"})
+	c.Assert(err, qt.IsNil)
+	abandoned := dbmodel.JobRun{
+		JobName:   "manual",
+		Trigger:   dbmodel.JobTriggerSchedule,
+		Replica:   "replica-0",
+		Status:    dbmodel.JobRunRunning,
+		StartedAt: time.Now().Add(-time.Hour),
+	}
+	err = database.AddJobRun(ctx, &abandoned)
+	c.Assert(err, qt.IsNil)
+
+	s := scheduler.Scheduler{
+		Database:     database,
+		Replica:      "replica-1",
+		PollInterval: 50 * time.Millisecond,
+		KeepRuns:     2,
+	}
+	var frequentRuns atomic.Int32
+	err = s.Register(scheduler.Job{
+		Name:     "frequent",
+		Schedule: "@every 1s",
+		Run: func(context.Context) error {
+			frequentRuns.Add(1)
+			return nil
+		},
+	})
+	c.Assert(err, qt.IsNil)
+	manualRuns := make(chan struct{}, 10)
+	err = s.Register(scheduler.Job{
+		Name:     "manual",
+		Schedule: "@yearly",
+		Run: func(context.Context) error {
+			manualRuns <- struct{}{}
+			return errors.E("test error")
+		},
+	})
+	c.Assert(err, qt.IsNil)
+
+	done := make(chan error)
+	go func() {
+		done <- s.Run(ctx)
+	}()
+
+	// The frequent job runs on its schedule.
+	waitFor(c, func() bool { return frequentRuns.Load() >= 2 })
+	waitFor(c, func() bool {
+		runs, err := database.ListJobRuns(ctx, "frequent", 10)
+		c.Assert(err, qt.IsNil)
+		return len(runs) == 2 && runs[1].Status == dbmodel.JobRunSucceeded
+	})
+	runs, err := database.ListJobRuns(ctx, "frequent", 10)
+	c.Assert(err, qt.IsNil)
+	c.Check(runs[1].Trigger, qt.Equals, dbmodel.JobTriggerSchedule)
+	c.Check(runs[1].Replica, qt.Equals, "replica-1")
+
+	// The manual job only runs when triggered.
+	select {
+	case <-manualRuns:
+		c.Fatalf("unexpected run of manual job")
+	default:
+	}
+	job := dbmodel.ScheduledJob{
+		Name:        "manual",
+		TriggeredAt: sql.NullTime{Time: time.Now(), Valid: true},
+		TriggeredBy: "alice@canonical.com",
+	}
+	err = database.TriggerScheduledJob(ctx, &job)
+	c.Assert(err, qt.IsNil)
+	select {
+	case <-manualRuns:
+	case <-time.After(5 * time.Second):
+		c.Fatalf("triggered job not run")
+	}
+	waitFor(c, func() bool {
+		runs, err := database.ListJobRuns(ctx, "manual", 10)
+		c.Assert(err, qt.IsNil)
+		return len(runs) == 2 && runs[0].Status != dbmodel.JobRunRunning
+	})
+	runs, err = database.ListJobRuns(ctx, "manual", 10)
+	c.Assert(err, qt.IsNil)
+	c.Check(runs[0].Status, qt.Equals, dbmodel.JobRunFailed)
+	c.Check(runs[0].Error, qt.Equals, "test error")
+	c.Check(runs[0].Trigger, qt.Equals, dbmodel.JobTriggerManual)
+	c.Check(runs[0].TriggeredBy, qt.Equals, "alice@canonical.com")
+	c.Check(runs[0].FinishedAt.Valid, qt.IsTrue)
+	c.Check(runs[1].ID, qt.Equals, abandoned.ID)
+	c.Check(runs[1].Status, qt.Equals, dbmodel.JobRunInterrupted)
+
+	job = dbmodel.ScheduledJob{Name: "manual"}
+	err = database.GetScheduledJob(ctx, &job)
+	c.Assert(err, qt.IsNil)
+	c.Check(job.TriggeredAt.Valid, qt.IsFalse)
+	c.Check(job.NextRunAt.Time.After(time.Now().Add(24*time.Hour)), qt.IsTrue)
+
+	cancel()
+	c.Check(<-done, qt.IsNil)
+}
+
+func waitFor(c *qt.C, f func() bool) {
+	deadline := time.Now().Add(10 * time.Second)
+	for !f() {
+		if time.Now().After(deadline) {
+			c.Fatalf("timed out")
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
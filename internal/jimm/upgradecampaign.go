@@ -0,0 +1,541 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/juju/state"
+	"github.com/juju/names/v5"
+	"github.com/juju/version/v2"
+	"github.com/juju/zaputil/zapctx"
+	"go.uber.org/zap"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// CreateUpgradeCampaign creates an upgrade campaign for every model that
+// matches the selectors in the given request. Models are only selected by
+// controller and label when the campaign is created; each model's agent
+// version is checked, along with a dry run of the upgrade, when the
+// campaign is validated in the background. Only JIMM administrators may
+// create upgrade campaigns.
+func (j *JIMM) CreateUpgradeCampaign(ctx context.Context, user *openfga.User, req apiparams.CreateUpgradeCampaignRequest) (apiparams.UpgradeCampaign, error) {
+	const op = errors.Op("jimm.CreateUpgradeCampaign")
+
+	if err := j.checkJimmAdmin(user); err != nil {
+		return apiparams.UpgradeCampaign{}, errors.E(op, err)
+	}
+	if req.Name == "" {
+		return apiparams.UpgradeCampaign{}, errors.E(op, errors.CodeBadRequest, "campaign name not specified")
+	}
+	if req.TargetVersion != "" {
+		if _, err := version.Parse(req.TargetVersion); err != nil {
+			return apiparams.UpgradeCampaign{}, errors.E(op, errors.CodeBadRequest, err)
+		}
+	}
+	if req.AgentVersion != "" {
+		if _, err := version.Parse(req.AgentVersion); err != nil {
+			return apiparams.UpgradeCampaign{}, errors.E(op, errors.CodeBadRequest, err)
+		}
+	}
+	selector, err := dbmodel.ParseLabelSelector(req.LabelSelector)
+	if err != nil {
+		return apiparams.UpgradeCampaign{}, errors.E(op, errors.CodeBadRequest, err)
+	}
+	if req.Concurrency < 0 || req.FailureThreshold < 0 {
+		return apiparams.UpgradeCampaign{}, errors.E(op, errors.CodeBadRequest, "concurrency and failure threshold cannot be negative")
+	}
+
+	c := dbmodel.UpgradeCampaign{
+		Name:             req.Name,
+		TargetVersion:    req.TargetVersion,
+		AgentStream:      req.AgentStream,
+		Controller:       req.Controller,
+		LabelSelector:    selector.String(),
+		AgentVersion:     req.AgentVersion,
+		Concurrency:      req.Concurrency,
+		FailureThreshold: req.FailureThreshold,
+		Status:           dbmodel.UpgradeCampaignValidating,
+	}
+	if c.Concurrency == 0 {
+		c.Concurrency = 1
+	}
+	err = j.Database.ForEachModel(ctx, func(m *dbmodel.Model) error {
+		if m.Life == state.Dead.String() || !m.UUID.Valid {
+			return nil
+		}
+		if req.Controller != "" && m.Controller.Name != req.Controller {
+			return nil
+		}
+		if !selector.Matches(m.Labels) {
+			return nil
+		}
+		c.Models = append(c.Models, dbmodel.UpgradeCampaignModel{
+			ModelID: m.ID,
+			Model:   *m,
+			Status:  dbmodel.UpgradeModelPending,
+		})
+		return nil
+	})
+	if err != nil {
+		return apiparams.UpgradeCampaign{}, errors.E(op, err)
+	}
+	if len(c.Models) == 0 {
+		return apiparams.UpgradeCampaign{}, errors.E(op, errors.CodeBadRequest, "no models match the campaign")
+	}
+	if err := j.Database.AddUpgradeCampaign(ctx, &c); err != nil {
+		return apiparams.UpgradeCampaign{}, errors.E(op, err)
+	}
+	return c.ToAPIUpgradeCampaign(false), nil
+}
+
+// StartUpgradeCampaign starts upgrading the models in a validated
+// campaign, or resumes a paused campaign. Resuming a campaign resets its
+// count of failed upgrades. Only JIMM administrators may start upgrade
+// campaigns.
+func (j *JIMM) StartUpgradeCampaign(ctx context.Context, user *openfga.User, name string) error {
+	const op = errors.Op("jimm.StartUpgradeCampaign")
+
+	if err := j.checkJimmAdmin(user); err != nil {
+		return errors.E(op, err)
+	}
+	c := dbmodel.UpgradeCampaign{Name: name}
+	if err := j.Database.GetUpgradeCampaign(ctx, &c); err != nil {
+		return errors.E(op, err)
+	}
+	if c.Status != dbmodel.UpgradeCampaignReady && c.Status != dbmodel.UpgradeCampaignPaused {
+		return errors.E(op, errors.CodeBadRequest, fmt.Sprintf("cannot start %s upgrade campaign", c.Status))
+	}
+	c.Status = dbmodel.UpgradeCampaignRunning
+	c.Failures = 0
+	c.Message = ""
+	if err := j.Database.UpdateUpgradeCampaign(ctx, &c); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// PauseUpgradeCampaign pauses a running upgrade campaign. Upgrades that
+// have already been started are allowed to finish. Only JIMM
+// administrators may pause upgrade campaigns.
+func (j *JIMM) PauseUpgradeCampaign(ctx context.Context, user *openfga.User, name string) error {
+	const op = errors.Op("jimm.PauseUpgradeCampaign")
+
+	if err := j.checkJimmAdmin(user); err != nil {
+		return errors.E(op, err)
+	}
+	c := dbmodel.UpgradeCampaign{Name: name}
+	if err := j.Database.GetUpgradeCampaign(ctx, &c); err != nil {
+		return errors.E(op, err)
+	}
+	if c.Status != dbmodel.UpgradeCampaignRunning {
+		return errors.E(op, errors.CodeBadRequest, fmt.Sprintf("cannot pause %s upgrade campaign", c.Status))
+	}
+	c.Status = dbmodel.UpgradeCampaignPaused
+	c.Message = fmt.Sprintf("paused by %s", user.Name)
+	if err := j.Database.UpdateUpgradeCampaign(ctx, &c); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// GetUpgradeCampaign returns the upgrade campaign with the given name,
+// including the state of each of its models. Only JIMM administrators may
+// read upgrade campaigns.
+func (j *JIMM) GetUpgradeCampaign(ctx context.Context, user *openfga.User, name string) (apiparams.UpgradeCampaign, error) {
+	const op = errors.Op("jimm.GetUpgradeCampaign")
+
+	if err := j.checkJimmAdmin(user); err != nil {
+		return apiparams.UpgradeCampaign{}, errors.E(op, err)
+	}
+	c := dbmodel.UpgradeCampaign{Name: name}
+	if err := j.Database.GetUpgradeCampaign(ctx, &c); err != nil {
+		return apiparams.UpgradeCampaign{}, errors.E(op, err)
+	}
+	return c.ToAPIUpgradeCampaign(true), nil
+}
+
+// ListUpgradeCampaigns returns all the upgrade campaigns along with their
+// progress. Only JIMM administrators may list upgrade campaigns.
+func (j *JIMM) ListUpgradeCampaigns(ctx context.Context, user *openfga.User) ([]apiparams.UpgradeCampaign, error) {
+	const op = errors.Op("jimm.ListUpgradeCampaigns")
+
+	if err := j.checkJimmAdmin(user); err != nil {
+		return nil, errors.E(op, err)
+	}
+	campaigns, err := j.Database.ListUpgradeCampaigns(ctx)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	resp := make([]apiparams.UpgradeCampaign, len(campaigns))
+	for i, c := range campaigns {
+		resp[i] = c.ToAPIUpgradeCampaign(false)
+	}
+	return resp, nil
+}
+
+// RemoveUpgradeCampaign removes the upgrade campaign with the given name.
+// A running campaign must be paused before it can be removed. Only JIMM
+// administrators may remove upgrade campaigns.
+func (j *JIMM) RemoveUpgradeCampaign(ctx context.Context, user *openfga.User, name string) error {
+	const op = errors.Op("jimm.RemoveUpgradeCampaign")
+
+	if err := j.checkJimmAdmin(user); err != nil {
+		return errors.E(op, err)
+	}
+	c := dbmodel.UpgradeCampaign{Name: name}
+	if err := j.Database.GetUpgradeCampaign(ctx, &c); err != nil {
+		return errors.E(op, err)
+	}
+	if c.Status == dbmodel.UpgradeCampaignRunning {
+		return errors.E(op, errors.CodeBadRequest, "cannot remove a running upgrade campaign")
+	}
+	if err := j.Database.DeleteUpgradeCampaign(ctx, &c); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// RunUpgradeCampaigns periodically processes every upgrade campaign that
+// is being validated or is running, until the given context is cancelled
+// or there is an error querying the database. The state of every model
+// in a campaign is stored as it changes, so campaigns interrupted by a
+// restart are resumed the next time they are processed.
+func (j *JIMM) RunUpgradeCampaigns(ctx context.Context, interval time.Duration) error {
+	const op = errors.Op("jimm.RunUpgradeCampaigns")
+
+	r := newRunner()
+	// Ensure that all started goroutines are completed before we return.
+	defer r.wait()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		campaigns, err := j.Database.ListUpgradeCampaigns(ctx, dbmodel.UpgradeCampaignValidating, dbmodel.UpgradeCampaignRunning)
+		if err != nil {
+			// Ignore temporary database errors.
+			if errors.ErrorCode(err) != errors.CodeDatabaseLocked {
+				return errors.E(op, err)
+			}
+			zapctx.Warn(ctx, "temporary error polling for upgrade campaigns", zap.Error(err))
+		}
+		for _, c := range campaigns {
+			name := c.Name
+			r.run(name, func() {
+				ctx := zapctx.WithFields(ctx, zap.String("campaign", name))
+				if err := j.ProcessUpgradeCampaign(ctx, name); err != nil {
+					zapctx.Error(ctx, "cannot process upgrade campaign", zap.Error(err))
+				}
+			})
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProcessUpgradeCampaign performs the work outstanding for the upgrade
+// campaign with the given name. A campaign being validated has each of
+// its pending models checked and a dry run of the upgrade performed, after
+// which it is ready to be started. A running campaign has each of its
+// validated models upgraded, with at most the campaign's concurrency
+// upgrading at once, until either every model has been processed, the
+// campaign is paused, or the campaign's failure threshold is reached.
+func (j *JIMM) ProcessUpgradeCampaign(ctx context.Context, name string) error {
+	const op = errors.Op("jimm.ProcessUpgradeCampaign")
+
+	c := dbmodel.UpgradeCampaign{Name: name}
+	if err := j.Database.GetUpgradeCampaign(ctx, &c); err != nil {
+		return errors.E(op, err)
+	}
+	var err error
+	switch c.Status {
+	case dbmodel.UpgradeCampaignValidating:
+		err = j.validateUpgradeCampaign(ctx, &c)
+	case dbmodel.UpgradeCampaignRunning:
+		err = j.runUpgradeCampaign(ctx, &c)
+	}
+	if err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// validateUpgradeCampaign validates each pending model in the given
+// campaign and then marks the campaign as ready to start. Models on
+// controllers in maintenance are left pending, and the campaign is not
+// ready until they have been validated.
+func (j *JIMM) validateUpgradeCampaign(ctx context.Context, c *dbmodel.UpgradeCampaign) error {
+	var targetVersion version.Number
+	if c.TargetVersion != "" {
+		var err error
+		if targetVersion, err = version.Parse(c.TargetVersion); err != nil {
+			return err
+		}
+	}
+	var deferred atomic.Bool
+	forEachCampaignModel(ctx, c, dbmodel.UpgradeModelPending, func(m *dbmodel.UpgradeCampaignModel) {
+		if m.Model.Controller.Maintenance.Active(time.Now()) {
+			deferred.Store(true)
+			return
+		}
+		if err := checkLeader(ctx); err != nil {
+			zapctx.Error(ctx, "cannot validate upgrade campaign model", zap.String("model", m.Model.UUID.String), zap.Error(err))
+			return
+		}
+		j.validateUpgradeCampaignModel(ctx, c, targetVersion, m)
+		if err := checkLeader(ctx); err != nil {
+			zapctx.Error(ctx, "cannot update upgrade campaign model", zap.String("model", m.Model.UUID.String), zap.Error(err))
+			return
+		}
+		if err := j.Database.UpdateUpgradeCampaignModel(ctx, m); err != nil {
+			zapctx.Error(ctx, "cannot update upgrade campaign model", zap.String("model", m.Model.UUID.String), zap.Error(err))
+		}
+	})
+	if deferred.Load() || ctx.Err() != nil {
+		return nil
+	}
+
+	var validated int
+	for _, m := range c.Models {
+		if m.Status == dbmodel.UpgradeModelValidated {
+			validated++
+		}
+	}
+	c.Status = dbmodel.UpgradeCampaignReady
+	c.Message = fmt.Sprintf("%d of %d models validated", validated, len(c.Models))
+	if err := checkLeader(ctx); err != nil {
+		return err
+	}
+	return j.Database.UpdateUpgradeCampaign(ctx, c)
+}
+
+// validateUpgradeCampaignModel checks whether the given model should, and
+// can, be upgraded by the given campaign, updating the model's state
+// accordingly.
+func (j *JIMM) validateUpgradeCampaignModel(ctx context.Context, c *dbmodel.UpgradeCampaign, targetVersion version.Number, m *dbmodel.UpgradeCampaignModel) {
+	mt := m.Model.ResourceTag()
+	fail := func(status string, err error) {
+		m.Status = status
+		m.Error = err.Error()
+	}
+
+	api, err := j.dial(ctx, &m.Model.Controller, names.ModelTag{})
+	if err != nil {
+		fail(dbmodel.UpgradeModelInvalid, err)
+		return
+	}
+	defer api.Close()
+
+	info := jujuparams.ModelInfo{UUID: mt.Id()}
+	if err := api.ModelInfo(ctx, &info); err != nil {
+		fail(dbmodel.UpgradeModelInvalid, err)
+		return
+	}
+	if info.AgentVersion != nil {
+		m.FromVersion = info.AgentVersion.String()
+	}
+	if c.AgentVersion != "" && m.FromVersion != c.AgentVersion {
+		fail(dbmodel.UpgradeModelSkipped, fmt.Errorf("agent version %s does not match %s", m.FromVersion, c.AgentVersion))
+		return
+	}
+	if targetVersion != version.Zero && info.AgentVersion != nil && targetVersion.Compare(*info.AgentVersion) <= 0 {
+		fail(dbmodel.UpgradeModelSkipped, fmt.Errorf("agent version %s is not older than %s", m.FromVersion, targetVersion))
+		return
+	}
+	if err := api.ValidateModelUpgrade(ctx, mt, false); err != nil {
+		fail(dbmodel.UpgradeModelInvalid, err)
+		return
+	}
+	chosen, err := api.UpgradeModel(ctx, mt, targetVersion, c.AgentStream, true)
+	if err != nil {
+		fail(dbmodel.UpgradeModelInvalid, err)
+		return
+	}
+	m.Status = dbmodel.UpgradeModelValidated
+	m.ChosenVersion = chosen.String()
+	m.Error = ""
+}
+
+// runUpgradeCampaign upgrades each validated model in the given campaign.
+// Models on controllers in maintenance are left validated, and the
+// campaign is not complete until they have been upgraded.
+func (j *JIMM) runUpgradeCampaign(ctx context.Context, c *dbmodel.UpgradeCampaign) error {
+	var targetVersion version.Number
+	if c.TargetVersion != "" {
+		var err error
+		if targetVersion, err = version.Parse(c.TargetVersion); err != nil {
+			return err
+		}
+	}
+
+	if err := checkLeader(ctx); err != nil {
+		return err
+	}
+
+	// Upgrades that were in progress when the campaign was last
+	// interrupted have an unknown outcome, so they are attempted again.
+	for i := range c.Models {
+		m := &c.Models[i]
+		if m.Status != dbmodel.UpgradeModelUpgrading {
+			continue
+		}
+		m.Status = dbmodel.UpgradeModelValidated
+		if err := j.Database.UpdateUpgradeCampaignModel(ctx, m); err != nil {
+			return err
+		}
+	}
+
+	var mu sync.Mutex
+	stopped := false
+	failures := c.Failures
+	var deferred atomic.Bool
+	forEachCampaignModel(ctx, c, dbmodel.UpgradeModelValidated, func(m *dbmodel.UpgradeCampaignModel) {
+		if m.Model.Controller.Maintenance.Active(time.Now()) {
+			deferred.Store(true)
+			return
+		}
+		// Check that the campaign is still running before each
+		// upgrade, it may have been paused.
+		mu.Lock()
+		if !stopped {
+			current := dbmodel.UpgradeCampaign{Name: c.Name}
+			if err := j.Database.GetUpgradeCampaign(ctx, &current); err != nil || current.Status != dbmodel.UpgradeCampaignRunning {
+				stopped = true
+			} else if err := checkLeader(ctx); err != nil {
+				zapctx.Error(ctx, "cannot continue upgrade campaign", zap.Error(err))
+				stopped = true
+			}
+		}
+		stop := stopped
+		mu.Unlock()
+		if stop {
+			return
+		}
+
+		m.Status = dbmodel.UpgradeModelUpgrading
+		if err := j.Database.UpdateUpgradeCampaignModel(ctx, m); err != nil {
+			zapctx.Error(ctx, "cannot update upgrade campaign model", zap.String("model", m.Model.UUID.String), zap.Error(err))
+			return
+		}
+		err := j.upgradeCampaignModel(ctx, c, targetVersion, m)
+		if err != nil && ctx.Err() != nil {
+			// The upgrade was interrupted, it will be retried
+			// when the campaign is next processed.
+			return
+		}
+		if err := checkLeader(ctx); err != nil {
+			// The new leader retries the upgrade.
+			zapctx.Error(ctx, "cannot update upgrade campaign model", zap.String("model", m.Model.UUID.String), zap.Error(err))
+			return
+		}
+		if err := j.Database.UpdateUpgradeCampaignModel(ctx, m); err != nil {
+			zapctx.Error(ctx, "cannot update upgrade campaign model", zap.String("model", m.Model.UUID.String), zap.Error(err))
+		}
+		if err == nil {
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		failures++
+		current := dbmodel.UpgradeCampaign{Name: c.Name}
+		if err := j.Database.GetUpgradeCampaign(ctx, &current); err != nil {
+			zapctx.Error(ctx, "cannot get upgrade campaign", zap.Error(err))
+			return
+		}
+		if err := checkLeader(ctx); err != nil {
+			zapctx.Error(ctx, "cannot update upgrade campaign", zap.Error(err))
+			return
+		}
+		current.Failures = failures
+		if current.Status == dbmodel.UpgradeCampaignRunning && c.FailureThreshold > 0 && failures >= c.FailureThreshold {
+			current.Status = dbmodel.UpgradeCampaignPaused
+			current.Message = fmt.Sprintf("paused after %d failed upgrades", failures)
+			stopped = true
+		}
+		if err := j.Database.UpdateUpgradeCampaign(ctx, &current); err != nil {
+			zapctx.Error(ctx, "cannot update upgrade campaign", zap.Error(err))
+		}
+	})
+	if stopped || deferred.Load() || ctx.Err() != nil {
+		return nil
+	}
+
+	current := dbmodel.UpgradeCampaign{Name: c.Name}
+	if err := j.Database.GetUpgradeCampaign(ctx, &current); err != nil {
+		return err
+	}
+	if current.Status != dbmodel.UpgradeCampaignRunning {
+		return nil
+	}
+	counts := current.ToAPIUpgradeCampaign(false).Progress
+	current.Status = dbmodel.UpgradeCampaignCompleted
+	current.Message = fmt.Sprintf("%d models upgraded, %d failed", counts[dbmodel.UpgradeModelUpgraded], counts[dbmodel.UpgradeModelFailed])
+	if err := checkLeader(ctx); err != nil {
+		return err
+	}
+	return j.Database.UpdateUpgradeCampaign(ctx, &current)
+}
+
+// upgradeCampaignModel starts the upgrade of the given model, updating
+// the model's state with the outcome.
+func (j *JIMM) upgradeCampaignModel(ctx context.Context, c *dbmodel.UpgradeCampaign, targetVersion version.Number, m *dbmodel.UpgradeCampaignModel) error {
+	api, err := j.dial(ctx, &m.Model.Controller, names.ModelTag{})
+	if err == nil {
+		defer api.Close()
+		var chosen version.Number
+		chosen, err = api.UpgradeModel(ctx, m.Model.ResourceTag(), targetVersion, c.AgentStream, false)
+		if err == nil {
+			m.Status = dbmodel.UpgradeModelUpgraded
+			m.ChosenVersion = chosen.String()
+			m.Error = ""
+			return nil
+		}
+	}
+	m.Status = dbmodel.UpgradeModelFailed
+	m.Error = err.Error()
+	return err
+}
+
+// forEachCampaignModel calls the given function for each model in the
+// given campaign with the given status, running at most the campaign's
+// concurrency at once. No more calls are started once the context is
+// cancelled. forEachCampaignModel returns when all calls have completed.
+func forEachCampaignModel(ctx context.Context, c *dbmodel.UpgradeCampaign, status string, f func(*dbmodel.UpgradeCampaignModel)) {
+	concurrency := c.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	for i := range c.Models {
+		m := &c.Models[i]
+		if m.Status != status {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case sem <- struct{}{}:
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			f(m)
+		}()
+	}
+}
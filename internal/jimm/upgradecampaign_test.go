@@ -0,0 +1,204 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+	"github.com/juju/version/v2"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	"github.com/canonical/jimm/v3/internal/testutils/jimmtest"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const upgradeCampaignTestEnv = `clouds:
+- name: test-cloud
+  type: test-provider
+  regions:
+  - name: test-region-1
+cloud-credentials:
+- name: test-credential-1
+  owner: alice@canonical.com
+  cloud: test-cloud
+  auth-type: empty
+controllers:
+- name: controller-1
+  uuid: 00000000-0000-0000-0000-0000-0000000000001
+  cloud: test-cloud
+  region: test-region-1
+models:
+- name: model-1
+  owner: alice@canonical.com
+  uuid: 00000001-0000-0000-0000-0000-000000000001
+  cloud: test-cloud
+  region: test-region-1
+  cloud-credential: test-credential-1
+  controller: controller-1
+  life: alive
+- name: model-2
+  owner: alice@canonical.com
+  uuid: 00000001-0000-0000-0000-0000-000000000002
+  cloud: test-cloud
+  region: test-region-1
+  cloud-credential: test-credential-1
+  controller: controller-1
+  life: alive
+- name: model-3
+  owner: alice@canonical.com
+  uuid: 00000001-0000-0000-0000-0000-000000000003
+  cloud: test-cloud
+  region: test-region-1
+  cloud-credential: test-credential-1
+  controller: controller-1
+  life: alive
+users:
+- username: alice@canonical.com
+  controller-access: login
+`
+
+func TestUpgradeCampaign(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	agentVersions := map[string]version.Number{
+		"00000001-0000-0000-0000-0000-000000000001": version.MustParse("3.4.0"),
+		"00000001-0000-0000-0000-0000-000000000002": version.MustParse("3.4.0"),
+		"00000001-0000-0000-0000-0000-000000000003": version.MustParse("3.5.1"),
+	}
+	var mu sync.Mutex
+	var upgraded []string
+	dialer := &jimmtest.Dialer{
+		API: &jimmtest.API{
+			ModelInfo_: func(_ context.Context, info *jujuparams.ModelInfo) error {
+				v := agentVersions[info.UUID]
+				info.AgentVersion = &v
+				return nil
+			},
+			ValidateModelUpgrade_: func(context.Context, names.ModelTag, bool) error {
+				return nil
+			},
+			UpgradeModel_: func(_ context.Context, mt names.ModelTag, target version.Number, _ string, dryRun bool) (version.Number, error) {
+				if dryRun {
+					return target, nil
+				}
+				if mt.Id() == "00000001-0000-0000-0000-0000-000000000002" {
+					return version.Zero, errors.E("upgrade in progress")
+				}
+				mu.Lock()
+				defer mu.Unlock()
+				upgraded = append(upgraded, mt.Id())
+				return target, nil
+			},
+		},
+	}
+	j := jimmtest.NewJIMM(c, &jimm.Parameters{
+		Dialer: dialer,
+	})
+
+	env := jimmtest.ParseEnvironment(c, upgradeCampaignTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, j.OpenFGAClient)
+	aliceIdentity := env.User("alice@canonical.com").DBObject(c, j.Database)
+	alice := openfga.NewUser(&aliceIdentity, j.OpenFGAClient)
+	admin := openfga.NewUser(&aliceIdentity, j.OpenFGAClient)
+	admin.JimmAdmin = true
+
+	req := apiparams.CreateUpgradeCampaignRequest{
+		Name:             "to-3.5.1",
+		TargetVersion:    "3.5.1",
+		Concurrency:      2,
+		FailureThreshold: 1,
+	}
+	_, err := j.CreateUpgradeCampaign(ctx, alice, req)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+
+	_, err = j.CreateUpgradeCampaign(ctx, admin, apiparams.CreateUpgradeCampaignRequest{Name: "none", Controller: "no-such-controller"})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+
+	campaign, err := j.CreateUpgradeCampaign(ctx, admin, req)
+	c.Assert(err, qt.IsNil)
+	c.Check(campaign.Status, qt.Equals, dbmodel.UpgradeCampaignValidating)
+	c.Check(campaign.Progress, qt.DeepEquals, map[string]int{dbmodel.UpgradeModelPending: 3})
+
+	// A campaign cannot be started until it has been validated.
+	err = j.StartUpgradeCampaign(ctx, admin, "to-3.5.1")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+
+	err = j.ProcessUpgradeCampaign(ctx, "to-3.5.1")
+	c.Assert(err, qt.IsNil)
+	campaign, err = j.GetUpgradeCampaign(ctx, admin, "to-3.5.1")
+	c.Assert(err, qt.IsNil)
+	c.Check(campaign.Status, qt.Equals, dbmodel.UpgradeCampaignReady)
+	c.Check(campaign.Message, qt.Equals, "2 of 3 models validated")
+	c.Assert(campaign.Models, qt.HasLen, 3)
+	c.Check(campaign.Models[0].Status, qt.Equals, dbmodel.UpgradeModelValidated)
+	c.Check(campaign.Models[0].FromVersion, qt.Equals, "3.4.0")
+	c.Check(campaign.Models[0].ChosenVersion, qt.Equals, "3.5.1")
+	c.Check(campaign.Models[2].Status, qt.Equals, dbmodel.UpgradeModelSkipped)
+	c.Check(campaign.Models[2].Error, qt.Equals, "agent version 3.5.1 is not older than 3.5.1")
+
+	// Simulate a restart part way through upgrading the first model.
+	uc := dbmodel.UpgradeCampaign{Name: "to-3.5.1"}
+	err = j.Database.GetUpgradeCampaign(ctx, &uc)
+	c.Assert(err, qt.IsNil)
+	uc.Models[0].Status = dbmodel.UpgradeModelUpgrading
+	err = j.Database.UpdateUpgradeCampaignModel(ctx, &uc.Models[0])
+	c.Assert(err, qt.IsNil)
+
+	err = j.StartUpgradeCampaign(ctx, admin, "to-3.5.1")
+	c.Assert(err, qt.IsNil)
+	err = j.ProcessUpgradeCampaign(ctx, "to-3.5.1")
+	c.Assert(err, qt.IsNil)
+
+	// The failed upgrade reaches the failure threshold and pauses the
+	// campaign.
+	campaign, err = j.GetUpgradeCampaign(ctx, admin, "to-3.5.1")
+	c.Assert(err, qt.IsNil)
+	c.Check(campaign.Status, qt.Equals, dbmodel.UpgradeCampaignPaused)
+	c.Check(campaign.Failures, qt.Equals, 1)
+	c.Check(campaign.Message, qt.Equals, "paused after 1 failed upgrades")
+	c.Check(campaign.Models[0].Status, qt.Equals, dbmodel.UpgradeModelUpgraded)
+	c.Check(campaign.Models[1].Status, qt.Equals, dbmodel.UpgradeModelFailed)
+	c.Check(campaign.Models[1].Error, qt.Equals, "upgrade in progress")
+	c.Check(upgraded, qt.DeepEquals, []string{"00000001-0000-0000-0000-0000-000000000001"})
+
+	err = j.RemoveUpgradeCampaign(ctx, alice, "to-3.5.1")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+
+	// Resuming the campaign completes it as there is nothing left to
+	// upgrade.
+	err = j.StartUpgradeCampaign(ctx, admin, "to-3.5.1")
+	c.Assert(err, qt.IsNil)
+	err = j.RemoveUpgradeCampaign(ctx, admin, "to-3.5.1")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+	err = j.ProcessUpgradeCampaign(ctx, "to-3.5.1")
+	c.Assert(err, qt.IsNil)
+
+	campaigns, err := j.ListUpgradeCampaigns(ctx, admin)
+	c.Assert(err, qt.IsNil)
+	c.Assert(campaigns, qt.HasLen, 1)
+	c.Check(campaigns[0].Status, qt.Equals, dbmodel.UpgradeCampaignCompleted)
+	c.Check(campaigns[0].Message, qt.Equals, "1 models upgraded, 1 failed")
+	c.Check(campaigns[0].Progress, qt.DeepEquals, map[string]int{
+		dbmodel.UpgradeModelUpgraded: 1,
+		dbmodel.UpgradeModelFailed:   1,
+		dbmodel.UpgradeModelSkipped:  1,
+	})
+	c.Check(campaigns[0].Models, qt.IsNil)
+
+	err = j.PauseUpgradeCampaign(ctx, admin, "to-3.5.1")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+
+	err = j.RemoveUpgradeCampaign(ctx, admin, "to-3.5.1")
+	c.Assert(err, qt.IsNil)
+	_, err = j.GetUpgradeCampaign(ctx, admin, "to-3.5.1")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+}
@@ -5,8 +5,10 @@ package jimm
 import (
 	"context"
 	"database/sql"
+	"sync"
 	"time"
 
+	jujuparams "github.com/juju/juju/rpc/params"
 	"github.com/juju/names/v5"
 	"github.com/juju/zaputil/zapctx"
 	"go.uber.org/zap"
@@ -22,6 +24,12 @@ type Publisher interface {
 	Publish(model string, content interface{}) <-chan struct{}
 }
 
+// A ModelEventRecorder records model lifecycle events for delivery to
+// webhooks.
+type ModelEventRecorder interface {
+	RecordModelEvent(ctx context.Context, eventType string, m *dbmodel.Model, message string)
+}
+
 // A Watcher watches juju controllers for changes to all models.
 type Watcher struct {
 	// Database is the database used by the Watcher.
@@ -35,10 +43,35 @@ type Watcher struct {
 	// model summaries.
 	Pubsub Publisher
 
+	// Broker, if set, is used to publish model summaries instead of
+	// Pubsub. The broker distributes the summaries to the pub-sub hubs
+	// of every JIMM replica, see DistributeModelSummaries.
+	Broker ModelSummaryBroker
+
+	// ModelEvents, if set, is used to record an event when the status of
+	// a model moves to error. It should only be set on a single JIMM
+	// replica so that each change is only recorded once.
+	ModelEvents ModelEventRecorder
+
+	// PerModelMetrics enables the model health metrics labelled with
+	// each model. These can produce a very large number of series so
+	// they are disabled by default, only the per-controller metrics are
+	// exported.
+	PerModelMetrics bool
+
+	// Status, if set, records whether the watcher for each controller
+	// is connected.
+	Status *ModelSummaryWatchStatus
+
 	controllerUnavailableChan chan error
 	deltaProcessedChan        chan bool
 }
 
+// agentVersionRefreshInterval is the minimum time between requests for the
+// agent versions of the models on a controller. The model summary watcher
+// does not report agent versions so they are fetched separately.
+const agentVersionRefreshInterval = 5 * time.Minute
+
 // WatchAllModelSummaries starts the watcher which connects to all known
 // controllers and monitors them for model summary updates.
 // WatchAllModelSummaries polls the database at the given
@@ -57,19 +90,58 @@ func (w *Watcher) WatchAllModelSummaries(ctx context.Context, interval time.Dura
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
+	// pause holds the functions that stop the running watchers, so that
+	// they can be paused while their controller is in maintenance.
+	var mu sync.Mutex
+	pause := make(map[string]context.CancelFunc)
+
 	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 	for {
+		seen := make(map[string]bool)
 		err := w.Database.ForEachController(ctx, func(ctl *dbmodel.Controller) error {
 			ctx := zapctx.WithFields(ctx, zap.String("controller", ctl.Name))
+			seen[ctl.Name] = true
+			if ctl.Maintenance.Active(time.Now()) {
+				mu.Lock()
+				if cancel, ok := pause[ctl.Name]; ok {
+					zapctx.Info(ctx, "pausing model summary watcher for controller maintenance")
+					cancel()
+				}
+				mu.Unlock()
+				w.Status.pauseController(ctl.Name)
+				return nil
+			}
 			r.run(ctl.Name, func() {
+				wctx, cancel := context.WithCancel(ctx)
+				mu.Lock()
+				pause[ctl.Name] = cancel
+				mu.Unlock()
+				defer func() {
+					mu.Lock()
+					delete(pause, ctl.Name)
+					mu.Unlock()
+					cancel()
+				}()
+
 				zapctx.Info(ctx, "starting model summary watcher")
-				err := w.watchAllModelSummaries(ctx, ctl)
+				err := w.watchAllModelSummaries(wctx, ctl)
 				zapctx.Error(ctx, "model summary watcher stopped", zap.Error(err))
+				switch {
+				case ctx.Err() != nil:
+					w.Status.removeController(ctl.Name)
+				case wctx.Err() != nil:
+					// The watcher has been paused, the status has
+					// already been recorded.
+				default:
+					w.Status.setController(ctl.Name, err)
+				}
 			})
 			return nil
 		})
-		if err != nil {
+		if err == nil {
+			w.Status.retainControllers(seen)
+		} else {
 			// Ignore temporary database errors.
 			if errors.ErrorCode(err) != errors.CodeDatabaseLocked {
 				return errors.E(op, err)
@@ -140,12 +212,16 @@ func (w *Watcher) watchAllModelSummaries(ctx context.Context, ctl *dbmodel.Contr
 	if err != nil {
 		return errors.E(op, err)
 	}
+	w.Status.setController(ctl.Name, nil)
 	defer func() {
 		if err := api.ModelSummaryWatcherStop(ctx, id); err != nil {
 			zapctx.Error(ctx, "failed to stop model summary watcher", zap.Error(err))
 		}
 	}()
 
+	health := newModelHealthMetrics(ctl.Name, w.PerModelMetrics)
+	defer health.reset()
+	var agentVersionsUpdated time.Time
 	for {
 		select {
 		case <-ctx.Done():
@@ -159,6 +235,9 @@ func (w *Watcher) watchAllModelSummaries(ctx context.Context, ctl *dbmodel.Contr
 		}
 		// Sanitize the model abstracts.
 		for _, summary := range modelSummaries {
+			if summary.Removed {
+				health.remove(summary.UUID)
+			}
 			m := dbmodel.Model{
 				UUID: sql.NullString{
 					String: summary.UUID,
@@ -180,7 +259,68 @@ func (w *Watcher) watchAllModelSummaries(ctx context.Context, ctl *dbmodel.Contr
 				admins = append(admins, admin)
 			}
 			summary.Admins = admins
-			w.Pubsub.Publish(summary.UUID, summary)
+			w.publish(ctx, summary)
+
+			w.updateSummaryStatus(ctx, &m, summary)
+			if !summary.Removed {
+				health.update(summary, m.OwnerIdentityName)
+			}
+		}
+		if time.Since(agentVersionsUpdated) > agentVersionRefreshInterval {
+			results, err := api.ListModelSummaries(ctx, jujuparams.ModelSummariesRequest{All: true})
+			if err != nil {
+				zapctx.Error(ctx, "failed to list model summaries", zap.String("controller", ctl.Name), zap.Error(err))
+			} else {
+				health.updateAgentVersions(results)
+			}
+			agentVersionsUpdated = time.Now()
+		}
+		health.export()
+	}
+}
+
+// updateSummaryStatus stores the status, and status message, in the given
+// summary of the given model if they have changed. The last status is
+// stored, rather than held by the watcher, so that a move to error is only
+// recorded once, however many times the watcher reconnects, and so that
+// the time the status changed is known to every replica.
+func (w *Watcher) updateSummaryStatus(ctx context.Context, m *dbmodel.Model, summary jujuparams.ModelAbstract) {
+	msg := modelSummaryMessage(summary)
+	if summary.Removed || (summary.Status == m.SummaryStatus && msg == m.SummaryStatusMessage) {
+		return
+	}
+	if summary.Status != m.SummaryStatus {
+		if summary.Status == "error" && w.ModelEvents != nil {
+			w.ModelEvents.RecordModelEvent(ctx, dbmodel.WebhookEventModelError, m, msg)
+		}
+		m.SummaryStatus = summary.Status
+		m.SummaryStatusSince = sql.NullTime{Time: time.Now().UTC(), Valid: true}
+	}
+	m.SummaryStatusMessage = msg
+	if err := w.Database.UpdateModelSummaryStatus(ctx, m); err != nil {
+		zapctx.Error(ctx, "cannot update model status", zap.String("model", summary.UUID), zap.Error(err))
+	}
+}
+
+// publish publishes the given model summary to the broker, if there is
+// one, or the pub-sub hub.
+func (w *Watcher) publish(ctx context.Context, summary jujuparams.ModelAbstract) {
+	if w.Broker == nil {
+		w.Pubsub.Publish(summary.UUID, summary)
+		return
+	}
+	if err := w.Broker.PublishModelSummary(ctx, summary); err != nil {
+		zapctx.Error(ctx, "cannot publish model summary", zap.String("model", summary.UUID), zap.Error(err))
+	}
+}
+
+// modelSummaryMessage returns the first status message in the given model
+// summary.
+func modelSummaryMessage(summary jujuparams.ModelAbstract) string {
+	for _, msg := range summary.Messages {
+		if msg.Message != "" {
+			return msg.Agent + ": " + msg.Message
 		}
 	}
+	return ""
 }
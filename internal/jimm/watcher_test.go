@@ -75,6 +75,7 @@ var modelSummaryWatcherTests = []struct {
 	name           string
 	summaries      [][]jujuparams.ModelAbstract
 	checkPublisher func(*qt.C, *testPublisher)
+	checkEvents    func(*qt.C, *testModelEventRecorder)
 }{{
 	name: "ModelSummaries",
 	summaries: [][]jujuparams.ModelAbstract{
@@ -122,6 +123,37 @@ var modelSummaryWatcherTests = []struct {
 			},
 		})
 	},
+}, {
+	name: "ModelErrorEvents",
+	summaries: [][]jujuparams.ModelAbstract{
+		{{
+			UUID:     "00000002-0000-0000-0000-000000000001",
+			Status:   "error",
+			Messages: []jujuparams.ModelSummaryMessage{{Agent: "unit-0", Message: "hook failed"}},
+		}},
+		{{
+			UUID:   "00000002-0000-0000-0000-000000000001",
+			Status: "error",
+		}},
+		{{
+			UUID:   "00000002-0000-0000-0000-000000000001",
+			Status: "available",
+		}},
+		{{
+			UUID:   "00000002-0000-0000-0000-000000000001",
+			Status: "error",
+		}},
+		nil,
+	},
+	checkPublisher: func(c *qt.C, publisher *testPublisher) {
+		c.Check(publisher.messages, qt.HasLen, 4)
+	},
+	checkEvents: func(c *qt.C, recorder *testModelEventRecorder) {
+		c.Check(recorder.events, qt.DeepEquals, []string{
+			"model-error 00000002-0000-0000-0000-000000000001 controller-1 unit-0: hook failed",
+			"model-error 00000002-0000-0000-0000-000000000001 controller-1 ",
+		})
+	},
 }}
 
 func TestModelSummaryWatcher(t *testing.T) {
@@ -136,9 +168,11 @@ func TestModelSummaryWatcher(t *testing.T) {
 			var stopped uint32
 
 			publisher := &testPublisher{}
+			recorder := &testModelEventRecorder{}
 
 			w := &jimm.Watcher{
-				Pubsub: publisher,
+				Pubsub:      publisher,
+				ModelEvents: recorder,
 				Database: &db.Database{
 					DB: jimmtest.PostgresDB(c, nil),
 				},
@@ -210,10 +244,83 @@ func TestModelSummaryWatcher(t *testing.T) {
 			wg.Wait()
 
 			test.checkPublisher(c, publisher)
+			if test.checkEvents != nil {
+				test.checkEvents(c, recorder)
+			} else {
+				c.Check(recorder.events, qt.HasLen, 0)
+			}
 		})
 	}
 }
 
+func TestModelSummaryWatcherErrorEventsAcrossReconnects(t *testing.T) {
+	c := qt.New(t)
+
+	database := &db.Database{
+		DB: jimmtest.PostgresDB(c, nil),
+	}
+	err := database.Migrate(context.Background(), false)
+	c.Assert(err, qt.IsNil)
+	env := jimmtest.ParseEnvironment(c, testWatcherEnv)
+	env.PopulateDB(c, database)
+
+	recorder := &testModelEventRecorder{}
+	// watch runs a new watcher, as after a reconnection, until it has
+	// processed the given summaries.
+	watch := func(summaries ...jujuparams.ModelAbstract) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		w := &jimm.Watcher{
+			Pubsub:      &testPublisher{},
+			ModelEvents: recorder,
+			Database:    database,
+			Dialer: &jimmtest.Dialer{
+				API: &jimmtest.API{
+					WatchAllModelSummaries_: func(_ context.Context) (string, error) {
+						return "1", nil
+					},
+					ModelSummaryWatcherNext_: func(ctx context.Context, _ string) ([]jujuparams.ModelAbstract, error) {
+						if summaries != nil {
+							s := summaries
+							summaries = nil
+							return s, nil
+						}
+						cancel()
+						<-ctx.Done()
+						return nil, ctx.Err()
+					},
+					ModelSummaryWatcherStop_: func(_ context.Context, _ string) error {
+						return nil
+					},
+					SupportsModelSummaryWatcher_: true,
+					ModelInfo_: func(_ context.Context, _ *jujuparams.ModelInfo) error {
+						return errors.E(errors.CodeNotFound)
+					},
+				},
+			},
+		}
+		err := w.WatchAllModelSummaries(ctx, time.Hour)
+		checkIfContextCanceled(c, ctx, err)
+	}
+
+	const modelUUID = "00000002-0000-0000-0000-000000000001"
+	watch(jujuparams.ModelAbstract{UUID: modelUUID, Status: "error"})
+	watch(jujuparams.ModelAbstract{UUID: modelUUID, Status: "error"})
+	c.Check(recorder.events, qt.DeepEquals, []string{
+		"model-error " + modelUUID + " controller-1 ",
+	})
+
+	m := dbmodel.Model{UUID: sql.NullString{String: modelUUID, Valid: true}}
+	err = database.GetModel(context.Background(), &m)
+	c.Assert(err, qt.IsNil)
+	c.Check(m.SummaryStatus, qt.Equals, "error")
+	c.Check(m.SummaryStatusSince.Valid, qt.IsTrue)
+
+	watch(jujuparams.ModelAbstract{UUID: modelUUID, Status: "available"})
+	watch(jujuparams.ModelAbstract{UUID: modelUUID, Status: "error"})
+	c.Check(recorder.events, qt.HasLen, 2)
+}
+
 func TestWatcherSetsControllerUnavailable(t *testing.T) {
 	c := qt.New(t)
 
@@ -359,3 +466,14 @@ func (p *testPublisher) Publish(model string, content interface{}) <-chan struct
 	close(done)
 	return done
 }
+
+type testModelEventRecorder struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (r *testModelEventRecorder) RecordModelEvent(_ context.Context, eventType string, m *dbmodel.Model, message string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, eventType+" "+m.UUID.String+" "+m.Controller.Name+" "+message)
+}
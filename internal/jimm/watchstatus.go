@@ -0,0 +1,168 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+// ModelSummaryWatchStatus records the connection state of the model
+// summary watchers and of the model summary distribution so that it can
+// be reported by the server's health checks. A nil
+// *ModelSummaryWatchStatus records nothing. A ModelSummaryWatchStatus
+// is safe to use from multiple goroutines.
+type ModelSummaryWatchStatus struct {
+	mu          sync.Mutex
+	distributed bool
+	listening   ModelSummaryConnectionStatus
+	controllers map[string]ModelSummaryConnectionStatus
+}
+
+// ModelSummaryConnectionStatus is the state of a single connection
+// reported by a ModelSummaryWatchStatus.
+type ModelSummaryConnectionStatus struct {
+	// Connected holds whether the connection is currently established.
+	Connected bool `json:"connected"`
+
+	// Since holds the time the connection entered its current state.
+	Since time.Time `json:"since"`
+
+	// Error holds the error that caused the connection to be lost, if
+	// any.
+	Error string `json:"error,omitempty"`
+
+	// Paused holds whether the connection has been deliberately closed
+	// because the controller is in maintenance. A paused connection is
+	// not reported as a failure.
+	Paused bool `json:"paused,omitempty"`
+}
+
+// modelSummaryWatchState is the value returned from
+// ModelSummaryWatchStatus.Check.
+type modelSummaryWatchState struct {
+	Distribution *ModelSummaryConnectionStatus           `json:"distribution,omitempty"`
+	Controllers  map[string]ModelSummaryConnectionStatus `json:"controllers"`
+}
+
+// Check reports the state of every watcher. An error is returned if any
+// of the controller watchers, or the model summary distribution, is not
+// connected. Watchers paused for controller maintenance are reported but
+// are not considered failures. Check can be used as a jimmhttp.StatusCheck function.
+func (s *ModelSummaryWatchStatus) Check(context.Context) (interface{}, error) {
+	if s == nil {
+		return nil, nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var state modelSummaryWatchState
+	var failed []string
+	if s.distributed {
+		listening := s.listening
+		state.Distribution = &listening
+		if !listening.Connected {
+			failed = append(failed, "distribution")
+		}
+	}
+	state.Controllers = make(map[string]ModelSummaryConnectionStatus, len(s.controllers))
+	for name, cs := range s.controllers {
+		state.Controllers[name] = cs
+		if !cs.Connected && !cs.Paused {
+			failed = append(failed, name)
+		}
+	}
+	if len(failed) > 0 {
+		sort.Strings(failed)
+		return state, errors.E("model summary watchers not connected: " + strings.Join(failed, ", "))
+	}
+	return state, nil
+}
+
+// setController records the connection state of the watcher for the
+// given controller.
+func (s *ModelSummaryWatchStatus) setController(name string, err error) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.controllers == nil {
+		s.controllers = make(map[string]ModelSummaryConnectionStatus)
+	}
+	s.controllers[name] = newModelSummaryConnectionStatus(err)
+}
+
+// pauseController records that the watcher for the given controller has
+// been paused because the controller is in maintenance.
+func (s *ModelSummaryWatchStatus) pauseController(name string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.controllers == nil {
+		s.controllers = make(map[string]ModelSummaryConnectionStatus)
+	}
+	if cs, ok := s.controllers[name]; ok && cs.Paused {
+		return
+	}
+	s.controllers[name] = ModelSummaryConnectionStatus{
+		Since:  time.Now().UTC(),
+		Paused: true,
+	}
+}
+
+// removeController stops reporting the state of the watcher for the
+// given controller.
+func (s *ModelSummaryWatchStatus) removeController(name string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.controllers, name)
+}
+
+// retainControllers stops reporting the state of the watchers for any
+// controller not in the given set.
+func (s *ModelSummaryWatchStatus) retainControllers(names map[string]bool) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for name := range s.controllers {
+		if !names[name] {
+			delete(s.controllers, name)
+		}
+	}
+}
+
+// setListening records the connection state of the model summary
+// distribution.
+func (s *ModelSummaryWatchStatus) setListening(err error) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.distributed = true
+	s.listening = newModelSummaryConnectionStatus(err)
+}
+
+func newModelSummaryConnectionStatus(err error) ModelSummaryConnectionStatus {
+	cs := ModelSummaryConnectionStatus{
+		Connected: err == nil,
+		Since:     time.Now().UTC(),
+	}
+	if err != nil {
+		cs.Error = err.Error()
+	}
+	return cs
+}
@@ -0,0 +1,78 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+)
+
+func TestModelSummaryWatchStatus(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	// A nil status records nothing and always passes.
+	var nilStatus *jimm.ModelSummaryWatchStatus
+	nilStatus.SetController("controller-1", nil)
+	v, err := nilStatus.Check(ctx)
+	c.Check(err, qt.IsNil)
+	c.Check(v, qt.IsNil)
+
+	var s jimm.ModelSummaryWatchStatus
+	_, err = s.Check(ctx)
+	c.Check(err, qt.IsNil)
+
+	s.SetController("controller-1", nil)
+	s.SetController("controller-2", errors.E("connection refused"))
+	s.SetListening(nil)
+	v, err = s.Check(ctx)
+	c.Check(err, qt.ErrorMatches, `model summary watchers not connected: controller-2`)
+
+	buf, err := json.Marshal(v)
+	c.Assert(err, qt.IsNil)
+	var state struct {
+		Distribution *struct {
+			Connected bool
+		}
+		Controllers map[string]struct {
+			Connected bool
+			Error     string
+		}
+	}
+	err = json.Unmarshal(buf, &state)
+	c.Assert(err, qt.IsNil)
+	c.Check(state.Distribution.Connected, qt.IsTrue)
+	c.Check(state.Controllers["controller-1"].Connected, qt.IsTrue)
+	c.Check(state.Controllers["controller-2"].Connected, qt.IsFalse)
+	c.Check(state.Controllers["controller-2"].Error, qt.Equals, "connection refused")
+
+	s.SetListening(errors.E("listener stopped"))
+	_, err = s.Check(ctx)
+	c.Check(err, qt.ErrorMatches, `model summary watchers not connected: controller-2, distribution`)
+
+	// A watcher paused for maintenance is not a failure.
+	s.SetListening(nil)
+	s.PauseController("controller-2")
+	v, err = s.Check(ctx)
+	c.Check(err, qt.IsNil)
+	buf, err = json.Marshal(v)
+	c.Assert(err, qt.IsNil)
+	c.Check(string(buf), qt.Matches, `.*"controller-2":\{"connected":false,"since":".*","paused":true\}.*`)
+
+	s.RetainControllers(map[string]bool{"controller-1": true})
+	_, err = s.Check(ctx)
+	c.Check(err, qt.IsNil)
+
+	s.RemoveController("controller-1")
+	v, err = s.Check(ctx)
+	c.Check(err, qt.IsNil)
+	buf, err = json.Marshal(v)
+	c.Assert(err, qt.IsNil)
+	c.Check(string(buf), qt.Matches, `\{"distribution":\{"connected":true,"since":".*"\},"controllers":\{\}\}`)
+}
@@ -0,0 +1,392 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/juju/zaputil/zapctx"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	"github.com/canonical/jimm/v3/internal/servermon"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const (
+	// WebhookSignatureHeader is the header holding the signature of a
+	// webhook event payload. The signature is the hex encoded
+	// HMAC-SHA256 of the payload, keyed with the subscription's secret,
+	// prefixed with "sha256=".
+	WebhookSignatureHeader = "X-JIMM-Signature"
+
+	// WebhookEventHeader is the header holding the type of a webhook
+	// event.
+	WebhookEventHeader = "X-JIMM-Event"
+
+	// WebhookDeliveryHeader is the header holding the ID of a webhook
+	// event.
+	WebhookDeliveryHeader = "X-JIMM-Delivery"
+
+	// maxWebhookAttempts is the number of times the delivery of an event
+	// is attempted before it is dead lettered.
+	maxWebhookAttempts = 8
+
+	// webhookDeliveryBatchSize is the maximum number of events delivered
+	// on each pass.
+	webhookDeliveryBatchSize = 100
+
+	// webhookDeliveryWorkers is the maximum number of subscriptions that
+	// events are delivered to at once.
+	webhookDeliveryWorkers = 8
+)
+
+// webhookClient is the HTTP client used to deliver webhook events.
+var webhookClient = &http.Client{
+	Timeout: 10 * time.Second,
+}
+
+// webhookRetryDelay returns the time to wait before the next attempt to
+// deliver an event that has failed the given number of times.
+func webhookRetryDelay(attempts int) time.Duration {
+	d := 30 * time.Second
+	for i := 1; i < attempts && d < time.Hour; i++ {
+		d *= 2
+	}
+	if d > time.Hour {
+		d = time.Hour
+	}
+	return d
+}
+
+// SignWebhookPayload returns the value of the WebhookSignatureHeader for
+// the given payload signed with the given secret.
+func SignWebhookPayload(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// AddWebhookSubscription subscribes a webhook to model lifecycle events.
+// If no secret is given one is generated; the secret is only ever
+// returned by this call. Only JIMM administrators may add webhook
+// subscriptions.
+func (j *JIMM) AddWebhookSubscription(ctx context.Context, user *openfga.User, req apiparams.AddWebhookSubscriptionRequest) (apiparams.WebhookSubscription, error) {
+	const op = errors.Op("jimm.AddWebhookSubscription")
+
+	if err := j.checkJimmAdmin(user); err != nil {
+		return apiparams.WebhookSubscription{}, errors.E(op, err)
+	}
+	if req.Name == "" {
+		return apiparams.WebhookSubscription{}, errors.E(op, errors.CodeBadRequest, "subscription name not specified")
+	}
+	u, err := url.Parse(req.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return apiparams.WebhookSubscription{}, errors.E(op, errors.CodeBadRequest, fmt.Sprintf("invalid webhook url %q", req.URL))
+	}
+	for _, t := range req.EventTypes {
+		if !isWebhookEventType(t) {
+			return apiparams.WebhookSubscription{}, errors.E(op, errors.CodeBadRequest, fmt.Sprintf("invalid event type %q", t))
+		}
+	}
+	if _, err := dbmodel.ParseLabelSelector(req.LabelSelector); err != nil {
+		return apiparams.WebhookSubscription{}, errors.E(op, errors.CodeBadRequest, err)
+	}
+	secret := req.Secret
+	if secret == "" {
+		buf := make([]byte, 32)
+		if _, err := rand.Read(buf); err != nil {
+			return apiparams.WebhookSubscription{}, errors.E(op, err)
+		}
+		secret = hex.EncodeToString(buf)
+	}
+
+	s := dbmodel.WebhookSubscription{
+		Name:          req.Name,
+		URL:           req.URL,
+		Secret:        secret,
+		EventTypes:    req.EventTypes,
+		LabelSelector: req.LabelSelector,
+		Controller:    req.Controller,
+		Owner:         req.Owner,
+	}
+	if err := j.Database.AddWebhookSubscription(ctx, &s); err != nil {
+		return apiparams.WebhookSubscription{}, errors.E(op, err)
+	}
+	ws := s.ToAPIWebhookSubscription()
+	ws.Secret = secret
+	return ws, nil
+}
+
+// ListWebhookSubscriptions returns all the webhook subscriptions. Only
+// JIMM administrators may list webhook subscriptions.
+func (j *JIMM) ListWebhookSubscriptions(ctx context.Context, user *openfga.User) ([]apiparams.WebhookSubscription, error) {
+	const op = errors.Op("jimm.ListWebhookSubscriptions")
+
+	if err := j.checkJimmAdmin(user); err != nil {
+		return nil, errors.E(op, err)
+	}
+	subscriptions, err := j.Database.ListWebhookSubscriptions(ctx)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	result := make([]apiparams.WebhookSubscription, len(subscriptions))
+	for i, s := range subscriptions {
+		result[i] = s.ToAPIWebhookSubscription()
+	}
+	return result, nil
+}
+
+// RemoveWebhookSubscription removes the named webhook subscription along
+// with any events waiting to be delivered to it. Only JIMM administrators
+// may remove webhook subscriptions.
+func (j *JIMM) RemoveWebhookSubscription(ctx context.Context, user *openfga.User, name string) error {
+	const op = errors.Op("jimm.RemoveWebhookSubscription")
+
+	if err := j.checkJimmAdmin(user); err != nil {
+		return errors.E(op, err)
+	}
+	if err := j.Database.DeleteWebhookSubscription(ctx, &dbmodel.WebhookSubscription{Name: name}); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// ListWebhookDeadLetters returns the events that could not be delivered,
+// optionally restricted to those of the named subscription. Only JIMM
+// administrators may list webhook dead letters.
+func (j *JIMM) ListWebhookDeadLetters(ctx context.Context, user *openfga.User, subscription string) ([]apiparams.WebhookDeadLetter, error) {
+	const op = errors.Op("jimm.ListWebhookDeadLetters")
+
+	if err := j.checkJimmAdmin(user); err != nil {
+		return nil, errors.E(op, err)
+	}
+	deadLetters, err := j.Database.ListWebhookDeadLetters(ctx, subscription)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	result := make([]apiparams.WebhookDeadLetter, len(deadLetters))
+	for i, dl := range deadLetters {
+		result[i] = dl.ToAPIWebhookDeadLetter()
+	}
+	return result, nil
+}
+
+// RetryWebhookDeadLetter queues the dead lettered event with the given ID
+// to be delivered again. Only JIMM administrators may retry webhook dead
+// letters.
+func (j *JIMM) RetryWebhookDeadLetter(ctx context.Context, user *openfga.User, id uint) error {
+	const op = errors.Op("jimm.RetryWebhookDeadLetter")
+
+	if err := j.checkJimmAdmin(user); err != nil {
+		return errors.E(op, err)
+	}
+	if err := j.Database.RequeueWebhookDeadLetter(ctx, &dbmodel.WebhookDeadLetter{ID: id}, time.Now()); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// RecordModelEvent queues an event of the given type about the given
+// model for delivery to every matching webhook subscription. The model's
+// controller must have been loaded. Failures are logged rather than
+// returned so that recording an event never fails the operation that
+// caused it.
+func (j *JIMM) RecordModelEvent(ctx context.Context, eventType string, m *dbmodel.Model, message string) {
+	subscriptions, err := j.Database.ListWebhookSubscriptions(ctx)
+	if err != nil {
+		zapctx.Error(ctx, "cannot list webhook subscriptions", zap.Error(err))
+		return
+	}
+	var matching []dbmodel.WebhookSubscription
+	for _, s := range subscriptions {
+		if s.Matches(eventType, m) {
+			matching = append(matching, s)
+		}
+	}
+	if len(matching) == 0 {
+		return
+	}
+
+	now := time.Now().UTC()
+	event := apiparams.WebhookEvent{
+		ID:   uuid.NewString(),
+		Type: eventType,
+		Time: now,
+		Model: apiparams.WebhookEventModel{
+			UUID:       m.UUID.String,
+			Name:       m.Name,
+			Owner:      m.OwnerIdentityName,
+			Controller: m.Controller.Name,
+			Labels:     m.Labels,
+		},
+		Message: message,
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		zapctx.Error(ctx, "cannot marshal webhook event", zap.Error(err))
+		return
+	}
+	deliveries := make([]dbmodel.WebhookDelivery, len(matching))
+	for i, s := range matching {
+		deliveries[i] = dbmodel.WebhookDelivery{
+			WebhookSubscriptionID: s.ID,
+			EventID:               event.ID,
+			EventType:             eventType,
+			Payload:               payload,
+			NextAttemptAt:         now,
+		}
+	}
+	if err := checkLeader(ctx); err != nil {
+		zapctx.Error(ctx, "cannot queue webhook event", zap.String("event", eventType), zap.String("model", m.UUID.String), zap.Error(err))
+		return
+	}
+	if err := j.Database.AddWebhookDeliveries(ctx, deliveries); err != nil {
+		zapctx.Error(ctx, "cannot queue webhook event", zap.String("event", eventType), zap.String("model", m.UUID.String), zap.Error(err))
+	}
+}
+
+// RunWebhookDeliveries delivers queued webhook events every interval
+// until the given context is cancelled. Events are delivered at least
+// once: an event is only removed from the queue once its webhook has
+// accepted it, failed deliveries are retried with an increasing delay
+// and events that still cannot be delivered are dead lettered.
+func (j *JIMM) RunWebhookDeliveries(ctx context.Context, interval time.Duration) error {
+	const op = errors.Op("jimm.RunWebhookDeliveries")
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := j.DeliverWebhookEvents(ctx); err != nil {
+			// Ignore temporary database errors, and leadership
+			// being lost, which stops the deliveries shortly.
+			if code := errors.ErrorCode(err); code != errors.CodeDatabaseLocked && code != errors.CodeUnauthorized {
+				return errors.E(op, err)
+			}
+			zapctx.Warn(ctx, "temporary error delivering webhook events", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// DeliverWebhookEvents attempts to deliver every queued webhook event
+// that is due. Events are delivered to each subscription in the order
+// they were queued, but subscriptions are delivered to concurrently so
+// that a slow webhook does not hold up the others.
+func (j *JIMM) DeliverWebhookEvents(ctx context.Context) error {
+	const op = errors.Op("jimm.DeliverWebhookEvents")
+
+	for {
+		deliveries, err := j.Database.ListDueWebhookDeliveries(ctx, time.Now(), webhookDeliveryBatchSize)
+		if err != nil {
+			return errors.E(op, err)
+		}
+		var subscriptions [][]*dbmodel.WebhookDelivery
+		index := make(map[uint]int)
+		for i := range deliveries {
+			wd := &deliveries[i]
+			n, ok := index[wd.WebhookSubscriptionID]
+			if !ok {
+				n = len(subscriptions)
+				index[wd.WebhookSubscriptionID] = n
+				subscriptions = append(subscriptions, nil)
+			}
+			subscriptions[n] = append(subscriptions[n], wd)
+		}
+		eg := new(errgroup.Group)
+		eg.SetLimit(webhookDeliveryWorkers)
+		for _, sd := range subscriptions {
+			sd := sd
+			eg.Go(func() error {
+				for _, wd := range sd {
+					if err := checkLeader(ctx); err != nil {
+						return err
+					}
+					if err := j.deliverWebhookEvent(ctx, wd); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+		}
+		if err := eg.Wait(); err != nil {
+			return errors.E(op, err)
+		}
+		if len(deliveries) < webhookDeliveryBatchSize {
+			return nil
+		}
+	}
+}
+
+// deliverWebhookEvent posts the given event to its webhook and updates
+// the queue with the result.
+func (j *JIMM) deliverWebhookEvent(ctx context.Context, wd *dbmodel.WebhookDelivery) error {
+	err := postWebhookEvent(ctx, wd)
+	if err == nil {
+		return j.Database.DeleteWebhookDelivery(ctx, wd)
+	}
+	zapctx.Warn(ctx, "webhook delivery failed",
+		zap.String("subscription", wd.WebhookSubscription.Name),
+		zap.String("event", wd.EventID),
+		zap.Error(err),
+	)
+	wd.Attempts++
+	wd.LastError = err.Error()
+	if wd.Attempts >= maxWebhookAttempts {
+		return j.Database.DeadLetterWebhookDelivery(ctx, wd)
+	}
+	wd.NextAttemptAt = time.Now().Add(webhookRetryDelay(wd.Attempts))
+	return j.Database.UpdateWebhookDelivery(ctx, wd)
+}
+
+// postWebhookEvent posts the payload of the given delivery to its
+// webhook. Any response other than a 2xx status is an error.
+func postWebhookEvent(ctx context.Context, wd *dbmodel.WebhookDelivery) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, wd.WebhookSubscription.URL, bytes.NewReader(wd.Payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventHeader, wd.EventType)
+	req.Header.Set(WebhookDeliveryHeader, wd.EventID)
+	req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(wd.WebhookSubscription.Secret, wd.Payload))
+	servermon.InjectTraceContext(ctx, req.Header)
+	resp, err := webhookClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected response status %q", resp.Status)
+	}
+	return nil
+}
+
+func isWebhookEventType(t string) bool {
+	for _, et := range dbmodel.WebhookEventTypes {
+		if et == t {
+			return true
+		}
+	}
+	return false
+}
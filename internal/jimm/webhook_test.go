@@ -0,0 +1,278 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	"github.com/canonical/jimm/v3/internal/testutils/jimmtest"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const webhookTestEnv = `clouds:
+- name: test-cloud
+  type: test-provider
+  regions:
+  - name: test-region-1
+cloud-credentials:
+- name: test-credential-1
+  owner: alice@canonical.com
+  cloud: test-cloud
+  auth-type: empty
+controllers:
+- name: controller-1
+  uuid: 00000000-0000-0000-0000-0000-0000000000001
+  cloud: test-cloud
+  region: test-region-1
+models:
+- name: model-1
+  owner: alice@canonical.com
+  uuid: 00000001-0000-0000-0000-0000-000000000001
+  cloud: test-cloud
+  region: test-region-1
+  cloud-credential: test-credential-1
+  controller: controller-1
+  life: alive
+users:
+- username: alice@canonical.com
+  controller-access: login
+`
+
+// webhookReceiver records the events posted to it, failing requests
+// while fail is set.
+type webhookReceiver struct {
+	mu     sync.Mutex
+	fail   bool
+	events []apiparams.WebhookEvent
+	errors []string
+}
+
+func (r *webhookReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.fail {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		r.errors = append(r.errors, err.Error())
+		return
+	}
+	if sig := req.Header.Get(jimm.WebhookSignatureHeader); sig != jimm.SignWebhookPayload("secret", body) {
+		r.errors = append(r.errors, "bad signature "+sig)
+	}
+	var event apiparams.WebhookEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		r.errors = append(r.errors, err.Error())
+	}
+	if req.Header.Get(jimm.WebhookEventHeader) != event.Type || req.Header.Get(jimm.WebhookDeliveryHeader) != event.ID {
+		r.errors = append(r.errors, "bad headers")
+	}
+	r.events = append(r.events, event)
+}
+
+func (r *webhookReceiver) setFail(fail bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.fail = fail
+}
+
+func (r *webhookReceiver) received() ([]apiparams.WebhookEvent, []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]apiparams.WebhookEvent(nil), r.events...), append([]string(nil), r.errors...)
+}
+
+func TestWebhooks(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	receiver := new(webhookReceiver)
+	srv := httptest.NewServer(receiver)
+	defer srv.Close()
+
+	j := jimmtest.NewJIMM(c, nil)
+
+	env := jimmtest.ParseEnvironment(c, webhookTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, j.OpenFGAClient)
+	aliceIdentity := env.User("alice@canonical.com").DBObject(c, j.Database)
+	alice := openfga.NewUser(&aliceIdentity, j.OpenFGAClient)
+	admin := openfga.NewUser(&aliceIdentity, j.OpenFGAClient)
+	admin.JimmAdmin = true
+
+	req := apiparams.AddWebhookSubscriptionRequest{
+		Name:       "chat-ops",
+		URL:        srv.URL,
+		Secret:     "secret",
+		EventTypes: []string{dbmodel.WebhookEventModelCreated, dbmodel.WebhookEventModelError},
+	}
+	_, err := j.AddWebhookSubscription(ctx, alice, req)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+
+	_, err = j.AddWebhookSubscription(ctx, admin, apiparams.AddWebhookSubscriptionRequest{Name: "bad", URL: "ftp://example.com"})
+	c.Check(err, qt.ErrorMatches, `invalid webhook url "ftp://example.com"`)
+	_, err = j.AddWebhookSubscription(ctx, admin, apiparams.AddWebhookSubscriptionRequest{Name: "bad", URL: srv.URL, EventTypes: []string{"model-exploded"}})
+	c.Check(err, qt.ErrorMatches, `invalid event type "model-exploded"`)
+
+	s, err := j.AddWebhookSubscription(ctx, admin, req)
+	c.Assert(err, qt.IsNil)
+	c.Check(s.Secret, qt.Equals, "secret")
+
+	// A subscription with a generated secret, for other controllers, is
+	// never sent any events.
+	s, err = j.AddWebhookSubscription(ctx, admin, apiparams.AddWebhookSubscriptionRequest{Name: "other", URL: srv.URL, Controller: "controller-2"})
+	c.Assert(err, qt.IsNil)
+	c.Check(s.Secret, qt.HasLen, 64)
+
+	subscriptions, err := j.ListWebhookSubscriptions(ctx, admin)
+	c.Assert(err, qt.IsNil)
+	c.Assert(subscriptions, qt.HasLen, 2)
+	c.Check(subscriptions[0].Name, qt.Equals, "chat-ops")
+	c.Check(subscriptions[0].Secret, qt.Equals, "")
+
+	m := env.Model("alice@canonical.com", "model-1").DBObject(c, j.Database)
+	j.RecordModelEvent(ctx, dbmodel.WebhookEventModelCreated, &m, "")
+	j.RecordModelEvent(ctx, dbmodel.WebhookEventModelDestroyed, &m, "")
+
+	err = j.DeliverWebhookEvents(ctx)
+	c.Assert(err, qt.IsNil)
+	events, errs := receiver.received()
+	c.Check(errs, qt.HasLen, 0)
+	c.Assert(events, qt.HasLen, 1)
+	c.Check(events[0].Type, qt.Equals, dbmodel.WebhookEventModelCreated)
+	c.Check(events[0].Model, qt.DeepEquals, apiparams.WebhookEventModel{
+		UUID:       "00000001-0000-0000-0000-0000-000000000001",
+		Name:       "model-1",
+		Owner:      "alice@canonical.com",
+		Controller: "controller-1",
+	})
+
+	// Failed deliveries are retried later.
+	receiver.setFail(true)
+	j.RecordModelEvent(ctx, dbmodel.WebhookEventModelError, &m, "unit-0: hook failed")
+	err = j.DeliverWebhookEvents(ctx)
+	c.Assert(err, qt.IsNil)
+	deliveries, err := j.Database.ListDueWebhookDeliveries(ctx, time.Now().Add(time.Hour), 10)
+	c.Assert(err, qt.IsNil)
+	c.Assert(deliveries, qt.HasLen, 1)
+	c.Check(deliveries[0].Attempts, qt.Equals, 1)
+	c.Check(deliveries[0].LastError, qt.Equals, `unexpected response status "503 Service Unavailable"`)
+	c.Check(deliveries[0].NextAttemptAt.After(time.Now()), qt.IsTrue)
+
+	// Once the retries are exhausted the event is dead lettered.
+	deliveries[0].Attempts = 7
+	deliveries[0].NextAttemptAt = time.Now()
+	err = j.Database.UpdateWebhookDelivery(ctx, &deliveries[0])
+	c.Assert(err, qt.IsNil)
+	err = j.DeliverWebhookEvents(ctx)
+	c.Assert(err, qt.IsNil)
+	deliveries, err = j.Database.ListDueWebhookDeliveries(ctx, time.Now().Add(time.Hour), 10)
+	c.Assert(err, qt.IsNil)
+	c.Check(deliveries, qt.HasLen, 0)
+
+	_, err = j.ListWebhookDeadLetters(ctx, alice, "")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+	deadLetters, err := j.ListWebhookDeadLetters(ctx, admin, "chat-ops")
+	c.Assert(err, qt.IsNil)
+	c.Assert(deadLetters, qt.HasLen, 1)
+	c.Check(deadLetters[0].Subscription, qt.Equals, "chat-ops")
+	c.Check(deadLetters[0].Attempts, qt.Equals, 8)
+	c.Check(deadLetters[0].Event.Type, qt.Equals, dbmodel.WebhookEventModelError)
+	c.Check(deadLetters[0].Event.Message, qt.Equals, "unit-0: hook failed")
+
+	// Retrying a dead letter delivers it again.
+	receiver.setFail(false)
+	err = j.RetryWebhookDeadLetter(ctx, admin, deadLetters[0].ID)
+	c.Assert(err, qt.IsNil)
+	err = j.DeliverWebhookEvents(ctx)
+	c.Assert(err, qt.IsNil)
+	events, errs = receiver.received()
+	c.Check(errs, qt.HasLen, 0)
+	c.Assert(events, qt.HasLen, 2)
+	c.Check(events[1].ID, qt.Equals, deadLetters[0].Event.ID)
+
+	err = j.RetryWebhookDeadLetter(ctx, admin, deadLetters[0].ID)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	err = j.RemoveWebhookSubscription(ctx, alice, "chat-ops")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+	err = j.RemoveWebhookSubscription(ctx, admin, "chat-ops")
+	c.Assert(err, qt.IsNil)
+	err = j.RemoveWebhookSubscription(ctx, admin, "chat-ops")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+}
+
+func TestWebhookDeliverySlowSubscription(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	// The slow webhook does not respond until it is released.
+	release := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		<-release
+	}))
+	defer slow.Close()
+	defer close(release)
+	receiver := new(webhookReceiver)
+	srv := httptest.NewServer(receiver)
+	defer srv.Close()
+
+	j := jimmtest.NewJIMM(c, nil)
+
+	env := jimmtest.ParseEnvironment(c, webhookTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, j.OpenFGAClient)
+	aliceIdentity := env.User("alice@canonical.com").DBObject(c, j.Database)
+	admin := openfga.NewUser(&aliceIdentity, j.OpenFGAClient)
+	admin.JimmAdmin = true
+
+	_, err := j.AddWebhookSubscription(ctx, admin, apiparams.AddWebhookSubscriptionRequest{Name: "slow", URL: slow.URL, Secret: "secret"})
+	c.Assert(err, qt.IsNil)
+	_, err = j.AddWebhookSubscription(ctx, admin, apiparams.AddWebhookSubscriptionRequest{Name: "chat-ops", URL: srv.URL, Secret: "secret"})
+	c.Assert(err, qt.IsNil)
+
+	m := env.Model("alice@canonical.com", "model-1").DBObject(c, j.Database)
+	j.RecordModelEvent(ctx, dbmodel.WebhookEventModelCreated, &m, "")
+
+	done := make(chan error, 1)
+	go func() {
+		done <- j.DeliverWebhookEvents(ctx)
+	}()
+
+	// The event is delivered to the other webhook while the slow one
+	// is still waiting.
+	deadline := time.After(5 * time.Second)
+	for {
+		events, errs := receiver.received()
+		c.Assert(errs, qt.HasLen, 0)
+		if len(events) == 1 {
+			break
+		}
+		select {
+		case <-deadline:
+			c.Fatal("event not delivered")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	select {
+	case <-done:
+		c.Fatal("delivery did not wait for the slow webhook")
+	default:
+	}
+
+	release <- struct{}{}
+	c.Assert(<-done, qt.IsNil)
+}
@@ -49,6 +49,8 @@ func (dh *DebugHandler) Info(w http.ResponseWriter, r *http.Request) {
 }
 
 // Status handles /status, returning the currently registered status checks.
+// Any check that takes longer than DefaultHealthCheckTimeout is reported
+// as failed.
 func (dh *DebugHandler) Status(w http.ResponseWriter, r *http.Request) {
 	checks := dh.StatusChecks
 	var mu sync.Mutex
@@ -62,9 +64,8 @@ func (dh *DebugHandler) Status(w http.ResponseWriter, r *http.Request) {
 			result := statusResult{
 				Name: check.Name(),
 			}
-			start := time.Now()
-			v, err := check.Check(r.Context())
-			result.Duration = time.Since(start)
+			v, d, err := runStatusCheck(r.Context(), check, DefaultHealthCheckTimeout)
+			result.Duration = d
 			if err == nil {
 				result.Passed = true
 				result.Value = v
@@ -0,0 +1,180 @@
+// Copyright 2024 Canonical.
+package jimmhttp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-chi/render"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+// DefaultHealthCheckTimeout is the time a check is allowed to run if it
+// doesn't specify its own timeout.
+const DefaultHealthCheckTimeout = 5 * time.Second
+
+// Health states reported by the readiness endpoint.
+const (
+	// HealthStarting is reported until the server has finished starting.
+	HealthStarting = "starting"
+
+	// HealthReady is reported when every check passes.
+	HealthReady = "ready"
+
+	// HealthDegraded is reported when only non-critical checks fail.
+	// A degraded server is still ready to serve requests.
+	HealthDegraded = "degraded"
+
+	// HealthUnavailable is reported when any critical check fails.
+	HealthUnavailable = "unavailable"
+)
+
+// A HealthCheck is a StatusCheck run as part of the readiness endpoint.
+type HealthCheck struct {
+	StatusCheck
+
+	// Critical determines whether the server is unable to serve
+	// requests when the check fails. A failing non-critical check
+	// only marks the server as degraded.
+	Critical bool
+
+	// Timeout is the time the check is allowed to run before it is
+	// considered failed. If this is zero DefaultHealthCheckTimeout is
+	// used.
+	Timeout time.Duration
+}
+
+// A HealthHandler serves the liveness and readiness endpoints used by
+// orchestrators, such as kubernetes, to probe the server.
+type HealthHandler struct {
+	// Checks contains the checks that determine whether the server is
+	// ready.
+	Checks map[string]HealthCheck
+
+	started atomic.Bool
+}
+
+// NewHealthHandler returns a new HealthHandler that runs the given
+// checks.
+func NewHealthHandler(checks map[string]HealthCheck) *HealthHandler {
+	return &HealthHandler{Checks: checks}
+}
+
+// SetStarted marks the server as having finished starting. Until this is
+// called the readiness endpoint reports HealthStarting.
+func (h *HealthHandler) SetStarted() {
+	h.started.Store(true)
+}
+
+// Healthz handles /healthz, the liveness endpoint. It always succeeds
+// whilst the server is able to handle requests.
+func (h *HealthHandler) Healthz(w http.ResponseWriter, r *http.Request) {
+	render.JSON(w, r, map[string]string{"status": "ok"})
+}
+
+// Readyz handles /readyz, the readiness endpoint. All checks are run
+// concurrently and the overall state is reported along with the result
+// of each check. If the server is starting, or any critical check fails,
+// the response has a status of 503 Service Unavailable.
+func (h *HealthHandler) Readyz(w http.ResponseWriter, r *http.Request) {
+	var mu sync.Mutex
+	results := make(map[string]healthResult, len(h.Checks))
+	var wg sync.WaitGroup
+	wg.Add(len(h.Checks))
+	for k, check := range h.Checks {
+		k, check := k, check
+		go func() {
+			defer wg.Done()
+			result := healthResult{
+				Name:     check.Name(),
+				Critical: check.Critical,
+			}
+			v, d, err := runStatusCheck(r.Context(), check, check.Timeout)
+			result.Duration = d
+			result.Value = v
+			if err == nil {
+				result.Passed = true
+			} else {
+				result.Error = err.Error()
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			results[k] = result
+		}()
+	}
+	wg.Wait()
+
+	resp := healthResponse{
+		Status: HealthReady,
+		Checks: results,
+	}
+	for _, result := range results {
+		if result.Passed {
+			continue
+		}
+		if result.Critical {
+			resp.Status = HealthUnavailable
+			break
+		}
+		resp.Status = HealthDegraded
+	}
+	if !h.started.Load() {
+		resp.Status = HealthStarting
+	}
+	if resp.Status == HealthStarting || resp.Status == HealthUnavailable {
+		render.Status(r, http.StatusServiceUnavailable)
+	}
+	render.JSON(w, r, resp)
+}
+
+// A healthResponse is the response body of the readiness endpoint.
+type healthResponse struct {
+	Status string                  `json:"status"`
+	Checks map[string]healthResult `json:"checks"`
+}
+
+// A healthResult is the result of a single check in the readiness
+// endpoint response body.
+type healthResult struct {
+	Name     string        `json:"name"`
+	Critical bool          `json:"critical"`
+	Passed   bool          `json:"passed"`
+	Value    interface{}   `json:"value,omitempty"`
+	Error    string        `json:"error,omitempty"`
+	Duration time.Duration `json:"duration"`
+}
+
+// runStatusCheck runs the given check, giving up after the given timeout.
+// It returns the value returned by the check and the time the check took.
+func runStatusCheck(ctx context.Context, check StatusCheck, timeout time.Duration) (interface{}, time.Duration, error) {
+	if timeout <= 0 {
+		timeout = DefaultHealthCheckTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	type result struct {
+		v   interface{}
+		err error
+	}
+	ch := make(chan result, 1)
+	start := time.Now()
+	go func() {
+		v, err := check.Check(ctx)
+		ch <- result{v, err}
+	}()
+	select {
+	case res := <-ch:
+		if res.err == nil || ctx.Err() == nil {
+			return res.v, time.Since(start), res.err
+		}
+		// The check failed because it ran out of time.
+	case <-ctx.Done():
+	}
+	return nil, time.Since(start), errors.E(fmt.Sprintf("check did not complete within %s", timeout))
+}
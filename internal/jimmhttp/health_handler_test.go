@@ -0,0 +1,162 @@
+// Copyright 2024 Canonical.
+package jimmhttp_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimmhttp"
+)
+
+type readyzResponse struct {
+	Status string `json:"status"`
+	Checks map[string]struct {
+		Name     string        `json:"name"`
+		Critical bool          `json:"critical"`
+		Passed   bool          `json:"passed"`
+		Value    interface{}   `json:"value"`
+		Error    string        `json:"error"`
+		Duration time.Duration `json:"duration"`
+	} `json:"checks"`
+}
+
+func passingCheck(name string) jimmhttp.StatusCheck {
+	return jimmhttp.MakeStatusCheck(name, func(context.Context) (interface{}, error) {
+		return "ok", nil
+	})
+}
+
+func failingCheck(name string) jimmhttp.StatusCheck {
+	return jimmhttp.MakeStatusCheck(name, func(context.Context) (interface{}, error) {
+		return nil, errors.E("test error")
+	})
+}
+
+func blockingCheck(name string) jimmhttp.StatusCheck {
+	return jimmhttp.MakeStatusCheck(name, func(ctx context.Context) (interface{}, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+}
+
+func TestHealthz(t *testing.T) {
+	c := qt.New(t)
+
+	h := jimmhttp.NewHealthHandler(map[string]jimmhttp.HealthCheck{
+		"db": {StatusCheck: failingCheck("database"), Critical: true},
+	})
+	rr := httptest.NewRecorder()
+	req, err := http.NewRequest("GET", "/healthz", nil)
+	c.Assert(err, qt.IsNil)
+	h.Healthz(rr, req)
+	c.Check(rr.Code, qt.Equals, http.StatusOK)
+	c.Check(rr.Body.String(), qt.JSONEquals, map[string]string{"status": "ok"})
+}
+
+var readyzTests = []struct {
+	name         string
+	checks       map[string]jimmhttp.HealthCheck
+	notStarted   bool
+	expectCode   int
+	expectStatus string
+}{{
+	name: "all checks pass",
+	checks: map[string]jimmhttp.HealthCheck{
+		"db":       {StatusCheck: passingCheck("database"), Critical: true},
+		"watchers": {StatusCheck: passingCheck("watchers")},
+	},
+	expectCode:   http.StatusOK,
+	expectStatus: jimmhttp.HealthReady,
+}, {
+	name: "starting",
+	checks: map[string]jimmhttp.HealthCheck{
+		"db": {StatusCheck: passingCheck("database"), Critical: true},
+	},
+	notStarted:   true,
+	expectCode:   http.StatusServiceUnavailable,
+	expectStatus: jimmhttp.HealthStarting,
+}, {
+	name: "non-critical check fails",
+	checks: map[string]jimmhttp.HealthCheck{
+		"db":       {StatusCheck: passingCheck("database"), Critical: true},
+		"watchers": {StatusCheck: failingCheck("watchers")},
+	},
+	expectCode:   http.StatusOK,
+	expectStatus: jimmhttp.HealthDegraded,
+}, {
+	name: "critical check fails",
+	checks: map[string]jimmhttp.HealthCheck{
+		"db":       {StatusCheck: failingCheck("database"), Critical: true},
+		"watchers": {StatusCheck: failingCheck("watchers")},
+	},
+	expectCode:   http.StatusServiceUnavailable,
+	expectStatus: jimmhttp.HealthUnavailable,
+}, {
+	name: "critical check times out",
+	checks: map[string]jimmhttp.HealthCheck{
+		"db": {StatusCheck: blockingCheck("database"), Critical: true, Timeout: 10 * time.Millisecond},
+	},
+	expectCode:   http.StatusServiceUnavailable,
+	expectStatus: jimmhttp.HealthUnavailable,
+}}
+
+func TestReadyz(t *testing.T) {
+	c := qt.New(t)
+
+	for _, test := range readyzTests {
+		c.Run(test.name, func(c *qt.C) {
+			h := jimmhttp.NewHealthHandler(test.checks)
+			if !test.notStarted {
+				h.SetStarted()
+			}
+			rr := httptest.NewRecorder()
+			req, err := http.NewRequest("GET", "/readyz", nil)
+			c.Assert(err, qt.IsNil)
+			h.Readyz(rr, req)
+			c.Check(rr.Code, qt.Equals, test.expectCode)
+
+			var resp readyzResponse
+			err = json.Unmarshal(rr.Body.Bytes(), &resp)
+			c.Assert(err, qt.IsNil)
+			c.Check(resp.Status, qt.Equals, test.expectStatus)
+			c.Check(resp.Checks, qt.HasLen, len(test.checks))
+			for k, check := range test.checks {
+				c.Check(resp.Checks[k].Name, qt.Equals, check.Name())
+				c.Check(resp.Checks[k].Critical, qt.Equals, check.Critical)
+			}
+		})
+	}
+}
+
+func TestReadyzCheckResults(t *testing.T) {
+	c := qt.New(t)
+
+	h := jimmhttp.NewHealthHandler(map[string]jimmhttp.HealthCheck{
+		"db":       {StatusCheck: passingCheck("database"), Critical: true},
+		"watchers": {StatusCheck: failingCheck("watchers")},
+		"openfga":  {StatusCheck: blockingCheck("openfga"), Timeout: 10 * time.Millisecond},
+	})
+	h.SetStarted()
+	rr := httptest.NewRecorder()
+	req, err := http.NewRequest("GET", "/readyz", nil)
+	c.Assert(err, qt.IsNil)
+	h.Readyz(rr, req)
+
+	var resp readyzResponse
+	err = json.Unmarshal(rr.Body.Bytes(), &resp)
+	c.Assert(err, qt.IsNil)
+	c.Check(resp.Checks["db"].Passed, qt.IsTrue)
+	c.Check(resp.Checks["db"].Value, qt.Equals, "ok")
+	c.Check(resp.Checks["watchers"].Passed, qt.IsFalse)
+	c.Check(resp.Checks["watchers"].Error, qt.Equals, "test error")
+	c.Check(resp.Checks["openfga"].Passed, qt.IsFalse)
+	c.Check(resp.Checks["openfga"].Error, qt.Equals, "check did not complete within 10ms")
+	c.Check(resp.Checks["openfga"].Duration >= 10*time.Millisecond, qt.IsTrue)
+}
@@ -4,8 +4,10 @@ package rebac_admin
 
 import (
 	"context"
+	"net/http"
 
 	rebac_handlers "github.com/canonical/rebac-admin-ui-handlers/v1"
+	"github.com/canonical/rebac-admin-ui-handlers/v1/resources"
 	"github.com/juju/zaputil/zapctx"
 	"go.uber.org/zap"
 
@@ -17,13 +19,17 @@ func SetupBackend(ctx context.Context, jimm jujuapi.JIMM) (*rebac_handlers.ReBAC
 	const op = errors.Op("rebac_admin.SetupBackend")
 
 	rebacBackend, err := rebac_handlers.NewReBACAdminBackend(rebac_handlers.ReBACAdminBackendParams{
-		Authenticator: nil, // Authentication is handled by internal middleware.
-		Entitlements:  newEntitlementService(),
-		Groups:        newGroupService(jimm),
-		Identities:    newidentitiesService(jimm),
-		Resources:     newResourcesService(jimm),
-		Capabilities:  newCapabilitiesService(),
-		Roles:         newRoleService(jimm),
+		Authenticator:         nil, // Authentication is handled by internal middleware.
+		Entitlements:          newEntitlementService(),
+		Groups:                newGroupService(jimm),
+		GroupsErrorMapper:     errorMapper{},
+		Identities:            newidentitiesService(jimm),
+		IdentitiesErrorMapper: errorMapper{},
+		Resources:             newResourcesService(jimm),
+		ResourcesErrorMapper:  errorMapper{},
+		Capabilities:          newCapabilitiesService(),
+		Roles:                 newRoleService(jimm),
+		RolesErrorMapper:      errorMapper{},
 	})
 	if err != nil {
 		zapctx.Error(ctx, "failed to create rebac admin backend", zap.Error(err))
@@ -32,3 +38,20 @@ func SetupBackend(ctx context.Context, jimm jujuapi.JIMM) (*rebac_handlers.ReBAC
 
 	return rebacBackend, nil
 }
+
+// errorMapper maps the errors returned by JIMM to ReBAC Admin API
+// responses. Any authenticated user may call the API, so that group
+// managers can manage their groups, and JIMM refuses everything else
+// with errors.CodeUnauthorized.
+type errorMapper struct{}
+
+// MapError implements rebac_handlers.ErrorResponseMapper.
+func (errorMapper) MapError(err error) *resources.Response {
+	if errors.ErrorCode(err) != errors.CodeUnauthorized {
+		return nil
+	}
+	return &resources.Response{
+		Message: err.Error(),
+		Status:  http.StatusUnauthorized,
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/juju/names/v5"
 	gc "gopkg.in/check.v1"
 
+	"github.com/canonical/jimm/v3/internal/dbmodel"
 	"github.com/canonical/jimm/v3/internal/jimmhttp/rebac_admin"
 	"github.com/canonical/jimm/v3/internal/openfga"
 	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
@@ -151,6 +152,42 @@ func (s rebacAdminSuite) TestPatchGroupIdentitiesIntegration(c *gc.C) {
 	c.Assert(allowed, gc.Equals, true)
 }
 
+func (s rebacAdminSuite) TestPatchGroupIdentitiesAsManagerIntegration(c *gc.C) {
+	ctx := context.Background()
+	group := s.AddGroup(c, "test-group")
+	otherGroup := s.AddGroup(c, "other-group")
+
+	manager := s.NewUser(&dbmodel.Identity{Name: "manager@canonical.com"})
+	err := s.JIMM.OpenFGAClient.AddRelation(ctx, openfga.Tuple{
+		Object:   ofganames.ConvertTag(manager.ResourceTag()),
+		Relation: ofganames.ManagerRelation,
+		Target:   ofganames.ConvertTag(group.ResourceTag()),
+	})
+	c.Assert(err, gc.IsNil)
+
+	ctx = rebac_handlers.ContextWithIdentity(ctx, manager)
+	res, err := s.groupSvc.PatchGroupIdentities(ctx, group.UUID, []resources.GroupIdentitiesPatchItem{
+		{Identity: "foo@canonical.com", Op: resources.GroupIdentitiesPatchItemOpAdd},
+	})
+	c.Assert(err, gc.IsNil)
+	c.Assert(res, gc.Equals, true)
+
+	allowed, err := s.JIMM.OpenFGAClient.CheckRelation(ctx, openfga.Tuple{
+		Object:   ofganames.ConvertTag(names.NewUserTag("foo@canonical.com")),
+		Relation: ofganames.MemberRelation,
+		Target:   ofganames.ConvertTag(group.ResourceTag()),
+	}, false)
+	c.Assert(err, gc.IsNil)
+	c.Assert(allowed, gc.Equals, true)
+
+	// The manager cannot change the membership of a group they don't
+	// manage.
+	_, err = s.groupSvc.PatchGroupIdentities(ctx, otherGroup.UUID, []resources.GroupIdentitiesPatchItem{
+		{Identity: "foo@canonical.com", Op: resources.GroupIdentitiesPatchItemOpAdd},
+	})
+	c.Assert(err, gc.ErrorMatches, "unauthorized")
+}
+
 func (s rebacAdminSuite) TestGetGroupRolesIntegration(c *gc.C) {
 	ctx := context.Background()
 	group := s.AddGroup(c, "test-group")
@@ -77,6 +77,13 @@ func (s *identitiesService) CreateIdentity(ctx context.Context, identity *resour
 
 // GetIdentity returns a single Identity.
 func (s *identitiesService) GetIdentity(ctx context.Context, identityId string) (*resources.Identity, error) {
+	authUser, err := utils.GetUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !authUser.JimmAdmin {
+		return nil, v1.NewAuthorizationError("user is not an admin")
+	}
 	user, err := s.jimm.FetchIdentity(ctx, identityId)
 	if err != nil {
 		if errors.ErrorCode(err) == errors.CodeNotFound {
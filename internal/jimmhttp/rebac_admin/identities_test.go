@@ -50,6 +50,11 @@ func TestGetIdentity(t *testing.T) {
 	// test with user not found
 	_, err = identitySvc.GetIdentity(ctx, "bob-not-found@canonical.com")
 	c.Assert(err, qt.ErrorMatches, "Not Found: User with id bob-not-found@canonical.com not found")
+
+	// test with a user that is not an admin
+	ctx = rebac_handlers.ContextWithIdentity(context.Background(), &openfga.User{})
+	_, err = identitySvc.GetIdentity(ctx, "bob@canonical.com")
+	c.Assert(err, qt.ErrorMatches, "Unauthorized: authorization failed: user is not an admin")
 }
 
 func TestListIdentities(t *testing.T) {
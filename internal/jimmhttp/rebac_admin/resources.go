@@ -4,17 +4,23 @@ package rebac_admin
 
 import (
 	"context"
+	"strings"
 
 	v1 "github.com/canonical/rebac-admin-ui-handlers/v1"
 	"github.com/canonical/rebac-admin-ui-handlers/v1/resources"
 
 	"github.com/canonical/jimm/v3/internal/common/pagination"
 	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
 	"github.com/canonical/jimm/v3/internal/errors"
 	"github.com/canonical/jimm/v3/internal/jimmhttp/rebac_admin/utils"
 	"github.com/canonical/jimm/v3/internal/jujuapi"
 )
 
+// labelSelectorPrefix is the prefix of a name filter that holds a model
+// label selector rather than a name prefix. See doc/rebac-admin.md.
+const labelSelectorPrefix = "label:"
+
 type resourcesService struct {
 	jimm jujuapi.JIMM
 }
@@ -33,6 +39,22 @@ func (s *resourcesService) ListResources(ctx context.Context, params *resources.
 	}
 	currentPage, expectedPageSize, pagination := pagination.CreatePaginationWithoutTotal(params.Size, params.Page)
 	namePrefixFilter, typeFilter := utils.GetNameAndTypeResourceFilter(params.EntityName, params.EntityType)
+	// The name filter may instead hold a label selector, as in
+	// "label:env=prod,team=ops", to select models by their labels.
+	var labelSelector dbmodel.LabelSelector
+	if selector, ok := strings.CutPrefix(namePrefixFilter, labelSelectorPrefix); ok {
+		namePrefixFilter = ""
+		labelSelector, err = dbmodel.ParseLabelSelector(selector)
+		if err != nil {
+			return nil, v1.NewInvalidRequestError(err.Error())
+		}
+		if len(labelSelector) == 0 {
+			return nil, v1.NewInvalidRequestError("empty label selector")
+		}
+		if typeFilter != "" && typeFilter != Model {
+			return nil, v1.NewInvalidRequestError("label selectors can only be used to list models")
+		}
+	}
 	if typeFilter != "" {
 		typeFilter, err = validateAndConvertResourceFilter(typeFilter)
 		if err != nil {
@@ -40,7 +62,7 @@ func (s *resourcesService) ListResources(ctx context.Context, params *resources.
 		}
 	}
 
-	res, err := s.jimm.ListResources(ctx, user, pagination, namePrefixFilter, typeFilter)
+	res, err := s.jimm.ListResources(ctx, user, pagination, namePrefixFilter, typeFilter, labelSelector)
 	if err != nil {
 		return nil, err
 	}
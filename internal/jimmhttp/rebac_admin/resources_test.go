@@ -13,6 +13,7 @@ import (
 	"github.com/canonical/jimm/v3/internal/common/pagination"
 	"github.com/canonical/jimm/v3/internal/common/utils"
 	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
 	"github.com/canonical/jimm/v3/internal/jimmhttp/rebac_admin"
 	"github.com/canonical/jimm/v3/internal/openfga"
 	"github.com/canonical/jimm/v3/internal/testutils/jimmtest"
@@ -20,8 +21,12 @@ import (
 
 func TestListResources(t *testing.T) {
 	c := qt.New(t)
+	var gotNameFilter string
+	var gotLabelSelector dbmodel.LabelSelector
 	jimm := jimmtest.JIMM{
-		ListResources_: func(ctx context.Context, user *openfga.User, filter pagination.LimitOffsetPagination, nameFilter, typeFilter string) ([]db.Resource, error) {
+		ListResources_: func(ctx context.Context, user *openfga.User, filter pagination.LimitOffsetPagination, nameFilter, typeFilter string, labelSelector dbmodel.LabelSelector) ([]db.Resource, error) {
+			gotNameFilter = nameFilter
+			gotLabelSelector = labelSelector
 			return []db.Resource{}, nil
 		},
 	}
@@ -38,6 +43,7 @@ func TestListResources(t *testing.T) {
 		nameFilter       *string
 		typeFilter       *string
 		expectErrorMatch string
+		expectSelector   dbmodel.LabelSelector
 	}{
 		{
 			desc:       "test good",
@@ -61,6 +67,38 @@ func TestListResources(t *testing.T) {
 			typeFilter:       utils.StringToPointer("type-not-found"),
 			expectErrorMatch: ".*this resource type is not supported.*",
 		},
+		{
+			desc:       "test with label selector",
+			nameFilter: utils.StringToPointer("label:env=prod,!deprecated"),
+			expectSelector: dbmodel.LabelSelector{
+				{Key: "env", Operator: dbmodel.LabelEquals, Value: "prod"},
+				{Key: "deprecated", Operator: dbmodel.LabelNotExists},
+			},
+		},
+		{
+			desc:             "test with invalid label selector",
+			nameFilter:       utils.StringToPointer("label:env=prod,=x"),
+			expectErrorMatch: `.*invalid label selector.*`,
+		},
+		{
+			desc:       "test with label selector and model type filter",
+			nameFilter: utils.StringToPointer("label:env=prod"),
+			typeFilter: utils.StringToPointer("model"),
+			expectSelector: dbmodel.LabelSelector{
+				{Key: "env", Operator: dbmodel.LabelEquals, Value: "prod"},
+			},
+		},
+		{
+			desc:             "test with empty label selector",
+			nameFilter:       utils.StringToPointer("label:"),
+			expectErrorMatch: `.*empty label selector.*`,
+		},
+		{
+			desc:             "test with label selector and other type filter",
+			nameFilter:       utils.StringToPointer("label:env=prod"),
+			typeFilter:       utils.StringToPointer("cloud"),
+			expectErrorMatch: `.*label selectors can only be used to list models.*`,
+		},
 	}
 	for _, t := range testCases {
 		c.Run(t.desc, func(c *qt.C) {
@@ -74,6 +112,10 @@ func TestListResources(t *testing.T) {
 				c.Assert(err, qt.ErrorMatches, t.expectErrorMatch)
 			} else {
 				c.Assert(err, qt.IsNil)
+				c.Check(gotLabelSelector, qt.DeepEquals, t.expectSelector)
+				if t.expectSelector != nil {
+					c.Check(gotNameFilter, qt.Equals, "")
+				}
 			}
 		})
 	}
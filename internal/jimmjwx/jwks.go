@@ -90,9 +90,9 @@ func rotateJWKS(ctx context.Context, credStore credentials.CredentialStore, init
 	return nil
 }
 
-// StartJWKSRotator starts a simple routine which checks the vaults TTL for the JWKS on a ticker.C.
-// It is expected that this routine will be cleaned up alongside other background services sharing
-// the same cancellable context.
+// RotateJWKS checks the expiry of the JWKS in the credential store and
+// generates a new JWKS if it has expired. If there is no JWKS in the
+// store one is generated, expiring at the given time.
 //
 // TODO(ale8k)[possibly?]:
 // For now, there's a single key, and this is probably OK. But possibly extend
@@ -101,35 +101,12 @@ func rotateJWKS(ctx context.Context, credStore credentials.CredentialStore, init
 // We also currently don't use x5c and x5t for validation and expect users
 // to use e and n for validation.
 // https://stackoverflow.com/questions/61395261/how-to-validate-signature-of-jwt-from-jwks-without-x5c
-func (jwks *JWKSService) StartJWKSRotator(ctx context.Context, checkRotateRequired <-chan time.Time, initialRotateRequiredTime time.Time) error {
-	const op = errors.Op("vault.StartJWKSRotator")
+func (jwks *JWKSService) RotateJWKS(ctx context.Context, initialRotateRequiredTime time.Time) error {
+	const op = errors.Op("vault.RotateJWKS")
 
-	credStore := jwks.credentialStore
-
-	if err := rotateJWKS(ctx, credStore, initialRotateRequiredTime); err != nil {
-		zapctx.Error(ctx, "Rotate JWKS error", zap.Error(err))
+	if err := rotateJWKS(ctx, jwks.credentialStore, initialRotateRequiredTime); err != nil {
 		return errors.E(op, err)
 	}
-
-	// The rotation method is as follows, if an expiry is not present, we know
-	// this is the first attempt to set the initial JWKS (or it may be subsequent from erroneous attempts).
-	// As the next attempt comes around, it is a simple check if the times is after the current.
-	//
-	// In this case we generate a new set, which should expire in 3 months.
-	go func() {
-		for {
-			select {
-			case <-checkRotateRequired:
-				if err := rotateJWKS(ctx, credStore, initialRotateRequiredTime); err != nil {
-					zapctx.Error(ctx, "security failure", zap.Any("op", op), zap.NamedError("jwks-error", err))
-				}
-			case <-ctx.Done():
-				zapctx.Debug(ctx, "Shutdown for JWKS rotator complete.")
-				return
-			}
-		}
-	}()
-
 	return nil
 }
 
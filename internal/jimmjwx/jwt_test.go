@@ -26,7 +26,7 @@ func TestRegisterJWKSCacheRegistersTheCacheSuccessfully(t *testing.T) {
 	// Setup JWKSService
 	jwksService := jimmjwx.NewJWKSService(store)
 	// Start rotator
-	startAndTestRotator(c, ctx, store, jwksService)
+	rotateAndTestJWKS(c, ctx, store, jwksService)
 	// Setup JWTService
 	jwtService := jimmjwx.NewJWTService(jimmjwx.JWTServiceParams{
 		Host:   "host",
@@ -57,7 +57,7 @@ func TestNewJWTIsParsableByExponent(t *testing.T) {
 	// Setup JWKSService
 	jwksService := jimmjwx.NewJWKSService(store)
 	// Start rotator
-	startAndTestRotator(c, ctx, store, jwksService)
+	rotateAndTestJWKS(c, ctx, store, jwksService)
 	// Setup JWTService
 	jwtService := jimmjwx.NewJWTService(jimmjwx.JWTServiceParams{
 		Host:   "host",
@@ -117,7 +117,7 @@ func TestNewJWTExpires(t *testing.T) {
 	// Setup JWKSService
 	jwksService := jimmjwx.NewJWKSService(store)
 	// Start rotator
-	startAndTestRotator(c, ctx, store, jwksService)
+	rotateAndTestJWKS(c, ctx, store, jwksService)
 	// Setup JWTService
 	jwtService := jimmjwx.NewJWTService(jimmjwx.JWTServiceParams{
 		Host:   "host",
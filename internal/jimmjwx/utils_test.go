@@ -48,31 +48,16 @@ func getJWKS(c *qt.C) jwk.Set {
 	return set
 }
 
-// startTestRotator starts a rotator, returning the ks that has been found
-// it does not guarantee the keyset has any keys!
-func startAndTestRotator(c *qt.C, ctx context.Context, store credentials.CredentialStore, svc *jimmjwx.JWKSService) jwk.Set {
+// rotateAndTestJWKS removes any JWKS from the store and then rotates it,
+// returning the new key set after checking it contains a key.
+func rotateAndTestJWKS(c *qt.C, ctx context.Context, store credentials.CredentialStore, svc *jimmjwx.JWKSService) jwk.Set {
 	err := store.CleanupJWKS(ctx)
 	c.Assert(err, qt.IsNil)
 
-	tick := make(chan time.Time, 1)
-	tick <- time.Now()
-	err = svc.StartJWKSRotator(ctx, tick, time.Now().AddDate(0, 3, 0))
+	err = svc.RotateJWKS(ctx, time.Now().AddDate(0, 3, 0))
 	c.Assert(err, qt.IsNil)
 
-	var ks jwk.Set
-	// We retry 500ms * 60 (30s)
-	for i := 0; i < 60; i++ {
-		if ks == nil {
-			ks, err = store.GetJWKS(ctx)
-			if err != nil {
-				c.Logf("failed to get JWKS: %s", err)
-			}
-			time.Sleep(500 * time.Millisecond)
-			continue
-		}
-		break
-
-	}
+	ks, err := store.GetJWKS(ctx)
 	c.Assert(err, qt.IsNil)
 	key, ok := ks.Key(0)
 	c.Assert(ok, qt.IsTrue)
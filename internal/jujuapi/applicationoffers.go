@@ -10,11 +10,14 @@ import (
 	"github.com/juju/juju/core/crossmodel"
 	jujuparams "github.com/juju/juju/rpc/params"
 	"github.com/juju/names/v5"
+	"github.com/juju/zaputil/zapctx"
+	"go.uber.org/zap"
 
 	"github.com/canonical/jimm/v3/internal/errors"
 	"github.com/canonical/jimm/v3/internal/jimm"
 	"github.com/canonical/jimm/v3/internal/jujuapi/rpc"
 	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
 )
 
 func init() {
@@ -26,6 +29,8 @@ func init() {
 		destroyOffersMethod := rpc.Method(r.DestroyOffers)
 		findOffersMethod := rpc.Method(r.FindApplicationOffers)
 		applicationOffersMethod := rpc.Method(r.ApplicationOffers)
+		listOfferConsumersMethod := rpc.Method(r.ListOfferConsumers)
+		revokeOfferAccessMethod := rpc.Method(r.RevokeOfferAccess)
 
 		r.AddMethod("ApplicationOffers", 4, "Offer", offerMethod)
 		r.AddMethod("ApplicationOffers", 4, "GetConsumeDetails", getConsumeDetailsMethod)
@@ -34,6 +39,9 @@ func init() {
 		r.AddMethod("ApplicationOffers", 4, "DestroyOffers", destroyOffersMethod)
 		r.AddMethod("ApplicationOffers", 4, "FindApplicationOffers", findOffersMethod)
 		r.AddMethod("ApplicationOffers", 4, "ApplicationOffers", applicationOffersMethod)
+		// JIMM extensions
+		r.AddMethod("ApplicationOffers", 4, "ListOfferConsumers", listOfferConsumersMethod)
+		r.AddMethod("ApplicationOffers", 4, "RevokeOfferAccess", revokeOfferAccessMethod)
 
 		return []int{4}
 	}
@@ -180,15 +188,47 @@ func (r *controllerRoot) modifyOfferAccess(ctx context.Context, change jujuparam
 		}
 		return nil
 	case jujuparams.RevokeOfferAccess:
-		if err := r.jimm.RevokeOfferAccess(ctx, r.user, change.OfferURL, ut, change.Access); err != nil {
+		broken, err := r.jimm.RevokeOfferAccess(ctx, r.user, change.OfferURL, ut, change.Access)
+		if err != nil {
 			return errors.E(op, err)
 		}
+		// The juju API has no way to report the relations that will
+		// break, clients that need them use RevokeOfferAccess.
+		for _, c := range broken {
+			for _, rel := range c.Relations {
+				zapctx.Warn(ctx, "revoked offer access used by relation",
+					zap.String("offer", change.OfferURL),
+					zap.String("identity", c.Identity),
+					zap.String("model-uuid", c.ModelUUID),
+					zap.Int("relation-id", rel.RelationID),
+					zap.String("endpoint", rel.Endpoint),
+				)
+			}
+		}
 		return nil
 	default:
 		return errors.E(op, errors.CodeBadRequest, fmt.Sprintf("unknown action %q", change.Action))
 	}
 }
 
+// RevokeOfferAccess revokes a user's access to an application offer,
+// returning the consumers with relations that relied on that access.
+func (r *controllerRoot) RevokeOfferAccess(ctx context.Context, req apiparams.RevokeOfferAccessRequest) (apiparams.RevokeOfferAccessResponse, error) {
+	const op = errors.Op("jujuapi.RevokeOfferAccess")
+
+	ut, err := parseUserTag(req.UserTag)
+	if err != nil {
+		return apiparams.RevokeOfferAccessResponse{}, errors.E(op, err, errors.CodeBadRequest)
+	}
+	broken, err := r.jimm.RevokeOfferAccess(ctx, r.user, req.OfferURL, ut, jujuparams.OfferAccessPermission(req.Access))
+	if err != nil {
+		return apiparams.RevokeOfferAccessResponse{}, errors.E(op, err)
+	}
+	return apiparams.RevokeOfferAccessResponse{
+		BrokenConsumers: broken,
+	}, nil
+}
+
 // DestroyOffers removes specified application offers.
 func (r *controllerRoot) DestroyOffers(ctx context.Context, args jujuparams.DestroyApplicationOffers) (jujuparams.ErrorResults, error) {
 	results := jujuparams.ErrorResults{
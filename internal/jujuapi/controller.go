@@ -57,6 +57,7 @@ type ControllerService interface {
 	ListControllers(ctx context.Context, user *openfga.User) ([]dbmodel.Controller, error)
 	RemoveController(ctx context.Context, user *openfga.User, controllerName string, force bool) error
 	SetControllerDeprecated(ctx context.Context, user *openfga.User, controllerName string, deprecated bool) error
+	SetControllerMaintenance(ctx context.Context, user *openfga.User, controllerName string, m dbmodel.ControllerMaintenance) error
 }
 
 // ConfigSet changes the value of specified controller configuration
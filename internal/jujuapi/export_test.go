@@ -7,6 +7,7 @@ import (
 
 	jujuparams "github.com/juju/juju/rpc/params"
 
+	"github.com/canonical/jimm/v3/internal/dbmodel"
 	"github.com/canonical/jimm/v3/internal/openfga"
 )
 
@@ -24,6 +25,12 @@ func NewModelSummaryWatcher() *modelSummaryWatcher {
 	}
 }
 
+// NewOfferConsumptionRecorder returns a function that records the
+// offers consumed by the proxied messages of a single connection.
+func NewOfferConsumptionRecorder(j JIMM, modelUUID string) func(context.Context, *dbmodel.AuditLogEntry) {
+	return newOfferConsumptionRecorder(j, modelUUID).record
+}
+
 func PublishToWatcher(w *modelSummaryWatcher, model string, data interface{}) {
 	w.pubsubHandler(model, data)
 }
@@ -18,6 +18,7 @@ import (
 	"github.com/canonical/jimm/v3/internal/openfga"
 	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
 	"github.com/canonical/jimm/v3/internal/pubsub"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
 	jimmnames "github.com/canonical/jimm/v3/pkg/names"
 )
 
@@ -27,12 +28,18 @@ type JIMM interface {
 	ControllerService
 	LoginService
 	ModelManager
+	AddAlertReceiver(ctx context.Context, user *openfga.User, req apiparams.AddAlertReceiverRequest) (apiparams.AlertReceiver, error)
+	AddAlertRule(ctx context.Context, user *openfga.User, req apiparams.AddAlertRuleRequest) (apiparams.AlertRule, error)
+	AddAlertSilence(ctx context.Context, user *openfga.User, req apiparams.AddAlertSilenceRequest) (apiparams.AlertSilence, error)
 	AddAuditLogEntry(ale *dbmodel.AuditLogEntry)
 	AddCloudToController(ctx context.Context, user *openfga.User, controllerName string, tag names.CloudTag, cloud jujuparams.Cloud, force bool) error
 	AddHostedCloud(ctx context.Context, user *openfga.User, tag names.CloudTag, cloud jujuparams.Cloud, force bool) error
 	AddServiceAccount(ctx context.Context, u *openfga.User, clientId string) error
+	AddWebhookSubscription(ctx context.Context, user *openfga.User, req apiparams.AddWebhookSubscriptionRequest) (apiparams.WebhookSubscription, error)
+	CharmDriftReport(ctx context.Context, user *openfga.User, req apiparams.CharmDriftReportRequest) (apiparams.CharmDriftReport, error)
 	CopyServiceAccountCredential(ctx context.Context, u *openfga.User, svcAcc *openfga.User, cloudCredentialTag names.CloudCredentialTag) (names.CloudCredentialTag, []jujuparams.UpdateCredentialModelResult, error)
 	CountIdentities(ctx context.Context, user *openfga.User) (int, error)
+	CreateUpgradeCampaign(ctx context.Context, user *openfga.User, req apiparams.CreateUpgradeCampaignRequest) (apiparams.UpgradeCampaign, error)
 	DestroyOffer(ctx context.Context, user *openfga.User, offerURL string, force bool) error
 	FindApplicationOffers(ctx context.Context, user *openfga.User, filters ...jujuparams.OfferFilter) ([]jujuparams.ApplicationOfferAdminDetailsV5, error)
 	FindAuditEvents(ctx context.Context, user *openfga.User, filter db.AuditLogFilter) ([]dbmodel.AuditLogEntry, error)
@@ -44,6 +51,18 @@ type JIMM interface {
 	GetCloud(ctx context.Context, u *openfga.User, tag names.CloudTag) (dbmodel.Cloud, error)
 	GetCloudCredential(ctx context.Context, user *openfga.User, tag names.CloudCredentialTag) (*dbmodel.CloudCredential, error)
 	GetCloudCredentialAttributes(ctx context.Context, u *openfga.User, cred *dbmodel.CloudCredential, hidden bool) (attrs map[string]string, redacted []string, err error)
+	GetModelInventory(ctx context.Context, user *openfga.User, mt names.ModelTag) (apiparams.ModelInventory, error)
+	GetModelLabels(ctx context.Context, user *openfga.User, mt names.ModelTag) (map[string]string, error)
+	GetModelQuotaUsage(ctx context.Context, user *openfga.User, identityName string) ([]apiparams.ModelQuotaUsage, error)
+	GetModelTemplate(ctx context.Context, user *openfga.User, name string) (apiparams.ModelTemplate, error)
+	GetUpgradeCampaign(ctx context.Context, user *openfga.User, name string) (apiparams.UpgradeCampaign, error)
+	ListAlertReceivers(ctx context.Context, user *openfga.User) ([]apiparams.AlertReceiver, error)
+	ListAlertRules(ctx context.Context, user *openfga.User) ([]apiparams.AlertRule, error)
+	ListAlertSilences(ctx context.Context, user *openfga.User) ([]apiparams.AlertSilence, error)
+	ListAlerts(ctx context.Context, user *openfga.User) ([]apiparams.Alert, error)
+	RemoveAlertReceiver(ctx context.Context, user *openfga.User, name string) error
+	RemoveAlertRule(ctx context.Context, user *openfga.User, name string) error
+	RemoveAlertSilence(ctx context.Context, user *openfga.User, id uint) error
 	RoleManager() jimm.RoleManager
 	GroupManager() jimm.GroupManager
 	GetJimmControllerAccess(ctx context.Context, user *openfga.User, tag names.UserTag) (string, error)
@@ -59,23 +78,50 @@ type JIMM interface {
 	GrantServiceAccountAccess(ctx context.Context, u *openfga.User, svcAccTag jimmnames.ServiceAccountTag, tags []string) error
 	InitiateInternalMigration(ctx context.Context, user *openfga.User, modelNameOrUUID string, targetController string) (jujuparams.InitiateMigrationResult, error)
 	InitiateMigration(ctx context.Context, user *openfga.User, spec jujuparams.MigrationSpec) (jujuparams.InitiateMigrationResult, error)
+	ListApplicationOfferConsumers(ctx context.Context, user *openfga.User, offerURL, revokedUser string) ([]apiparams.OfferConsumer, error)
 	ListApplicationOffers(ctx context.Context, user *openfga.User, filters ...jujuparams.OfferFilter) ([]jujuparams.ApplicationOfferAdminDetailsV5, error)
 	ListIdentities(ctx context.Context, user *openfga.User, pagination pagination.LimitOffsetPagination, match string) ([]openfga.User, error)
-	ListModels(ctx context.Context, user *openfga.User) ([]base.UserModel, error)
-	ListResources(ctx context.Context, user *openfga.User, filter pagination.LimitOffsetPagination, namePrefixFilter, typeFilter string) ([]db.Resource, error)
+	ListJobRuns(ctx context.Context, user *openfga.User, job string, limit int) ([]apiparams.JobRun, error)
+	ListModelPolicies(ctx context.Context, user *openfga.User) ([]apiparams.ModelPolicy, error)
+	ListModelQuotas(ctx context.Context, user *openfga.User) ([]apiparams.ModelQuota, error)
+	ListModelTemplates(ctx context.Context, user *openfga.User) ([]apiparams.ModelTemplate, error)
+	ListModels(ctx context.Context, user *openfga.User, labelSelector dbmodel.LabelSelector) ([]base.UserModel, error)
+	ListResources(ctx context.Context, user *openfga.User, filter pagination.LimitOffsetPagination, namePrefixFilter, typeFilter string, labelSelector dbmodel.LabelSelector) ([]db.Resource, error)
+	ListScheduledJobs(ctx context.Context, user *openfga.User) ([]apiparams.ScheduledJob, error)
+	ListUpgradeCampaigns(ctx context.Context, user *openfga.User) ([]apiparams.UpgradeCampaign, error)
+	ListWebhookDeadLetters(ctx context.Context, user *openfga.User, subscription string) ([]apiparams.WebhookDeadLetter, error)
+	ListWebhookSubscriptions(ctx context.Context, user *openfga.User) ([]apiparams.WebhookSubscription, error)
 	Offer(ctx context.Context, user *openfga.User, offer jimm.AddApplicationOfferParams) error
+	PauseUpgradeCampaign(ctx context.Context, user *openfga.User, name string) error
 	PubSubHub() *pubsub.Hub
 	PurgeLogs(ctx context.Context, user *openfga.User, before time.Time) (int64, error)
+	QueryInventory(ctx context.Context, user *openfga.User, req apiparams.QueryInventoryRequest) ([]apiparams.InventoryApplicationResult, error)
+	RecordApplicationOfferConsumed(ctx context.Context, identityName, modelUUID, offerUUID, offerURL string) error
 	RemoveCloud(ctx context.Context, u *openfga.User, ct names.CloudTag) error
 	RemoveCloudFromController(ctx context.Context, u *openfga.User, controllerName string, ct names.CloudTag) error
 	RemoveController(ctx context.Context, user *openfga.User, controllerName string, force bool) error
+	RemoveModelLabels(ctx context.Context, user *openfga.User, mt names.ModelTag, keys []string) error
+	RemoveModelPolicy(ctx context.Context, user *openfga.User, name string) error
+	RemoveModelQuota(ctx context.Context, user *openfga.User, kind, subject, region string) error
+	RemoveModelTemplate(ctx context.Context, user *openfga.User, name string) error
+	RemoveUpgradeCampaign(ctx context.Context, user *openfga.User, name string) error
+	RemoveWebhookSubscription(ctx context.Context, user *openfga.User, name string) error
+	RetryWebhookDeadLetter(ctx context.Context, user *openfga.User, id uint) error
 	ResourceTag() names.ControllerTag
 	RevokeAuditLogAccess(ctx context.Context, user *openfga.User, targetUserTag names.UserTag) error
 	RevokeCloudAccess(ctx context.Context, user *openfga.User, ct names.CloudTag, ut names.UserTag, access string) error
 	RevokeCloudCredential(ctx context.Context, user *dbmodel.Identity, tag names.CloudCredentialTag, force bool) error
 	RevokeModelAccess(ctx context.Context, user *openfga.User, mt names.ModelTag, ut names.UserTag, access jujuparams.UserAccessPermission) error
-	RevokeOfferAccess(ctx context.Context, user *openfga.User, offerURL string, ut names.UserTag, access jujuparams.OfferAccessPermission) (err error)
+	RevokeOfferAccess(ctx context.Context, user *openfga.User, offerURL string, ut names.UserTag, access jujuparams.OfferAccessPermission) ([]apiparams.OfferConsumer, error)
+	SetModelLabels(ctx context.Context, user *openfga.User, mt names.ModelTag, labels map[string]string) error
+	SetModelPolicy(ctx context.Context, user *openfga.User, policy apiparams.ModelPolicy) error
+	SetModelQuota(ctx context.Context, user *openfga.User, quota apiparams.ModelQuota) error
+	SetModelTemplate(ctx context.Context, user *openfga.User, template apiparams.ModelTemplate) error
+	SetModelTTL(ctx context.Context, user *openfga.User, mt names.ModelTag, ttl time.Duration) (time.Time, error)
+	StartUpgradeCampaign(ctx context.Context, user *openfga.User, name string) error
 	ToJAASTag(ctx context.Context, tag *ofganames.Tag, resolveUUIDs bool) (string, error)
+	TransferModelOwnership(ctx context.Context, user *openfga.User, mt names.ModelTag, newOwner string, cloudCredentialTag names.CloudCredentialTag) error
+	TriggerScheduledJob(ctx context.Context, user *openfga.User, job string) error
 	UpdateCloud(ctx context.Context, u *openfga.User, ct names.CloudTag, cloud jujuparams.Cloud) error
 	UpdateCloudCredential(ctx context.Context, u *openfga.User, args jimm.UpdateCloudCredentialArgs) ([]jujuparams.UpdateCredentialModelResult, error)
 	UserLogin(ctx context.Context, identityName string) (*openfga.User, error)
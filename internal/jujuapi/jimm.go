@@ -4,6 +4,7 @@ package jujuapi
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"net"
 	"strings"
@@ -36,6 +37,7 @@ func init() {
 		removeControllerMethod := rpc.Method(r.RemoveController)
 		revokeAuditLogAccessMethod := rpc.Method(r.RevokeAuditLogAccess)
 		setControllerDeprecatedMethod := rpc.Method(r.SetControllerDeprecated)
+		setControllerMaintenanceMethod := rpc.Method(r.SetControllerMaintenance)
 		fullModelStatusMethod := rpc.Method(r.FullModelStatus)
 		updateMigratedModelMethod := rpc.Method(r.UpdateMigratedModel)
 		addCloudToControllerMethod := rpc.Method(r.AddCloudToController)
@@ -63,6 +65,52 @@ func init() {
 		listServiceAccountCredentials := rpc.Method(r.ListServiceAccountCredentials)
 		grantServiceAccountAccess := rpc.Method(r.GrantServiceAccountAccess)
 		version := rpc.Method(r.Version)
+		listOfferConsumers := rpc.Method(r.ListOfferConsumers)
+		setModelQuotaMethod := rpc.Method(r.SetModelQuota)
+		removeModelQuotaMethod := rpc.Method(r.RemoveModelQuota)
+		listModelQuotasMethod := rpc.Method(r.ListModelQuotas)
+		getModelQuotaUsageMethod := rpc.Method(r.GetModelQuotaUsage)
+		setModelTTLMethod := rpc.Method(r.SetModelTTL)
+		setModelLabelsMethod := rpc.Method(r.SetModelLabels)
+		removeModelLabelsMethod := rpc.Method(r.RemoveModelLabels)
+		getModelLabelsMethod := rpc.Method(r.GetModelLabels)
+		listModelsByLabelMethod := rpc.Method(r.ListModelsByLabel)
+		listModelSummariesByLabelMethod := rpc.Method(r.ListModelSummariesByLabel)
+		setModelTemplateMethod := rpc.Method(r.SetModelTemplate)
+		removeModelTemplateMethod := rpc.Method(r.RemoveModelTemplate)
+		getModelTemplateMethod := rpc.Method(r.GetModelTemplate)
+		listModelTemplatesMethod := rpc.Method(r.ListModelTemplates)
+		setModelPolicyMethod := rpc.Method(r.SetModelPolicy)
+		removeModelPolicyMethod := rpc.Method(r.RemoveModelPolicy)
+		listModelPoliciesMethod := rpc.Method(r.ListModelPolicies)
+		getModelInventoryMethod := rpc.Method(r.GetModelInventory)
+		queryInventoryMethod := rpc.Method(r.QueryInventory)
+		charmDriftReportMethod := rpc.Method(r.CharmDriftReport)
+		createUpgradeCampaignMethod := rpc.Method(r.CreateUpgradeCampaign)
+		startUpgradeCampaignMethod := rpc.Method(r.StartUpgradeCampaign)
+		pauseUpgradeCampaignMethod := rpc.Method(r.PauseUpgradeCampaign)
+		getUpgradeCampaignMethod := rpc.Method(r.GetUpgradeCampaign)
+		listUpgradeCampaignsMethod := rpc.Method(r.ListUpgradeCampaigns)
+		removeUpgradeCampaignMethod := rpc.Method(r.RemoveUpgradeCampaign)
+		transferModelOwnershipMethod := rpc.Method(r.TransferModelOwnership)
+		addWebhookSubscriptionMethod := rpc.Method(r.AddWebhookSubscription)
+		listWebhookSubscriptionsMethod := rpc.Method(r.ListWebhookSubscriptions)
+		removeWebhookSubscriptionMethod := rpc.Method(r.RemoveWebhookSubscription)
+		listWebhookDeadLettersMethod := rpc.Method(r.ListWebhookDeadLetters)
+		retryWebhookDeadLetterMethod := rpc.Method(r.RetryWebhookDeadLetter)
+		listScheduledJobsMethod := rpc.Method(r.ListScheduledJobs)
+		listJobRunsMethod := rpc.Method(r.ListJobRuns)
+		triggerScheduledJobMethod := rpc.Method(r.TriggerScheduledJob)
+		addAlertReceiverMethod := rpc.Method(r.AddAlertReceiver)
+		listAlertReceiversMethod := rpc.Method(r.ListAlertReceivers)
+		removeAlertReceiverMethod := rpc.Method(r.RemoveAlertReceiver)
+		addAlertRuleMethod := rpc.Method(r.AddAlertRule)
+		listAlertRulesMethod := rpc.Method(r.ListAlertRules)
+		removeAlertRuleMethod := rpc.Method(r.RemoveAlertRule)
+		listAlertsMethod := rpc.Method(r.ListAlerts)
+		addAlertSilenceMethod := rpc.Method(r.AddAlertSilence)
+		listAlertSilencesMethod := rpc.Method(r.ListAlertSilences)
+		removeAlertSilenceMethod := rpc.Method(r.RemoveAlertSilence)
 
 		// JIMM Generic RPC
 		r.AddMethod("JIMM", 4, "AddController", addControllerMethod)
@@ -75,11 +123,70 @@ func init() {
 		r.AddMethod("JIMM", 4, "RemoveController", removeControllerMethod)
 		r.AddMethod("JIMM", 4, "RevokeAuditLogAccess", revokeAuditLogAccessMethod)
 		r.AddMethod("JIMM", 4, "SetControllerDeprecated", setControllerDeprecatedMethod)
+		r.AddMethod("JIMM", 4, "SetControllerMaintenance", setControllerMaintenanceMethod)
 		r.AddMethod("JIMM", 4, "UpdateMigratedModel", updateMigratedModelMethod)
 		r.AddMethod("JIMM", 4, "AddCloudToController", addCloudToControllerMethod)
 		r.AddMethod("JIMM", 4, "RemoveCloudFromController", removeCloudFromControllerMethod)
 		r.AddMethod("JIMM", 4, "PurgeLogs", purgeLogsMethod)
 		r.AddMethod("JIMM", 4, "MigrateModel", migrateModel)
+		r.AddMethod("JIMM", 4, "ListOfferConsumers", listOfferConsumers)
+		// JIMM Model quotas
+		r.AddMethod("JIMM", 4, "SetModelQuota", setModelQuotaMethod)
+		r.AddMethod("JIMM", 4, "RemoveModelQuota", removeModelQuotaMethod)
+		r.AddMethod("JIMM", 4, "ListModelQuotas", listModelQuotasMethod)
+		r.AddMethod("JIMM", 4, "GetModelQuotaUsage", getModelQuotaUsageMethod)
+		// JIMM ephemeral models
+		r.AddMethod("JIMM", 4, "SetModelTTL", setModelTTLMethod)
+		// JIMM model labels
+		r.AddMethod("JIMM", 4, "SetModelLabels", setModelLabelsMethod)
+		r.AddMethod("JIMM", 4, "RemoveModelLabels", removeModelLabelsMethod)
+		r.AddMethod("JIMM", 4, "GetModelLabels", getModelLabelsMethod)
+		r.AddMethod("JIMM", 4, "ListModelsByLabel", listModelsByLabelMethod)
+		r.AddMethod("JIMM", 4, "ListModelSummariesByLabel", listModelSummariesByLabelMethod)
+		// JIMM model templates
+		r.AddMethod("JIMM", 4, "SetModelTemplate", setModelTemplateMethod)
+		r.AddMethod("JIMM", 4, "RemoveModelTemplate", removeModelTemplateMethod)
+		r.AddMethod("JIMM", 4, "GetModelTemplate", getModelTemplateMethod)
+		r.AddMethod("JIMM", 4, "ListModelTemplates", listModelTemplatesMethod)
+		// JIMM model policies
+		r.AddMethod("JIMM", 4, "SetModelPolicy", setModelPolicyMethod)
+		r.AddMethod("JIMM", 4, "RemoveModelPolicy", removeModelPolicyMethod)
+		r.AddMethod("JIMM", 4, "ListModelPolicies", listModelPoliciesMethod)
+		// JIMM estate inventory
+		r.AddMethod("JIMM", 4, "GetModelInventory", getModelInventoryMethod)
+		r.AddMethod("JIMM", 4, "QueryInventory", queryInventoryMethod)
+		// JIMM reports
+		r.AddMethod("JIMM", 4, "CharmDriftReport", charmDriftReportMethod)
+		// JIMM model upgrade campaigns
+		r.AddMethod("JIMM", 4, "CreateUpgradeCampaign", createUpgradeCampaignMethod)
+		r.AddMethod("JIMM", 4, "StartUpgradeCampaign", startUpgradeCampaignMethod)
+		r.AddMethod("JIMM", 4, "PauseUpgradeCampaign", pauseUpgradeCampaignMethod)
+		r.AddMethod("JIMM", 4, "GetUpgradeCampaign", getUpgradeCampaignMethod)
+		r.AddMethod("JIMM", 4, "ListUpgradeCampaigns", listUpgradeCampaignsMethod)
+		r.AddMethod("JIMM", 4, "RemoveUpgradeCampaign", removeUpgradeCampaignMethod)
+		// JIMM model ownership
+		r.AddMethod("JIMM", 4, "TransferModelOwnership", transferModelOwnershipMethod)
+		// JIMM model lifecycle webhooks
+		r.AddMethod("JIMM", 4, "AddWebhookSubscription", addWebhookSubscriptionMethod)
+		r.AddMethod("JIMM", 4, "ListWebhookSubscriptions", listWebhookSubscriptionsMethod)
+		r.AddMethod("JIMM", 4, "RemoveWebhookSubscription", removeWebhookSubscriptionMethod)
+		r.AddMethod("JIMM", 4, "ListWebhookDeadLetters", listWebhookDeadLettersMethod)
+		r.AddMethod("JIMM", 4, "RetryWebhookDeadLetter", retryWebhookDeadLetterMethod)
+		// JIMM scheduled jobs
+		r.AddMethod("JIMM", 4, "ListScheduledJobs", listScheduledJobsMethod)
+		r.AddMethod("JIMM", 4, "ListJobRuns", listJobRunsMethod)
+		r.AddMethod("JIMM", 4, "TriggerScheduledJob", triggerScheduledJobMethod)
+		// JIMM alerting
+		r.AddMethod("JIMM", 4, "AddAlertReceiver", addAlertReceiverMethod)
+		r.AddMethod("JIMM", 4, "ListAlertReceivers", listAlertReceiversMethod)
+		r.AddMethod("JIMM", 4, "RemoveAlertReceiver", removeAlertReceiverMethod)
+		r.AddMethod("JIMM", 4, "AddAlertRule", addAlertRuleMethod)
+		r.AddMethod("JIMM", 4, "ListAlertRules", listAlertRulesMethod)
+		r.AddMethod("JIMM", 4, "RemoveAlertRule", removeAlertRuleMethod)
+		r.AddMethod("JIMM", 4, "ListAlerts", listAlertsMethod)
+		r.AddMethod("JIMM", 4, "AddAlertSilence", addAlertSilenceMethod)
+		r.AddMethod("JIMM", 4, "ListAlertSilences", listAlertSilencesMethod)
+		r.AddMethod("JIMM", 4, "RemoveAlertSilence", removeAlertSilenceMethod)
 		// JIMM ReBAC RPC
 		r.AddMethod("JIMM", 4, "AddGroup", addGroupMethod)
 		r.AddMethod("JIMM", 4, "GetGroup", getGroupMethod)
@@ -288,6 +395,28 @@ func (r *controllerRoot) SetControllerDeprecated(ctx context.Context, req apipar
 	return ctl.ToAPIControllerInfo(), nil
 }
 
+// SetControllerMaintenance sets the maintenance state of a controller.
+func (r *controllerRoot) SetControllerMaintenance(ctx context.Context, req apiparams.SetControllerMaintenanceRequest) (apiparams.ControllerInfo, error) {
+	const op = errors.Op("jujuapi.SetControllerMaintenance")
+
+	m := dbmodel.ControllerMaintenance{
+		Enabled:       req.Maintenance,
+		Message:       req.Message,
+		AllowReadOnly: req.AllowReadOnly,
+	}
+	if req.EndTime != nil {
+		m.EndsAt = sql.NullTime{Time: *req.EndTime, Valid: true}
+	}
+	if err := r.jimm.SetControllerMaintenance(ctx, r.user, req.Name, m); err != nil {
+		return apiparams.ControllerInfo{}, errors.E(op, err)
+	}
+	ctl, err := r.jimm.ControllerInfo(ctx, req.Name)
+	if err != nil {
+		return apiparams.ControllerInfo{}, errors.E(op, err)
+	}
+	return ctl.ToAPIControllerInfo(), nil
+}
+
 // maxLimit is the maximum number of audit-log entries that will be
 // returned from the audit log, no matter how many are requested.
 const maxLimit = 1000
@@ -466,6 +595,10 @@ func (r *controllerRoot) RemoveCloudFromController(ctx context.Context, req apip
 func (r *controllerRoot) CrossModelQuery(ctx context.Context, req apiparams.CrossModelQueryRequest) (apiparams.CrossModelQueryResponse, error) {
 	const op = errors.Op("jujuapi.CrossModelQuery")
 
+	labelSelector, err := dbmodel.ParseLabelSelector(req.LabelSelector)
+	if err != nil {
+		return apiparams.CrossModelQueryResponse{}, errors.E(op, err)
+	}
 	modelUUIDs, err := r.user.ListModels(ctx, ofganames.ReaderRelation)
 	if err != nil {
 		return apiparams.CrossModelQueryResponse{}, errors.E(op, errors.Code("failed to list user's model access"))
@@ -473,7 +606,7 @@ func (r *controllerRoot) CrossModelQuery(ctx context.Context, req apiparams.Cros
 
 	switch strings.TrimSpace(strings.ToLower(req.Type)) {
 	case "jq":
-		return r.jimm.QueryModelsJq(ctx, modelUUIDs, req.Query)
+		return r.jimm.QueryModelsJq(ctx, modelUUIDs, req.Query, labelSelector)
 	case "jimmsql":
 		return apiparams.CrossModelQueryResponse{}, errors.E(op, errors.CodeNotImplemented)
 	default:
@@ -515,6 +648,597 @@ func (r *controllerRoot) MigrateModel(ctx context.Context, args apiparams.Migrat
 	}, nil
 }
 
+// ListOfferConsumers returns the identities and models that consume an
+// application offer, along with their relations to the offer.
+func (r *controllerRoot) ListOfferConsumers(ctx context.Context, req apiparams.ListOfferConsumersRequest) (apiparams.ListOfferConsumersResponse, error) {
+	const op = errors.Op("jujuapi.ListOfferConsumers")
+
+	if req.RevokedUser != "" && !names.IsValidUser(req.RevokedUser) {
+		return apiparams.ListOfferConsumersResponse{}, errors.E(op, errors.CodeBadRequest, "invalid user name")
+	}
+	consumers, err := r.jimm.ListApplicationOfferConsumers(ctx, r.user, req.OfferURL, req.RevokedUser)
+	if err != nil {
+		return apiparams.ListOfferConsumersResponse{}, errors.E(op, err)
+	}
+	return apiparams.ListOfferConsumersResponse{
+		Consumers: consumers,
+	}, nil
+}
+
+// SetModelQuota creates, or updates, a model quota.
+func (r *controllerRoot) SetModelQuota(ctx context.Context, req apiparams.SetModelQuotaRequest) error {
+	const op = errors.Op("jujuapi.SetModelQuota")
+
+	if err := r.jimm.SetModelQuota(ctx, r.user, req.ModelQuota); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// RemoveModelQuota removes a model quota.
+func (r *controllerRoot) RemoveModelQuota(ctx context.Context, req apiparams.RemoveModelQuotaRequest) error {
+	const op = errors.Op("jujuapi.RemoveModelQuota")
+
+	if err := r.jimm.RemoveModelQuota(ctx, r.user, req.Kind, req.Subject, req.Region); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// ListModelQuotas returns all the model quotas defined in JIMM.
+func (r *controllerRoot) ListModelQuotas(ctx context.Context) (apiparams.ListModelQuotasResponse, error) {
+	const op = errors.Op("jujuapi.ListModelQuotas")
+
+	quotas, err := r.jimm.ListModelQuotas(ctx, r.user)
+	if err != nil {
+		return apiparams.ListModelQuotasResponse{}, errors.E(op, err)
+	}
+	return apiparams.ListModelQuotasResponse{
+		Quotas: quotas,
+	}, nil
+}
+
+// GetModelQuotaUsage returns the model quotas that apply to an identity
+// and the identity's usage against them.
+func (r *controllerRoot) GetModelQuotaUsage(ctx context.Context, req apiparams.ModelQuotaUsageRequest) (apiparams.ModelQuotaUsageResponse, error) {
+	const op = errors.Op("jujuapi.GetModelQuotaUsage")
+
+	if req.Identity != "" && !names.IsValidUser(req.Identity) {
+		return apiparams.ModelQuotaUsageResponse{}, errors.E(op, errors.CodeBadRequest, "invalid identity name")
+	}
+	identity := req.Identity
+	if identity == "" {
+		identity = r.user.Name
+	}
+	quotas, err := r.jimm.GetModelQuotaUsage(ctx, r.user, identity)
+	if err != nil {
+		return apiparams.ModelQuotaUsageResponse{}, errors.E(op, err)
+	}
+	return apiparams.ModelQuotaUsageResponse{
+		Identity: identity,
+		Quotas:   quotas,
+	}, nil
+}
+
+// SetModelTTL sets an ephemeral model to expire once the given TTL has
+// passed, this is used to extend the life of ephemeral models.
+func (r *controllerRoot) SetModelTTL(ctx context.Context, req apiparams.SetModelTTLRequest) (apiparams.SetModelTTLResponse, error) {
+	const op = errors.Op("jujuapi.SetModelTTL")
+
+	mt, err := names.ParseModelTag(req.ModelTag)
+	if err != nil {
+		return apiparams.SetModelTTLResponse{}, errors.E(op, err, errors.CodeBadRequest)
+	}
+	ttl, err := time.ParseDuration(req.TTL)
+	if err != nil {
+		return apiparams.SetModelTTLResponse{}, errors.E(op, err, errors.CodeBadRequest)
+	}
+	expiresAt, err := r.jimm.SetModelTTL(ctx, r.user, mt, ttl)
+	if err != nil {
+		return apiparams.SetModelTTLResponse{}, errors.E(op, err)
+	}
+	return apiparams.SetModelTTLResponse{
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// SetModelLabels sets labels on a model.
+func (r *controllerRoot) SetModelLabels(ctx context.Context, req apiparams.SetModelLabelsRequest) error {
+	const op = errors.Op("jujuapi.SetModelLabels")
+
+	mt, err := names.ParseModelTag(req.ModelTag)
+	if err != nil {
+		return errors.E(op, err, errors.CodeBadRequest)
+	}
+	if err := r.jimm.SetModelLabels(ctx, r.user, mt, req.Labels); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// RemoveModelLabels removes labels from a model.
+func (r *controllerRoot) RemoveModelLabels(ctx context.Context, req apiparams.RemoveModelLabelsRequest) error {
+	const op = errors.Op("jujuapi.RemoveModelLabels")
+
+	mt, err := names.ParseModelTag(req.ModelTag)
+	if err != nil {
+		return errors.E(op, err, errors.CodeBadRequest)
+	}
+	if err := r.jimm.RemoveModelLabels(ctx, r.user, mt, req.Keys); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// GetModelLabels returns the labels of a model.
+func (r *controllerRoot) GetModelLabels(ctx context.Context, req apiparams.GetModelLabelsRequest) (apiparams.GetModelLabelsResponse, error) {
+	const op = errors.Op("jujuapi.GetModelLabels")
+
+	mt, err := names.ParseModelTag(req.ModelTag)
+	if err != nil {
+		return apiparams.GetModelLabelsResponse{}, errors.E(op, err, errors.CodeBadRequest)
+	}
+	labels, err := r.jimm.GetModelLabels(ctx, r.user, mt)
+	if err != nil {
+		return apiparams.GetModelLabelsResponse{}, errors.E(op, err)
+	}
+	return apiparams.GetModelLabelsResponse{
+		Labels: labels,
+	}, nil
+}
+
+// ListModelsByLabel returns the models the authenticated user has access
+// to that match the given label selector.
+func (r *controllerRoot) ListModelsByLabel(ctx context.Context, req apiparams.ListModelsByLabelRequest) (jujuparams.UserModelList, error) {
+	const op = errors.Op("jujuapi.ListModelsByLabel")
+
+	labelSelector, err := dbmodel.ParseLabelSelector(req.LabelSelector)
+	if err != nil {
+		return jujuparams.UserModelList{}, errors.E(op, err)
+	}
+	res, err := r.listModels(ctx, labelSelector)
+	if err != nil {
+		return jujuparams.UserModelList{}, errors.E(op, err)
+	}
+	return res, nil
+}
+
+// ListModelSummariesByLabel returns summaries for the models the
+// authenticated user has access to that match the given label selector.
+func (r *controllerRoot) ListModelSummariesByLabel(ctx context.Context, req apiparams.ListModelsByLabelRequest) (jujuparams.ModelSummaryResults, error) {
+	const op = errors.Op("jujuapi.ListModelSummariesByLabel")
+
+	labelSelector, err := dbmodel.ParseLabelSelector(req.LabelSelector)
+	if err != nil {
+		return jujuparams.ModelSummaryResults{}, errors.E(op, err)
+	}
+	maskingControllerUUID := ""
+	if r.controllerUUIDMasking {
+		maskingControllerUUID = r.params.ControllerUUID
+	}
+	res, err := r.jimm.ListModelSummaries(ctx, r.user, maskingControllerUUID, labelSelector)
+	if err != nil {
+		return jujuparams.ModelSummaryResults{}, errors.E(op, err)
+	}
+	return res, nil
+}
+
+// SetModelTemplate creates, or replaces, a model template.
+func (r *controllerRoot) SetModelTemplate(ctx context.Context, req apiparams.SetModelTemplateRequest) error {
+	const op = errors.Op("jujuapi.SetModelTemplate")
+
+	if err := r.jimm.SetModelTemplate(ctx, r.user, req.ModelTemplate); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// RemoveModelTemplate removes a model template.
+func (r *controllerRoot) RemoveModelTemplate(ctx context.Context, req apiparams.RemoveModelTemplateRequest) error {
+	const op = errors.Op("jujuapi.RemoveModelTemplate")
+
+	if err := r.jimm.RemoveModelTemplate(ctx, r.user, req.Name); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// GetModelTemplate returns a model template.
+func (r *controllerRoot) GetModelTemplate(ctx context.Context, req apiparams.GetModelTemplateRequest) (apiparams.ModelTemplate, error) {
+	const op = errors.Op("jujuapi.GetModelTemplate")
+
+	t, err := r.jimm.GetModelTemplate(ctx, r.user, req.Name)
+	if err != nil {
+		return apiparams.ModelTemplate{}, errors.E(op, err)
+	}
+	return t, nil
+}
+
+// ListModelTemplates returns all the model templates defined in JIMM.
+func (r *controllerRoot) ListModelTemplates(ctx context.Context) (apiparams.ListModelTemplatesResponse, error) {
+	const op = errors.Op("jujuapi.ListModelTemplates")
+
+	templates, err := r.jimm.ListModelTemplates(ctx, r.user)
+	if err != nil {
+		return apiparams.ListModelTemplatesResponse{}, errors.E(op, err)
+	}
+	return apiparams.ListModelTemplatesResponse{
+		Templates: templates,
+	}, nil
+}
+
+// SetModelPolicy creates, or replaces, a model policy.
+func (r *controllerRoot) SetModelPolicy(ctx context.Context, req apiparams.SetModelPolicyRequest) error {
+	const op = errors.Op("jujuapi.SetModelPolicy")
+
+	if err := r.jimm.SetModelPolicy(ctx, r.user, req.ModelPolicy); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// RemoveModelPolicy removes a model policy.
+func (r *controllerRoot) RemoveModelPolicy(ctx context.Context, req apiparams.RemoveModelPolicyRequest) error {
+	const op = errors.Op("jujuapi.RemoveModelPolicy")
+
+	if err := r.jimm.RemoveModelPolicy(ctx, r.user, req.Name); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// ListModelPolicies returns all the model policies defined in JIMM.
+func (r *controllerRoot) ListModelPolicies(ctx context.Context) (apiparams.ListModelPoliciesResponse, error) {
+	const op = errors.Op("jujuapi.ListModelPolicies")
+
+	policies, err := r.jimm.ListModelPolicies(ctx, r.user)
+	if err != nil {
+		return apiparams.ListModelPoliciesResponse{}, errors.E(op, err)
+	}
+	return apiparams.ListModelPoliciesResponse{
+		Policies: policies,
+	}, nil
+}
+
+// GetModelInventory returns the most recently collected inventory of a
+// model.
+func (r *controllerRoot) GetModelInventory(ctx context.Context, req apiparams.GetModelInventoryRequest) (apiparams.ModelInventory, error) {
+	const op = errors.Op("jujuapi.GetModelInventory")
+
+	mt, err := names.ParseModelTag(req.ModelTag)
+	if err != nil {
+		return apiparams.ModelInventory{}, errors.E(op, err, errors.CodeBadRequest)
+	}
+	inv, err := r.jimm.GetModelInventory(ctx, r.user, mt)
+	if err != nil {
+		return apiparams.ModelInventory{}, errors.E(op, err)
+	}
+	return inv, nil
+}
+
+// QueryInventory finds applications across all model inventories.
+func (r *controllerRoot) QueryInventory(ctx context.Context, req apiparams.QueryInventoryRequest) (apiparams.QueryInventoryResponse, error) {
+	const op = errors.Op("jujuapi.QueryInventory")
+
+	apps, err := r.jimm.QueryInventory(ctx, r.user, req)
+	if err != nil {
+		return apiparams.QueryInventoryResponse{}, errors.E(op, err)
+	}
+	return apiparams.QueryInventoryResponse{
+		Applications: apps,
+	}, nil
+}
+
+// CharmDriftReport reports the charm revisions deployed across the
+// estate.
+func (r *controllerRoot) CharmDriftReport(ctx context.Context, req apiparams.CharmDriftReportRequest) (apiparams.CharmDriftReport, error) {
+	const op = errors.Op("jujuapi.CharmDriftReport")
+
+	report, err := r.jimm.CharmDriftReport(ctx, r.user, req)
+	if err != nil {
+		return apiparams.CharmDriftReport{}, errors.E(op, err)
+	}
+	return report, nil
+}
+
+// CreateUpgradeCampaign creates a model upgrade campaign.
+func (r *controllerRoot) CreateUpgradeCampaign(ctx context.Context, req apiparams.CreateUpgradeCampaignRequest) (apiparams.UpgradeCampaign, error) {
+	const op = errors.Op("jujuapi.CreateUpgradeCampaign")
+
+	campaign, err := r.jimm.CreateUpgradeCampaign(ctx, r.user, req)
+	if err != nil {
+		return apiparams.UpgradeCampaign{}, errors.E(op, err)
+	}
+	return campaign, nil
+}
+
+// StartUpgradeCampaign starts, or resumes, a model upgrade campaign.
+func (r *controllerRoot) StartUpgradeCampaign(ctx context.Context, req apiparams.UpgradeCampaignRequest) error {
+	const op = errors.Op("jujuapi.StartUpgradeCampaign")
+
+	if err := r.jimm.StartUpgradeCampaign(ctx, r.user, req.Name); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// PauseUpgradeCampaign pauses a running model upgrade campaign.
+func (r *controllerRoot) PauseUpgradeCampaign(ctx context.Context, req apiparams.UpgradeCampaignRequest) error {
+	const op = errors.Op("jujuapi.PauseUpgradeCampaign")
+
+	if err := r.jimm.PauseUpgradeCampaign(ctx, r.user, req.Name); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// GetUpgradeCampaign returns a model upgrade campaign, including the
+// state of each of its models.
+func (r *controllerRoot) GetUpgradeCampaign(ctx context.Context, req apiparams.UpgradeCampaignRequest) (apiparams.UpgradeCampaign, error) {
+	const op = errors.Op("jujuapi.GetUpgradeCampaign")
+
+	campaign, err := r.jimm.GetUpgradeCampaign(ctx, r.user, req.Name)
+	if err != nil {
+		return apiparams.UpgradeCampaign{}, errors.E(op, err)
+	}
+	return campaign, nil
+}
+
+// ListUpgradeCampaigns returns all the model upgrade campaigns.
+func (r *controllerRoot) ListUpgradeCampaigns(ctx context.Context) (apiparams.ListUpgradeCampaignsResponse, error) {
+	const op = errors.Op("jujuapi.ListUpgradeCampaigns")
+
+	campaigns, err := r.jimm.ListUpgradeCampaigns(ctx, r.user)
+	if err != nil {
+		return apiparams.ListUpgradeCampaignsResponse{}, errors.E(op, err)
+	}
+	return apiparams.ListUpgradeCampaignsResponse{
+		Campaigns: campaigns,
+	}, nil
+}
+
+// RemoveUpgradeCampaign removes a model upgrade campaign.
+func (r *controllerRoot) RemoveUpgradeCampaign(ctx context.Context, req apiparams.UpgradeCampaignRequest) error {
+	const op = errors.Op("jujuapi.RemoveUpgradeCampaign")
+
+	if err := r.jimm.RemoveUpgradeCampaign(ctx, r.user, req.Name); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// TransferModelOwnership transfers the ownership of a model to another
+// user, optionally switching the cloud credential the model uses.
+func (r *controllerRoot) TransferModelOwnership(ctx context.Context, req apiparams.TransferModelOwnershipRequest) error {
+	const op = errors.Op("jujuapi.TransferModelOwnership")
+
+	mt, err := names.ParseModelTag(req.ModelTag)
+	if err != nil {
+		return errors.E(op, err, errors.CodeBadRequest)
+	}
+	var cct names.CloudCredentialTag
+	if req.CloudCredentialTag != "" {
+		cct, err = names.ParseCloudCredentialTag(req.CloudCredentialTag)
+		if err != nil {
+			return errors.E(op, err, errors.CodeBadRequest)
+		}
+	}
+	if err := r.jimm.TransferModelOwnership(ctx, r.user, mt, req.NewOwner, cct); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// AddWebhookSubscription subscribes a webhook to model lifecycle events.
+func (r *controllerRoot) AddWebhookSubscription(ctx context.Context, req apiparams.AddWebhookSubscriptionRequest) (apiparams.WebhookSubscription, error) {
+	const op = errors.Op("jujuapi.AddWebhookSubscription")
+
+	subscription, err := r.jimm.AddWebhookSubscription(ctx, r.user, req)
+	if err != nil {
+		return apiparams.WebhookSubscription{}, errors.E(op, err)
+	}
+	return subscription, nil
+}
+
+// ListWebhookSubscriptions returns all the webhook subscriptions.
+func (r *controllerRoot) ListWebhookSubscriptions(ctx context.Context) (apiparams.ListWebhookSubscriptionsResponse, error) {
+	const op = errors.Op("jujuapi.ListWebhookSubscriptions")
+
+	subscriptions, err := r.jimm.ListWebhookSubscriptions(ctx, r.user)
+	if err != nil {
+		return apiparams.ListWebhookSubscriptionsResponse{}, errors.E(op, err)
+	}
+	return apiparams.ListWebhookSubscriptionsResponse{
+		Subscriptions: subscriptions,
+	}, nil
+}
+
+// RemoveWebhookSubscription removes a webhook subscription.
+func (r *controllerRoot) RemoveWebhookSubscription(ctx context.Context, req apiparams.RemoveWebhookSubscriptionRequest) error {
+	const op = errors.Op("jujuapi.RemoveWebhookSubscription")
+
+	if err := r.jimm.RemoveWebhookSubscription(ctx, r.user, req.Name); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// ListWebhookDeadLetters returns the events that could not be delivered
+// to webhooks.
+func (r *controllerRoot) ListWebhookDeadLetters(ctx context.Context, req apiparams.ListWebhookDeadLettersRequest) (apiparams.ListWebhookDeadLettersResponse, error) {
+	const op = errors.Op("jujuapi.ListWebhookDeadLetters")
+
+	deadLetters, err := r.jimm.ListWebhookDeadLetters(ctx, r.user, req.Subscription)
+	if err != nil {
+		return apiparams.ListWebhookDeadLettersResponse{}, errors.E(op, err)
+	}
+	return apiparams.ListWebhookDeadLettersResponse{
+		DeadLetters: deadLetters,
+	}, nil
+}
+
+// RetryWebhookDeadLetter queues an event that could not be delivered to
+// be delivered again.
+func (r *controllerRoot) RetryWebhookDeadLetter(ctx context.Context, req apiparams.RetryWebhookDeadLetterRequest) error {
+	const op = errors.Op("jujuapi.RetryWebhookDeadLetter")
+
+	if err := r.jimm.RetryWebhookDeadLetter(ctx, r.user, req.ID); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// ListScheduledJobs returns the scheduled background jobs.
+func (r *controllerRoot) ListScheduledJobs(ctx context.Context) (apiparams.ListScheduledJobsResponse, error) {
+	const op = errors.Op("jujuapi.ListScheduledJobs")
+
+	jobs, err := r.jimm.ListScheduledJobs(ctx, r.user)
+	if err != nil {
+		return apiparams.ListScheduledJobsResponse{}, errors.E(op, err)
+	}
+	return apiparams.ListScheduledJobsResponse{
+		Jobs: jobs,
+	}, nil
+}
+
+// ListJobRuns returns the most recent runs of a scheduled job.
+func (r *controllerRoot) ListJobRuns(ctx context.Context, req apiparams.ListJobRunsRequest) (apiparams.ListJobRunsResponse, error) {
+	const op = errors.Op("jujuapi.ListJobRuns")
+
+	runs, err := r.jimm.ListJobRuns(ctx, r.user, req.Job, req.Limit)
+	if err != nil {
+		return apiparams.ListJobRunsResponse{}, errors.E(op, err)
+	}
+	return apiparams.ListJobRunsResponse{
+		Runs: runs,
+	}, nil
+}
+
+// TriggerScheduledJob requests that a scheduled job is run immediately.
+func (r *controllerRoot) TriggerScheduledJob(ctx context.Context, req apiparams.TriggerScheduledJobRequest) error {
+	const op = errors.Op("jujuapi.TriggerScheduledJob")
+
+	if err := r.jimm.TriggerScheduledJob(ctx, r.user, req.Job); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// AddAlertReceiver adds a destination for alert notifications.
+func (r *controllerRoot) AddAlertReceiver(ctx context.Context, req apiparams.AddAlertReceiverRequest) (apiparams.AlertReceiver, error) {
+	const op = errors.Op("jujuapi.AddAlertReceiver")
+
+	receiver, err := r.jimm.AddAlertReceiver(ctx, r.user, req)
+	if err != nil {
+		return apiparams.AlertReceiver{}, errors.E(op, err)
+	}
+	return receiver, nil
+}
+
+// ListAlertReceivers returns all the alert receivers.
+func (r *controllerRoot) ListAlertReceivers(ctx context.Context) (apiparams.ListAlertReceiversResponse, error) {
+	const op = errors.Op("jujuapi.ListAlertReceivers")
+
+	receivers, err := r.jimm.ListAlertReceivers(ctx, r.user)
+	if err != nil {
+		return apiparams.ListAlertReceiversResponse{}, errors.E(op, err)
+	}
+	return apiparams.ListAlertReceiversResponse{
+		Receivers: receivers,
+	}, nil
+}
+
+// RemoveAlertReceiver removes an alert receiver.
+func (r *controllerRoot) RemoveAlertReceiver(ctx context.Context, req apiparams.RemoveAlertReceiverRequest) error {
+	const op = errors.Op("jujuapi.RemoveAlertReceiver")
+
+	if err := r.jimm.RemoveAlertReceiver(ctx, r.user, req.Name); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// AddAlertRule adds an alert rule.
+func (r *controllerRoot) AddAlertRule(ctx context.Context, req apiparams.AddAlertRuleRequest) (apiparams.AlertRule, error) {
+	const op = errors.Op("jujuapi.AddAlertRule")
+
+	rule, err := r.jimm.AddAlertRule(ctx, r.user, req)
+	if err != nil {
+		return apiparams.AlertRule{}, errors.E(op, err)
+	}
+	return rule, nil
+}
+
+// ListAlertRules returns all the alert rules.
+func (r *controllerRoot) ListAlertRules(ctx context.Context) (apiparams.ListAlertRulesResponse, error) {
+	const op = errors.Op("jujuapi.ListAlertRules")
+
+	rules, err := r.jimm.ListAlertRules(ctx, r.user)
+	if err != nil {
+		return apiparams.ListAlertRulesResponse{}, errors.E(op, err)
+	}
+	return apiparams.ListAlertRulesResponse{
+		Rules: rules,
+	}, nil
+}
+
+// RemoveAlertRule removes an alert rule.
+func (r *controllerRoot) RemoveAlertRule(ctx context.Context, req apiparams.RemoveAlertRuleRequest) error {
+	const op = errors.Op("jujuapi.RemoveAlertRule")
+
+	if err := r.jimm.RemoveAlertRule(ctx, r.user, req.Name); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// ListAlerts returns the alerts that are pending or firing.
+func (r *controllerRoot) ListAlerts(ctx context.Context) (apiparams.ListAlertsResponse, error) {
+	const op = errors.Op("jujuapi.ListAlerts")
+
+	alerts, err := r.jimm.ListAlerts(ctx, r.user)
+	if err != nil {
+		return apiparams.ListAlertsResponse{}, errors.E(op, err)
+	}
+	return apiparams.ListAlertsResponse{
+		Alerts: alerts,
+	}, nil
+}
+
+// AddAlertSilence silences notifications for matching alerts.
+func (r *controllerRoot) AddAlertSilence(ctx context.Context, req apiparams.AddAlertSilenceRequest) (apiparams.AlertSilence, error) {
+	const op = errors.Op("jujuapi.AddAlertSilence")
+
+	silence, err := r.jimm.AddAlertSilence(ctx, r.user, req)
+	if err != nil {
+		return apiparams.AlertSilence{}, errors.E(op, err)
+	}
+	return silence, nil
+}
+
+// ListAlertSilences returns the alert silences that have not ended.
+func (r *controllerRoot) ListAlertSilences(ctx context.Context) (apiparams.ListAlertSilencesResponse, error) {
+	const op = errors.Op("jujuapi.ListAlertSilences")
+
+	silences, err := r.jimm.ListAlertSilences(ctx, r.user)
+	if err != nil {
+		return apiparams.ListAlertSilencesResponse{}, errors.E(op, err)
+	}
+	return apiparams.ListAlertSilencesResponse{
+		Silences: silences,
+	}, nil
+}
+
+// RemoveAlertSilence removes an alert silence.
+func (r *controllerRoot) RemoveAlertSilence(ctx context.Context, req apiparams.RemoveAlertSilenceRequest) error {
+	const op = errors.Op("jujuapi.RemoveAlertSilence")
+
+	if err := r.jimm.RemoveAlertSilence(ctx, r.user, req.ID); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
 // Version is a method on the JIMM facade that returns information on the version of JIMM.
 func (r *controllerRoot) Version(ctx context.Context) (apiparams.VersionResponse, error) {
 	versionInfo := apiparams.VersionResponse{
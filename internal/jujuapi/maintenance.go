@@ -0,0 +1,129 @@
+// Copyright 2024 Canonical.
+
+package jujuapi
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juju/zaputil/zapctx"
+	"go.uber.org/zap"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/jimm"
+)
+
+// maintenanceRefreshInterval is the time after which a maintenanceChecker
+// re-reads the maintenance state of its controller.
+const maintenanceRefreshInterval = 10 * time.Second
+
+// A maintenanceChecker refuses calls proxied to a model while the model's
+// controller is in maintenance. If the maintenance allows read-only
+// access calls that do not change the model are still allowed.
+type maintenanceChecker struct {
+	// getController reads the current state of the controller.
+	getController func(context.Context, *dbmodel.Controller) error
+
+	mu         sync.Mutex
+	controller dbmodel.Controller
+	checked    time.Time
+	now        func() time.Time
+}
+
+// newMaintenanceChecker returns a maintenanceChecker that reads the
+// controller state using the given function.
+func newMaintenanceChecker(getController func(context.Context, *dbmodel.Controller) error) *maintenanceChecker {
+	return &maintenanceChecker{
+		getController: getController,
+		now:           time.Now,
+	}
+}
+
+// connect checks whether a new connection may be made to a model on the
+// given controller. Connections are refused while the controller is in
+// maintenance, unless read-only access is allowed.
+func (c *maintenanceChecker) connect(ctl *dbmodel.Controller) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.controller = *ctl
+	c.checked = c.now()
+	if ctl.Maintenance.AllowReadOnly {
+		return nil
+	}
+	return jimm.ControllerMaintenanceError(ctl)
+}
+
+// checkCall implements the rpc.ProxyHelpers CheckCall function.
+func (c *maintenanceChecker) checkCall(ctx context.Context, facade, method string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.controller.Name == "" {
+		// Not connected to a controller.
+		return nil
+	}
+	if c.now().Sub(c.checked) >= maintenanceRefreshInterval {
+		ctl := dbmodel.Controller{Name: c.controller.Name}
+		if err := c.getController(ctx, &ctl); err != nil {
+			zapctx.Error(ctx, "cannot check controller maintenance", zap.String("controller", ctl.Name), zap.Error(err))
+		} else {
+			c.controller = ctl
+			c.checked = c.now()
+		}
+	}
+	err := jimm.ControllerMaintenanceError(&c.controller)
+	if err == nil || (c.controller.Maintenance.AllowReadOnly && readOnlyCall(facade, method)) {
+		return nil
+	}
+	return err
+}
+
+// readOnlyFacades are the facades on which every method is read-only.
+var readOnlyFacades = map[string]bool{
+	"AllModelWatcher":       true,
+	"AllWatcher":            true,
+	"ModelSummaryWatcher":   true,
+	"NotifyWatcher":         true,
+	"Pinger":                true,
+	"RelationStatusWatcher": true,
+	"StringsWatcher":        true,
+}
+
+// readOnlyMethods are the methods, on any facade, that are read-only.
+var readOnlyMethods = map[string]bool{
+	"CharmInfo":   true,
+	"Info":        true,
+	"ModelGet":    true,
+	"ModelInfo":   true,
+	"ModelStatus": true,
+	"Next":        true,
+	"Ping":        true,
+	"Stop":        true,
+}
+
+// readOnlyPrefixes are the prefixes of method names that are read-only.
+var readOnlyPrefixes = []string{
+	"Find",
+	"FullStatus",
+	"Get",
+	"List",
+	"Read",
+	"Show",
+	"Status",
+	"Watch",
+}
+
+// readOnlyCall returns whether the given facade method only reads the
+// state of a model.
+func readOnlyCall(facade, method string) bool {
+	if readOnlyFacades[facade] || readOnlyMethods[method] {
+		return true
+	}
+	for _, p := range readOnlyPrefixes {
+		if strings.HasPrefix(method, p) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+// Copyright 2024 Canonical.
+
+package jujuapi
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+func TestMaintenanceChecker(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	now := time.Now()
+	current := dbmodel.Controller{Name: "controller-1"}
+	checker := newMaintenanceChecker(func(_ context.Context, ctl *dbmodel.Controller) error {
+		c.Check(ctl.Name, qt.Equals, "controller-1")
+		*ctl = current
+		return nil
+	})
+	checker.now = func() time.Time { return now }
+
+	// Calls are allowed before the controller is connected.
+	c.Check(checker.checkCall(ctx, "Application", "Deploy"), qt.IsNil)
+
+	c.Assert(checker.connect(&current), qt.IsNil)
+	c.Check(checker.checkCall(ctx, "Application", "Deploy"), qt.IsNil)
+
+	// The maintenance state is only re-read after the refresh interval.
+	current.Maintenance = dbmodel.ControllerMaintenance{
+		Enabled:       true,
+		Message:       "upgrading to 3.6",
+		AllowReadOnly: true,
+	}
+	c.Check(checker.checkCall(ctx, "Application", "Deploy"), qt.IsNil)
+	now = now.Add(maintenanceRefreshInterval)
+	err := checker.checkCall(ctx, "Application", "Deploy")
+	c.Check(err, qt.ErrorMatches, `controller "controller-1" is in maintenance: upgrading to 3.6`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeControllerMaintenance)
+	c.Check(checker.checkCall(ctx, "Client", "FullStatus"), qt.IsNil)
+	c.Check(checker.checkCall(ctx, "AllWatcher", "Next"), qt.IsNil)
+
+	// Without read-only access no call is allowed, and new connections
+	// are refused.
+	current.Maintenance.AllowReadOnly = false
+	current.Maintenance.EndsAt = sql.NullTime{Time: time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC), Valid: true}
+	now = now.Add(maintenanceRefreshInterval)
+	c.Check(checker.checkCall(ctx, "Client", "FullStatus"), qt.ErrorMatches, `controller "controller-1" is in maintenance: upgrading to 3.6 \(expected to end at 2030-01-02T03:04:05Z\)`)
+	c.Check(checker.connect(&current), qt.ErrorMatches, `controller "controller-1" is in maintenance: .*`)
+
+	// Maintenance that has ended no longer refuses calls.
+	current.Maintenance.EndsAt = sql.NullTime{Time: time.Now().Add(-time.Minute), Valid: true}
+	now = now.Add(maintenanceRefreshInterval)
+	c.Check(checker.checkCall(ctx, "Application", "Deploy"), qt.IsNil)
+}
+
+func TestReadOnlyCall(t *testing.T) {
+	c := qt.New(t)
+
+	for _, call := range [][2]string{
+		{"Client", "FullStatus"},
+		{"Application", "Get"},
+		{"ModelConfig", "ModelGet"},
+		{"Pinger", "Ping"},
+		{"AllWatcher", "Next"},
+		{"Action", "ListOperations"},
+	} {
+		c.Check(readOnlyCall(call[0], call[1]), qt.IsTrue, qt.Commentf("%s.%s", call[0], call[1]))
+	}
+	for _, call := range [][2]string{
+		{"Application", "Deploy"},
+		{"Application", "SetConfigs"},
+		{"ModelConfig", "ModelSet"},
+		{"Action", "EnqueueOperation"},
+	} {
+		c.Check(readOnlyCall(call[0], call[1]), qt.IsFalse, qt.Commentf("%s.%s", call[0], call[1]))
+	}
+}
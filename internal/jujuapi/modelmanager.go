@@ -71,9 +71,9 @@ type ModelManager interface {
 	ImportModel(ctx context.Context, user *openfga.User, controllerName string, modelTag names.ModelTag, newOwner string) error
 	ModelDefaultsForCloud(ctx context.Context, user *dbmodel.Identity, cloudTag names.CloudTag) (jujuparams.ModelDefaultsResult, error)
 	ModelInfo(ctx context.Context, u *openfga.User, mt names.ModelTag) (*jujuparams.ModelInfo, error)
-	ListModelSummaries(ctx context.Context, user *openfga.User, maskingControllerUUID string) (jujuparams.ModelSummaryResults, error)
+	ListModelSummaries(ctx context.Context, user *openfga.User, maskingControllerUUID string, labelSelector dbmodel.LabelSelector) (jujuparams.ModelSummaryResults, error)
 	ModelStatus(ctx context.Context, u *openfga.User, mt names.ModelTag) (*jujuparams.ModelStatus, error)
-	QueryModelsJq(ctx context.Context, models []string, jqQuery string) (params.CrossModelQueryResponse, error)
+	QueryModelsJq(ctx context.Context, models []string, jqQuery string, labelSelector dbmodel.LabelSelector) (params.CrossModelQueryResponse, error)
 	SetModelDefaults(ctx context.Context, user *dbmodel.Identity, cloudTag names.CloudTag, region string, configs map[string]interface{}) error
 	UnsetModelDefaults(ctx context.Context, user *dbmodel.Identity, cloudTag names.CloudTag, region string, keys []string) error
 	UpdateMigratedModel(ctx context.Context, user *openfga.User, modelTag names.ModelTag, targetControllerName string) error
@@ -113,7 +113,7 @@ func (r *controllerRoot) ListModelSummaries(ctx context.Context, _ jujuparams.Mo
 	if r.controllerUUIDMasking {
 		maskingControllerUUID = r.params.ControllerUUID
 	}
-	res, err := r.jimm.ListModelSummaries(ctx, r.user, maskingControllerUUID)
+	res, err := r.jimm.ListModelSummaries(ctx, r.user, maskingControllerUUID, nil)
 	if err != nil {
 		return jujuparams.ModelSummaryResults{}, errors.E(op, err)
 	}
@@ -127,9 +127,15 @@ func (r *controllerRoot) ListModels(ctx context.Context, _ jujuparams.Entity) (j
 	const op = errors.Op("jujuapi.ListModels")
 	zapctx.Info(ctx, string(op))
 
+	return r.listModels(ctx, nil)
+}
+
+// listModels returns the models that the authenticated user has access to
+// and that have labels matching the given selector.
+func (r *controllerRoot) listModels(ctx context.Context, labelSelector dbmodel.LabelSelector) (jujuparams.UserModelList, error) {
 	res := jujuparams.UserModelList{}
 
-	models, err := r.jimm.ListModels(ctx, r.user)
+	models, err := r.jimm.ListModels(ctx, r.user, labelSelector)
 	if err != nil {
 		return res, err
 	}
@@ -10,7 +10,11 @@ import (
 
 	"github.com/juju/rpcreflect"
 	"github.com/juju/zaputil/zapctx"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
 	"go.uber.org/zap"
+
+	"github.com/canonical/jimm/v3/internal/servermon"
 )
 
 // A Root provides the root of an RPC server connection.
@@ -107,12 +111,20 @@ type rootMethodCaller struct {
 	version    int
 }
 
-// Call implements rpcreflect.MethodCaller.Call.
-func (c rootMethodCaller) Call(ctx context.Context, objID string, arg reflect.Value) (reflect.Value, error) {
+// Call implements rpcreflect.MethodCaller.Call. Each call is traced in
+// its own span.
+func (c rootMethodCaller) Call(ctx context.Context, objID string, arg reflect.Value) (_ reflect.Value, err error) {
 	ctx, callID := c.r.start(ctx)
 	defer c.r.end(callID)
 	ctx = zapctx.WithFields(ctx, zap.String("facade", c.facadeName))
 	ctx = zapctx.WithFields(ctx, zap.String("method", c.methodName))
 	ctx = zapctx.WithFields(ctx, zap.Int("version", c.version))
+	ctx, span := servermon.StartSpan(ctx, c.facadeName+"."+c.methodName,
+		semconv.RPCSystemKey.String("jujurpc"),
+		semconv.RPCService(c.facadeName),
+		semconv.RPCMethod(c.methodName),
+		attribute.Int("rpc.jujurpc.version", c.version),
+	)
+	defer servermon.EndSpan(span, &err)
 	return c.MethodCaller.Call(ctx, objID, arg)
 }
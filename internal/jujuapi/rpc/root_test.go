@@ -13,8 +13,12 @@ import (
 	qt "github.com/frankban/quicktest"
 	jujurpc "github.com/juju/juju/rpc"
 	"github.com/juju/juju/rpc/jsoncodec"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 
+	"github.com/canonical/jimm/v3/internal/errors"
 	"github.com/canonical/jimm/v3/internal/jujuapi/rpc"
+	"github.com/canonical/jimm/v3/internal/testutils/jimmtest"
 )
 
 func TestRPC(t *testing.T) {
@@ -100,6 +104,49 @@ func TestKill(t *testing.T) {
 	c.Check(atomic.LoadInt32(&ended), qt.Equals, int32(2))
 }
 
+func TestCallSpans(t *testing.T) {
+	c := qt.New(t)
+	sr := jimmtest.RecordSpans(c)
+
+	cl, srv := pipe()
+	c.Cleanup(func() {
+		if err := cl.Close(); err != nil {
+			c.Logf("error closing RPC connection: %s", err)
+		}
+	})
+
+	r := new(rpc.Root)
+	srv.ServeRoot(r, nil, nil)
+	r.AddMethod("Calc", 1, "Add", rpc.Method(add))
+	r.AddMethod("Calc", 1, "Fail", rpc.Method(func(context.Context) error {
+		return errors.E("failed")
+	}))
+
+	var res AddResult
+	err := cl.Call(jujurpc.Request{Type: "Calc", Version: 1, Action: "Add"}, AddRequest{A: 1, B: 2}, &res)
+	c.Assert(err, qt.IsNil)
+	err = cl.Call(jujurpc.Request{Type: "Calc", Version: 1, Action: "Fail"}, nil, nil)
+	c.Assert(err, qt.ErrorMatches, `failed`)
+
+	spans := sr.Ended()
+	c.Assert(spans, qt.HasLen, 2)
+	c.Check(spans[0].Name(), qt.Equals, "Calc.Add")
+	attrs := make(map[attribute.Key]string)
+	for _, kv := range spans[0].Attributes() {
+		attrs[kv.Key] = kv.Value.Emit()
+	}
+	c.Check(attrs, qt.DeepEquals, map[attribute.Key]string{
+		"rpc.system":          "jujurpc",
+		"rpc.service":         "Calc",
+		"rpc.method":          "Add",
+		"rpc.jujurpc.version": "1",
+	})
+	c.Check(spans[0].Status().Code, qt.Equals, codes.Unset)
+	c.Check(spans[1].Name(), qt.Equals, "Calc.Fail")
+	c.Check(spans[1].Status().Code, qt.Equals, codes.Error)
+	c.Check(spans[1].Status().Description, qt.Equals, "failed")
+}
+
 func pipe() (*jujurpc.Conn, *jujurpc.Conn) {
 	c1, c2 := net.Pipe()
 	rpc1 := jujurpc.NewConn(jsoncodec.NewNet(c1), nil)
@@ -94,9 +94,10 @@ func (s streamProxier) ServeWS(ctx context.Context, clientConn *websocket.Conn)
 		writeError(fmt.Sprintf("failed to dial controller: %s", err.Error()), errors.CodeConnectionFailed)
 		return
 	}
-	defer api.Close()
-
 	controllerStream, err := api.ConnectStream(finalPath, nil)
+	// The stream has its own connection, so the API connection can be
+	// returned to the pool whilst the stream is proxied.
+	api.Close()
 	if err != nil {
 		zapctx.Error(ctx, "failed to connect stream", zap.Error(err))
 		writeError(fmt.Sprintf("failed to connect stream: %s", err.Error()), errors.CodeConnectionFailed)
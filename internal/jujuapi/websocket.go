@@ -5,14 +5,18 @@ package jujuapi
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	stderrors "errors"
 	"net/http"
 	"regexp"
+	"sync"
 	"time"
 
 	"github.com/gorilla/websocket"
 	"github.com/juju/juju/rpc"
 	"github.com/juju/juju/rpc/jsoncodec"
 	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
 	"github.com/juju/zaputil"
 	"github.com/juju/zaputil/zapctx"
 	"go.uber.org/zap"
@@ -132,10 +136,17 @@ func mapError(err error) *jujuparams.Error {
 	// TODO the error mapper should really accept a context from the RPC package.
 	zapctx.Debug(context.TODO(), "rpc error", zaputil.Error(err))
 
-	return &jujuparams.Error{
+	perr := &jujuparams.Error{
 		Message: err.Error(),
 		Code:    string(errors.ErrorCode(err)),
 	}
+	// Errors that carry structured information, such as model policy
+	// violations, pass it on to the client.
+	var infoErr interface{ ErrorInfo() map[string]interface{} }
+	if stderrors.As(err, &infoErr) {
+		perr.Info = infoErr.ErrorInfo()
+	}
+	return perr
 }
 
 // apiProxier serves the /commands and /api server for a model by
@@ -174,9 +185,15 @@ func modelInfoFromPath(path string) (uuid string, finalPath string, err error) {
 // requests to the appropriate Juju controller.
 func (s apiProxier) ServeWS(ctx context.Context, clientConn *websocket.Conn) {
 	jwtGenerator := jujuauth.New(s.jimm.Database, s.jimm, s.jimm.JWTService)
-	connectionFunc := controllerConnectionFunc(s, &jwtGenerator)
+	maintenance := newMaintenanceChecker(s.jimm.Database.GetController)
+	connectionFunc := controllerConnectionFunc(s, &jwtGenerator, maintenance)
 	zapctx.Debug(ctx, "Starting proxier")
-	auditLogger := s.jimm.AddAuditLogEntry
+	modelUUID, _, _ := modelInfoFromPath(jimmhttp.PathElementFromContext(ctx, "path"))
+	consumptions := newOfferConsumptionRecorder(s.jimm, modelUUID)
+	auditLogger := func(ale *dbmodel.AuditLogEntry) {
+		s.jimm.AddAuditLogEntry(ale)
+		consumptions.record(ctx, ale)
+	}
 	proxyHelpers := jimmRPC.ProxyHelpers{
 		ConnClient:              clientConn,
 		TokenGen:                &jwtGenerator,
@@ -184,15 +201,105 @@ func (s apiProxier) ServeWS(ctx context.Context, clientConn *websocket.Conn) {
 		AuditLog:                auditLogger,
 		LoginService:            s.jimm,
 		AuthenticatedIdentityID: auth.SessionIdentityFromContext(ctx),
+		CheckCall:               maintenance.checkCall,
 	}
 	if err := jimmRPC.ProxySockets(ctx, proxyHelpers); err != nil {
 		zapctx.Error(ctx, "failed to start jimm model proxy", zap.Error(err))
 	}
 }
 
+// An offerConsumptionRecorder records the application offers consumed by
+// Application.Consume calls proxied to a model. Requests are held until
+// the controller responds so that only successful consumptions are
+// recorded.
+type offerConsumptionRecorder struct {
+	jimm      JIMM
+	modelUUID string
+
+	mu      sync.Mutex
+	pending map[uint64]consumeRequest
+}
+
+// A consumeRequest is an Application.Consume request awaiting its
+// response.
+type consumeRequest struct {
+	identityName string
+	args         []jujuparams.ConsumeApplicationArgV5
+}
+
+// newOfferConsumptionRecorder returns an offerConsumptionRecorder for
+// calls proxied to the model with the given UUID.
+func newOfferConsumptionRecorder(j JIMM, modelUUID string) *offerConsumptionRecorder {
+	return &offerConsumptionRecorder{
+		jimm:      j,
+		modelUUID: modelUUID,
+		pending:   make(map[uint64]consumeRequest),
+	}
+}
+
+// record processes the audit log entry of a proxied message. Consume
+// requests are held until their response, each offer in the request is
+// then recorded unless the controller returned an error for it.
+func (r *offerConsumptionRecorder) record(ctx context.Context, ale *dbmodel.AuditLogEntry) {
+	if !ale.IsResponse {
+		if ale.FacadeName != "Application" || ale.FacadeMethod != "Consume" {
+			return
+		}
+		ut, err := names.ParseUserTag(ale.IdentityTag)
+		if err != nil {
+			return
+		}
+		var args jujuparams.ConsumeApplicationArgsV5
+		if err := json.Unmarshal(ale.Params, &args); err != nil {
+			zapctx.Error(ctx, "cannot parse consume request", zap.Error(err))
+			return
+		}
+		r.mu.Lock()
+		r.pending[ale.MessageId] = consumeRequest{identityName: ut.Id(), args: args.Args}
+		r.mu.Unlock()
+		return
+	}
+
+	r.mu.Lock()
+	req, ok := r.pending[ale.MessageId]
+	delete(r.pending, ale.MessageId)
+	r.mu.Unlock()
+	if !ok {
+		return
+	}
+	// The response errors hold the result of each argument followed by
+	// the error for the call as a whole.
+	var results jujuparams.ErrorResults
+	if err := json.Unmarshal(ale.Errors, &results); err != nil {
+		zapctx.Error(ctx, "cannot parse consume response", zap.Error(err))
+		return
+	}
+	if n := len(results.Results); n > 0 {
+		if callErr := results.Results[n-1].Error; callErr != nil && callErr.Message != "" {
+			return
+		}
+		results.Results = results.Results[:n-1]
+	}
+	for i, arg := range req.args {
+		if i < len(results.Results) && results.Results[i].Error != nil {
+			continue
+		}
+		err := r.jimm.RecordApplicationOfferConsumed(ctx, req.identityName, r.modelUUID, arg.OfferUUID, arg.OfferURL)
+		if errors.ErrorCode(err) == errors.CodeNotFound {
+			// The offer is not managed by JIMM.
+			continue
+		}
+		if err != nil {
+			zapctx.Error(ctx, "cannot record offer consumption", zap.String("offer", arg.OfferURL), zap.Error(err))
+		}
+	}
+}
+
 // controllerConnectionFunc returns a function that will be used to
-// connect to a controller when a client makes a request.
-func controllerConnectionFunc(s apiProxier, jwtGenerator *jujuauth.TokenGenerator) func(context.Context) (jimmRPC.WebsocketConnectionWithMetadata, error) {
+// connect to a controller when a client makes a request. Connections to
+// models on a controller in maintenance are refused by the given
+// maintenanceChecker.
+func controllerConnectionFunc(s apiProxier, jwtGenerator *jujuauth.TokenGenerator, maintenance *maintenanceChecker) func(context.Context) (jimmRPC.WebsocketConnectionWithMetadata, error) {
 	return func(ctx context.Context) (jimmRPC.WebsocketConnectionWithMetadata, error) {
 		const op = errors.Op("proxy.controllerConnectionFunc")
 		path := jimmhttp.PathElementFromContext(ctx, "path")
@@ -212,6 +319,10 @@ func controllerConnectionFunc(s apiProxier, jwtGenerator *jujuauth.TokenGenerato
 			zapctx.Error(ctx, "failed to find model", zap.String("uuid", uuid), zap.Error(err))
 			return jimmRPC.WebsocketConnectionWithMetadata{}, errors.E(err, errors.CodeNotFound)
 		}
+		if err := maintenance.connect(&m.Controller); err != nil {
+			zapctx.Info(ctx, "refusing connection to controller in maintenance", zap.String("controller", m.Controller.Name))
+			return jimmRPC.WebsocketConnectionWithMetadata{}, errors.E(op, err)
+		}
 		jwtGenerator.SetTags(m.ResourceTag(), m.Controller.ResourceTag())
 		mt := m.ResourceTag()
 		zapctx.Debug(ctx, "Dialing Controller", zap.String("path", path))
@@ -6,6 +6,7 @@ import (
 	"bytes"
 	"context"
 	"crypto/tls"
+	"encoding/json"
 	"encoding/pem"
 	"fmt"
 	"net/http"
@@ -22,6 +23,7 @@ import (
 	gc "gopkg.in/check.v1"
 
 	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
 	"github.com/canonical/jimm/v3/internal/jimmhttp"
 	"github.com/canonical/jimm/v3/internal/jujuapi"
 	"github.com/canonical/jimm/v3/internal/openfga"
@@ -319,3 +321,100 @@ func (s *pathTestSuite) Test(c *gc.C) {
 		}
 	}
 }
+
+type recordOfferConsumptionSuite struct{}
+
+var _ = gc.Suite(&recordOfferConsumptionSuite{})
+
+func (s *recordOfferConsumptionSuite) TestRecordOfferConsumption(c *gc.C) {
+	const modelUUID = "059744f6-26d2-4f00-92be-5df97fccbb97"
+	var recorded []string
+	j := &jimmtest.JIMM{
+		RecordApplicationOfferConsumed_: func(ctx context.Context, identityName, muuid, offerUUID, offerURL string) error {
+			c.Check(muuid, gc.Equals, modelUUID)
+			recorded = append(recorded, identityName+" "+offerURL)
+			if offerURL == "bob@canonical.com/model.unknown" {
+				return errors.E(errors.CodeNotFound)
+			}
+			return nil
+		},
+	}
+	params, err := json.Marshal(jujuparams.ConsumeApplicationArgsV5{
+		Args: []jujuparams.ConsumeApplicationArgV5{{
+			ApplicationOfferDetailsV5: jujuparams.ApplicationOfferDetailsV5{
+				OfferURL: "bob@canonical.com/model.db",
+			},
+		}, {
+			ApplicationOfferDetailsV5: jujuparams.ApplicationOfferDetailsV5{
+				OfferURL: "bob@canonical.com/model.unknown",
+			},
+		}},
+	})
+	c.Assert(err, gc.IsNil)
+
+	errs, err := json.Marshal(jujuparams.ErrorResults{Results: []jujuparams.ErrorResult{
+		{},
+		{},
+		{Error: &jujuparams.Error{}},
+	}})
+	c.Assert(err, gc.IsNil)
+
+	ctx := context.Background()
+	record := jujuapi.NewOfferConsumptionRecorder(j, modelUUID)
+	req := dbmodel.AuditLogEntry{
+		MessageId:    1,
+		IdentityTag:  names.NewUserTag("alice@canonical.com").String(),
+		FacadeName:   "Application",
+		FacadeMethod: "Consume",
+		Params:       params,
+	}
+	resp := dbmodel.AuditLogEntry{
+		MessageId:   1,
+		IdentityTag: req.IdentityTag,
+		Errors:      errs,
+		IsResponse:  true,
+	}
+	// Nothing is recorded until the controller responds.
+	record(ctx, &req)
+	c.Check(recorded, gc.HasLen, 0)
+	record(ctx, &resp)
+	c.Check(recorded, gc.DeepEquals, []string{
+		"alice@canonical.com bob@canonical.com/model.db",
+		"alice@canonical.com bob@canonical.com/model.unknown",
+	})
+
+	// A response is only handled once.
+	recorded = nil
+	record(ctx, &resp)
+	c.Check(recorded, gc.HasLen, 0)
+
+	// Offers the controller failed to consume are not recorded.
+	resp.Errors, err = json.Marshal(jujuparams.ErrorResults{Results: []jujuparams.ErrorResult{
+		{Error: &jujuparams.Error{Message: "permission denied"}},
+		{},
+		{Error: &jujuparams.Error{}},
+	}})
+	c.Assert(err, gc.IsNil)
+	record(ctx, &req)
+	record(ctx, &resp)
+	c.Check(recorded, gc.DeepEquals, []string{
+		"alice@canonical.com bob@canonical.com/model.unknown",
+	})
+
+	// Nothing is recorded when the call fails.
+	recorded = nil
+	resp.Errors, err = json.Marshal(jujuparams.ErrorResults{Results: []jujuparams.ErrorResult{
+		{Error: &jujuparams.Error{Message: "facade not supported"}},
+	}})
+	c.Assert(err, gc.IsNil)
+	record(ctx, &req)
+	record(ctx, &resp)
+	c.Check(recorded, gc.HasLen, 0)
+
+	// Other methods are ignored.
+	req.FacadeMethod = "Deploy"
+	resp.Errors = errs
+	record(ctx, &req)
+	record(ctx, &resp)
+	c.Check(recorded, gc.HasLen, 0)
+}
@@ -24,6 +24,8 @@ import (
 	jujuparams "github.com/juju/juju/rpc/params"
 	"github.com/juju/names/v5"
 	"github.com/juju/zaputil/zapctx"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
 	"go.uber.org/zap"
 	"gopkg.in/httprequest.v1"
 
@@ -83,15 +85,21 @@ func (d *Dialer) createLoginRequest(ctx context.Context, ctl *dbmodel.Controller
 }
 
 // Dial implements jimm.Dialer.
-func (d *Dialer) Dial(ctx context.Context, ctl *dbmodel.Controller, modelTag names.ModelTag, requiredPermissions map[string]string) (jimm.API, error) {
+func (d *Dialer) Dial(ctx context.Context, ctl *dbmodel.Controller, modelTag names.ModelTag, requiredPermissions map[string]string) (_ jimm.API, err error) {
 	const op = errors.Op("jujuclient.Dial")
+	ctx, span := servermon.StartSpan(ctx, string(op),
+		attribute.String("jimm.controller.name", ctl.Name),
+		attribute.String("jimm.controller.uuid", ctl.UUID),
+		attribute.String("jimm.model.uuid", modelTag.Id()),
+	)
+	defer servermon.EndSpan(span, &err)
 
 	conn, err := rpc.Dial(ctx, ctl, modelTag, "", nil)
 	if err != nil {
-		return nil, err
+		return nil, errors.E(op, errors.CodeConnectionFailed, &jimm.TransportError{Err: err})
 	}
 	if conn == nil {
-		return nil, errors.E(op, errors.CodeConnectionFailed, err)
+		return nil, errors.E(op, errors.CodeConnectionFailed, &jimm.TransportError{Err: errors.E("no connection")})
 	}
 	client := rpc.NewClient(conn)
 
@@ -257,6 +265,14 @@ func (c *Connection) Call(ctx context.Context, facade string, version int, id, m
 	durationObserver := servermon.DurationObserver(servermon.JujuCallDurationHistogram, labels...)
 	defer durationObserver()
 	defer servermon.ErrorCounter(servermon.JujuCallErrorCount, &err, labels...)
+	ctx, span := servermon.StartSpan(ctx, "juju."+facade+"."+method,
+		semconv.RPCSystemKey.String("jujurpc"),
+		semconv.RPCService(facade),
+		semconv.RPCMethod(method),
+		attribute.Int("rpc.jujurpc.version", version),
+		attribute.String("jimm.controller.uuid", labels[2]),
+	)
+	defer servermon.EndSpan(span, &err)
 
 	err = c.client.Call(ctx, facade, version, id, method, args, resp)
 	if err != nil {
@@ -11,6 +11,7 @@ import (
 	"github.com/juju/juju/api/client/modelmanager"
 	jujuparams "github.com/juju/juju/rpc/params"
 	"github.com/juju/names/v5"
+	"github.com/juju/version/v2"
 
 	"github.com/canonical/jimm/v3/internal/errors"
 )
@@ -249,6 +250,30 @@ func (c Connection) ValidateModelUpgrade(ctx context.Context, model names.ModelT
 	return nil
 }
 
+// UpgradeModel starts upgrading the agents of the given model to the
+// given version. If the target version is zero the controller chooses
+// the version to upgrade to. If dryRun is true the upgrade is checked but
+// not started. The version chosen by the controller is returned. This
+// uses UpgradeModel on the ModelUpgrader facade.
+func (c Connection) UpgradeModel(ctx context.Context, model names.ModelTag, targetVersion version.Number, stream string, dryRun bool) (version.Number, error) {
+	const op = errors.Op("jujuclient.UpgradeModel")
+	args := jujuparams.UpgradeModelParams{
+		ModelTag:      model.String(),
+		TargetVersion: targetVersion,
+		AgentStream:   stream,
+		DryRun:        dryRun,
+	}
+	var resp jujuparams.UpgradeModelResult
+	err := c.Call(ctx, "ModelUpgrader", 1, "", "UpgradeModel", &args, &resp)
+	if err != nil {
+		return version.Zero, errors.E(op, jujuerrors.Cause(err))
+	}
+	if resp.Error != nil {
+		return version.Zero, errors.E(op, resp.Error)
+	}
+	return resp.ChosenVersion, nil
+}
+
 // DestroyModel starts the destruction of the given model. This method uses
 // the highest available method from:
 //
@@ -13,6 +13,10 @@ import (
 	"go.uber.org/zap/zapcore"
 )
 
+// level is the level of the logger configured by SetupLogger, it can be
+// changed at any time using SetLogLevel.
+var level = zap.NewAtomicLevel()
+
 // SetupLogger sets up the default logger.
 // The local logger is a colorized plain text logger.
 // The production logger is a JSON structured logger.
@@ -22,6 +26,7 @@ func SetupLogger(ctx context.Context, logLevel string, devMode bool) {
 		fmt.Printf("ERROR: log level %q cannot be parsed, defaulting to info\n", logLevel)
 		pLogLevel = zap.InfoLevel
 	}
+	level.SetLevel(pLogLevel)
 	if devMode {
 		devConfig := zap.NewDevelopmentEncoderConfig()
 		devConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
@@ -29,17 +34,35 @@ func SetupLogger(ctx context.Context, logLevel string, devMode bool) {
 		developLogger := zap.New(zapcore.NewCore(
 			zapcore.NewConsoleEncoder(devConfig),
 			zapcore.AddSync(colorable.NewColorableStdout()),
-			pLogLevel,
+			level,
 		))
 		zapctx.Default = developLogger
 	} else {
 		prodConfig := zap.NewProductionConfig()
-		prodConfig.Level = zap.NewAtomicLevelAt(pLogLevel)
+		prodConfig.Level = level
 		proLogger := zap.Must(prodConfig.Build()) // this panics if an error is encountered during Build
 		zapctx.Default = proLogger
 	}
 }
 
+// SetLogLevel changes the level of the logger configured by SetupLogger.
+// Unlike SetupLogger an invalid level is an error and the current level is
+// left unchanged.
+func SetLogLevel(logLevel string) error {
+	pLogLevel, err := zapcore.ParseLevel(logLevel)
+	if err != nil {
+		return err
+	}
+	level.SetLevel(pLogLevel)
+	return nil
+}
+
+// LogLevel returns the current level of the logger configured by
+// SetupLogger.
+func LogLevel() string {
+	return level.Level().String()
+}
+
 // shortTimeEncoder encodes time as 15:04:05.000
 func shortTimeEncoder(t time.Time, enc zapcore.PrimitiveArrayEncoder) {
 	enc.AppendString(t.Format("15:04:05.000"))
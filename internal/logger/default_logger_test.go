@@ -65,3 +65,25 @@ func TestLogSetup(t *testing.T) {
 		})
 	}
 }
+
+func TestSetLogLevel(t *testing.T) {
+	ctx := context.Background()
+	logger.SetupLogger(ctx, "info", false)
+	if level := logger.LogLevel(); level != "info" {
+		t.Fatalf("unexpected log level %q", level)
+	}
+
+	if err := logger.SetLogLevel("debug"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if level := logger.LogLevel(); level != "debug" {
+		t.Errorf("unexpected log level %q", level)
+	}
+
+	if err := logger.SetLogLevel("not_existing_level"); err == nil {
+		t.Errorf("expected error setting invalid log level")
+	}
+	if level := logger.LogLevel(); level != "debug" {
+		t.Errorf("unexpected log level %q", level)
+	}
+}
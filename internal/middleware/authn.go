@@ -46,10 +46,12 @@ var unauthenticatedEndpoints = map[string]struct{}{
 }
 
 // AuthenticateRebac is a layer on top of AuthenticateViaCookie. It places the
-// OpenFGA user for the session identity inside the request's context and
-// verifies that the user is a JIMM admin. Note that the method needs the base
-// URL to decide if the request does not require authentication; this is to
-// safeguard against conflicting/similar endpoint names in the future.
+// OpenFGA user for the session identity inside the request's context.
+// Authorisation is left to the ReBAC Admin services, which allow JIMM admins
+// everything and group managers to manage the groups they manage. Note that
+// the method needs the base URL to decide if the request does not require
+// authentication; this is to safeguard against conflicting/similar endpoint
+// names in the future.
 func AuthenticateRebac(baseURL string, next http.Handler, jimm JIMMAuthner) http.Handler {
 	cookieAuthenticator := AuthenticateViaCookie(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
@@ -67,11 +69,6 @@ func AuthenticateRebac(baseURL string, next http.Handler, jimm JIMMAuthner) http
 			http.Error(w, "internal authentication error", http.StatusInternalServerError)
 			return
 		}
-		if !user.JimmAdmin {
-			w.WriteHeader(http.StatusUnauthorized)
-			_, _ = w.Write([]byte("user is not an admin"))
-			return
-		}
 
 		ctx = rebac_handlers.ContextWithIdentity(ctx, user)
 		next.ServeHTTP(w, r.WithContext(ctx))
@@ -65,7 +65,7 @@ func TestAuthenticateRebac(t *testing.T) {
 				return auth.ContextWithSessionIdentity(ctx, testUser), nil
 			},
 			jimmAdmin:      false,
-			expectedStatus: http.StatusUnauthorized,
+			expectedStatus: http.StatusOK,
 		},
 		{
 			name: "should skip auth for /swagger.json",
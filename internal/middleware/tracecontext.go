@@ -0,0 +1,19 @@
+// Copyright 2024 Canonical.
+
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+// TraceContext continues any W3C trace context sent with a request, so
+// that the spans started whilst handling the request, including the RPC
+// calls made over websocket connections, join the caller's trace.
+func TraceContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := servermon.ExtractTraceContext(r.Context(), r.Header)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
@@ -0,0 +1,39 @@
+// Copyright 2024 Canonical.
+
+package middleware_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/canonical/jimm/v3/internal/middleware"
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+func TestTraceContext(t *testing.T) {
+	c := qt.New(t)
+
+	_, err := servermon.SetupTracing(context.Background(), servermon.TracingParams{})
+	c.Assert(err, qt.IsNil)
+
+	var sc trace.SpanContext
+	h := middleware.TraceContext(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sc = trace.SpanContextFromContext(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/api", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	c.Check(sc.TraceID().String(), qt.Equals, "4bf92f3577b34da6a3ce929d0e0e4736")
+	c.Check(sc.SpanID().String(), qt.Equals, "00f067aa0ba902b7")
+	c.Check(sc.IsRemote(), qt.IsTrue)
+
+	req = httptest.NewRequest(http.MethodGet, "/api", nil)
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	c.Check(sc.IsValid(), qt.IsFalse)
+}
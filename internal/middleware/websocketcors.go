@@ -3,22 +3,30 @@ package middleware
 
 import (
 	"net/http"
+	"sync/atomic"
 
 	"github.com/rs/cors"
 )
 
 // WebsocketCors provides middleware for handling CORS on websockets.
 type WebsocketCors struct {
-	cors *cors.Cors
+	cors atomic.Pointer[cors.Cors]
 }
 
 // NewWebsocketCors returns a new WebsocketCors object.
 // If no allowedOrigins are provided, all origins will be allowed.
 func NewWebsocketCors(allowedOrigins []string) *WebsocketCors {
-	corsOpts := cors.New(cors.Options{
+	c := new(WebsocketCors)
+	c.SetAllowedOrigins(allowedOrigins)
+	return c
+}
+
+// SetAllowedOrigins replaces the origins that are allowed to connect.
+// Requests already in progress are unaffected.
+func (c *WebsocketCors) SetAllowedOrigins(allowedOrigins []string) {
+	c.cors.Store(cors.New(cors.Options{
 		AllowedOrigins: allowedOrigins,
-	})
-	return &WebsocketCors{cors: corsOpts}
+	}))
 }
 
 // Handler implements CORS validation for websocket handlers.
@@ -40,7 +48,7 @@ func (c *WebsocketCors) Handler(h http.Handler) http.Handler {
 		// Ignoring "http://localhost/" to support CLI clients that send this header.
 		// https://github.com/juju/juju/pull/18419
 		originPresent := r.Header.Get("Origin") != "" && r.Header.Get("Origin") != "http://localhost/"
-		if originPresent && !c.cors.OriginAllowed(r) {
+		if originPresent && !c.cors.Load().OriginAllowed(r) {
 			w.WriteHeader(http.StatusForbidden)
 			return
 		}
@@ -89,3 +89,24 @@ func TestWebsocketCors(t *testing.T) {
 		})
 	}
 }
+
+func TestWebsocketCorsSetAllowedOrigins(t *testing.T) {
+	c := qt.New(t)
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	cors := middleware.NewWebsocketCors([]string{"jaas.com"})
+	handlerWithCors := cors.Handler(handler)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Add("Origin", "dashboard.example.com")
+	w := httptest.NewRecorder()
+	handlerWithCors.ServeHTTP(w, req)
+	c.Check(w.Code, qt.Equals, http.StatusForbidden)
+
+	cors.SetAllowedOrigins([]string{"jaas.com", "dashboard.example.com"})
+	w = httptest.NewRecorder()
+	handlerWithCors.ServeHTTP(w, req)
+	c.Check(w.Code, qt.Equals, http.StatusOK)
+}
@@ -0,0 +1,113 @@
+// Copyright 2024 Canonical.
+
+package openfga
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// A checkCache is a bounded, least recently used, cache of the results of
+// authorisation checks. Entries expire after a fixed TTL and the whole
+// cache is invalidated whenever a tuple is written, as a single tuple may
+// affect the result of any number of checks.
+type checkCache struct {
+	maxEntries int
+	ttl        time.Duration
+
+	// now returns the current time, it is replaced in tests.
+	now func() time.Time
+
+	mu sync.Mutex
+	// generation is incremented every time the cache is invalidated.
+	// Results of requests that started in an earlier generation are
+	// discarded so that a result computed before a write cannot be
+	// cached after it.
+	generation uint64
+	entries    map[string]*list.Element
+	lru        *list.List
+}
+
+// A cacheEntry is a single value held in a checkCache.
+type cacheEntry struct {
+	key     string
+	value   any
+	expires time.Time
+}
+
+// newCheckCache returns a new checkCache holding at most maxEntries
+// entries, each of which is valid for the given TTL.
+func newCheckCache(maxEntries int, ttl time.Duration) *checkCache {
+	return &checkCache{
+		maxEntries: maxEntries,
+		ttl:        ttl,
+		now:        time.Now,
+		entries:    make(map[string]*list.Element),
+		lru:        list.New(),
+	}
+}
+
+// get returns the value stored for the given key, if there is an
+// unexpired one, and the current generation of the cache. The generation
+// should be passed to put when storing a newly computed value.
+func (c *checkCache) get(key string) (any, uint64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok {
+		return nil, c.generation, false
+	}
+	ce := e.Value.(*cacheEntry)
+	if c.now().After(ce.expires) {
+		c.lru.Remove(e)
+		delete(c.entries, key)
+		return nil, c.generation, false
+	}
+	c.lru.MoveToFront(e)
+	return ce.value, c.generation, true
+}
+
+// put stores the given value for the given key, evicting the least
+// recently used entry if the cache is full. The value is only stored if
+// the cache has not been invalidated since the given generation.
+func (c *checkCache) put(key string, value any, generation uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if generation != c.generation {
+		return
+	}
+	expires := c.now().Add(c.ttl)
+	if e, ok := c.entries[key]; ok {
+		ce := e.Value.(*cacheEntry)
+		ce.value = value
+		ce.expires = expires
+		c.lru.MoveToFront(e)
+		return
+	}
+	for c.lru.Len() >= c.maxEntries {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+	c.entries[key] = c.lru.PushFront(&cacheEntry{key: key, value: value, expires: expires})
+}
+
+// invalidate removes all entries from the cache.
+func (c *checkCache) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	c.entries = make(map[string]*list.Element)
+	c.lru.Init()
+}
+
+// len returns the number of entries in the cache.
+func (c *checkCache) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Len()
+}
@@ -0,0 +1,143 @@
+// Copyright 2024 Canonical.
+package openfga_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/juju/names/v5"
+	gc "gopkg.in/check.v1"
+
+	"github.com/canonical/jimm/v3/internal/openfga"
+	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
+	jimmnames "github.com/canonical/jimm/v3/pkg/names"
+)
+
+type checkCacheSuite struct{}
+
+var _ = gc.Suite(&checkCacheSuite{})
+
+func (s *checkCacheSuite) TestGetPut(c *gc.C) {
+	now := time.Now()
+	cache := openfga.NewCheckCache(10, time.Minute, func() time.Time { return now })
+
+	_, generation, ok := cache.Get("a")
+	c.Check(ok, gc.Equals, false)
+
+	cache.Put("a", true, generation)
+	v, _, ok := cache.Get("a")
+	c.Check(ok, gc.Equals, true)
+	c.Check(v, gc.Equals, true)
+
+	cache.Put("a", false, generation)
+	v, _, ok = cache.Get("a")
+	c.Check(ok, gc.Equals, true)
+	c.Check(v, gc.Equals, false)
+	c.Check(cache.Len(), gc.Equals, 1)
+}
+
+func (s *checkCacheSuite) TestExpiry(c *gc.C) {
+	now := time.Now()
+	cache := openfga.NewCheckCache(10, time.Minute, func() time.Time { return now })
+
+	_, generation, _ := cache.Get("a")
+	cache.Put("a", true, generation)
+
+	now = now.Add(59 * time.Second)
+	_, _, ok := cache.Get("a")
+	c.Check(ok, gc.Equals, true)
+
+	now = now.Add(2 * time.Second)
+	_, _, ok = cache.Get("a")
+	c.Check(ok, gc.Equals, false)
+	c.Check(cache.Len(), gc.Equals, 0)
+}
+
+func (s *checkCacheSuite) TestEviction(c *gc.C) {
+	now := time.Now()
+	cache := openfga.NewCheckCache(2, time.Minute, func() time.Time { return now })
+
+	_, generation, _ := cache.Get("a")
+	cache.Put("a", 1, generation)
+	cache.Put("b", 2, generation)
+	// Using a makes b the least recently used entry.
+	_, _, ok := cache.Get("a")
+	c.Check(ok, gc.Equals, true)
+	cache.Put("c", 3, generation)
+
+	c.Check(cache.Len(), gc.Equals, 2)
+	_, _, ok = cache.Get("a")
+	c.Check(ok, gc.Equals, true)
+	_, _, ok = cache.Get("b")
+	c.Check(ok, gc.Equals, false)
+	_, _, ok = cache.Get("c")
+	c.Check(ok, gc.Equals, true)
+}
+
+func (s *checkCacheSuite) TestInvalidate(c *gc.C) {
+	now := time.Now()
+	cache := openfga.NewCheckCache(10, time.Minute, func() time.Time { return now })
+
+	_, generation, _ := cache.Get("a")
+	cache.Put("a", true, generation)
+
+	_, staleGeneration, _ := cache.Get("b")
+	cache.Invalidate()
+	c.Check(cache.Len(), gc.Equals, 0)
+
+	// A result computed before the invalidation is not stored.
+	cache.Put("b", true, staleGeneration)
+	_, _, ok := cache.Get("b")
+	c.Check(ok, gc.Equals, false)
+}
+
+func (s *openFGATestSuite) TestCachedCheckRelationInvalidatedByWrite(c *gc.C) {
+	ctx := context.Background()
+	s.ofgaClient.EnableCache(100, time.Hour)
+
+	user := ofganames.ConvertTag(names.NewUserTag("alice"))
+	group := ofganames.ConvertTag(jimmnames.NewGroupTag(uuid.NewString()))
+	tuple := openfga.Tuple{
+		Object:   user,
+		Relation: ofganames.MemberRelation,
+		Target:   group,
+	}
+
+	allowed, err := s.ofgaClient.CheckRelation(ctx, tuple, false)
+	c.Assert(err, gc.IsNil)
+	c.Check(allowed, gc.Equals, false)
+
+	// A tuple written by another client is not seen until the cache
+	// expires.
+	err = s.cofgaClient.AddRelation(ctx, tuple)
+	c.Assert(err, gc.IsNil)
+	allowed, err = s.ofgaClient.CheckRelation(ctx, tuple, false)
+	c.Assert(err, gc.IsNil)
+	c.Check(allowed, gc.Equals, false)
+
+	// A traced check is not answered from the cache.
+	allowed, err = s.ofgaClient.CheckRelation(ctx, tuple, true)
+	c.Assert(err, gc.IsNil)
+	c.Check(allowed, gc.Equals, true)
+
+	objects, err := s.ofgaClient.ListObjects(ctx, user, ofganames.MemberRelation, openfga.GroupType, nil)
+	c.Assert(err, gc.IsNil)
+	c.Check(objects, gc.DeepEquals, []openfga.Tag{*group})
+
+	// Writing through the client invalidates the cache.
+	err = s.ofgaClient.RemoveRelation(ctx, tuple)
+	c.Assert(err, gc.IsNil)
+	allowed, err = s.ofgaClient.CheckRelation(ctx, tuple, false)
+	c.Assert(err, gc.IsNil)
+	c.Check(allowed, gc.Equals, false)
+	objects, err = s.ofgaClient.ListObjects(ctx, user, ofganames.MemberRelation, openfga.GroupType, nil)
+	c.Assert(err, gc.IsNil)
+	c.Check(objects, gc.HasLen, 0)
+
+	err = s.ofgaClient.AddRelation(ctx, tuple)
+	c.Assert(err, gc.IsNil)
+	allowed, err = s.ofgaClient.CheckRelation(ctx, tuple, false)
+	c.Assert(err, gc.IsNil)
+	c.Check(allowed, gc.Equals, true)
+}
@@ -4,6 +4,7 @@ package openfga
 
 import (
 	"context"
+	"time"
 
 	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
 )
@@ -17,3 +18,29 @@ func UnsetMultipleResourceAccesses[T ofganames.ResourceTagger](ctx context.Conte
 func (o *OFGAClient) RemoveTuples(ctx context.Context, tuple Tuple) error {
 	return o.removeTuples(ctx, tuple)
 }
+
+var RenameRelation = renameRelation
+
+type CheckCache = checkCache
+
+func NewCheckCache(maxEntries int, ttl time.Duration, now func() time.Time) *CheckCache {
+	c := newCheckCache(maxEntries, ttl)
+	c.now = now
+	return c
+}
+
+func (c *checkCache) Get(key string) (any, uint64, bool) {
+	return c.get(key)
+}
+
+func (c *checkCache) Put(key string, value any, generation uint64) {
+	c.put(key, value, generation)
+}
+
+func (c *checkCache) Invalidate() {
+	c.invalidate()
+}
+
+func (c *checkCache) Len() int {
+	return c.len()
+}
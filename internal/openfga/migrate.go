@@ -0,0 +1,138 @@
+// Copyright 2024 Canonical.
+
+package openfga
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/juju/zaputil/zapctx"
+	"go.uber.org/zap"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
+)
+
+// An authModelMigration is a single step that transforms the tuples held
+// in an OpenFGA store so that they conform to the given version of the
+// authorisation model. A migration may be re-run if a later step fails,
+// so every migration must be idempotent.
+type authModelMigration struct {
+	version     int
+	description string
+	migrate     func(ctx context.Context, o *OFGAClient) error
+}
+
+// authModelMigrations holds the ordered list of authorisation model
+// migrations. When openfga/authorisation_model.fga is changed in a way
+// that affects existing tuples, for example by renaming a relation, a
+// migration must be appended to this list.
+var authModelMigrations = []authModelMigration{{
+	version:     1,
+	description: "initial versioned authorisation model",
+}, {
+	version:     2,
+	description: "add manager relation to group",
+}}
+
+// AuthModelVersion is the version of the authorisation model supported by
+// this version of JIMM.
+var AuthModelVersion = authModelMigrations[len(authModelMigrations)-1].version
+
+// MigrateAuthModel applies all the authorisation model migrations newer
+// than the given version to the tuples in the OpenFGA store. It returns
+// the version of the last migration applied. If the given version is newer
+// than AuthModelVersion an error with a code of
+// errors.CodeServerConfiguration is returned.
+func MigrateAuthModel(ctx context.Context, o *OFGAClient, version int) (int, error) {
+	const op = errors.Op("openfga.MigrateAuthModel")
+
+	if version > AuthModelVersion {
+		return version, errors.E(op, errors.CodeServerConfiguration, fmt.Sprintf("authorisation model has incompatible version %d, expected at most %d", version, AuthModelVersion))
+	}
+	for _, m := range authModelMigrations {
+		if m.version <= version {
+			continue
+		}
+		zapctx.Info(ctx, "migrating authorisation model", zap.Int("version", m.version), zap.String("description", m.description))
+		if m.migrate != nil {
+			if err := m.migrate(ctx, o); err != nil {
+				return version, errors.E(op, err, fmt.Sprintf("failed to migrate authorisation model to version %d", m.version))
+			}
+		}
+		version = m.version
+	}
+	return version, nil
+}
+
+// renameRelation returns a migration that replaces every tuple with the
+// given relation on a target of the given kind with an equivalent tuple
+// using the new relation.
+func renameRelation(kind Kind, from, to Relation) func(context.Context, *OFGAClient) error {
+	return func(ctx context.Context, o *OFGAClient) error {
+		return o.rewriteTuples(ctx, func(t Tuple) (Tuple, bool) {
+			if t.Target.Kind != kind || t.Relation != from {
+				return t, false
+			}
+			t.Relation = to
+			return t, true
+		})
+	}
+}
+
+// rewriteTuples reads every tuple in the store and passes it to the given
+// function. If the function reports that the tuple has changed the old
+// tuple is removed and the returned tuple is written in its place.
+func (o *OFGAClient) rewriteTuples(ctx context.Context, f func(Tuple) (Tuple, bool)) error {
+	// Collect all the changes before writing any so that pagination is
+	// not affected by the changes being made.
+	var oldTuples, newTuples []Tuple
+	var continuationToken string
+	for {
+		tuples, ct, err := o.ReadRelatedObjects(ctx, Tuple{}, 50, continuationToken)
+		if err != nil {
+			return errors.E(err)
+		}
+		for _, t := range tuples {
+			// The wildcard user is presented as the everyone user when
+			// read, convert it back so that the correct tuple is
+			// written and removed.
+			if t.Object.Kind == UserType && t.Object.ID == ofganames.EveryoneUser {
+				object := *t.Object
+				object.ID = "*"
+				t.Object = &object
+			}
+			nt, changed := f(t)
+			if !changed {
+				continue
+			}
+			oldTuples = append(oldTuples, t)
+			newTuples = append(newTuples, nt)
+		}
+		if ct == "" {
+			break
+		}
+		continuationToken = ct
+	}
+
+	// New tuples are written one at a time so that a tuple written by a
+	// previous, partially completed, run of the migration doesn't cause
+	// the whole write to fail. Writes are done before deletes so that
+	// access is never lost part way through a migration.
+	for _, t := range newTuples {
+		err := o.AddRelation(ctx, t)
+		if err != nil && !strings.Contains(err.Error(), "cannot write a tuple which already exists") {
+			return errors.E(err)
+		}
+	}
+	// OpenFGA limits the number of writes in a single request, so old
+	// tuples are removed in batches.
+	const batchSize = 50
+	for i := 0; i < len(oldTuples); i += batchSize {
+		if err := o.RemoveRelation(ctx, oldTuples[i:min(i+batchSize, len(oldTuples))]...); err != nil {
+			return errors.E(err)
+		}
+	}
+	return nil
+}
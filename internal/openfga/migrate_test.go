@@ -0,0 +1,77 @@
+// Copyright 2024 Canonical.
+
+package openfga_test
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/juju/names/v5"
+	gc "gopkg.in/check.v1"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
+)
+
+func (s *openFGATestSuite) TestMigrateAuthModel(c *gc.C) {
+	ctx := context.Background()
+
+	version, err := openfga.MigrateAuthModel(ctx, s.ofgaClient, 0)
+	c.Assert(err, gc.IsNil)
+	c.Check(version, gc.Equals, openfga.AuthModelVersion)
+
+	// Migrating an up to date store is a no-op.
+	version, err = openfga.MigrateAuthModel(ctx, s.ofgaClient, openfga.AuthModelVersion)
+	c.Assert(err, gc.IsNil)
+	c.Check(version, gc.Equals, openfga.AuthModelVersion)
+}
+
+func (s *openFGATestSuite) TestMigrateAuthModelIncompatibleVersion(c *gc.C) {
+	ctx := context.Background()
+
+	_, err := openfga.MigrateAuthModel(ctx, s.ofgaClient, openfga.AuthModelVersion+1)
+	c.Check(err, gc.ErrorMatches, `authorisation model has incompatible version .*`)
+	c.Check(errors.ErrorCode(err), gc.Equals, errors.CodeServerConfiguration)
+}
+
+func (s *openFGATestSuite) TestRenameRelation(c *gc.C) {
+	ctx := context.Background()
+
+	model := ofganames.ConvertTag(names.NewModelTag(uuid.NewString()))
+	alice := ofganames.ConvertTag(names.NewUserTag("alice@canonical.com"))
+	everyone := ofganames.ConvertTag(names.NewUserTag(ofganames.EveryoneUser))
+	controller := ofganames.ConvertTag(names.NewControllerTag(uuid.NewString()))
+
+	err := s.ofgaClient.AddRelation(ctx,
+		openfga.Tuple{Object: alice, Relation: ofganames.ReaderRelation, Target: model},
+		openfga.Tuple{Object: everyone, Relation: ofganames.ReaderRelation, Target: model},
+		openfga.Tuple{Object: alice, Relation: ofganames.AdministratorRelation, Target: controller},
+	)
+	c.Assert(err, gc.IsNil)
+
+	migrate := openfga.RenameRelation(openfga.ModelType, ofganames.ReaderRelation, ofganames.WriterRelation)
+	err = migrate(ctx, s.ofgaClient)
+	c.Assert(err, gc.IsNil)
+	// Migrations must be idempotent.
+	err = migrate(ctx, s.ofgaClient)
+	c.Assert(err, gc.IsNil)
+
+	tuples, _, err := s.ofgaClient.ReadRelatedObjects(ctx, openfga.Tuple{Target: model}, 50, "")
+	c.Assert(err, gc.IsNil)
+	c.Assert(tuples, gc.HasLen, 2)
+	for _, t := range tuples {
+		c.Check(t.Relation, gc.Equals, ofganames.WriterRelation)
+	}
+	allowed, err := s.ofgaClient.CheckRelation(ctx, openfga.Tuple{
+		Object:   ofganames.ConvertTag(names.NewUserTag("bob@canonical.com")),
+		Relation: ofganames.WriterRelation,
+		Target:   model,
+	}, false)
+	c.Assert(err, gc.IsNil)
+	c.Check(allowed, gc.Equals, true)
+
+	tuples, _, err = s.ofgaClient.ReadRelatedObjects(ctx, openfga.Tuple{Target: controller}, 50, "")
+	c.Assert(err, gc.IsNil)
+	c.Check(tuples, gc.DeepEquals, []openfga.Tuple{{Object: alice, Relation: ofganames.AdministratorRelation, Target: controller}})
+}
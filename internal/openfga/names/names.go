@@ -37,13 +37,15 @@ var (
 	CanAddModelRelation cofga.Relation = "can_addmodel"
 	// AuditLogViewer represents an audit_log_viewer relation between entities.
 	AuditLogViewerRelation cofga.Relation = "audit_log_viewer"
+	// ManagerRelation represents a manager relation between entities.
+	ManagerRelation cofga.Relation = "manager"
 	// NoRelation is returned when there is no relation.
 	NoRelation cofga.Relation = ""
 )
 
 // allRelations contains a slice of all valid relations.
 // NB: Add any new relations from the above to this slice.
-var allRelations = []cofga.Relation{MemberRelation, AdministratorRelation, ControllerRelation, ModelRelation, ConsumerRelation, ReaderRelation, WriterRelation, CanAddModelRelation, AuditLogViewerRelation, AssigneeRelation, NoRelation}
+var allRelations = []cofga.Relation{MemberRelation, AdministratorRelation, ControllerRelation, ModelRelation, ConsumerRelation, ReaderRelation, WriterRelation, CanAddModelRelation, AuditLogViewerRelation, AssigneeRelation, ManagerRelation, NoRelation}
 
 // EveryoneUser is the username representing all users and is treated uniquely when used in OpenFGA tuples.
 const EveryoneUser = "everyone@external"
@@ -173,6 +175,8 @@ func ParseRelation(relationString string) (cofga.Relation, error) {
 		return AuditLogViewerRelation, nil
 	case AssigneeRelation.String():
 		return AssigneeRelation, nil
+	case ManagerRelation.String():
+		return ManagerRelation, nil
 	default:
 		return cofga.Relation(""), errors.E(op, fmt.Sprintf("unknown relation %s", relationString))
 
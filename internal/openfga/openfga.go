@@ -5,9 +5,11 @@ package openfga
 import (
 	"context"
 	"strings"
+	"time"
 
 	cofga "github.com/canonical/ofga"
 	"github.com/juju/names/v5"
+	"go.opentelemetry.io/otel/attribute"
 
 	"github.com/canonical/jimm/v3/internal/errors"
 	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
@@ -73,6 +75,10 @@ var (
 // an administrator.
 type OFGAClient struct {
 	cofgaClient *cofga.Client
+
+	// cache, if set, holds the results of recent CheckRelation and
+	// ListObjects calls.
+	cache *checkCache
 }
 
 // NewOpenFGAClient returns a new JIMM-specific client that wraps the given core OpenFGA client.
@@ -80,6 +86,29 @@ func NewOpenFGAClient(cofgaClient *cofga.Client) *OFGAClient {
 	return &OFGAClient{cofgaClient: cofgaClient}
 }
 
+// EnableCache configures the client to cache the results of at most
+// maxEntries CheckRelation and ListObjects calls for the given TTL. The
+// cache is invalidated whenever tuples are written through this client,
+// changes made by other clients are only seen once the TTL has expired.
+// EnableCache must be called before the client is used.
+func (o *OFGAClient) EnableCache(maxEntries int, ttl time.Duration) {
+	if maxEntries <= 0 || ttl <= 0 {
+		o.cache = nil
+		return
+	}
+	o.cache = newCheckCache(maxEntries, ttl)
+}
+
+// invalidateCache removes all cached results, it is called after tuples
+// are written.
+func (o *OFGAClient) invalidateCache() {
+	if o.cache == nil {
+		return
+	}
+	o.cache.invalidate()
+	servermon.OpenFGACacheInvalidationCount.Inc()
+}
+
 // publicAccessAdaptor handles cases where a tuple need to be transformed before being
 // returned to the application layer. The wildcard tuple * for users is replaced
 // with the everyone user.
@@ -177,6 +206,9 @@ func (o *OFGAClient) AddRelation(ctx context.Context, tuples ...Tuple) (err erro
 	durationObserver := servermon.DurationObserver(servermon.OpenFGACallDurationHistogram, string(op))
 	defer durationObserver()
 	defer servermon.ErrorCounter(servermon.OpenFGACallErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+	defer o.invalidateCache()
 
 	return o.cofgaClient.AddRelation(ctx, tuples...)
 }
@@ -188,6 +220,9 @@ func (o *OFGAClient) RemoveRelation(ctx context.Context, tuples ...Tuple) (err e
 	durationObserver := servermon.DurationObserver(servermon.OpenFGACallDurationHistogram, string(op))
 	defer durationObserver()
 	defer servermon.ErrorCounter(servermon.OpenFGACallErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+	defer o.invalidateCache()
 
 	return o.cofgaClient.RemoveRelation(ctx, tuples...)
 }
@@ -199,8 +234,27 @@ func (o *OFGAClient) ListObjects(ctx context.Context, user *Tag, relation Relati
 	durationObserver := servermon.DurationObserver(servermon.OpenFGACallDurationHistogram, string(op))
 	defer durationObserver()
 	defer servermon.ErrorCounter(servermon.OpenFGACallErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
 
-	return o.listObjects(ctx, user, relation, objType, contextualTuples)
+	// Results that depend on contextual tuples are not cached.
+	if o.cache == nil || len(contextualTuples) > 0 {
+		return o.listObjects(ctx, user, relation, objType, contextualTuples)
+	}
+	key := "list:" + user.String() + " " + relation.String() + " " + objType.String()
+	v, generation, ok := o.cache.get(key)
+	span.SetAttributes(attribute.Bool("openfga.cache_hit", ok))
+	if ok {
+		servermon.OpenFGACacheHitCount.WithLabelValues(string(op)).Inc()
+		return append([]Tag(nil), v.([]Tag)...), nil
+	}
+	servermon.OpenFGACacheMissCount.WithLabelValues(string(op)).Inc()
+	objects, err := o.listObjects(ctx, user, relation, objType, nil)
+	if err != nil {
+		return nil, err
+	}
+	o.cache.put(key, append([]Tag(nil), objects...), generation)
+	return objects, nil
 }
 
 // ReadRelations reads a relation(s) from the provided tuple where a match can be found.
@@ -214,6 +268,8 @@ func (o *OFGAClient) ReadRelatedObjects(ctx context.Context, tuple Tuple, pageSi
 	durationObserver := servermon.DurationObserver(servermon.OpenFGACallDurationHistogram, string(op))
 	defer durationObserver()
 	defer servermon.ErrorCounter(servermon.OpenFGACallErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
 
 	return o.getRelatedObjects(ctx, tuple, pageSize, continuationToken)
 }
@@ -227,11 +283,48 @@ func (o *OFGAClient) CheckRelation(ctx context.Context, tuple Tuple, trace bool)
 	durationObserver := servermon.DurationObserver(servermon.OpenFGACallDurationHistogram, string(op))
 	defer durationObserver()
 	defer servermon.ErrorCounter(servermon.OpenFGACallErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
 
+	// A traced check is never answered from the cache, otherwise the
+	// trace would not be produced.
 	if trace {
 		return o.cofgaClient.CheckRelationWithTracing(ctx, tuple)
 	}
-	return o.cofgaClient.CheckRelation(ctx, tuple)
+	if o.cache == nil || tuple.Object == nil || tuple.Target == nil {
+		return o.cofgaClient.CheckRelation(ctx, tuple)
+	}
+	key := "check:" + tuple.Object.String() + " " + tuple.Relation.String() + " " + tuple.Target.String()
+	v, generation, ok := o.cache.get(key)
+	span.SetAttributes(attribute.Bool("openfga.cache_hit", ok))
+	if ok {
+		servermon.OpenFGACacheHitCount.WithLabelValues(string(op)).Inc()
+		return v.(bool), nil
+	}
+	servermon.OpenFGACacheMissCount.WithLabelValues(string(op)).Inc()
+	allowed, err := o.cofgaClient.CheckRelation(ctx, tuple)
+	if err != nil {
+		return false, err
+	}
+	o.cache.put(key, allowed, generation)
+	return allowed, nil
+}
+
+// Ping checks that the OpenFGA server can be reached by fetching the
+// authorisation model the client is configured to use.
+func (o *OFGAClient) Ping(ctx context.Context) (err error) {
+	op := errors.Op("openfga.Ping")
+
+	durationObserver := servermon.DurationObserver(servermon.OpenFGACallDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.OpenFGACallErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
+
+	if _, err := o.cofgaClient.GetAuthModel(ctx, o.cofgaClient.AuthModelID()); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
 }
 
 // removeTuples iteratively reads through all the tuples with the parameters as supplied by tuple and deletes them.
@@ -241,6 +334,8 @@ func (o *OFGAClient) removeTuples(ctx context.Context, tuple Tuple) (err error)
 	durationObserver := servermon.DurationObserver(servermon.OpenFGACallDurationHistogram, string(op))
 	defer durationObserver()
 	defer servermon.ErrorCounter(servermon.OpenFGACallErrorCount, &err, string(op))
+	ctx, span := servermon.StartSpan(ctx, string(op))
+	defer servermon.EndSpan(span, &err)
 
 	// Note (babakks): an obvious improvement to this function is to make it work
 	// atomically and remove all the tuples in a transaction. At the moment, it's
@@ -344,12 +439,12 @@ func (o *OFGAClient) RemoveRole(ctx context.Context, role jimmnames.RoleTag) err
 
 // RemoveGroup removes a group.
 func (o *OFGAClient) RemoveGroup(ctx context.Context, group jimmnames.GroupTag) error {
-	// Remove all access to a group. I.e. user->group
+	// Remove all access to a group. I.e. user->group, including
+	// the group's managers.
 	if err := o.removeTuples(
 		ctx,
 		Tuple{
-			Relation: ofganames.MemberRelation,
-			Target:   ofganames.ConvertTag(group),
+			Target: ofganames.ConvertTag(group),
 		},
 	); err != nil {
 		return errors.E(err)
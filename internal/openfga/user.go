@@ -97,6 +97,15 @@ func (u *User) IsServiceAccountAdmin(ctx context.Context, clientID jimmnames.Ser
 	return isAdmin, nil
 }
 
+// IsGroupManager returns true if the user has manager relation to the group.
+func (u *User) IsGroupManager(ctx context.Context, group jimmnames.GroupTag) (bool, error) {
+	isManager, err := checkRelation(ctx, u, group, ofganames.ManagerRelation)
+	if err != nil {
+		return false, errors.E(err)
+	}
+	return isManager, nil
+}
+
 // GetCloudAccess returns the relation the user has to the specified cloud.
 func (u *User) GetCloudAccess(ctx context.Context, resource names.CloudTag) Relation {
 	isCloudAdmin, err := IsAdministrator(ctx, u, resource)
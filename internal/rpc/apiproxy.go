@@ -13,6 +13,8 @@ import (
 	"github.com/juju/juju/rpc/params"
 	"github.com/juju/names/v5"
 	"github.com/juju/zaputil/zapctx"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
 	"go.uber.org/zap"
 	"golang.org/x/oauth2"
 
@@ -78,6 +80,11 @@ type ProxyHelpers struct {
 	AuditLog                func(*dbmodel.AuditLogEntry)
 	LoginService            LoginService
 	AuthenticatedIdentityID string
+	// CheckCall, if set, is called before each request other than those
+	// to the Admin facade is sent to the controller. If it returns an
+	// error the request is not sent and the error is returned to the
+	// client instead.
+	CheckCall func(ctx context.Context, facade, method string) error
 }
 
 // ProxySockets will proxy requests from a client connection through to a controller
@@ -114,6 +121,7 @@ func ProxySockets(ctx context.Context, helpers ProxyHelpers) error {
 		},
 		errChan:              errChan,
 		createControllerConn: helpers.ConnectController,
+		checkCall:            helpers.CheckCall,
 	}
 	clProxy.wg.Add(1)
 	go func() {
@@ -135,6 +143,8 @@ func ProxySockets(ctx context.Context, helpers ProxyHelpers) error {
 	// connection to the controller fails and we want to trigger cleanup.
 	helpers.ConnClient.Close()
 	clProxy.wg.Wait()
+	// End the spans of any requests the controller never responded to.
+	msgInFlight.removeAll(errors.E(op, "proxy closed before response received"))
 	return err
 }
 
@@ -209,8 +219,9 @@ func (msgs *inflightMsgs) addMessage(msg *message) {
 }
 
 // removeMessage deletes the request message that corresponds
-// to the responses message ID.
-func (msgs *inflightMsgs) removeMessage(msgID uint64) {
+// to the responses message ID. The trace span of the request is ended,
+// marking the request as failed if err is not nil.
+func (msgs *inflightMsgs) removeMessage(msgID uint64, err error) {
 	msgs.mu.Lock()
 	req, ok := msgs.messages[msgID]
 	if ok {
@@ -224,9 +235,51 @@ func (msgs *inflightMsgs) removeMessage(msgID uint64) {
 			req.Request,
 			msgs.controllerUUID,
 		).Observe(time.Since(req.start).Seconds())
+		if req.span != nil {
+			req.span.SetAttributes(attribute.String("jimm.controller.uuid", msgs.controllerUUID))
+		}
+		endMessageSpan(req, err)
+	}
+}
+
+// removeAll deletes all the request messages still pending a response.
+// The trace spans of the requests are ended, marking the requests as
+// failed with the given error.
+func (msgs *inflightMsgs) removeAll(err error) {
+	msgs.mu.Lock()
+	pending := msgs.messages
+	msgs.messages = make(map[uint64]*message)
+	msgs.mu.Unlock()
+
+	for _, req := range pending {
+		if req.span != nil {
+			req.span.SetAttributes(attribute.String("jimm.controller.uuid", msgs.controllerUUID))
+		}
+		endMessageSpan(req, err)
 	}
 }
 
+// startMessageSpan starts the trace span covering a request message
+// proxied from the client until the response is returned.
+func startMessageSpan(ctx context.Context, msg *message) context.Context {
+	ctx, msg.span = servermon.StartSpan(ctx, "proxy "+msg.Type+"."+msg.Request,
+		semconv.RPCSystemKey.String("jujurpc"),
+		semconv.RPCService(msg.Type),
+		semconv.RPCMethod(msg.Request),
+		attribute.Int("rpc.jujurpc.version", msg.Version),
+	)
+	return ctx
+}
+
+// endMessageSpan ends the trace span of the given request message, if it
+// has one, marking the request as failed if err is not nil.
+func endMessageSpan(msg *message, err error) {
+	if msg.span == nil {
+		return
+	}
+	servermon.EndSpan(msg.span, &err)
+}
+
 func (msgs *inflightMsgs) getMessage(key uint64) *message {
 	msgs.mu.Lock()
 	defer msgs.mu.Unlock()
@@ -331,6 +384,7 @@ type clientProxy struct {
 	errChan              chan error
 	createControllerConn func(context.Context) (WebsocketConnectionWithMetadata, error)
 	connectController    sync.Once
+	checkCall            func(ctx context.Context, facade, method string) error
 }
 
 // start begins the client->controller proxier.
@@ -351,10 +405,12 @@ func (p *clientProxy) start(ctx context.Context) error {
 			return nil
 		}
 		zapctx.Debug(ctx, "Read message from client", zap.Any("message", msg))
-		err := p.makeControllerConnection(ctx)
+		msgCtx := startMessageSpan(ctx, msg)
+		err := p.makeControllerConnection(msgCtx)
 		if err != nil {
 			zapctx.Error(ctx, "error connecting to controller", zap.Error(err))
 			p.sendError(p.src, msg, err)
+			endMessageSpan(msg, err)
 			return fmt.Errorf("failed to connect to controller: %w", err)
 		}
 		if err := p.auditLogMessage(msg, false); err != nil {
@@ -364,9 +420,10 @@ func (p *clientProxy) start(ctx context.Context) error {
 		// except for auth related requests like Login because JIMM is auth gateway.
 		if msg.Type == "Admin" {
 			zapctx.Debug(ctx, "handling an Admin facade call")
-			toClient, toController, err := p.handleAdminFacade(ctx, msg)
+			toClient, toController, err := p.handleAdminFacade(msgCtx, msg)
 			if err != nil {
 				p.sendError(p.src, msg, err)
+				endMessageSpan(msg, err)
 				continue
 			}
 			// If there is a response for the client, send it to the client and continue.
@@ -374,18 +431,25 @@ func (p *clientProxy) start(ctx context.Context) error {
 			// We can't send the client a response from JIMM and send a message to the controller.
 			if toClient != nil {
 				p.src.sendMessage(nil, toClient)
+				endMessageSpan(msg, nil)
 				continue
 			} else if toController != nil {
 				msg = toController
 				p.msgs.addLoginMessage(toController)
 			}
+		} else if p.checkCall != nil {
+			if err := p.checkCall(msgCtx, msg.Type, msg.Request); err != nil {
+				p.sendError(p.src, msg, err)
+				endMessageSpan(msg, err)
+				continue
+			}
 		}
 		p.msgs.addMessage(msg)
 		zapctx.Debug(ctx, "Writing to controller")
 		if err := p.dst.writeJson(msg); err != nil {
 			zapctx.Error(ctx, "clientProxy error writing to dst", zap.Error(err))
 			p.sendError(p.src, msg, err)
-			p.msgs.removeMessage(msg.RequestID)
+			p.msgs.removeMessage(msg.RequestID, err)
 			continue
 		}
 	}
@@ -462,6 +526,9 @@ func (p *controllerProxy) start(ctx context.Context) error {
 			// Write back to the controller.
 			msg := p.msgs.getMessage(msg.RequestID)
 			if msg != nil {
+				if msg.span != nil {
+					msg.span.AddEvent("retrying with additional permissions")
+				}
 				if err := p.src.writeJson(msg); err != nil {
 					zapctx.Error(context.Background(), "failed to write back to controller", zap.Error(err))
 				}
@@ -475,7 +542,11 @@ func (p *controllerProxy) start(ctx context.Context) error {
 				return fmt.Errorf("error modifying controller response: %w", err)
 			}
 		}
-		p.msgs.removeMessage(msg.RequestID)
+		var respErr error
+		if msg.Error != "" {
+			respErr = &Error{Message: msg.Error, Code: msg.ErrorCode}
+		}
+		p.msgs.removeMessage(msg.RequestID, respErr)
 		if err := p.auditLogMessage(msg, true); err != nil {
 			zapctx.Error(context.Background(), "failed to audit log message", zap.Error(err))
 		}
@@ -489,7 +560,7 @@ func (p *controllerProxy) start(ctx context.Context) error {
 
 func (p *controllerProxy) handleError(msg *message, err error) {
 	p.sendError(p.dst, msg, err)
-	p.msgs.removeMessage(msg.RequestID)
+	p.msgs.removeMessage(msg.RequestID, err)
 }
 
 // checkPermissionsRequired returns a nil map if no permissions are required.
@@ -70,6 +70,7 @@ func TestProxySocketsAdminFacade(t *testing.T) {
 		expectedClientResponse    *message
 		expectedControllerMessage *message
 		oauthAuthenticatorError   error
+		checkCallError            error
 		expectedProxyError        string
 	}{{
 		about: "login device call - client gets response with both user code and verification uri",
@@ -199,6 +200,21 @@ func TestProxySocketsAdminFacade(t *testing.T) {
 			Request:   "AnyMethod",
 			Params:    []byte(`{"key":"value"}`),
 		},
+	}, {
+		about: "message refused by the call check - client gets the error",
+		messageToSend: message{
+			RequestID: 1,
+			Type:      "Application",
+			Version:   19,
+			Request:   "Deploy",
+			Params:    []byte(`{"key":"value"}`),
+		},
+		expectedClientResponse: &message{
+			RequestID: 1,
+			Error:     "controller in maintenance",
+			ErrorCode: "controller maintenance",
+		},
+		checkCallError: errors.E(errors.CodeControllerMaintenance, "controller in maintenance"),
 	}, {
 		about: "login with session cookie - a login message is sent to the controller",
 		messageToSend: message{
@@ -272,6 +288,9 @@ func TestProxySocketsAdminFacade(t *testing.T) {
 				AuditLog:                func(*dbmodel.AuditLogEntry) {},
 				LoginService:            loginSvc,
 				AuthenticatedIdentityID: test.authenticateEntityID,
+				CheckCall: func(ctx context.Context, facade, method string) error {
+					return test.checkCallError
+				},
 			}
 			var wg sync.WaitGroup
 			wg.Add(1)
@@ -17,11 +17,14 @@ import (
 	qt "github.com/frankban/quicktest"
 	"github.com/gorilla/websocket"
 	"github.com/juju/names/v5"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 
 	"github.com/canonical/jimm/v3/internal/dbmodel"
 	"github.com/canonical/jimm/v3/internal/errors"
 	"github.com/canonical/jimm/v3/internal/openfga"
 	"github.com/canonical/jimm/v3/internal/rpc"
+	"github.com/canonical/jimm/v3/internal/testutils/jimmtest"
 )
 
 func TestDialError(t *testing.T) {
@@ -299,6 +302,134 @@ func TestProxySockets(t *testing.T) {
 	<-errChan // Ensure go routines are cleaned up
 }
 
+func TestProxySocketsSpans(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	sr := jimmtest.RecordSpans(c)
+	srvController := newServer(echo)
+
+	errChan := make(chan error)
+	srvJIMM := newServer(func(connClient *websocket.Conn) error {
+		f := func(context.Context) (rpc.WebsocketConnectionWithMetadata, error) {
+			connController, err := srvController.dialer.DialWebsocket(ctx, srvController.URL, nil)
+			c.Check(err, qt.IsNil)
+			return rpc.WebsocketConnectionWithMetadata{
+				Conn:           connController,
+				ControllerUUID: "00000001-0000-0000-0000-000000000001",
+				ModelName:      "TestName",
+			}, nil
+		}
+		proxyHelpers := rpc.ProxyHelpers{
+			ConnClient:        connClient,
+			TokenGen:          &testTokenGenerator{},
+			ConnectController: f,
+			AuditLog:          func(*dbmodel.AuditLogEntry) {},
+			LoginService:      &mockLoginService{},
+		}
+		err := rpc.ProxySockets(ctx, proxyHelpers)
+		errChan <- err
+		return err
+	})
+
+	defer srvController.Close()
+	defer srvJIMM.Close()
+	ws, err := srvJIMM.dialer.DialWebsocket(ctx, srvJIMM.URL, nil)
+	c.Assert(err, qt.IsNil)
+	defer ws.Close()
+
+	msg := rpc.Message{RequestID: 1, Type: "TestType", Version: 2, Request: "TestReq", Params: json.RawMessage(`{}`)}
+	err = ws.WriteJSON(&msg)
+	c.Assert(err, qt.IsNil)
+	var resp rpc.Message
+	err = ws.ReadJSON(&resp)
+	c.Assert(err, qt.IsNil)
+	ws.Close()
+	<-errChan // Ensure go routines are cleaned up
+
+	spans := sr.Ended()
+	c.Assert(spans, qt.HasLen, 1)
+	c.Check(spans[0].Name(), qt.Equals, "proxy TestType.TestReq")
+	attrs := make(map[attribute.Key]string)
+	for _, kv := range spans[0].Attributes() {
+		attrs[kv.Key] = kv.Value.Emit()
+	}
+	c.Check(attrs, qt.DeepEquals, map[attribute.Key]string{
+		"rpc.system":           "jujurpc",
+		"rpc.service":          "TestType",
+		"rpc.method":           "TestReq",
+		"rpc.jujurpc.version":  "2",
+		"jimm.controller.uuid": "00000001-0000-0000-0000-000000000001",
+	})
+}
+
+func TestProxySocketsEndsPendingSpans(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	sr := jimmtest.RecordSpans(c)
+	received := make(chan struct{})
+	// The controller never responds to the request.
+	srvController := newServer(func(conn *websocket.Conn) error {
+		var msg map[string]interface{}
+		if err := conn.ReadJSON(&msg); err != nil {
+			return err
+		}
+		close(received)
+		for {
+			if err := conn.ReadJSON(&msg); err != nil {
+				return nil
+			}
+		}
+	})
+
+	errChan := make(chan error)
+	srvJIMM := newServer(func(connClient *websocket.Conn) error {
+		f := func(context.Context) (rpc.WebsocketConnectionWithMetadata, error) {
+			connController, err := srvController.dialer.DialWebsocket(ctx, srvController.URL, nil)
+			c.Check(err, qt.IsNil)
+			return rpc.WebsocketConnectionWithMetadata{
+				Conn:           connController,
+				ControllerUUID: "00000001-0000-0000-0000-000000000001",
+				ModelName:      "TestName",
+			}, nil
+		}
+		proxyHelpers := rpc.ProxyHelpers{
+			ConnClient:        connClient,
+			TokenGen:          &testTokenGenerator{},
+			ConnectController: f,
+			AuditLog:          func(*dbmodel.AuditLogEntry) {},
+			LoginService:      &mockLoginService{},
+		}
+		err := rpc.ProxySockets(ctx, proxyHelpers)
+		errChan <- err
+		return err
+	})
+
+	defer srvController.Close()
+	defer srvJIMM.Close()
+	ws, err := srvJIMM.dialer.DialWebsocket(ctx, srvJIMM.URL, nil)
+	c.Assert(err, qt.IsNil)
+	defer ws.Close()
+
+	msg := rpc.Message{RequestID: 1, Type: "TestType", Version: 2, Request: "TestReq", Params: json.RawMessage(`{}`)}
+	err = ws.WriteJSON(&msg)
+	c.Assert(err, qt.IsNil)
+	select {
+	case <-received:
+	case <-time.After(5 * time.Second):
+		c.Fatalf("request not proxied to the controller")
+	}
+	ws.Close()
+	<-errChan // Ensure go routines are cleaned up
+
+	spans := sr.Ended()
+	c.Assert(spans, qt.HasLen, 1)
+	c.Check(spans[0].Name(), qt.Equals, "proxy TestType.TestReq")
+	c.Check(spans[0].Status().Code, qt.Equals, codes.Error)
+	c.Check(spans[0].Status().Description, qt.Equals, "proxy closed before response received")
+}
+
 func TestProxySocketsControllerConnectionFails(t *testing.T) {
 	c := qt.New(t)
 	ctx := context.Background()
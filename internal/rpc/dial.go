@@ -21,6 +21,7 @@ import (
 
 	"github.com/canonical/jimm/v3/internal/dbmodel"
 	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/servermon"
 )
 
 // A Dialer is used to create client connections to an RPC URL.
@@ -45,6 +46,13 @@ func (d Dialer) DialWebsocket(ctx context.Context, url string, headers http.Head
 	dialer := websocket.Dialer{
 		TLSClientConfig: d.TLSConfig,
 	}
+	// Pass on the trace context so that the trace can be continued by
+	// servers that support it.
+	headers = headers.Clone()
+	if headers == nil {
+		headers = make(http.Header)
+	}
+	servermon.InjectTraceContext(ctx, headers)
 	conn, resp, err := dialer.DialContext(ctx, url, headers)
 	if err != nil {
 		zapctx.Error(ctx, "BasicDial failed", zap.Error(err))
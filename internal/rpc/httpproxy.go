@@ -16,6 +16,7 @@ import (
 	"gopkg.in/errgo.v1"
 
 	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/servermon"
 )
 
 type httpOptions struct {
@@ -75,6 +76,7 @@ func doRequest(ctx context.Context, w http.ResponseWriter, req *http.Request, op
 	req = req.Clone(ctx)
 	req.RequestURI = ""
 	req.URL = &opt.URL
+	servermon.InjectTraceContext(ctx, req.Header)
 	resp, err := client.Do(req)
 	if err != nil {
 		return err
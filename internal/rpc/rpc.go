@@ -10,6 +10,8 @@ package rpc
 import (
 	"encoding/json"
 	"time"
+
+	"go.opentelemetry.io/otel/trace"
 )
 
 // A message encodes a single message sent, or received, over an RPC
@@ -17,6 +19,7 @@ import (
 // message.
 type message struct {
 	start     time.Time
+	span      trace.Span
 	RequestID uint64                 `json:"request-id,omitempty"`
 	Type      string                 `json:"type,omitempty"`
 	Version   int                    `json:"version,omitempty"`
@@ -12,6 +12,12 @@ import (
 )
 
 var (
+	AlertNotificationCount = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "jimm",
+		Subsystem: "alerting",
+		Name:      "notifications_total",
+		Help:      "The number of alert notifications sent, by receiver type and result.",
+	}, []string{"type", "result"})
 	AuthenticationFailCount = promauto.NewCounterVec(prometheus.CounterOpts{
 		Namespace: "jimm",
 		Subsystem: "auth",
@@ -57,6 +63,24 @@ var (
 		Name:      "error_total",
 		Help:      "The number of openfga call errors.",
 	}, []string{"method"})
+	OpenFGACacheHitCount = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "jimm",
+		Subsystem: "openfga",
+		Name:      "cache_hit_total",
+		Help:      "The number of openfga calls answered from the cache.",
+	}, []string{"method"})
+	OpenFGACacheMissCount = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "jimm",
+		Subsystem: "openfga",
+		Name:      "cache_miss_total",
+		Help:      "The number of cacheable openfga calls not found in the cache.",
+	}, []string{"method"})
+	OpenFGACacheInvalidationCount = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "jimm",
+		Subsystem: "openfga",
+		Name:      "cache_invalidation_total",
+		Help:      "The number of times the openfga cache has been invalidated.",
+	})
 	VaultCallDurationHistogram = promauto.NewHistogramVec(prometheus.HistogramOpts{
 		Namespace: "jimm",
 		Subsystem: "vault",
@@ -132,6 +156,54 @@ var (
 		Name:      "controller",
 		Help:      "The number of controllers managed by JIMM.",
 	})
+	ModelSummaryModelCount = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "jimm",
+		Subsystem: "model_summary",
+		Name:      "models",
+		Help:      "The number of models on each controller, by model status.",
+	}, []string{"controller", "status"})
+	ModelSummaryErrorModelCount = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "jimm",
+		Subsystem: "model_summary",
+		Name:      "models_in_error",
+		Help:      "The number of models on each controller with a status of error.",
+	}, []string{"controller"})
+	ModelSummaryMachineCount = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "jimm",
+		Subsystem: "model_summary",
+		Name:      "machines",
+		Help:      "The number of machines on each controller, by model owner.",
+	}, []string{"controller", "owner"})
+	ModelSummaryUnitCount = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "jimm",
+		Subsystem: "model_summary",
+		Name:      "units",
+		Help:      "The number of units on each controller, by model owner.",
+	}, []string{"controller", "owner"})
+	ModelSummaryAgentVersionCount = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "jimm",
+		Subsystem: "model_summary",
+		Name:      "agent_versions",
+		Help:      "The number of models on each controller, by model agent version.",
+	}, []string{"controller", "version"})
+	ModelSummaryModelMachines = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "jimm",
+		Subsystem: "model_summary",
+		Name:      "model_machines",
+		Help:      "The number of machines in each model. Only exported if per-model metrics are enabled.",
+	}, []string{"controller", "model_uuid", "model_name", "owner"})
+	ModelSummaryModelUnits = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "jimm",
+		Subsystem: "model_summary",
+		Name:      "model_units",
+		Help:      "The number of units in each model. Only exported if per-model metrics are enabled.",
+	}, []string{"controller", "model_uuid", "model_name", "owner"})
+	ModelSummaryModelStatus = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "jimm",
+		Subsystem: "model_summary",
+		Name:      "model_status",
+		Help:      "The status of each model, the value is always 1. Only exported if per-model metrics are enabled.",
+	}, []string{"controller", "model_uuid", "model_name", "status"})
 	ResponseTimeHistogram = promauto.NewHistogramVec(prometheus.HistogramOpts{
 		Namespace: "jimm",
 		Subsystem: "http",
@@ -139,6 +211,62 @@ var (
 		Help:      "The duration of handling an HTTP request in seconds.",
 		Buckets:   []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10},
 	}, []string{"route", "method", "status_code"})
+	ScheduledJobRunCount = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "jimm",
+		Subsystem: "scheduler",
+		Name:      "job_runs_total",
+		Help:      "The number of finished runs of each scheduled job, by status.",
+	}, []string{"job", "status"})
+	ScheduledJobDurationHistogram = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "jimm",
+		Subsystem: "scheduler",
+		Name:      "job_duration_seconds",
+		Help:      "The duration of each run of a scheduled job in seconds.",
+		Buckets:   []float64{.1, 1, 10, 60, 300, 900, 3600},
+	}, []string{"job"})
+	ScheduledJobLastSuccess = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "jimm",
+		Subsystem: "scheduler",
+		Name:      "job_last_success_timestamp_seconds",
+		Help:      "The time each scheduled job last finished successfully, as a unix timestamp.",
+	}, []string{"job"})
+	ScheduledJobRunning = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "jimm",
+		Subsystem: "scheduler",
+		Name:      "job_running",
+		Help:      "Whether each scheduled job is currently running on this replica.",
+	}, []string{"job"})
+	ControllerPoolConnections = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "jimm",
+		Subsystem: "controller_pool",
+		Name:      "connections",
+		Help:      "The number of pooled connections to each controller and its models, by state (idle or in_use).",
+	}, []string{"controller", "state"})
+	ControllerPoolAcquireDurationHistogram = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "jimm",
+		Subsystem: "controller_pool",
+		Name:      "acquire_duration_seconds",
+		Help:      "The time taken to get a connection to a controller from the pool, including any wait or dial, in seconds.",
+		Buckets:   []float64{.001, .005, .01, .05, .1, .5, 1, 5, 10, 30},
+	}, []string{"controller"})
+	ControllerPoolDialCount = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "jimm",
+		Subsystem: "controller_pool",
+		Name:      "dials_total",
+		Help:      "The number of attempts to dial each controller, by result (success, failure or rejected by the circuit breaker).",
+	}, []string{"controller", "result"})
+	ControllerPoolEvictionCount = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "jimm",
+		Subsystem: "controller_pool",
+		Name:      "evictions_total",
+		Help:      "The number of pooled connections to each controller that have been closed, by reason.",
+	}, []string{"controller", "reason"})
+	ControllerCircuitBreakerState = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "jimm",
+		Subsystem: "controller_pool",
+		Name:      "circuit_state",
+		Help:      "The state of the circuit breaker for each controller: 0 closed, 1 half-open, 2 open.",
+	}, []string{"controller"})
 )
 
 // DurationObserver returns a function that, when run with `defer` will
@@ -0,0 +1,147 @@
+// Copyright 2024 Canonical.
+
+package servermon
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+// tracerName is the name of the tracer used for all JIMM's spans.
+const tracerName = "github.com/canonical/jimm/v3"
+
+// defaultTracesPath is the path traces are exported to when the tracing
+// endpoint does not include one.
+const defaultTracesPath = "/v1/traces"
+
+// TracingParams holds the parameters used to export traces.
+type TracingParams struct {
+	// Endpoint is the URL of the OTLP/HTTP collector that traces are
+	// exported to, for example "http://otel-collector:4318". If the URL
+	// has no path "/v1/traces" is used. If this is empty traces are not
+	// exported, although incoming trace context is still propagated.
+	Endpoint string
+
+	// Headers are added to every export request, for example to
+	// authenticate with the collector.
+	Headers map[string]string
+
+	// SampleRatio is the fraction of new traces that are sampled. Traces
+	// started by a caller are sampled if the caller sampled them. If this
+	// is zero every trace is sampled.
+	SampleRatio float64
+
+	// ServiceVersion is the version of JIMM reported with every span.
+	ServiceVersion string
+}
+
+// SetupTracing configures the global tracer provider to export spans to
+// the OTLP collector described by the given parameters and configures
+// the W3C trace context propagator. The returned function flushes any
+// buffered spans and stops the exporter, it should be called on
+// shutdown.
+func SetupTracing(ctx context.Context, p TracingParams) (func(context.Context) error, error) {
+	const op = errors.Op("servermon.SetupTracing")
+
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+	if p.Endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	u, err := url.Parse(p.Endpoint)
+	if err != nil {
+		return nil, errors.E(op, errors.CodeBadRequest, "invalid tracing endpoint", err)
+	}
+	opts := []otlptracehttp.Option{
+		otlptracehttp.WithEndpoint(u.Host),
+		otlptracehttp.WithURLPath(defaultTracesPath),
+	}
+	switch u.Scheme {
+	case "http":
+		opts = append(opts, otlptracehttp.WithInsecure())
+	case "https":
+	default:
+		return nil, errors.E(op, errors.CodeBadRequest, "tracing endpoint must be an http or https URL")
+	}
+	if u.Path != "" && u.Path != "/" {
+		opts = append(opts, otlptracehttp.WithURLPath(u.Path))
+	}
+	if len(p.Headers) > 0 {
+		opts = append(opts, otlptracehttp.WithHeaders(p.Headers))
+	}
+	exporter, err := otlptracehttp.New(ctx, opts...)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	res, err := resource.New(ctx,
+		resource.WithFromEnv(),
+		resource.WithHost(),
+		resource.WithTelemetrySDK(),
+		resource.WithAttributes(
+			semconv.ServiceName("jimm"),
+			semconv.ServiceVersion(p.ServiceVersion),
+		),
+	)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	sampler := sdktrace.AlwaysSample()
+	if p.SampleRatio > 0 && p.SampleRatio < 1 {
+		sampler = sdktrace.TraceIDRatioBased(p.SampleRatio)
+	}
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sampler)),
+	)
+	otel.SetTracerProvider(tp)
+	return tp.Shutdown, nil
+}
+
+// StartSpan starts a new span with the given name and attributes as a
+// child of any span in the given context. The returned context holds the
+// new span.
+func StartSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// EndSpan ends the given span, marking it as failed if the error is not
+// nil. It is designed to be run with `defer` alongside ErrorCounter.
+func EndSpan(span trace.Span, err *error) {
+	if *err != nil {
+		span.RecordError(*err)
+		span.SetStatus(codes.Error, (*err).Error())
+	}
+	span.End()
+}
+
+// InjectTraceContext adds the W3C trace context of the span in the given
+// context to the given HTTP headers, so that the receiver can continue
+// the trace.
+func InjectTraceContext(ctx context.Context, h http.Header) {
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(h))
+}
+
+// ExtractTraceContext returns a context holding the W3C trace context
+// sent in the given HTTP headers, if any.
+func ExtractTraceContext(ctx context.Context, h http.Header) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.HeaderCarrier(h))
+}
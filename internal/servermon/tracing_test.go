@@ -0,0 +1,99 @@
+// Copyright 2024 Canonical.
+
+package servermon_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/servermon"
+	"github.com/canonical/jimm/v3/internal/testutils/jimmtest"
+)
+
+func TestSetupTracing(t *testing.T) {
+	c := qt.New(t)
+
+	prev := otel.GetTracerProvider()
+	c.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+	collector := jimmtest.NewOTLPCollector(c)
+	ctx := context.Background()
+	shutdown, err := servermon.SetupTracing(ctx, servermon.TracingParams{
+		Endpoint:       collector.URL,
+		Headers:        map[string]string{"X-Test": "test-value"},
+		ServiceVersion: "1.2.3",
+	})
+	c.Assert(err, qt.IsNil)
+
+	ctx, parent := servermon.StartSpan(ctx, "parent", attribute.String("test.key", "value"))
+	_, child := servermon.StartSpan(ctx, "child")
+	err = errors.E("test error")
+	servermon.EndSpan(child, &err)
+	err = nil
+	servermon.EndSpan(parent, &err)
+
+	// Shutting down flushes the batched spans to the collector.
+	c.Assert(shutdown(context.Background()), qt.IsNil)
+
+	rss := collector.ResourceSpans()
+	c.Assert(rss, qt.HasLen, 1)
+	resAttrs := make(map[string]string)
+	for _, kv := range rss[0].Resource.Attributes {
+		resAttrs[kv.Key] = kv.Value.GetStringValue()
+	}
+	c.Check(resAttrs["service.name"], qt.Equals, "jimm")
+	c.Check(resAttrs["service.version"], qt.Equals, "1.2.3")
+
+	c.Assert(rss[0].ScopeSpans, qt.HasLen, 1)
+	spans := rss[0].ScopeSpans[0].Spans
+	c.Assert(spans, qt.HasLen, 2)
+	c.Check(spans[0].Name, qt.Equals, "child")
+	c.Check(spans[0].Status.Code.String(), qt.Equals, "STATUS_CODE_ERROR")
+	c.Check(spans[0].Status.Message, qt.Equals, "test error")
+	c.Check(spans[0].TraceId, qt.DeepEquals, spans[1].TraceId)
+	c.Check(spans[0].ParentSpanId, qt.DeepEquals, spans[1].SpanId)
+	c.Check(spans[1].Name, qt.Equals, "parent")
+	c.Check(spans[1].Status.Code.String(), qt.Equals, "STATUS_CODE_UNSET")
+	c.Assert(spans[1].Attributes, qt.HasLen, 1)
+	c.Check(spans[1].Attributes[0].Key, qt.Equals, "test.key")
+	c.Check(spans[1].Attributes[0].Value.GetStringValue(), qt.Equals, "value")
+
+	for _, h := range collector.Headers() {
+		c.Check(h.Get("X-Test"), qt.Equals, "test-value")
+	}
+}
+
+func TestSetupTracingInvalidEndpoint(t *testing.T) {
+	c := qt.New(t)
+
+	_, err := servermon.SetupTracing(context.Background(), servermon.TracingParams{
+		Endpoint: "grpc://localhost:4317",
+	})
+	c.Check(err, qt.ErrorMatches, `tracing endpoint must be an http or https URL`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+}
+
+func TestTraceContextPropagation(t *testing.T) {
+	c := qt.New(t)
+
+	jimmtest.RecordSpans(c)
+	_, err := servermon.SetupTracing(context.Background(), servermon.TracingParams{})
+	c.Assert(err, qt.IsNil)
+
+	ctx, span := servermon.StartSpan(context.Background(), "test")
+	defer span.End()
+
+	h := make(http.Header)
+	servermon.InjectTraceContext(ctx, h)
+	c.Check(h.Get("traceparent"), qt.Not(qt.Equals), "")
+
+	_, remote := servermon.StartSpan(servermon.ExtractTraceContext(context.Background(), h), "remote")
+	defer remote.End()
+	c.Check(remote.SpanContext().TraceID(), qt.Equals, span.SpanContext().TraceID())
+}
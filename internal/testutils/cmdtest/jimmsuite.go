@@ -104,9 +104,6 @@ func (s *JimmCmdSuite) SetUpTest(c *gc.C) {
 	s.JIMM = srv.JIMM()
 	s.HTTP.Config = &http.Server{Handler: srv, ReadHeaderTimeout: time.Second * 5}
 
-	err = s.Service.StartJWKSRotator(ctx, time.NewTicker(time.Hour).C, time.Now().UTC().AddDate(0, 3, 0))
-	c.Assert(err, gc.Equals, nil)
-
 	s.HTTP.Start()
 
 	// Now we can set up the juju conn suites
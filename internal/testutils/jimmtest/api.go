@@ -14,6 +14,7 @@ import (
 	jujuparams "github.com/juju/juju/rpc/params"
 	"github.com/juju/juju/version"
 	"github.com/juju/names/v5"
+	jujuversion "github.com/juju/version/v2"
 
 	"github.com/canonical/jimm/v3/internal/dbmodel"
 	"github.com/canonical/jimm/v3/internal/errors"
@@ -160,6 +161,7 @@ type API struct {
 	Status_                            func(context.Context, []string) (*jujuparams.FullStatus, error)
 	UpdateCloud_                       func(context.Context, names.CloudTag, jujuparams.Cloud) error
 	UpdateCredential_                  func(context.Context, jujuparams.TaggedCredential) ([]jujuparams.UpdateCredentialModelResult, error)
+	UpgradeModel_                      func(context.Context, names.ModelTag, jujuversion.Number, string, bool) (jujuversion.Number, error)
 	ValidateModelUpgrade_              func(context.Context, names.ModelTag, bool) error
 	WatchAllModelSummaries_            func(context.Context) (string, error)
 	ListFilesystems_                   func(ctx context.Context, machines []string) ([]jujuparams.FilesystemDetailsListResult, error)
@@ -425,6 +427,13 @@ func (a *API) UpdateCredential(ctx context.Context, cred jujuparams.TaggedCreden
 	return a.UpdateCredential_(ctx, cred)
 }
 
+func (a *API) UpgradeModel(ctx context.Context, tag names.ModelTag, targetVersion jujuversion.Number, stream string, dryRun bool) (jujuversion.Number, error) {
+	if a.UpgradeModel_ == nil {
+		return jujuversion.Zero, errors.E(errors.CodeNotImplemented)
+	}
+	return a.UpgradeModel_(ctx, tag, targetVersion, stream, dryRun)
+}
+
 func (a *API) ValidateModelUpgrade(ctx context.Context, tag names.ModelTag, force bool) error {
 	if a.ValidateModelUpgrade_ == nil {
 		return errors.E(errors.CodeNotImplemented)
@@ -3,6 +3,7 @@
 package jimmtest
 
 import (
+	"context"
 	"time"
 
 	"github.com/google/uuid"
@@ -70,6 +71,9 @@ func NewJIMM(t Tester, additionalParameters *jimm.Parameters, options ...Option)
 			DB: PostgresDB(t, func() time.Time { return now }),
 		}
 	}
+	if err := p.Database.Migrate(context.Background(), false); err != nil {
+		t.Fatalf("migrating database: %v", err)
+	}
 	if p.CredentialStore == nil {
 		p.CredentialStore = p.Database
 	}
@@ -22,6 +22,7 @@ import (
 	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
 	"github.com/canonical/jimm/v3/internal/pubsub"
 	"github.com/canonical/jimm/v3/internal/testutils/jimmtest/mocks"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
 	jimmnames "github.com/canonical/jimm/v3/pkg/names"
 )
 
@@ -34,14 +35,20 @@ type JIMM struct {
 	mocks.ControllerService
 	mocks.LoginService
 	mocks.ModelManager
+	AddAlertReceiver_                  func(ctx context.Context, user *openfga.User, req apiparams.AddAlertReceiverRequest) (apiparams.AlertReceiver, error)
+	AddAlertRule_                      func(ctx context.Context, user *openfga.User, req apiparams.AddAlertRuleRequest) (apiparams.AlertRule, error)
+	AddAlertSilence_                   func(ctx context.Context, user *openfga.User, req apiparams.AddAlertSilenceRequest) (apiparams.AlertSilence, error)
 	AddAuditLogEntry_                  func(ale *dbmodel.AuditLogEntry)
 	AddCloudToController_              func(ctx context.Context, user *openfga.User, controllerName string, tag names.CloudTag, cloud jujuparams.Cloud, force bool) error
 	AddHostedCloud_                    func(ctx context.Context, user *openfga.User, tag names.CloudTag, cloud jujuparams.Cloud, force bool) error
 	AddServiceAccount_                 func(ctx context.Context, u *openfga.User, clientId string) error
+	AddWebhookSubscription_            func(ctx context.Context, user *openfga.User, req apiparams.AddWebhookSubscriptionRequest) (apiparams.WebhookSubscription, error)
 	Authenticate_                      func(ctx context.Context, req *jujuparams.LoginRequest) (*openfga.User, error)
+	CharmDriftReport_                  func(ctx context.Context, user *openfga.User, req apiparams.CharmDriftReportRequest) (apiparams.CharmDriftReport, error)
 	CheckPermission_                   func(ctx context.Context, user *openfga.User, cachedPerms map[string]string, desiredPerms map[string]interface{}) (map[string]string, error)
 	CopyServiceAccountCredential_      func(ctx context.Context, u *openfga.User, svcAcc *openfga.User, cloudCredentialTag names.CloudCredentialTag) (names.CloudCredentialTag, []jujuparams.UpdateCredentialModelResult, error)
 	CountIdentities_                   func(ctx context.Context, user *openfga.User) (int, error)
+	CreateUpgradeCampaign_             func(ctx context.Context, user *openfga.User, req apiparams.CreateUpgradeCampaignRequest) (apiparams.UpgradeCampaign, error)
 	DestroyOffer_                      func(ctx context.Context, user *openfga.User, offerURL string, force bool) error
 	FetchIdentity_                     func(ctx context.Context, username string) (*openfga.User, error)
 	FindApplicationOffers_             func(ctx context.Context, user *openfga.User, filters ...jujuparams.OfferFilter) ([]jujuparams.ApplicationOfferAdminDetailsV5, error)
@@ -56,6 +63,11 @@ type JIMM struct {
 	GetCloudCredentialAttributes_      func(ctx context.Context, u *openfga.User, cred *dbmodel.CloudCredential, hidden bool) (attrs map[string]string, redacted []string, err error)
 	GetCredentialStore_                func() jimmcreds.CredentialStore
 	GetJimmControllerAccess_           func(ctx context.Context, user *openfga.User, tag names.UserTag) (string, error)
+	GetModelInventory_                 func(ctx context.Context, user *openfga.User, mt names.ModelTag) (apiparams.ModelInventory, error)
+	GetModelLabels_                    func(ctx context.Context, user *openfga.User, mt names.ModelTag) (map[string]string, error)
+	GetModelQuotaUsage_                func(ctx context.Context, user *openfga.User, identityName string) ([]apiparams.ModelQuotaUsage, error)
+	GetModelTemplate_                  func(ctx context.Context, user *openfga.User, name string) (apiparams.ModelTemplate, error)
+	GetUpgradeCampaign_                func(ctx context.Context, user *openfga.User, name string) (apiparams.UpgradeCampaign, error)
 	GetUserCloudAccess_                func(ctx context.Context, user *openfga.User, cloud names.CloudTag) (string, error)
 	GetUserControllerAccess_           func(ctx context.Context, user *openfga.User, controller names.ControllerTag) (string, error)
 	GetUserModelAccess_                func(ctx context.Context, user *openfga.User, model names.ModelTag) (string, error)
@@ -67,28 +79,62 @@ type JIMM struct {
 	GroupManager_                      func() jimm.GroupManager
 	InitiateInternalMigration_         func(ctx context.Context, user *openfga.User, modelNameOrUUID string, targetController string) (jujuparams.InitiateMigrationResult, error)
 	InitiateMigration_                 func(ctx context.Context, user *openfga.User, spec jujuparams.MigrationSpec) (jujuparams.InitiateMigrationResult, error)
+	ListAlertReceivers_                func(ctx context.Context, user *openfga.User) ([]apiparams.AlertReceiver, error)
+	ListAlertRules_                    func(ctx context.Context, user *openfga.User) ([]apiparams.AlertRule, error)
+	ListAlertSilences_                 func(ctx context.Context, user *openfga.User) ([]apiparams.AlertSilence, error)
+	ListAlerts_                        func(ctx context.Context, user *openfga.User) ([]apiparams.Alert, error)
+	ListApplicationOfferConsumers_     func(ctx context.Context, user *openfga.User, offerURL, revokedUser string) ([]apiparams.OfferConsumer, error)
 	ListApplicationOffers_             func(ctx context.Context, user *openfga.User, filters ...jujuparams.OfferFilter) ([]jujuparams.ApplicationOfferAdminDetailsV5, error)
 	ListIdentities_                    func(ctx context.Context, user *openfga.User, pagination pagination.LimitOffsetPagination, match string) ([]openfga.User, error)
-	ListResources_                     func(ctx context.Context, user *openfga.User, filter pagination.LimitOffsetPagination, namePrefixFilter, typeFilter string) ([]db.Resource, error)
+	ListJobRuns_                       func(ctx context.Context, user *openfga.User, job string, limit int) ([]apiparams.JobRun, error)
+	ListModelPolicies_                 func(ctx context.Context, user *openfga.User) ([]apiparams.ModelPolicy, error)
+	ListModelQuotas_                   func(ctx context.Context, user *openfga.User) ([]apiparams.ModelQuota, error)
+	ListModelTemplates_                func(ctx context.Context, user *openfga.User) ([]apiparams.ModelTemplate, error)
+	ListResources_                     func(ctx context.Context, user *openfga.User, filter pagination.LimitOffsetPagination, namePrefixFilter, typeFilter string, labelSelector dbmodel.LabelSelector) ([]db.Resource, error)
+	ListScheduledJobs_                 func(ctx context.Context, user *openfga.User) ([]apiparams.ScheduledJob, error)
+	ListUpgradeCampaigns_              func(ctx context.Context, user *openfga.User) ([]apiparams.UpgradeCampaign, error)
+	ListWebhookDeadLetters_            func(ctx context.Context, user *openfga.User, subscription string) ([]apiparams.WebhookDeadLetter, error)
+	ListWebhookSubscriptions_          func(ctx context.Context, user *openfga.User) ([]apiparams.WebhookSubscription, error)
 	Offer_                             func(ctx context.Context, user *openfga.User, offer jimm.AddApplicationOfferParams) error
+	PauseUpgradeCampaign_              func(ctx context.Context, user *openfga.User, name string) error
 	PubSubHub_                         func() *pubsub.Hub
 	PurgeLogs_                         func(ctx context.Context, user *openfga.User, before time.Time) (int64, error)
+	QueryInventory_                    func(ctx context.Context, user *openfga.User, req apiparams.QueryInventoryRequest) ([]apiparams.InventoryApplicationResult, error)
+	RecordApplicationOfferConsumed_    func(ctx context.Context, identityName, modelUUID, offerUUID, offerURL string) error
+	RemoveAlertReceiver_               func(ctx context.Context, user *openfga.User, name string) error
+	RemoveAlertRule_                   func(ctx context.Context, user *openfga.User, name string) error
+	RemoveAlertSilence_                func(ctx context.Context, user *openfga.User, id uint) error
 	RemoveCloud_                       func(ctx context.Context, u *openfga.User, ct names.CloudTag) error
 	RemoveCloudFromController_         func(ctx context.Context, u *openfga.User, controllerName string, ct names.CloudTag) error
+	RemoveModelLabels_                 func(ctx context.Context, user *openfga.User, mt names.ModelTag, keys []string) error
+	RemoveModelPolicy_                 func(ctx context.Context, user *openfga.User, name string) error
+	RemoveModelQuota_                  func(ctx context.Context, user *openfga.User, kind, subject, region string) error
+	RemoveModelTemplate_               func(ctx context.Context, user *openfga.User, name string) error
+	RemoveUpgradeCampaign_             func(ctx context.Context, user *openfga.User, name string) error
+	RemoveWebhookSubscription_         func(ctx context.Context, user *openfga.User, name string) error
 	ResourceTag_                       func() names.ControllerTag
+	RetryWebhookDeadLetter_            func(ctx context.Context, user *openfga.User, id uint) error
 	RevokeAuditLogAccess_              func(ctx context.Context, user *openfga.User, targetUserTag names.UserTag) error
 	RevokeCloudAccess_                 func(ctx context.Context, user *openfga.User, ct names.CloudTag, ut names.UserTag, access string) error
 	RevokeCloudCredential_             func(ctx context.Context, user *dbmodel.Identity, tag names.CloudCredentialTag, force bool) error
 	RevokeModelAccess_                 func(ctx context.Context, user *openfga.User, mt names.ModelTag, ut names.UserTag, access jujuparams.UserAccessPermission) error
-	RevokeOfferAccess_                 func(ctx context.Context, user *openfga.User, offerURL string, ut names.UserTag, access jujuparams.OfferAccessPermission) (err error)
+	RevokeOfferAccess_                 func(ctx context.Context, user *openfga.User, offerURL string, ut names.UserTag, access jujuparams.OfferAccessPermission) ([]apiparams.OfferConsumer, error)
 	RoleManager_                       func() jimm.RoleManager
 	SetIdentityModelDefaults_          func(ctx context.Context, user *dbmodel.Identity, configs map[string]interface{}) error
+	SetModelLabels_                    func(ctx context.Context, user *openfga.User, mt names.ModelTag, labels map[string]string) error
+	SetModelPolicy_                    func(ctx context.Context, user *openfga.User, policy apiparams.ModelPolicy) error
+	SetModelQuota_                     func(ctx context.Context, user *openfga.User, quota apiparams.ModelQuota) error
+	SetModelTemplate_                  func(ctx context.Context, user *openfga.User, template apiparams.ModelTemplate) error
+	SetModelTTL_                       func(ctx context.Context, user *openfga.User, mt names.ModelTag, ttl time.Duration) (time.Time, error)
+	StartUpgradeCampaign_              func(ctx context.Context, user *openfga.User, name string) error
 	ToJAASTag_                         func(ctx context.Context, tag *ofganames.Tag, resolveUUIDs bool) (string, error)
+	TransferModelOwnership_            func(ctx context.Context, user *openfga.User, mt names.ModelTag, newOwner string, cloudCredentialTag names.CloudCredentialTag) error
+	TriggerScheduledJob_               func(ctx context.Context, user *openfga.User, job string) error
 	UpdateApplicationOffer_            func(ctx context.Context, controller *dbmodel.Controller, offerUUID string, removed bool) error
 	UpdateCloud_                       func(ctx context.Context, u *openfga.User, ct names.CloudTag, cloud jujuparams.Cloud) error
 	UpdateCloudCredential_             func(ctx context.Context, u *openfga.User, args jimm.UpdateCloudCredentialArgs) ([]jujuparams.UpdateCredentialModelResult, error)
 	UserLogin_                         func(ctx context.Context, identityName string) (*openfga.User, error)
-	ListModels_                        func(ctx context.Context, user *openfga.User) ([]base.UserModel, error)
+	ListModels_                        func(ctx context.Context, user *openfga.User, labelSelector dbmodel.LabelSelector) ([]base.UserModel, error)
 }
 
 func (j *JIMM) AddAuditLogEntry(ale *dbmodel.AuditLogEntry) {
@@ -313,17 +359,23 @@ func (j *JIMM) InitiateInternalMigration(ctx context.Context, user *openfga.User
 	}
 	return j.InitiateInternalMigration_(ctx, user, modelNameOrUUID, targetController)
 }
+func (j *JIMM) ListApplicationOfferConsumers(ctx context.Context, user *openfga.User, offerURL, revokedUser string) ([]apiparams.OfferConsumer, error) {
+	if j.ListApplicationOfferConsumers_ == nil {
+		return nil, errors.E(errors.CodeNotImplemented)
+	}
+	return j.ListApplicationOfferConsumers_(ctx, user, offerURL, revokedUser)
+}
 func (j *JIMM) ListApplicationOffers(ctx context.Context, user *openfga.User, filters ...jujuparams.OfferFilter) ([]jujuparams.ApplicationOfferAdminDetailsV5, error) {
 	if j.ListApplicationOffers_ == nil {
 		return nil, errors.E(errors.CodeNotImplemented)
 	}
 	return j.ListApplicationOffers_(ctx, user, filters...)
 }
-func (j *JIMM) ListResources(ctx context.Context, user *openfga.User, filter pagination.LimitOffsetPagination, namePrefixFilter, typeFilter string) ([]db.Resource, error) {
+func (j *JIMM) ListResources(ctx context.Context, user *openfga.User, filter pagination.LimitOffsetPagination, namePrefixFilter, typeFilter string, labelSelector dbmodel.LabelSelector) ([]db.Resource, error) {
 	if j.ListResources_ == nil {
 		return nil, errors.E(errors.CodeNotImplemented)
 	}
-	return j.ListResources_(ctx, user, filter, namePrefixFilter, typeFilter)
+	return j.ListResources_(ctx, user, filter, namePrefixFilter, typeFilter, labelSelector)
 }
 func (j *JIMM) Offer(ctx context.Context, user *openfga.User, offer jimm.AddApplicationOfferParams) error {
 	if j.Offer_ == nil {
@@ -343,6 +395,12 @@ func (j *JIMM) PurgeLogs(ctx context.Context, user *openfga.User, before time.Ti
 	}
 	return j.PurgeLogs_(ctx, user, before)
 }
+func (j *JIMM) RecordApplicationOfferConsumed(ctx context.Context, identityName, modelUUID, offerUUID, offerURL string) error {
+	if j.RecordApplicationOfferConsumed_ == nil {
+		return errors.E(errors.CodeNotImplemented)
+	}
+	return j.RecordApplicationOfferConsumed_(ctx, identityName, modelUUID, offerUUID, offerURL)
+}
 func (j *JIMM) RemoveCloud(ctx context.Context, u *openfga.User, ct names.CloudTag) error {
 	if j.RemoveCloud_ == nil {
 		return errors.E(errors.CodeNotImplemented)
@@ -385,9 +443,9 @@ func (j *JIMM) RevokeModelAccess(ctx context.Context, user *openfga.User, mt nam
 	}
 	return j.RevokeModelAccess_(ctx, user, mt, ut, access)
 }
-func (j *JIMM) RevokeOfferAccess(ctx context.Context, user *openfga.User, offerURL string, ut names.UserTag, access jujuparams.OfferAccessPermission) (err error) {
+func (j *JIMM) RevokeOfferAccess(ctx context.Context, user *openfga.User, offerURL string, ut names.UserTag, access jujuparams.OfferAccessPermission) ([]apiparams.OfferConsumer, error) {
 	if j.RevokeOfferAccess_ == nil {
-		return errors.E(errors.CodeNotImplemented)
+		return nil, errors.E(errors.CodeNotImplemented)
 	}
 	return j.RevokeOfferAccess_(ctx, user, offerURL, ut, access)
 }
@@ -428,9 +486,267 @@ func (j *JIMM) UserLogin(ctx context.Context, identityName string) (*openfga.Use
 	}
 	return j.UserLogin_(ctx, identityName)
 }
-func (j *JIMM) ListModels(ctx context.Context, user *openfga.User) ([]base.UserModel, error) {
+func (j *JIMM) ListModels(ctx context.Context, user *openfga.User, labelSelector dbmodel.LabelSelector) ([]base.UserModel, error) {
 	if j.ListModels_ == nil {
 		return nil, errors.E(errors.CodeNotImplemented)
 	}
-	return j.ListModels_(ctx, user)
+	return j.ListModels_(ctx, user, labelSelector)
+}
+func (j *JIMM) GetModelQuotaUsage(ctx context.Context, user *openfga.User, identityName string) ([]apiparams.ModelQuotaUsage, error) {
+	if j.GetModelQuotaUsage_ == nil {
+		return nil, errors.E(errors.CodeNotImplemented)
+	}
+	return j.GetModelQuotaUsage_(ctx, user, identityName)
+}
+func (j *JIMM) ListModelQuotas(ctx context.Context, user *openfga.User) ([]apiparams.ModelQuota, error) {
+	if j.ListModelQuotas_ == nil {
+		return nil, errors.E(errors.CodeNotImplemented)
+	}
+	return j.ListModelQuotas_(ctx, user)
+}
+func (j *JIMM) RemoveModelQuota(ctx context.Context, user *openfga.User, kind, subject, region string) error {
+	if j.RemoveModelQuota_ == nil {
+		return errors.E(errors.CodeNotImplemented)
+	}
+	return j.RemoveModelQuota_(ctx, user, kind, subject, region)
+}
+func (j *JIMM) SetModelQuota(ctx context.Context, user *openfga.User, quota apiparams.ModelQuota) error {
+	if j.SetModelQuota_ == nil {
+		return errors.E(errors.CodeNotImplemented)
+	}
+	return j.SetModelQuota_(ctx, user, quota)
+}
+func (j *JIMM) SetModelTTL(ctx context.Context, user *openfga.User, mt names.ModelTag, ttl time.Duration) (time.Time, error) {
+	if j.SetModelTTL_ == nil {
+		return time.Time{}, errors.E(errors.CodeNotImplemented)
+	}
+	return j.SetModelTTL_(ctx, user, mt, ttl)
+}
+func (j *JIMM) GetModelLabels(ctx context.Context, user *openfga.User, mt names.ModelTag) (map[string]string, error) {
+	if j.GetModelLabels_ == nil {
+		return nil, errors.E(errors.CodeNotImplemented)
+	}
+	return j.GetModelLabels_(ctx, user, mt)
+}
+func (j *JIMM) RemoveModelLabels(ctx context.Context, user *openfga.User, mt names.ModelTag, keys []string) error {
+	if j.RemoveModelLabels_ == nil {
+		return errors.E(errors.CodeNotImplemented)
+	}
+	return j.RemoveModelLabels_(ctx, user, mt, keys)
+}
+func (j *JIMM) SetModelLabels(ctx context.Context, user *openfga.User, mt names.ModelTag, labels map[string]string) error {
+	if j.SetModelLabels_ == nil {
+		return errors.E(errors.CodeNotImplemented)
+	}
+	return j.SetModelLabels_(ctx, user, mt, labels)
+}
+func (j *JIMM) GetModelTemplate(ctx context.Context, user *openfga.User, name string) (apiparams.ModelTemplate, error) {
+	if j.GetModelTemplate_ == nil {
+		return apiparams.ModelTemplate{}, errors.E(errors.CodeNotImplemented)
+	}
+	return j.GetModelTemplate_(ctx, user, name)
+}
+func (j *JIMM) ListModelTemplates(ctx context.Context, user *openfga.User) ([]apiparams.ModelTemplate, error) {
+	if j.ListModelTemplates_ == nil {
+		return nil, errors.E(errors.CodeNotImplemented)
+	}
+	return j.ListModelTemplates_(ctx, user)
+}
+func (j *JIMM) RemoveModelTemplate(ctx context.Context, user *openfga.User, name string) error {
+	if j.RemoveModelTemplate_ == nil {
+		return errors.E(errors.CodeNotImplemented)
+	}
+	return j.RemoveModelTemplate_(ctx, user, name)
+}
+func (j *JIMM) SetModelTemplate(ctx context.Context, user *openfga.User, template apiparams.ModelTemplate) error {
+	if j.SetModelTemplate_ == nil {
+		return errors.E(errors.CodeNotImplemented)
+	}
+	return j.SetModelTemplate_(ctx, user, template)
+}
+func (j *JIMM) ListModelPolicies(ctx context.Context, user *openfga.User) ([]apiparams.ModelPolicy, error) {
+	if j.ListModelPolicies_ == nil {
+		return nil, errors.E(errors.CodeNotImplemented)
+	}
+	return j.ListModelPolicies_(ctx, user)
+}
+func (j *JIMM) RemoveModelPolicy(ctx context.Context, user *openfga.User, name string) error {
+	if j.RemoveModelPolicy_ == nil {
+		return errors.E(errors.CodeNotImplemented)
+	}
+	return j.RemoveModelPolicy_(ctx, user, name)
+}
+func (j *JIMM) SetModelPolicy(ctx context.Context, user *openfga.User, policy apiparams.ModelPolicy) error {
+	if j.SetModelPolicy_ == nil {
+		return errors.E(errors.CodeNotImplemented)
+	}
+	return j.SetModelPolicy_(ctx, user, policy)
+}
+func (j *JIMM) GetModelInventory(ctx context.Context, user *openfga.User, mt names.ModelTag) (apiparams.ModelInventory, error) {
+	if j.GetModelInventory_ == nil {
+		return apiparams.ModelInventory{}, errors.E(errors.CodeNotImplemented)
+	}
+	return j.GetModelInventory_(ctx, user, mt)
+}
+func (j *JIMM) QueryInventory(ctx context.Context, user *openfga.User, req apiparams.QueryInventoryRequest) ([]apiparams.InventoryApplicationResult, error) {
+	if j.QueryInventory_ == nil {
+		return nil, errors.E(errors.CodeNotImplemented)
+	}
+	return j.QueryInventory_(ctx, user, req)
+}
+func (j *JIMM) CharmDriftReport(ctx context.Context, user *openfga.User, req apiparams.CharmDriftReportRequest) (apiparams.CharmDriftReport, error) {
+	if j.CharmDriftReport_ == nil {
+		return apiparams.CharmDriftReport{}, errors.E(errors.CodeNotImplemented)
+	}
+	return j.CharmDriftReport_(ctx, user, req)
+}
+func (j *JIMM) CreateUpgradeCampaign(ctx context.Context, user *openfga.User, req apiparams.CreateUpgradeCampaignRequest) (apiparams.UpgradeCampaign, error) {
+	if j.CreateUpgradeCampaign_ == nil {
+		return apiparams.UpgradeCampaign{}, errors.E(errors.CodeNotImplemented)
+	}
+	return j.CreateUpgradeCampaign_(ctx, user, req)
+}
+func (j *JIMM) GetUpgradeCampaign(ctx context.Context, user *openfga.User, name string) (apiparams.UpgradeCampaign, error) {
+	if j.GetUpgradeCampaign_ == nil {
+		return apiparams.UpgradeCampaign{}, errors.E(errors.CodeNotImplemented)
+	}
+	return j.GetUpgradeCampaign_(ctx, user, name)
+}
+func (j *JIMM) ListUpgradeCampaigns(ctx context.Context, user *openfga.User) ([]apiparams.UpgradeCampaign, error) {
+	if j.ListUpgradeCampaigns_ == nil {
+		return nil, errors.E(errors.CodeNotImplemented)
+	}
+	return j.ListUpgradeCampaigns_(ctx, user)
+}
+func (j *JIMM) PauseUpgradeCampaign(ctx context.Context, user *openfga.User, name string) error {
+	if j.PauseUpgradeCampaign_ == nil {
+		return errors.E(errors.CodeNotImplemented)
+	}
+	return j.PauseUpgradeCampaign_(ctx, user, name)
+}
+func (j *JIMM) RemoveUpgradeCampaign(ctx context.Context, user *openfga.User, name string) error {
+	if j.RemoveUpgradeCampaign_ == nil {
+		return errors.E(errors.CodeNotImplemented)
+	}
+	return j.RemoveUpgradeCampaign_(ctx, user, name)
+}
+func (j *JIMM) StartUpgradeCampaign(ctx context.Context, user *openfga.User, name string) error {
+	if j.StartUpgradeCampaign_ == nil {
+		return errors.E(errors.CodeNotImplemented)
+	}
+	return j.StartUpgradeCampaign_(ctx, user, name)
+}
+func (j *JIMM) TransferModelOwnership(ctx context.Context, user *openfga.User, mt names.ModelTag, newOwner string, cloudCredentialTag names.CloudCredentialTag) error {
+	if j.TransferModelOwnership_ == nil {
+		return errors.E(errors.CodeNotImplemented)
+	}
+	return j.TransferModelOwnership_(ctx, user, mt, newOwner, cloudCredentialTag)
+}
+func (j *JIMM) AddWebhookSubscription(ctx context.Context, user *openfga.User, req apiparams.AddWebhookSubscriptionRequest) (apiparams.WebhookSubscription, error) {
+	if j.AddWebhookSubscription_ == nil {
+		return apiparams.WebhookSubscription{}, errors.E(errors.CodeNotImplemented)
+	}
+	return j.AddWebhookSubscription_(ctx, user, req)
+}
+func (j *JIMM) ListWebhookDeadLetters(ctx context.Context, user *openfga.User, subscription string) ([]apiparams.WebhookDeadLetter, error) {
+	if j.ListWebhookDeadLetters_ == nil {
+		return nil, errors.E(errors.CodeNotImplemented)
+	}
+	return j.ListWebhookDeadLetters_(ctx, user, subscription)
+}
+func (j *JIMM) ListWebhookSubscriptions(ctx context.Context, user *openfga.User) ([]apiparams.WebhookSubscription, error) {
+	if j.ListWebhookSubscriptions_ == nil {
+		return nil, errors.E(errors.CodeNotImplemented)
+	}
+	return j.ListWebhookSubscriptions_(ctx, user)
+}
+func (j *JIMM) RemoveWebhookSubscription(ctx context.Context, user *openfga.User, name string) error {
+	if j.RemoveWebhookSubscription_ == nil {
+		return errors.E(errors.CodeNotImplemented)
+	}
+	return j.RemoveWebhookSubscription_(ctx, user, name)
+}
+func (j *JIMM) RetryWebhookDeadLetter(ctx context.Context, user *openfga.User, id uint) error {
+	if j.RetryWebhookDeadLetter_ == nil {
+		return errors.E(errors.CodeNotImplemented)
+	}
+	return j.RetryWebhookDeadLetter_(ctx, user, id)
+}
+func (j *JIMM) ListJobRuns(ctx context.Context, user *openfga.User, job string, limit int) ([]apiparams.JobRun, error) {
+	if j.ListJobRuns_ == nil {
+		return nil, errors.E(errors.CodeNotImplemented)
+	}
+	return j.ListJobRuns_(ctx, user, job, limit)
+}
+func (j *JIMM) ListScheduledJobs(ctx context.Context, user *openfga.User) ([]apiparams.ScheduledJob, error) {
+	if j.ListScheduledJobs_ == nil {
+		return nil, errors.E(errors.CodeNotImplemented)
+	}
+	return j.ListScheduledJobs_(ctx, user)
+}
+func (j *JIMM) TriggerScheduledJob(ctx context.Context, user *openfga.User, job string) error {
+	if j.TriggerScheduledJob_ == nil {
+		return errors.E(errors.CodeNotImplemented)
+	}
+	return j.TriggerScheduledJob_(ctx, user, job)
+}
+func (j *JIMM) AddAlertReceiver(ctx context.Context, user *openfga.User, req apiparams.AddAlertReceiverRequest) (apiparams.AlertReceiver, error) {
+	if j.AddAlertReceiver_ == nil {
+		return apiparams.AlertReceiver{}, errors.E(errors.CodeNotImplemented)
+	}
+	return j.AddAlertReceiver_(ctx, user, req)
+}
+func (j *JIMM) ListAlertReceivers(ctx context.Context, user *openfga.User) ([]apiparams.AlertReceiver, error) {
+	if j.ListAlertReceivers_ == nil {
+		return nil, errors.E(errors.CodeNotImplemented)
+	}
+	return j.ListAlertReceivers_(ctx, user)
+}
+func (j *JIMM) RemoveAlertReceiver(ctx context.Context, user *openfga.User, name string) error {
+	if j.RemoveAlertReceiver_ == nil {
+		return errors.E(errors.CodeNotImplemented)
+	}
+	return j.RemoveAlertReceiver_(ctx, user, name)
+}
+func (j *JIMM) AddAlertRule(ctx context.Context, user *openfga.User, req apiparams.AddAlertRuleRequest) (apiparams.AlertRule, error) {
+	if j.AddAlertRule_ == nil {
+		return apiparams.AlertRule{}, errors.E(errors.CodeNotImplemented)
+	}
+	return j.AddAlertRule_(ctx, user, req)
+}
+func (j *JIMM) ListAlertRules(ctx context.Context, user *openfga.User) ([]apiparams.AlertRule, error) {
+	if j.ListAlertRules_ == nil {
+		return nil, errors.E(errors.CodeNotImplemented)
+	}
+	return j.ListAlertRules_(ctx, user)
+}
+func (j *JIMM) RemoveAlertRule(ctx context.Context, user *openfga.User, name string) error {
+	if j.RemoveAlertRule_ == nil {
+		return errors.E(errors.CodeNotImplemented)
+	}
+	return j.RemoveAlertRule_(ctx, user, name)
+}
+func (j *JIMM) ListAlerts(ctx context.Context, user *openfga.User) ([]apiparams.Alert, error) {
+	if j.ListAlerts_ == nil {
+		return nil, errors.E(errors.CodeNotImplemented)
+	}
+	return j.ListAlerts_(ctx, user)
+}
+func (j *JIMM) AddAlertSilence(ctx context.Context, user *openfga.User, req apiparams.AddAlertSilenceRequest) (apiparams.AlertSilence, error) {
+	if j.AddAlertSilence_ == nil {
+		return apiparams.AlertSilence{}, errors.E(errors.CodeNotImplemented)
+	}
+	return j.AddAlertSilence_(ctx, user, req)
+}
+func (j *JIMM) ListAlertSilences(ctx context.Context, user *openfga.User) ([]apiparams.AlertSilence, error) {
+	if j.ListAlertSilences_ == nil {
+		return nil, errors.E(errors.CodeNotImplemented)
+	}
+	return j.ListAlertSilences_(ctx, user)
+}
+func (j *JIMM) RemoveAlertSilence(ctx context.Context, user *openfga.User, id uint) error {
+	if j.RemoveAlertSilence_ == nil {
+		return errors.E(errors.CodeNotImplemented)
+	}
+	return j.RemoveAlertSilence_(ctx, user, id)
 }
@@ -19,6 +19,7 @@ type ControllerService struct {
 	ListControllers_           func(ctx context.Context, user *openfga.User) ([]dbmodel.Controller, error)
 	RemoveController_          func(ctx context.Context, user *openfga.User, controllerName string, force bool) error
 	SetControllerDeprecated_   func(ctx context.Context, user *openfga.User, controllerName string, deprecated bool) error
+	SetControllerMaintenance_  func(ctx context.Context, user *openfga.User, controllerName string, m dbmodel.ControllerMaintenance) error
 }
 
 func (j *ControllerService) AddController(ctx context.Context, u *openfga.User, ctl *dbmodel.Controller) error {
@@ -62,3 +63,10 @@ func (j *ControllerService) SetControllerDeprecated(ctx context.Context, user *o
 	}
 	return j.SetControllerDeprecated_(ctx, user, controllerName, deprecated)
 }
+
+func (j *ControllerService) SetControllerMaintenance(ctx context.Context, user *openfga.User, controllerName string, m dbmodel.ControllerMaintenance) error {
+	if j.SetControllerMaintenance_ == nil {
+		return errors.E(errors.CodeNotImplemented)
+	}
+	return j.SetControllerMaintenance_(ctx, user, controllerName, m)
+}
@@ -131,7 +131,7 @@ func (s *JIMMSuite) SetUpTest(c *gc.C) {
 	u, _ := url.Parse(s.Server.URL)
 
 	jwksService := jimmjwx.NewJWKSService(credentialStore)
-	err = jwksService.StartJWKSRotator(ctx, time.NewTicker(time.Hour).C, time.Now().UTC().AddDate(0, 3, 0))
+	err = jwksService.RotateJWKS(ctx, time.Now().UTC().AddDate(0, 3, 0))
 	c.Assert(err, gc.Equals, nil)
 
 	jwtService := jimmjwx.NewJWTService(jimmjwx.JWTServiceParams{
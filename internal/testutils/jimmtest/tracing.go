@@ -0,0 +1,98 @@
+// Copyright 2024 Canonical.
+
+package jimmtest
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	collectortracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// RecordSpans installs a global tracer provider that records every span
+// started for the remainder of the test. The previous tracer provider is
+// restored when the test finishes.
+func RecordSpans(t Tester) *tracetest.SpanRecorder {
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(tp)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prev)
+	})
+	return sr
+}
+
+// An OTLPCollector is a stand-in for an OpenTelemetry collector that
+// accepts traces exported using OTLP/HTTP with protobuf encoding.
+type OTLPCollector struct {
+	// URL is the base URL of the collector, suitable for use as a
+	// tracing endpoint.
+	URL string
+
+	mu      sync.Mutex
+	spans   []*tracepb.ResourceSpans
+	headers []http.Header
+}
+
+// NewOTLPCollector starts a collector stand-in that is stopped when the
+// test finishes.
+func NewOTLPCollector(t Tester) *OTLPCollector {
+	c := new(OTLPCollector)
+	srv := httptest.NewServer(http.HandlerFunc(c.serveTraces))
+	t.Cleanup(srv.Close)
+	c.URL = srv.URL
+	return c
+}
+
+func (c *OTLPCollector) serveTraces(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost || req.URL.Path != "/v1/traces" {
+		http.NotFound(w, req)
+		return
+	}
+	buf, err := io.ReadAll(req.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var exportReq collectortracepb.ExportTraceServiceRequest
+	if err := proto.Unmarshal(buf, &exportReq); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	c.mu.Lock()
+	c.spans = append(c.spans, exportReq.ResourceSpans...)
+	c.headers = append(c.headers, req.Header.Clone())
+	c.mu.Unlock()
+
+	resp, err := proto.Marshal(&collectortracepb.ExportTraceServiceResponse{})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/x-protobuf")
+	_, _ = w.Write(resp)
+}
+
+// ResourceSpans returns all the spans received by the collector, grouped
+// by the resource that sent them.
+func (c *OTLPCollector) ResourceSpans() []*tracepb.ResourceSpans {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]*tracepb.ResourceSpans(nil), c.spans...)
+}
+
+// Headers returns the headers of every export request received by the
+// collector.
+func (c *OTLPCollector) Headers() []http.Header {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]http.Header(nil), c.headers...)
+}
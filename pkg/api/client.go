@@ -95,6 +95,13 @@ func (c *Client) SetControllerDeprecated(req *params.SetControllerDeprecatedRequ
 	return info, err
 }
 
+// SetControllerMaintenance sets the maintenance state of a controller.
+func (c *Client) SetControllerMaintenance(req *params.SetControllerMaintenanceRequest) (params.ControllerInfo, error) {
+	var info params.ControllerInfo
+	err := c.caller.APICall("JIMM", 4, "", "SetControllerMaintenance", req, &info)
+	return info, err
+}
+
 // FullModelStatus returns the full status of the juju model.
 func (c *Client) FullModelStatus(req *params.FullModelStatusRequest) (jujuparams.FullStatus, error) {
 	var status jujuparams.FullStatus
@@ -264,6 +271,309 @@ func (c *Client) GrantServiceAccountAccess(req *params.GrantServiceAccountAccess
 	return c.caller.APICall("JIMM", 4, "", "GrantServiceAccountAccess", req, nil)
 }
 
+// ListOfferConsumers lists the consumers of an application offer.
+func (c *Client) ListOfferConsumers(req *params.ListOfferConsumersRequest) (*params.ListOfferConsumersResponse, error) {
+	var response params.ListOfferConsumersResponse
+	err := c.caller.APICall("JIMM", 4, "", "ListOfferConsumers", req, &response)
+	return &response, err
+}
+
+// RevokeOfferAccess revokes a user's access to an application offer and
+// returns the consumers with relations that relied on it.
+func (c *Client) RevokeOfferAccess(req *params.RevokeOfferAccessRequest) (*params.RevokeOfferAccessResponse, error) {
+	var response params.RevokeOfferAccessResponse
+	err := c.caller.APICall("ApplicationOffers", 4, "", "RevokeOfferAccess", req, &response)
+	return &response, err
+}
+
+// SetModelQuota creates, or updates, a model quota.
+func (c *Client) SetModelQuota(req *params.SetModelQuotaRequest) error {
+	return c.caller.APICall("JIMM", 4, "", "SetModelQuota", req, nil)
+}
+
+// RemoveModelQuota removes a model quota.
+func (c *Client) RemoveModelQuota(req *params.RemoveModelQuotaRequest) error {
+	return c.caller.APICall("JIMM", 4, "", "RemoveModelQuota", req, nil)
+}
+
+// ListModelQuotas lists all the model quotas.
+func (c *Client) ListModelQuotas() (*params.ListModelQuotasResponse, error) {
+	var response params.ListModelQuotasResponse
+	err := c.caller.APICall("JIMM", 4, "", "ListModelQuotas", nil, &response)
+	return &response, err
+}
+
+// GetModelQuotaUsage returns the model quotas that apply to an identity
+// and the identity's usage against them.
+func (c *Client) GetModelQuotaUsage(req *params.ModelQuotaUsageRequest) (*params.ModelQuotaUsageResponse, error) {
+	var response params.ModelQuotaUsageResponse
+	err := c.caller.APICall("JIMM", 4, "", "GetModelQuotaUsage", req, &response)
+	return &response, err
+}
+
+// SetModelTTL sets an ephemeral model to expire once the given TTL has
+// passed.
+func (c *Client) SetModelTTL(req *params.SetModelTTLRequest) (*params.SetModelTTLResponse, error) {
+	var response params.SetModelTTLResponse
+	err := c.caller.APICall("JIMM", 4, "", "SetModelTTL", req, &response)
+	return &response, err
+}
+
+// SetModelLabels sets labels on a model.
+func (c *Client) SetModelLabels(req *params.SetModelLabelsRequest) error {
+	return c.caller.APICall("JIMM", 4, "", "SetModelLabels", req, nil)
+}
+
+// RemoveModelLabels removes labels from a model.
+func (c *Client) RemoveModelLabels(req *params.RemoveModelLabelsRequest) error {
+	return c.caller.APICall("JIMM", 4, "", "RemoveModelLabels", req, nil)
+}
+
+// GetModelLabels returns the labels of a model.
+func (c *Client) GetModelLabels(req *params.GetModelLabelsRequest) (*params.GetModelLabelsResponse, error) {
+	var response params.GetModelLabelsResponse
+	err := c.caller.APICall("JIMM", 4, "", "GetModelLabels", req, &response)
+	return &response, err
+}
+
+// ListModelsByLabel lists the models matching a label selector.
+func (c *Client) ListModelsByLabel(req *params.ListModelsByLabelRequest) (*jujuparams.UserModelList, error) {
+	var response jujuparams.UserModelList
+	err := c.caller.APICall("JIMM", 4, "", "ListModelsByLabel", req, &response)
+	return &response, err
+}
+
+// ListModelSummariesByLabel returns summaries of the models matching a
+// label selector.
+func (c *Client) ListModelSummariesByLabel(req *params.ListModelsByLabelRequest) (*jujuparams.ModelSummaryResults, error) {
+	var response jujuparams.ModelSummaryResults
+	err := c.caller.APICall("JIMM", 4, "", "ListModelSummariesByLabel", req, &response)
+	return &response, err
+}
+
+// SetModelTemplate creates, or replaces, a model template.
+func (c *Client) SetModelTemplate(req *params.SetModelTemplateRequest) error {
+	return c.caller.APICall("JIMM", 4, "", "SetModelTemplate", req, nil)
+}
+
+// RemoveModelTemplate removes a model template.
+func (c *Client) RemoveModelTemplate(req *params.RemoveModelTemplateRequest) error {
+	return c.caller.APICall("JIMM", 4, "", "RemoveModelTemplate", req, nil)
+}
+
+// GetModelTemplate returns a model template.
+func (c *Client) GetModelTemplate(req *params.GetModelTemplateRequest) (*params.ModelTemplate, error) {
+	var response params.ModelTemplate
+	err := c.caller.APICall("JIMM", 4, "", "GetModelTemplate", req, &response)
+	return &response, err
+}
+
+// ListModelTemplates lists all the model templates.
+func (c *Client) ListModelTemplates() (*params.ListModelTemplatesResponse, error) {
+	var response params.ListModelTemplatesResponse
+	err := c.caller.APICall("JIMM", 4, "", "ListModelTemplates", nil, &response)
+	return &response, err
+}
+
+// SetModelPolicy creates, or replaces, a model policy.
+func (c *Client) SetModelPolicy(req *params.SetModelPolicyRequest) error {
+	return c.caller.APICall("JIMM", 4, "", "SetModelPolicy", req, nil)
+}
+
+// RemoveModelPolicy removes a model policy.
+func (c *Client) RemoveModelPolicy(req *params.RemoveModelPolicyRequest) error {
+	return c.caller.APICall("JIMM", 4, "", "RemoveModelPolicy", req, nil)
+}
+
+// ListModelPolicies lists all the model policies.
+func (c *Client) ListModelPolicies() (*params.ListModelPoliciesResponse, error) {
+	var response params.ListModelPoliciesResponse
+	err := c.caller.APICall("JIMM", 4, "", "ListModelPolicies", nil, &response)
+	return &response, err
+}
+
+// GetModelInventory returns the most recently collected inventory of a
+// model.
+func (c *Client) GetModelInventory(req *params.GetModelInventoryRequest) (*params.ModelInventory, error) {
+	var response params.ModelInventory
+	err := c.caller.APICall("JIMM", 4, "", "GetModelInventory", req, &response)
+	return &response, err
+}
+
+// QueryInventory finds applications across all model inventories.
+func (c *Client) QueryInventory(req *params.QueryInventoryRequest) (*params.QueryInventoryResponse, error) {
+	var response params.QueryInventoryResponse
+	err := c.caller.APICall("JIMM", 4, "", "QueryInventory", req, &response)
+	return &response, err
+}
+
+// CharmDriftReport reports the charm revisions deployed across the
+// estate.
+func (c *Client) CharmDriftReport(req *params.CharmDriftReportRequest) (*params.CharmDriftReport, error) {
+	var response params.CharmDriftReport
+	err := c.caller.APICall("JIMM", 4, "", "CharmDriftReport", req, &response)
+	return &response, err
+}
+
+// CreateUpgradeCampaign creates a model upgrade campaign.
+func (c *Client) CreateUpgradeCampaign(req *params.CreateUpgradeCampaignRequest) (*params.UpgradeCampaign, error) {
+	var response params.UpgradeCampaign
+	err := c.caller.APICall("JIMM", 4, "", "CreateUpgradeCampaign", req, &response)
+	return &response, err
+}
+
+// StartUpgradeCampaign starts, or resumes, a model upgrade campaign.
+func (c *Client) StartUpgradeCampaign(req *params.UpgradeCampaignRequest) error {
+	return c.caller.APICall("JIMM", 4, "", "StartUpgradeCampaign", req, nil)
+}
+
+// PauseUpgradeCampaign pauses a running model upgrade campaign.
+func (c *Client) PauseUpgradeCampaign(req *params.UpgradeCampaignRequest) error {
+	return c.caller.APICall("JIMM", 4, "", "PauseUpgradeCampaign", req, nil)
+}
+
+// GetUpgradeCampaign returns a model upgrade campaign, including the
+// state of each of its models.
+func (c *Client) GetUpgradeCampaign(req *params.UpgradeCampaignRequest) (*params.UpgradeCampaign, error) {
+	var response params.UpgradeCampaign
+	err := c.caller.APICall("JIMM", 4, "", "GetUpgradeCampaign", req, &response)
+	return &response, err
+}
+
+// ListUpgradeCampaigns lists all the model upgrade campaigns.
+func (c *Client) ListUpgradeCampaigns() (*params.ListUpgradeCampaignsResponse, error) {
+	var response params.ListUpgradeCampaignsResponse
+	err := c.caller.APICall("JIMM", 4, "", "ListUpgradeCampaigns", nil, &response)
+	return &response, err
+}
+
+// RemoveUpgradeCampaign removes a model upgrade campaign.
+func (c *Client) RemoveUpgradeCampaign(req *params.UpgradeCampaignRequest) error {
+	return c.caller.APICall("JIMM", 4, "", "RemoveUpgradeCampaign", req, nil)
+}
+
+// TransferModelOwnership transfers the ownership of a model to another
+// user.
+func (c *Client) TransferModelOwnership(req *params.TransferModelOwnershipRequest) error {
+	return c.caller.APICall("JIMM", 4, "", "TransferModelOwnership", req, nil)
+}
+
+// AddWebhookSubscription subscribes a webhook to model lifecycle events.
+func (c *Client) AddWebhookSubscription(req *params.AddWebhookSubscriptionRequest) (*params.WebhookSubscription, error) {
+	var response params.WebhookSubscription
+	err := c.caller.APICall("JIMM", 4, "", "AddWebhookSubscription", req, &response)
+	return &response, err
+}
+
+// ListWebhookSubscriptions lists all the webhook subscriptions.
+func (c *Client) ListWebhookSubscriptions() (*params.ListWebhookSubscriptionsResponse, error) {
+	var response params.ListWebhookSubscriptionsResponse
+	err := c.caller.APICall("JIMM", 4, "", "ListWebhookSubscriptions", nil, &response)
+	return &response, err
+}
+
+// RemoveWebhookSubscription removes a webhook subscription.
+func (c *Client) RemoveWebhookSubscription(req *params.RemoveWebhookSubscriptionRequest) error {
+	return c.caller.APICall("JIMM", 4, "", "RemoveWebhookSubscription", req, nil)
+}
+
+// ListWebhookDeadLetters lists the events that could not be delivered to
+// webhooks.
+func (c *Client) ListWebhookDeadLetters(req *params.ListWebhookDeadLettersRequest) (*params.ListWebhookDeadLettersResponse, error) {
+	var response params.ListWebhookDeadLettersResponse
+	err := c.caller.APICall("JIMM", 4, "", "ListWebhookDeadLetters", req, &response)
+	return &response, err
+}
+
+// RetryWebhookDeadLetter queues an event that could not be delivered to
+// be delivered again.
+func (c *Client) RetryWebhookDeadLetter(req *params.RetryWebhookDeadLetterRequest) error {
+	return c.caller.APICall("JIMM", 4, "", "RetryWebhookDeadLetter", req, nil)
+}
+
+// ListScheduledJobs lists the scheduled background jobs.
+func (c *Client) ListScheduledJobs() (*params.ListScheduledJobsResponse, error) {
+	var response params.ListScheduledJobsResponse
+	err := c.caller.APICall("JIMM", 4, "", "ListScheduledJobs", nil, &response)
+	return &response, err
+}
+
+// ListJobRuns lists the most recent runs of a scheduled job.
+func (c *Client) ListJobRuns(req *params.ListJobRunsRequest) (*params.ListJobRunsResponse, error) {
+	var response params.ListJobRunsResponse
+	err := c.caller.APICall("JIMM", 4, "", "ListJobRuns", req, &response)
+	return &response, err
+}
+
+// TriggerScheduledJob requests that a scheduled job is run immediately.
+func (c *Client) TriggerScheduledJob(req *params.TriggerScheduledJobRequest) error {
+	return c.caller.APICall("JIMM", 4, "", "TriggerScheduledJob", req, nil)
+}
+
+// AddAlertReceiver adds a destination for alert notifications.
+func (c *Client) AddAlertReceiver(req *params.AddAlertReceiverRequest) (*params.AlertReceiver, error) {
+	var response params.AlertReceiver
+	err := c.caller.APICall("JIMM", 4, "", "AddAlertReceiver", req, &response)
+	return &response, err
+}
+
+// ListAlertReceivers lists the alert receivers.
+func (c *Client) ListAlertReceivers() (*params.ListAlertReceiversResponse, error) {
+	var response params.ListAlertReceiversResponse
+	err := c.caller.APICall("JIMM", 4, "", "ListAlertReceivers", nil, &response)
+	return &response, err
+}
+
+// RemoveAlertReceiver removes an alert receiver.
+func (c *Client) RemoveAlertReceiver(req *params.RemoveAlertReceiverRequest) error {
+	return c.caller.APICall("JIMM", 4, "", "RemoveAlertReceiver", req, nil)
+}
+
+// AddAlertRule adds an alert rule.
+func (c *Client) AddAlertRule(req *params.AddAlertRuleRequest) (*params.AlertRule, error) {
+	var response params.AlertRule
+	err := c.caller.APICall("JIMM", 4, "", "AddAlertRule", req, &response)
+	return &response, err
+}
+
+// ListAlertRules lists the alert rules.
+func (c *Client) ListAlertRules() (*params.ListAlertRulesResponse, error) {
+	var response params.ListAlertRulesResponse
+	err := c.caller.APICall("JIMM", 4, "", "ListAlertRules", nil, &response)
+	return &response, err
+}
+
+// RemoveAlertRule removes an alert rule.
+func (c *Client) RemoveAlertRule(req *params.RemoveAlertRuleRequest) error {
+	return c.caller.APICall("JIMM", 4, "", "RemoveAlertRule", req, nil)
+}
+
+// ListAlerts lists the alerts that are pending or firing.
+func (c *Client) ListAlerts() (*params.ListAlertsResponse, error) {
+	var response params.ListAlertsResponse
+	err := c.caller.APICall("JIMM", 4, "", "ListAlerts", nil, &response)
+	return &response, err
+}
+
+// AddAlertSilence silences notifications for matching alerts.
+func (c *Client) AddAlertSilence(req *params.AddAlertSilenceRequest) (*params.AlertSilence, error) {
+	var response params.AlertSilence
+	err := c.caller.APICall("JIMM", 4, "", "AddAlertSilence", req, &response)
+	return &response, err
+}
+
+// ListAlertSilences lists the alert silences that have not ended.
+func (c *Client) ListAlertSilences() (*params.ListAlertSilencesResponse, error) {
+	var response params.ListAlertSilencesResponse
+	err := c.caller.APICall("JIMM", 4, "", "ListAlertSilences", nil, &response)
+	return &response, err
+}
+
+// RemoveAlertSilence removes an alert silence.
+func (c *Client) RemoveAlertSilence(req *params.RemoveAlertSilenceRequest) error {
+	return c.caller.APICall("JIMM", 4, "", "RemoveAlertSilence", req, nil)
+}
+
 // Version returns version info of the controller.
 func (c *Client) Version() (params.VersionResponse, error) {
 	var response params.VersionResponse
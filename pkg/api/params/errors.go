@@ -3,5 +3,8 @@
 package params
 
 const (
-	CodeStillAlive = "still alive"
+	CodeStillAlive            = "still alive"
+	CodeQuotaExceeded         = "quota exceeded"
+	CodePolicyViolation       = "policy violation"
+	CodeControllerMaintenance = "controller maintenance"
 )
@@ -176,7 +176,9 @@ type ControllerInfo struct {
 	AgentVersion string `json:"agent-version"`
 
 	// Status contains the current status of the controller. The status
-	// will either be "available", "deprecated", or "unavailable".
+	// will either be "available", "deprecated", "maintenance" or
+	// "unavailable". A controller in maintenance has the maintenance
+	// message as the status info.
 	Status jujuparams.EntityStatus `json:"status"`
 }
 
@@ -240,6 +242,29 @@ type SetControllerDeprecatedRequest struct {
 	Deprecated bool `json:"deprecated"`
 }
 
+// A SetControllerMaintenanceRequest is the request that is sent in a
+// SetControllerMaintenance method.
+type SetControllerMaintenanceRequest struct {
+	// Name is the name of the controller.
+	Name string `json:"name"`
+
+	// Maintenance specifies whether the controller should be put into
+	// or taken out of maintenance.
+	Maintenance bool `json:"maintenance"`
+
+	// Message is the message given to users whose requests are refused
+	// because of the maintenance.
+	Message string `json:"message,omitempty"`
+
+	// EndTime, if set, is the time the maintenance is expected to end.
+	// The controller leaves maintenance automatically at this time.
+	EndTime *time.Time `json:"end-time,omitempty"`
+
+	// AllowReadOnly specifies whether read-only requests to models on
+	// the controller are allowed during the maintenance.
+	AllowReadOnly bool `json:"allow-read-only,omitempty"`
+}
+
 // FullModelStatusRequest is the request that is sent in a FullModelStatus method.
 type FullModelStatusRequest struct {
 	ModelTag string
@@ -443,6 +468,9 @@ type ListRoleResponse struct {
 type CrossModelQueryRequest struct {
 	Type  string `json:"type"`
 	Query string `json:"query"`
+	// LabelSelector, if set, restricts the query to models with
+	// matching labels.
+	LabelSelector string `json:"label-selector,omitempty"`
 }
 
 // CrossModelJqQueryResponse holds results for a cross-model query that has been filtered utilising JQ.
@@ -575,3 +603,965 @@ type VersionResponse struct {
 	Version string `json:"version" yaml:"version"`
 	Commit  string `json:"commit" yaml:"commit"`
 }
+
+// ListOfferConsumersRequest holds a request to list the consumers of an
+// application offer.
+type ListOfferConsumersRequest struct {
+	// OfferURL is the URL of the application offer.
+	OfferURL string `json:"offer-url"`
+
+	// RevokedUser, if set, limits the consumers returned to those with
+	// relations that would break if the named user's access to the offer
+	// was revoked.
+	RevokedUser string `json:"revoked-user,omitempty"`
+}
+
+// ListOfferConsumersResponse holds the consumers of an application offer.
+type ListOfferConsumersResponse struct {
+	Consumers []OfferConsumer `json:"consumers" yaml:"consumers"`
+}
+
+// RevokeOfferAccessRequest holds a request to revoke a user's access to
+// an application offer.
+type RevokeOfferAccessRequest struct {
+	// OfferURL is the URL of the application offer.
+	OfferURL string `json:"offer-url"`
+
+	// UserTag is the tag of the user whose access is revoked.
+	UserTag string `json:"user-tag"`
+
+	// Access is the level of access to revoke.
+	Access string `json:"access"`
+}
+
+// RevokeOfferAccessResponse holds the result of revoking access to an
+// application offer.
+type RevokeOfferAccessResponse struct {
+	// BrokenConsumers holds the consumers with relations that relied on
+	// the revoked access and will no longer work.
+	BrokenConsumers []OfferConsumer `json:"broken-consumers,omitempty" yaml:"broken-consumers,omitempty"`
+}
+
+// OfferConsumer describes an identity that has consumed an application
+// offer into a model.
+type OfferConsumer struct {
+	// Identity is the name of the identity that consumed the offer.
+	Identity string `json:"identity" yaml:"identity"`
+
+	// ModelUUID is the UUID of the consuming model.
+	ModelUUID string `json:"model-uuid" yaml:"model-uuid"`
+
+	// ModelName is the name of the consuming model in the form
+	// <owner>/<name>, if the model is known to JIMM.
+	ModelName string `json:"model-name,omitempty" yaml:"model-name,omitempty"`
+
+	// LastConsumed is the time the offer was last consumed into the
+	// model, if JIMM has recorded it.
+	LastConsumed *time.Time `json:"last-consumed,omitempty" yaml:"last-consumed,omitempty"`
+
+	// Relations holds the relations between the consuming model and
+	// the offer.
+	Relations []OfferConsumerRelation `json:"relations,omitempty" yaml:"relations,omitempty"`
+}
+
+// OfferConsumerRelation describes a relation to an application offer.
+type OfferConsumerRelation struct {
+	RelationID int    `json:"relation-id" yaml:"relation-id"`
+	Endpoint   string `json:"endpoint" yaml:"endpoint"`
+	Status     string `json:"status" yaml:"status"`
+}
+
+// ModelQuota holds a limit on the number of models that may be created.
+type ModelQuota struct {
+	// Kind is the kind of entity the quota applies to, one of
+	// "identity", "group" or "cloud".
+	Kind string `json:"kind" yaml:"kind"`
+
+	// Subject is the name of the identity, group or cloud the quota
+	// applies to.
+	Subject string `json:"subject" yaml:"subject"`
+
+	// Region is the cloud region a cloud quota applies to. An empty
+	// region applies to the whole cloud.
+	Region string `json:"region,omitempty" yaml:"region,omitempty"`
+
+	// MaxModels is the maximum number of models allowed. For identity
+	// quotas this is the number of models the identity may own, for
+	// group quotas the number of models each member of the group may
+	// own and for cloud quotas the number of models hosted on the cloud
+	// or region.
+	MaxModels int `json:"max-models" yaml:"max-models"`
+}
+
+// SetModelQuotaRequest holds a request to create or update a model quota.
+type SetModelQuotaRequest struct {
+	ModelQuota
+}
+
+// RemoveModelQuotaRequest holds a request to remove a model quota.
+type RemoveModelQuotaRequest struct {
+	Kind    string `json:"kind"`
+	Subject string `json:"subject"`
+	Region  string `json:"region,omitempty"`
+}
+
+// ListModelQuotasResponse holds all the model quotas defined in JIMM.
+type ListModelQuotasResponse struct {
+	Quotas []ModelQuota `json:"quotas" yaml:"quotas"`
+}
+
+// ModelQuotaUsageRequest holds a request for an identity's usage against
+// its model quotas.
+type ModelQuotaUsageRequest struct {
+	// Identity is the name of the identity to report on. If it is
+	// empty the authenticated identity is used. Only JIMM
+	// administrators may report on other identities.
+	Identity string `json:"identity,omitempty"`
+}
+
+// ModelQuotaUsage holds a model quota and the current usage against it.
+type ModelQuotaUsage struct {
+	ModelQuota `yaml:",inline"`
+
+	// Usage is the number of models counted against the quota.
+	Usage int `json:"usage" yaml:"usage"`
+}
+
+// ModelQuotaUsageResponse holds an identity's usage against the model
+// quotas that apply to it.
+type ModelQuotaUsageResponse struct {
+	Identity string            `json:"identity" yaml:"identity"`
+	Quotas   []ModelQuotaUsage `json:"quotas" yaml:"quotas"`
+}
+
+// SetModelTTLRequest holds a request to set the time after which an
+// ephemeral model expires.
+type SetModelTTLRequest struct {
+	// ModelTag is the tag of the model.
+	ModelTag string `json:"model-tag"`
+
+	// TTL is the duration, from now, after which the model expires.
+	TTL string `json:"ttl"`
+}
+
+// SetModelTTLResponse holds the response to a SetModelTTLRequest.
+type SetModelTTLResponse struct {
+	// ExpiresAt is the time at which the model will now expire.
+	ExpiresAt time.Time `json:"expires-at" yaml:"expires-at"`
+}
+
+// SetModelLabelsRequest holds a request to set labels on a model.
+type SetModelLabelsRequest struct {
+	// ModelTag is the tag of the model.
+	ModelTag string `json:"model-tag"`
+
+	// Labels holds the labels to set. Existing labels with the same
+	// keys are replaced.
+	Labels map[string]string `json:"labels"`
+}
+
+// RemoveModelLabelsRequest holds a request to remove labels from a model.
+type RemoveModelLabelsRequest struct {
+	// ModelTag is the tag of the model.
+	ModelTag string `json:"model-tag"`
+
+	// Keys holds the keys of the labels to remove.
+	Keys []string `json:"keys"`
+}
+
+// GetModelLabelsRequest holds a request to get the labels of a model.
+type GetModelLabelsRequest struct {
+	// ModelTag is the tag of the model.
+	ModelTag string `json:"model-tag"`
+}
+
+// GetModelLabelsResponse holds the labels of a model.
+type GetModelLabelsResponse struct {
+	Labels map[string]string `json:"labels" yaml:"labels"`
+}
+
+// ListModelsByLabelRequest holds a request to list the models matching a
+// label selector. A selector is a comma separated list of requirements
+// of the form "key=value", "key!=value", "key" or "!key".
+type ListModelsByLabelRequest struct {
+	LabelSelector string `json:"label-selector"`
+}
+
+// ModelTemplate holds a named set of settings applied to models created
+// from the template.
+type ModelTemplate struct {
+	// Name is the name of the template.
+	Name string `json:"name" yaml:"name"`
+
+	// Config holds model config applied to models created from the
+	// template. Template config takes precedence over any config
+	// supplied when the model is created.
+	Config map[string]interface{} `json:"config,omitempty" yaml:"config,omitempty"`
+
+	// Cloud, if set, is the cloud models created from the template must
+	// be hosted on.
+	Cloud string `json:"cloud,omitempty" yaml:"cloud,omitempty"`
+
+	// Region, if set, is the cloud region models created from the
+	// template must be hosted in.
+	Region string `json:"region,omitempty" yaml:"region,omitempty"`
+
+	// CloudCredential, if set, is the name of the model owner's cloud
+	// credential used when no credential is specified.
+	CloudCredential string `json:"cloud-credential,omitempty" yaml:"cloud-credential,omitempty"`
+
+	// Labels holds labels set on models created from the template.
+	Labels map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+
+	// Grants maps the names of groups to the access level, one of
+	// "read", "write" or "admin", they are granted on models created
+	// from the template.
+	Grants map[string]string `json:"grants,omitempty" yaml:"grants,omitempty"`
+}
+
+// SetModelTemplateRequest holds a request to create or replace a model
+// template.
+type SetModelTemplateRequest struct {
+	ModelTemplate
+}
+
+// RemoveModelTemplateRequest holds a request to remove a model template.
+type RemoveModelTemplateRequest struct {
+	Name string `json:"name"`
+}
+
+// GetModelTemplateRequest holds a request to get a model template.
+type GetModelTemplateRequest struct {
+	Name string `json:"name"`
+}
+
+// ListModelTemplatesResponse holds all the model templates defined in
+// JIMM.
+type ListModelTemplatesResponse struct {
+	Templates []ModelTemplate `json:"templates" yaml:"templates"`
+}
+
+// ModelPolicy holds a set of admission rules checked when a model is
+// added. A policy applies to a model if the model's owner is a member of
+// any of the policy's groups and the model is hosted on any of the
+// policy's clouds. An empty list of groups, or clouds, matches every
+// model.
+type ModelPolicy struct {
+	// Name is the name of the policy.
+	Name string `json:"name" yaml:"name"`
+
+	// Groups holds the names of the groups whose members' models the
+	// policy applies to.
+	Groups []string `json:"groups,omitempty" yaml:"groups,omitempty"`
+
+	// Clouds holds the clouds, in the form "cloud" or "cloud/region",
+	// hosting the models the policy applies to.
+	Clouds []string `json:"clouds,omitempty" yaml:"clouds,omitempty"`
+
+	// AllowedClouds, if set, holds the clouds, in the form "cloud" or
+	// "cloud/region", models may be hosted on.
+	AllowedClouds []string `json:"allowed-clouds,omitempty" yaml:"allowed-clouds,omitempty"`
+
+	// RequiredConfig maps model config keys that must be set to the
+	// value they must have. An empty value allows any value.
+	RequiredConfig map[string]string `json:"required-config,omitempty" yaml:"required-config,omitempty"`
+
+	// ForbiddenConfig maps model config keys to a value they must not
+	// have. An empty value forbids the key from being set at all.
+	ForbiddenConfig map[string]string `json:"forbidden-config,omitempty" yaml:"forbidden-config,omitempty"`
+
+	// NamePattern, if set, is a regular expression model names must
+	// match in full.
+	NamePattern string `json:"name-pattern,omitempty" yaml:"name-pattern,omitempty"`
+
+	// AllowedCredentials, if set, holds shell patterns matched against
+	// the "cloud/owner/name" path of the credential used by the model.
+	AllowedCredentials []string `json:"allowed-credentials,omitempty" yaml:"allowed-credentials,omitempty"`
+}
+
+// SetModelPolicyRequest holds a request to create or replace a model
+// policy.
+type SetModelPolicyRequest struct {
+	ModelPolicy
+}
+
+// RemoveModelPolicyRequest holds a request to remove a model policy.
+type RemoveModelPolicyRequest struct {
+	Name string `json:"name"`
+}
+
+// ListModelPoliciesResponse holds all the model policies defined in JIMM.
+type ListModelPoliciesResponse struct {
+	Policies []ModelPolicy `json:"policies" yaml:"policies"`
+}
+
+// ModelPolicyViolation describes a model policy rule that prevented a
+// model from being added. Violations are returned in the "violations"
+// entry of the info of errors with the code CodePolicyViolation.
+type ModelPolicyViolation struct {
+	// Policy is the name of the violated policy.
+	Policy string `json:"policy" yaml:"policy"`
+
+	// Rule is the violated rule, one of "allowed-clouds",
+	// "required-config", "forbidden-config", "name-pattern" or
+	// "allowed-credentials".
+	Rule string `json:"rule" yaml:"rule"`
+
+	// Message describes the violation.
+	Message string `json:"message" yaml:"message"`
+}
+
+// InventoryApplication describes an application in a model inventory.
+type InventoryApplication struct {
+	Name            string `json:"name" yaml:"name"`
+	Charm           string `json:"charm" yaml:"charm"`
+	CharmURL        string `json:"charm-url" yaml:"charm-url"`
+	CharmChannel    string `json:"charm-channel,omitempty" yaml:"charm-channel,omitempty"`
+	CharmRevision   int    `json:"charm-revision" yaml:"charm-revision"`
+	Base            string `json:"base,omitempty" yaml:"base,omitempty"`
+	Status          string `json:"status,omitempty" yaml:"status,omitempty"`
+	Exposed         bool   `json:"exposed,omitempty" yaml:"exposed,omitempty"`
+	WorkloadVersion string `json:"workload-version,omitempty" yaml:"workload-version,omitempty"`
+}
+
+// InventoryUnit describes a unit in a model inventory.
+type InventoryUnit struct {
+	Name           string `json:"name" yaml:"name"`
+	Application    string `json:"application" yaml:"application"`
+	Machine        string `json:"machine,omitempty" yaml:"machine,omitempty"`
+	Principal      string `json:"principal,omitempty" yaml:"principal,omitempty"`
+	WorkloadStatus string `json:"workload-status,omitempty" yaml:"workload-status,omitempty"`
+	AgentStatus    string `json:"agent-status,omitempty" yaml:"agent-status,omitempty"`
+}
+
+// InventoryMachine describes a machine in a model inventory.
+type InventoryMachine struct {
+	ID         string `json:"id" yaml:"id"`
+	Base       string `json:"base,omitempty" yaml:"base,omitempty"`
+	InstanceID string `json:"instance-id,omitempty" yaml:"instance-id,omitempty"`
+	Status     string `json:"status,omitempty" yaml:"status,omitempty"`
+	Hardware   string `json:"hardware,omitempty" yaml:"hardware,omitempty"`
+}
+
+// InventoryRelation describes a relation in a model inventory.
+type InventoryRelation struct {
+	ID        int    `json:"id" yaml:"id"`
+	Key       string `json:"key" yaml:"key"`
+	Interface string `json:"interface,omitempty" yaml:"interface,omitempty"`
+	Scope     string `json:"scope,omitempty" yaml:"scope,omitempty"`
+	Status    string `json:"status,omitempty" yaml:"status,omitempty"`
+}
+
+// ModelInventory holds the most recently collected snapshot of the
+// contents of a model.
+type ModelInventory struct {
+	ModelUUID    string                 `json:"model-uuid" yaml:"model-uuid"`
+	ModelName    string                 `json:"model-name" yaml:"model-name"`
+	Owner        string                 `json:"owner" yaml:"owner"`
+	Controller   string                 `json:"controller" yaml:"controller"`
+	CollectedAt  time.Time              `json:"collected-at" yaml:"collected-at"`
+	Applications []InventoryApplication `json:"applications,omitempty" yaml:"applications,omitempty"`
+	Units        []InventoryUnit        `json:"units,omitempty" yaml:"units,omitempty"`
+	Machines     []InventoryMachine     `json:"machines,omitempty" yaml:"machines,omitempty"`
+	Relations    []InventoryRelation    `json:"relations,omitempty" yaml:"relations,omitempty"`
+}
+
+// GetModelInventoryRequest holds a request to get the inventory of a
+// model.
+type GetModelInventoryRequest struct {
+	// ModelTag is the tag of the model.
+	ModelTag string `json:"model-tag"`
+}
+
+// QueryInventoryRequest holds a request to find applications across all
+// the model inventories in JIMM. Empty fields match any value.
+type QueryInventoryRequest struct {
+	// Controller matches applications in models hosted on the named
+	// controller.
+	Controller string `json:"controller,omitempty"`
+
+	// Charm matches applications deployed from the named charm.
+	Charm string `json:"charm,omitempty"`
+
+	// CharmChannel matches applications whose charm was deployed from
+	// the given channel.
+	CharmChannel string `json:"charm-channel,omitempty"`
+
+	// Revision matches applications whose charm has the given revision.
+	Revision int `json:"revision,omitempty"`
+
+	// BelowRevision matches applications whose charm has a revision
+	// lower than the given revision.
+	BelowRevision int `json:"below-revision,omitempty"`
+
+	// Base matches applications with the given base, in the form
+	// "<name>@<channel>", for example "ubuntu@22.04".
+	Base string `json:"base,omitempty"`
+
+	// Offset is the number of matching applications to skip.
+	Offset int `json:"offset,omitempty"`
+
+	// Limit is the maximum number of applications to return.
+	Limit int `json:"limit,omitempty"`
+}
+
+// InventoryApplicationResult holds an application found by an inventory
+// query, along with the model it is deployed in.
+type InventoryApplicationResult struct {
+	InventoryApplication `yaml:",inline"`
+
+	ModelUUID   string    `json:"model-uuid" yaml:"model-uuid"`
+	ModelName   string    `json:"model-name" yaml:"model-name"`
+	Owner       string    `json:"owner" yaml:"owner"`
+	Controller  string    `json:"controller" yaml:"controller"`
+	CollectedAt time.Time `json:"collected-at" yaml:"collected-at"`
+}
+
+// QueryInventoryResponse holds the applications found by an inventory
+// query.
+type QueryInventoryResponse struct {
+	Applications []InventoryApplicationResult `json:"applications" yaml:"applications"`
+}
+
+// CharmDriftReportRequest holds a request for a report of the charm
+// revisions deployed across the estate. Empty fields match any value.
+type CharmDriftReportRequest struct {
+	// Charm restricts the report to the named charm.
+	Charm string `json:"charm,omitempty"`
+
+	// Controller restricts the report to models hosted on the named
+	// controller.
+	Controller string `json:"controller,omitempty"`
+}
+
+// DistributionEntry holds the number of applications with a value.
+type DistributionEntry struct {
+	Value string `json:"value" yaml:"value"`
+	Count int    `json:"count" yaml:"count"`
+}
+
+// DriftedApplication describes an application deployed from an older
+// revision of its charm than the newest revision, LatestRevision, in use
+// by applications with the same channel and base.
+type DriftedApplication struct {
+	ModelUUID      string `json:"model-uuid" yaml:"model-uuid"`
+	ModelName      string `json:"model-name" yaml:"model-name"`
+	Owner          string `json:"owner" yaml:"owner"`
+	Controller     string `json:"controller" yaml:"controller"`
+	Application    string `json:"application" yaml:"application"`
+	Revision       int    `json:"revision" yaml:"revision"`
+	LatestRevision int    `json:"latest-revision" yaml:"latest-revision"`
+	Channel        string `json:"channel,omitempty" yaml:"channel,omitempty"`
+	Base           string `json:"base,omitempty" yaml:"base,omitempty"`
+}
+
+// CharmDrift describes how the applications deployed from a charm
+// differ across the estate.
+type CharmDrift struct {
+	// Charm is the name of the charm.
+	Charm string `json:"charm" yaml:"charm"`
+
+	// Applications is the number of applications deployed from the
+	// charm.
+	Applications int `json:"applications" yaml:"applications"`
+
+	// Models is the number of models containing the charm.
+	Models int `json:"models" yaml:"models"`
+
+	// LatestRevision is the newest revision of the charm in use, or -1
+	// if no application uses a revisioned charm.
+	LatestRevision int `json:"latest-revision" yaml:"latest-revision"`
+
+	// Revisions, Channels, Bases and WorkloadVersions hold the number
+	// of applications using each revision, channel, base and workload
+	// version. Revisions are ordered newest first, the others by
+	// decreasing count.
+	Revisions        []DistributionEntry `json:"revisions" yaml:"revisions"`
+	Channels         []DistributionEntry `json:"channels,omitempty" yaml:"channels,omitempty"`
+	Bases            []DistributionEntry `json:"bases,omitempty" yaml:"bases,omitempty"`
+	WorkloadVersions []DistributionEntry `json:"workload-versions,omitempty" yaml:"workload-versions,omitempty"`
+
+	// Behind holds the applications using an older revision than the
+	// newest revision in use by applications with the same channel and
+	// base.
+	Behind []DriftedApplication `json:"behind,omitempty" yaml:"behind,omitempty"`
+}
+
+// CharmDriftReport holds a report of the charm revisions deployed across
+// the estate, ordered by charm name.
+type CharmDriftReport struct {
+	Charms []CharmDrift `json:"charms" yaml:"charms"`
+}
+
+// CreateUpgradeCampaignRequest holds a request to create a model upgrade
+// campaign. A campaign selects every model matching all of the given
+// selectors.
+type CreateUpgradeCampaignRequest struct {
+	// Name is the name of the campaign.
+	Name string `json:"name"`
+
+	// TargetVersion is the agent version to upgrade models to. If it is
+	// empty each model's controller chooses the version.
+	TargetVersion string `json:"target-version,omitempty"`
+
+	// AgentStream is the agent stream to upgrade from.
+	AgentStream string `json:"agent-stream,omitempty"`
+
+	// Controller restricts the campaign to models hosted on the named
+	// controller.
+	Controller string `json:"controller,omitempty"`
+
+	// LabelSelector restricts the campaign to models whose labels match
+	// the selector.
+	LabelSelector string `json:"label-selector,omitempty"`
+
+	// AgentVersion restricts the campaign to models running the given
+	// agent version.
+	AgentVersion string `json:"agent-version,omitempty"`
+
+	// Concurrency is the maximum number of models upgraded at the same
+	// time.
+	Concurrency int `json:"concurrency,omitempty"`
+
+	// FailureThreshold is the number of failed upgrades after which the
+	// campaign is paused. A threshold of zero never pauses the
+	// campaign.
+	FailureThreshold int `json:"failure-threshold,omitempty"`
+}
+
+// UpgradeCampaignRequest holds a request that refers to an upgrade
+// campaign by name.
+type UpgradeCampaignRequest struct {
+	Name string `json:"name"`
+}
+
+// UpgradeCampaignModel holds the state of a model in an upgrade
+// campaign.
+type UpgradeCampaignModel struct {
+	ModelUUID     string    `json:"model-uuid" yaml:"model-uuid"`
+	ModelName     string    `json:"model-name" yaml:"model-name"`
+	Owner         string    `json:"owner" yaml:"owner"`
+	Controller    string    `json:"controller" yaml:"controller"`
+	Status        string    `json:"status" yaml:"status"`
+	FromVersion   string    `json:"from-version,omitempty" yaml:"from-version,omitempty"`
+	ChosenVersion string    `json:"chosen-version,omitempty" yaml:"chosen-version,omitempty"`
+	Error         string    `json:"error,omitempty" yaml:"error,omitempty"`
+	UpdatedAt     time.Time `json:"updated-at" yaml:"updated-at"`
+}
+
+// UpgradeCampaign describes a model upgrade campaign.
+type UpgradeCampaign struct {
+	Name             string    `json:"name" yaml:"name"`
+	TargetVersion    string    `json:"target-version,omitempty" yaml:"target-version,omitempty"`
+	AgentStream      string    `json:"agent-stream,omitempty" yaml:"agent-stream,omitempty"`
+	Controller       string    `json:"controller,omitempty" yaml:"controller,omitempty"`
+	LabelSelector    string    `json:"label-selector,omitempty" yaml:"label-selector,omitempty"`
+	AgentVersion     string    `json:"agent-version,omitempty" yaml:"agent-version,omitempty"`
+	Concurrency      int       `json:"concurrency" yaml:"concurrency"`
+	FailureThreshold int       `json:"failure-threshold" yaml:"failure-threshold"`
+	Status           string    `json:"status" yaml:"status"`
+	Failures         int       `json:"failures" yaml:"failures"`
+	Message          string    `json:"message,omitempty" yaml:"message,omitempty"`
+	CreatedAt        time.Time `json:"created-at" yaml:"created-at"`
+	UpdatedAt        time.Time `json:"updated-at" yaml:"updated-at"`
+
+	// Progress holds the number of models in each state.
+	Progress map[string]int `json:"progress" yaml:"progress"`
+
+	// Models holds the state of each model in the campaign. It is only
+	// populated when a single campaign is requested.
+	Models []UpgradeCampaignModel `json:"models,omitempty" yaml:"models,omitempty"`
+}
+
+// ListUpgradeCampaignsResponse holds the response to a
+// ListUpgradeCampaigns request.
+type ListUpgradeCampaignsResponse struct {
+	Campaigns []UpgradeCampaign `json:"campaigns" yaml:"campaigns"`
+}
+
+// A TransferModelOwnershipRequest holds a request to transfer the
+// ownership of a model to another user.
+type TransferModelOwnershipRequest struct {
+	// ModelTag is the tag of the model to transfer.
+	ModelTag string `json:"model-tag"`
+
+	// NewOwner is the name of the user that will own the model.
+	NewOwner string `json:"new-owner"`
+
+	// CloudCredentialTag is the tag of a cloud credential the model
+	// should be switched to use. Can be empty to keep the model's
+	// current credential.
+	CloudCredentialTag string `json:"cloud-credential-tag,omitempty"`
+}
+
+// An AddWebhookSubscriptionRequest holds a request to subscribe a webhook
+// to model lifecycle events.
+type AddWebhookSubscriptionRequest struct {
+	// Name is the name of the subscription.
+	Name string `json:"name"`
+
+	// URL is the URL events are posted to.
+	URL string `json:"url"`
+
+	// Secret is the key used to sign event payloads. If it is empty a
+	// secret is generated and returned in the response.
+	Secret string `json:"secret,omitempty"`
+
+	// EventTypes holds the types of event delivered to the webhook. If
+	// it is empty all events are delivered.
+	EventTypes []string `json:"event-types,omitempty"`
+
+	// LabelSelector, Controller and Owner restrict the events
+	// delivered to those for matching models.
+	LabelSelector string `json:"label-selector,omitempty"`
+	Controller    string `json:"controller,omitempty"`
+	Owner         string `json:"owner,omitempty"`
+}
+
+// WebhookSubscription describes a webhook subscribed to model lifecycle
+// events.
+type WebhookSubscription struct {
+	Name          string    `json:"name" yaml:"name"`
+	URL           string    `json:"url" yaml:"url"`
+	Secret        string    `json:"secret,omitempty" yaml:"secret,omitempty"`
+	EventTypes    []string  `json:"event-types,omitempty" yaml:"event-types,omitempty"`
+	LabelSelector string    `json:"label-selector,omitempty" yaml:"label-selector,omitempty"`
+	Controller    string    `json:"controller,omitempty" yaml:"controller,omitempty"`
+	Owner         string    `json:"owner,omitempty" yaml:"owner,omitempty"`
+	CreatedAt     time.Time `json:"created-at" yaml:"created-at"`
+}
+
+// ListWebhookSubscriptionsResponse holds the response to a
+// ListWebhookSubscriptions request.
+type ListWebhookSubscriptionsResponse struct {
+	Subscriptions []WebhookSubscription `json:"subscriptions" yaml:"subscriptions"`
+}
+
+// A RemoveWebhookSubscriptionRequest holds a request to remove a webhook
+// subscription.
+type RemoveWebhookSubscriptionRequest struct {
+	Name string `json:"name"`
+}
+
+// WebhookEventModel describes the model a webhook event is about.
+type WebhookEventModel struct {
+	UUID       string            `json:"uuid" yaml:"uuid"`
+	Name       string            `json:"name" yaml:"name"`
+	Owner      string            `json:"owner" yaml:"owner"`
+	Controller string            `json:"controller" yaml:"controller"`
+	Labels     map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+}
+
+// A WebhookEvent is the payload posted to a webhook.
+type WebhookEvent struct {
+	// ID uniquely identifies the event. An event may be delivered more
+	// than once, receivers can use the ID to discard duplicates.
+	ID string `json:"id" yaml:"id"`
+
+	// Type is the type of the event.
+	Type string `json:"type" yaml:"type"`
+
+	// Time is the time the event occurred.
+	Time time.Time `json:"time" yaml:"time"`
+
+	// Model is the model the event is about.
+	Model WebhookEventModel `json:"model" yaml:"model"`
+
+	// Message holds any further details of the event.
+	Message string `json:"message,omitempty" yaml:"message,omitempty"`
+}
+
+// A ListWebhookDeadLettersRequest holds a request to list the events that
+// could not be delivered.
+type ListWebhookDeadLettersRequest struct {
+	// Subscription, if set, restricts the dead letters to those of the
+	// named subscription.
+	Subscription string `json:"subscription,omitempty"`
+}
+
+// WebhookDeadLetter describes an event that could not be delivered to a
+// webhook.
+type WebhookDeadLetter struct {
+	ID           uint         `json:"id" yaml:"id"`
+	Subscription string       `json:"subscription" yaml:"subscription"`
+	Attempts     int          `json:"attempts" yaml:"attempts"`
+	LastError    string       `json:"last-error,omitempty" yaml:"last-error,omitempty"`
+	CreatedAt    time.Time    `json:"created-at" yaml:"created-at"`
+	Event        WebhookEvent `json:"event" yaml:"event"`
+}
+
+// ListWebhookDeadLettersResponse holds the response to a
+// ListWebhookDeadLetters request.
+type ListWebhookDeadLettersResponse struct {
+	DeadLetters []WebhookDeadLetter `json:"dead-letters" yaml:"dead-letters"`
+}
+
+// A RetryWebhookDeadLetterRequest holds a request to retry the delivery
+// of an event that could not be delivered.
+type RetryWebhookDeadLetterRequest struct {
+	ID uint `json:"id"`
+}
+
+// ScheduledJob describes a background job that JIMM runs on a schedule.
+type ScheduledJob struct {
+	Name string `json:"name" yaml:"name"`
+
+	// Schedule is the cron expression describing when the job runs.
+	Schedule string `json:"schedule" yaml:"schedule"`
+
+	// Jitter is the maximum random delay added to each scheduled run.
+	Jitter string `json:"jitter,omitempty" yaml:"jitter,omitempty"`
+
+	// NextRunAt is the time the job is next scheduled to run.
+	NextRunAt *time.Time `json:"next-run-at,omitempty" yaml:"next-run-at,omitempty"`
+
+	// TriggeredAt is set when the job has been triggered manually but
+	// has not yet started running.
+	TriggeredAt *time.Time `json:"triggered-at,omitempty" yaml:"triggered-at,omitempty"`
+
+	// LastRun is the most recent run of the job.
+	LastRun *JobRun `json:"last-run,omitempty" yaml:"last-run,omitempty"`
+}
+
+// ListScheduledJobsResponse holds the response to a ListScheduledJobs
+// request.
+type ListScheduledJobsResponse struct {
+	Jobs []ScheduledJob `json:"jobs" yaml:"jobs"`
+}
+
+// JobRun describes a single run of a scheduled job.
+type JobRun struct {
+	ID  uint   `json:"id" yaml:"id"`
+	Job string `json:"job" yaml:"job"`
+
+	// Trigger is "schedule" if the run was started by the job's
+	// schedule, or "manual" if it was triggered by a user.
+	Trigger     string `json:"trigger" yaml:"trigger"`
+	TriggeredBy string `json:"triggered-by,omitempty" yaml:"triggered-by,omitempty"`
+
+	// Replica identifies the JIMM replica that performed the run.
+	Replica string `json:"replica" yaml:"replica"`
+
+	// Status is one of "running", "succeeded", "failed" or
+	// "interrupted".
+	Status     string     `json:"status" yaml:"status"`
+	StartedAt  time.Time  `json:"started-at" yaml:"started-at"`
+	FinishedAt *time.Time `json:"finished-at,omitempty" yaml:"finished-at,omitempty"`
+	Duration   string     `json:"duration,omitempty" yaml:"duration,omitempty"`
+	Error      string     `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// A ListJobRunsRequest holds a request to list the history of a scheduled
+// job.
+type ListJobRunsRequest struct {
+	Job string `json:"job"`
+
+	// Limit is the maximum number of runs to return, most recent first.
+	// If this is zero a default limit is used.
+	Limit int `json:"limit,omitempty"`
+}
+
+// ListJobRunsResponse holds the response to a ListJobRuns request.
+type ListJobRunsResponse struct {
+	Runs []JobRun `json:"runs" yaml:"runs"`
+}
+
+// A TriggerScheduledJobRequest holds a request to run a scheduled job
+// immediately.
+type TriggerScheduledJobRequest struct {
+	Job string `json:"job"`
+}
+
+// The states of an alert.
+const (
+	// AlertStatePending alerts have a condition that holds, but has not
+	// yet held for long enough to fire.
+	AlertStatePending = "pending"
+
+	// AlertStateFiring alerts have had a condition hold for the
+	// duration of their rule, receivers are notified about them.
+	AlertStateFiring = "firing"
+
+	// AlertStateResolved is the state of an alert in the notification
+	// sent once its condition no longer holds.
+	AlertStateResolved = "resolved"
+)
+
+// An AddAlertReceiverRequest holds a request to add a destination for
+// alert notifications.
+type AddAlertReceiverRequest struct {
+	// Name is the name of the receiver.
+	Name string `json:"name"`
+
+	// Type is the type of the receiver, one of "webhook", "email" or
+	// "alertmanager".
+	Type string `json:"type"`
+
+	// URL is the URL notifications are posted to by webhook receivers,
+	// or the base URL of the Alertmanager for alertmanager receivers.
+	URL string `json:"url,omitempty"`
+
+	// Secret is the key used to sign notifications posted to webhook
+	// receivers. If it is empty a secret is generated and returned in
+	// the response.
+	Secret string `json:"secret,omitempty"`
+
+	// Emails holds the addresses notifications are sent to by email
+	// receivers.
+	Emails []string `json:"emails,omitempty"`
+}
+
+// AlertReceiver describes a destination for alert notifications.
+type AlertReceiver struct {
+	Name      string    `json:"name" yaml:"name"`
+	Type      string    `json:"type" yaml:"type"`
+	URL       string    `json:"url,omitempty" yaml:"url,omitempty"`
+	Secret    string    `json:"secret,omitempty" yaml:"secret,omitempty"`
+	Emails    []string  `json:"emails,omitempty" yaml:"emails,omitempty"`
+	CreatedAt time.Time `json:"created-at" yaml:"created-at"`
+}
+
+// ListAlertReceiversResponse holds the response to a ListAlertReceivers
+// request.
+type ListAlertReceiversResponse struct {
+	Receivers []AlertReceiver `json:"receivers" yaml:"receivers"`
+}
+
+// A RemoveAlertReceiverRequest holds a request to remove an alert
+// receiver.
+type RemoveAlertReceiverRequest struct {
+	Name string `json:"name"`
+}
+
+// An AddAlertRuleRequest holds a request to add an alert rule.
+type AddAlertRuleRequest struct {
+	// Name is the name of the rule.
+	Name string `json:"name"`
+
+	// Kind is the kind of condition the rule is evaluated against, one
+	// of "model-error", "controller-unavailable", "credential-invalid"
+	// or "audit-write-failure".
+	Kind string `json:"kind"`
+
+	// For is the length of time, for example "15m", the condition must
+	// hold before the alert fires. If it is empty the alert fires as
+	// soon as the condition is seen.
+	For string `json:"for,omitempty"`
+
+	// RepeatInterval is the length of time to wait before notifying
+	// receivers again about an alert that is still firing. If it is
+	// empty a default of 4 hours is used.
+	RepeatInterval string `json:"repeat-interval,omitempty"`
+
+	// Severity is included with every alert raised by the rule. If it
+	// is empty "warning" is used.
+	Severity string `json:"severity,omitempty"`
+
+	// Receivers holds the names of the receivers notified about the
+	// rule's alerts.
+	Receivers []string `json:"receivers"`
+}
+
+// AlertRule describes a condition that raises alerts.
+type AlertRule struct {
+	Name           string    `json:"name" yaml:"name"`
+	Kind           string    `json:"kind" yaml:"kind"`
+	For            string    `json:"for" yaml:"for"`
+	RepeatInterval string    `json:"repeat-interval" yaml:"repeat-interval"`
+	Severity       string    `json:"severity" yaml:"severity"`
+	Receivers      []string  `json:"receivers" yaml:"receivers"`
+	CreatedAt      time.Time `json:"created-at" yaml:"created-at"`
+}
+
+// ListAlertRulesResponse holds the response to a ListAlertRules request.
+type ListAlertRulesResponse struct {
+	Rules []AlertRule `json:"rules" yaml:"rules"`
+}
+
+// A RemoveAlertRuleRequest holds a request to remove an alert rule, along
+// with any alerts it has raised.
+type RemoveAlertRuleRequest struct {
+	Name string `json:"name"`
+}
+
+// Alert describes an alert raised by an alert rule.
+type Alert struct {
+	Rule     string `json:"rule" yaml:"rule"`
+	Kind     string `json:"kind" yaml:"kind"`
+	Severity string `json:"severity" yaml:"severity"`
+
+	// Subject identifies the entity the alert is about, for example a
+	// model UUID or controller name.
+	Subject string            `json:"subject" yaml:"subject"`
+	Summary string            `json:"summary" yaml:"summary"`
+	Labels  map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+
+	// State is one of "pending", "firing" or "resolved".
+	State      string     `json:"state" yaml:"state"`
+	ActiveAt   time.Time  `json:"active-at" yaml:"active-at"`
+	FiringAt   *time.Time `json:"firing-at,omitempty" yaml:"firing-at,omitempty"`
+	ResolvedAt *time.Time `json:"resolved-at,omitempty" yaml:"resolved-at,omitempty"`
+	NotifiedAt *time.Time `json:"notified-at,omitempty" yaml:"notified-at,omitempty"`
+
+	// Silenced is set if notifications about the alert are currently
+	// silenced.
+	Silenced bool `json:"silenced,omitempty" yaml:"silenced,omitempty"`
+}
+
+// ListAlertsResponse holds the response to a ListAlerts request.
+type ListAlertsResponse struct {
+	Alerts []Alert `json:"alerts" yaml:"alerts"`
+}
+
+// An AlertNotification is the payload posted to webhook alert receivers.
+type AlertNotification struct {
+	// Receiver is the name of the receiver being notified.
+	Receiver string `json:"receiver"`
+
+	// Status is "firing" if any of the alerts are firing, otherwise it
+	// is "resolved".
+	Status string `json:"status"`
+
+	// Alerts holds the alerts the notification is about.
+	Alerts []Alert `json:"alerts"`
+}
+
+// An AddAlertSilenceRequest holds a request to silence notifications for
+// matching alerts.
+type AddAlertSilenceRequest struct {
+	// Rule and Subject restrict the alerts silenced to those raised by
+	// the named rule and about the given subject. Empty values match
+	// all alerts, but at least one must be given.
+	Rule    string `json:"rule,omitempty"`
+	Subject string `json:"subject,omitempty"`
+
+	// Duration is the length of time, for example "2h", the silence
+	// applies from now.
+	Duration string `json:"duration"`
+
+	// Comment describes the reason for the silence.
+	Comment string `json:"comment,omitempty"`
+}
+
+// AlertSilence describes a silence of alert notifications.
+type AlertSilence struct {
+	ID        uint      `json:"id" yaml:"id"`
+	Rule      string    `json:"rule,omitempty" yaml:"rule,omitempty"`
+	Subject   string    `json:"subject,omitempty" yaml:"subject,omitempty"`
+	StartsAt  time.Time `json:"starts-at" yaml:"starts-at"`
+	EndsAt    time.Time `json:"ends-at" yaml:"ends-at"`
+	CreatedBy string    `json:"created-by" yaml:"created-by"`
+	Comment   string    `json:"comment,omitempty" yaml:"comment,omitempty"`
+}
+
+// ListAlertSilencesResponse holds the response to a ListAlertSilences
+// request.
+type ListAlertSilencesResponse struct {
+	Silences []AlertSilence `json:"silences" yaml:"silences"`
+}
+
+// A RemoveAlertSilenceRequest holds a request to remove a silence, so that
+// notifications for the alerts it matched resume.
+type RemoveAlertSilenceRequest struct {
+	ID uint `json:"id"`
+}